@@ -1,12 +1,20 @@
 import dotenv from 'dotenv';
 import envvar from 'env-var';
+import * as os from 'node:os';
 dotenv.config();
 
 const applicationConfig = {
   browserPath: envvar.get('PFLOW_BROWSER_PATH').required().asString(),
   mqQueueUrl: envvar.get('PFLOW_MQ_URL').required().asUrlString(),
   mqRequestUrl: envvar.get('PFLOW_MQ_REQUEST_URL').required().asUrlString(),
-  mqHertbeatUrl: envvar.get('PFLOW_MQ_HEARTBEAT_URL').default('heartbeat').asUrlString()
+  mqHertbeatUrl: envvar.get('PFLOW_MQ_HEARTBEAT_URL').default('agent.heartbeat').asString(),
+  mqDeadLetterExchange: envvar.get('PFLOW_MQ_DEAD_LETTER_EXCHANGE').default('parrotflow.dlx').asString(),
+  // Enrollment: the token is only needed until the credential file exists
+  apiUrl: envvar.get('PFLOW_API_URL').required().asUrlString(),
+  enrollmentToken: envvar.get('PFLOW_ENROLLMENT_TOKEN').asString(),
+  credentialFile: envvar.get('PFLOW_CREDENTIAL_FILE').default('data/credential.json').asString(),
+  agentName: envvar.get('PFLOW_AGENT_NAME').default(os.hostname()).asString(),
+  browserVersion: envvar.get('PFLOW_BROWSER_VERSION').default('latest').asString()
 };
 
 export { applicationConfig };
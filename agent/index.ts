@@ -6,7 +6,12 @@
  */
 
 import { applicationConfig } from 'configuration.js';
-import { RabbitMQAdapter, HeartbeatMonitor } from './src/adapters/index.js';
+import {
+  RabbitMQAdapter,
+  HeartbeatMonitor,
+  HmacMessageSigner,
+  EnrollmentClient
+} from './src/adapters/index.js';
 import { AgentService, AgentLifecycle } from './src/application/index.js';
 
 const AGENT_VERSION = '1.0.0';
@@ -16,9 +21,23 @@ const AGENT_VERSION = '1.0.0';
  */
 (async () => {
   try {
+    // Enroll, or reuse the credential of an earlier enrollment
+    const enrollment = new EnrollmentClient({
+      apiUrl: applicationConfig.apiUrl,
+      credentialFile: applicationConfig.credentialFile,
+      enrollmentToken: applicationConfig.enrollmentToken,
+      name: applicationConfig.agentName,
+      browserType: 'chromium',
+      browserVersion: applicationConfig.browserVersion,
+      queueName: applicationConfig.mqRequestUrl
+    });
+    const { agentId, credential } = await enrollment.enroll();
+    const messageSigner = new HmacMessageSigner({ agentId, credential });
+
     // Initialize RabbitMQ adapter
     const rabbitMQ = new RabbitMQAdapter({
       url: applicationConfig.mqQueueUrl,
+      deadLetterExchange: applicationConfig.mqDeadLetterExchange,
       onConnectionError: (error) => {
         console.error('[Bootstrap] RabbitMQ connection error:', error);
         process.exit(1);
@@ -39,17 +58,20 @@ const AGENT_VERSION = '1.0.0';
     // Initialize heartbeat monitor
     const heartbeatMonitor = new HeartbeatMonitor({
       queueName: applicationConfig.mqHertbeatUrl,
-      publisher: rabbitMQ
+      publisher: rabbitMQ,
+      signer: messageSigner
     });
 
     // Initialize agent service
     const agentService = new AgentService({
+      agentId,
       version: AGENT_VERSION,
       browserType: 'chromium',
       browserPath: applicationConfig.browserPath,
       messageConsumer: rabbitMQ,
       messagePublisher: rabbitMQ,
       healthMonitor: heartbeatMonitor,
+      messageSigner,
       requestQueueName: applicationConfig.mqRequestUrl
     });
 
@@ -0,0 +1,140 @@
+/**
+ * Enrollment Client Adapter
+ *
+ * Registers the agent with the backend in exchange for a one-time enrollment
+ * token minted by an admin, and keeps the returned credential in a file so a
+ * restarted agent signs in again without a new token.
+ */
+
+import * as fs from 'node:fs/promises';
+import * as os from 'node:os';
+import * as path from 'node:path';
+
+export interface EnrollmentConfig {
+  apiUrl: string;
+  credentialFile: string;
+  enrollmentToken?: string;
+  name: string;
+  browserType: 'chromium' | 'firefox' | 'webkit';
+  browserVersion: string;
+  queueName: string;
+  maxConcurrentRuns?: number;
+}
+
+/**
+ * Identity of an enrolled agent; the credential signs its messages and is
+ * only handed out once by the backend
+ */
+export interface AgentCredential {
+  agentId: string;
+  credential: string;
+}
+
+export class EnrollmentClient {
+  private readonly config: EnrollmentConfig;
+
+  constructor(config: EnrollmentConfig) {
+    this.config = config;
+  }
+
+  /**
+   * Load the stored credential, or enroll with the token when there is none
+   */
+  async enroll(): Promise<AgentCredential> {
+    const stored = await this.load();
+    if (stored) {
+      console.log(`[Enrollment] Using the credential of agent ${stored.agentId}`);
+      return stored;
+    }
+    if (!this.config.enrollmentToken) {
+      throw new Error(
+        `No agent credential in ${this.config.credentialFile}; set PFLOW_ENROLLMENT_TOKEN to enroll`
+      );
+    }
+
+    const response = await fetch(new URL('/api/agents/', this.config.apiUrl), {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify(this.registration()),
+    });
+    if (!response.ok) {
+      throw new Error(`Enrollment failed with ${response.status}: ${await response.text()}`);
+    }
+
+    const registered = (await response.json()) as { id: string; credential: string };
+    const credential = { agentId: registered.id, credential: registered.credential };
+    await this.save(credential);
+    console.log(`[Enrollment] Enrolled as agent ${credential.agentId}`);
+    return credential;
+  }
+
+  private registration() {
+    return {
+      name: this.config.name,
+      enrollment_token: this.config.enrollmentToken,
+      capabilities: {
+        browsers: [
+          { type: this.config.browserType, version: this.config.browserVersion, headless: true },
+        ],
+        os: {
+          platform: platform(),
+          architecture: architecture(),
+          version: os.release(),
+        },
+        proxy: { supports_proxy: false },
+        resource: {
+          max_concurrent_runs: this.config.maxConcurrentRuns ?? 1,
+          max_memory_mb: Math.floor(os.totalmem() / (1024 * 1024)),
+          max_cpu_cores: os.cpus().length,
+        },
+      },
+      connection_info: {
+        hostname: os.hostname(),
+        queue_name: this.config.queueName,
+      },
+    };
+  }
+
+  private async load(): Promise<AgentCredential | null> {
+    try {
+      const data = JSON.parse(await fs.readFile(this.config.credentialFile, 'utf8'));
+      return { agentId: data.agent_id, credential: data.credential };
+    } catch (error) {
+      if ((error as NodeJS.ErrnoException).code === 'ENOENT') {
+        return null;
+      }
+      throw error;
+    }
+  }
+
+  private async save(credential: AgentCredential): Promise<void> {
+    await fs.mkdir(path.dirname(this.config.credentialFile), { recursive: true });
+    const data = JSON.stringify({ agent_id: credential.agentId, credential: credential.credential });
+    // Only the agent may read its credential
+    await fs.writeFile(this.config.credentialFile, data, { mode: 0o600 });
+  }
+}
+
+function platform(): 'linux' | 'darwin' | 'windows' {
+  switch (process.platform) {
+    case 'darwin':
+      return 'darwin';
+    case 'win32':
+      return 'windows';
+    default:
+      return 'linux';
+  }
+}
+
+function architecture(): 'amd64' | 'arm64' | '386' | 'arm' {
+  switch (process.arch) {
+    case 'arm64':
+      return 'arm64';
+    case 'ia32':
+      return '386';
+    case 'arm':
+      return 'arm';
+    default:
+      return 'amd64';
+  }
+}
@@ -9,3 +9,9 @@ export type { RabbitMQConfig } from './messaging/RabbitMQAdapter.js';
 
 export { HeartbeatMonitor } from './monitoring/HeartbeatMonitor.js';
 export type { HeartbeatConfig } from './monitoring/HeartbeatMonitor.js';
+
+export { HmacMessageSigner } from './security/HmacMessageSigner.js';
+export type { HmacSignerConfig } from './security/HmacMessageSigner.js';
+
+export { EnrollmentClient } from './enrollment/EnrollmentClient.js';
+export type { EnrollmentConfig, AgentCredential } from './enrollment/EnrollmentClient.js';
@@ -6,7 +6,10 @@
  */
 
 import amqp from "amqplib";
-import type { IMessageConsumer } from "../../ports/messaging/IMessageConsumer.js";
+import type {
+  IMessageConsumer,
+  MessageMetadata,
+} from "../../ports/messaging/IMessageConsumer.js";
 import type {
   IMessagePublisher,
   PublishOptions,
@@ -14,6 +17,11 @@ import type {
 
 export interface RabbitMQConfig {
   url: string;
+  /**
+   * Exchange that messages rejected from asserted queues are routed to.
+   * Must match the exchange the backend collects dead letters from.
+   */
+  deadLetterExchange?: string;
   onConnectionError?: (error: Error) => void;
   onConnectionClose?: () => void;
 }
@@ -67,7 +75,13 @@ export class RabbitMQAdapter implements IMessageConsumer, IMessagePublisher {
     }
 
     try {
-      await this.channel.assertQueue(queueName, { durable: true });
+      const options: amqp.Options.AssertQueue = { durable: true };
+      if (this.config.deadLetterExchange) {
+        options.arguments = {
+          "x-dead-letter-exchange": this.config.deadLetterExchange,
+        };
+      }
+      await this.channel.assertQueue(queueName, options);
     } catch (error) {
       console.error(`[RabbitMQ] Failed to assert queue ${queueName}:`, error);
       throw error;
@@ -106,7 +120,7 @@ export class RabbitMQAdapter implements IMessageConsumer, IMessagePublisher {
    */
   async consume<TMessage = any>(
     queueName: string,
-    handler: (message: TMessage) => Promise<void>
+    handler: (message: TMessage, metadata: MessageMetadata) => Promise<void>
   ): Promise<void> {
     if (!this.channel) {
       throw new Error("Channel not initialized. Call connect() first.");
@@ -122,19 +136,19 @@ export class RabbitMQAdapter implements IMessageConsumer, IMessagePublisher {
 
           try {
             const message: TMessage = JSON.parse(msg.content.toString());
-            await handler(message);
+            await handler(message, {
+              messageId: msg.properties.messageId,
+              headers: msg.properties.headers ?? {},
+            });
 
             // Acknowledge message after successful processing
             this.channel?.ack(msg);
           } catch (error) {
             console.error("[RabbitMQ] Error processing message:", error);
 
-            // Acknowledge anyway to prevent requeue loop
-            // In production, you might want to:
-            // - Requeue with a retry limit
-            // - Send to a dead letter queue
-            // - Use nack with requeue: false
-            this.channel?.ack(msg);
+            // Reject without requeueing; the broker routes the message to the
+            // dead-letter exchange, where it can be inspected and replayed
+            this.channel?.nack(msg, false, false);
           }
         },
         { noAck: false } // Manual acknowledgment
@@ -11,10 +11,15 @@ import type {
   AgentStatus,
 } from "../../ports/monitoring/IHealthMonitor.js";
 import type { IMessagePublisher } from "../../ports/messaging/IMessagePublisher.js";
+import {
+  HEARTBEAT_PURPOSE,
+  type IMessageSigner,
+} from "../../ports/security/IMessageSigner.js";
 
 export interface HeartbeatConfig {
   queueName: string;
   publisher: IMessagePublisher;
+  signer: IMessageSigner;
 }
 
 export class HeartbeatMonitor implements IHealthMonitor {
@@ -49,7 +54,10 @@ export class HeartbeatMonitor implements IHealthMonitor {
       await this.config.publisher.publish(
         this.config.queueName,
         heartbeat,
-        { persistent: false } // Heartbeats don't need to be persisted
+        {
+          persistent: false, // Heartbeats don't need to be persisted
+          headers: this.config.signer.sign(HEARTBEAT_PURPOSE, JSON.stringify(heartbeat)),
+        }
       );
     } catch (error) {
       console.error("[Heartbeat] Failed to report status:", error);
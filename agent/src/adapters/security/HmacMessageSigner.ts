@@ -0,0 +1,52 @@
+/**
+ * HMAC Message Signer Adapter
+ *
+ * Signs messages the way the backend verifies them:
+ *
+ *   sha256=hex(HMAC-SHA256(credential, "<timestamp>.<nonce>.<purpose>.<body>"))
+ *
+ * where timestamp is in Unix seconds and may be at most 5 minutes off the
+ * backend clock, and nonce is unique per message: the backend rejects a nonce
+ * it has already seen.
+ */
+
+import { createHmac, randomUUID } from 'node:crypto';
+import {
+  AGENT_HEADER,
+  NONCE_HEADER,
+  SIGNATURE_HEADER,
+  TIMESTAMP_HEADER,
+  type IMessageSigner,
+} from '../../ports/security/IMessageSigner.js';
+
+export interface HmacSignerConfig {
+  agentId: string;
+  credential: string;
+  now?: () => Date;
+  nonce?: () => string;
+}
+
+export class HmacMessageSigner implements IMessageSigner {
+  private readonly config: HmacSignerConfig;
+
+  constructor(config: HmacSignerConfig) {
+    this.config = config;
+  }
+
+  sign(purpose: string, body: string): Record<string, string> {
+    const now = this.config.now ? this.config.now() : new Date();
+    const timestamp = Math.floor(now.getTime() / 1000).toString();
+    const nonce = this.config.nonce ? this.config.nonce() : randomUUID();
+    const signature = createHmac('sha256', this.config.credential)
+      .update(`${timestamp}.${nonce}.${purpose}.`)
+      .update(body)
+      .digest('hex');
+
+    return {
+      [AGENT_HEADER]: this.config.agentId,
+      [TIMESTAMP_HEADER]: timestamp,
+      [NONCE_HEADER]: nonce,
+      [SIGNATURE_HEADER]: `sha256=${signature}`,
+    };
+  }
+}
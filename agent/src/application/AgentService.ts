@@ -11,21 +11,29 @@
  * Uses dependency injection for all infrastructure concerns.
  */
 
-import { nanoid } from 'nanoid';
-import type { IMessageConsumer } from '../ports/messaging/IMessageConsumer.js';
+import {
+  CAUSATION_ID_HEADER,
+  CORRELATION_ID_HEADER,
+  TRACE_CONTEXT_HEADERS,
+  type IMessageConsumer,
+  type MessageMetadata,
+} from '../ports/messaging/IMessageConsumer.js';
 import type { IMessagePublisher } from '../ports/messaging/IMessagePublisher.js';
 import type { IHealthMonitor } from '../ports/monitoring/IHealthMonitor.js';
+import { progressPurpose, type IMessageSigner } from '../ports/security/IMessageSigner.js';
 import { ScenarioExecutor } from '../execution/index.js';
 import type { ExecuteScenarioMessage, ProgressEvent } from '../types/generated/messages.js';
 
 export interface AgentServiceConfig {
-  agentId?: string;
+  /** ID the backend assigned to the agent when it enrolled */
+  agentId: string;
   version: string;
   browserType?: 'chromium' | 'firefox' | 'webkit';
   browserPath?: string;
   messageConsumer: IMessageConsumer<ExecuteScenarioMessage>;
   messagePublisher: IMessagePublisher;
   healthMonitor: IHealthMonitor;
+  messageSigner: IMessageSigner;
   requestQueueName: string;
 }
 
@@ -37,7 +45,7 @@ export class AgentService {
 
   constructor(config: AgentServiceConfig) {
     this.config = config;
-    this.agentId = config.agentId || nanoid();
+    this.agentId = config.agentId;
   }
 
   /**
@@ -73,7 +81,7 @@ export class AgentService {
     // Start consuming messages
     await this.config.messageConsumer.consume(
       this.config.requestQueueName,
-      (message) => this.handleExecutionRequest(message)
+      (message, metadata) => this.handleExecutionRequest(message, metadata)
     );
 
     console.log(`[Agent ${this.agentId}] Ready to receive execution requests`);
@@ -91,7 +99,11 @@ export class AgentService {
   /**
    * Handle an incoming execution request
    */
-  private async handleExecutionRequest(message: ExecuteScenarioMessage): Promise<void> {
+  private async handleExecutionRequest(
+    message: ExecuteScenarioMessage,
+    metadata: MessageMetadata
+  ): Promise<void> {
+    const headers = correlationHeaders(metadata);
     console.log(
       `\n[Agent ${this.agentId}] Starting run ${message.run_id} for scenario ${message.scenario_id}`
     );
@@ -109,7 +121,7 @@ export class AgentService {
         browserType: this.config.browserType || 'chromium',
         browserPath: this.config.browserPath,
         onProgress: async (event: ProgressEvent) => {
-          await this.publishProgressEvent(progressQueue, event);
+          await this.publishProgressEvent(progressQueue, event, headers);
         }
       });
 
@@ -124,7 +136,7 @@ export class AgentService {
       console.error(`[Agent ${this.agentId}] Execution error:`, error);
 
       // Send failure event
-      await this.publishFailureEvent(message, error);
+      await this.publishFailureEvent(message, error, headers);
 
       // Update status
       this.updateStatus('error', null);
@@ -143,11 +155,13 @@ export class AgentService {
    */
   private async publishProgressEvent(
     queueName: string,
-    event: ProgressEvent
+    event: ProgressEvent,
+    headers: Record<string, string>
   ): Promise<void> {
     try {
       await this.config.messagePublisher.publish(queueName, event, {
-        persistent: true
+        persistent: true,
+        headers: { ...headers, ...this.signedHeaders(event) }
       });
     } catch (error) {
       console.error(`[Agent ${this.agentId}] Failed to publish progress event:`, error);
@@ -159,7 +173,8 @@ export class AgentService {
    */
   private async publishFailureEvent(
     message: ExecuteScenarioMessage,
-    error: unknown
+    error: unknown,
+    headers: Record<string, string>
   ): Promise<void> {
     try {
       const progressQueue = message.reply_queue || `agent.progress.${message.run_id}`;
@@ -172,13 +187,21 @@ export class AgentService {
       };
 
       await this.config.messagePublisher.publish(progressQueue, failureEvent, {
-        persistent: true
+        persistent: true,
+        headers: { ...headers, ...this.signedHeaders(failureEvent) }
       });
     } catch (err) {
       console.error(`[Agent ${this.agentId}] Failed to publish failure event:`, err);
     }
   }
 
+  /**
+   * Sign a progress event for its run, so the backend accepts it from this agent only
+   */
+  private signedHeaders(event: ProgressEvent): Record<string, string> {
+    return this.config.messageSigner.sign(progressPurpose(event.run_id), JSON.stringify(event));
+  }
+
   /**
    * Update agent status and report to health monitor
    */
@@ -197,3 +220,24 @@ export class AgentService {
     });
   }
 }
+
+/**
+ * Headers that keep the messages sent for a request in the request's correlation
+ * and trace, with the request message as their cause
+ */
+function correlationHeaders(metadata: MessageMetadata): Record<string, string> {
+  const headers: Record<string, string> = {};
+  const correlationId = metadata.headers[CORRELATION_ID_HEADER] ?? metadata.messageId;
+  if (correlationId) {
+    headers[CORRELATION_ID_HEADER] = String(correlationId);
+  }
+  if (metadata.messageId) {
+    headers[CAUSATION_ID_HEADER] = metadata.messageId;
+  }
+  for (const header of TRACE_CONTEXT_HEADERS) {
+    if (metadata.headers[header]) {
+      headers[header] = String(metadata.headers[header]);
+    }
+  }
+  return headers;
+}
@@ -7,3 +7,4 @@
 export type { IMessageConsumer } from './messaging/IMessageConsumer.js';
 export type { IMessagePublisher, PublishOptions } from './messaging/IMessagePublisher.js';
 export type { IHealthMonitor, AgentStatus } from './monitoring/IHealthMonitor.js';
+export type { IMessageSigner } from './security/IMessageSigner.js';
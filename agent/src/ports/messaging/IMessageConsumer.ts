@@ -6,15 +6,36 @@
  * messaging implementation (RabbitMQ, Kafka, Redis, etc.)
  */
 
+/**
+ * Headers tying a message to the request that led to it; the backend sets them
+ * and the agent copies them onto the messages it sends in response
+ */
+export const CORRELATION_ID_HEADER = 'x-correlation-id';
+export const CAUSATION_ID_HEADER = 'x-causation-id';
+
+/**
+ * W3C trace context headers; copying them onto responses keeps the agent's
+ * messages in the trace of the backend request that started the run
+ */
+export const TRACE_CONTEXT_HEADERS = ['traceparent', 'tracestate'] as const;
+
+/**
+ * Broker properties of a consumed message
+ */
+export interface MessageMetadata {
+  messageId?: string;
+  headers: Record<string, any>;
+}
+
 export interface IMessageConsumer<TMessage = any> {
   /**
    * Start consuming messages from the queue
    * @param queueName - Name of the queue to consume from
-   * @param handler - Callback to handle incoming messages
+   * @param handler - Callback to handle incoming messages and their metadata
    */
   consume(
     queueName: string,
-    handler: (message: TMessage) => Promise<void>
+    handler: (message: TMessage, metadata: MessageMetadata) => Promise<void>
   ): Promise<void>;
 
   /**
@@ -0,0 +1,35 @@
+/**
+ * Message Signer Port
+ *
+ * Abstraction for signing the messages the agent sends to the backend with the
+ * credential it received when it enrolled. The backend rejects heartbeats and
+ * progress events that are not signed by a registered agent.
+ */
+
+/**
+ * Headers carrying the signature of a broker message, mirroring the
+ * X-Parrotflow-* headers of signed HTTP requests
+ */
+export const AGENT_HEADER = 'x-parrotflow-agent';
+export const TIMESTAMP_HEADER = 'x-parrotflow-timestamp';
+export const NONCE_HEADER = 'x-parrotflow-nonce';
+export const SIGNATURE_HEADER = 'x-parrotflow-signature';
+
+/**
+ * Purposes a message is signed for, so a signature for one cannot be replayed as another
+ */
+export const HEARTBEAT_PURPOSE = 'heartbeat';
+
+export function progressPurpose(runId: string): string {
+  return `progress.${runId}`;
+}
+
+export interface IMessageSigner {
+  /**
+   * Sign a message body for a purpose
+   * @param purpose - What the message is for, e.g. HEARTBEAT_PURPOSE
+   * @param body - The exact bytes that are sent, as the publisher serializes them
+   * @returns The headers to send along the message
+   */
+  sign(purpose: string, body: string): Record<string, string>;
+}
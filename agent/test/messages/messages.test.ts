@@ -0,0 +1,136 @@
+/**
+ * Round-trips the sample messages shared with the Go backend
+ *
+ * The backend decodes the same samples into its generated types, so a schema
+ * change that is not reflected in the samples breaks one side or the other
+ */
+
+import { describe, it } from 'node:test';
+import assert from 'node:assert';
+import * as fs from 'fs';
+import * as path from 'path';
+import * as yaml from 'js-yaml';
+import { fileURLToPath } from 'url';
+import type {
+  AgentHeartbeat,
+  ControlCommand,
+  ExecuteScenarioMessage,
+  ProgressEvent,
+} from '../../src/types/generated/messages.js';
+
+const __dirname = path.dirname(fileURLToPath(import.meta.url));
+const SCHEMA_DIR = path.join(__dirname, '../../../shared/schemas');
+
+interface MessageSamples {
+  ExecuteScenarioMessage: ExecuteScenarioMessage;
+  ProgressEvent: ProgressEvent;
+  ControlCommand: ControlCommand;
+  AgentHeartbeat: AgentHeartbeat;
+}
+
+const schemas = (
+  yaml.load(fs.readFileSync(path.join(SCHEMA_DIR, 'messages.yaml'), 'utf-8')) as {
+    components: { schemas: Record<string, any> };
+  }
+).components.schemas;
+
+function loadSample<K extends keyof MessageSamples>(name: K): MessageSamples[K] {
+  const json = fs.readFileSync(path.join(SCHEMA_DIR, 'samples', `${name}.json`), 'utf-8');
+  return JSON.parse(json) as MessageSamples[K];
+}
+
+/**
+ * Checks a value against a schema: required properties are present, no
+ * undeclared properties appear and enums hold one of their values
+ */
+function validate(value: any, schema: any, at: string): void {
+  if (schema.$ref) {
+    const name = schema.$ref.split('/').pop();
+    validate(value, schemas[name], at);
+    return;
+  }
+  if (schema.oneOf || value === null) {
+    return;
+  }
+  if (schema.enum) {
+    assert.ok(schema.enum.includes(value), `${at}: ${value} is not one of ${schema.enum.join(', ')}`);
+  }
+
+  switch (schema.type) {
+    case 'array':
+      assert.ok(Array.isArray(value), `${at}: expected an array`);
+      value.forEach((item: any, i: number) => validate(item, schema.items, `${at}[${i}]`));
+      break;
+    case 'object':
+      assert.strictEqual(typeof value, 'object', `${at}: expected an object`);
+      if (!schema.properties) {
+        break;
+      }
+      for (const required of schema.required ?? []) {
+        assert.ok(required in value, `${at}: missing required property ${required}`);
+      }
+      for (const [key, item] of Object.entries(value)) {
+        assert.ok(key in schema.properties, `${at}: property ${key} is not in the schema`);
+        validate(item, schema.properties[key], `${at}.${key}`);
+      }
+      break;
+    case 'integer':
+      assert.ok(Number.isInteger(value), `${at}: expected an integer`);
+      break;
+    case 'string':
+    case 'number':
+    case 'boolean':
+      assert.strictEqual(typeof value, schema.type, `${at}: expected a ${schema.type}`);
+      break;
+  }
+}
+
+describe('Shared message samples', () => {
+  const names: (keyof MessageSamples)[] = [
+    'ExecuteScenarioMessage',
+    'ProgressEvent',
+    'ControlCommand',
+    'AgentHeartbeat',
+  ];
+
+  for (const name of names) {
+    it(`${name} matches the schema and survives a JSON round trip`, () => {
+      const sample = loadSample(name);
+      validate(sample, schemas[name], name);
+
+      const roundTripped = JSON.parse(JSON.stringify(sample)) as MessageSamples[typeof name];
+      assert.deepStrictEqual(roundTripped, sample);
+    });
+  }
+
+  it('declares every property of the message schemas in some sample', () => {
+    // A property no sample uses would not be caught drifting on the Go side
+    const seen = new Set<string>();
+    const collect = (value: any, schema: any, type: string): void => {
+      if (schema?.$ref) {
+        const name = schema.$ref.split('/').pop();
+        collect(value, schemas[name], name);
+        return;
+      }
+      if (Array.isArray(value)) {
+        value.forEach((item) => collect(item, schema.items, type));
+        return;
+      }
+      if (value && typeof value === 'object' && schema?.properties) {
+        for (const [key, item] of Object.entries(value)) {
+          seen.add(`${type}.${key}`);
+          collect(item, schema.properties[key], type);
+        }
+      }
+    };
+    for (const name of names) {
+      collect(loadSample(name), schemas[name], name);
+    }
+
+    for (const name of names) {
+      for (const key of Object.keys(schemas[name].properties)) {
+        assert.ok(seen.has(`${name}.${key}`), `no sample sets ${name}.${key}`);
+      }
+    }
+  });
+});
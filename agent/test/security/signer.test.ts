@@ -0,0 +1,45 @@
+/**
+ * The signatures have to match what the Go backend computes, or it rejects
+ * every heartbeat and progress event of the agent
+ */
+
+import { describe, it } from 'node:test';
+import assert from 'node:assert';
+import { HmacMessageSigner } from '../../src/adapters/security/HmacMessageSigner.js';
+import {
+  AGENT_HEADER,
+  NONCE_HEADER,
+  SIGNATURE_HEADER,
+  TIMESTAMP_HEADER,
+  progressPurpose,
+} from '../../src/ports/security/IMessageSigner.js';
+
+describe('HmacMessageSigner', () => {
+  it('signs like the backend verifies', () => {
+    const signer = new HmacMessageSigner({
+      agentId: '3',
+      credential: 's3cr3t',
+      now: () => new Date(1700000000 * 1000),
+      nonce: () => '2f1c6c1e-7d0a-4c5e-9a53-6a6f3c1e8b11',
+    });
+
+    // Computed with agent.Sign of the backend
+    const headers = signer.sign(progressPurpose('7'), '{"run_id":"7","event":"node_started"}');
+
+    assert.deepStrictEqual(headers, {
+      [AGENT_HEADER]: '3',
+      [TIMESTAMP_HEADER]: '1700000000',
+      [NONCE_HEADER]: '2f1c6c1e-7d0a-4c5e-9a53-6a6f3c1e8b11',
+      [SIGNATURE_HEADER]: 'sha256=35c29b89ad8aa0696377e051715078f856030a34ea9eba9c1b5078c5f07efecb',
+    });
+  });
+
+  it('signs every message with a fresh nonce', () => {
+    const signer = new HmacMessageSigner({ agentId: '3', credential: 's3cr3t' });
+
+    const first = signer.sign(progressPurpose('7'), '{}');
+    const second = signer.sign(progressPurpose('7'), '{}');
+
+    assert.notStrictEqual(first[NONCE_HEADER], second[NONCE_HEADER]);
+  });
+});
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	accesscommand "parrotflow/internal/application/command/access"
+	command "parrotflow/internal/application/command/apikey"
+	"parrotflow/internal/domain/apikey"
+	"parrotflow/internal/infrastructure/events"
+	"parrotflow/internal/infrastructure/persistence"
+
+	"github.com/danielgtaylor/huma/v2/humacli"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// apiKeyCommand manages API keys straight in the database, which is how the first
+// admin key is created; the domain events are relayed once the server runs
+func apiKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apikey",
+		Short: "Create, list and revoke API keys",
+	}
+	cmd.AddCommand(apiKeyCreateCommand(), apiKeyListCommand(), apiKeyRevokeCommand())
+	return cmd
+}
+
+func apiKeyCreateCommand() *cobra.Command {
+	var name string
+	var scopes []string
+	var roles []string
+	var expiresIn time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an API key and print it; the key cannot be shown again",
+		Args:  cobra.NoArgs,
+		Run: humacli.WithOptions(func(cmd *cobra.Command, args []string, options *Options) {
+			database := openDatabaseOrExit(options)
+			eventBus := events.NewInMemoryEventBus()
+			handler := command.NewCreateAPIKeyCommandHandler(persistence.NewAPIKeyRepository(database), eventBus)
+
+			create := command.CreateAPIKeyCommand{Name: name, Scopes: scopes}
+			if expiresIn > 0 {
+				expiresAt := time.Now().Add(expiresIn)
+				create.ExpiresAt = &expiresAt
+			}
+			issued, err := handler.Handle(cmd.Context(), create)
+			exitOnError(err, "failed to create API key")
+
+			k := issued.APIKey
+			fmt.Fprintf(os.Stderr, "Created API key %s %q with scopes %s\n", k.Id, k.Name, strings.Join(k.Scopes, ","))
+
+			if len(roles) > 0 {
+				assign := accesscommand.NewAssignRolesCommandHandler(persistence.NewRoleAssignmentRepository(database), persistence.NewAPIKeyRepository(database), eventBus)
+				a, err := assign.Handle(cmd.Context(), accesscommand.AssignRolesCommand{Subject: k.Subject(), Roles: roles})
+				exitOnError(err, "failed to assign roles, the key was created without any")
+				fmt.Fprintf(os.Stderr, "Assigned %s the roles %s\n", a.Subject, formatRoles(a.Roles))
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), issued.Token)
+		}),
+	}
+	cmd.Flags().StringVar(&name, "name", "", "What the key is for, e.g. the integration using it")
+	cmd.Flags().StringSliceVar(&scopes, "scopes", []string{apikey.ScopeRead}, "Comma-separated scopes: read, write or admin")
+	cmd.Flags().StringSliceVar(&roles, "roles", nil, "Comma-separated roles of the key: viewer, operator, author or admin")
+	cmd.Flags().DurationVar(&expiresIn, "expires-in", 0, "How long the key works, e.g. 720h (0 never expires)")
+	_ = cmd.MarkFlagRequired("name")
+	return cmd
+}
+
+func apiKeyListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List API keys",
+		Args:  cobra.NoArgs,
+		Run: humacli.WithOptions(func(cmd *cobra.Command, args []string, options *Options) {
+			keys, err := persistence.NewAPIKeyRepository(openDatabaseOrExit(options)).FindAll(cmd.Context())
+			exitOnError(err, "failed to list API keys")
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tSTATUS\tLAST USED")
+			now := time.Now()
+			for _, k := range keys {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", k.Id, k.Name, k.Prefix, strings.Join(k.Scopes, ","), apiKeyStatus(k, now), formatOptionalTime(k.LastUsedAt))
+			}
+			_ = w.Flush()
+		}),
+	}
+}
+
+func apiKeyRevokeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke <id>",
+		Short: "Revoke an API key",
+		Args:  cobra.ExactArgs(1),
+		Run: humacli.WithOptions(func(cmd *cobra.Command, args []string, options *Options) {
+			keyID, err := apikey.NewAPIKeyID(args[0])
+			exitOnError(err, "invalid API key ID")
+
+			handler := command.NewRevokeAPIKeyCommandHandler(persistence.NewAPIKeyRepository(openDatabaseOrExit(options)), events.NewInMemoryEventBus())
+			k, err := handler.Handle(cmd.Context(), command.RevokeAPIKeyCommand{ID: keyID})
+			exitOnError(err, "failed to revoke API key")
+
+			fmt.Fprintf(os.Stderr, "Revoked API key %s %q\n", k.Id, k.Name)
+		}),
+	}
+}
+
+func openDatabaseOrExit(options *Options) *gorm.DB {
+	database, err := openDatabase(options.DbPath)
+	exitOnError(err, "failed to open database")
+	return database
+}
+
+func apiKeyStatus(k *apikey.APIKey, now time.Time) string {
+	switch {
+	case k.RevokedAt != nil:
+		return "revoked"
+	case k.ExpiresAt != nil && !now.Before(*k.ExpiresAt):
+		return "expired"
+	case k.ExpiresAt != nil:
+		return "expires " + k.ExpiresAt.Format(time.RFC3339)
+	default:
+		return "active"
+	}
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// exitOnError ends a subcommand with a message instead of the panic FailOnError raises
+func exitOnError(err error, msg string) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", msg, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"parrotflow/internal/infrastructure/encryption"
+	"parrotflow/internal/infrastructure/persistence"
+
+	"github.com/danielgtaylor/huma/v2/humacli"
+	"github.com/spf13/cobra"
+)
+
+// keysCommand generates encryption keys and re-encrypts stored credentials with the
+// primary one, which is how keys are rotated: put a new key first, run rotate, then
+// drop the old key
+func keysCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Generate encryption keys and rotate stored credentials to the primary key",
+	}
+	cmd.AddCommand(keysGenerateCommand(), keysRotateCommand())
+	return cmd
+}
+
+func keysGenerateCommand() *cobra.Command {
+	var id string
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Print a new key entry for the encryption keys",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			entry, err := encryption.GenerateKey(id)
+			exitOnError(err, "failed to generate key")
+			fmt.Fprintln(cmd.OutOrStdout(), entry)
+		},
+	}
+	cmd.Flags().StringVar(&id, "id", "", "ID of the key, recorded next to every value it encrypts, e.g. 2026-10")
+	_ = cmd.MarkFlagRequired("id")
+	return cmd
+}
+
+func keysRotateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate",
+		Short: "Re-encrypt stored credentials with the primary key",
+		Long:  "Re-encrypt stored credentials with the primary key, the first of the encryption keys. Every key values are currently encrypted with must still be configured.",
+		Args:  cobra.NoArgs,
+		Run: humacli.WithOptions(func(cmd *cobra.Command, args []string, options *Options) {
+			keyring, err := encryption.NewKeyring(encryptionConfig(options))
+			exitOnError(err, "invalid encryption keys")
+			if !keyring.Enabled() {
+				fmt.Fprintln(os.Stderr, "No encryption keys configured, rotating would store credentials in plaintext")
+				os.Exit(1)
+			}
+
+			database := openDatabaseOrExit(options)
+			proxies, err := persistence.NewProxyRepository(database, keyring).ReencryptPasswords(cmd.Context())
+			exitOnError(err, "failed to re-encrypt proxy passwords")
+			secrets, err := persistence.NewSecretRepository(database, keyring).ReencryptValues(cmd.Context())
+			exitOnError(err, "failed to re-encrypt secrets")
+			webhooks, err := persistence.NewWebhookRepository(database, keyring).ReencryptSecrets(cmd.Context())
+			exitOnError(err, "failed to re-encrypt webhook secrets")
+			agents, err := persistence.NewAgentRepository(database, keyring).ReencryptCredentials(cmd.Context())
+			exitOnError(err, "failed to re-encrypt agent credentials")
+			deadLetters, err := persistence.NewDeadLetterRepository(database, keyring).ReencryptPayloads(cmd.Context())
+			exitOnError(err, "failed to re-encrypt dead letter payloads")
+			fmt.Fprintf(os.Stderr, "Re-encrypted %d proxy passwords, %d secrets, %d webhook secrets, %d agent credentials and %d dead letter payloads with key %q\n", proxies, secrets, webhooks, agents, deadLetters, keyring.PrimaryKeyID())
+		}),
+	}
+}
@@ -1,12 +1,32 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	"parrotflow/internal/container"
+	"parrotflow/internal/domain/access"
+	"parrotflow/internal/domain/apikey"
+	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/infrastructure/auth"
+	"parrotflow/internal/infrastructure/encryption"
+	"parrotflow/internal/infrastructure/events"
+	"parrotflow/internal/infrastructure/health"
+	"parrotflow/internal/infrastructure/logging"
+	"parrotflow/internal/infrastructure/maintenance"
+	"parrotflow/internal/infrastructure/messaging"
+	"parrotflow/internal/infrastructure/persistence"
+	"parrotflow/internal/infrastructure/tracing"
+	"parrotflow/internal/infrastructure/webhooks"
+	"parrotflow/internal/interfaces/http/middleware"
 	"parrotflow/internal/interfaces/http/routes"
+	"parrotflow/internal/interfaces/http/ws"
 	"parrotflow/internal/models"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -18,8 +38,63 @@ import (
 )
 
 type Options struct {
-	Port   int    `help:"Port to listen on" short:"p" default:"8888"`
-	DbPath string `help:"Database file path" short:"d" default:"store.db"`
+	Port           int           `help:"Port to listen on" short:"p" default:"8888"`
+	DbPath         string        `help:"Database file path" short:"d" default:"store.db"`
+	LogFormat      string        `help:"Log output format: text or json" default:"text"`
+	LogLevel       string        `help:"Minimum level logged: debug, info, warn or error" default:"info"`
+	TrashRetention time.Duration `help:"How long deleted scenarios, proxies and tags stay in the trash before being purged (0 keeps them forever)" default:"720h"`
+	PurgeInterval  time.Duration `help:"How often the trash is checked for expired items" default:"1h"`
+
+	// Global run retention, scenarios can override it through the API
+	RunMaxAge       time.Duration `help:"Compact finished runs older than this (0 keeps them regardless of age)" default:"0s"`
+	RunMaxCount     int           `help:"Keep at most this many runs per scenario (0 means no limit)" default:"0"`
+	RunKeepStatuses string        `help:"Comma-separated run statuses that are never compacted, e.g. FAILED" default:""`
+	RunArchiveDir   string        `help:"Directory for gzip JSONL archives of compacted runs (empty deletes without archiving)" default:"archive/runs"`
+	CompactInterval time.Duration `help:"How often run retention is applied" default:"1h"`
+
+	// Materialized daily rollup of the run analytics
+	RollupInterval time.Duration `help:"How often the daily run rollup is refreshed (0 disables it)" default:"0s"`
+	RollupLookback time.Duration `help:"How many past days the rollup refresh recomputes; keep it below run retention, a large value once backfills history" default:"48h"`
+
+	WebhookTimeout             time.Duration `help:"Timeout of a single webhook request" default:"10s"`
+	WebhookMaxAttempts         int           `help:"Attempts per webhook delivery before it is marked failed" default:"8"`
+	WebhookDisableAfter        int           `help:"Consecutive failed deliveries that disable a webhook (0 never disables)" default:"5"`
+	WebhookAllowPrivateTargets bool          `help:"Let webhooks reach loopback, private and link-local addresses, e.g. to deliver inside a private network" default:"false"`
+
+	EventWorkers         int           `help:"Workers running in-process event handlers" default:"8"`
+	EventQueueSize       int           `help:"Events queued per event worker before publishing blocks" default:"256"`
+	EventHandlerTimeout  time.Duration `help:"Timeout of a single event handler call" default:"30s"`
+	EventShutdownTimeout time.Duration `help:"How long to wait for queued events to be handled on shutdown" default:"10s"`
+
+	Broker           string `help:"Message broker: memory (in-process), rabbitmq or nats" default:"memory"`
+	BrokerURL        string `help:"URL of the broker, required for rabbitmq; nats starts an embedded server when empty" default:""`
+	NatsStoreDir     string `help:"Directory the embedded NATS server keeps its streams in" default:"data/nats"`
+	NatsPort         int    `help:"Client port of the embedded NATS server (0 accepts in-process connections only)" default:"0"`
+	DeadLetterQueues string `help:"Comma-separated queues asserted with dead-letter routing" default:"agent.requests"`
+
+	HealthTimeout          time.Duration `help:"How long a readiness probe may take before its component counts as down" default:"2s"`
+	AgentHeartbeatTimeout  time.Duration `help:"Heartbeat age after which a connected agent counts as stale" default:"5m"`
+	MaxStaleAgentsPercent  int           `help:"Percentage of stale agents above which the instance reports itself degraded" default:"50"`
+	MaxEventBacklogPercent int           `help:"Fill percentage of the event queues above which the instance reports itself degraded" default:"80"`
+
+	// Prometheus cannot present API keys, so the metrics are served to anyone who can reach them
+	ServeMetrics bool `help:"Serve Prometheus metrics on /metrics without authentication; turn off or firewall it where the API is public" default:"true"`
+
+	TraceExporter      string `help:"Where OpenTelemetry spans are sent: none, stdout or otlp" default:"none"`
+	OtlpEndpoint       string `help:"host:port of the OTLP/HTTP collector (empty uses OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318)" default:""`
+	OtlpInsecure       bool   `help:"Send spans to the collector over plain HTTP" default:"false"`
+	TraceSamplePercent int    `help:"Percentage of new traces recorded, traces started by a caller follow its decision" default:"100"`
+
+	// API keys are always accepted, JWT bearer tokens once a secret or JWKS is set
+	AuthDisabled bool   `help:"Accept requests without credentials as an admin, for local development only" default:"false"`
+	JwtSecret    string `help:"HMAC secret verifying HS256/HS384/HS512 bearer tokens, better set as SERVICE_JWT_SECRET" default:""`
+	JwksUrl      string `help:"JWKS URL of the identity provider verifying RS/PS/ES/EdDSA bearer tokens" default:""`
+	JwtIssuer    string `help:"iss claim bearer tokens must carry (empty accepts any)" default:""`
+	JwtAudience  string `help:"aud claim bearer tokens must carry (empty accepts any)" default:""`
+
+	// Key encryption keys sealing stored credentials, the first one encrypts new values
+	EncryptionKeys    string `help:"Comma-separated id:base64 keys encrypting stored credentials, better set as SERVICE_ENCRYPTION_KEYS (see keys generate)" default:""`
+	EncryptionKeyFile string `help:"File with one id:base64 key per line, read after the encryption keys" default:""`
 }
 
 func FailOnError(err error, msg string) {
@@ -28,39 +103,236 @@ func FailOnError(err error, msg string) {
 	}
 }
 
+func loggingConfig(options *Options) (logging.Config, error) {
+	config := logging.DefaultConfig()
+	config.Format = options.LogFormat
+	level, err := logging.ParseLevel(options.LogLevel)
+	config.Level = level
+	return config, err
+}
+
+func tracingConfig(options *Options) tracing.Config {
+	config := tracing.DefaultConfig()
+	config.Exporter = options.TraceExporter
+	config.OTLPEndpoint = options.OtlpEndpoint
+	config.OTLPInsecure = options.OtlpInsecure
+	config.SampleRatio = float64(options.TraceSamplePercent) / 100
+	return config
+}
+
+func authConfig(options *Options) auth.Config {
+	config := auth.DefaultConfig()
+	config.Disabled = options.AuthDisabled
+	config.JWTSecret = options.JwtSecret
+	config.JWKSURL = options.JwksUrl
+	config.Issuer = options.JwtIssuer
+	config.Audience = options.JwtAudience
+	return config
+}
+
+func encryptionConfig(options *Options) encryption.Config {
+	return encryption.Config{
+		Keys:    options.EncryptionKeys,
+		KeyFile: options.EncryptionKeyFile,
+	}
+}
+
+func healthConfig(options *Options) health.Config {
+	config := health.DefaultConfig()
+	config.Timeout = options.HealthTimeout
+	config.HeartbeatTimeout = options.AgentHeartbeatTimeout
+	config.MaxStaleAgents = float64(options.MaxStaleAgentsPercent) / 100
+	config.MaxEventBacklog = float64(options.MaxEventBacklogPercent) / 100
+	return config
+}
+
+func webhookConfig(options *Options) webhooks.Config {
+	config := webhooks.DefaultConfig()
+	config.Timeout = options.WebhookTimeout
+	config.MaxAttempts = options.WebhookMaxAttempts
+	config.DisableAfter = options.WebhookDisableAfter
+	config.AllowPrivateTargets = options.WebhookAllowPrivateTargets
+	return config
+}
+
+func eventBusConfig(options *Options) events.WorkerPoolConfig {
+	config := events.DefaultWorkerPoolConfig()
+	config.Workers = options.EventWorkers
+	config.QueueSize = options.EventQueueSize
+	config.HandlerTimeout = options.EventHandlerTimeout
+	return config
+}
+
+func messagingConfig(options *Options) messaging.Config {
+	config := messaging.DefaultConfig()
+	config.Broker = options.Broker
+	config.BrokerURL = options.BrokerURL
+	config.NATSStoreDir = options.NatsStoreDir
+	config.NATSPort = options.NatsPort
+	config.Queues = nil
+	for _, queue := range strings.Split(options.DeadLetterQueues, ",") {
+		if queue = strings.TrimSpace(queue); queue != "" {
+			config.Queues = append(config.Queues, queue)
+		}
+	}
+	return config
+}
+
+// openDatabase opens the SQLite database and migrates it to the current schema
+func openDatabase(path string) (*gorm.DB, error) {
+	database, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.AutoMigrate(
+		&models.Scenario{},
+		&models.ScenarioRun{},
+		&models.RunNodeStep{},
+		&models.Tag{},
+		&models.Proxy{},
+		&models.Agent{},
+		&models.AgentNonce{},
+		&models.OutboxEvent{},
+		&models.RunSummary{},
+		&models.RunRollup{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.APIKey{},
+		&models.RoleAssignment{},
+		&models.EnrollmentToken{},
+		&models.Secret{},
+		&models.EventLogEntry{},
+		&models.AuditEntry{},
+		&models.EventDeadLetter{},
+		&models.MessageDeadLetter{},
+		&models.SchemaMigration{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+	if err := persistence.ProtectAuditLog(context.Background(), database); err != nil {
+		return nil, fmt.Errorf("failed to protect the audit log: %w", err)
+	}
+	if err := persistence.RecordSchemaVersion(context.Background(), database); err != nil {
+		return nil, fmt.Errorf("failed to record schema version: %w", err)
+	}
+	return database, nil
+}
+
+// setupServer initializes tracing, the database and the application, and routes the API
+// The returned function flushes pending spans
+func setupServer(options *Options) (*container.Application, http.Handler, func(context.Context) error) {
+	// Initialize tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingConfig(options))
+	FailOnError(err, "failed to initialize tracing")
+
+	// Initialize and migrate the database
+	database, err := openDatabase(options.DbPath)
+	FailOnError(err, "failed to open database")
+	FailOnError(tracing.InstrumentGORM(database), "failed to instrument database")
+
+	// Initialize application with Wire DI
+	retention, err := scenario.NewRetentionPolicy(options.RunMaxAge, options.RunMaxCount, strings.Split(options.RunKeepStatuses, ","))
+	FailOnError(err, "invalid run retention policy")
+
+	app, err := container.InitializeApp(database, maintenance.Config{
+		Purge: maintenance.PurgeConfig{
+			Retention: options.TrashRetention,
+			Interval:  options.PurgeInterval,
+		},
+		Compaction: maintenance.CompactionConfig{
+			Retention:  retention,
+			Interval:   options.CompactInterval,
+			ArchiveDir: options.RunArchiveDir,
+		},
+		Rollup: maintenance.RollupConfig{
+			Interval: options.RollupInterval,
+			Lookback: options.RollupLookback,
+		},
+	}, webhookConfig(options), eventBusConfig(options), messagingConfig(options), healthConfig(options), authConfig(options), encryptionConfig(options))
+	FailOnError(err, "failed to initialize application")
+	if options.EncryptionKeys == "" && options.EncryptionKeyFile == "" {
+		slog.Warn("No encryption keys configured, secret routes are disabled, proxies with passwords cannot be saved and webhook secrets and agent credentials are stored in plaintext")
+	}
+
+	// Setup HTTP router and API
+	router := chi.NewMux()
+	router.Use(middleware.Tracing, middleware.RequestID, middleware.Logger)
+	api := humachi.New(router, huma.DefaultConfig("Parrot Flow API", "1.0.0"))
+
+	// Register all routes
+	routes.RegisterAllRoutes(&api, app)
+	app.WebSocketServer.SetAuthHooks(ws.AuthHooks{Authorize: routes.AuthorizeTopic})
+	router.With(
+		middleware.RequireScope(app.Authenticator, apikey.ScopeRead),
+		middleware.RequirePermission(access.PermissionRead),
+	).Handle("/api/ws", app.WebSocketServer)
+	if options.ServeMetrics {
+		router.Handle("/metrics", app.Metrics.Handler())
+	}
+
+	return app, router, shutdownTracing
+}
+
 func main() {
 	cli := humacli.New(func(hooks humacli.Hooks, options *Options) {
-		// Initialize database
-		database, err := gorm.Open(sqlite.Open(options.DbPath), &gorm.Config{})
-		FailOnError(err, "failed to connect to database")
-
-		// Run migrations
-		err = database.AutoMigrate(
-			&models.Scenario{},
-			&models.ScenarioRun{},
-			&models.Tag{},
-			&models.Proxy{},
-			&models.Agent{},
-		)
-		FailOnError(err, "failed to migrate database")
-
-		// Initialize application with Wire DI
-		app, err := container.InitializeApp(database)
-		FailOnError(err, "failed to initialize application")
-
-		// Setup HTTP router and API
-		router := chi.NewMux()
-		api := humachi.New(router, huma.DefaultConfig("Parrot Flow API", "1.0.0"))
-
-		// Register all routes
-		routes.RegisterAllRoutes(&api, app)
-
-		// Start server
+		// Initialize logging, the standard log package writes through it as well
+		logConfig, err := loggingConfig(options)
+		FailOnError(err, "invalid logging configuration")
+		logger, err := logging.New(os.Stderr, logConfig)
+		FailOnError(err, "invalid logging configuration")
+		slog.SetDefault(logger)
+
+		// The server is set up when it starts, so subcommands do not connect to the broker
+		var app *container.Application
+		var shutdownTracing func(context.Context) error
+		ready := make(chan struct{})
+
+		// Start server and background workers
+		ctx, cancel := context.WithCancel(context.Background())
 		hooks.OnStart(func() {
-			fmt.Printf("Starting server on port %d...\n", options.Port)
-			http.ListenAndServe(fmt.Sprintf(":%d", options.Port), router)
+			var router http.Handler
+			app, router, shutdownTracing = setupServer(options)
+			close(ready)
+
+			// Consume agent messages before relaying events, so started runs find the consumer ready
+			app.AgentConsumer.Start(ctx)
+			go app.OutboxRelay.Run(ctx)
+			go app.PurgeWorker.Run(ctx)
+			go app.RunCompactor.Run(ctx)
+			go app.RollupWorker.Run(ctx)
+			go app.WebhookWorker.Run(ctx)
+			go app.DeadLetterCollector.Run(ctx)
+
+			slog.Info("Starting server", "port", options.Port)
+			if err := http.ListenAndServe(fmt.Sprintf(":%d", options.Port), router); err != nil {
+				slog.Error("Server stopped", "error", err)
+			}
+		})
+		hooks.OnStop(func() {
+			cancel()
+			select {
+			case <-ready:
+			default:
+				return // Stopped before the server was set up
+			}
+
+			// Let in-process handlers finish the events already relayed to them
+			shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), options.EventShutdownTimeout)
+			defer cancelShutdown()
+			if err := app.EventDispatcher.Shutdown(shutdownCtx); err != nil {
+				slog.Warn("Event handlers did not drain", "error", err)
+			}
+			if err := app.MessageBroker.Close(); err != nil {
+				slog.Error("Error closing message broker", "error", err)
+			}
+			if err := shutdownTracing(shutdownCtx); err != nil {
+				slog.Error("Error flushing spans", "error", err)
+			}
 		})
 	})
 
+	cli.Root().AddCommand(apiKeyCommand(), roleCommand(), keysCommand())
 	cli.Run()
 }
@@ -1,9 +1,12 @@
 package migrations
 
 import (
+	"context"
+
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
+	"parrotflow/internal/infrastructure/persistence"
 	"parrotflow/internal/models"
 )
 
@@ -17,8 +20,26 @@ func Init(dbPath string) {
 	database.AutoMigrate(
 		&models.Scenario{},
 		&models.ScenarioRun{},
+		&models.RunNodeStep{},
 		&models.Tag{},
 		&models.Proxy{},
 		&models.Agent{},
+		&models.AgentNonce{},
+		&models.OutboxEvent{},
+		&models.RunSummary{},
+		&models.RunRollup{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.APIKey{},
+		&models.RoleAssignment{},
+		&models.EnrollmentToken{},
+		&models.Secret{},
+		&models.EventLogEntry{},
+		&models.AuditEntry{},
+		&models.EventDeadLetter{},
+		&models.MessageDeadLetter{},
+		&models.SchemaMigration{},
 	)
+	persistence.ProtectAuditLog(context.Background(), database)
+	persistence.RecordSchemaVersion(context.Background(), database)
 }
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	command "parrotflow/internal/application/command/access"
+	"parrotflow/internal/domain/access"
+	"parrotflow/internal/infrastructure/events"
+	"parrotflow/internal/infrastructure/persistence"
+
+	"github.com/danielgtaylor/huma/v2/humacli"
+	"github.com/spf13/cobra"
+)
+
+// roleCommand manages role assignments straight in the database, so the first
+// admin can be named before anyone is allowed to use the API
+func roleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "role",
+		Short: "Assign, unassign and list roles of API keys and identity provider subjects",
+	}
+	cmd.AddCommand(roleAssignCommand(), roleUnassignCommand(), roleListCommand())
+	return cmd
+}
+
+func roleAssignCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "assign <subject> <role>...",
+		Short: "Replace the roles of a subject: apikey:<id> or the sub claim of a JWT",
+		Args:  cobra.MinimumNArgs(2),
+		Run: humacli.WithOptions(func(cmd *cobra.Command, args []string, options *Options) {
+			database := openDatabaseOrExit(options)
+			handler := command.NewAssignRolesCommandHandler(persistence.NewRoleAssignmentRepository(database), persistence.NewAPIKeyRepository(database), events.NewInMemoryEventBus())
+
+			a, err := handler.Handle(cmd.Context(), command.AssignRolesCommand{Subject: args[0], Roles: args[1:]})
+			exitOnError(err, "failed to assign roles")
+
+			fmt.Fprintf(os.Stderr, "Assigned %s the roles %s\n", a.Subject, formatRoles(a.Roles))
+		}),
+	}
+}
+
+func roleUnassignCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unassign <subject>",
+		Short: "Remove every role of a subject",
+		Args:  cobra.ExactArgs(1),
+		Run: humacli.WithOptions(func(cmd *cobra.Command, args []string, options *Options) {
+			handler := command.NewUnassignRolesCommandHandler(persistence.NewRoleAssignmentRepository(openDatabaseOrExit(options)), events.NewInMemoryEventBus())
+			exitOnError(handler.Handle(cmd.Context(), command.UnassignRolesCommand{Subject: args[0]}), "failed to unassign roles")
+
+			fmt.Fprintf(os.Stderr, "Unassigned the roles of %s\n", args[0])
+		}),
+	}
+}
+
+func roleListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List role assignments",
+		Args:  cobra.NoArgs,
+		Run: humacli.WithOptions(func(cmd *cobra.Command, args []string, options *Options) {
+			assignments, err := persistence.NewRoleAssignmentRepository(openDatabaseOrExit(options)).FindAll(cmd.Context())
+			exitOnError(err, "failed to list role assignments")
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "SUBJECT\tROLES\tUPDATED")
+			for _, a := range assignments {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", a.Subject, formatRoles(a.Roles), a.UpdatedAt.Time().Format(time.RFC3339))
+			}
+			_ = w.Flush()
+		}),
+	}
+}
+
+func formatRoles(roles []access.Role) string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.String()
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,89 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/access"
+	"parrotflow/internal/domain/apikey"
+	"parrotflow/internal/domain/shared"
+	utils "parrotflow/pkg/shared"
+)
+
+type AssignRolesCommand struct {
+	Subject string
+	Roles   []string
+}
+
+type AssignRolesCommandHandler struct {
+	repository access.Repository
+	keys       apikey.Repository
+	eventBus   shared.EventBus
+}
+
+func NewAssignRolesCommandHandler(repository access.Repository, keys apikey.Repository, eventBus shared.EventBus) *AssignRolesCommandHandler {
+	return &AssignRolesCommandHandler{
+		repository: repository,
+		keys:       keys,
+		eventBus:   eventBus,
+	}
+}
+
+// Handle replaces the roles of a subject, creating its assignment on first use
+// API key subjects must name an existing key; JWT subjects are not known in advance
+func (h *AssignRolesCommandHandler) Handle(ctx context.Context, cmd AssignRolesCommand) (*access.RoleAssignment, error) {
+	roles, err := access.NormalizeRoles(cmd.Roles)
+	if err != nil {
+		return nil, err
+	}
+	if keyID, ok := strings.CutPrefix(cmd.Subject, apikey.SubjectPrefix); ok {
+		if err := h.checkAPIKey(ctx, keyID); err != nil {
+			return nil, err
+		}
+	}
+
+	var a *access.RoleAssignment
+	err = command.RetryOnConflict(ctx, func() error {
+		var err error
+		a, err = h.repository.FindBySubject(ctx, cmd.Subject)
+		if errors.Is(err, access.ErrAssignmentNotFound) {
+			id, err := access.NewRoleAssignmentID(utils.CustomUUID())
+			if err != nil {
+				return err
+			}
+			a, err = access.NewRoleAssignment(id, cmd.Subject, roles)
+			if err != nil {
+				return err
+			}
+			return h.repository.Save(ctx, a)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := a.Assign(roles); err != nil {
+			return err
+		}
+		if len(a.Events) == 0 {
+			return nil
+		}
+		return h.repository.Save(ctx, a)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, a.Events, a)
+	return a, nil
+}
+
+func (h *AssignRolesCommandHandler) checkAPIKey(ctx context.Context, id string) error {
+	keyID, err := apikey.NewAPIKeyID(id)
+	if err != nil {
+		return err
+	}
+	_, err = h.keys.FindByID(ctx, keyID)
+	return err
+}
@@ -0,0 +1,41 @@
+package command
+
+import (
+	"context"
+
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/access"
+	"parrotflow/internal/domain/shared"
+)
+
+type UnassignRolesCommand struct {
+	Subject string
+}
+
+type UnassignRolesCommandHandler struct {
+	repository access.Repository
+	eventBus   shared.EventBus
+}
+
+func NewUnassignRolesCommandHandler(repository access.Repository, eventBus shared.EventBus) *UnassignRolesCommandHandler {
+	return &UnassignRolesCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *UnassignRolesCommandHandler) Handle(ctx context.Context, cmd UnassignRolesCommand) error {
+	a, err := h.repository.FindBySubject(ctx, cmd.Subject)
+	if err != nil {
+		return err
+	}
+
+	a.Unassign()
+
+	if err := h.repository.Delete(ctx, a); err != nil {
+		return err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, a.Events, a)
+	return nil
+}
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	command "parrotflow/internal/application/command"
 
 	"parrotflow/internal/domain/agent"
 	"parrotflow/internal/domain/shared"
@@ -27,29 +28,30 @@ func NewAssignRunCommandHandler(
 }
 
 func (h *AssignRunCommandHandler) Handle(ctx context.Context, cmd AssignRunCommand) (*agent.Agent, error) {
-	// Find agent
-	a, err := h.repository.FindByID(ctx, cmd.AgentID)
+	var a *agent.Agent
+	err := command.RetryOnConflict(ctx, func() error {
+		// Find agent
+		var err error
+		a, err = h.repository.FindByID(ctx, cmd.AgentID)
+		if err != nil {
+			return err
+		}
+		if a == nil {
+			return agent.ErrAgentNotFound
+		}
+
+		// Assign run
+		if err := a.AssignRun(); err != nil {
+			return err
+		}
+
+		// Save agent
+		return h.repository.Save(ctx, a)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if a == nil {
-		return nil, agent.ErrAgentNotFound
-	}
-
-	// Assign run
-	if err := a.AssignRun(); err != nil {
-		return nil, err
-	}
-
-	// Save agent
-	if err := h.repository.Save(ctx, a); err != nil {
-		return nil, err
-	}
-
-	// Publish events
-	for _, event := range a.Events {
-		h.eventBus.Publish(event)
-	}
 
+	command.PublishDomainEvents(ctx, h.eventBus, a.Events, a)
 	return a, nil
 }
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	command "parrotflow/internal/application/command"
 
 	"parrotflow/internal/domain/agent"
 	"parrotflow/internal/domain/shared"
@@ -27,27 +28,29 @@ func NewDeregisterAgentCommandHandler(
 }
 
 func (h *DeregisterAgentCommandHandler) Handle(ctx context.Context, cmd DeregisterAgentCommand) error {
-	// Find agent
-	a, err := h.repository.FindByID(ctx, cmd.AgentID)
+	var a *agent.Agent
+	err := command.RetryOnConflict(ctx, func() error {
+		// Find agent
+		var err error
+		a, err = h.repository.FindByID(ctx, cmd.AgentID)
+		if err != nil {
+			return err
+		}
+		if a == nil {
+			return agent.ErrAgentNotFound
+		}
+
+		// Deregister agent
+		a.Deregister()
+
+		// Save agent (marks as offline)
+		return h.repository.Save(ctx, a)
+	})
 	if err != nil {
 		return err
 	}
-	if a == nil {
-		return agent.ErrAgentNotFound
-	}
-
-	// Deregister agent
-	a.Deregister()
-
-	// Save agent (marks as offline)
-	if err := h.repository.Save(ctx, a); err != nil {
-		return err
-	}
 
-	// Publish events
-	for _, event := range a.Events {
-		h.eventBus.Publish(event)
-	}
+	command.PublishDomainEvents(ctx, h.eventBus, a.Events, a)
 
 	return nil
 }
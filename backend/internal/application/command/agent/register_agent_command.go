@@ -2,64 +2,106 @@ package agent
 
 import (
 	"context"
+	"errors"
+	command "parrotflow/internal/application/command"
+	"time"
 
 	"parrotflow/internal/domain/agent"
+	"parrotflow/internal/domain/enrollment"
 	"parrotflow/internal/domain/shared"
 	utils "parrotflow/pkg/shared"
 )
 
 type RegisterAgentCommand struct {
-	Name           string
-	Capabilities   agent.Capabilities
-	ConnectionInfo agent.ConnectionInfo
+	Name            string
+	Capabilities    agent.Capabilities
+	ConnectionInfo  agent.ConnectionInfo
+	EnrollmentToken string // One-time token minted by an admin, exchanged for the agent credential
 }
 
 type RegisterAgentCommandHandler struct {
 	repository agent.Repository
+	tokens     enrollment.Repository
 	eventBus   shared.EventBus
 }
 
 func NewRegisterAgentCommandHandler(
 	repository agent.Repository,
+	tokens enrollment.Repository,
 	eventBus shared.EventBus,
 ) *RegisterAgentCommandHandler {
 	return &RegisterAgentCommandHandler{
 		repository: repository,
+		tokens:     tokens,
 		eventBus:   eventBus,
 	}
 }
 
-func (h *RegisterAgentCommandHandler) Handle(ctx context.Context, cmd RegisterAgentCommand) (*agent.Agent, error) {
-	// Check if agent with the same name already exists
-	exists, err := h.repository.ExistsByName(ctx, cmd.Name)
+func (h *RegisterAgentCommandHandler) Handle(ctx context.Context, cmd RegisterAgentCommand) (*agent.EnrolledAgent, error) {
+	var a *agent.Agent
+	var token *enrollment.Token
+	var secret string
+	err := command.RetryOnConflict(ctx, func() error {
+		// Check the enrollment token before anything else, so unenrolled callers learn nothing
+		var err error
+		token, err = h.findToken(ctx, cmd.EnrollmentToken)
+		if err != nil {
+			return err
+		}
+
+		// Check if agent with the same name already exists
+		exists, err := h.repository.ExistsByName(ctx, cmd.Name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return agent.ErrAgentAlreadyExists
+		}
+
+		// Create agent ID
+		agentID, err := agent.NewAgentID(utils.CustomUUID())
+		if err != nil {
+			return err
+		}
+
+		// Create agent (auto-registration)
+		a, err = agent.NewAgent(agentID, cmd.Name, cmd.Capabilities, cmd.ConnectionInfo)
+		if err != nil {
+			return err
+		}
+		secret, err = a.IssueCredential()
+		if err != nil {
+			return err
+		}
+
+		// Save the agent and redeem the token together; if another registration won the
+		// race, neither is stored and the retry finds the token used
+		return h.repository.Enroll(ctx, a, token)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, agent.ErrAgentAlreadyExists
-	}
 
-	// Create agent ID
-	agentID, err := agent.NewAgentID(utils.CustomUUID())
+	command.PublishDomainEvents(ctx, h.eventBus, a.Events, a)
+	command.PublishDomainEvents(ctx, h.eventBus, token.Events, token)
+	return &agent.EnrolledAgent{Agent: a, Secret: secret}, nil
+}
+
+// findToken looks up a presented token and checks it can still be redeemed
+func (h *RegisterAgentCommandHandler) findToken(ctx context.Context, plaintext string) (*enrollment.Token, error) {
+	prefix, err := enrollment.ParsePrefix(plaintext)
 	if err != nil {
 		return nil, err
 	}
-
-	// Create agent (auto-registration)
-	a, err := agent.NewAgent(agentID, cmd.Name, cmd.Capabilities, cmd.ConnectionInfo)
+	token, err := h.tokens.FindByPrefix(ctx, prefix)
+	if errors.Is(err, enrollment.ErrTokenNotFound) {
+		return nil, enrollment.ErrInvalidToken
+	}
 	if err != nil {
 		return nil, err
 	}
-
-	// Save agent
-	if err := h.repository.Save(ctx, a); err != nil {
+	if err := token.Verify(plaintext, time.Now()); err != nil {
 		return nil, err
 	}
-
-	// Publish events
-	for _, event := range a.Events {
-		h.eventBus.Publish(event)
-	}
-
-	return a, nil
+	return token, nil
 }
@@ -0,0 +1,214 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"parrotflow/internal/domain/agent"
+	"parrotflow/internal/domain/enrollment"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/encryption"
+	"parrotflow/internal/infrastructure/persistence"
+	"parrotflow/internal/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordingEventBus keeps published events
+type recordingEventBus struct {
+	published []shared.DomainEvent
+}
+
+func (b *recordingEventBus) Publish(event shared.DomainEvent) error {
+	b.published = append(b.published, event)
+	return nil
+}
+
+func (b *recordingEventBus) Subscribe(handler shared.EventHandler) error {
+	return nil
+}
+
+type enrollmentFixture struct {
+	db       *gorm.DB
+	agents   *persistence.AgentRepository
+	tokens   *persistence.EnrollmentTokenRepository
+	register *RegisterAgentCommandHandler
+	beat     *UpdateHeartbeatCommandHandler
+	revoke   *RevokeAgentCommandHandler
+}
+
+func newEnrollmentFixture(t *testing.T) *enrollmentFixture {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	// Every connection to :memory: opens its own empty database
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.Tag{}, &models.Agent{}, &models.AgentNonce{}, &models.EnrollmentToken{}, &models.OutboxEvent{}, &models.EventLogEntry{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+
+	entry, _ := encryption.GenerateKey("k1")
+	keyring, err := encryption.NewKeyring(encryption.Config{Keys: entry})
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+
+	bus := &recordingEventBus{}
+	agents := persistence.NewAgentRepository(db, keyring)
+	tokens := persistence.NewEnrollmentTokenRepository(db)
+	return &enrollmentFixture{
+		db:       db,
+		agents:   agents,
+		tokens:   tokens,
+		register: NewRegisterAgentCommandHandler(agents, tokens, bus),
+		beat:     NewUpdateHeartbeatCommandHandler(agents, bus),
+		revoke:   NewRevokeAgentCommandHandler(agents, bus),
+	}
+}
+
+func (f *enrollmentFixture) mintToken(t *testing.T) string {
+	t.Helper()
+	id, _ := enrollment.NewTokenID("0")
+	issued, err := enrollment.Issue(id, "worker host", nil)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if err := f.tokens.Save(context.Background(), issued.Token); err != nil {
+		t.Fatalf("Save(token) error = %v", err)
+	}
+	return issued.Plaintext
+}
+
+func registerCommand(t *testing.T, name, token string) RegisterAgentCommand {
+	t.Helper()
+	browser, _ := agent.NewBrowserCapability(agent.BrowserChromium, "120", true)
+	osInfo, _ := agent.NewOSInfo(agent.PlatformLinux, agent.ArchAMD64, "6.1")
+	limits, _ := agent.NewResourceLimits(2, 1024, 2)
+	capabilities, err := agent.NewCapabilities([]agent.BrowserCapability{browser}, osInfo, agent.NewProxyCapability(false, nil), limits, nil)
+	if err != nil {
+		t.Fatalf("NewCapabilities() error = %v", err)
+	}
+	connectionInfo, _ := agent.NewConnectionInfo("10.0.0.2", name, "agent."+name)
+	return RegisterAgentCommand{Name: name, Capabilities: capabilities, ConnectionInfo: connectionInfo, EnrollmentToken: token}
+}
+
+func signedHeartbeat(id agent.AgentID, secret, nonce string, at time.Time) UpdateHeartbeatCommand {
+	body := []byte(`{}`)
+	return UpdateHeartbeatCommand{
+		AgentID: id,
+		Signature: agent.Signature{
+			Timestamp: strconv.FormatInt(at.Unix(), 10),
+			Nonce:     nonce,
+			Value:     agent.Sign(secret, at.Unix(), nonce, agent.PurposeHeartbeat, body),
+		},
+		Body: body,
+	}
+}
+
+func TestRegisterAgent_ExchangesTokenForCredentialOnce(t *testing.T) {
+	ctx := context.Background()
+	f := newEnrollmentFixture(t)
+	token := f.mintToken(t)
+
+	enrolled, err := f.register.Handle(ctx, registerCommand(t, "worker-1", token))
+	if err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	if enrolled.Secret == "" {
+		t.Fatal("Handle() returned no credential")
+	}
+	var stored models.Agent
+	f.db.First(&stored)
+	if stored.CredentialSecret == enrolled.Secret || stored.CredentialKeyID != "k1" {
+		t.Errorf("Stored credential = %q with key %q, want it encrypted with k1", stored.CredentialSecret, stored.CredentialKeyID)
+	}
+
+	prefix, _ := enrollment.ParsePrefix(token)
+	redeemed, err := f.tokens.FindByPrefix(ctx, prefix)
+	if err != nil {
+		t.Fatalf("FindByPrefix() error = %v", err)
+	}
+	if redeemed.UsedAt == nil || redeemed.AgentID != enrolled.Agent.Id.String() {
+		t.Errorf("token used_at = %v, agent_id = %q, want it redeemed by agent %s", redeemed.UsedAt, redeemed.AgentID, enrolled.Agent.Id)
+	}
+
+	if _, err := f.register.Handle(ctx, registerCommand(t, "worker-2", token)); !errors.Is(err, enrollment.ErrTokenUsed) {
+		t.Errorf("second Handle() error = %v, want %v", err, enrollment.ErrTokenUsed)
+	}
+	if _, err := f.register.Handle(ctx, registerCommand(t, "worker-3", "pfe_unknown_token")); !errors.Is(err, enrollment.ErrInvalidToken) {
+		t.Errorf("Handle() with unknown token error = %v, want %v", err, enrollment.ErrInvalidToken)
+	}
+}
+
+func TestRegisterAgent_StoresNothingWhenTokenIsRedeemedConcurrently(t *testing.T) {
+	ctx := context.Background()
+	f := newEnrollmentFixture(t)
+	plaintext := f.mintToken(t)
+	prefix, _ := enrollment.ParsePrefix(plaintext)
+	stale, err := f.tokens.FindByPrefix(ctx, prefix)
+	if err != nil {
+		t.Fatalf("FindByPrefix() error = %v", err)
+	}
+
+	// Another registration redeems the token after this one read it
+	if _, err := f.register.Handle(ctx, registerCommand(t, "worker-1", plaintext)); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	cmd := registerCommand(t, "worker-2", plaintext)
+	id, _ := agent.NewAgentID("0")
+	late, _ := agent.NewAgent(id, cmd.Name, cmd.Capabilities, cmd.ConnectionInfo)
+	if err := f.agents.Enroll(ctx, late, stale); !errors.Is(err, shared.ErrConcurrentModification) {
+		t.Fatalf("Enroll() error = %v, want %v", err, shared.ErrConcurrentModification)
+	}
+	if exists, _ := f.agents.ExistsByName(ctx, "worker-2"); exists {
+		t.Error("Agent of the failed enrollment was stored")
+	}
+}
+
+func TestUpdateHeartbeat_VerifiesSignatureUntilRevoked(t *testing.T) {
+	ctx := context.Background()
+	f := newEnrollmentFixture(t)
+	enrolled, err := f.register.Handle(ctx, registerCommand(t, "worker-1", f.mintToken(t)))
+	if err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	id := enrolled.Agent.Id
+	now := time.Now()
+
+	if _, err := f.beat.Handle(ctx, signedHeartbeat(id, enrolled.Secret, "n1", now)); err != nil {
+		t.Fatalf("signed heartbeat error = %v", err)
+	}
+	if _, err := f.beat.Handle(ctx, signedHeartbeat(id, enrolled.Secret, "n1", now)); !errors.Is(err, agent.ErrReplayedSignature) {
+		t.Errorf("replayed heartbeat error = %v, want %v", err, agent.ErrReplayedSignature)
+	}
+	if _, err := f.beat.Handle(ctx, signedHeartbeat(id, "not-the-credential", "n2", now)); !errors.Is(err, agent.ErrInvalidSignature) {
+		t.Errorf("heartbeat with wrong secret error = %v, want %v", err, agent.ErrInvalidSignature)
+	}
+	unknown, _ := agent.NewAgentID("999")
+	if _, err := f.beat.Handle(ctx, signedHeartbeat(unknown, enrolled.Secret, "n3", now)); !errors.Is(err, agent.ErrInvalidSignature) {
+		t.Errorf("heartbeat of unknown agent error = %v, want %v", err, agent.ErrInvalidSignature)
+	}
+	if _, err := f.beat.Handle(ctx, signedHeartbeat(id, enrolled.Secret, "n4", now.Add(-time.Hour))); !errors.Is(err, agent.ErrStaleSignature) {
+		t.Errorf("stale heartbeat error = %v, want %v", err, agent.ErrStaleSignature)
+	}
+
+	revoked, err := f.revoke.Handle(ctx, RevokeAgentCommand{AgentID: id})
+	if err != nil {
+		t.Fatalf("revoke error = %v", err)
+	}
+	if revoked.Status.String() != agent.AgentStatusOffline.String() {
+		t.Errorf("revoked agent status = %s, want offline", revoked.Status)
+	}
+	if _, err := f.beat.Handle(ctx, signedHeartbeat(id, enrolled.Secret, "n5", now)); !errors.Is(err, agent.ErrAgentRevoked) {
+		t.Errorf("heartbeat after revoke error = %v, want %v", err, agent.ErrAgentRevoked)
+	}
+}
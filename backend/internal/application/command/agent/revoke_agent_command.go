@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+
+	"parrotflow/internal/domain/agent"
+	"parrotflow/internal/domain/shared"
+)
+
+type RevokeAgentCommand struct {
+	AgentID agent.AgentID
+}
+
+type RevokeAgentCommandHandler struct {
+	repository agent.Repository
+	eventBus   shared.EventBus
+}
+
+func NewRevokeAgentCommandHandler(
+	repository agent.Repository,
+	eventBus shared.EventBus,
+) *RevokeAgentCommandHandler {
+	return &RevokeAgentCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+// Handle invalidates the agent credential and deregisters it; the agent has to
+// enroll again with a new token to come back
+func (h *RevokeAgentCommandHandler) Handle(ctx context.Context, cmd RevokeAgentCommand) (*agent.Agent, error) {
+	var a *agent.Agent
+	err := command.RetryOnConflict(ctx, func() error {
+		var err error
+		a, err = h.repository.FindByID(ctx, cmd.AgentID)
+		if err != nil {
+			return err
+		}
+		if a.IsRevoked() {
+			return nil
+		}
+
+		if err := a.Revoke(); err != nil {
+			return err
+		}
+		return h.repository.Save(ctx, a)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, a.Events, a)
+	return a, nil
+}
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	command "parrotflow/internal/application/command"
 
 	"parrotflow/internal/domain/agent"
 	"parrotflow/internal/domain/shared"
@@ -14,6 +15,9 @@ type UpdateAgentCommand struct {
 	Capabilities *agent.Capabilities
 	TagsToAdd    []tag.TagID
 	TagsToRemove []tag.TagID
+
+	// ExpectedVersion is the version the client last saw (If-Match), nil to skip the check
+	ExpectedVersion *uint64
 }
 
 type UpdateAgentCommandHandler struct {
@@ -32,49 +36,56 @@ func NewUpdateAgentCommandHandler(
 }
 
 func (h *UpdateAgentCommandHandler) Handle(ctx context.Context, cmd UpdateAgentCommand) (*agent.Agent, error) {
-	// Find agent
-	a, err := h.repository.FindByID(ctx, cmd.AgentID)
-	if err != nil {
-		return nil, err
-	}
-	if a == nil {
-		return nil, agent.ErrAgentNotFound
-	}
+	var a *agent.Agent
+	err := command.RetryOnConflict(ctx, func() error {
+		// Find agent
+		var err error
+		a, err = h.repository.FindByID(ctx, cmd.AgentID)
+		if err != nil {
+			return err
+		}
+		if a == nil {
+			return agent.ErrAgentNotFound
+		}
 
-	// Update name if provided
-	if cmd.Name != nil {
-		if err := a.UpdateName(*cmd.Name); err != nil {
-			return nil, err
+		// Honour the caller's If-Match precondition
+		if err := shared.CheckVersion("agent", a.Id.String(), a.Version, cmd.ExpectedVersion); err != nil {
+			return err
 		}
-	}
 
-	// Update capabilities if provided
-	if cmd.Capabilities != nil {
-		a.UpdateCapabilities(*cmd.Capabilities)
-	}
+		// Update name if provided
+		if cmd.Name != nil {
+			if err := a.UpdateName(*cmd.Name); err != nil {
+				return err
+			}
+		}
 
-	// Add tags
-	for _, tagID := range cmd.TagsToAdd {
-		if err := a.AddTag(tagID); err != nil {
-			// Ignore if tag already exists
-			continue
+		// Update capabilities if provided
+		if cmd.Capabilities != nil {
+			a.UpdateCapabilities(*cmd.Capabilities)
 		}
-	}
 
-	// Remove tags
-	for _, tagID := range cmd.TagsToRemove {
-		a.RemoveTag(tagID)
-	}
+		// Add tags
+		for _, tagID := range cmd.TagsToAdd {
+			if err := a.AddTag(tagID); err != nil {
+				// Ignore if tag already exists
+				continue
+			}
+		}
 
-	// Save agent
-	if err := h.repository.Save(ctx, a); err != nil {
+		// Remove tags
+		for _, tagID := range cmd.TagsToRemove {
+			a.RemoveTag(tagID)
+		}
+
+		// Save agent
+		return h.repository.Save(ctx, a)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Publish events
-	for _, event := range a.Events {
-		h.eventBus.Publish(event)
-	}
+	command.PublishDomainEvents(ctx, h.eventBus, a.Events, a)
 
 	return a, nil
 }
@@ -2,13 +2,18 @@ package agent
 
 import (
 	"context"
+	"errors"
+	command "parrotflow/internal/application/command"
+	"time"
 
 	"parrotflow/internal/domain/agent"
 	"parrotflow/internal/domain/shared"
 )
 
 type UpdateHeartbeatCommand struct {
-	AgentID agent.AgentID
+	AgentID   agent.AgentID
+	Signature agent.Signature // Made with the agent credential over the request body
+	Body      []byte
 }
 
 type UpdateHeartbeatCommandHandler struct {
@@ -27,27 +32,41 @@ func NewUpdateHeartbeatCommandHandler(
 }
 
 func (h *UpdateHeartbeatCommandHandler) Handle(ctx context.Context, cmd UpdateHeartbeatCommand) (*agent.Agent, error) {
-	// Find agent
+	// Verify the heartbeat once, its nonce cannot be used again by a retry
 	a, err := h.repository.FindByID(ctx, cmd.AgentID)
+	if errors.Is(err, agent.ErrAgentNotFound) || (err == nil && a == nil) {
+		// Unknown agents fail like bad signatures, without confirming which IDs exist
+		return nil, agent.ErrInvalidSignature
+	}
 	if err != nil {
 		return nil, err
 	}
-	if a == nil {
-		return nil, agent.ErrAgentNotFound
+	now := time.Now()
+	if err := a.VerifySignature(agent.PurposeHeartbeat, cmd.Signature, cmd.Body, now); err != nil {
+		return nil, err
 	}
-
-	// Update heartbeat
-	a.UpdateHeartbeat()
-
-	// Save agent
-	if err := h.repository.Save(ctx, a); err != nil {
+	if err := h.repository.UseNonce(ctx, a.Id, cmd.Signature.Nonce, now); err != nil {
 		return nil, err
 	}
 
-	// Publish events
-	for _, event := range a.Events {
-		h.eventBus.Publish(event)
+	err = command.RetryOnConflict(ctx, func() error {
+		// Find agent
+		var err error
+		a, err = h.repository.FindByID(ctx, cmd.AgentID)
+		if err != nil {
+			return err
+		}
+
+		// Update heartbeat
+		a.UpdateHeartbeat()
+
+		// Save agent
+		return h.repository.Save(ctx, a)
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	command.PublishDomainEvents(ctx, h.eventBus, a.Events, a)
 	return a, nil
 }
@@ -0,0 +1,48 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/apikey"
+	"parrotflow/internal/domain/shared"
+	utils "parrotflow/pkg/shared"
+	"time"
+)
+
+type CreateAPIKeyCommand struct {
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time // Never expires when nil
+}
+
+type CreateAPIKeyCommandHandler struct {
+	repository apikey.Repository
+	eventBus   shared.EventBus
+}
+
+func NewCreateAPIKeyCommandHandler(repository apikey.Repository, eventBus shared.EventBus) *CreateAPIKeyCommandHandler {
+	return &CreateAPIKeyCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *CreateAPIKeyCommandHandler) Handle(ctx context.Context, cmd CreateAPIKeyCommand) (*apikey.IssuedKey, error) {
+	keyID, err := apikey.NewAPIKeyID(utils.CustomUUID())
+	if err != nil {
+		return nil, err
+	}
+
+	issued, err := apikey.Issue(keyID, cmd.Name, cmd.Scopes, cmd.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	k := issued.APIKey
+	if err := h.repository.Save(ctx, k); err != nil {
+		return nil, err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, k.Events, k)
+	return issued, nil
+}
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/apikey"
+	"parrotflow/internal/domain/shared"
+)
+
+type RevokeAPIKeyCommand struct {
+	ID apikey.APIKeyID
+}
+
+type RevokeAPIKeyCommandHandler struct {
+	repository apikey.Repository
+	eventBus   shared.EventBus
+}
+
+func NewRevokeAPIKeyCommandHandler(repository apikey.Repository, eventBus shared.EventBus) *RevokeAPIKeyCommandHandler {
+	return &RevokeAPIKeyCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *RevokeAPIKeyCommandHandler) Handle(ctx context.Context, cmd RevokeAPIKeyCommand) (*apikey.APIKey, error) {
+	var k *apikey.APIKey
+	err := command.RetryOnConflict(ctx, func() error {
+		var err error
+		k, err = h.repository.FindByID(ctx, cmd.ID)
+		if err != nil {
+			return err
+		}
+		if k.RevokedAt != nil {
+			return nil
+		}
+
+		k.Revoke()
+		return h.repository.Save(ctx, k)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, k.Events, k)
+	return k, nil
+}
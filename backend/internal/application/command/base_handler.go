@@ -1,7 +1,11 @@
 package command
 
 import (
+	"context"
+	"errors"
+	"log/slog"
 	"parrotflow/internal/domain/shared"
+	"time"
 )
 
 // EventCarrier is a minimal interface for entities that carry domain events
@@ -12,15 +16,48 @@ type EventCarrier interface {
 
 // PublishDomainEvents publishes all domain events and clears them from the entity
 // This eliminates the boilerplate event publishing code repeated in every command handler
+// Events are tied to the correlation of ctx, like the copies the repository recorded
 //
 // Usage in command handlers:
-//   command.PublishDomainEvents(h.eventBus, entity.Events, entity)
-func PublishDomainEvents(eventBus shared.EventBus, events []shared.DomainEvent, carrier EventCarrier) {
+//   command.PublishDomainEvents(ctx, h.eventBus, entity.Events, entity)
+func PublishDomainEvents(ctx context.Context, eventBus shared.EventBus, events []shared.DomainEvent, carrier EventCarrier) {
 	for _, event := range events {
-		if err := eventBus.Publish(event); err != nil {
-			// TODO: Add proper logging instead of silently ignoring
-			// For now, maintain backward compatibility
+		// Events saved with their aggregate are already in the outbox and still get relayed
+		if err := eventBus.Publish(shared.Correlate(ctx, event)); err != nil {
+			slog.WarnContext(ctx, "Error publishing event", "event_id", event.EventID(), "event_type", event.EventType(), "error", err)
 		}
 	}
 	carrier.ClearEvents()
 }
+
+// DefaultConflictRetries is how many times a load-modify-save cycle is attempted
+// before a concurrent modification is reported to the caller
+const DefaultConflictRetries = 3
+
+// RetryOnConflict runs fn and re-runs it while it fails with shared.ErrConcurrentModification
+// fn must reload the aggregate on every attempt, otherwise it will conflict again
+// A failed If-Match check (shared.ErrStaleVersion) is returned at once, it cannot succeed
+//
+// Usage in command handlers:
+//   err := command.RetryOnConflict(ctx, func() error {
+//       entity, err := h.repository.FindByID(ctx, cmd.ID)
+//       ...
+//       return h.repository.Save(ctx, entity)
+//   })
+func RetryOnConflict(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 1; attempt <= DefaultConflictRetries; attempt++ {
+		err = fn()
+		if err == nil || !errors.Is(err, shared.ErrConcurrentModification) || errors.Is(err, shared.ErrStaleVersion) {
+			return err
+		}
+
+		// Back off a little so the competing writer can finish
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt*attempt) * 5 * time.Millisecond):
+		}
+	}
+	return err
+}
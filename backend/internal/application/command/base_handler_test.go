@@ -1,6 +1,7 @@
 package command
 
 import (
+	"context"
 	"errors"
 	"parrotflow/internal/domain/shared"
 	"testing"
@@ -52,7 +53,7 @@ func TestPublishDomainEvents(t *testing.T) {
 		},
 	}
 
-	PublishDomainEvents(mockBus, entity.Events, entity)
+	PublishDomainEvents(context.Background(), mockBus, entity.Events, entity)
 
 	// Verify all events were published
 	if len(mockBus.publishedEvents) != 3 {
@@ -72,7 +73,7 @@ func TestPublishDomainEvents_EmptyEvents(t *testing.T) {
 		Events: []shared.DomainEvent{},
 	}
 
-	PublishDomainEvents(mockBus, entity.Events, entity)
+	PublishDomainEvents(context.Background(), mockBus, entity.Events, entity)
 
 	// Verify no events published
 	if len(mockBus.publishedEvents) != 0 {
@@ -97,10 +98,79 @@ func TestPublishDomainEvents_PublishErrorHandled(t *testing.T) {
 	}
 
 	// Should not panic
-	PublishDomainEvents(mockBus, entity.Events, entity)
+	PublishDomainEvents(context.Background(), mockBus, entity.Events, entity)
 
 	// Events should still be cleared despite error
 	if !entity.eventCleared {
 		t.Error("Events should be cleared even when publish fails")
 	}
 }
+
+func TestRetryOnConflict_RetriesUntilSuccess(t *testing.T) {
+	// Test case: Conflicts are retried and the eventual success is returned
+	attempts := 0
+	err := RetryOnConflict(context.Background(), func() error {
+		attempts++
+		if attempts < 2 {
+			return shared.NewConcurrentModificationError("agent", "1", 3)
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("RetryOnConflict() error = %v, want nil", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryOnConflict_GivesUp(t *testing.T) {
+	// Test case: Persistent conflicts are reported after the retry budget
+	attempts := 0
+	err := RetryOnConflict(context.Background(), func() error {
+		attempts++
+		return shared.NewConcurrentModificationError("agent", "1", 3)
+	})
+
+	if !errors.Is(err, shared.ErrConcurrentModification) {
+		t.Errorf("RetryOnConflict() error = %v, want ErrConcurrentModification", err)
+	}
+	if attempts != DefaultConflictRetries {
+		t.Errorf("Expected %d attempts, got %d", DefaultConflictRetries, attempts)
+	}
+}
+
+func TestRetryOnConflict_OtherErrorsNotRetried(t *testing.T) {
+	// Test case: Non-conflict errors are returned immediately
+	attempts := 0
+	expectedErr := errors.New("database connection lost")
+	err := RetryOnConflict(context.Background(), func() error {
+		attempts++
+		return expectedErr
+	})
+
+	if err != expectedErr {
+		t.Errorf("RetryOnConflict() error = %v, want %v", err, expectedErr)
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestRetryOnConflict_StaleVersionNotRetried(t *testing.T) {
+	// Test case: A failed If-Match check is reported without retrying
+	attempts := 0
+	expected := uint64(2)
+	err := RetryOnConflict(context.Background(), func() error {
+		attempts++
+		return shared.CheckVersion("tag", "1", 3, &expected)
+	})
+
+	if !errors.Is(err, shared.ErrConcurrentModification) || !errors.Is(err, shared.ErrStaleVersion) {
+		t.Errorf("RetryOnConflict() error = %v, want a stale concurrent modification", err)
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+}
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/deadletter"
+	"parrotflow/internal/domain/shared"
+)
+
+// DiscardDeadLetterCommand drops a dead-lettered message for good
+type DiscardDeadLetterCommand struct {
+	ID deadletter.DeadLetterID
+}
+
+type DiscardDeadLetterCommandHandler struct {
+	repository deadletter.Repository
+	eventBus   shared.EventBus
+}
+
+func NewDiscardDeadLetterCommandHandler(repository deadletter.Repository, eventBus shared.EventBus) *DiscardDeadLetterCommandHandler {
+	return &DiscardDeadLetterCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *DiscardDeadLetterCommandHandler) Handle(ctx context.Context, cmd DiscardDeadLetterCommand) (*deadletter.DeadLetter, error) {
+	var d *deadletter.DeadLetter
+	err := command.RetryOnConflict(ctx, func() error {
+		var err error
+		d, err = h.repository.FindByID(ctx, cmd.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := d.Discard(); err != nil {
+			return err
+		}
+		return h.repository.Save(ctx, d)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, d.Events, d)
+	return d, nil
+}
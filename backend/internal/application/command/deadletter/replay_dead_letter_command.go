@@ -0,0 +1,80 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/deadletter"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/ports"
+)
+
+// HeaderReplayedFrom marks replayed messages with the dead letter they came from
+const HeaderReplayedFrom = "x-parrotflow-replayed-from"
+
+// ReplayDeadLetterCommand publishes a dead-lettered message to its original queue again
+type ReplayDeadLetterCommand struct {
+	ID deadletter.DeadLetterID
+}
+
+type ReplayDeadLetterCommandHandler struct {
+	repository deadletter.Repository
+	broker     ports.DeadLetterBroker
+	eventBus   shared.EventBus
+}
+
+func NewReplayDeadLetterCommandHandler(repository deadletter.Repository, broker ports.DeadLetterBroker, eventBus shared.EventBus) *ReplayDeadLetterCommandHandler {
+	return &ReplayDeadLetterCommandHandler{
+		repository: repository,
+		broker:     broker,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *ReplayDeadLetterCommandHandler) Handle(ctx context.Context, cmd ReplayDeadLetterCommand) (*deadletter.DeadLetter, error) {
+	var d *deadletter.DeadLetter
+	published := false
+	err := command.RetryOnConflict(ctx, func() error {
+		var err error
+		d, err = h.repository.FindByID(ctx, cmd.ID)
+		if err != nil {
+			return err
+		}
+		if d.Status != deadletter.StatusPending {
+			return deadletter.ErrAlreadyResolved
+		}
+
+		// Publish once, a conflicting save only needs the status recorded again
+		if !published {
+			if err := h.broker.Publish(ctx, d.Queue, replayMessage(d)); err != nil {
+				return err
+			}
+			published = true
+		}
+
+		if err := d.MarkReplayed(); err != nil {
+			return err
+		}
+		return h.repository.Save(ctx, d)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, d.Events, d)
+	return d, nil
+}
+
+func replayMessage(d *deadletter.DeadLetter) ports.Message {
+	headers := make(map[string]string, len(d.Headers)+1)
+	for key, value := range d.Headers {
+		headers[key] = value
+	}
+	headers[HeaderReplayedFrom] = d.Id.String()
+
+	return ports.Message{
+		ID:          d.MessageID,
+		ContentType: d.ContentType,
+		Headers:     headers,
+		Body:        d.Payload,
+	}
+}
@@ -0,0 +1,136 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"parrotflow/internal/domain/deadletter"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/messaging"
+	"parrotflow/internal/infrastructure/messaging/memory"
+	"parrotflow/internal/ports"
+)
+
+// memoryRepository keeps dead letters in a map
+type memoryRepository struct {
+	mu          sync.Mutex
+	deadLetters map[string]*deadletter.DeadLetter
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{deadLetters: make(map[string]*deadletter.DeadLetter)}
+}
+
+func (r *memoryRepository) Save(ctx context.Context, d *deadletter.DeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deadLetters[d.Id.String()] = d
+	return nil
+}
+
+func (r *memoryRepository) FindByID(ctx context.Context, id deadletter.DeadLetterID) (*deadletter.DeadLetter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.deadLetters[id.String()]
+	if !ok {
+		return nil, deadletter.ErrDeadLetterNotFound
+	}
+	return d, nil
+}
+
+func (r *memoryRepository) Find(ctx context.Context, criteria deadletter.Criteria) (shared.Page[*deadletter.DeadLetter], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var items []*deadletter.DeadLetter
+	for _, d := range r.deadLetters {
+		items = append(items, d)
+	}
+	return shared.Page[*deadletter.DeadLetter]{Items: items, Total: int64(len(items))}, nil
+}
+
+// recordingEventBus keeps published events
+type recordingEventBus struct {
+	published []shared.DomainEvent
+}
+
+func (b *recordingEventBus) Publish(event shared.DomainEvent) error {
+	b.published = append(b.published, event)
+	return nil
+}
+
+func (b *recordingEventBus) Subscribe(handler shared.EventHandler) error {
+	return nil
+}
+
+func TestReplayDeadLetter_RepublishesCollectedMessage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	broker := memory.NewBroker()
+	repository := newMemoryRepository()
+	collector := messaging.NewDeadLetterCollector(broker, repository, []string{messaging.AgentRequestQueue})
+	go collector.Run(ctx)
+
+	// The collector declares the queue too, declaring it here avoids racing it
+	if err := broker.AssertQueue(ctx, messaging.AgentRequestQueue); err != nil {
+		t.Fatalf("AssertQueue() error = %v", err)
+	}
+	message := ports.Message{
+		ID:          "request-1",
+		ContentType: "application/json",
+		Headers:     map[string]string{"x-source": "test"},
+		Body:        []byte(`{"run_id":"42"}`),
+	}
+	broker.Reject(messaging.AgentRequestQueue, message, "rejected")
+
+	var collected *deadletter.DeadLetter
+	deadline := time.Now().Add(2 * time.Second)
+	for collected == nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		page, _ := repository.Find(ctx, deadletter.Criteria{})
+		if len(page.Items) > 0 {
+			collected = page.Items[0]
+		}
+	}
+	if collected == nil {
+		t.Fatal("Rejected message was not collected")
+	}
+	if collected.Queue != messaging.AgentRequestQueue || collected.MessageID != "request-1" || collected.Reason != "rejected" {
+		t.Fatalf("Collected %+v", collected)
+	}
+
+	eventBus := &recordingEventBus{}
+	handler := NewReplayDeadLetterCommandHandler(repository, broker, eventBus)
+	replayed, err := handler.Handle(ctx, ReplayDeadLetterCommand{ID: collected.Id})
+	if err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	if replayed.Status != deadletter.StatusReplayed {
+		t.Errorf("Status = %s, want %s", replayed.Status, deadletter.StatusReplayed)
+	}
+
+	queued := broker.Messages(messaging.AgentRequestQueue)
+	if len(queued) != 1 {
+		t.Fatalf("Queue holds %d messages, want 1", len(queued))
+	}
+	if string(queued[0].Body) != `{"run_id":"42"}` || queued[0].ID != "request-1" {
+		t.Errorf("Replayed message = %+v", queued[0])
+	}
+	if queued[0].Headers[HeaderReplayedFrom] != collected.Id.String() || queued[0].Headers["x-source"] != "test" {
+		t.Errorf("Replayed headers = %v", queued[0].Headers)
+	}
+	if len(eventBus.published) != 1 || eventBus.published[0].EventType() != deadletter.EventDeadLetterReplayed {
+		t.Errorf("Published events = %v", eventBus.published)
+	}
+
+	// A dead letter is replayed at most once
+	if _, err := handler.Handle(ctx, ReplayDeadLetterCommand{ID: collected.Id}); !errors.Is(err, deadletter.ErrAlreadyResolved) {
+		t.Errorf("Second Handle() error = %v, want ErrAlreadyResolved", err)
+	}
+	if len(broker.Messages(messaging.AgentRequestQueue)) != 1 {
+		t.Error("Second replay published the message again")
+	}
+}
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/enrollment"
+	"parrotflow/internal/domain/shared"
+	utils "parrotflow/pkg/shared"
+	"time"
+)
+
+type CreateEnrollmentTokenCommand struct {
+	Name      string
+	ExpiresAt *time.Time // enrollment.DefaultTTL from now when nil
+}
+
+type CreateEnrollmentTokenCommandHandler struct {
+	repository enrollment.Repository
+	eventBus   shared.EventBus
+}
+
+func NewCreateEnrollmentTokenCommandHandler(repository enrollment.Repository, eventBus shared.EventBus) *CreateEnrollmentTokenCommandHandler {
+	return &CreateEnrollmentTokenCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *CreateEnrollmentTokenCommandHandler) Handle(ctx context.Context, cmd CreateEnrollmentTokenCommand) (*enrollment.IssuedToken, error) {
+	tokenID, err := enrollment.NewTokenID(utils.CustomUUID())
+	if err != nil {
+		return nil, err
+	}
+
+	issued, err := enrollment.Issue(tokenID, cmd.Name, cmd.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	t := issued.Token
+	if err := h.repository.Save(ctx, t); err != nil {
+		return nil, err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, t.Events, t)
+	return issued, nil
+}
@@ -0,0 +1,52 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/enrollment"
+	"parrotflow/internal/domain/shared"
+)
+
+type RevokeEnrollmentTokenCommand struct {
+	ID enrollment.TokenID
+}
+
+type RevokeEnrollmentTokenCommandHandler struct {
+	repository enrollment.Repository
+	eventBus   shared.EventBus
+}
+
+func NewRevokeEnrollmentTokenCommandHandler(repository enrollment.Repository, eventBus shared.EventBus) *RevokeEnrollmentTokenCommandHandler {
+	return &RevokeEnrollmentTokenCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+// Handle revokes an unused token; used tokens are left alone since revoking
+// the agent that redeemed them is what cuts its access
+func (h *RevokeEnrollmentTokenCommandHandler) Handle(ctx context.Context, cmd RevokeEnrollmentTokenCommand) (*enrollment.Token, error) {
+	var t *enrollment.Token
+	err := command.RetryOnConflict(ctx, func() error {
+		var err error
+		t, err = h.repository.FindByID(ctx, cmd.ID)
+		if err != nil {
+			return err
+		}
+		if t.UsedAt != nil {
+			return enrollment.ErrTokenUsed
+		}
+		if t.RevokedAt != nil {
+			return nil
+		}
+
+		t.Revoke()
+		return h.repository.Save(ctx, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, t.Events, t)
+	return t, nil
+}
@@ -2,6 +2,7 @@ package proxy
 
 import (
 	"context"
+	command "parrotflow/internal/application/command"
 
 	"parrotflow/internal/domain/proxy"
 	"parrotflow/internal/domain/shared"
@@ -87,10 +88,7 @@ func (h *CreateProxyCommandHandler) Handle(ctx context.Context, cmd CreateProxyC
 		return nil, err
 	}
 
-	// Publish domain events
-	for _, event := range p.Events {
-		h.eventBus.Publish(event)
-	}
+	command.PublishDomainEvents(ctx, h.eventBus, p.Events, p)
 
 	return p, nil
 }
@@ -2,6 +2,7 @@ package proxy
 
 import (
 	"context"
+	command "parrotflow/internal/application/command"
 
 	"parrotflow/internal/domain/proxy"
 	"parrotflow/internal/domain/shared"
@@ -30,27 +31,29 @@ func (h *DeactivateProxyCommandHandler) Handle(ctx context.Context, cmd Deactiva
 		return nil, err
 	}
 
-	// Find proxy
-	p, err := h.repository.FindByID(ctx, proxyID)
+	var p *proxy.Proxy
+	err = command.RetryOnConflict(ctx, func() error {
+		// Find proxy
+		var err error
+		p, err = h.repository.FindByID(ctx, proxyID)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return proxy.ErrProxyNotFound
+		}
+
+		// Deactivate proxy
+		p.Deactivate()
+
+		// Save to repository
+		return h.repository.Save(ctx, p)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if p == nil {
-		return nil, proxy.ErrProxyNotFound
-	}
-
-	// Deactivate proxy
-	p.Deactivate()
-
-	// Save to repository
-	if err := h.repository.Save(ctx, p); err != nil {
-		return nil, err
-	}
 
-	// Publish domain events
-	for _, event := range p.Events {
-		h.eventBus.Publish(event)
-	}
+	command.PublishDomainEvents(ctx, h.eventBus, p.Events, p)
 
 	return p, nil
 }
@@ -2,6 +2,7 @@ package proxy
 
 import (
 	"context"
+	command "parrotflow/internal/application/command"
 
 	"parrotflow/internal/domain/proxy"
 	"parrotflow/internal/domain/shared"
@@ -39,15 +40,14 @@ func (h *DeleteProxyCommandHandler) Handle(ctx context.Context, cmd DeleteProxyC
 		return proxy.ErrProxyNotFound
 	}
 
-	// Delete from repository
+	p.Delete()
+
+	// Move to the trash
 	if err := h.repository.Delete(ctx, proxyID); err != nil {
 		return err
 	}
 
-	// Publish domain events (if any were generated before deletion)
-	for _, event := range p.Events {
-		h.eventBus.Publish(event)
-	}
+	command.PublishDomainEvents(ctx, h.eventBus, p.Events, p)
 
 	return nil
 }
@@ -1,7 +1,9 @@
 package proxy
 
 import (
+	command "parrotflow/internal/application/command"
 	"context"
+	"time"
 
 	"parrotflow/internal/domain/proxy"
 	"parrotflow/internal/domain/shared"
@@ -17,12 +19,14 @@ type RecordHealthCommand struct {
 type RecordHealthCommandHandler struct {
 	repository proxy.Repository
 	eventBus   shared.EventBus
+	observer   proxy.HealthObserver
 }
 
-func NewRecordHealthCommandHandler(repository proxy.Repository, eventBus shared.EventBus) *RecordHealthCommandHandler {
+func NewRecordHealthCommandHandler(repository proxy.Repository, eventBus shared.EventBus, observer proxy.HealthObserver) *RecordHealthCommandHandler {
 	return &RecordHealthCommandHandler{
 		repository: repository,
 		eventBus:   eventBus,
+		observer:   observer,
 	}
 }
 
@@ -33,31 +37,34 @@ func (h *RecordHealthCommandHandler) Handle(ctx context.Context, cmd RecordHealt
 		return nil, err
 	}
 
-	// Find proxy
-	p, err := h.repository.FindByID(ctx, proxyID)
-	if err != nil {
-		return nil, err
-	}
-	if p == nil {
-		return nil, proxy.ErrProxyNotFound
-	}
+	var p *proxy.Proxy
+	err = command.RetryOnConflict(ctx, func() error {
+		// Find proxy
+		var err error
+		p, err = h.repository.FindByID(ctx, proxyID)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return proxy.ErrProxyNotFound
+		}
 
-	// Record success or failure
-	if cmd.Success {
-		p.RecordSuccess(cmd.LatencyMs)
-	} else {
-		p.RecordFailure(cmd.ErrorMsg)
-	}
+		// Record success or failure
+		if cmd.Success {
+			p.RecordSuccess(cmd.LatencyMs)
+		} else {
+			p.RecordFailure(cmd.ErrorMsg)
+		}
 
-	// Save to repository
-	if err := h.repository.Save(ctx, p); err != nil {
+		// Save to repository
+		return h.repository.Save(ctx, p)
+	})
+	if err != nil {
 		return nil, err
 	}
+	h.observer.ObserveHealthCheck(p.Id.String(), cmd.Success, time.Duration(cmd.LatencyMs)*time.Millisecond)
 
-	// Publish domain events
-	for _, event := range p.Events {
-		h.eventBus.Publish(event)
-	}
+	command.PublishDomainEvents(ctx, h.eventBus, p.Events, p)
 
 	return p, nil
 }
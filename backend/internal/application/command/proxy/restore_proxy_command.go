@@ -0,0 +1,48 @@
+package proxy
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+
+	"parrotflow/internal/domain/proxy"
+	"parrotflow/internal/domain/shared"
+)
+
+type RestoreProxyCommand struct {
+	ID string
+}
+
+type RestoreProxyCommandHandler struct {
+	repository proxy.Repository
+	eventBus   shared.EventBus
+}
+
+func NewRestoreProxyCommandHandler(repository proxy.Repository, eventBus shared.EventBus) *RestoreProxyCommandHandler {
+	return &RestoreProxyCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *RestoreProxyCommandHandler) Handle(ctx context.Context, cmd RestoreProxyCommand) (*proxy.Proxy, error) {
+	// Parse proxy ID
+	proxyID, err := proxy.NewProxyID(cmd.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Take it out of the trash
+	if err := h.repository.Restore(ctx, proxyID); err != nil {
+		return nil, err
+	}
+
+	p, err := h.repository.FindByID(ctx, proxyID)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Restore()
+
+	command.PublishDomainEvents(ctx, h.eventBus, p.Events, p)
+	return p, nil
+}
@@ -2,6 +2,7 @@ package proxy
 
 import (
 	"context"
+	command "parrotflow/internal/application/command"
 
 	"parrotflow/internal/domain/proxy"
 	"parrotflow/internal/domain/shared"
@@ -15,6 +16,9 @@ type UpdateProxyCommand struct {
 	Protocol *string
 	Username *string
 	Password *string
+
+	// ExpectedVersion is the version the client last saw (If-Match), nil to skip the check
+	ExpectedVersion *uint64
 }
 
 type UpdateProxyCommandHandler struct {
@@ -36,72 +40,79 @@ func (h *UpdateProxyCommandHandler) Handle(ctx context.Context, cmd UpdateProxyC
 		return nil, err
 	}
 
-	// Find proxy
-	p, err := h.repository.FindByID(ctx, proxyID)
-	if err != nil {
-		return nil, err
-	}
-	if p == nil {
-		return nil, proxy.ErrProxyNotFound
-	}
-
-	// Update name if provided
-	if cmd.Name != nil && *cmd.Name != "" {
-		p.Name = *cmd.Name
-	}
-
-	// Update host if provided
-	if cmd.Host != nil && *cmd.Host != "" {
-		p.Host = *cmd.Host
-	}
+	var p *proxy.Proxy
+	err = command.RetryOnConflict(ctx, func() error {
+		// Find proxy
+		var err error
+		p, err = h.repository.FindByID(ctx, proxyID)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return proxy.ErrProxyNotFound
+		}
 
-	// Update port if provided
-	if cmd.Port != nil && *cmd.Port > 0 {
-		p.Port = *cmd.Port
-	}
+		// Honour the caller's If-Match precondition
+		if err := shared.CheckVersion("proxy", p.Id.String(), p.Version, cmd.ExpectedVersion); err != nil {
+			return err
+		}
 
-	// Update protocol if provided
-	if cmd.Protocol != nil && *cmd.Protocol != "" {
-		protocol, err := proxy.NewProxyProtocol(*cmd.Protocol)
-		if err != nil {
-			return nil, err
+		// Update name if provided
+		if cmd.Name != nil && *cmd.Name != "" {
+			p.Name = *cmd.Name
 		}
-		p.Protocol = protocol
-	}
 
-	// Update credentials if provided
-	if cmd.Username != nil || cmd.Password != nil {
-		username := ""
-		password := ""
-		if cmd.Username != nil {
-			username = *cmd.Username
-		} else if p.Credentials != nil {
-			username = p.Credentials.Username
+		// Update host if provided
+		if cmd.Host != nil && *cmd.Host != "" {
+			p.Host = *cmd.Host
 		}
-		if cmd.Password != nil {
-			password = *cmd.Password
-		} else if p.Credentials != nil {
-			password = p.Credentials.Password
+
+		// Update port if provided
+		if cmd.Port != nil && *cmd.Port > 0 {
+			p.Port = *cmd.Port
 		}
 
-		if username != "" || password != "" {
-			credentials, err := proxy.NewProxyCredentials(username, password)
+		// Update protocol if provided
+		if cmd.Protocol != nil && *cmd.Protocol != "" {
+			protocol, err := proxy.NewProxyProtocol(*cmd.Protocol)
 			if err != nil {
-				return nil, err
+				return err
 			}
-			p.SetCredentials(credentials)
+			p.Protocol = protocol
 		}
-	}
 
-	// Save to repository
-	if err := h.repository.Save(ctx, p); err != nil {
+		// Update credentials if provided
+		if cmd.Username != nil || cmd.Password != nil {
+			username := ""
+			password := ""
+			if cmd.Username != nil {
+				username = *cmd.Username
+			} else if p.Credentials != nil {
+				username = p.Credentials.Username
+			}
+			if cmd.Password != nil {
+				password = *cmd.Password
+			} else if p.Credentials != nil {
+				password = p.Credentials.Password
+			}
+
+			if username != "" || password != "" {
+				credentials, err := proxy.NewProxyCredentials(username, password)
+				if err != nil {
+					return err
+				}
+				p.SetCredentials(credentials)
+			}
+		}
+
+		// Save to repository
+		return h.repository.Save(ctx, p)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Publish domain events
-	for _, event := range p.Events {
-		h.eventBus.Publish(event)
-	}
+	command.PublishDomainEvents(ctx, h.eventBus, p.Events, p)
 
 	return p, nil
 }
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/shared"
+)
+
+type CancelRunCommand struct {
+	RunID run.RunID
+}
+
+type CancelRunCommandHandler struct {
+	repository run.Repository
+	eventBus   shared.EventBus
+}
+
+func NewCancelRunCommandHandler(repository run.Repository, eventBus shared.EventBus) *CancelRunCommandHandler {
+	return &CancelRunCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+// Handle cancels a pending or running run; cancelling it again changes nothing
+func (h *CancelRunCommandHandler) Handle(ctx context.Context, cmd CancelRunCommand) (*run.Run, error) {
+	var r *run.Run
+	err := command.RetryOnConflict(ctx, func() error {
+		var err error
+		r, err = h.repository.FindByID(ctx, cmd.RunID)
+		if err != nil {
+			return err
+		}
+		if r.Status == shared.StatusCancelled {
+			return nil
+		}
+
+		if err := r.Cancel(); err != nil {
+			return err
+		}
+
+		return h.repository.Save(ctx, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, r.Events, r)
+	return r, nil
+}
@@ -57,6 +57,6 @@ func (h *CreateRunCommandHandler) Handle(ctx context.Context, cmd CreateRunComma
 	}
 
 	// Publish domain events using centralized helper
-	command.PublishDomainEvents(h.eventBus, run.Events, run)
+	command.PublishDomainEvents(ctx, h.eventBus, run.Events, run)
 	return run, nil
 }
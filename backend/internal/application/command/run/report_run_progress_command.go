@@ -0,0 +1,101 @@
+package command
+
+import (
+	"context"
+	"errors"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/agent"
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/secret"
+	"parrotflow/internal/domain/shared"
+	"time"
+)
+
+type ReportRunProgressCommand struct {
+	RunID   run.RunID
+	NodeID  string
+	Status  run.NodeStatus
+	Message string
+
+	// Reporting agent and its signature over the request body
+	AgentID   agent.AgentID
+	Signature agent.Signature
+	Body      []byte
+}
+
+type ReportRunProgressCommandHandler struct {
+	repository run.Repository
+	agents     agent.Repository
+	secrets    secret.Repository
+	eventBus   shared.EventBus
+}
+
+func NewReportRunProgressCommandHandler(repository run.Repository, agents agent.Repository, secrets secret.Repository, eventBus shared.EventBus) *ReportRunProgressCommandHandler {
+	return &ReportRunProgressCommandHandler{
+		repository: repository,
+		agents:     agents,
+		secrets:    secrets,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *ReportRunProgressCommandHandler) Handle(ctx context.Context, cmd ReportRunProgressCommand) (*run.Run, error) {
+	reporter, err := h.agents.FindByID(ctx, cmd.AgentID)
+	if errors.Is(err, agent.ErrAgentNotFound) {
+		// Unknown agents fail like bad signatures, without confirming which IDs exist
+		return nil, agent.ErrInvalidSignature
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := reporter.VerifySignature(agent.PurposeProgress(cmd.RunID.String()), cmd.Signature, cmd.Body, now); err != nil {
+		return nil, err
+	}
+	if err := h.agents.UseNonce(ctx, reporter.Id, cmd.Signature.Nonce, now); err != nil {
+		return nil, err
+	}
+
+	var r *run.Run
+	err = command.RetryOnConflict(ctx, func() error {
+		var err error
+		r, err = h.repository.FindByID(ctx, cmd.RunID)
+		if err != nil {
+			return err
+		}
+		if err := r.ClaimBy(cmd.AgentID.String()); err != nil {
+			return err
+		}
+
+		message, err := h.mask(ctx, r, cmd.Message)
+		if err != nil {
+			return err
+		}
+
+		if err := r.ReportProgress(cmd.NodeID, cmd.Status, message); err != nil {
+			return err
+		}
+
+		return h.repository.Save(ctx, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, r.Events, r)
+	return r, nil
+}
+
+// mask hides secret values agents echo in their progress messages, before they are
+// stored and published. The secrets the run referenced when it started are masked,
+// the agent received no others
+func (h *ReportRunProgressCommandHandler) mask(ctx context.Context, r *run.Run, message string) (string, error) {
+	if message == "" || len(r.SecretNames) == 0 {
+		return message, nil
+	}
+	secrets, err := h.secrets.FindByNames(ctx, r.SecretNames)
+	if err != nil {
+		return "", err
+	}
+	return secret.NewMasker(secrets).Mask(message), nil
+}
@@ -2,7 +2,10 @@ package command
 
 import (
 	"context"
+	command "parrotflow/internal/application/command"
 	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/secret"
 	"parrotflow/internal/domain/shared"
 )
 
@@ -12,36 +15,68 @@ type StartRunCommand struct {
 
 type StartRunCommandHandler struct {
 	repository run.Repository
+	scenarios  scenario.Repository
+	secrets    secret.Repository
 	eventBus   shared.EventBus
 }
 
-func NewStartRunCommandHandler(repository run.Repository, eventBus shared.EventBus) *StartRunCommandHandler {
+func NewStartRunCommandHandler(repository run.Repository, scenarios scenario.Repository, secrets secret.Repository, eventBus shared.EventBus) *StartRunCommandHandler {
 	return &StartRunCommandHandler{
 		repository: repository,
+		scenarios:  scenarios,
+		secrets:    secrets,
 		eventBus:   eventBus,
 	}
 }
 
 func (h *StartRunCommandHandler) Handle(ctx context.Context, cmd StartRunCommand) (*run.Run, error) {
-	run, err := h.repository.FindByID(ctx, cmd.RunID)
+	var r *run.Run
+	err := command.RetryOnConflict(ctx, func() error {
+		var err error
+		r, err = h.repository.FindByID(ctx, cmd.RunID)
+		if err != nil {
+			return err
+		}
+
+		if err := r.Start(); err != nil {
+			return err
+		}
+
+		// The run executes the scenario as it is now, whatever edits come after
+		s, err := h.scenarios.FindByID(ctx, r.ScenarioID)
+		if err != nil {
+			return err
+		}
+		definition := s.Definition()
+		r.Definition = &definition
+
+		// The execution request is built once the run started, refuse runs it would fail to build
+		r.SecretNames, err = h.checkSecrets(ctx, definition)
+		if err != nil {
+			return err
+		}
+
+		return h.repository.Save(ctx, r)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if err := run.Start(); err != nil {
-		return nil, err
-	}
+	command.PublishDomainEvents(ctx, h.eventBus, r.Events, r)
+	return r, nil
+}
 
-	if err := h.repository.Save(ctx, run); err != nil {
+// checkSecrets returns the names of the secrets the definition references, failing with
+// secret.ErrUnknownSecret when some do not exist
+func (h *StartRunCommandHandler) checkSecrets(ctx context.Context, definition scenario.Definition) ([]string, error) {
+	values := definition.ParameterValues()
+	names := secret.References(values...)
+	found, err := h.secrets.FindByNames(ctx, names)
+	if err != nil {
 		return nil, err
 	}
-
-	for _, event := range run.Events {
-		if err := h.eventBus.Publish(event); err != nil {
-			// TODO log
-		}
+	if _, err := secret.Resolve(values, secret.Values(found)); err != nil {
+		return nil, err
 	}
-
-	run.ClearEvents()
-	return run, nil
+	return names, nil
 }
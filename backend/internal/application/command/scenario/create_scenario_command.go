@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	command "parrotflow/internal/application/command"
 	"parrotflow/internal/domain/scenario"
 	"parrotflow/internal/domain/shared"
 	utils "parrotflow/pkg/shared"
@@ -68,12 +69,6 @@ func (h *CreateScenarioCommandHandler) Handle(ctx context.Context, cmd CreateSce
 		return nil, err
 	}
 
-	for _, event := range s.Events {
-		if err := h.eventBus.Publish(event); err != nil {
-			// TODO log only or handle it somehow?
-		}
-	}
-
-	s.ClearEvents()
+	command.PublishDomainEvents(ctx, h.eventBus, s.Events, s)
 	return s, nil
 }
@@ -37,7 +37,7 @@ func (h *DeleteScenarioCommandHandler) Handle(ctx context.Context, cmd DeleteSce
 	}
 
 	event := scenario.ScenarioDeleted{
-		BaseEvent:  shared.NewBaseEvent("ScenarioDeleted", cmd.ID.String()),
+		BaseEvent:  shared.NewBaseEvent(scenario.EventScenarioDeleted, cmd.ID.String()),
 		ScenarioID: cmd.ID.String(),
 	}
 
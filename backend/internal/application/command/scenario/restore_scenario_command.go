@@ -0,0 +1,41 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/shared"
+)
+
+type RestoreScenarioCommand struct {
+	ID scenario.ScenarioID
+}
+
+type RestoreScenarioCommandHandler struct {
+	repository scenario.Repository
+	eventBus   shared.EventBus
+}
+
+func NewRestoreScenarioCommandHandler(repository scenario.Repository, eventBus shared.EventBus) *RestoreScenarioCommandHandler {
+	return &RestoreScenarioCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *RestoreScenarioCommandHandler) Handle(ctx context.Context, cmd RestoreScenarioCommand) (*scenario.Scenario, error) {
+	// Runs are hidden while their scenario is trashed, so restoring it brings the history back
+	if err := h.repository.Restore(ctx, cmd.ID); err != nil {
+		return nil, err
+	}
+
+	s, err := h.repository.FindByID(ctx, cmd.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Restore()
+
+	command.PublishDomainEvents(ctx, h.eventBus, s.Events, s)
+	return s, nil
+}
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/shared"
+)
+
+// SetScenarioRetentionCommand overrides the global run retention for one scenario
+// A nil Policy removes the override so the global policy applies again
+type SetScenarioRetentionCommand struct {
+	ID     scenario.ScenarioID
+	Policy *scenario.RetentionPolicy
+}
+
+type SetScenarioRetentionCommandHandler struct {
+	repository scenario.Repository
+	eventBus   shared.EventBus
+}
+
+func NewSetScenarioRetentionCommandHandler(repository scenario.Repository, eventBus shared.EventBus) *SetScenarioRetentionCommandHandler {
+	return &SetScenarioRetentionCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *SetScenarioRetentionCommandHandler) Handle(ctx context.Context, cmd SetScenarioRetentionCommand) (*scenario.Scenario, error) {
+	var s *scenario.Scenario
+	err := command.RetryOnConflict(ctx, func() error {
+		var err error
+		s, err = h.repository.FindByID(ctx, cmd.ID)
+		if err != nil {
+			return err
+		}
+
+		s.UpdateRetention(cmd.Policy)
+		return h.repository.Save(ctx, s)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, s.Events, s)
+	return s, nil
+}
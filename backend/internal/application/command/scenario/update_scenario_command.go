@@ -16,6 +16,9 @@ type UpdateScenarioCommand struct {
 	Context     *scenario.Context
 	InputData   *scenario.InputData
 	Parameters  *scenario.Parameters
+
+	// ExpectedVersion is the version the client last saw (If-Match), nil to skip the check
+	ExpectedVersion *uint64
 }
 
 type UpdateScenarioCommandHandler struct {
@@ -31,47 +34,56 @@ func NewUpdateScenarioCommandHandler(repository scenario.Repository, eventBus sh
 }
 
 func (h *UpdateScenarioCommandHandler) Handle(ctx context.Context, cmd UpdateScenarioCommand) (*scenario.Scenario, error) {
-	scenario, err := h.repository.FindByID(ctx, cmd.ID)
-	if err != nil {
-		return nil, err
-	}
+	var s *scenario.Scenario
+	err := command.RetryOnConflict(ctx, func() error {
+		var err error
+		s, err = h.repository.FindByID(ctx, cmd.ID)
+		if err != nil {
+			return err
+		}
 
-	if cmd.Name != nil {
-		if err := scenario.UpdateName(*cmd.Name); err != nil {
-			return nil, err
+		if err := shared.CheckVersion("scenario", s.Id.String(), s.Version, cmd.ExpectedVersion); err != nil {
+			return err
 		}
-	}
 
-	if cmd.Description != nil {
-		scenario.UpdateDescription(*cmd.Description)
-	}
+		if cmd.Name != nil {
+			if err := s.UpdateName(*cmd.Name); err != nil {
+				return err
+			}
+		}
 
-	if cmd.Tag != nil {
-		scenario.UpdateTag(*cmd.Tag)
-	}
+		if cmd.Description != nil {
+			s.UpdateDescription(*cmd.Description)
+		}
 
-	if cmd.Icon != nil {
-		scenario.UpdateIcon(*cmd.Icon)
-	}
+		if cmd.Tag != nil {
+			s.UpdateTag(*cmd.Tag)
+		}
 
-	if cmd.Context != nil {
-		scenario.UpdateContext(*cmd.Context)
-	}
+		if cmd.Icon != nil {
+			s.UpdateIcon(*cmd.Icon)
+		}
 
-	if cmd.InputData != nil {
-		scenario.UpdateInputData(*cmd.InputData)
-	}
+		if cmd.Context != nil {
+			s.UpdateContext(*cmd.Context)
+		}
 
-	if cmd.Parameters != nil {
-		scenario.UpdateParameters(*cmd.Parameters)
-	}
+		if cmd.InputData != nil {
+			s.UpdateInputData(*cmd.InputData)
+		}
 
-	// Save updated scenario
-	if err := h.repository.Save(ctx, scenario); err != nil {
+		if cmd.Parameters != nil {
+			s.UpdateParameters(*cmd.Parameters)
+		}
+
+		// Save updated scenario
+		return h.repository.Save(ctx, s)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	// Publish domain events using centralized helper
-	command.PublishDomainEvents(h.eventBus, scenario.Events, scenario)
-	return scenario, nil
+	command.PublishDomainEvents(ctx, h.eventBus, s.Events, s)
+	return s, nil
 }
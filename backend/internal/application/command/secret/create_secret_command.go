@@ -0,0 +1,54 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/secret"
+	"parrotflow/internal/domain/shared"
+	utils "parrotflow/pkg/shared"
+)
+
+type CreateSecretCommand struct {
+	Name        string
+	Value       string
+	Description string
+}
+
+type CreateSecretCommandHandler struct {
+	repository secret.Repository
+	eventBus   shared.EventBus
+}
+
+func NewCreateSecretCommandHandler(repository secret.Repository, eventBus shared.EventBus) *CreateSecretCommandHandler {
+	return &CreateSecretCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *CreateSecretCommandHandler) Handle(ctx context.Context, cmd CreateSecretCommand) (*secret.Secret, error) {
+	exists, err := h.repository.Exists(ctx, cmd.Name)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, secret.ErrSecretAlreadyExists
+	}
+
+	secretID, err := secret.NewSecretID(utils.CustomUUID())
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := secret.NewSecret(secretID, cmd.Name, cmd.Value, cmd.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.repository.Save(ctx, s); err != nil {
+		return nil, err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, s.Events, s)
+	return s, nil
+}
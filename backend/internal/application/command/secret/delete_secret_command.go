@@ -0,0 +1,41 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/secret"
+	"parrotflow/internal/domain/shared"
+)
+
+type DeleteSecretCommand struct {
+	ID secret.SecretID
+}
+
+type DeleteSecretCommandHandler struct {
+	repository secret.Repository
+	eventBus   shared.EventBus
+}
+
+func NewDeleteSecretCommandHandler(repository secret.Repository, eventBus shared.EventBus) *DeleteSecretCommandHandler {
+	return &DeleteSecretCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+// Handle removes the secret for good; runs of scenarios still referencing it can no longer start
+func (h *DeleteSecretCommandHandler) Handle(ctx context.Context, cmd DeleteSecretCommand) error {
+	s, err := h.repository.FindByID(ctx, cmd.ID)
+	if err != nil {
+		return err
+	}
+
+	s.Delete()
+
+	if err := h.repository.Delete(ctx, cmd.ID); err != nil {
+		return err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, s.Events, s)
+	return nil
+}
@@ -0,0 +1,61 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/secret"
+	"parrotflow/internal/domain/shared"
+)
+
+type UpdateSecretCommand struct {
+	ID          secret.SecretID
+	Value       *string
+	Description *string
+
+	// ExpectedVersion is the version the client last saw (If-Match), nil to skip the check
+	ExpectedVersion *uint64
+}
+
+type UpdateSecretCommandHandler struct {
+	repository secret.Repository
+	eventBus   shared.EventBus
+}
+
+func NewUpdateSecretCommandHandler(repository secret.Repository, eventBus shared.EventBus) *UpdateSecretCommandHandler {
+	return &UpdateSecretCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *UpdateSecretCommandHandler) Handle(ctx context.Context, cmd UpdateSecretCommand) (*secret.Secret, error) {
+	var s *secret.Secret
+	err := command.RetryOnConflict(ctx, func() error {
+		var err error
+		s, err = h.repository.FindByID(ctx, cmd.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := shared.CheckVersion("secret", s.Id.String(), s.Version, cmd.ExpectedVersion); err != nil {
+			return err
+		}
+
+		if cmd.Value != nil {
+			if err := s.UpdateValue(*cmd.Value); err != nil {
+				return err
+			}
+		}
+		if cmd.Description != nil {
+			s.UpdateDescription(*cmd.Description)
+		}
+
+		return h.repository.Save(ctx, s)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, s.Events, s)
+	return s, nil
+}
@@ -66,6 +66,6 @@ func (h *CreateTagCommandHandler) Handle(ctx context.Context, cmd CreateTagComma
 	}
 
 	// Publish domain events using centralized helper
-	command.PublishDomainEvents(h.eventBus, t.Events, t)
+	command.PublishDomainEvents(ctx, h.eventBus, t.Events, t)
 	return t, nil
 }
@@ -53,10 +53,18 @@ func (m *MockTagRepository) FindByIDs(ctx context.Context, ids []tag.TagID) ([]*
 	return nil, errors.New("not implemented")
 }
 
+func (m *MockTagRepository) FindTrashed(ctx context.Context) ([]*tag.Tag, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (m *MockTagRepository) Delete(ctx context.Context, id tag.TagID) error {
 	return errors.New("not implemented")
 }
 
+func (m *MockTagRepository) Restore(ctx context.Context, id tag.TagID) error {
+	return errors.New("not implemented")
+}
+
 // MockEventBus is a mock implementation of shared.EventBus for testing
 type MockEventBus struct {
 	PublishedEvents []shared.DomainEvent
@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	command "parrotflow/internal/application/command"
 	"errors"
 	"parrotflow/internal/domain/shared"
 	"parrotflow/internal/domain/tag"
@@ -41,11 +42,7 @@ func (h *DeleteTagCommandHandler) Handle(ctx context.Context, cmd DeleteTagComma
 		return err
 	}
 
-	for _, event := range t.Events {
-		if err := h.eventBus.Publish(event); err != nil {
-			// TODO: log error
-		}
-	}
+	command.PublishDomainEvents(ctx, h.eventBus, t.Events, t)
 
 	return nil
 }
@@ -0,0 +1,40 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/domain/tag"
+)
+
+type RestoreTagCommand struct {
+	ID tag.TagID
+}
+
+type RestoreTagCommandHandler struct {
+	repository tag.Repository
+	eventBus   shared.EventBus
+}
+
+func NewRestoreTagCommandHandler(repository tag.Repository, eventBus shared.EventBus) *RestoreTagCommandHandler {
+	return &RestoreTagCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *RestoreTagCommandHandler) Handle(ctx context.Context, cmd RestoreTagCommand) (*tag.Tag, error) {
+	if err := h.repository.Restore(ctx, cmd.ID); err != nil {
+		return nil, err
+	}
+
+	t, err := h.repository.FindByID(ctx, cmd.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Restore()
+
+	command.PublishDomainEvents(ctx, h.eventBus, t.Events, t)
+	return t, nil
+}
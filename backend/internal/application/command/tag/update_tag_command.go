@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	command "parrotflow/internal/application/command"
 	"parrotflow/internal/domain/shared"
 	"parrotflow/internal/domain/tag"
 )
@@ -10,6 +11,9 @@ type UpdateTagCommand struct {
 	ID          tag.TagID
 	Description *string
 	Color       *string
+
+	// ExpectedVersion is the version the client last saw (If-Match), nil to skip the check
+	ExpectedVersion *uint64
 }
 
 type UpdateTagCommandHandler struct {
@@ -25,31 +29,34 @@ func NewUpdateTagCommandHandler(repository tag.Repository, eventBus shared.Event
 }
 
 func (h *UpdateTagCommandHandler) Handle(ctx context.Context, cmd UpdateTagCommand) (*tag.Tag, error) {
-	t, err := h.repository.FindByID(ctx, cmd.ID)
-	if err != nil {
-		return nil, err
-	}
-
-	if cmd.Description != nil {
-		t.UpdateDescription(*cmd.Description)
-	}
+	var t *tag.Tag
+	err := command.RetryOnConflict(ctx, func() error {
+		var err error
+		t, err = h.repository.FindByID(ctx, cmd.ID)
+		if err != nil {
+			return err
+		}
 
-	if cmd.Color != nil {
-		if err := t.UpdateColor(*cmd.Color); err != nil {
-			return nil, err
+		if err := shared.CheckVersion("tag", t.Id.String(), t.Version, cmd.ExpectedVersion); err != nil {
+			return err
 		}
-	}
 
-	if err := h.repository.Save(ctx, t); err != nil {
-		return nil, err
-	}
+		if cmd.Description != nil {
+			t.UpdateDescription(*cmd.Description)
+		}
 
-	for _, event := range t.Events {
-		if err := h.eventBus.Publish(event); err != nil {
-			// TODO: log error
+		if cmd.Color != nil {
+			if err := t.UpdateColor(*cmd.Color); err != nil {
+				return err
+			}
 		}
+
+		return h.repository.Save(ctx, t)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	t.ClearEvents()
+	command.PublishDomainEvents(ctx, h.eventBus, t.Events, t)
 	return t, nil
 }
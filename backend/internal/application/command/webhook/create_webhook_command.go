@@ -0,0 +1,57 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/domain/webhook"
+	utils "parrotflow/pkg/shared"
+)
+
+type CreateWebhookCommand struct {
+	URL         string
+	EventTypes  []string
+	Secret      string // Generated when empty
+	Description string
+}
+
+type CreateWebhookCommandHandler struct {
+	repository webhook.Repository
+	eventBus   shared.EventBus
+}
+
+func NewCreateWebhookCommandHandler(repository webhook.Repository, eventBus shared.EventBus) *CreateWebhookCommandHandler {
+	return &CreateWebhookCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *CreateWebhookCommandHandler) Handle(ctx context.Context, cmd CreateWebhookCommand) (*webhook.Webhook, error) {
+	webhookID, err := webhook.NewWebhookID(utils.CustomUUID())
+	if err != nil {
+		return nil, err
+	}
+
+	secret := cmd.Secret
+	if secret == "" {
+		if secret, err = webhook.NewSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	w, err := webhook.NewWebhook(webhookID, cmd.URL, cmd.EventTypes, secret)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.Description != "" {
+		w.UpdateDescription(cmd.Description)
+	}
+
+	if err := h.repository.Save(ctx, w); err != nil {
+		return nil, err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, w.Events, w)
+	return w, nil
+}
@@ -0,0 +1,40 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/domain/webhook"
+)
+
+type DeleteWebhookCommand struct {
+	ID webhook.WebhookID
+}
+
+type DeleteWebhookCommandHandler struct {
+	repository webhook.Repository
+	eventBus   shared.EventBus
+}
+
+func NewDeleteWebhookCommandHandler(repository webhook.Repository, eventBus shared.EventBus) *DeleteWebhookCommandHandler {
+	return &DeleteWebhookCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *DeleteWebhookCommandHandler) Handle(ctx context.Context, cmd DeleteWebhookCommand) error {
+	w, err := h.repository.FindByID(ctx, cmd.ID)
+	if err != nil {
+		return err
+	}
+
+	w.Delete()
+
+	if err := h.repository.Delete(ctx, cmd.ID); err != nil {
+		return err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, w.Events, w)
+	return nil
+}
@@ -0,0 +1,48 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/domain/webhook"
+)
+
+// EnableWebhookCommand turns a webhook back on, e.g. after it was disabled for failing
+type EnableWebhookCommand struct {
+	ID webhook.WebhookID
+}
+
+type EnableWebhookCommandHandler struct {
+	repository webhook.Repository
+	eventBus   shared.EventBus
+}
+
+func NewEnableWebhookCommandHandler(repository webhook.Repository, eventBus shared.EventBus) *EnableWebhookCommandHandler {
+	return &EnableWebhookCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *EnableWebhookCommandHandler) Handle(ctx context.Context, cmd EnableWebhookCommand) (*webhook.Webhook, error) {
+	var w *webhook.Webhook
+	err := command.RetryOnConflict(ctx, func() error {
+		var err error
+		w, err = h.repository.FindByID(ctx, cmd.ID)
+		if err != nil {
+			return err
+		}
+		if w.Enabled {
+			return nil
+		}
+
+		w.Enable()
+		return h.repository.Save(ctx, w)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, w.Events, w)
+	return w, nil
+}
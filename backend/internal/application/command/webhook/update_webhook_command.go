@@ -0,0 +1,73 @@
+package command
+
+import (
+	"context"
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/domain/webhook"
+)
+
+type UpdateWebhookCommand struct {
+	ID          webhook.WebhookID
+	URL         *string
+	EventTypes  []string // Nil keeps the current subscription
+	Secret      *string
+	Description *string
+
+	// ExpectedVersion is the version the client last saw (If-Match), nil to skip the check
+	ExpectedVersion *uint64
+}
+
+type UpdateWebhookCommandHandler struct {
+	repository webhook.Repository
+	eventBus   shared.EventBus
+}
+
+func NewUpdateWebhookCommandHandler(repository webhook.Repository, eventBus shared.EventBus) *UpdateWebhookCommandHandler {
+	return &UpdateWebhookCommandHandler{
+		repository: repository,
+		eventBus:   eventBus,
+	}
+}
+
+func (h *UpdateWebhookCommandHandler) Handle(ctx context.Context, cmd UpdateWebhookCommand) (*webhook.Webhook, error) {
+	var w *webhook.Webhook
+	err := command.RetryOnConflict(ctx, func() error {
+		var err error
+		w, err = h.repository.FindByID(ctx, cmd.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := shared.CheckVersion("webhook", w.Id.String(), w.Version, cmd.ExpectedVersion); err != nil {
+			return err
+		}
+
+		if cmd.URL != nil {
+			if err := w.UpdateURL(*cmd.URL); err != nil {
+				return err
+			}
+		}
+		if cmd.EventTypes != nil {
+			if err := w.UpdateEventTypes(cmd.EventTypes); err != nil {
+				return err
+			}
+		}
+		if cmd.Secret != nil {
+			if err := w.RotateSecret(*cmd.Secret); err != nil {
+				return err
+			}
+		}
+		if cmd.Description != nil {
+			w.UpdateDescription(*cmd.Description)
+		}
+
+		return h.repository.Save(ctx, w)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	command.PublishDomainEvents(ctx, h.eventBus, w.Events, w)
+	return w, nil
+}
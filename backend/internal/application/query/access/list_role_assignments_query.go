@@ -0,0 +1,22 @@
+package query
+
+import (
+	"context"
+	"parrotflow/internal/domain/access"
+)
+
+type ListRoleAssignmentsQuery struct{}
+
+type ListRoleAssignmentsQueryHandler struct {
+	repository access.Repository
+}
+
+func NewListRoleAssignmentsQueryHandler(repository access.Repository) *ListRoleAssignmentsQueryHandler {
+	return &ListRoleAssignmentsQueryHandler{
+		repository: repository,
+	}
+}
+
+func (h *ListRoleAssignmentsQueryHandler) Handle(ctx context.Context, query ListRoleAssignmentsQuery) ([]*access.RoleAssignment, error) {
+	return h.repository.FindAll(ctx)
+}
@@ -0,0 +1,56 @@
+package query
+
+import (
+	"fmt"
+	"time"
+
+	"parrotflow/internal/domain/analytics"
+)
+
+// Defaults of the criteria left out of a request
+const (
+	defaultPeriod = 30 * 24 * time.Hour
+	defaultLimit  = 10
+	maxBuckets    = 2000
+)
+
+var bucketWidths = map[string]time.Duration{
+	analytics.IntervalHour: time.Hour,
+	analytics.IntervalDay:  24 * time.Hour,
+	analytics.IntervalWeek: 7 * 24 * time.Hour,
+}
+
+// withDefaults fills in the left out criteria and rejects inconsistent ones
+// The period defaults to the 30 days up to now, in daily buckets computed from the runs
+func withDefaults(criteria analytics.Criteria, now time.Time) (analytics.Criteria, error) {
+	if criteria.Until.IsZero() {
+		criteria.Until = now
+	}
+	if criteria.Since.IsZero() {
+		criteria.Since = criteria.Until.Add(-defaultPeriod)
+	}
+	if criteria.Interval == "" {
+		criteria.Interval = analytics.IntervalDay
+	}
+	if criteria.Source == "" {
+		criteria.Source = analytics.SourceRuns
+	}
+	if criteria.Limit <= 0 {
+		criteria.Limit = defaultLimit
+	}
+
+	width, ok := bucketWidths[criteria.Interval]
+	switch {
+	case !criteria.Since.Before(criteria.Until):
+		return criteria, fmt.Errorf("%w: since must be before until", analytics.ErrInvalidCriteria)
+	case !ok:
+		return criteria, fmt.Errorf("%w: unknown interval %q", analytics.ErrInvalidCriteria, criteria.Interval)
+	case criteria.Source != analytics.SourceRuns && criteria.Source != analytics.SourceRollup:
+		return criteria, fmt.Errorf("%w: unknown source %q", analytics.ErrInvalidCriteria, criteria.Source)
+	case criteria.Source == analytics.SourceRollup && criteria.Interval == analytics.IntervalHour:
+		return criteria, fmt.Errorf("%w: the rollup is daily, it has no hourly buckets", analytics.ErrInvalidCriteria)
+	case criteria.Until.Sub(criteria.Since)/width > maxBuckets:
+		return criteria, fmt.Errorf("%w: more than %d %s buckets, use a wider interval", analytics.ErrInvalidCriteria, maxBuckets, criteria.Interval)
+	}
+	return criteria, nil
+}
@@ -0,0 +1,33 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"parrotflow/internal/domain/analytics"
+)
+
+// GetFleetAnalyticsQuery reports on the runs of every scenario not in the trash
+type GetFleetAnalyticsQuery struct {
+	Criteria analytics.Criteria
+}
+
+type GetFleetAnalyticsQueryHandler struct {
+	repository analytics.Repository
+}
+
+func NewGetFleetAnalyticsQueryHandler(repository analytics.Repository) *GetFleetAnalyticsQueryHandler {
+	return &GetFleetAnalyticsQueryHandler{
+		repository: repository,
+	}
+}
+
+func (h *GetFleetAnalyticsQueryHandler) Handle(ctx context.Context, query GetFleetAnalyticsQuery) (*analytics.Report, error) {
+	criteria, err := withDefaults(query.Criteria, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	criteria.ScenarioID = ""
+	return h.repository.Report(ctx, criteria)
+}
@@ -0,0 +1,44 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"parrotflow/internal/domain/analytics"
+	"parrotflow/internal/domain/scenario"
+)
+
+type GetScenarioAnalyticsQuery struct {
+	ScenarioID scenario.ScenarioID
+	Criteria   analytics.Criteria
+}
+
+type GetScenarioAnalyticsQueryHandler struct {
+	repository analytics.Repository
+	scenarios  scenario.Repository
+}
+
+func NewGetScenarioAnalyticsQueryHandler(repository analytics.Repository, scenarios scenario.Repository) *GetScenarioAnalyticsQueryHandler {
+	return &GetScenarioAnalyticsQueryHandler{
+		repository: repository,
+		scenarios:  scenarios,
+	}
+}
+
+func (h *GetScenarioAnalyticsQueryHandler) Handle(ctx context.Context, query GetScenarioAnalyticsQuery) (*analytics.Report, error) {
+	criteria, err := withDefaults(query.Criteria, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := h.scenarios.Exists(ctx, query.ScenarioID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, analytics.ErrScenarioNotFound
+	}
+
+	criteria.ScenarioID = query.ScenarioID.String()
+	return h.repository.Report(ctx, criteria)
+}
@@ -0,0 +1,22 @@
+package query
+
+import (
+	"context"
+	"parrotflow/internal/domain/apikey"
+)
+
+type ListAPIKeysQuery struct{}
+
+type ListAPIKeysQueryHandler struct {
+	repository apikey.Repository
+}
+
+func NewListAPIKeysQueryHandler(repository apikey.Repository) *ListAPIKeysQueryHandler {
+	return &ListAPIKeysQueryHandler{
+		repository: repository,
+	}
+}
+
+func (h *ListAPIKeysQueryHandler) Handle(ctx context.Context, query ListAPIKeysQuery) ([]*apikey.APIKey, error) {
+	return h.repository.FindAll(ctx)
+}
@@ -0,0 +1,32 @@
+package query
+
+import (
+	"context"
+	"parrotflow/internal/domain/audit"
+)
+
+// ExportAuditEntriesQuery selects the entries to export; limit and offset are ignored
+type ExportAuditEntriesQuery struct {
+	Criteria audit.Criteria
+}
+
+type ExportAuditEntriesQueryHandler struct {
+	repository audit.Repository
+}
+
+func NewExportAuditEntriesQueryHandler(repository audit.Repository) *ExportAuditEntriesQueryHandler {
+	return &ExportAuditEntriesQueryHandler{
+		repository: repository,
+	}
+}
+
+// Handle checks the criteria and returns the export, which reads the entries in batches
+// once it is written, so exports of any size are never held in memory
+func (h *ExportAuditEntriesQueryHandler) Handle(ctx context.Context, query ExportAuditEntriesQuery) (audit.Export, error) {
+	if err := validateTimeRange(query.Criteria); err != nil {
+		return nil, err
+	}
+	return func(write func(*audit.Entry) error) error {
+		return h.repository.Each(ctx, query.Criteria, write)
+	}, nil
+}
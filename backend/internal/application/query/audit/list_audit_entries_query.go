@@ -0,0 +1,36 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"parrotflow/internal/domain/audit"
+	"parrotflow/internal/domain/shared"
+)
+
+type ListAuditEntriesQuery struct {
+	Criteria audit.Criteria
+}
+
+type ListAuditEntriesQueryHandler struct {
+	repository audit.Repository
+}
+
+func NewListAuditEntriesQueryHandler(repository audit.Repository) *ListAuditEntriesQueryHandler {
+	return &ListAuditEntriesQueryHandler{
+		repository: repository,
+	}
+}
+
+func (h *ListAuditEntriesQueryHandler) Handle(ctx context.Context, query ListAuditEntriesQuery) (shared.Page[*audit.Entry], error) {
+	if err := validateTimeRange(query.Criteria); err != nil {
+		return shared.Page[*audit.Entry]{}, err
+	}
+	return h.repository.Find(ctx, query.Criteria)
+}
+
+func validateTimeRange(criteria audit.Criteria) error {
+	if !criteria.Since.IsZero() && !criteria.Until.IsZero() && !criteria.Since.Before(criteria.Until) {
+		return fmt.Errorf("%w: since must be before until", shared.ErrInvalidPageRequest)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package query
+
+import (
+	"context"
+	"parrotflow/internal/domain/deadletter"
+)
+
+type GetDeadLetterQuery struct {
+	ID deadletter.DeadLetterID
+}
+
+type GetDeadLetterQueryHandler struct {
+	repository deadletter.Repository
+}
+
+func NewGetDeadLetterQueryHandler(repository deadletter.Repository) *GetDeadLetterQueryHandler {
+	return &GetDeadLetterQueryHandler{
+		repository: repository,
+	}
+}
+
+func (h *GetDeadLetterQueryHandler) Handle(ctx context.Context, query GetDeadLetterQuery) (*deadletter.DeadLetter, error) {
+	return h.repository.FindByID(ctx, query.ID)
+}
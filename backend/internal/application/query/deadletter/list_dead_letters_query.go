@@ -0,0 +1,25 @@
+package query
+
+import (
+	"context"
+	"parrotflow/internal/domain/deadletter"
+	"parrotflow/internal/domain/shared"
+)
+
+type ListDeadLettersQuery struct {
+	Criteria deadletter.Criteria
+}
+
+type ListDeadLettersQueryHandler struct {
+	repository deadletter.Repository
+}
+
+func NewListDeadLettersQueryHandler(repository deadletter.Repository) *ListDeadLettersQueryHandler {
+	return &ListDeadLettersQueryHandler{
+		repository: repository,
+	}
+}
+
+func (h *ListDeadLettersQueryHandler) Handle(ctx context.Context, query ListDeadLettersQuery) (shared.Page[*deadletter.DeadLetter], error) {
+	return h.repository.Find(ctx, query.Criteria)
+}
@@ -0,0 +1,22 @@
+package query
+
+import (
+	"context"
+	"parrotflow/internal/domain/enrollment"
+)
+
+type ListEnrollmentTokensQuery struct{}
+
+type ListEnrollmentTokensQueryHandler struct {
+	repository enrollment.Repository
+}
+
+func NewListEnrollmentTokensQueryHandler(repository enrollment.Repository) *ListEnrollmentTokensQueryHandler {
+	return &ListEnrollmentTokensQueryHandler{
+		repository: repository,
+	}
+}
+
+func (h *ListEnrollmentTokensQueryHandler) Handle(ctx context.Context, query ListEnrollmentTokensQuery) ([]*enrollment.Token, error) {
+	return h.repository.FindAll(ctx)
+}
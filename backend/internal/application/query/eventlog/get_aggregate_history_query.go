@@ -0,0 +1,40 @@
+package query
+
+import (
+	"context"
+	"parrotflow/internal/domain/eventlog"
+	"parrotflow/internal/domain/shared"
+)
+
+// GetAggregateHistoryQuery lists the events of one aggregate, oldest first unless
+// the criteria order them otherwise
+// Deleted aggregates keep their history, so a missing aggregate is not an error
+type GetAggregateHistoryQuery struct {
+	AggregateType string
+	AggregateID   string
+	Criteria      eventlog.Criteria
+}
+
+type GetAggregateHistoryQueryHandler struct {
+	repository eventlog.Repository
+}
+
+func NewGetAggregateHistoryQueryHandler(repository eventlog.Repository) *GetAggregateHistoryQueryHandler {
+	return &GetAggregateHistoryQueryHandler{
+		repository: repository,
+	}
+}
+
+func (h *GetAggregateHistoryQueryHandler) Handle(ctx context.Context, query GetAggregateHistoryQuery) (shared.Page[*eventlog.Entry], error) {
+	criteria := query.Criteria
+	criteria.AggregateType = query.AggregateType
+	criteria.AggregateID = query.AggregateID
+	if criteria.OrderDir == "" {
+		criteria.OrderDir = shared.SortAsc
+	}
+
+	if err := validateTimeRange(criteria); err != nil {
+		return shared.Page[*eventlog.Entry]{}, err
+	}
+	return h.repository.Find(ctx, criteria)
+}
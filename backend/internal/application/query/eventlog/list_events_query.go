@@ -0,0 +1,36 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"parrotflow/internal/domain/eventlog"
+	"parrotflow/internal/domain/shared"
+)
+
+type ListEventsQuery struct {
+	Criteria eventlog.Criteria
+}
+
+type ListEventsQueryHandler struct {
+	repository eventlog.Repository
+}
+
+func NewListEventsQueryHandler(repository eventlog.Repository) *ListEventsQueryHandler {
+	return &ListEventsQueryHandler{
+		repository: repository,
+	}
+}
+
+func (h *ListEventsQueryHandler) Handle(ctx context.Context, query ListEventsQuery) (shared.Page[*eventlog.Entry], error) {
+	if err := validateTimeRange(query.Criteria); err != nil {
+		return shared.Page[*eventlog.Entry]{}, err
+	}
+	return h.repository.Find(ctx, query.Criteria)
+}
+
+func validateTimeRange(criteria eventlog.Criteria) error {
+	if !criteria.Since.IsZero() && !criteria.Until.IsZero() && !criteria.Since.Before(criteria.Until) {
+		return fmt.Errorf("%w: since must be before until", shared.ErrInvalidPageRequest)
+	}
+	return nil
+}
@@ -8,8 +8,9 @@ import (
 )
 
 type ListProxiesQuery struct {
-	Status *string   // Optional filter by status
-	Tags   []string  // Optional filter by tags
+	Status  *string  // Optional filter by status
+	Tags    []string // Optional filter by tags
+	Trashed bool     // List the trash instead of live proxies
 }
 
 type ListProxiesQueryHandler struct {
@@ -23,6 +24,11 @@ func NewListProxiesQueryHandler(repository proxy.Repository) *ListProxiesQueryHa
 }
 
 func (h *ListProxiesQueryHandler) Handle(ctx context.Context, query ListProxiesQuery) ([]*proxy.Proxy, error) {
+	// The trash is listed on its own
+	if query.Trashed {
+		return h.repository.FindTrashed(ctx)
+	}
+
 	// If filtering by tags
 	if len(query.Tags) > 0 {
 		tagIDs := make([]tag.TagID, len(query.Tags))
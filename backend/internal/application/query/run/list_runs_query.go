@@ -3,6 +3,7 @@ package query
 import (
 	"context"
 	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/shared"
 )
 
 type ListRunsQuery struct {
@@ -19,6 +20,9 @@ func NewListRunsQueryHandler(repository run.Repository) *ListRunsQueryHandler {
 	}
 }
 
-func (h *ListRunsQueryHandler) Handle(ctx context.Context, query ListRunsQuery) ([]*run.Run, error) {
+func (h *ListRunsQueryHandler) Handle(ctx context.Context, query ListRunsQuery) (shared.Page[*run.Run], error) {
+	if err := query.Criteria.Validate(); err != nil {
+		return shared.Page[*run.Run]{}, err
+	}
 	return h.repository.FindAll(ctx, query.Criteria)
 }
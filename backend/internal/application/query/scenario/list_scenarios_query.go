@@ -3,6 +3,7 @@ package query
 import (
 	"context"
 	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/shared"
 )
 
 type ListScenariosQuery struct {
@@ -19,6 +20,9 @@ func NewListScenariosQueryHandler(repository scenario.Repository) *ListScenarios
 	}
 }
 
-func (h *ListScenariosQueryHandler) Handle(ctx context.Context, query ListScenariosQuery) ([]*scenario.Scenario, error) {
+func (h *ListScenariosQueryHandler) Handle(ctx context.Context, query ListScenariosQuery) (shared.Page[*scenario.Scenario], error) {
+	if err := query.Criteria.Validate(); err != nil {
+		return shared.Page[*scenario.Scenario]{}, err
+	}
 	return h.repository.FindAll(ctx, query.Criteria)
 }
@@ -0,0 +1,24 @@
+package query
+
+import (
+	"context"
+	"parrotflow/internal/domain/secret"
+)
+
+type GetSecretQuery struct {
+	ID secret.SecretID
+}
+
+type GetSecretQueryHandler struct {
+	repository secret.Repository
+}
+
+func NewGetSecretQueryHandler(repository secret.Repository) *GetSecretQueryHandler {
+	return &GetSecretQueryHandler{
+		repository: repository,
+	}
+}
+
+func (h *GetSecretQueryHandler) Handle(ctx context.Context, query GetSecretQuery) (*secret.Secret, error) {
+	return h.repository.FindByID(ctx, query.ID)
+}
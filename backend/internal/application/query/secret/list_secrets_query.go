@@ -0,0 +1,22 @@
+package query
+
+import (
+	"context"
+	"parrotflow/internal/domain/secret"
+)
+
+type ListSecretsQuery struct{}
+
+type ListSecretsQueryHandler struct {
+	repository secret.Repository
+}
+
+func NewListSecretsQueryHandler(repository secret.Repository) *ListSecretsQueryHandler {
+	return &ListSecretsQueryHandler{
+		repository: repository,
+	}
+}
+
+func (h *ListSecretsQueryHandler) Handle(ctx context.Context, query ListSecretsQuery) ([]*secret.Secret, error) {
+	return h.repository.FindAll(ctx)
+}
@@ -7,6 +7,7 @@ import (
 
 type ListTagsQuery struct {
 	Category *tag.TagCategory // Optional filter by category
+	Trashed  bool             // List the trash instead of live tags
 }
 
 type ListTagsQueryHandler struct {
@@ -20,6 +21,9 @@ func NewListTagsQueryHandler(repository tag.Repository) *ListTagsQueryHandler {
 }
 
 func (h *ListTagsQueryHandler) Handle(ctx context.Context, query ListTagsQuery) ([]*tag.Tag, error) {
+	if query.Trashed {
+		return h.repository.FindTrashed(ctx)
+	}
 	if query.Category != nil {
 		return h.repository.FindByCategory(ctx, *query.Category)
 	}
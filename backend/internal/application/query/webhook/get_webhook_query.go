@@ -0,0 +1,24 @@
+package query
+
+import (
+	"context"
+	"parrotflow/internal/domain/webhook"
+)
+
+type GetWebhookQuery struct {
+	ID webhook.WebhookID
+}
+
+type GetWebhookQueryHandler struct {
+	repository webhook.Repository
+}
+
+func NewGetWebhookQueryHandler(repository webhook.Repository) *GetWebhookQueryHandler {
+	return &GetWebhookQueryHandler{
+		repository: repository,
+	}
+}
+
+func (h *GetWebhookQueryHandler) Handle(ctx context.Context, query GetWebhookQuery) (*webhook.Webhook, error) {
+	return h.repository.FindByID(ctx, query.ID)
+}
@@ -0,0 +1,31 @@
+package query
+
+import (
+	"context"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/domain/webhook"
+)
+
+type ListWebhookDeliveriesQuery struct {
+	Criteria webhook.DeliveryCriteria
+}
+
+type ListWebhookDeliveriesQueryHandler struct {
+	repository webhook.Repository
+	deliveries webhook.DeliveryRepository
+}
+
+func NewListWebhookDeliveriesQueryHandler(repository webhook.Repository, deliveries webhook.DeliveryRepository) *ListWebhookDeliveriesQueryHandler {
+	return &ListWebhookDeliveriesQueryHandler{
+		repository: repository,
+		deliveries: deliveries,
+	}
+}
+
+func (h *ListWebhookDeliveriesQueryHandler) Handle(ctx context.Context, query ListWebhookDeliveriesQuery) (shared.Page[*webhook.Delivery], error) {
+	// Report a missing webhook instead of an empty log
+	if _, err := h.repository.FindByID(ctx, query.Criteria.WebhookID); err != nil {
+		return shared.Page[*webhook.Delivery]{}, err
+	}
+	return h.deliveries.FindByWebhook(ctx, query.Criteria)
+}
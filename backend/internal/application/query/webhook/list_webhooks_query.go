@@ -0,0 +1,22 @@
+package query
+
+import (
+	"context"
+	"parrotflow/internal/domain/webhook"
+)
+
+type ListWebhooksQuery struct{}
+
+type ListWebhooksQueryHandler struct {
+	repository webhook.Repository
+}
+
+func NewListWebhooksQueryHandler(repository webhook.Repository) *ListWebhooksQueryHandler {
+	return &ListWebhooksQueryHandler{
+		repository: repository,
+	}
+}
+
+func (h *ListWebhooksQueryHandler) Handle(ctx context.Context, query ListWebhooksQuery) ([]*webhook.Webhook, error) {
+	return h.repository.FindAll(ctx)
+}
@@ -1,46 +1,115 @@
 package container
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/google/wire"
 	"gorm.io/gorm"
 
 	// Domain
+	"parrotflow/internal/domain/access"
 	"parrotflow/internal/domain/agent"
+	"parrotflow/internal/domain/analytics"
+	"parrotflow/internal/domain/apikey"
+	"parrotflow/internal/domain/audit"
+	"parrotflow/internal/domain/deadletter"
+	"parrotflow/internal/domain/enrollment"
+	"parrotflow/internal/domain/eventlog"
 	"parrotflow/internal/domain/proxy"
 	"parrotflow/internal/domain/run"
 	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/secret"
 	"parrotflow/internal/domain/shared"
 	"parrotflow/internal/domain/tag"
+	"parrotflow/internal/domain/webhook"
 
 	// Infrastructure
+	"parrotflow/internal/infrastructure/auth"
 	"parrotflow/internal/infrastructure/events"
+	"parrotflow/internal/infrastructure/health"
+	"parrotflow/internal/infrastructure/maintenance"
+	"parrotflow/internal/infrastructure/messaging"
+	"parrotflow/internal/infrastructure/messaging/memory"
+	"parrotflow/internal/infrastructure/messaging/nats"
+	"parrotflow/internal/infrastructure/messaging/rabbitmq"
+	"parrotflow/internal/infrastructure/metrics"
+	"parrotflow/internal/infrastructure/outbox"
 	"parrotflow/internal/infrastructure/persistence"
+	"parrotflow/internal/infrastructure/realtime"
+	"parrotflow/internal/infrastructure/webhooks"
+	"parrotflow/internal/ports"
 
 	// Application - Commands
+	accesscommand "parrotflow/internal/application/command/access"
 	agentcommand "parrotflow/internal/application/command/agent"
+	apikeycommand "parrotflow/internal/application/command/apikey"
+	deadlettercommand "parrotflow/internal/application/command/deadletter"
+	enrollmentcommand "parrotflow/internal/application/command/enrollment"
 	proxycommand "parrotflow/internal/application/command/proxy"
 	runcommand "parrotflow/internal/application/command/run"
 	scenariocommand "parrotflow/internal/application/command/scenario"
+	secretcommand "parrotflow/internal/application/command/secret"
 	tagcommand "parrotflow/internal/application/command/tag"
+	webhookcommand "parrotflow/internal/application/command/webhook"
 
 	// Application - Queries
+	accessquery "parrotflow/internal/application/query/access"
 	agentquery "parrotflow/internal/application/query/agent"
+	analyticsquery "parrotflow/internal/application/query/analytics"
+	apikeyquery "parrotflow/internal/application/query/apikey"
+	auditquery "parrotflow/internal/application/query/audit"
+	deadletterquery "parrotflow/internal/application/query/deadletter"
+	enrollmentquery "parrotflow/internal/application/query/enrollment"
+	eventlogquery "parrotflow/internal/application/query/eventlog"
 	proxyquery "parrotflow/internal/application/query/proxy"
 	runquery "parrotflow/internal/application/query/run"
 	scenarioquery "parrotflow/internal/application/query/scenario"
+	secretquery "parrotflow/internal/application/query/secret"
 	tagquery "parrotflow/internal/application/query/tag"
+	webhookquery "parrotflow/internal/application/query/webhook"
 
 	// HTTP
 	"parrotflow/internal/interfaces/http/handlers"
+	"parrotflow/internal/interfaces/http/ws"
+	"parrotflow/internal/interfaces/messaging/consumers"
 )
 
 // ============================================================================
 // INFRASTRUCTURE PROVIDERS
 // ============================================================================
 
-// NewEventBus creates a new async event bus
-func NewEventBus() shared.EventBus {
-	bus := events.NewAsyncEventBus()
+// NewHealthRegistry creates the registry of the readiness probes
+// The database, its schema and the broker are required to work; stale agents and
+// a filling event backlog only degrade the instance
+func NewHealthRegistry(
+	db *gorm.DB,
+	config health.Config,
+	broker ports.MessageBroker,
+	eventDispatcher *events.WorkerPoolEventBus,
+	agents agent.Repository,
+) *health.Registry {
+	registry := health.NewRegistry(config.Timeout)
+	registry.Register("database", health.DatabasePing(db))
+	registry.Register("schema", health.SchemaVersion(db))
+	registry.Register("broker", health.Broker(broker))
+	registry.RegisterOptional("event_bus", health.EventBacklog(eventDispatcher, config.MaxEventBacklog))
+	registry.RegisterOptional("agents", health.StaleAgents(agents, config.HeartbeatTimeout, config.MaxStaleAgents))
+	return registry
+}
+
+// NewAuthenticator creates the authenticator of API callers
+// A configured JWKS is refreshed in the background for the lifetime of the process
+func NewAuthenticator(config auth.Config, keys apikey.Repository, assignments access.Repository) (*auth.Authenticator, error) {
+	return auth.NewAuthenticator(context.Background(), config, keys, assignments)
+}
+
+// NewEventDispatcher creates the worker pool bus that delivers relayed events to subscribers
+// Events handlers gave up on are kept in the event_dead_letters table
+// Started runs are sent to the agents from here, so a failed send is retried like any handler
+func NewEventDispatcher(db *gorm.DB, config events.WorkerPoolConfig, m *metrics.Metrics, runs run.Repository, secrets secret.Repository, broker ports.MessageBroker) *events.WorkerPoolEventBus {
+	bus := events.NewWorkerPoolEventBus(config, persistence.NewEventDeadLetterRepository(db))
 
 	// Subscribe event handlers
 	bus.Subscribe(events.NewScenarioCreatedHandler())
@@ -50,10 +119,141 @@ func NewEventBus() shared.EventBus {
 	bus.Subscribe(events.NewRunStartedHandler())
 	bus.Subscribe(events.NewRunCompletedHandler())
 	bus.Subscribe(events.NewRunFailedHandler())
+	bus.Subscribe(metrics.NewRunRecorder(m))
+	bus.Subscribe(messaging.NewRunDispatcher(runs, secrets, broker))
+
+	return bus
+}
+
+// NewAgentConsumer creates the consumer of the heartbeats and progress agents publish
+// to the broker; it follows started runs to consume their progress queues
+func NewAgentConsumer(
+	broker ports.MessageBroker,
+	runs run.Repository,
+	heartbeats *agentcommand.UpdateHeartbeatCommandHandler,
+	progress *runcommand.ReportRunProgressCommandHandler,
+	dispatcher *events.WorkerPoolEventBus,
+) *consumers.AgentConsumer {
+	consumer := consumers.NewAgentConsumer(broker, runs, heartbeats, progress)
+	dispatcher.Subscribe(consumer)
+	return consumer
+}
+
+// NewMetrics creates the Prometheus metrics served on /metrics
+func NewMetrics(runs run.Repository, agents agent.Repository) *metrics.Metrics {
+	return metrics.New(runs, agents)
+}
+
+// ProvideProxyHealthObserver keeps metrics of proxy health checks
+func ProvideProxyHealthObserver(m *metrics.Metrics) proxy.HealthObserver {
+	return m
+}
 
+// NewRealtimeHub creates the hub that fans events out to streaming clients
+// Message IDs are event log sequences, the hub resumes after the last one recorded
+func NewRealtimeHub(db *gorm.DB) (*realtime.Hub, error) {
+	last, err := persistence.NewEventLogRepository(db).LastSequence(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return realtime.NewHub(realtime.DefaultHubConfig(), last), nil
+}
+
+// NewStreamBus creates the synchronous bus feeding the realtime hub
+// Unlike the dispatcher it delivers in relay order, so streams see events in sequence
+func NewStreamBus(hub *realtime.Hub) *events.InMemoryEventBus {
+	bus := events.NewInMemoryEventBus()
+	bus.Subscribe(realtime.NewRunEventPublisher(hub))
+	bus.Subscribe(realtime.NewFleetEventPublisher(hub))
 	return bus
 }
 
+// NewWebSocketServer creates the WebSocket channel pushing hub topics to clients
+func NewWebSocketServer(hub *realtime.Hub) *ws.Server {
+	return ws.NewServer(hub, ws.DefaultConfig())
+}
+
+// NewWebhookWorker creates the worker that sends queued webhook deliveries
+func NewWebhookWorker(repository webhook.Repository, deliveries webhook.DeliveryRepository, config webhooks.Config) *webhooks.Worker {
+	return webhooks.NewWorker(repository, deliveries, config)
+}
+
+// NewOutboxRelay creates the relay that delivers outbox events to the dispatcher, the stream bus and webhooks
+func NewOutboxRelay(store *persistence.OutboxRepository, dispatcher *events.WorkerPoolEventBus, stream *events.InMemoryEventBus, webhookSink *webhooks.Sink) *outbox.Relay {
+	return outbox.NewRelay(
+		store,
+		events.NewDefaultRegistry(),
+		outbox.DefaultRelayConfig(),
+		outbox.NewEventBusSink("dispatcher", dispatcher),
+		outbox.NewEventBusSink("stream", stream),
+		webhookSink,
+	)
+}
+
+// NewEventBus creates the outbox-backed event bus used by command handlers
+func NewEventBus(store *persistence.OutboxRepository, relay *outbox.Relay, dispatcher *events.WorkerPoolEventBus) shared.EventBus {
+	return outbox.NewEventBus(store, relay, dispatcher)
+}
+
+// NewPurgeWorker creates the worker that empties the trash of scenarios, proxies and tags
+func NewPurgeWorker(db *gorm.DB, config maintenance.PurgeConfig, cipher ports.Cipher) *maintenance.PurgeWorker {
+	worker := maintenance.NewPurgeWorker(config)
+	worker.AddPurger("scenarios", persistence.NewScenarioRepository(db))
+	worker.AddPurger("proxies", persistence.NewProxyRepository(db, cipher))
+	worker.AddPurger("tags", persistence.NewTagRepository(db))
+	return worker
+}
+
+// NewRunCompactor creates the worker that applies run retention policies
+func NewRunCompactor(db *gorm.DB, scenarios scenario.Repository, config maintenance.CompactionConfig) *maintenance.RunCompactor {
+	var archiver maintenance.Archiver
+	if config.ArchiveDir != "" {
+		archiver = maintenance.NewFileArchiver(config.ArchiveDir)
+	}
+	return maintenance.NewRunCompactor(scenarios, persistence.NewRunRepository(db), archiver, config)
+}
+
+// NewRollupWorker creates the worker that refreshes the daily run rollup
+func NewRollupWorker(repository analytics.Repository, config maintenance.RollupConfig) *maintenance.RollupWorker {
+	return maintenance.NewRollupWorker(repository, config)
+}
+
+// NewMessageBroker creates the broker selected by the configuration
+func NewMessageBroker(config messaging.Config) (ports.MessageBroker, error) {
+	broker, err := newBroker(config)
+	if err != nil {
+		return nil, err
+	}
+	return messaging.NewTracedBroker(messaging.NewCorrelatedBroker(broker)), nil
+}
+
+func newBroker(config messaging.Config) (ports.MessageBroker, error) {
+	switch config.Broker {
+	case messaging.BrokerMemory, "":
+		return memory.NewBroker(), nil
+	case messaging.BrokerRabbitMQ:
+		if config.BrokerURL == "" {
+			return nil, errors.New("the rabbitmq broker needs a broker URL")
+		}
+		return rabbitmq.NewBroker(config.BrokerURL), nil
+	case messaging.BrokerNATS:
+		if config.BrokerURL != "" {
+			return nats.NewBroker(config.BrokerURL)
+		}
+		return nats.NewEmbeddedBroker(nats.EmbeddedConfig{
+			StoreDir: config.NATSStoreDir,
+			Port:     config.NATSPort,
+		})
+	default:
+		return nil, fmt.Errorf("unknown broker %q", config.Broker)
+	}
+}
+
+// NewDeadLetterCollector creates the worker that stores messages rejected by agents
+func NewDeadLetterCollector(broker ports.MessageBroker, repository deadletter.Repository, config messaging.Config) *messaging.DeadLetterCollector {
+	return messaging.NewDeadLetterCollector(broker, repository, config.Queues)
+}
+
 // ============================================================================
 // REPOSITORY PROVIDERS
 // ============================================================================
@@ -65,14 +265,25 @@ var RepositorySet = wire.NewSet(
 	ProvideTagRepository,
 	ProvideScenarioRepository,
 	ProvideRunRepository,
+	ProvideWebhookRepository,
+	ProvideWebhookDeliveryRepository,
+	ProvideEventLogRepository,
+	ProvideDeadLetterRepository,
+	ProvideAnalyticsRepository,
+	ProvideAPIKeyRepository,
+	ProvideRoleAssignmentRepository,
+	ProvideEnrollmentTokenRepository,
+	ProvideSecretRepository,
+	ProvideAuditLogRepository,
+	persistence.NewOutboxRepository,
 )
 
-func ProvideAgentRepository(db *gorm.DB) agent.Repository {
-	return persistence.NewAgentRepository(db)
+func ProvideAgentRepository(db *gorm.DB, cipher ports.Cipher) agent.Repository {
+	return persistence.NewAgentRepository(db, cipher)
 }
 
-func ProvideProxyRepository(db *gorm.DB) proxy.Repository {
-	return persistence.NewProxyRepository(db)
+func ProvideProxyRepository(db *gorm.DB, cipher ports.Cipher) proxy.Repository {
+	return persistence.NewProxyRepository(db, cipher)
 }
 
 func ProvideTagRepository(db *gorm.DB) tag.Repository {
@@ -87,6 +298,46 @@ func ProvideRunRepository(db *gorm.DB) run.Repository {
 	return persistence.NewRunRepository(db)
 }
 
+func ProvideWebhookRepository(db *gorm.DB, cipher ports.Cipher) webhook.Repository {
+	return persistence.NewWebhookRepository(db, cipher)
+}
+
+func ProvideWebhookDeliveryRepository(db *gorm.DB) webhook.DeliveryRepository {
+	return persistence.NewWebhookDeliveryRepository(db)
+}
+
+func ProvideEventLogRepository(db *gorm.DB) eventlog.Repository {
+	return persistence.NewEventLogRepository(db)
+}
+
+func ProvideAuditLogRepository(db *gorm.DB) audit.Repository {
+	return persistence.NewAuditLogRepository(db)
+}
+
+func ProvideDeadLetterRepository(db *gorm.DB, cipher ports.Cipher) deadletter.Repository {
+	return persistence.NewDeadLetterRepository(db, cipher)
+}
+
+func ProvideAnalyticsRepository(db *gorm.DB) analytics.Repository {
+	return persistence.NewAnalyticsRepository(db)
+}
+
+func ProvideAPIKeyRepository(db *gorm.DB) apikey.Repository {
+	return persistence.NewAPIKeyRepository(db)
+}
+
+func ProvideRoleAssignmentRepository(db *gorm.DB) access.Repository {
+	return persistence.NewRoleAssignmentRepository(db)
+}
+
+func ProvideEnrollmentTokenRepository(db *gorm.DB) enrollment.Repository {
+	return persistence.NewEnrollmentTokenRepository(db)
+}
+
+func ProvideSecretRepository(db *gorm.DB, cipher ports.Cipher) secret.Repository {
+	return persistence.NewSecretRepository(db, cipher)
+}
+
 // ============================================================================
 // COMMAND HANDLER PROVIDERS
 // ============================================================================
@@ -100,6 +351,7 @@ var CommandHandlerSet = wire.NewSet(
 	agentcommand.NewReleaseRunCommandHandler,
 	agentcommand.NewUpdateAgentCommandHandler,
 	agentcommand.NewDeregisterAgentCommandHandler,
+	agentcommand.NewRevokeAgentCommandHandler,
 
 	// Proxy commands
 	proxycommand.NewCreateProxyCommandHandler,
@@ -108,20 +360,53 @@ var CommandHandlerSet = wire.NewSet(
 	proxycommand.NewActivateProxyCommandHandler,
 	proxycommand.NewDeactivateProxyCommandHandler,
 	proxycommand.NewRecordHealthCommandHandler,
+	proxycommand.NewRestoreProxyCommandHandler,
 
 	// Tag commands
 	tagcommand.NewCreateTagCommandHandler,
 	tagcommand.NewUpdateTagCommandHandler,
 	tagcommand.NewDeleteTagCommandHandler,
+	tagcommand.NewRestoreTagCommandHandler,
 
 	// Scenario commands
 	scenariocommand.NewCreateScenarioCommandHandler,
 	scenariocommand.NewUpdateScenarioCommandHandler,
 	scenariocommand.NewDeleteScenarioCommandHandler,
+	scenariocommand.NewRestoreScenarioCommandHandler,
+	scenariocommand.NewSetScenarioRetentionCommandHandler,
 
 	// Run commands
 	runcommand.NewCreateRunCommandHandler,
 	runcommand.NewStartRunCommandHandler,
+	runcommand.NewCancelRunCommandHandler,
+	runcommand.NewReportRunProgressCommandHandler,
+
+	// Webhook commands
+	webhookcommand.NewCreateWebhookCommandHandler,
+	webhookcommand.NewUpdateWebhookCommandHandler,
+	webhookcommand.NewDeleteWebhookCommandHandler,
+	webhookcommand.NewEnableWebhookCommandHandler,
+
+	// Dead letter commands
+	deadlettercommand.NewReplayDeadLetterCommandHandler,
+	deadlettercommand.NewDiscardDeadLetterCommandHandler,
+
+	// API key commands
+	apikeycommand.NewCreateAPIKeyCommandHandler,
+	apikeycommand.NewRevokeAPIKeyCommandHandler,
+
+	// Access commands
+	accesscommand.NewAssignRolesCommandHandler,
+	accesscommand.NewUnassignRolesCommandHandler,
+
+	// Secret commands
+	secretcommand.NewCreateSecretCommandHandler,
+	secretcommand.NewUpdateSecretCommandHandler,
+	secretcommand.NewDeleteSecretCommandHandler,
+
+	// Enrollment commands
+	enrollmentcommand.NewCreateEnrollmentTokenCommandHandler,
+	enrollmentcommand.NewRevokeEnrollmentTokenCommandHandler,
 )
 
 // ============================================================================
@@ -152,6 +437,40 @@ var QueryHandlerSet = wire.NewSet(
 	// Run queries
 	runquery.NewGetRunQueryHandler,
 	runquery.NewListRunsQueryHandler,
+
+	// Webhook queries
+	webhookquery.NewGetWebhookQueryHandler,
+	webhookquery.NewListWebhooksQueryHandler,
+	webhookquery.NewListWebhookDeliveriesQueryHandler,
+
+	// Event log queries
+	eventlogquery.NewListEventsQueryHandler,
+	eventlogquery.NewGetAggregateHistoryQueryHandler,
+
+	// Dead letter queries
+	deadletterquery.NewGetDeadLetterQueryHandler,
+	deadletterquery.NewListDeadLettersQueryHandler,
+
+	// Analytics queries
+	analyticsquery.NewGetScenarioAnalyticsQueryHandler,
+	analyticsquery.NewGetFleetAnalyticsQueryHandler,
+
+	// API key queries
+	apikeyquery.NewListAPIKeysQueryHandler,
+
+	// Access queries
+	accessquery.NewListRoleAssignmentsQueryHandler,
+
+	// Secret queries
+	secretquery.NewGetSecretQueryHandler,
+	secretquery.NewListSecretsQueryHandler,
+
+	// Enrollment queries
+	enrollmentquery.NewListEnrollmentTokensQueryHandler,
+
+	// Audit queries
+	auditquery.NewListAuditEntriesQueryHandler,
+	auditquery.NewExportAuditEntriesQueryHandler,
 )
 
 // ============================================================================
@@ -165,19 +484,53 @@ var HTTPHandlerSet = wire.NewSet(
 	handlers.NewTagHandler,
 	handlers.NewScenarioHandler,
 	handlers.NewRunHandler,
+	handlers.NewWebhookHandler,
+	handlers.NewEventHandler,
+	handlers.NewDeadLetterHandler,
+	handlers.NewHealthHandler,
+	handlers.NewAnalyticsHandler,
+	handlers.NewAPIKeyHandler,
+	handlers.NewAccessHandler,
+	handlers.NewEnrollmentHandler,
+	handlers.NewSecretHandler,
+	handlers.NewAuditHandler,
 )
 
 // ============================================================================
 // APPLICATION
 // ============================================================================
 
-// Application holds all HTTP handlers
+// Application holds all HTTP handlers and background workers
 type Application struct {
-	AgentHandler    *handlers.AgentHandler
-	ProxyHandler    *handlers.ProxyHandler
-	TagHandler      *handlers.TagHandler
-	ScenarioHandler *handlers.ScenarioHandler
-	RunHandler      *handlers.RunHandler
+	AgentHandler        *handlers.AgentHandler
+	ProxyHandler        *handlers.ProxyHandler
+	TagHandler          *handlers.TagHandler
+	ScenarioHandler     *handlers.ScenarioHandler
+	RunHandler          *handlers.RunHandler
+	WebhookHandler      *handlers.WebhookHandler
+	EventHandler        *handlers.EventHandler
+	DeadLetterHandler   *handlers.DeadLetterHandler
+	HealthHandler       *handlers.HealthHandler
+	AnalyticsHandler    *handlers.AnalyticsHandler
+	APIKeyHandler       *handlers.APIKeyHandler
+	AccessHandler       *handlers.AccessHandler
+	EnrollmentHandler   *handlers.EnrollmentHandler
+	SecretHandler       *handlers.SecretHandler
+	SecretsEnabled      bool // Secrets are only stored encrypted, so they need an encryption key
+	AuditHandler        *handlers.AuditHandler
+	Authenticator       *auth.Authenticator
+	AuditLog            audit.Repository
+	OutboxRelay         *outbox.Relay
+	EventDispatcher     *events.WorkerPoolEventBus
+	PurgeWorker         *maintenance.PurgeWorker
+	RunCompactor        *maintenance.RunCompactor
+	RollupWorker        *maintenance.RollupWorker
+	WebSocketServer     *ws.Server
+	WebhookWorker       *webhooks.Worker
+	DeadLetterCollector *messaging.DeadLetterCollector
+	AgentConsumer       *consumers.AgentConsumer
+	MessageBroker       ports.MessageBroker
+	Metrics             *metrics.Metrics
 }
 
 // NewApplication creates a new application with all dependencies wired
@@ -187,12 +540,60 @@ func NewApplication(
 	tagHandler *handlers.TagHandler,
 	scenarioHandler *handlers.ScenarioHandler,
 	runHandler *handlers.RunHandler,
+	webhookHandler *handlers.WebhookHandler,
+	eventHandler *handlers.EventHandler,
+	deadLetterHandler *handlers.DeadLetterHandler,
+	healthHandler *handlers.HealthHandler,
+	analyticsHandler *handlers.AnalyticsHandler,
+	apiKeyHandler *handlers.APIKeyHandler,
+	accessHandler *handlers.AccessHandler,
+	enrollmentHandler *handlers.EnrollmentHandler,
+	secretHandler *handlers.SecretHandler,
+	auditHandler *handlers.AuditHandler,
+	authenticator *auth.Authenticator,
+	auditLog audit.Repository,
+	outboxRelay *outbox.Relay,
+	eventDispatcher *events.WorkerPoolEventBus,
+	purgeWorker *maintenance.PurgeWorker,
+	runCompactor *maintenance.RunCompactor,
+	rollupWorker *maintenance.RollupWorker,
+	webSocketServer *ws.Server,
+	webhookWorker *webhooks.Worker,
+	deadLetterCollector *messaging.DeadLetterCollector,
+	agentConsumer *consumers.AgentConsumer,
+	messageBroker ports.MessageBroker,
+	metrics *metrics.Metrics,
+	cipher ports.Cipher,
 ) *Application {
 	return &Application{
-		AgentHandler:    agentHandler,
-		ProxyHandler:    proxyHandler,
-		TagHandler:      tagHandler,
-		ScenarioHandler: scenarioHandler,
-		RunHandler:      runHandler,
+		AgentHandler:        agentHandler,
+		ProxyHandler:        proxyHandler,
+		TagHandler:          tagHandler,
+		ScenarioHandler:     scenarioHandler,
+		RunHandler:          runHandler,
+		WebhookHandler:      webhookHandler,
+		EventHandler:        eventHandler,
+		DeadLetterHandler:   deadLetterHandler,
+		HealthHandler:       healthHandler,
+		AnalyticsHandler:    analyticsHandler,
+		APIKeyHandler:       apiKeyHandler,
+		AccessHandler:       accessHandler,
+		EnrollmentHandler:   enrollmentHandler,
+		SecretHandler:       secretHandler,
+		SecretsEnabled:      cipher.PrimaryKeyID() != "",
+		AuditHandler:        auditHandler,
+		Authenticator:       authenticator,
+		AuditLog:            auditLog,
+		OutboxRelay:         outboxRelay,
+		EventDispatcher:     eventDispatcher,
+		PurgeWorker:         purgeWorker,
+		RunCompactor:        runCompactor,
+		RollupWorker:        rollupWorker,
+		WebSocketServer:     webSocketServer,
+		WebhookWorker:       webhookWorker,
+		DeadLetterCollector: deadLetterCollector,
+		AgentConsumer:       agentConsumer,
+		MessageBroker:       messageBroker,
+		Metrics:             metrics,
 	}
 }
@@ -6,13 +6,43 @@ package container
 import (
 	"github.com/google/wire"
 	"gorm.io/gorm"
+
+	"parrotflow/internal/infrastructure/auth"
+	"parrotflow/internal/infrastructure/encryption"
+	"parrotflow/internal/infrastructure/events"
+	"parrotflow/internal/infrastructure/health"
+	"parrotflow/internal/infrastructure/maintenance"
+	"parrotflow/internal/infrastructure/messaging"
+	"parrotflow/internal/infrastructure/webhooks"
+	"parrotflow/internal/ports"
 )
 
 // InitializeApp creates a fully wired application
-func InitializeApp(db *gorm.DB) (*Application, error) {
+func InitializeApp(db *gorm.DB, maintenanceConfig maintenance.Config, webhookConfig webhooks.Config, eventBusConfig events.WorkerPoolConfig, messagingConfig messaging.Config, healthConfig health.Config, authConfig auth.Config, encryptionConfig encryption.Config) (*Application, error) {
 	wire.Build(
 		// Infrastructure
+		NewEventDispatcher,
+		NewMetrics,
+		ProvideProxyHealthObserver,
+		NewRealtimeHub,
+		NewStreamBus,
+		NewWebSocketServer,
+		NewWebhookWorker,
+		webhooks.NewSink,
+		NewOutboxRelay,
 		NewEventBus,
+		NewPurgeWorker,
+		NewRunCompactor,
+		NewRollupWorker,
+		NewMessageBroker,
+		wire.Bind(new(ports.DeadLetterBroker), new(ports.MessageBroker)),
+		NewDeadLetterCollector,
+		NewAgentConsumer,
+		NewHealthRegistry,
+		NewAuthenticator,
+		encryption.NewKeyring,
+		wire.Bind(new(ports.Cipher), new(*encryption.Keyring)),
+		wire.FieldsOf(new(maintenance.Config), "Purge", "Compaction", "Rollup"),
 
 		// Repositories
 		RepositorySet,
@@ -0,0 +1,189 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run -mod=mod github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package container
+
+import (
+	"gorm.io/gorm"
+	command7 "parrotflow/internal/application/command/access"
+	"parrotflow/internal/application/command/agent"
+	command6 "parrotflow/internal/application/command/apikey"
+	command5 "parrotflow/internal/application/command/deadletter"
+	command8 "parrotflow/internal/application/command/enrollment"
+	"parrotflow/internal/application/command/proxy"
+	command3 "parrotflow/internal/application/command/run"
+	command2 "parrotflow/internal/application/command/scenario"
+	command9 "parrotflow/internal/application/command/secret"
+	"parrotflow/internal/application/command/tag"
+	command4 "parrotflow/internal/application/command/webhook"
+	query9 "parrotflow/internal/application/query/access"
+	agent2 "parrotflow/internal/application/query/agent"
+	query7 "parrotflow/internal/application/query/analytics"
+	query8 "parrotflow/internal/application/query/apikey"
+	query12 "parrotflow/internal/application/query/audit"
+	query6 "parrotflow/internal/application/query/deadletter"
+	query10 "parrotflow/internal/application/query/enrollment"
+	query5 "parrotflow/internal/application/query/eventlog"
+	proxy2 "parrotflow/internal/application/query/proxy"
+	query3 "parrotflow/internal/application/query/run"
+	query2 "parrotflow/internal/application/query/scenario"
+	query11 "parrotflow/internal/application/query/secret"
+	"parrotflow/internal/application/query/tag"
+	query4 "parrotflow/internal/application/query/webhook"
+	"parrotflow/internal/infrastructure/auth"
+	"parrotflow/internal/infrastructure/encryption"
+	"parrotflow/internal/infrastructure/events"
+	"parrotflow/internal/infrastructure/health"
+	"parrotflow/internal/infrastructure/maintenance"
+	"parrotflow/internal/infrastructure/messaging"
+	"parrotflow/internal/infrastructure/persistence"
+	"parrotflow/internal/infrastructure/webhooks"
+	"parrotflow/internal/interfaces/http/handlers"
+)
+
+// Injectors from wire.go:
+
+// InitializeApp creates a fully wired application
+func InitializeApp(db *gorm.DB, maintenanceConfig maintenance.Config, webhookConfig webhooks.Config, eventBusConfig events.WorkerPoolConfig, messagingConfig messaging.Config, healthConfig health.Config, authConfig auth.Config, encryptionConfig encryption.Config) (*Application, error) {
+	keyring, err := encryption.NewKeyring(encryptionConfig)
+	if err != nil {
+		return nil, err
+	}
+	repository := ProvideAgentRepository(db, keyring)
+	enrollmentRepository := ProvideEnrollmentTokenRepository(db)
+	outboxRepository := persistence.NewOutboxRepository(db)
+	runRepository := ProvideRunRepository(db)
+	metrics := NewMetrics(runRepository, repository)
+	secretRepository := ProvideSecretRepository(db, keyring)
+	messageBroker, err := NewMessageBroker(messagingConfig)
+	if err != nil {
+		return nil, err
+	}
+	workerPoolEventBus := NewEventDispatcher(db, eventBusConfig, metrics, runRepository, secretRepository, messageBroker)
+	hub, err := NewRealtimeHub(db)
+	if err != nil {
+		return nil, err
+	}
+	inMemoryEventBus := NewStreamBus(hub)
+	webhookRepository := ProvideWebhookRepository(db, keyring)
+	deliveryRepository := ProvideWebhookDeliveryRepository(db)
+	worker := NewWebhookWorker(webhookRepository, deliveryRepository, webhookConfig)
+	sink := webhooks.NewSink(webhookRepository, deliveryRepository, worker)
+	relay := NewOutboxRelay(outboxRepository, workerPoolEventBus, inMemoryEventBus, sink)
+	eventBus := NewEventBus(outboxRepository, relay, workerPoolEventBus)
+	registerAgentCommandHandler := agent.NewRegisterAgentCommandHandler(repository, enrollmentRepository, eventBus)
+	updateHeartbeatCommandHandler := agent.NewUpdateHeartbeatCommandHandler(repository, eventBus)
+	assignRunCommandHandler := agent.NewAssignRunCommandHandler(repository, eventBus)
+	releaseRunCommandHandler := agent.NewReleaseRunCommandHandler(repository, eventBus)
+	updateAgentCommandHandler := agent.NewUpdateAgentCommandHandler(repository, eventBus)
+	deregisterAgentCommandHandler := agent.NewDeregisterAgentCommandHandler(repository, eventBus)
+	revokeAgentCommandHandler := agent.NewRevokeAgentCommandHandler(repository, eventBus)
+	getAgentQueryHandler := agent2.NewGetAgentQueryHandler(repository)
+	listAgentsQueryHandler := agent2.NewListAgentsQueryHandler(repository)
+	getAvailableAgentsQueryHandler := agent2.NewGetAvailableAgentsQueryHandler(repository)
+	getStaleAgentsQueryHandler := agent2.NewGetStaleAgentsQueryHandler(repository)
+	agentHandler := handlers.NewAgentHandler(registerAgentCommandHandler, updateHeartbeatCommandHandler, assignRunCommandHandler, releaseRunCommandHandler, updateAgentCommandHandler, deregisterAgentCommandHandler, revokeAgentCommandHandler, getAgentQueryHandler, listAgentsQueryHandler, getAvailableAgentsQueryHandler, getStaleAgentsQueryHandler)
+	proxyRepository := ProvideProxyRepository(db, keyring)
+	createProxyCommandHandler := proxy.NewCreateProxyCommandHandler(proxyRepository, eventBus)
+	updateProxyCommandHandler := proxy.NewUpdateProxyCommandHandler(proxyRepository, eventBus)
+	deleteProxyCommandHandler := proxy.NewDeleteProxyCommandHandler(proxyRepository, eventBus)
+	healthObserver := ProvideProxyHealthObserver(metrics)
+	recordHealthCommandHandler := proxy.NewRecordHealthCommandHandler(proxyRepository, eventBus, healthObserver)
+	activateProxyCommandHandler := proxy.NewActivateProxyCommandHandler(proxyRepository, eventBus)
+	deactivateProxyCommandHandler := proxy.NewDeactivateProxyCommandHandler(proxyRepository, eventBus)
+	restoreProxyCommandHandler := proxy.NewRestoreProxyCommandHandler(proxyRepository, eventBus)
+	getProxyQueryHandler := proxy2.NewGetProxyQueryHandler(proxyRepository)
+	listProxiesQueryHandler := proxy2.NewListProxiesQueryHandler(proxyRepository)
+	getActiveProxiesQueryHandler := proxy2.NewGetActiveProxiesQueryHandler(proxyRepository)
+	proxyHandler := handlers.NewProxyHandler(createProxyCommandHandler, updateProxyCommandHandler, deleteProxyCommandHandler, recordHealthCommandHandler, activateProxyCommandHandler, deactivateProxyCommandHandler, restoreProxyCommandHandler, getProxyQueryHandler, listProxiesQueryHandler, getActiveProxiesQueryHandler)
+	tagRepository := ProvideTagRepository(db)
+	createTagCommandHandler := command.NewCreateTagCommandHandler(tagRepository, eventBus)
+	updateTagCommandHandler := command.NewUpdateTagCommandHandler(tagRepository, eventBus)
+	deleteTagCommandHandler := command.NewDeleteTagCommandHandler(tagRepository, eventBus)
+	restoreTagCommandHandler := command.NewRestoreTagCommandHandler(tagRepository, eventBus)
+	getTagQueryHandler := query.NewGetTagQueryHandler(tagRepository)
+	listTagsQueryHandler := query.NewListTagsQueryHandler(tagRepository)
+	tagHandler := handlers.NewTagHandler(createTagCommandHandler, updateTagCommandHandler, deleteTagCommandHandler, restoreTagCommandHandler, getTagQueryHandler, listTagsQueryHandler)
+	scenarioRepository := ProvideScenarioRepository(db)
+	createScenarioCommandHandler := command2.NewCreateScenarioCommandHandler(scenarioRepository, eventBus)
+	updateScenarioCommandHandler := command2.NewUpdateScenarioCommandHandler(scenarioRepository, eventBus)
+	deleteScenarioCommandHandler := command2.NewDeleteScenarioCommandHandler(scenarioRepository, eventBus)
+	restoreScenarioCommandHandler := command2.NewRestoreScenarioCommandHandler(scenarioRepository, eventBus)
+	setScenarioRetentionCommandHandler := command2.NewSetScenarioRetentionCommandHandler(scenarioRepository, eventBus)
+	getScenarioQueryHandler := query2.NewGetScenarioQueryHandler(scenarioRepository)
+	listScenariosQueryHandler := query2.NewListScenariosQueryHandler(scenarioRepository)
+	scenarioHandler := handlers.NewScenarioHandler(createScenarioCommandHandler, updateScenarioCommandHandler, deleteScenarioCommandHandler, restoreScenarioCommandHandler, setScenarioRetentionCommandHandler, getScenarioQueryHandler, listScenariosQueryHandler)
+	createRunCommandHandler := command3.NewCreateRunCommandHandler(runRepository, scenarioRepository, eventBus)
+	startRunCommandHandler := command3.NewStartRunCommandHandler(runRepository, scenarioRepository, secretRepository, eventBus)
+	cancelRunCommandHandler := command3.NewCancelRunCommandHandler(runRepository, eventBus)
+	getRunQueryHandler := query3.NewGetRunQueryHandler(runRepository)
+	listRunsQueryHandler := query3.NewListRunsQueryHandler(runRepository)
+	reportRunProgressCommandHandler := command3.NewReportRunProgressCommandHandler(runRepository, repository, secretRepository, eventBus)
+	runHandler := handlers.NewRunHandler(createRunCommandHandler, startRunCommandHandler, cancelRunCommandHandler, getRunQueryHandler, listRunsQueryHandler, reportRunProgressCommandHandler, hub)
+	createWebhookCommandHandler := command4.NewCreateWebhookCommandHandler(webhookRepository, eventBus)
+	updateWebhookCommandHandler := command4.NewUpdateWebhookCommandHandler(webhookRepository, eventBus)
+	deleteWebhookCommandHandler := command4.NewDeleteWebhookCommandHandler(webhookRepository, eventBus)
+	enableWebhookCommandHandler := command4.NewEnableWebhookCommandHandler(webhookRepository, eventBus)
+	getWebhookQueryHandler := query4.NewGetWebhookQueryHandler(webhookRepository)
+	listWebhooksQueryHandler := query4.NewListWebhooksQueryHandler(webhookRepository)
+	listWebhookDeliveriesQueryHandler := query4.NewListWebhookDeliveriesQueryHandler(webhookRepository, deliveryRepository)
+	webhookHandler := handlers.NewWebhookHandler(createWebhookCommandHandler, updateWebhookCommandHandler, deleteWebhookCommandHandler, enableWebhookCommandHandler, getWebhookQueryHandler, listWebhooksQueryHandler, listWebhookDeliveriesQueryHandler)
+	eventlogRepository := ProvideEventLogRepository(db)
+	listEventsQueryHandler := query5.NewListEventsQueryHandler(eventlogRepository)
+	getAggregateHistoryQueryHandler := query5.NewGetAggregateHistoryQueryHandler(eventlogRepository)
+	eventHandler := handlers.NewEventHandler(listEventsQueryHandler, getAggregateHistoryQueryHandler)
+	deadletterRepository := ProvideDeadLetterRepository(db, keyring)
+	replayDeadLetterCommandHandler := command5.NewReplayDeadLetterCommandHandler(deadletterRepository, messageBroker, eventBus)
+	discardDeadLetterCommandHandler := command5.NewDiscardDeadLetterCommandHandler(deadletterRepository, eventBus)
+	getDeadLetterQueryHandler := query6.NewGetDeadLetterQueryHandler(deadletterRepository)
+	listDeadLettersQueryHandler := query6.NewListDeadLettersQueryHandler(deadletterRepository)
+	deadLetterHandler := handlers.NewDeadLetterHandler(replayDeadLetterCommandHandler, discardDeadLetterCommandHandler, getDeadLetterQueryHandler, listDeadLettersQueryHandler)
+	registry := NewHealthRegistry(db, healthConfig, messageBroker, workerPoolEventBus, repository)
+	healthHandler := handlers.NewHealthHandler(registry)
+	analyticsRepository := ProvideAnalyticsRepository(db)
+	getScenarioAnalyticsQueryHandler := query7.NewGetScenarioAnalyticsQueryHandler(analyticsRepository, scenarioRepository)
+	getFleetAnalyticsQueryHandler := query7.NewGetFleetAnalyticsQueryHandler(analyticsRepository)
+	analyticsHandler := handlers.NewAnalyticsHandler(getScenarioAnalyticsQueryHandler, getFleetAnalyticsQueryHandler)
+	apikeyRepository := ProvideAPIKeyRepository(db)
+	createAPIKeyCommandHandler := command6.NewCreateAPIKeyCommandHandler(apikeyRepository, eventBus)
+	revokeAPIKeyCommandHandler := command6.NewRevokeAPIKeyCommandHandler(apikeyRepository, eventBus)
+	listAPIKeysQueryHandler := query8.NewListAPIKeysQueryHandler(apikeyRepository)
+	apiKeyHandler := handlers.NewAPIKeyHandler(createAPIKeyCommandHandler, revokeAPIKeyCommandHandler, listAPIKeysQueryHandler)
+	accessRepository := ProvideRoleAssignmentRepository(db)
+	assignRolesCommandHandler := command7.NewAssignRolesCommandHandler(accessRepository, apikeyRepository, eventBus)
+	unassignRolesCommandHandler := command7.NewUnassignRolesCommandHandler(accessRepository, eventBus)
+	listRoleAssignmentsQueryHandler := query9.NewListRoleAssignmentsQueryHandler(accessRepository)
+	accessHandler := handlers.NewAccessHandler(assignRolesCommandHandler, unassignRolesCommandHandler, listRoleAssignmentsQueryHandler)
+	createEnrollmentTokenCommandHandler := command8.NewCreateEnrollmentTokenCommandHandler(enrollmentRepository, eventBus)
+	revokeEnrollmentTokenCommandHandler := command8.NewRevokeEnrollmentTokenCommandHandler(enrollmentRepository, eventBus)
+	listEnrollmentTokensQueryHandler := query10.NewListEnrollmentTokensQueryHandler(enrollmentRepository)
+	enrollmentHandler := handlers.NewEnrollmentHandler(createEnrollmentTokenCommandHandler, revokeEnrollmentTokenCommandHandler, listEnrollmentTokensQueryHandler)
+	createSecretCommandHandler := command9.NewCreateSecretCommandHandler(secretRepository, eventBus)
+	updateSecretCommandHandler := command9.NewUpdateSecretCommandHandler(secretRepository, eventBus)
+	deleteSecretCommandHandler := command9.NewDeleteSecretCommandHandler(secretRepository, eventBus)
+	getSecretQueryHandler := query11.NewGetSecretQueryHandler(secretRepository)
+	listSecretsQueryHandler := query11.NewListSecretsQueryHandler(secretRepository)
+	secretHandler := handlers.NewSecretHandler(createSecretCommandHandler, updateSecretCommandHandler, deleteSecretCommandHandler, getSecretQueryHandler, listSecretsQueryHandler)
+	auditRepository := ProvideAuditLogRepository(db)
+	listAuditEntriesQueryHandler := query12.NewListAuditEntriesQueryHandler(auditRepository)
+	exportAuditEntriesQueryHandler := query12.NewExportAuditEntriesQueryHandler(auditRepository)
+	auditHandler := handlers.NewAuditHandler(listAuditEntriesQueryHandler, exportAuditEntriesQueryHandler)
+	authenticator, err := NewAuthenticator(authConfig, apikeyRepository, accessRepository)
+	if err != nil {
+		return nil, err
+	}
+	purgeConfig := maintenanceConfig.Purge
+	purgeWorker := NewPurgeWorker(db, purgeConfig, keyring)
+	compactionConfig := maintenanceConfig.Compaction
+	runCompactor := NewRunCompactor(db, scenarioRepository, compactionConfig)
+	rollupConfig := maintenanceConfig.Rollup
+	rollupWorker := NewRollupWorker(analyticsRepository, rollupConfig)
+	server := NewWebSocketServer(hub)
+	deadLetterCollector := NewDeadLetterCollector(messageBroker, deadletterRepository, messagingConfig)
+	agentConsumer := NewAgentConsumer(messageBroker, runRepository, updateHeartbeatCommandHandler, reportRunProgressCommandHandler, workerPoolEventBus)
+	application := NewApplication(agentHandler, proxyHandler, tagHandler, scenarioHandler, runHandler, webhookHandler, eventHandler, deadLetterHandler, healthHandler, analyticsHandler, apiKeyHandler, accessHandler, enrollmentHandler, secretHandler, auditHandler, authenticator, auditRepository, relay, workerPoolEventBus, purgeWorker, runCompactor, rollupWorker, server, worker, deadLetterCollector, agentConsumer, messageBroker, metrics, keyring)
+	return application, nil
+}
@@ -0,0 +1,115 @@
+package access
+
+import (
+	"errors"
+	"parrotflow/internal/domain/shared"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Domain errors
+var (
+	ErrAssignmentNotFound = errors.New("role assignment not found")
+	ErrUnknownRole        = errors.New("unknown role")
+	ErrNoRoles            = errors.New("a role assignment needs at least one role")
+)
+
+type RoleAssignmentID struct {
+	shared.ID
+}
+
+func NewRoleAssignmentID(value string) (RoleAssignmentID, error) {
+	id, err := shared.NewID(value)
+	if err != nil {
+		return RoleAssignmentID{}, err
+	}
+	return RoleAssignmentID{ID: id}, nil
+}
+
+// RoleAssignment grants roles to a subject: apikey:<id> for API keys, the sub claim
+// of a JWT for people signing in through the identity provider
+type RoleAssignment struct {
+	Id      RoleAssignmentID
+	Subject string
+	Roles   []Role
+
+	CreatedAt shared.Timestamp
+	UpdatedAt shared.Timestamp
+	Version   uint64 // Optimistic concurrency version, 0 until first saved
+	Events    []shared.DomainEvent
+}
+
+func NewRoleAssignment(id RoleAssignmentID, subject string, roles []Role) (*RoleAssignment, error) {
+	subject = strings.TrimSpace(subject)
+	if subject == "" {
+		return nil, errors.New("role assignment subject cannot be empty")
+	}
+	if len(roles) == 0 {
+		return nil, ErrNoRoles
+	}
+
+	a := &RoleAssignment{
+		Id:        id,
+		Subject:   subject,
+		CreatedAt: shared.NewTimestamp(time.Now()),
+		UpdatedAt: shared.NewTimestamp(time.Now()),
+		Events:    make([]shared.DomainEvent, 0),
+	}
+	a.assign(roles)
+	return a, nil
+}
+
+// Assign replaces the subject's roles; assigning the roles it already has changes nothing
+func (a *RoleAssignment) Assign(roles []Role) error {
+	if len(roles) == 0 {
+		return ErrNoRoles
+	}
+	if slices.Equal(a.Roles, roles) {
+		return nil
+	}
+	a.touch()
+	a.assign(roles)
+	return nil
+}
+
+func (a *RoleAssignment) assign(roles []Role) {
+	previous := a.Roles
+	a.Roles = slices.Clone(roles)
+
+	a.addEvent(RolesAssigned{
+		BaseEvent:     shared.NewBaseEvent(EventRolesAssigned, a.Id.String()),
+		Subject:       a.Subject,
+		Roles:         roleNames(a.Roles),
+		PreviousRoles: roleNames(previous),
+	})
+}
+
+// Unassign records that the subject loses its roles; the repository deletes the assignment
+func (a *RoleAssignment) Unassign() {
+	a.addEvent(RolesUnassigned{
+		BaseEvent: shared.NewBaseEvent(EventRolesUnassigned, a.Id.String()),
+		Subject:   a.Subject,
+		Roles:     roleNames(a.Roles),
+	})
+}
+
+func (a *RoleAssignment) touch() {
+	a.UpdatedAt = shared.NewTimestamp(time.Now())
+}
+
+func (a *RoleAssignment) addEvent(event shared.DomainEvent) {
+	a.Events = append(a.Events, event)
+}
+
+func (a *RoleAssignment) ClearEvents() {
+	a.Events = make([]shared.DomainEvent, 0)
+}
+
+func roleNames(roles []Role) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.String()
+	}
+	return names
+}
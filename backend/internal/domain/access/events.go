@@ -0,0 +1,21 @@
+package access
+
+import "parrotflow/internal/domain/shared"
+
+const (
+	EventRolesAssigned   = "access.roles_assigned"
+	EventRolesUnassigned = "access.roles_unassigned"
+)
+
+type RolesAssigned struct {
+	shared.BaseEvent
+	Subject       string
+	Roles         []string
+	PreviousRoles []string
+}
+
+type RolesUnassigned struct {
+	shared.BaseEvent
+	Subject string
+	Roles   []string
+}
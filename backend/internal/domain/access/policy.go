@@ -0,0 +1,149 @@
+package access
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Role is a named set of permissions assigned to a subject
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"   // Looks at everything except credentials
+	RoleOperator Role = "operator" // Runs scenarios and keeps the agent fleet going
+	RoleAuthor   Role = "author"   // Writes scenarios and the proxies, tags and secrets they use
+	RoleAdmin    Role = "admin"    // Everything, including credentials and access
+)
+
+// Roles lists every role, least privileged first
+var Roles = []Role{RoleViewer, RoleOperator, RoleAuthor, RoleAdmin}
+
+func NewRole(value string) (Role, error) {
+	role := Role(strings.ToLower(strings.TrimSpace(value)))
+	if !slices.Contains(Roles, role) {
+		return "", fmt.Errorf("%w %q, expected viewer, operator, author or admin", ErrUnknownRole, value)
+	}
+	return role, nil
+}
+
+func (r Role) String() string {
+	return string(r)
+}
+
+// Permission is what an operation requires
+type Permission string
+
+const (
+	PermissionRead               Permission = "read"                // View scenarios, runs, agents, proxies and the rest
+	PermissionRunsExecute        Permission = "runs:execute"        // Create, start and cancel runs
+	PermissionScenariosEdit      Permission = "scenarios:edit"      // Create, change, delete and restore scenarios
+	PermissionTagsEdit           Permission = "tags:edit"           // Create, change, delete and restore tags
+	PermissionProxiesEdit        Permission = "proxies:edit"        // Create, change, delete and restore proxies
+	PermissionProxiesOperate     Permission = "proxies:operate"     // Activate, deactivate and health-check proxies
+	PermissionProxiesCredentials Permission = "proxies:credentials" // View proxy usernames and passwords
+	PermissionSecretsEdit        Permission = "secrets:edit"        // Create, change and delete secrets, whose values are never shown
+	PermissionAgentsOperate      Permission = "agents:operate"      // Change agents and assign runs to them
+	PermissionAgentsDeregister   Permission = "agents:deregister"   // Remove agents from the fleet and revoke their credentials
+	PermissionAgentsEnroll       Permission = "agents:enroll"       // Mint and revoke the tokens agents register with
+	PermissionDeadLettersManage  Permission = "deadletters:manage"  // Replay and discard dead letters
+	PermissionWebhooksManage     Permission = "webhooks:manage"     // Configure webhooks, which send events elsewhere
+	PermissionAccessManage       Permission = "access:manage"       // Manage API keys and role assignments
+	PermissionAuditRead          Permission = "audit:read"          // Search and export the audit log of changes
+)
+
+func (p Permission) String() string {
+	return string(p)
+}
+
+// policy is the permission table; admin is granted every permission rather than listed here
+var policy = map[Role][]Permission{
+	RoleViewer: {
+		PermissionRead,
+	},
+	RoleOperator: {
+		PermissionRead,
+		PermissionRunsExecute,
+		PermissionProxiesOperate,
+		PermissionAgentsOperate,
+		PermissionDeadLettersManage,
+	},
+	RoleAuthor: {
+		PermissionRead,
+		PermissionRunsExecute,
+		PermissionScenariosEdit,
+		PermissionTagsEdit,
+		PermissionProxiesEdit,
+		PermissionSecretsEdit,
+	},
+}
+
+// Permissions lists every permission in the order they are documented
+var Permissions = []Permission{
+	PermissionRead,
+	PermissionRunsExecute,
+	PermissionScenariosEdit,
+	PermissionTagsEdit,
+	PermissionProxiesEdit,
+	PermissionProxiesOperate,
+	PermissionProxiesCredentials,
+	PermissionSecretsEdit,
+	PermissionAgentsOperate,
+	PermissionAgentsDeregister,
+	PermissionAgentsEnroll,
+	PermissionDeadLettersManage,
+	PermissionWebhooksManage,
+	PermissionAccessManage,
+	PermissionAuditRead,
+}
+
+// Grants reports whether the role has the permission
+func (r Role) Grants(permission Permission) bool {
+	if r == RoleAdmin {
+		return slices.Contains(Permissions, permission)
+	}
+	return slices.Contains(policy[r], permission)
+}
+
+// PermissionsOf returns the permissions a role grants
+func PermissionsOf(role Role) []Permission {
+	if role == RoleAdmin {
+		return slices.Clone(Permissions)
+	}
+	return slices.Clone(policy[role])
+}
+
+// RolesGranting returns the roles that grant a permission, least privileged first
+func RolesGranting(permission Permission) []Role {
+	var roles []Role
+	for _, role := range Roles {
+		if role.Grants(permission) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// AnyGrants reports whether one of the roles has the permission
+func AnyGrants(roles []Role, permission Permission) bool {
+	return slices.ContainsFunc(roles, func(role Role) bool { return role.Grants(permission) })
+}
+
+// NormalizeRoles parses, deduplicates and orders role names
+func NormalizeRoles(values []string) ([]Role, error) {
+	var roles []Role
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		role, err := NewRole(value)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	slices.SortFunc(roles, func(a, b Role) int {
+		return slices.Index(Roles, a) - slices.Index(Roles, b)
+	})
+	return slices.Compact(roles), nil
+}
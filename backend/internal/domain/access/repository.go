@@ -0,0 +1,18 @@
+package access
+
+import "context"
+
+// Repository defines the interface for role assignment persistence
+type Repository interface {
+	// Save persists a role assignment
+	Save(ctx context.Context, assignment *RoleAssignment) error
+
+	// FindBySubject retrieves the role assignment of a subject
+	FindBySubject(ctx context.Context, subject string) (*RoleAssignment, error)
+
+	// FindAll retrieves all role assignments ordered by subject
+	FindAll(ctx context.Context) ([]*RoleAssignment, error)
+
+	// Delete removes a role assignment, recording its pending events
+	Delete(ctx context.Context, assignment *RoleAssignment) error
+}
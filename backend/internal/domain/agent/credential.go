@@ -0,0 +1,142 @@
+package agent
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"parrotflow/internal/domain/shared"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Credential errors
+var (
+	ErrMissingSignature  = errors.New("agent request is not signed")
+	ErrInvalidSignature  = errors.New("agent signature mismatch")
+	ErrStaleSignature    = errors.New("agent signature timestamp outside tolerance")
+	ErrReplayedSignature = errors.New("agent signature nonce was already used")
+	ErrNoCredential      = errors.New("agent has no credential, enroll it again")
+	ErrAgentRevoked      = errors.New("agent is revoked")
+)
+
+// SignatureTolerance is how far a signature timestamp may drift from the server clock
+const SignatureTolerance = 5 * time.Minute
+
+// NonceRetention is how long a used nonce is remembered: a signature stays fresh for
+// SignatureTolerance after its timestamp, which may itself be SignatureTolerance ahead
+const NonceRetention = 2 * SignatureTolerance
+
+// MaxNonceLength bounds the nonces agents sign their messages with
+const MaxNonceLength = 64
+
+const signaturePrefix = "sha256="
+
+// Purposes an agent signs messages for, so a signature for one cannot be replayed as another
+const PurposeHeartbeat = "heartbeat"
+
+// PurposeProgress is the purpose of progress reports for a run
+func PurposeProgress(runID string) string {
+	return "progress." + runID
+}
+
+// Credential is the secret an agent received when it enrolled and signs its messages with
+type Credential struct {
+	Secret    string
+	IssuedAt  time.Time
+	RevokedAt *time.Time
+}
+
+// EnrolledAgent is a newly registered agent together with its credential secret,
+// which is handed out once and cannot be read back afterwards
+type EnrolledAgent struct {
+	Agent  *Agent
+	Secret string
+}
+
+// Signature is what an agent sends along a signed message
+type Signature struct {
+	Timestamp string // Unix seconds the message was signed at
+	Nonce     string // Unique per message, a message carrying a used one is a replay
+	Value     string // sha256=<hex>
+}
+
+// Sign computes the signature of a message body for a purpose at timestamp (unix seconds):
+//
+//	sha256=hex(HMAC-SHA256(secret, "<timestamp>.<nonce>.<purpose>.<body>"))
+func Sign(secret string, timestamp int64, nonce, purpose string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("." + nonce + "." + purpose + "."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// IssueCredential generates a new credential, replacing any previous one
+// The secret is returned so it can be handed to the agent once
+func (a *Agent) IssueCredential() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	secret := hex.EncodeToString(buf)
+	a.Credential = &Credential{Secret: secret, IssuedAt: time.Now()}
+	a.UpdatedAt = shared.NewTimestamp(time.Now())
+	return secret, nil
+}
+
+// IsRevoked reports whether the agent's credential was revoked
+func (a *Agent) IsRevoked() bool {
+	return a.Credential != nil && a.Credential.RevokedAt != nil
+}
+
+// VerifySignature checks that a message for purpose was signed with the agent's credential
+// Whether the nonce was used before is up to the caller, see Repository.UseNonce
+func (a *Agent) VerifySignature(purpose string, signature Signature, body []byte, now time.Time) error {
+	if a.Credential == nil {
+		return ErrNoCredential
+	}
+	if a.Credential.RevokedAt != nil {
+		return ErrAgentRevoked
+	}
+	if signature.Timestamp == "" || signature.Nonce == "" || signature.Value == "" {
+		return ErrMissingSignature
+	}
+	if len(signature.Nonce) > MaxNonceLength {
+		return ErrInvalidSignature
+	}
+	ts, err := strconv.ParseInt(signature.Timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if now.Sub(time.Unix(ts, 0)).Abs() > SignatureTolerance {
+		return ErrStaleSignature
+	}
+	if !strings.HasPrefix(signature.Value, signaturePrefix) ||
+		!hmac.Equal([]byte(signature.Value), []byte(Sign(a.Credential.Secret, ts, signature.Nonce, purpose, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Revoke invalidates the agent's credential and deregisters it
+func (a *Agent) Revoke() error {
+	if a.IsRevoked() {
+		return ErrAgentRevoked
+	}
+	now := time.Now()
+	if a.Credential == nil {
+		a.Credential = &Credential{}
+	}
+	a.Credential.RevokedAt = &now
+	a.Deregister()
+
+	a.addEvent(AgentRevoked{
+		BaseEvent: shared.NewBaseEvent(EventAgentRevoked, a.Id.String()),
+		AgentID:   a.Id.String(),
+		RevokedAt: now,
+	})
+	return nil
+}
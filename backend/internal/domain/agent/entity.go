@@ -66,6 +66,8 @@ type Agent struct {
 	UpdatedAt         shared.Timestamp
 	ConnectionInfo    ConnectionInfo
 	Metadata          map[string]interface{} // Extensible metadata
+	Credential        *Credential            // Set once the agent enrolled with a token
+	Version           uint64                 // Optimistic concurrency version, 0 until first saved
 	Events            []shared.DomainEvent
 }
 
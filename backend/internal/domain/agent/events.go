@@ -11,6 +11,7 @@ const (
 	EventAgentStatusChanged        = "agent.status.changed"
 	EventAgentDisconnected         = "agent.disconnected"
 	EventAgentCapabilitiesUpdated  = "agent.capabilities.updated"
+	EventAgentRevoked              = "agent.revoked"
 )
 
 type AgentRegistered struct {
@@ -43,3 +44,9 @@ type AgentCapabilitiesUpdated struct {
 	shared.BaseEvent
 	AgentID string
 }
+
+type AgentRevoked struct {
+	shared.BaseEvent
+	AgentID   string
+	RevokedAt time.Time
+}
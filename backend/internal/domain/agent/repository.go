@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	"parrotflow/internal/domain/enrollment"
 	"parrotflow/internal/domain/tag"
 	"time"
 )
@@ -21,6 +22,10 @@ type Repository interface {
 	// Save persists an agent
 	Save(ctx context.Context, agent *Agent) error
 
+	// Enroll persists a new agent and redeems the enrollment token it registered with
+	// in one transaction; nothing is stored when the token was redeemed concurrently
+	Enroll(ctx context.Context, agent *Agent, token *enrollment.Token) error
+
 	// FindByID retrieves an agent by its ID
 	FindByID(ctx context.Context, id AgentID) (*Agent, error)
 
@@ -60,4 +65,8 @@ type Repository interface {
 
 	// ExistsByName checks if an agent with the given name already exists
 	ExistsByName(ctx context.Context, name string) (bool, error)
+
+	// UseNonce records the nonce of a verified signature of the agent, failing with
+	// ErrReplayedSignature when the agent used it before; nonces are remembered for NonceRetention
+	UseNonce(ctx context.Context, id AgentID, nonce string, now time.Time) error
 }
@@ -0,0 +1,116 @@
+package analytics
+
+import (
+	"errors"
+	"time"
+)
+
+// Domain errors
+var (
+	ErrInvalidCriteria  = errors.New("invalid analytics criteria")
+	ErrScenarioNotFound = errors.New("scenario not found")
+)
+
+// Widths of the time buckets of a report
+const (
+	IntervalHour = "hour"
+	IntervalDay  = "day"
+	IntervalWeek = "week"
+)
+
+// Where the run counts of a report, overall and per time bucket, are computed from
+const (
+	SourceRuns   = "runs"   // The scenario_runs rows, exact but limited to runs not compacted yet
+	SourceRollup = "rollup" // The daily run_rollups table, which outlives run retention
+)
+
+// Criteria selects the runs a report covers
+// Since is inclusive and Until exclusive; an empty ScenarioID covers the whole fleet
+type Criteria struct {
+	ScenarioID string
+	Since      time.Time
+	Until      time.Time
+	Interval   string
+	Source     string
+	Limit      int // Of failure reasons, failing nodes and scenarios
+}
+
+// Counts are runs by outcome
+type Counts struct {
+	Total     int64
+	Completed int64
+	Failed    int64
+	Cancelled int64
+}
+
+// SuccessRate is the share of completed runs among the runs that completed or failed
+// Cancelled and unfinished runs say nothing about the scenario, so they are left out
+func (c Counts) SuccessRate() float64 {
+	if c.Completed+c.Failed == 0 {
+		return 0
+	}
+	return float64(c.Completed) / float64(c.Completed+c.Failed)
+}
+
+// FailureRate is the share of failed runs among the runs that completed or failed
+func (c Counts) FailureRate() float64 {
+	if c.Completed+c.Failed == 0 {
+		return 0
+	}
+	return float64(c.Failed) / float64(c.Completed+c.Failed)
+}
+
+// Durations are nearest-rank percentiles of the durations of finished runs
+type Durations struct {
+	Measured int64 // Runs with both a start and a finish time
+	P50      time.Duration
+	P95      time.Duration
+}
+
+// Bucket is one period of a report's time series
+type Bucket struct {
+	Start time.Time
+	Counts
+	Durations // Zero for buckets read from the rollup, which keeps no percentiles
+}
+
+// FailureReason is a reason failed runs gave, with how often it was given
+type FailureReason struct {
+	Reason     string
+	Count      int64
+	LastSeenAt time.Time
+}
+
+// NodeFailures is how often a scenario node failed in the runs that reached it
+type NodeFailures struct {
+	ScenarioID  string
+	NodeID      string
+	Runs        int64
+	Failures    int64
+	LastMessage string // Message of the latest failure
+}
+
+// FailureRate is the share of runs reaching the node in which it failed
+func (n NodeFailures) FailureRate() float64 {
+	if n.Runs == 0 {
+		return 0
+	}
+	return float64(n.Failures) / float64(n.Runs)
+}
+
+// ScenarioStats are the outcomes of one scenario, for fleet-wide reports
+type ScenarioStats struct {
+	ScenarioID string
+	Counts
+}
+
+// Report answers how reliable and fast runs were over a period
+type Report struct {
+	Criteria       Criteria // With the defaults that were applied
+	Counts         Counts
+	Durations      Durations
+	Buckets        []Bucket
+	FailureReasons []FailureReason // Most frequent first
+	FailingNodes   []NodeFailures  // Most failures first
+	Scenarios      []ScenarioStats // Fleet-wide reports only, highest failure rate first
+}
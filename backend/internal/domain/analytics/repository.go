@@ -0,0 +1,15 @@
+package analytics
+
+import (
+	"context"
+	"time"
+)
+
+// Repository computes reports with aggregate queries over the runs and their node steps
+type Repository interface {
+	// Report computes the report of the runs matching the criteria
+	Report(ctx context.Context, criteria Criteria) (*Report, error)
+
+	// RefreshRollup recomputes the daily rollup for the days from since on
+	RefreshRollup(ctx context.Context, since time.Time) error
+}
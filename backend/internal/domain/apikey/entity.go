@@ -0,0 +1,260 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"parrotflow/internal/domain/shared"
+	"strings"
+	"time"
+)
+
+// Domain errors
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrAPIKeyRevoked  = errors.New("api key is revoked")
+	ErrAPIKeyExpired  = errors.New("api key is expired")
+	ErrInvalidKey     = errors.New("invalid api key")
+	ErrExpiryInPast   = errors.New("api key expiry must be in the future")
+)
+
+// TokenPrefix starts every API key, so leaked keys are easy to recognize
+const TokenPrefix = "pf_"
+
+// SubjectPrefix starts the subject a key authenticates as, see Subject
+const SubjectPrefix = "apikey:"
+
+// Scopes limit what a key may do; each scope includes the ones below it
+const (
+	ScopeRead  = "read"  // Queries
+	ScopeWrite = "write" // Commands
+	ScopeAdmin = "admin" // Managing credentials
+)
+
+var scopeRanks = map[string]int{
+	ScopeRead:  1,
+	ScopeWrite: 2,
+	ScopeAdmin: 3,
+}
+
+type APIKeyID struct {
+	shared.ID
+}
+
+func NewAPIKeyID(value string) (APIKeyID, error) {
+	id, err := shared.NewID(value)
+	if err != nil {
+		return APIKeyID{}, err
+	}
+	return APIKeyID{ID: id}, nil
+}
+
+// APIKey is a long-lived credential for scripts and integrations
+// Only a hash of the key is kept; the key itself is shown once, when it is created
+type APIKey struct {
+	Id        APIKeyID
+	Name      string
+	Prefix    string   // Public part of the key, used to look it up
+	Hash      string   // Hex SHA-256 of the whole key
+	Scopes    []string // Granted scopes, see ScopeRead, ScopeWrite and ScopeAdmin
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+
+	// LastUsedAt is refreshed at most once a minute, see NeedsUsageUpdate
+	LastUsedAt *time.Time
+
+	CreatedAt shared.Timestamp
+	UpdatedAt shared.Timestamp
+	Version   uint64 // Optimistic concurrency version, 0 until first saved
+	Events    []shared.DomainEvent
+}
+
+// UsageUpdateInterval bounds how often authenticating with a key writes its last use
+const UsageUpdateInterval = time.Minute
+
+// NewAPIKey creates an active key from a token issued by GenerateToken
+func NewAPIKey(id APIKeyID, name, token string, scopes []string, expiresAt *time.Time) (*APIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("api key name cannot be empty")
+	}
+	prefix, err := ParsePrefix(token)
+	if err != nil {
+		return nil, err
+	}
+	normalized, err := NormalizeScopes(scopes)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, ErrExpiryInPast
+	}
+
+	k := &APIKey{
+		Id:        id,
+		Name:      name,
+		Prefix:    prefix,
+		Hash:      HashToken(token),
+		Scopes:    normalized,
+		ExpiresAt: expiresAt,
+		CreatedAt: shared.NewTimestamp(time.Now()),
+		UpdatedAt: shared.NewTimestamp(time.Now()),
+		Events:    make([]shared.DomainEvent, 0),
+	}
+
+	k.addEvent(APIKeyCreated{
+		BaseEvent: shared.NewBaseEvent(EventAPIKeyCreated, id.String()),
+		APIKeyID:  id.String(),
+		Name:      name,
+		Scopes:    normalized,
+	})
+
+	return k, nil
+}
+
+// IssuedKey is a new key together with its plaintext, which is not stored anywhere
+type IssuedKey struct {
+	APIKey *APIKey
+	Token  string
+}
+
+// Issue generates a key and creates the API key it authenticates as
+func Issue(id APIKeyID, name string, scopes []string, expiresAt *time.Time) (*IssuedKey, error) {
+	token, err := GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	k, err := NewAPIKey(id, name, token, scopes, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &IssuedKey{APIKey: k, Token: token}, nil
+}
+
+// GenerateToken creates a random key of the form pf_<prefix>_<secret>
+func GenerateToken() (string, error) {
+	prefix := make([]byte, 6)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(prefix); err != nil {
+		return "", err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return TokenPrefix + hex.EncodeToString(prefix) + "_" + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// ParsePrefix extracts the lookup prefix of a key
+func ParsePrefix(token string) (string, error) {
+	rest, ok := strings.CutPrefix(token, TokenPrefix)
+	if !ok {
+		return "", ErrInvalidKey
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || prefix == "" || secret == "" {
+		return "", ErrInvalidKey
+	}
+	return prefix, nil
+}
+
+// HashToken hashes a key for storage; keys are random, so a fast hash is enough
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify checks a presented key against this one and reports why it cannot be used
+func (k *APIKey) Verify(token string, now time.Time) error {
+	if subtle.ConstantTimeCompare([]byte(HashToken(token)), []byte(k.Hash)) != 1 {
+		return ErrInvalidKey
+	}
+	if k.RevokedAt != nil {
+		return ErrAPIKeyRevoked
+	}
+	if k.ExpiresAt != nil && !now.Before(*k.ExpiresAt) {
+		return ErrAPIKeyExpired
+	}
+	return nil
+}
+
+// Allows reports whether the key was granted the scope or a higher one
+func (k *APIKey) Allows(scope string) bool {
+	return ScopesAllow(k.Scopes, scope)
+}
+
+// Subject names the key wherever principals are named, such as role assignments
+func (k *APIKey) Subject() string {
+	return SubjectPrefix + k.Id.String()
+}
+
+// NeedsUsageUpdate reports whether a use at now should be recorded
+func (k *APIKey) NeedsUsageUpdate(now time.Time) bool {
+	return k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= UsageUpdateInterval
+}
+
+// Revoke disables the key for good
+func (k *APIKey) Revoke() {
+	if k.RevokedAt != nil {
+		return
+	}
+	now := time.Now()
+	k.RevokedAt = &now
+	k.touch()
+
+	k.addEvent(APIKeyRevoked{
+		BaseEvent: shared.NewBaseEvent(EventAPIKeyRevoked, k.Id.String()),
+		APIKeyID:  k.Id.String(),
+		Name:      k.Name,
+	})
+}
+
+func (k *APIKey) touch() {
+	k.UpdatedAt = shared.NewTimestamp(time.Now())
+}
+
+func (k *APIKey) addEvent(event shared.DomainEvent) {
+	k.Events = append(k.Events, event)
+}
+
+func (k *APIKey) ClearEvents() {
+	k.Events = make([]shared.DomainEvent, 0)
+}
+
+// ScopesAllow reports whether granted scopes include the required one or a higher one
+func ScopesAllow(granted []string, required string) bool {
+	need, ok := scopeRanks[required]
+	if !ok {
+		return false
+	}
+	for _, scope := range granted {
+		if scopeRanks[scope] >= need {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeScopes trims and deduplicates scopes and rejects unknown ones
+func NormalizeScopes(scopes []string) ([]string, error) {
+	normalized := make([]string, 0, len(scopes))
+	seen := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if scope == "" || seen[scope] {
+			continue
+		}
+		if _, ok := scopeRanks[scope]; !ok {
+			return nil, fmt.Errorf("unknown api key scope %q, expected %s, %s or %s", scope, ScopeRead, ScopeWrite, ScopeAdmin)
+		}
+		seen[scope] = true
+		normalized = append(normalized, scope)
+	}
+	if len(normalized) == 0 {
+		return nil, errors.New("api key must have at least one scope")
+	}
+	return normalized, nil
+}
@@ -0,0 +1,21 @@
+package apikey
+
+import "parrotflow/internal/domain/shared"
+
+const (
+	EventAPIKeyCreated = "apikey.created"
+	EventAPIKeyRevoked = "apikey.revoked"
+)
+
+type APIKeyCreated struct {
+	shared.BaseEvent
+	APIKeyID string
+	Name     string
+	Scopes   []string
+}
+
+type APIKeyRevoked struct {
+	shared.BaseEvent
+	APIKeyID string
+	Name     string
+}
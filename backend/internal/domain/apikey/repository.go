@@ -0,0 +1,24 @@
+package apikey
+
+import (
+	"context"
+	"time"
+)
+
+// Repository defines the interface for API key persistence
+type Repository interface {
+	// Save persists an API key
+	Save(ctx context.Context, key *APIKey) error
+
+	// FindByID retrieves an API key by its ID
+	FindByID(ctx context.Context, id APIKeyID) (*APIKey, error)
+
+	// FindByPrefix retrieves the API key a presented key claims to be
+	FindByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+
+	// FindAll retrieves all API keys, including revoked and expired ones
+	FindAll(ctx context.Context) ([]*APIKey, error)
+
+	// RecordUsage stores when a key was last used without touching its version
+	RecordUsage(ctx context.Context, id APIKeyID, usedAt time.Time) error
+}
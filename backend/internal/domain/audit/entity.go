@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Outcomes of an audited call, derived from its response status
+const (
+	OutcomeSuccess = "success" // The call was served
+	OutcomeDenied  = "denied"  // Credentials were missing, invalid or not allowed to make the call
+	OutcomeFailure = "failure" // The call was rejected or failed
+)
+
+// Outcomes lists every outcome
+var Outcomes = []string{OutcomeSuccess, OutcomeDenied, OutcomeFailure}
+
+// ActorAnonymous is the actor of calls made without credentials, such as agents
+// registering with an enrollment token
+const ActorAnonymous = "anonymous"
+
+// Entry is one mutating API call as recorded in the audit log
+// Entries are append-only; Sequence orders them in the order they were recorded
+type Entry struct {
+	Sequence      uint64
+	OccurredAt    time.Time
+	Actor         string // Subject of the caller, e.g. apikey:3, or ActorAnonymous
+	ActorName     string // Key name, or the name claim of a JWT when present
+	AuthMethod    string
+	OperationID   string
+	Method        string
+	Path          string
+	ResourceType  string          // First path segment after /api, e.g. runs
+	ResourceID    string          // From the path, or the response of calls creating a resource
+	Request       json.RawMessage // Redacted request body, nil when there was none
+	Status        int
+	Outcome       string
+	Error         string // Detail of error responses
+	CorrelationID string
+	Duration      time.Duration
+}
+
+// OutcomeOf classifies a response status
+func OutcomeOf(status int) string {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return OutcomeDenied
+	case status >= http.StatusBadRequest:
+		return OutcomeFailure
+	default:
+		return OutcomeSuccess
+	}
+}
+
+// Criteria selects a page of the audit log
+// Zero values leave a filter out; Since is inclusive and Until exclusive
+type Criteria struct {
+	Actor        string
+	OperationIDs []string
+	ResourceType string
+	ResourceID   string
+	Outcome      string
+	Since        time.Time
+	Until        time.Time
+	OrderDir     string // asc for the order entries were recorded in, newest first otherwise
+	Limit        int
+	Offset       int
+}
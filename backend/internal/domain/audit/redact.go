@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Redacted replaces the values of sensitive fields in request summaries
+const Redacted = "[REDACTED]"
+
+// MaxRequestBytes bounds the request bodies summarized; larger ones are only noted
+const MaxRequestBytes = 64 << 10
+
+// sensitiveWords mark field names whose values are always redacted, whatever the operation
+var sensitiveWords = []string{"password", "secret", "token", "credential", "authorization", "key"}
+
+// Summarize returns the JSON request body with the values of sensitive fields and
+// of the operation's extra fields replaced by Redacted
+// Bodies that are too large or not JSON are replaced by a note, empty ones give nil
+func Summarize(body []byte, fields ...string) json.RawMessage {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil
+	}
+	if len(body) > MaxRequestBytes {
+		return note(fmt.Sprintf("request body of more than %d bytes omitted", MaxRequestBytes))
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return note(fmt.Sprintf("request body of %d bytes omitted, it is not JSON", len(body)))
+	}
+
+	summary, err := json.Marshal(redact(value, fields))
+	if err != nil {
+		return note("request body omitted, it could not be summarized")
+	}
+	return summary
+}
+
+func redact(value any, fields []string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		// Name/value pairs, such as scenario parameters, are sensitive when the name is
+		if name, ok := v["name"].(string); ok && isSensitive(name, nil) {
+			if _, ok := v["value"]; ok {
+				v["value"] = Redacted
+			}
+		}
+		for key, item := range v {
+			if item != nil && isSensitive(key, fields) {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = redact(item, fields)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redact(item, fields)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+func isSensitive(key string, fields []string) bool {
+	key = strings.ToLower(key)
+	for _, field := range fields {
+		if strings.EqualFold(key, field) {
+			return true
+		}
+	}
+	for _, word := range sensitiveWords {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func note(text string) json.RawMessage {
+	encoded, _ := json.Marshal(text)
+	return encoded
+}
@@ -0,0 +1,23 @@
+package audit
+
+import (
+	"context"
+	"parrotflow/internal/domain/shared"
+)
+
+// Repository appends to and reads the audit log, which offers no way to change
+// or remove entries
+type Repository interface {
+	// Append records an entry and sets its Sequence
+	Append(ctx context.Context, entry *Entry) error
+
+	// Find retrieves a page of entries matching the criteria, in recording order
+	Find(ctx context.Context, criteria Criteria) (shared.Page[*Entry], error)
+
+	// Each calls fn with every entry matching the criteria, in recording order,
+	// reading them in batches; limit and offset are ignored
+	Each(ctx context.Context, criteria Criteria, fn func(*Entry) error) error
+}
+
+// Export writes matching entries one at a time with write until they run out or write fails
+type Export func(write func(*Entry) error) error
@@ -0,0 +1,144 @@
+package deadletter
+
+import (
+	"errors"
+	"parrotflow/internal/domain/shared"
+	"strings"
+	"time"
+)
+
+// Domain errors
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	ErrAlreadyResolved    = errors.New("dead letter was already replayed or discarded")
+)
+
+type DeadLetterID struct {
+	shared.ID
+}
+
+func NewDeadLetterID(value string) (DeadLetterID, error) {
+	id, err := shared.NewID(value)
+	if err != nil {
+		return DeadLetterID{}, err
+	}
+	return DeadLetterID{ID: id}, nil
+}
+
+// Status tells whether a dead letter still waits for an operator
+type Status struct {
+	value string
+}
+
+func NewStatus(value string) (Status, error) {
+	switch strings.ToLower(value) {
+	case "pending":
+		return StatusPending, nil
+	case "replayed":
+		return StatusReplayed, nil
+	case "discarded":
+		return StatusDiscarded, nil
+	default:
+		return Status{}, errors.New("invalid dead letter status")
+	}
+}
+
+func (s Status) String() string {
+	return s.value
+}
+
+var (
+	StatusPending   = Status{value: "pending"}   // Waiting to be replayed or discarded
+	StatusReplayed  = Status{value: "replayed"}  // Published again to its original queue
+	StatusDiscarded = Status{value: "discarded"} // Dropped by an operator
+)
+
+// DeadLetter is a broker message that a consumer rejected, collected from the
+// dead-letter queue so it can be inspected, then replayed or discarded
+type DeadLetter struct {
+	Id             DeadLetterID
+	MessageID      string // Broker message ID, empty if the publisher set none
+	Queue          string // Queue the message was rejected from
+	Reason         string // Why the broker dead-lettered it: rejected, expired, maxlen or delivery_limit
+	ContentType    string
+	Headers        map[string]string
+	Payload        []byte
+	DeathCount     int // Times the message has been dead-lettered from Queue
+	DeadLetteredAt time.Time
+
+	Status     Status
+	ResolvedAt *time.Time
+
+	CreatedAt shared.Timestamp
+	UpdatedAt shared.Timestamp
+	Version   uint64 // Optimistic concurrency version, 0 until first saved
+	Events    []shared.DomainEvent
+}
+
+// NewDeadLetter creates a pending dead letter for a rejected message
+func NewDeadLetter(id DeadLetterID, queue, reason string, payload []byte, deadLetteredAt time.Time) (*DeadLetter, error) {
+	if queue == "" {
+		return nil, errors.New("dead letter queue cannot be empty")
+	}
+
+	return &DeadLetter{
+		Id:             id,
+		Queue:          queue,
+		Reason:         reason,
+		Headers:        make(map[string]string),
+		Payload:        payload,
+		DeathCount:     1,
+		DeadLetteredAt: deadLetteredAt,
+		Status:         StatusPending,
+		CreatedAt:      shared.NewTimestamp(time.Now()),
+		UpdatedAt:      shared.NewTimestamp(time.Now()),
+		Events:         make([]shared.DomainEvent, 0),
+	}, nil
+}
+
+// MarkReplayed records that the message was published to its queue again
+func (d *DeadLetter) MarkReplayed() error {
+	if err := d.resolve(StatusReplayed); err != nil {
+		return err
+	}
+	d.addEvent(DeadLetterReplayed{
+		BaseEvent:    shared.NewBaseEvent(EventDeadLetterReplayed, d.Id.String()),
+		DeadLetterID: d.Id.String(),
+		Queue:        d.Queue,
+		MessageID:    d.MessageID,
+	})
+	return nil
+}
+
+// Discard drops the message for good
+func (d *DeadLetter) Discard() error {
+	if err := d.resolve(StatusDiscarded); err != nil {
+		return err
+	}
+	d.addEvent(DeadLetterDiscarded{
+		BaseEvent:    shared.NewBaseEvent(EventDeadLetterDiscarded, d.Id.String()),
+		DeadLetterID: d.Id.String(),
+		Queue:        d.Queue,
+		MessageID:    d.MessageID,
+	})
+	return nil
+}
+
+func (d *DeadLetter) resolve(status Status) error {
+	if d.Status != StatusPending {
+		return ErrAlreadyResolved
+	}
+	now := time.Now()
+	d.Status = status
+	d.ResolvedAt = &now
+	d.UpdatedAt = shared.NewTimestamp(now)
+	return nil
+}
+
+func (d *DeadLetter) addEvent(event shared.DomainEvent) {
+	d.Events = append(d.Events, event)
+}
+
+func (d *DeadLetter) ClearEvents() {
+	d.Events = make([]shared.DomainEvent, 0)
+}
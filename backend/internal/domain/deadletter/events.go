@@ -0,0 +1,22 @@
+package deadletter
+
+import "parrotflow/internal/domain/shared"
+
+const (
+	EventDeadLetterReplayed  = "deadletter.replayed"
+	EventDeadLetterDiscarded = "deadletter.discarded"
+)
+
+type DeadLetterReplayed struct {
+	shared.BaseEvent
+	DeadLetterID string
+	Queue        string
+	MessageID    string
+}
+
+type DeadLetterDiscarded struct {
+	shared.BaseEvent
+	DeadLetterID string
+	Queue        string
+	MessageID    string
+}
@@ -0,0 +1,26 @@
+package deadletter
+
+import (
+	"context"
+	"parrotflow/internal/domain/shared"
+)
+
+// Criteria selects one page of dead letters, newest first
+type Criteria struct {
+	Queue  string
+	Status *Status
+	Limit  int
+	Offset int
+}
+
+// Repository defines the interface for dead letter persistence
+type Repository interface {
+	// Save persists a dead letter
+	Save(ctx context.Context, deadLetter *DeadLetter) error
+
+	// FindByID retrieves a dead letter by its ID
+	FindByID(ctx context.Context, id DeadLetterID) (*DeadLetter, error)
+
+	// Find retrieves a page of dead letters matching the criteria
+	Find(ctx context.Context, criteria Criteria) (shared.Page[*DeadLetter], error)
+}
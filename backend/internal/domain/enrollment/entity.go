@@ -0,0 +1,202 @@
+package enrollment
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"parrotflow/internal/domain/shared"
+	"strings"
+	"time"
+)
+
+// Domain errors
+var (
+	ErrTokenNotFound = errors.New("enrollment token not found")
+	ErrTokenUsed     = errors.New("enrollment token was already used")
+	ErrTokenRevoked  = errors.New("enrollment token is revoked")
+	ErrTokenExpired  = errors.New("enrollment token is expired")
+	ErrInvalidToken  = errors.New("invalid enrollment token")
+	ErrExpiryInPast  = errors.New("enrollment token expiry must be in the future")
+)
+
+// TokenPrefix starts every enrollment token, telling them apart from API keys (pf_)
+const TokenPrefix = "pfe_"
+
+// DefaultTTL is how long a token works when no expiry is given
+const DefaultTTL = 24 * time.Hour
+
+type TokenID struct {
+	shared.ID
+}
+
+func NewTokenID(value string) (TokenID, error) {
+	id, err := shared.NewID(value)
+	if err != nil {
+		return TokenID{}, err
+	}
+	return TokenID{ID: id}, nil
+}
+
+// Token lets one agent register; the agent exchanges it for its own credential
+// Only a hash of the token is kept, the token itself is shown once, when it is created
+type Token struct {
+	Id        TokenID
+	Name      string // What the token is for, e.g. the host the agent will run on
+	Prefix    string // Public part of the token, used to look it up
+	Hash      string // Hex SHA-256 of the whole token
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	UsedAt    *time.Time
+	AgentID   string // Agent that registered with the token, empty until used
+
+	CreatedAt shared.Timestamp
+	UpdatedAt shared.Timestamp
+	Version   uint64 // Optimistic concurrency version, 0 until first saved
+	Events    []shared.DomainEvent
+}
+
+// IssuedToken is a new token together with its plaintext, which is not stored anywhere
+type IssuedToken struct {
+	Token     *Token
+	Plaintext string
+}
+
+// Issue generates an enrollment token valid until expiresAt, DefaultTTL from now when nil
+func Issue(id TokenID, name string, expiresAt *time.Time) (*IssuedToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("enrollment token name cannot be empty")
+	}
+	now := time.Now()
+	expiry := now.Add(DefaultTTL)
+	if expiresAt != nil {
+		if !expiresAt.After(now) {
+			return nil, ErrExpiryInPast
+		}
+		expiry = *expiresAt
+	}
+
+	plaintext, prefix, err := generate()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Token{
+		Id:        id,
+		Name:      name,
+		Prefix:    prefix,
+		Hash:      hashToken(plaintext),
+		ExpiresAt: expiry,
+		CreatedAt: shared.NewTimestamp(now),
+		UpdatedAt: shared.NewTimestamp(now),
+		Events:    make([]shared.DomainEvent, 0),
+	}
+
+	t.addEvent(TokenCreated{
+		BaseEvent: shared.NewBaseEvent(EventTokenCreated, id.String()),
+		TokenID:   id.String(),
+		Name:      name,
+		ExpiresAt: expiry,
+	})
+
+	return &IssuedToken{Token: t, Plaintext: plaintext}, nil
+}
+
+// generate creates a random token of the form pfe_<prefix>_<secret>
+func generate() (string, string, error) {
+	prefix := make([]byte, 6)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(prefix); err != nil {
+		return "", "", err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	encoded := hex.EncodeToString(prefix)
+	return TokenPrefix + encoded + "_" + base64.RawURLEncoding.EncodeToString(secret), encoded, nil
+}
+
+// ParsePrefix extracts the lookup prefix of a token
+func ParsePrefix(plaintext string) (string, error) {
+	rest, ok := strings.CutPrefix(plaintext, TokenPrefix)
+	if !ok {
+		return "", ErrInvalidToken
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || prefix == "" || secret == "" {
+		return "", ErrInvalidToken
+	}
+	return prefix, nil
+}
+
+func hashToken(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify checks a presented token against this one and reports why it cannot be used
+func (t *Token) Verify(plaintext string, now time.Time) error {
+	if subtle.ConstantTimeCompare([]byte(hashToken(plaintext)), []byte(t.Hash)) != 1 {
+		return ErrInvalidToken
+	}
+	if t.RevokedAt != nil {
+		return ErrTokenRevoked
+	}
+	if t.UsedAt != nil {
+		return ErrTokenUsed
+	}
+	if !now.Before(t.ExpiresAt) {
+		return ErrTokenExpired
+	}
+	return nil
+}
+
+// Redeem records that an agent registered with the token, which cannot be used again
+func (t *Token) Redeem(agentID string) error {
+	if t.UsedAt != nil {
+		return ErrTokenUsed
+	}
+	now := time.Now()
+	t.UsedAt = &now
+	t.AgentID = agentID
+	t.touch()
+
+	t.addEvent(TokenRedeemed{
+		BaseEvent: shared.NewBaseEvent(EventTokenRedeemed, t.Id.String()),
+		TokenID:   t.Id.String(),
+		Name:      t.Name,
+		AgentID:   agentID,
+	})
+	return nil
+}
+
+// Revoke disables an unused token for good
+func (t *Token) Revoke() {
+	if t.RevokedAt != nil || t.UsedAt != nil {
+		return
+	}
+	now := time.Now()
+	t.RevokedAt = &now
+	t.touch()
+
+	t.addEvent(TokenRevoked{
+		BaseEvent: shared.NewBaseEvent(EventTokenRevoked, t.Id.String()),
+		TokenID:   t.Id.String(),
+		Name:      t.Name,
+	})
+}
+
+func (t *Token) touch() {
+	t.UpdatedAt = shared.NewTimestamp(time.Now())
+}
+
+func (t *Token) addEvent(event shared.DomainEvent) {
+	t.Events = append(t.Events, event)
+}
+
+func (t *Token) ClearEvents() {
+	t.Events = make([]shared.DomainEvent, 0)
+}
@@ -0,0 +1,32 @@
+package enrollment
+
+import (
+	"parrotflow/internal/domain/shared"
+	"time"
+)
+
+const (
+	EventTokenCreated  = "enrollment.token_created"
+	EventTokenRedeemed = "enrollment.token_redeemed"
+	EventTokenRevoked  = "enrollment.token_revoked"
+)
+
+type TokenCreated struct {
+	shared.BaseEvent
+	TokenID   string
+	Name      string
+	ExpiresAt time.Time
+}
+
+type TokenRedeemed struct {
+	shared.BaseEvent
+	TokenID string
+	Name    string
+	AgentID string
+}
+
+type TokenRevoked struct {
+	shared.BaseEvent
+	TokenID string
+	Name    string
+}
@@ -0,0 +1,18 @@
+package enrollment
+
+import "context"
+
+// Repository defines the interface for enrollment token persistence
+type Repository interface {
+	// Save persists an enrollment token
+	Save(ctx context.Context, token *Token) error
+
+	// FindByID retrieves an enrollment token by its ID
+	FindByID(ctx context.Context, id TokenID) (*Token, error)
+
+	// FindByPrefix retrieves the token a presented one claims to be
+	FindByPrefix(ctx context.Context, prefix string) (*Token, error)
+
+	// FindAll retrieves all enrollment tokens, including used, revoked and expired ones
+	FindAll(ctx context.Context) ([]*Token, error)
+}
@@ -0,0 +1,36 @@
+package eventlog
+
+import (
+	"parrotflow/internal/domain/shared"
+	"time"
+)
+
+// Aggregate types with a history endpoint
+const (
+	AggregateAgent    = "agent"
+	AggregateProxy    = "proxy"
+	AggregateRun      = "run"
+	AggregateScenario = "scenario"
+)
+
+// Entry is one published domain event as recorded in the event log
+// Entries are append-only; Sequence orders them in the order they were recorded
+type Entry struct {
+	shared.Envelope
+	Sequence   uint64
+	RecordedAt time.Time
+}
+
+// Criteria selects a page of the event log
+// Zero values leave a filter out; Since is inclusive and Until exclusive
+type Criteria struct {
+	EventTypes    []string
+	AggregateType string
+	AggregateID   string
+	CorrelationID string
+	Since         time.Time
+	Until         time.Time
+	OrderDir      string // asc for chronological order, newest first otherwise
+	Limit         int
+	Offset        int
+}
@@ -0,0 +1,13 @@
+package eventlog
+
+import (
+	"context"
+	"parrotflow/internal/domain/shared"
+)
+
+// Repository reads the event log
+// Entries are written together with the outbox by the repositories that persist aggregates
+type Repository interface {
+	// Find retrieves a page of entries matching the criteria, ordered by occurrence
+	Find(ctx context.Context, criteria Criteria) (shared.Page[*Entry], error)
+}
@@ -54,7 +54,7 @@ var (
 // ProxyCredentials represents authentication credentials for a proxy
 type ProxyCredentials struct {
 	Username string
-	Password string // Should be encrypted in storage
+	Password string // Encrypted at rest by the proxy repository
 }
 
 func NewProxyCredentials(username, password string) (ProxyCredentials, error) {
@@ -117,6 +117,8 @@ type Proxy struct {
 	AverageLatency  int // in milliseconds
 	CreatedAt       shared.Timestamp
 	UpdatedAt       shared.Timestamp
+	Version         uint64            // Optimistic concurrency version, 0 until first saved
+	DeletedAt       *shared.Timestamp // Set while the proxy is in the trash
 	Events          []shared.DomainEvent
 }
 
@@ -262,6 +264,25 @@ func (p *Proxy) HasTag(tagID tag.TagID) bool {
 	return false
 }
 
+// Delete records that the proxy was moved to the trash
+func (p *Proxy) Delete() {
+	p.addEvent(ProxyDeleted{
+		BaseEvent: shared.NewBaseEvent(EventProxyDeleted, p.Id.String()),
+		ProxyID:   p.Id.String(),
+		Name:      p.Name,
+	})
+}
+
+// Restore takes the proxy back out of the trash
+func (p *Proxy) Restore() {
+	p.DeletedAt = nil
+	p.addEvent(ProxyRestored{
+		BaseEvent: shared.NewBaseEvent(EventProxyRestored, p.Id.String()),
+		ProxyID:   p.Id.String(),
+		Name:      p.Name,
+	})
+}
+
 // GetConnectionURL returns the full proxy connection URL
 func (p *Proxy) GetConnectionURL() string {
 	if p.Credentials != nil {
@@ -275,6 +296,23 @@ func (p *Proxy) GetConnectionURL() string {
 	return fmt.Sprintf("%s://%s:%d", p.Protocol.String(), p.Host, p.Port)
 }
 
+// RedactedPassword replaces the password in GetRedactedConnectionURL
+const RedactedPassword = "xxxxx"
+
+// GetRedactedConnectionURL returns the connection URL with the password masked,
+// for callers not allowed to see credentials
+func (p *Proxy) GetRedactedConnectionURL() string {
+	if p.Credentials != nil {
+		return fmt.Sprintf("%s://%s:%s@%s:%d",
+			p.Protocol.String(),
+			p.Credentials.Username,
+			RedactedPassword,
+			p.Host,
+			p.Port)
+	}
+	return p.GetConnectionURL()
+}
+
 func (p *Proxy) addEvent(event shared.DomainEvent) {
 	p.Events = append(p.Events, event)
 }
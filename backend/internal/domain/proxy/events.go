@@ -9,6 +9,8 @@ const (
 	EventProxyCreated       = "proxy.created"
 	EventProxyStatusChanged = "proxy.status.changed"
 	EventProxyFailed        = "proxy.failed"
+	EventProxyDeleted       = "proxy.deleted"
+	EventProxyRestored      = "proxy.restored"
 )
 
 type ProxyCreated struct {
@@ -33,3 +35,15 @@ type ProxyFailed struct {
 	Reason   string
 	FailedAt time.Time
 }
+
+type ProxyDeleted struct {
+	shared.BaseEvent
+	ProxyID string
+	Name    string
+}
+
+type ProxyRestored struct {
+	shared.BaseEvent
+	ProxyID string
+	Name    string
+}
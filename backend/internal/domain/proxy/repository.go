@@ -3,8 +3,15 @@ package proxy
 import (
 	"context"
 	"parrotflow/internal/domain/tag"
+	"time"
 )
 
+// HealthObserver is told about every recorded health check, e.g. to keep metrics
+// Checks are too frequent to be domain events; status changes are events
+type HealthObserver interface {
+	ObserveHealthCheck(proxyID string, success bool, latency time.Duration)
+}
+
 // Repository defines the interface for proxy persistence
 type Repository interface {
 	// Save persists a proxy
@@ -28,9 +35,15 @@ type Repository interface {
 	// FindActive retrieves all active proxies
 	FindActive(ctx context.Context) ([]*Proxy, error)
 
-	// Delete removes a proxy
+	// FindTrashed retrieves all proxies currently in the trash
+	FindTrashed(ctx context.Context) ([]*Proxy, error)
+
+	// Delete moves a proxy to the trash
 	Delete(ctx context.Context, id ProxyID) error
 
+	// Restore takes a proxy back out of the trash
+	Restore(ctx context.Context, id ProxyID) error
+
 	// Exists checks if a proxy with the given name already exists
 	Exists(ctx context.Context, name string) (bool, error)
 }
@@ -7,6 +7,11 @@ import (
 	"time"
 )
 
+// Domain errors
+var (
+	ErrRunClaimedByAnotherAgent = errors.New("run is executed by another agent")
+)
+
 type RunID struct {
 	shared.ID
 }
@@ -19,6 +24,40 @@ func NewRunID(value string) (RunID, error) {
 	return RunID{ID: id}, nil
 }
 
+// NodeStatus represents the execution state of one scenario node within a run
+type NodeStatus struct {
+	value string
+}
+
+func NewNodeStatus(value string) (NodeStatus, error) {
+	switch value {
+	case "RUNNING", "COMPLETED", "FAILED", "SKIPPED":
+		return NodeStatus{value: value}, nil
+	default:
+		return NodeStatus{}, errors.New("invalid node status: " + value)
+	}
+}
+
+func (ns NodeStatus) String() string {
+	return ns.value
+}
+
+// Common node statuses
+var (
+	NodeStatusRunning   = NodeStatus{value: "RUNNING"}
+	NodeStatusCompleted = NodeStatus{value: "COMPLETED"}
+	NodeStatusFailed    = NodeStatus{value: "FAILED"}
+	NodeStatusSkipped   = NodeStatus{value: "SKIPPED"}
+)
+
+// NodeStep is the last reported state of one scenario node in a run
+type NodeStep struct {
+	NodeID     string
+	Status     NodeStatus
+	Message    string
+	ReportedAt time.Time
+}
+
 type Run struct {
 	Id         RunID
 	ScenarioID scenario.ScenarioID
@@ -28,7 +67,13 @@ type Run struct {
 	FinishedAt *shared.Timestamp
 	CreatedAt  shared.Timestamp
 	UpdatedAt  shared.Timestamp
+	Version    uint64 // Optimistic concurrency version, 0 until first saved
 	Events     []shared.DomainEvent
+
+	FailureReason string               // Why a failed run failed, empty otherwise
+	AgentID       string               // Agent executing the run, empty until one claims it
+	SecretNames   []string             // Secrets the scenario referenced when the run started, the only ones it gets
+	Definition    *scenario.Definition // What the run executes, snapshotted from the scenario when it started
 }
 
 func NewRun(id RunID, scenarioID scenario.ScenarioID, parameters string) (*Run, error) {
@@ -56,6 +101,12 @@ func NewRun(id RunID, scenarioID scenario.ScenarioID, parameters string) (*Run,
 	return run, nil
 }
 
+func (r *Run) IsFinished() bool {
+	return r.Status == shared.StatusCompleted ||
+		r.Status == shared.StatusFailed ||
+		r.Status == shared.StatusCancelled
+}
+
 func (r *Run) Start() error {
 	if r.Status != shared.StatusPending {
 		return errors.New("can only start a pending run")
@@ -90,6 +141,7 @@ func (r *Run) Complete() error {
 		BaseEvent:  shared.NewBaseEvent(EventRunCompleted, r.Id.String()),
 		RunID:      r.Id.String(),
 		ScenarioID: r.ScenarioID.String(),
+		StartedAt:  r.StartedAt.Time(),
 		FinishedAt: r.FinishedAt.Time(),
 	})
 
@@ -102,6 +154,7 @@ func (r *Run) Fail(reason string) error {
 	}
 
 	r.Status = shared.StatusFailed
+	r.FailureReason = reason
 	finishedAt := shared.NewTimestamp(time.Now())
 	r.FinishedAt = &finishedAt
 	r.UpdatedAt = shared.NewTimestamp(time.Now())
@@ -111,6 +164,7 @@ func (r *Run) Fail(reason string) error {
 		RunID:      r.Id.String(),
 		ScenarioID: r.ScenarioID.String(),
 		Reason:     reason,
+		StartedAt:  r.StartedAt.Time(),
 		FailedAt:   r.FinishedAt.Time(),
 	})
 
@@ -137,6 +191,40 @@ func (r *Run) Cancel() error {
 	return nil
 }
 
+// ClaimBy records the agent executing the run
+// Execution requests are queued for any agent, so the first agent reporting progress
+// claims the run and reports from other agents are refused
+func (r *Run) ClaimBy(agentID string) error {
+	if r.AgentID != "" && r.AgentID != agentID {
+		return ErrRunClaimedByAnotherAgent
+	}
+	r.AgentID = agentID
+	return nil
+}
+
+func (r *Run) ReportProgress(nodeID string, status NodeStatus, message string) error {
+	if r.Status != shared.StatusRunning {
+		return errors.New("can only report progress of a running run")
+	}
+	if nodeID == "" {
+		return errors.New("node id cannot be empty")
+	}
+
+	r.UpdatedAt = shared.NewTimestamp(time.Now())
+
+	r.addEvent(RunProgress{
+		BaseEvent:  shared.NewBaseEvent(EventRunProgress, r.Id.String()),
+		RunID:      r.Id.String(),
+		ScenarioID: r.ScenarioID.String(),
+		NodeID:     nodeID,
+		NodeStatus: status.String(),
+		Message:    message,
+		ReportedAt: r.UpdatedAt.Time(),
+	})
+
+	return nil
+}
+
 func (r *Run) addEvent(event shared.DomainEvent) {
 	r.Events = append(r.Events, event)
 }
@@ -5,12 +5,13 @@ import (
 	"time"
 )
 
-var (
-	EventRunCreated   = "RunCreated"
-	EventRunStarted   = "RunStarted"
-	EventRunCompleted = "RunCompleted"
-	EventRunFailed    = "RunFailed"
-	EventRunCancelled = "RunCancelled"
+const (
+	EventRunCreated   = "run.created"
+	EventRunStarted   = "run.started"
+	EventRunCompleted = "run.completed"
+	EventRunFailed    = "run.failed"
+	EventRunCancelled = "run.cancelled"
+	EventRunProgress  = "run.progress"
 )
 
 type RunCreated struct {
@@ -31,6 +32,7 @@ type RunCompleted struct {
 	shared.BaseEvent
 	RunID      string
 	ScenarioID string
+	StartedAt  time.Time
 	FinishedAt time.Time
 }
 
@@ -39,6 +41,7 @@ type RunFailed struct {
 	RunID      string
 	ScenarioID string
 	Reason     string
+	StartedAt  time.Time
 	FailedAt   time.Time
 }
 
@@ -48,3 +51,14 @@ type RunCancelled struct {
 	ScenarioID  string
 	CancelledAt time.Time
 }
+
+// RunProgress reports the state of a single scenario node while the run executes
+type RunProgress struct {
+	shared.BaseEvent
+	RunID      string
+	ScenarioID string
+	NodeID     string
+	NodeStatus string
+	Message    string
+	ReportedAt time.Time
+}
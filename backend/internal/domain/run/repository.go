@@ -2,14 +2,17 @@ package run
 
 import (
 	"context"
+	"fmt"
 	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/shared"
+	"strings"
 )
 
 type Repository interface {
 	Save(ctx context.Context, run *Run) error
 	FindByID(ctx context.Context, id RunID) (*Run, error)
 	FindByScenarioID(ctx context.Context, scenarioID scenario.ScenarioID) ([]*Run, error)
-	FindAll(ctx context.Context, criteria SearchCriteria) ([]*Run, error)
+	FindAll(ctx context.Context, criteria SearchCriteria) (shared.Page[*Run], error)
 	Delete(ctx context.Context, id RunID) error
 	Exists(ctx context.Context, id RunID) (bool, error)
 }
@@ -21,8 +24,12 @@ type SearchCriteria struct {
 	Offset     int
 	OrderBy    string
 	OrderDir   string
+	Cursor     string // Keyset pagination on (created_at, id) newest first, replaces Offset and ordering
 }
 
+// SortableFields lists the fields runs can be ordered by
+var SortableFields = []string{"created_at", "started_at", "finished_at", "status"}
+
 func NewSearchCriteria() SearchCriteria {
 	return SearchCriteria{
 		Limit:    10,
@@ -53,3 +60,25 @@ func (sc SearchCriteria) WithOrdering(orderBy, orderDir string) SearchCriteria {
 	sc.OrderDir = orderDir
 	return sc
 }
+
+func (sc SearchCriteria) WithCursor(cursor string) SearchCriteria {
+	sc.Cursor = cursor
+	return sc
+}
+
+func (sc SearchCriteria) Validate() error {
+	if err := shared.ValidateSort(sc.OrderBy, sc.OrderDir, SortableFields); err != nil {
+		return err
+	}
+	if sc.Cursor == "" {
+		return nil
+	}
+	if sc.Offset > 0 {
+		return fmt.Errorf("%w: cursor and offset cannot be combined", shared.ErrInvalidPageRequest)
+	}
+	if (sc.OrderBy != "" && sc.OrderBy != "created_at") || strings.EqualFold(sc.OrderDir, shared.SortAsc) {
+		return fmt.Errorf("%w: cursor pagination only orders by created_at desc", shared.ErrInvalidPageRequest)
+	}
+	_, err := shared.DecodeCursor(sc.Cursor)
+	return err
+}
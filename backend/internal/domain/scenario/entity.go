@@ -6,6 +6,11 @@ import (
 	"time"
 )
 
+// Domain errors
+var (
+	ErrScenarioNotFound = errors.New("scenario not found")
+)
+
 type ScenarioID struct {
 	shared.ID
 }
@@ -27,8 +32,11 @@ type Scenario struct {
 	Context     Context
 	InputData   InputData
 	Parameters  Parameters
+	Retention   *RetentionPolicy // Overrides the global run retention, nil to inherit it
 	CreatedAt   shared.Timestamp
 	UpdatedAt   shared.Timestamp
+	Version     uint64            // Optimistic concurrency version, 0 until first saved
+	DeletedAt   *shared.Timestamp // Set while the scenario is in the trash
 	Events      []shared.DomainEvent
 }
 
@@ -93,6 +101,34 @@ func (s *Scenario) UpdateParameters(parameters Parameters) {
 	s.UpdatedAt = shared.NewTimestamp(time.Now())
 }
 
+func (s *Scenario) UpdateRetention(policy *RetentionPolicy) {
+	s.Retention = policy
+	s.UpdatedAt = shared.NewTimestamp(time.Now())
+}
+
+// ParameterValues returns the values of the node parameters and scenario parameters,
+// which is where scenarios reference secrets
+func (s *Scenario) ParameterValues() []any {
+	return s.Definition().ParameterValues()
+}
+
+// Definition is what a run of the scenario executes, as the scenario is now
+func (s *Scenario) Definition() Definition {
+	return Definition{
+		Context:    s.Context,
+		InputData:  s.InputData,
+		Parameters: s.Parameters,
+	}
+}
+
+func (s *Scenario) Restore() {
+	s.DeletedAt = nil
+	s.addEvent(ScenarioRestored{
+		BaseEvent:  shared.NewBaseEvent(EventScenarioRestored, s.Id.String()),
+		ScenarioID: s.Id.String(),
+	})
+}
+
 func (s *Scenario) addEvent(event shared.DomainEvent) {
 	s.Events = append(s.Events, event)
 }
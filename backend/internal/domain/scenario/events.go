@@ -4,12 +4,13 @@ import (
 	"parrotflow/internal/domain/shared"
 )
 
-var (
-	EventScenarioCreated           = "ScenarioCreated"
-	EventScenarioUpdated           = "ScenarioUpdated"
-	EventScenarioDeleted           = "ScenarioDeleted"
-	EventScenarioContextUpdated    = "ScenarioContextUpdated"
-	EventScenarioParametersUpdated = "ScenarioParametersUpdated"
+const (
+	EventScenarioCreated           = "scenario.created"
+	EventScenarioUpdated           = "scenario.updated"
+	EventScenarioDeleted           = "scenario.deleted"
+	EventScenarioRestored          = "scenario.restored"
+	EventScenarioContextUpdated    = "scenario.context.updated"
+	EventScenarioParametersUpdated = "scenario.parameters.updated"
 )
 
 type ScenarioCreated struct {
@@ -29,6 +30,11 @@ type ScenarioDeleted struct {
 	ScenarioID string
 }
 
+type ScenarioRestored struct {
+	shared.BaseEvent
+	ScenarioID string
+}
+
 type ScenarioContextUpdated struct {
 	shared.BaseEvent
 	ScenarioID string
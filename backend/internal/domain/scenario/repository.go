@@ -2,26 +2,32 @@ package scenario
 
 import (
 	"context"
+	"parrotflow/internal/domain/shared"
 )
 
 type Repository interface {
 	Save(ctx context.Context, scenario *Scenario) error
 	FindByID(ctx context.Context, id ScenarioID) (*Scenario, error)
 	FindByName(ctx context.Context, name string) (*Scenario, error)
-	FindAll(ctx context.Context, criteria SearchCriteria) ([]*Scenario, error)
+	FindAll(ctx context.Context, criteria SearchCriteria) (shared.Page[*Scenario], error)
 	Delete(ctx context.Context, id ScenarioID) error
+	Restore(ctx context.Context, id ScenarioID) error
 	Exists(ctx context.Context, id ScenarioID) (bool, error)
 }
 
 type SearchCriteria struct {
 	Name     string
 	Tag      string
+	Trashed  bool // Only scenarios in the trash instead of live ones
 	Limit    int
 	Offset   int
 	OrderBy  string
 	OrderDir string
 }
 
+// SortableFields lists the fields scenarios can be ordered by
+var SortableFields = []string{"created_at", "updated_at", "name"}
+
 func NewSearchCriteria() SearchCriteria {
 	return SearchCriteria{
 		Limit:    10,
@@ -41,6 +47,11 @@ func (sc SearchCriteria) WithTag(tag string) SearchCriteria {
 	return sc
 }
 
+func (sc SearchCriteria) WithTrashed(trashed bool) SearchCriteria {
+	sc.Trashed = trashed
+	return sc
+}
+
 func (sc SearchCriteria) WithPagination(limit, offset int) SearchCriteria {
 	sc.Limit = limit
 	sc.Offset = offset
@@ -52,3 +63,7 @@ func (sc SearchCriteria) WithOrdering(orderBy, orderDir string) SearchCriteria {
 	sc.OrderDir = orderDir
 	return sc
 }
+
+func (sc SearchCriteria) Validate() error {
+	return shared.ValidateSort(sc.OrderBy, sc.OrderDir, SortableFields)
+}
@@ -2,6 +2,8 @@ package scenario
 
 import (
 	"errors"
+	"strings"
+	"time"
 )
 
 type Context struct {
@@ -151,3 +153,78 @@ func NewParameterItem(parameter Parameter, paramType string, values []string) Pa
 		Values:    values,
 	}
 }
+
+// RetentionPolicy limits how much run history is kept for a scenario
+// A zero MaxAge or MaxRuns means no limit on that dimension
+type RetentionPolicy struct {
+	MaxAge       time.Duration // Finished runs older than this are compacted
+	MaxRuns      int           // Only the newest MaxRuns runs are kept
+	KeepStatuses []string      // Runs in these statuses are never compacted
+}
+
+func NewRetentionPolicy(maxAge time.Duration, maxRuns int, keepStatuses []string) (RetentionPolicy, error) {
+	if maxAge < 0 {
+		return RetentionPolicy{}, errors.New("retention max age cannot be negative")
+	}
+	if maxRuns < 0 {
+		return RetentionPolicy{}, errors.New("retention max runs cannot be negative")
+	}
+
+	statuses := make([]string, 0, len(keepStatuses))
+	for _, status := range keepStatuses {
+		status = strings.ToUpper(strings.TrimSpace(status))
+		if status != "" {
+			statuses = append(statuses, status)
+		}
+	}
+
+	return RetentionPolicy{
+		MaxAge:       maxAge,
+		MaxRuns:      maxRuns,
+		KeepStatuses: statuses,
+	}, nil
+}
+
+// IsUnlimited reports whether the policy never removes any run
+func (p RetentionPolicy) IsUnlimited() bool {
+	return p.MaxAge == 0 && p.MaxRuns == 0
+}
+
+// Keeps reports whether runs in the given status are exempt from retention
+func (p RetentionPolicy) Keeps(status string) bool {
+	for _, keep := range p.KeepStatuses {
+		if strings.EqualFold(keep, status) {
+			return true
+		}
+	}
+	return false
+}
+
+// Definition is the executable part of a scenario: its graph and parameters
+// Runs keep the definition they started with, edits to the scenario do not reach them
+type Definition struct {
+	Context    Context
+	InputData  InputData
+	Parameters Parameters
+}
+
+// ParameterValues returns the values of the node parameters and scenario parameters,
+// which is where scenarios reference secrets
+func (d Definition) ParameterValues() []any {
+	var values []any
+	for _, node := range d.InputData.Parameters {
+		for _, p := range node.Input {
+			values = append(values, p.Value)
+		}
+		for _, p := range node.Output {
+			values = append(values, p.Value)
+		}
+	}
+	for _, item := range d.Parameters.Input {
+		values = append(values, item.Parameter.Value)
+	}
+	for _, item := range d.Parameters.Output {
+		values = append(values, item.Parameter.Value)
+	}
+	return values
+}
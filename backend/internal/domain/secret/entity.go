@@ -0,0 +1,128 @@
+package secret
+
+import (
+	"errors"
+	"fmt"
+	"parrotflow/internal/domain/shared"
+	"regexp"
+	"time"
+)
+
+// Domain errors
+var (
+	ErrSecretNotFound      = errors.New("secret not found")
+	ErrSecretAlreadyExists = errors.New("secret with this name already exists")
+	ErrInvalidName         = errors.New("invalid secret name")
+	ErrEmptyValue          = errors.New("secret value cannot be empty")
+	ErrUnknownSecret       = errors.New("scenario references a secret that does not exist")
+)
+
+// namePattern is what a secret can be called, the same names references accept
+var namePattern = regexp.MustCompile(`^` + namePart + `$`)
+
+// namePart matches a secret name, up to 64 letters, digits, '_', '-' and '.' starting with a letter or '_'
+const namePart = `[A-Za-z_][A-Za-z0-9_.-]{0,63}`
+
+type SecretID struct {
+	shared.ID
+}
+
+func NewSecretID(value string) (SecretID, error) {
+	id, err := shared.NewID(value)
+	if err != nil {
+		return SecretID{}, err
+	}
+	return SecretID{ID: id}, nil
+}
+
+// Secret is a named value scenarios reference as ${secret:NAME}
+// The value is encrypted at rest and never returned by the API, only sent to agents
+type Secret struct {
+	Id          SecretID
+	Name        string
+	Value       string
+	Description string
+	CreatedAt   shared.Timestamp
+	UpdatedAt   shared.Timestamp
+	Version     uint64 // Optimistic concurrency version, 0 until first saved
+	Events      []shared.DomainEvent
+}
+
+// NewSecret creates a secret; names are case-sensitive
+func NewSecret(id SecretID, name, value, description string) (*Secret, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	if value == "" {
+		return nil, ErrEmptyValue
+	}
+
+	s := &Secret{
+		Id:          id,
+		Name:        name,
+		Value:       value,
+		Description: description,
+		CreatedAt:   shared.NewTimestamp(time.Now()),
+		UpdatedAt:   shared.NewTimestamp(time.Now()),
+		Events:      make([]shared.DomainEvent, 0),
+	}
+
+	s.addEvent(SecretCreated{
+		BaseEvent: shared.NewBaseEvent(EventSecretCreated, id.String()),
+		SecretID:  id.String(),
+		Name:      name,
+	})
+
+	return s, nil
+}
+
+// ValidateName checks that a name can be referenced as ${secret:NAME}
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("%w %q: use up to 64 letters, digits, '_', '-' and '.', starting with a letter or '_'", ErrInvalidName, name)
+	}
+	return nil
+}
+
+// UpdateValue replaces the value; runs started afterwards receive the new one
+func (s *Secret) UpdateValue(value string) error {
+	if value == "" {
+		return ErrEmptyValue
+	}
+	s.Value = value
+	s.touch()
+
+	s.addEvent(SecretUpdated{
+		BaseEvent: shared.NewBaseEvent(EventSecretUpdated, s.Id.String()),
+		SecretID:  s.Id.String(),
+		Name:      s.Name,
+	})
+	return nil
+}
+
+// UpdateDescription updates the secret description
+func (s *Secret) UpdateDescription(description string) {
+	s.Description = description
+	s.touch()
+}
+
+// Delete marks the secret for deletion
+func (s *Secret) Delete() {
+	s.addEvent(SecretDeleted{
+		BaseEvent: shared.NewBaseEvent(EventSecretDeleted, s.Id.String()),
+		SecretID:  s.Id.String(),
+		Name:      s.Name,
+	})
+}
+
+func (s *Secret) touch() {
+	s.UpdatedAt = shared.NewTimestamp(time.Now())
+}
+
+func (s *Secret) addEvent(event shared.DomainEvent) {
+	s.Events = append(s.Events, event)
+}
+
+func (s *Secret) ClearEvents() {
+	s.Events = make([]shared.DomainEvent, 0)
+}
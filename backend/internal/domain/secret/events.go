@@ -0,0 +1,29 @@
+package secret
+
+import "parrotflow/internal/domain/shared"
+
+// Secret events carry names only, never values
+const (
+	EventSecretCreated = "secret.created"
+	EventSecretUpdated = "secret.updated"
+	EventSecretDeleted = "secret.deleted"
+)
+
+type SecretCreated struct {
+	shared.BaseEvent
+	SecretID string
+	Name     string
+}
+
+// SecretUpdated is recorded when the value changes
+type SecretUpdated struct {
+	shared.BaseEvent
+	SecretID string
+	Name     string
+}
+
+type SecretDeleted struct {
+	shared.BaseEvent
+	SecretID string
+	Name     string
+}
@@ -0,0 +1,114 @@
+package secret
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MaskedValue replaces resolved secret values in what is stored about a run
+const MaskedValue = "********"
+
+// referencePattern matches ${secret:NAME}, capturing the name
+var referencePattern = regexp.MustCompile(`\$\{secret:(` + namePart + `)\}`)
+
+// Reference formats the reference to a secret, as written in scenario parameters
+func Reference(name string) string {
+	return "${secret:" + name + "}"
+}
+
+// References returns the names of the secrets the values reference, without duplicates
+// Strings nested in slices and maps are searched as well
+func References(values ...any) []string {
+	var names []string
+	for _, value := range values {
+		walkStrings(value, func(s string) string {
+			for _, match := range referencePattern.FindAllStringSubmatch(s, -1) {
+				if !slices.Contains(names, match[1]) {
+					names = append(names, match[1])
+				}
+			}
+			return s
+		})
+	}
+	return names
+}
+
+// Resolve replaces the references in value with the secret values, which are keyed by name
+// Strings nested in slices and maps are resolved in copies, other values are returned as they are
+func Resolve(value any, values map[string]string) (any, error) {
+	var missing []string
+	resolved := walkStrings(value, func(s string) string {
+		return referencePattern.ReplaceAllStringFunc(s, func(reference string) string {
+			name := referencePattern.FindStringSubmatch(reference)[1]
+			v, ok := values[name]
+			if !ok {
+				missing = append(missing, name)
+			}
+			return v
+		})
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSecret, strings.Join(missing, ", "))
+	}
+	return resolved, nil
+}
+
+// Values keys the values of the secrets by name, as Resolve takes them
+func Values(secrets []*Secret) map[string]string {
+	values := make(map[string]string, len(secrets))
+	for _, s := range secrets {
+		values[s.Name] = s.Value
+	}
+	return values
+}
+
+// walkStrings returns value with fn applied to every string in it
+func walkStrings(value any, fn func(string) string) any {
+	switch v := value.(type) {
+	case string:
+		return fn(v)
+	case []any:
+		walked := make([]any, len(v))
+		for i, item := range v {
+			walked[i] = walkStrings(item, fn)
+		}
+		return walked
+	case map[string]any:
+		walked := make(map[string]any, len(v))
+		for key, item := range v {
+			walked[key] = walkStrings(item, fn)
+		}
+		return walked
+	default:
+		return value
+	}
+}
+
+// Masker hides secret values in text, such as the progress agents report
+type Masker struct {
+	replacer *strings.Replacer
+}
+
+// NewMasker masks the values of the secrets, however short: a short value may mangle
+// ordinary text that happens to contain it, but is never let through
+func NewMasker(secrets []*Secret) *Masker {
+	// Longer values go first, so a value containing another is masked whole
+	sorted := slices.Clone(secrets)
+	slices.SortFunc(sorted, func(a, b *Secret) int { return cmp.Compare(len(b.Value), len(a.Value)) })
+
+	pairs := make([]string, 0, 2*len(sorted))
+	for _, s := range sorted {
+		if s.Value != "" {
+			pairs = append(pairs, s.Value, MaskedValue)
+		}
+	}
+	return &Masker{replacer: strings.NewReplacer(pairs...)}
+}
+
+// Mask replaces every secret value in text with MaskedValue
+func (m *Masker) Mask(text string) string {
+	return m.replacer.Replace(text)
+}
@@ -0,0 +1,25 @@
+package secret
+
+import "context"
+
+// Repository defines the interface for secret persistence
+// Values are decrypted on the way out, callers must not return them to API clients
+type Repository interface {
+	// Save persists a secret
+	Save(ctx context.Context, secret *Secret) error
+
+	// FindByID retrieves a secret by its ID
+	FindByID(ctx context.Context, id SecretID) (*Secret, error)
+
+	// FindByNames retrieves the secrets with the given names, names without a secret are left out
+	FindByNames(ctx context.Context, names []string) ([]*Secret, error)
+
+	// FindAll retrieves all secrets, ordered by name
+	FindAll(ctx context.Context) ([]*Secret, error)
+
+	// Exists checks if a secret with the given name exists
+	Exists(ctx context.Context, name string) (bool, error)
+
+	// Delete permanently removes a secret
+	Delete(ctx context.Context, id SecretID) error
+}
@@ -0,0 +1,75 @@
+package shared
+
+import (
+	"context"
+	"reflect"
+)
+
+// Correlation ties an event or message to the chain of work it belongs to
+// CorrelationID is shared by everything a request led to; CausationID is the ID
+// of the request, event or message that directly caused this one
+type Correlation struct {
+	CorrelationID string
+	CausationID   string
+}
+
+// CorrelatedEvent is implemented by events that carry a correlation, i.e. all
+// events embedding BaseEvent
+type CorrelatedEvent interface {
+	CorrelationID() string
+	CausationID() string
+}
+
+type correlationKey struct{}
+
+// ContextWithCorrelation returns a context carrying the correlation
+func ContextWithCorrelation(ctx context.Context, c Correlation) context.Context {
+	return context.WithValue(ctx, correlationKey{}, c)
+}
+
+// CorrelationFromContext returns the correlation of ctx, empty when there is none
+func CorrelationFromContext(ctx context.Context) Correlation {
+	c, _ := ctx.Value(correlationKey{}).(Correlation)
+	return c
+}
+
+// CorrelationOf returns the correlation an event carries
+func CorrelationOf(event DomainEvent) Correlation {
+	if correlated, ok := event.(CorrelatedEvent); ok {
+		return Correlation{CorrelationID: correlated.CorrelationID(), CausationID: correlated.CausationID()}
+	}
+	return Correlation{}
+}
+
+// CausedBy returns a context for work done in reaction to an event: the work stays
+// in the event's correlation, or starts one at the event, and the event is its cause
+func CausedBy(ctx context.Context, event DomainEvent) context.Context {
+	c := Correlation{CorrelationID: CorrelationOf(event).CorrelationID, CausationID: event.EventID()}
+	if c.CorrelationID == "" {
+		c.CorrelationID = event.EventID()
+	}
+	return ContextWithCorrelation(ctx, c)
+}
+
+// Correlate stamps the correlation of ctx on an event that does not carry one yet
+// Aggregates raise events without a context, so this happens when they are saved
+func Correlate(ctx context.Context, event DomainEvent) DomainEvent {
+	c := CorrelationFromContext(ctx)
+	if c.CorrelationID == "" || CorrelationOf(event).CorrelationID != "" {
+		return event
+	}
+
+	value := reflect.ValueOf(event)
+	if value.Kind() != reflect.Struct {
+		return event
+	}
+	copied := reflect.New(value.Type()).Elem()
+	copied.Set(value)
+
+	field := copied.FieldByName("BaseEvent")
+	if !field.IsValid() || field.Type() != reflect.TypeOf(BaseEvent{}) {
+		return event
+	}
+	field.Set(reflect.ValueOf(field.Interface().(BaseEvent).WithCorrelation(c)))
+	return copied.Interface().(DomainEvent)
+}
@@ -0,0 +1,212 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Envelope is the canonical serialized form of a domain event
+// Everything that moves events out of the process (outbox, webhooks, brokers, replays)
+// stores or sends this shape, so consumers decode one format:
+//
+//	{"id":"…","type":"run.failed","version":1,"aggregate_type":"run","aggregate_id":"42","occurred_at":"…","correlation_id":"…","causation_id":"…","payload":{…}}
+type Envelope struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	Version       int             `json:"version"` // Payload schema version of the event type
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	CausationID   string          `json:"causation_id,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// VersionedEvent is implemented by events whose payload schema changed
+// Events that do not implement it are version 1
+type VersionedEvent interface {
+	SchemaVersion() int
+}
+
+// SchemaVersionOf returns the payload schema version of an event
+func SchemaVersionOf(event DomainEvent) int {
+	if v, ok := event.(VersionedEvent); ok {
+		return v.SchemaVersion()
+	}
+	return 1
+}
+
+// AggregateTypeOf derives the aggregate from a dotted event type, e.g. "run" for "run.failed"
+func AggregateTypeOf(eventType string) string {
+	aggregate, _, _ := strings.Cut(eventType, ".")
+	return aggregate
+}
+
+// NewEnvelope serializes an event; its fields beyond BaseEvent become the payload
+func NewEnvelope(event DomainEvent) (Envelope, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("encode %s: %w", event.EventType(), err)
+	}
+
+	correlation := CorrelationOf(event)
+	return Envelope{
+		ID:            event.EventID(),
+		Type:          event.EventType(),
+		Version:       SchemaVersionOf(event),
+		AggregateType: AggregateTypeOf(event.EventType()),
+		AggregateID:   event.AggregateID(),
+		OccurredAt:    event.OccurredAt(),
+		CorrelationID: correlation.CorrelationID,
+		CausationID:   correlation.CausationID,
+		Payload:       payload,
+	}, nil
+}
+
+// Base rebuilds the BaseEvent carried by the envelope
+func (e Envelope) Base() BaseEvent {
+	return RestoreBaseEvent(e.ID, e.Type, e.AggregateID, e.OccurredAt).
+		WithCorrelation(Correlation{CorrelationID: e.CorrelationID, CausationID: e.CausationID})
+}
+
+// RawEvent is decoded for event types the registry does not know, so that
+// sinks forwarding payloads as-is (e.g. brokers) still receive them unchanged
+type RawEvent struct {
+	BaseEvent
+	Version int
+	Payload json.RawMessage
+}
+
+func (e RawEvent) SchemaVersion() int {
+	return e.Version
+}
+
+// MarshalJSON keeps the payload as it was received
+func (e RawEvent) MarshalJSON() ([]byte, error) {
+	if len(e.Payload) == 0 {
+		return []byte("null"), nil
+	}
+	return e.Payload, nil
+}
+
+// EventRegistry maps event type names to the Go structs they decode into
+type EventRegistry struct {
+	events  map[string]registeredEvent
+	aliases map[string]string
+	mu      sync.RWMutex
+}
+
+type registeredEvent struct {
+	version int
+	decode  func(base BaseEvent, payload []byte) (DomainEvent, error)
+}
+
+func NewEventRegistry() *EventRegistry {
+	return &EventRegistry{
+		events:  make(map[string]registeredEvent),
+		aliases: make(map[string]string),
+	}
+}
+
+// RegisterEvent adds an event struct that embeds BaseEvent under its type name
+func RegisterEvent[T DomainEvent](r *EventRegistry, eventType string) {
+	var zero T
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events[eventType] = registeredEvent{
+		version: SchemaVersionOf(zero),
+		decode: func(base BaseEvent, payload []byte) (DomainEvent, error) {
+			var event T
+			if err := json.Unmarshal(payload, &event); err != nil {
+				return nil, err
+			}
+
+			// BaseEvent is not part of the payload, restore it from the envelope
+			field := reflect.ValueOf(&event).Elem().FieldByName("BaseEvent")
+			if field.IsValid() && field.CanSet() {
+				field.Set(reflect.ValueOf(base))
+			}
+
+			return event, nil
+		},
+	}
+}
+
+// Alias lets events stored under a former type name decode as the current one
+func (r *EventRegistry) Alias(legacyType, eventType string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.aliases[legacyType] = eventType
+}
+
+// Canonical resolves a former type name to the current one
+func (r *EventRegistry) Canonical(eventType string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if current, ok := r.aliases[eventType]; ok {
+		return current
+	}
+	return eventType
+}
+
+// IsRegistered reports whether an event type (or a former name of one) is known
+func (r *EventRegistry) IsRegistered(eventType string) bool {
+	eventType = r.Canonical(eventType)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.events[eventType]
+	return ok
+}
+
+// Types lists the registered event type names, sorted
+func (r *EventRegistry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	types := make([]string, 0, len(r.events))
+	for eventType := range r.events {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Encode serializes an event into its envelope
+func (r *EventRegistry) Encode(event DomainEvent) (Envelope, error) {
+	return NewEnvelope(event)
+}
+
+// Decode rebuilds the concrete event of an envelope, falling back to RawEvent for unknown types
+// Envelopes written by a newer schema version than the registered one are rejected
+func (r *EventRegistry) Decode(envelope Envelope) (DomainEvent, error) {
+	envelope.Type = r.Canonical(envelope.Type)
+	if envelope.Version == 0 {
+		envelope.Version = 1
+	}
+
+	r.mu.RLock()
+	registered, ok := r.events[envelope.Type]
+	r.mu.RUnlock()
+
+	if !ok {
+		return RawEvent{BaseEvent: envelope.Base(), Version: envelope.Version, Payload: envelope.Payload}, nil
+	}
+	if envelope.Version > registered.version {
+		return nil, fmt.Errorf("decode %s: payload version %d is newer than supported version %d",
+			envelope.Type, envelope.Version, registered.version)
+	}
+
+	event, err := registered.decode(envelope.Base(), envelope.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", envelope.Type, err)
+	}
+	return event, nil
+}
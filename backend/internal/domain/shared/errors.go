@@ -0,0 +1,53 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrConcurrentModification is returned when an aggregate was changed by someone else
+// between being loaded and saved. Match it with errors.Is
+var ErrConcurrentModification = errors.New("concurrent modification")
+
+// ErrStaleVersion is returned when the version a caller based its change on (If-Match)
+// is not the current one; it is a concurrent modification that retrying cannot fix
+var ErrStaleVersion = errors.New("stale version")
+
+// ErrEncryptionRequired is returned when a credential would be stored in plaintext
+// because no encryption key is configured
+var ErrEncryptionRequired = errors.New("no encryption key is configured to store the credential with")
+
+// ConcurrentModificationError describes which aggregate version was expected
+type ConcurrentModificationError struct {
+	Aggregate       string
+	ID              string
+	ExpectedVersion uint64
+	Stale           bool // The caller's expected version, rather than the one loaded, is outdated
+}
+
+func NewConcurrentModificationError(aggregate, id string, expectedVersion uint64) *ConcurrentModificationError {
+	return &ConcurrentModificationError{
+		Aggregate:       aggregate,
+		ID:              id,
+		ExpectedVersion: expectedVersion,
+	}
+}
+
+func (e *ConcurrentModificationError) Error() string {
+	return fmt.Sprintf("%s %s was modified concurrently (expected version %d)", e.Aggregate, e.ID, e.ExpectedVersion)
+}
+
+func (e *ConcurrentModificationError) Is(target error) bool {
+	return target == ErrConcurrentModification || (e.Stale && target == ErrStaleVersion)
+}
+
+// CheckVersion compares the loaded version against the one the caller based its change on
+// A nil expected version means the caller did not ask for a precondition
+func CheckVersion(aggregate, id string, current uint64, expected *uint64) error {
+	if expected != nil && *expected != current {
+		err := NewConcurrentModificationError(aggregate, id, *expected)
+		err.Stale = true
+		return err
+	}
+	return nil
+}
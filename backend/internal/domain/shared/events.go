@@ -1,6 +1,7 @@
 package shared
 
 import (
+	"context"
 	"parrotflow/pkg/shared"
 	"time"
 )
@@ -12,10 +13,12 @@ type DomainEvent interface {
 	AggregateID() string
 }
 type BaseEvent struct {
-	eventID     string
-	eventType   string
-	occurredAt  time.Time
-	aggregateID string
+	eventID       string
+	eventType     string
+	occurredAt    time.Time
+	aggregateID   string
+	correlationID string
+	causationID   string
 }
 
 func NewBaseEvent(eventType, aggregateID string) BaseEvent {
@@ -27,6 +30,17 @@ func NewBaseEvent(eventType, aggregateID string) BaseEvent {
 	}
 }
 
+// RestoreBaseEvent rebuilds a BaseEvent from previously persisted values,
+// e.g. when an event is read back from the outbox
+func RestoreBaseEvent(eventID, eventType, aggregateID string, occurredAt time.Time) BaseEvent {
+	return BaseEvent{
+		eventID:     eventID,
+		eventType:   eventType,
+		occurredAt:  occurredAt,
+		aggregateID: aggregateID,
+	}
+}
+
 func (e BaseEvent) EventID() string {
 	return e.eventID
 }
@@ -43,8 +57,26 @@ func (e BaseEvent) AggregateID() string {
 	return e.aggregateID
 }
 
+func (e BaseEvent) CorrelationID() string {
+	return e.correlationID
+}
+
+func (e BaseEvent) CausationID() string {
+	return e.causationID
+}
+
+// WithCorrelation returns a copy of the event base tied to the given correlation
+func (e BaseEvent) WithCorrelation(c Correlation) BaseEvent {
+	e.correlationID = c.CorrelationID
+	e.causationID = c.CausationID
+	return e
+}
+
+// EventHandler reacts to published events
+// Handle gets a context in the event's correlation, continuing the trace of the work
+// that published it where the bus knows it
 type EventHandler interface {
-	Handle(event DomainEvent) error
+	Handle(ctx context.Context, event DomainEvent) error
 	CanHandle(eventType string) bool
 }
 type EventBus interface {
@@ -0,0 +1,80 @@
+package shared
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidPageRequest is returned for unknown sort fields, bad directions or malformed cursors.
+// Match it with errors.Is
+var ErrInvalidPageRequest = errors.New("invalid page request")
+
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// Page is one slice of a list query plus what the caller needs to fetch the next one
+type Page[T any] struct {
+	Items      []T
+	Total      int64  // Rows matching the filters, regardless of limit, offset or cursor
+	NextCursor string // Opaque cursor for the following page, empty on the last page
+}
+
+// ValidateSort checks an ordering against the fields an aggregate allows sorting on
+// Empty values are accepted, repositories fall back to their default ordering
+func ValidateSort(orderBy, orderDir string, allowed []string) error {
+	if orderBy != "" && !containsField(allowed, orderBy) {
+		return fmt.Errorf("%w: cannot sort by %q, expected one of %s",
+			ErrInvalidPageRequest, orderBy, strings.Join(allowed, ", "))
+	}
+	switch strings.ToLower(orderDir) {
+	case "", SortAsc, SortDesc:
+		return nil
+	default:
+		return fmt.Errorf("%w: sort direction must be asc or desc, got %q", ErrInvalidPageRequest, orderDir)
+	}
+}
+
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Cursor marks the last row of a keyset page, ordered by (created_at, id)
+type Cursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+}
+
+func NewCursor(createdAt time.Time, id string) Cursor {
+	return Cursor{CreatedAt: createdAt, ID: id}
+}
+
+// Encode renders the cursor as an opaque URL-safe token
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor reads back a token produced by Cursor.Encode
+func DecodeCursor(token string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidPageRequest)
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return Cursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidPageRequest)
+	}
+	return cursor, nil
+}
@@ -59,6 +59,8 @@ type Tag struct {
 	IsSystem    bool   // System tags cannot be deleted by users
 	CreatedAt   shared.Timestamp
 	UpdatedAt   shared.Timestamp
+	Version     uint64            // Optimistic concurrency version, 0 until first saved
+	DeletedAt   *shared.Timestamp // Set while the tag is in the trash
 	Events      []shared.DomainEvent
 }
 
@@ -149,6 +151,16 @@ func (t *Tag) Delete() error {
 	return nil
 }
 
+// Restore takes the tag back out of the trash
+func (t *Tag) Restore() {
+	t.DeletedAt = nil
+	t.addEvent(TagRestored{
+		BaseEvent: shared.NewBaseEvent(EventTagRestored, t.Id.String()),
+		TagID:     t.Id.String(),
+		Name:      t.Name,
+	})
+}
+
 func (t *Tag) addEvent(event shared.DomainEvent) {
 	t.Events = append(t.Events, event)
 }
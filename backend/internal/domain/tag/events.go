@@ -3,8 +3,9 @@ package tag
 import "parrotflow/internal/domain/shared"
 
 const (
-	EventTagCreated = "tag.created"
-	EventTagDeleted = "tag.deleted"
+	EventTagCreated  = "tag.created"
+	EventTagDeleted  = "tag.deleted"
+	EventTagRestored = "tag.restored"
 )
 
 type TagCreated struct {
@@ -20,3 +21,9 @@ type TagDeleted struct {
 	TagID string
 	Name  string
 }
+
+type TagRestored struct {
+	shared.BaseEvent
+	TagID string
+	Name  string
+}
@@ -22,9 +22,15 @@ type Repository interface {
 	// FindByIDs retrieves multiple tags by their IDs
 	FindByIDs(ctx context.Context, ids []TagID) ([]*Tag, error)
 
-	// Delete removes a tag
+	// FindTrashed retrieves all tags currently in the trash
+	FindTrashed(ctx context.Context) ([]*Tag, error)
+
+	// Delete moves a tag to the trash
 	Delete(ctx context.Context, id TagID) error
 
+	// Restore takes a tag back out of the trash
+	Restore(ctx context.Context, id TagID) error
+
 	// Exists checks if a tag with the given name already exists
 	Exists(ctx context.Context, name string) (bool, error)
 }
@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// DeliveryStatus represents where a delivery is in its retry lifecycle
+type DeliveryStatus struct {
+	value string
+}
+
+func NewDeliveryStatus(value string) (DeliveryStatus, error) {
+	switch strings.ToLower(value) {
+	case "pending":
+		return DeliveryStatusPending, nil
+	case "succeeded":
+		return DeliveryStatusSucceeded, nil
+	case "failed":
+		return DeliveryStatusFailed, nil
+	default:
+		return DeliveryStatus{}, errors.New("invalid delivery status")
+	}
+}
+
+func (s DeliveryStatus) String() string {
+	return s.value
+}
+
+var (
+	DeliveryStatusPending   = DeliveryStatus{value: "pending"}   // Waiting for its next attempt
+	DeliveryStatusSucceeded = DeliveryStatus{value: "succeeded"} // The endpoint answered with 2xx
+	DeliveryStatusFailed    = DeliveryStatus{value: "failed"}    // Retries exhausted or the webhook went away
+)
+
+// Delivery is one event sent to one webhook, kept as the delivery log
+type Delivery struct {
+	ID        string
+	WebhookID WebhookID
+	EventID   string
+	EventType string
+	Payload   []byte // Request body, fixed at enqueue time so retries send the same bytes
+
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	ResponseStatus int // HTTP status of the last attempt, 0 if no response was received
+	LastError      string
+	Duration       time.Duration // Round trip of the last attempt
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+}
+
+// NewDelivery creates a pending delivery due immediately
+func NewDelivery(webhookID WebhookID, eventID, eventType string, payload []byte, now time.Time) *Delivery {
+	return &Delivery{
+		WebhookID:     webhookID,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        DeliveryStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+// Succeed records a successful attempt
+func (d *Delivery) Succeed(responseStatus int, duration time.Duration, at time.Time) {
+	d.Attempts++
+	d.Status = DeliveryStatusSucceeded
+	d.ResponseStatus = responseStatus
+	d.Duration = duration
+	d.LastError = ""
+	d.DeliveredAt = &at
+}
+
+// Retry records a failed attempt that will be tried again at next
+func (d *Delivery) Retry(responseStatus int, duration time.Duration, cause string, next time.Time) {
+	d.Attempts++
+	d.ResponseStatus = responseStatus
+	d.Duration = duration
+	d.LastError = cause
+	d.NextAttemptAt = next
+}
+
+// Fail records a final failed attempt
+func (d *Delivery) Fail(responseStatus int, duration time.Duration, cause string) {
+	d.Attempts++
+	d.Status = DeliveryStatusFailed
+	d.ResponseStatus = responseStatus
+	d.Duration = duration
+	d.LastError = cause
+}
+
+// Abandon fails a delivery without attempting it, e.g. because the webhook was disabled
+func (d *Delivery) Abandon(cause string) {
+	d.Status = DeliveryStatusFailed
+	d.LastError = cause
+}
@@ -0,0 +1,256 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"parrotflow/internal/domain/shared"
+	"strings"
+	"time"
+)
+
+// Domain errors
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
+	ErrWebhookDisabled = errors.New("webhook is disabled")
+)
+
+// AllEvents subscribes a webhook to every event type
+const AllEvents = "*"
+
+// MinSecretLength keeps signing secrets long enough to resist brute force
+const MinSecretLength = 16
+
+type WebhookID struct {
+	shared.ID
+}
+
+func NewWebhookID(value string) (WebhookID, error) {
+	id, err := shared.NewID(value)
+	if err != nil {
+		return WebhookID{}, err
+	}
+	return WebhookID{ID: id}, nil
+}
+
+// Webhook is an HTTP endpoint that receives domain events as signed JSON payloads
+type Webhook struct {
+	Id          WebhookID
+	URL         string
+	EventTypes  []string // Event types delivered to the endpoint, AllEvents for every type
+	Secret      string   // HMAC-SHA256 key used to sign payloads
+	Description string
+	Enabled     bool
+
+	// ConsecutiveFailures counts deliveries that exhausted their retries since the last success
+	ConsecutiveFailures int
+	DisabledReason      string
+
+	CreatedAt shared.Timestamp
+	UpdatedAt shared.Timestamp
+	Version   uint64 // Optimistic concurrency version, 0 until first saved
+	Events    []shared.DomainEvent
+}
+
+// NewWebhook creates an enabled webhook
+func NewWebhook(id WebhookID, endpoint string, eventTypes []string, secret string) (*Webhook, error) {
+	if err := validateURL(endpoint); err != nil {
+		return nil, err
+	}
+	normalized, err := normalizeEventTypes(eventTypes)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSecret(secret); err != nil {
+		return nil, err
+	}
+
+	w := &Webhook{
+		Id:         id,
+		URL:        endpoint,
+		EventTypes: normalized,
+		Secret:     secret,
+		Enabled:    true,
+		CreatedAt:  shared.NewTimestamp(time.Now()),
+		UpdatedAt:  shared.NewTimestamp(time.Now()),
+		Events:     make([]shared.DomainEvent, 0),
+	}
+
+	w.addEvent(WebhookCreated{
+		BaseEvent:  shared.NewBaseEvent(EventWebhookCreated, id.String()),
+		WebhookID:  id.String(),
+		URL:        endpoint,
+		EventTypes: normalized,
+	})
+
+	return w, nil
+}
+
+// NewSecret generates a random signing secret
+func NewSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// UpdateURL changes the endpoint events are delivered to
+func (w *Webhook) UpdateURL(endpoint string) error {
+	if err := validateURL(endpoint); err != nil {
+		return err
+	}
+	w.URL = endpoint
+	w.touch()
+	return nil
+}
+
+// UpdateEventTypes replaces the subscribed event types
+func (w *Webhook) UpdateEventTypes(eventTypes []string) error {
+	normalized, err := normalizeEventTypes(eventTypes)
+	if err != nil {
+		return err
+	}
+	w.EventTypes = normalized
+	w.touch()
+	return nil
+}
+
+// UpdateDescription updates the webhook description
+func (w *Webhook) UpdateDescription(description string) {
+	w.Description = description
+	w.touch()
+}
+
+// RotateSecret replaces the signing secret; deliveries after this use the new one
+func (w *Webhook) RotateSecret(secret string) error {
+	if err := validateSecret(secret); err != nil {
+		return err
+	}
+	w.Secret = secret
+	w.touch()
+	return nil
+}
+
+// Subscribes reports whether an event of the given type should be delivered
+func (w *Webhook) Subscribes(eventType string) bool {
+	if !w.Enabled {
+		return false
+	}
+	for _, t := range w.EventTypes {
+		if t == AllEvents || t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Enable re-enables a webhook and forgets previous failures
+func (w *Webhook) Enable() {
+	if w.Enabled {
+		return
+	}
+	w.Enabled = true
+	w.ConsecutiveFailures = 0
+	w.DisabledReason = ""
+	w.touch()
+
+	w.addEvent(WebhookEnabled{
+		BaseEvent: shared.NewBaseEvent(EventWebhookEnabled, w.Id.String()),
+		WebhookID: w.Id.String(),
+	})
+}
+
+// Disable stops deliveries until the webhook is enabled again
+func (w *Webhook) Disable(reason string) {
+	if !w.Enabled {
+		return
+	}
+	w.Enabled = false
+	w.DisabledReason = reason
+	w.touch()
+
+	w.addEvent(WebhookDisabled{
+		BaseEvent: shared.NewBaseEvent(EventWebhookDisabled, w.Id.String()),
+		WebhookID: w.Id.String(),
+		URL:       w.URL,
+		Reason:    reason,
+	})
+}
+
+// RecordDeliverySuccess resets the failure streak, reporting whether anything changed
+func (w *Webhook) RecordDeliverySuccess() bool {
+	if w.ConsecutiveFailures == 0 {
+		return false
+	}
+	w.ConsecutiveFailures = 0
+	w.touch()
+	return true
+}
+
+// RecordDeliveryFailure counts a delivery that exhausted its retries and disables
+// the webhook once disableAfter consecutive deliveries failed (0 never disables)
+func (w *Webhook) RecordDeliveryFailure(disableAfter int) {
+	w.ConsecutiveFailures++
+	w.touch()
+
+	if disableAfter > 0 && w.ConsecutiveFailures >= disableAfter {
+		w.Disable(fmt.Sprintf("disabled after %d consecutive failed deliveries", w.ConsecutiveFailures))
+	}
+}
+
+// Delete marks the webhook for deletion
+func (w *Webhook) Delete() {
+	w.addEvent(WebhookDeleted{
+		BaseEvent: shared.NewBaseEvent(EventWebhookDeleted, w.Id.String()),
+		WebhookID: w.Id.String(),
+		URL:       w.URL,
+	})
+}
+
+func (w *Webhook) touch() {
+	w.UpdatedAt = shared.NewTimestamp(time.Now())
+}
+
+func (w *Webhook) addEvent(event shared.DomainEvent) {
+	w.Events = append(w.Events, event)
+}
+
+func (w *Webhook) ClearEvents() {
+	w.Events = make([]shared.DomainEvent, 0)
+}
+
+func validateURL(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("webhook url must be an absolute http or https url, got %q", endpoint)
+	}
+	return nil
+}
+
+func validateSecret(secret string) error {
+	if len(secret) < MinSecretLength {
+		return fmt.Errorf("webhook secret must be at least %d characters", MinSecretLength)
+	}
+	return nil
+}
+
+// normalizeEventTypes trims and deduplicates event types, keeping their order
+func normalizeEventTypes(eventTypes []string) ([]string, error) {
+	normalized := make([]string, 0, len(eventTypes))
+	seen := make(map[string]bool, len(eventTypes))
+	for _, t := range eventTypes {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		normalized = append(normalized, t)
+	}
+	if len(normalized) == 0 {
+		return nil, errors.New("webhook must subscribe to at least one event type")
+	}
+	return normalized, nil
+}
@@ -0,0 +1,35 @@
+package webhook
+
+import "parrotflow/internal/domain/shared"
+
+const (
+	EventWebhookCreated  = "webhook.created"
+	EventWebhookDeleted  = "webhook.deleted"
+	EventWebhookEnabled  = "webhook.enabled"
+	EventWebhookDisabled = "webhook.disabled"
+)
+
+type WebhookCreated struct {
+	shared.BaseEvent
+	WebhookID  string
+	URL        string
+	EventTypes []string
+}
+
+type WebhookDeleted struct {
+	shared.BaseEvent
+	WebhookID string
+	URL       string
+}
+
+type WebhookEnabled struct {
+	shared.BaseEvent
+	WebhookID string
+}
+
+type WebhookDisabled struct {
+	shared.BaseEvent
+	WebhookID string
+	URL       string
+	Reason    string
+}
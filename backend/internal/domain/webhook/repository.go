@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"context"
+	"parrotflow/internal/domain/shared"
+	"time"
+)
+
+// Repository defines the interface for webhook persistence
+type Repository interface {
+	// Save persists a webhook
+	Save(ctx context.Context, webhook *Webhook) error
+
+	// FindByID retrieves a webhook by its ID
+	FindByID(ctx context.Context, id WebhookID) (*Webhook, error)
+
+	// FindAll retrieves all webhooks
+	FindAll(ctx context.Context) ([]*Webhook, error)
+
+	// FindSubscribed retrieves the enabled webhooks subscribed to an event type
+	FindSubscribed(ctx context.Context, eventType string) ([]*Webhook, error)
+
+	// Delete permanently removes a webhook and its delivery log
+	Delete(ctx context.Context, id WebhookID) error
+}
+
+// DeliveryCriteria selects one page of a webhook's delivery log, newest first
+type DeliveryCriteria struct {
+	WebhookID WebhookID
+	Status    *DeliveryStatus
+	Limit     int
+	Offset    int
+}
+
+// DeliveryRepository defines the interface for the delivery log
+type DeliveryRepository interface {
+	// Enqueue stores new deliveries; a webhook receives each event at most once
+	Enqueue(ctx context.Context, deliveries ...*Delivery) error
+
+	// FindDue retrieves pending deliveries whose next attempt is due, oldest first
+	FindDue(ctx context.Context, now time.Time, limit int) ([]*Delivery, error)
+
+	// Update records the outcome of an attempt
+	Update(ctx context.Context, delivery *Delivery) error
+
+	// FindByWebhook retrieves a page of the delivery log
+	FindByWebhook(ctx context.Context, criteria DeliveryCriteria) (shared.Page[*Delivery], error)
+}
@@ -0,0 +1,277 @@
+// Package auth authenticates API callers with API keys or JWT bearer tokens
+//
+// API keys are issued and stored by the application; JWTs come from an external
+// identity provider and are verified with a shared HMAC secret or the provider's
+// JWKS. Either way the caller ends up as a Principal with scopes, which limit what
+// the credential may be used for, and roles, which say what the caller may do
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"parrotflow/internal/domain/access"
+	"parrotflow/internal/domain/apikey"
+
+	"github.com/MicahParks/keyfunc/v3"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Errors returned by Authenticate, the reason is wrapped in ErrInvalidCredentials
+var (
+	ErrMissingCredentials = errors.New("missing credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Ways a principal authenticated
+const (
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+	MethodNone   = "none" // Authentication disabled
+)
+
+// Config selects which bearer tokens are accepted besides API keys
+type Config struct {
+	Disabled    bool          // Every request passes as an anonymous admin, for local development only
+	JWTSecret   string        // HMAC key of HS256, HS384 and HS512 tokens
+	JWKSURL     string        // JWKS of the identity provider signing RS, PS, ES and EdDSA tokens
+	JWKSRefresh time.Duration // How often the JWKS is fetched again
+	Issuer      string        // Required iss claim, empty accepts any
+	Audience    string        // Required aud claim, empty accepts any
+	Leeway      time.Duration // Clock skew tolerated on exp and nbf
+}
+
+func DefaultConfig() Config {
+	return Config{
+		JWKSRefresh: time.Hour,
+		Leeway:      30 * time.Second,
+	}
+}
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Subject string // apikey:<id> for API keys, the sub claim for JWTs
+	Name    string // Key name, or the name claim of a JWT when present
+	Method  string
+	Scopes  []string
+	Roles   []access.Role // Assigned to the subject, plus those in the roles claim of a JWT
+}
+
+// Allows reports whether the principal was granted the scope or a higher one
+func (p *Principal) Allows(scope string) bool {
+	return apikey.ScopesAllow(p.Scopes, scope)
+}
+
+// Can reports whether one of the principal's roles grants the permission
+func (p *Principal) Can(permission access.Permission) bool {
+	return access.AnyGrants(p.Roles, permission)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the authenticated caller
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the authenticated caller of the request ctx belongs to
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
+
+// Authenticator turns presented credentials into a principal
+type Authenticator struct {
+	config      Config
+	keys        apikey.Repository
+	assignments access.Repository
+	jwks        keyfunc.Keyfunc // nil without a JWKS URL
+	parser      *jwt.Parser     // nil when JWTs are not accepted
+	now         func() time.Time
+}
+
+// NewAuthenticator fetches the JWKS when one is configured; the set is refreshed
+// in the background for as long as ctx lives
+func NewAuthenticator(ctx context.Context, config Config, keys apikey.Repository, assignments access.Repository) (*Authenticator, error) {
+	a := &Authenticator{config: config, keys: keys, assignments: assignments, now: time.Now}
+	if config.Disabled {
+		slog.Warn("Authentication is disabled, every request is treated as an admin")
+		return a, nil
+	}
+
+	var methods []string
+	if config.JWTSecret != "" {
+		methods = append(methods, "HS256", "HS384", "HS512")
+	}
+	if config.JWKSURL != "" {
+		jwks, err := keyfunc.NewDefaultOverrideCtx(ctx, []string{config.JWKSURL}, keyfunc.Override{RefreshInterval: config.JWKSRefresh})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JWKS from %s: %w", config.JWKSURL, err)
+		}
+		a.jwks = jwks
+		methods = append(methods, "RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA")
+	}
+	if len(methods) == 0 {
+		return a, nil
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(config.Leeway),
+		jwt.WithTimeFunc(func() time.Time { return a.now() }),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+	a.parser = jwt.NewParser(options...)
+	return a, nil
+}
+
+// Authenticate checks the Authorization and X-API-Key header values of a request
+// A bearer token starting with apikey.TokenPrefix is an API key, any other one a JWT
+func (a *Authenticator) Authenticate(ctx context.Context, authorization, apiKey string) (*Principal, error) {
+	if a.config.Disabled {
+		return &Principal{Subject: "anonymous", Method: MethodNone, Scopes: []string{apikey.ScopeAdmin}, Roles: []access.Role{access.RoleAdmin}}, nil
+	}
+
+	principal, err := a.authenticate(ctx, authorization, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.assignRoles(ctx, principal); err != nil {
+		return nil, err
+	}
+	return principal, nil
+}
+
+func (a *Authenticator) authenticate(ctx context.Context, authorization, apiKey string) (*Principal, error) {
+	if apiKey != "" {
+		return a.authenticateAPIKey(ctx, apiKey)
+	}
+	if authorization == "" {
+		return nil, ErrMissingCredentials
+	}
+
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return nil, fmt.Errorf("%w: expected a bearer token", ErrInvalidCredentials)
+	}
+	token = strings.TrimSpace(token)
+	if strings.HasPrefix(token, apikey.TokenPrefix) {
+		return a.authenticateAPIKey(ctx, token)
+	}
+	return a.authenticateJWT(token)
+}
+
+func (a *Authenticator) authenticateAPIKey(ctx context.Context, token string) (*Principal, error) {
+	prefix, err := apikey.ParsePrefix(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	key, err := a.keys.FindByPrefix(ctx, prefix)
+	if errors.Is(err, apikey.ErrAPIKeyNotFound) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, apikey.ErrInvalidKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := a.now()
+	if err := key.Verify(token, now); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	if key.NeedsUsageUpdate(now) {
+		if err := a.keys.RecordUsage(ctx, key.Id, now); err != nil {
+			slog.WarnContext(ctx, "Error recording API key usage", "api_key_id", key.Id.String(), "error", err)
+		}
+	}
+
+	return &Principal{
+		Subject: key.Subject(),
+		Name:    key.Name,
+		Method:  MethodAPIKey,
+		Scopes:  key.Scopes,
+	}, nil
+}
+
+// assignRoles adds the roles assigned to the principal's subject
+func (a *Authenticator) assignRoles(ctx context.Context, principal *Principal) error {
+	assignment, err := a.assignments.FindBySubject(ctx, principal.Subject)
+	if errors.Is(err, access.ErrAssignmentNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	roles := make([]string, 0, len(principal.Roles)+len(assignment.Roles))
+	for _, role := range append(principal.Roles, assignment.Roles...) {
+		roles = append(roles, role.String())
+	}
+	principal.Roles, err = access.NormalizeRoles(roles)
+	return err
+}
+
+// claims are the registered claims plus the ones carrying scopes, roles and a display name
+type claims struct {
+	jwt.RegisteredClaims
+	Scope string           `json:"scope,omitempty"` // Space-separated, RFC 8693
+	Scp   jwt.ClaimStrings `json:"scp,omitempty"`   // Azure AD and Okta
+	Roles jwt.ClaimStrings `json:"roles,omitempty"` // Managed by the identity provider
+	Name  string           `json:"name,omitempty"`
+}
+
+func (a *Authenticator) authenticateJWT(token string) (*Principal, error) {
+	if a.parser == nil {
+		return nil, fmt.Errorf("%w: bearer tokens are not accepted, configure a JWT secret or JWKS", ErrInvalidCredentials)
+	}
+
+	var c claims
+	if _, err := a.parser.ParseWithClaims(token, &c, a.keyFor); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+	if c.Subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrInvalidCredentials)
+	}
+
+	scopes := strings.Fields(c.Scope)
+	scopes = append(scopes, c.Scp...)
+	return &Principal{
+		Subject: c.Subject,
+		Name:    c.Name,
+		Method:  MethodJWT,
+		Scopes:  scopes,
+		Roles:   knownRoles(c.Roles),
+	}, nil
+}
+
+// knownRoles keeps the roles of a claim this service defines; identity providers
+// often put roles of other applications in the same claim
+func knownRoles(names []string) []access.Role {
+	var roles []access.Role
+	for _, name := range names {
+		if role, err := access.NewRole(name); err == nil {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// keyFor picks the verification key by algorithm; WithValidMethods already
+// rejected algorithms without a configured key
+func (a *Authenticator) keyFor(token *jwt.Token) (any, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		return []byte(a.config.JWTSecret), nil
+	}
+	return a.jwks.Keyfunc(token)
+}
@@ -0,0 +1,258 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"parrotflow/internal/domain/access"
+	"parrotflow/internal/domain/apikey"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// MockAPIKeyRepository keeps API keys in memory
+type MockAPIKeyRepository struct {
+	apikey.Repository
+	keys  map[string]*apikey.APIKey
+	usage int
+}
+
+func (m *MockAPIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*apikey.APIKey, error) {
+	for _, k := range m.keys {
+		if k.Prefix == prefix {
+			return k, nil
+		}
+	}
+	return nil, apikey.ErrAPIKeyNotFound
+}
+
+func (m *MockAPIKeyRepository) RecordUsage(ctx context.Context, id apikey.APIKeyID, usedAt time.Time) error {
+	m.usage++
+	m.keys[id.String()].LastUsedAt = &usedAt
+	return nil
+}
+
+// MockRoleAssignmentRepository keeps role assignments in memory
+type MockRoleAssignmentRepository struct {
+	access.Repository
+	roles map[string][]access.Role
+}
+
+func (m *MockRoleAssignmentRepository) FindBySubject(ctx context.Context, subject string) (*access.RoleAssignment, error) {
+	roles, ok := m.roles[subject]
+	if !ok {
+		return nil, access.ErrAssignmentNotFound
+	}
+	return &access.RoleAssignment{Subject: subject, Roles: roles}, nil
+}
+
+func issueKey(t *testing.T, repository *MockAPIKeyRepository, scopes ...string) *apikey.IssuedKey {
+	t.Helper()
+	id, _ := apikey.NewAPIKeyID("1")
+	issued, err := apikey.Issue(id, "ci", scopes, nil)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	repository.keys[id.String()] = issued.APIKey
+	return issued
+}
+
+func TestAuthenticator_APIKey(t *testing.T) {
+	ctx := context.Background()
+	repository := &MockAPIKeyRepository{keys: map[string]*apikey.APIKey{}}
+	issued := issueKey(t, repository, apikey.ScopeWrite)
+	authenticator, err := NewAuthenticator(ctx, DefaultConfig(), repository, &MockRoleAssignmentRepository{})
+	if err != nil {
+		t.Fatalf("NewAuthenticator() error = %v", err)
+	}
+
+	// The key is accepted in its own header and as a bearer token
+	for _, presented := range [][2]string{{"", issued.Token}, {"Bearer " + issued.Token, ""}} {
+		principal, err := authenticator.Authenticate(ctx, presented[0], presented[1])
+		if err != nil {
+			t.Fatalf("Authenticate() error = %v", err)
+		}
+		if principal.Subject != "apikey:1" || principal.Method != MethodAPIKey {
+			t.Errorf("Principal = %+v, want apikey:1 authenticated by API key", principal)
+		}
+		if !principal.Allows(apikey.ScopeRead) || !principal.Allows(apikey.ScopeWrite) || principal.Allows(apikey.ScopeAdmin) {
+			t.Errorf("Scopes = %v, want read and write but not admin", principal.Scopes)
+		}
+	}
+	if repository.usage != 1 {
+		t.Errorf("Usage recorded %d times, want once within a minute", repository.usage)
+	}
+
+	if _, err := authenticator.Authenticate(ctx, "", ""); !errors.Is(err, ErrMissingCredentials) {
+		t.Errorf("Authenticate(nothing) error = %v, want ErrMissingCredentials", err)
+	}
+	if _, err := authenticator.Authenticate(ctx, "", issued.Token+"x"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Authenticate(wrong secret) error = %v, want ErrInvalidCredentials", err)
+	}
+
+	issued.APIKey.Revoke()
+	if _, err := authenticator.Authenticate(ctx, "", issued.Token); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Authenticate(revoked) error = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestAuthenticator_JWTSecret(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultConfig()
+	config.JWTSecret = "0123456789abcdef0123456789abcdef"
+	config.Issuer = "https://id.example.com"
+	authenticator, err := NewAuthenticator(ctx, config, &MockAPIKeyRepository{}, &MockRoleAssignmentRepository{})
+	if err != nil {
+		t.Fatalf("NewAuthenticator() error = %v", err)
+	}
+
+	sign := func(claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.JWTSecret))
+		if err != nil {
+			t.Fatalf("SignedString() error = %v", err)
+		}
+		return "Bearer " + token
+	}
+	valid := jwt.MapClaims{"sub": "alice", "iss": config.Issuer, "scope": "read", "exp": time.Now().Add(time.Hour).Unix()}
+
+	principal, err := authenticator.Authenticate(ctx, sign(valid), "")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if principal.Subject != "alice" || principal.Method != MethodJWT || !principal.Allows(apikey.ScopeRead) || principal.Allows(apikey.ScopeWrite) {
+		t.Errorf("Principal = %+v, want alice with the read scope", principal)
+	}
+
+	rejected := map[string]jwt.MapClaims{
+		"expired":      {"sub": "alice", "iss": config.Issuer, "exp": time.Now().Add(-time.Hour).Unix()},
+		"no expiry":    {"sub": "alice", "iss": config.Issuer},
+		"other issuer": {"sub": "alice", "iss": "https://evil.example.com", "exp": time.Now().Add(time.Hour).Unix()},
+	}
+	for name, claims := range rejected {
+		if _, err := authenticator.Authenticate(ctx, sign(claims), ""); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Authenticate(%s) error = %v, want ErrInvalidCredentials", name, err)
+		}
+	}
+
+	// Tokens signed with another secret or algorithm are rejected
+	forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, valid).SignedString([]byte("another secret entirely"))
+	if _, err := authenticator.Authenticate(ctx, "Bearer "+forged, ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Authenticate(forged) error = %v, want ErrInvalidCredentials", err)
+	}
+	unsigned, _ := jwt.NewWithClaims(jwt.SigningMethodNone, valid).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if _, err := authenticator.Authenticate(ctx, "Bearer "+unsigned, ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Authenticate(alg none) error = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestAuthenticator_JWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": "k1",
+		"alg": "RS256",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(jwks)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config := DefaultConfig()
+	config.JWKSURL = server.URL
+	config.Audience = "parrotflow"
+	authenticator, err := NewAuthenticator(ctx, config, &MockAPIKeyRepository{}, &MockRoleAssignmentRepository{})
+	if err != nil {
+		t.Fatalf("NewAuthenticator() error = %v", err)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"sub": "bob",
+		"aud": "parrotflow",
+		"scp": []string{"read", "write"},
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = "k1"
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	principal, err := authenticator.Authenticate(ctx, "Bearer "+signed, "")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if principal.Subject != "bob" || !principal.Allows(apikey.ScopeWrite) {
+		t.Errorf("Principal = %+v, want bob with the write scope", principal)
+	}
+}
+
+func TestAuthenticator_Roles(t *testing.T) {
+	ctx := context.Background()
+	keys := &MockAPIKeyRepository{keys: map[string]*apikey.APIKey{}}
+	issued := issueKey(t, keys, apikey.ScopeWrite)
+	assignments := &MockRoleAssignmentRepository{roles: map[string][]access.Role{
+		"apikey:1": {access.RoleOperator},
+		"alice":    {access.RoleAuthor},
+	}}
+
+	config := DefaultConfig()
+	config.JWTSecret = "0123456789abcdef0123456789abcdef"
+	authenticator, err := NewAuthenticator(ctx, config, keys, assignments)
+	if err != nil {
+		t.Fatalf("NewAuthenticator() error = %v", err)
+	}
+
+	principal, err := authenticator.Authenticate(ctx, "", issued.Token)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if !principal.Can(access.PermissionRunsExecute) || principal.Can(access.PermissionScenariosEdit) {
+		t.Errorf("Roles = %v, want an operator who cannot edit scenarios", principal.Roles)
+	}
+
+	// Roles of the claim are added to the assigned ones, unknown roles are ignored
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "alice",
+		"roles": []string{"operator", "billing-admin"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(config.JWTSecret))
+	principal, err = authenticator.Authenticate(ctx, "Bearer "+token, "")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	want := []access.Role{access.RoleOperator, access.RoleAuthor}
+	if len(principal.Roles) != len(want) || principal.Roles[0] != want[0] || principal.Roles[1] != want[1] {
+		t.Errorf("Roles = %v, want %v", principal.Roles, want)
+	}
+
+	// Without an assignment or claim a principal authenticates but may do nothing
+	token, _ = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "mallory",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(config.JWTSecret))
+	principal, err = authenticator.Authenticate(ctx, "Bearer "+token, "")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if principal.Can(access.PermissionRead) {
+		t.Errorf("Roles = %v, want none", principal.Roles)
+	}
+}
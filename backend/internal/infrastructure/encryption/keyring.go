@@ -0,0 +1,222 @@
+// Package encryption seals credentials the application has to store, such as proxy
+// passwords, with envelope encryption
+//
+// Every value is encrypted with its own random data key using AES-256-GCM, and the
+// data key is encrypted with a key encryption key from the keyring. Stored values
+// record the ID of that key, so the keyring can hold new and retired keys at once
+// while rows are re-encrypted with `parrotflow keys rotate`.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Errors returned while loading keys or opening values
+var (
+	ErrInvalidKey     = errors.New("invalid encryption key")
+	ErrUnknownKey     = errors.New("value is encrypted with a key missing from the keyring")
+	ErrMalformedValue = errors.New("malformed encrypted value")
+)
+
+// KeySize is the length of key encryption keys and data keys, AES-256
+const KeySize = 32
+
+// sealedPrefix starts every sealed value and names its format
+const sealedPrefix = "v1."
+
+// Config tells where the key encryption keys come from
+// Keys are written id:base64-key and separated by commas or newlines; the first one
+// encrypts new values and the others can only decrypt
+type Config struct {
+	Keys    string // Keys inline, usually from an environment variable
+	KeyFile string // File holding keys, read in addition to Keys
+}
+
+// Keyring encrypts values with its primary key and decrypts with any of its keys
+// A keyring without keys stores values as they are, recording an empty key ID
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring loads the keys of the configuration
+func NewKeyring(config Config) (*Keyring, error) {
+	source := config.Keys
+	if config.KeyFile != "" {
+		content, err := os.ReadFile(config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading key file: %w", err)
+		}
+		source += "\n" + string(content)
+	}
+
+	keyring := &Keyring{keys: map[string]cipher.AEAD{}}
+	for _, entry := range strings.FieldsFunc(source, func(r rune) bool { return r == ',' || r == '\n' }) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		if err := keyring.add(entry); err != nil {
+			return nil, err
+		}
+	}
+	return keyring, nil
+}
+
+func (k *Keyring) add(entry string) error {
+	id, encoded, ok := strings.Cut(entry, ":")
+	id = strings.TrimSpace(id)
+	if !ok || id == "" {
+		return fmt.Errorf("%w: expected id:base64-key", ErrInvalidKey)
+	}
+	if _, exists := k.keys[id]; exists {
+		return fmt.Errorf("%w: key %q is listed twice", ErrInvalidKey, id)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != KeySize {
+		return fmt.Errorf("%w: key %q must be %d bytes, base64 encoded", ErrInvalidKey, id, KeySize)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	k.keys[id] = aead
+	if k.primary == "" {
+		k.primary = id
+	}
+	return nil
+}
+
+// GenerateKey returns a new key entry for the keyring configuration
+func GenerateKey(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, ":,\n") {
+		return "", fmt.Errorf("%w: key IDs cannot be empty or contain ':' or ','", ErrInvalidKey)
+	}
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return id + ":" + base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Enabled reports whether the keyring has keys, without them values are stored in plaintext
+func (k *Keyring) Enabled() bool {
+	return k.primary != ""
+}
+
+// PrimaryKeyID is the ID of the key new values are encrypted with, empty without keys
+func (k *Keyring) PrimaryKeyID() string {
+	return k.primary
+}
+
+// Encrypt seals a value with a fresh data key and returns it with the ID of the key
+// encryption key; without keys the value is returned as is with an empty ID
+//
+//	v1.<base64 nonce+sealed data key>.<base64 nonce+sealed value>
+func (k *Keyring) Encrypt(plaintext string) (string, string, error) {
+	if !k.Enabled() {
+		return plaintext, "", nil
+	}
+
+	dataKey := make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", "", err
+	}
+	valueAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	// The key ID is authenticated, a data key cannot be moved under another key
+	wrappedKey, err := seal(k.keys[k.primary], dataKey, []byte(k.primary))
+	if err != nil {
+		return "", "", err
+	}
+	sealedValue, err := seal(valueAEAD, []byte(plaintext), nil)
+	if err != nil {
+		return "", "", err
+	}
+
+	encoded := sealedPrefix + base64.RawStdEncoding.EncodeToString(wrappedKey) + "." + base64.RawStdEncoding.EncodeToString(sealedValue)
+	return encoded, k.primary, nil
+}
+
+// Decrypt opens a value Encrypt sealed with the key keyID; values stored before
+// encryption was configured have an empty key ID and are returned as they are
+func (k *Keyring) Decrypt(ciphertext, keyID string) (string, error) {
+	if keyID == "" {
+		return ciphertext, nil
+	}
+	keyAEAD, ok := k.keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+
+	encoded, ok := strings.CutPrefix(ciphertext, sealedPrefix)
+	if !ok {
+		return "", ErrMalformedValue
+	}
+	encodedKey, encodedValue, ok := strings.Cut(encoded, ".")
+	if !ok {
+		return "", ErrMalformedValue
+	}
+	wrappedKey, err := base64.RawStdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return "", ErrMalformedValue
+	}
+	sealedValue, err := base64.RawStdEncoding.DecodeString(encodedValue)
+	if err != nil {
+		return "", ErrMalformedValue
+	}
+
+	dataKey, err := open(keyAEAD, wrappedKey, []byte(keyID))
+	if err != nil {
+		return "", err
+	}
+	valueAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(valueAEAD, sealedValue, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts with a random nonce, which is prepended to the result
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformedValue
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedValue, err)
+	}
+	return plaintext, nil
+}
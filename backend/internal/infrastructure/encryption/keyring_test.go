@@ -0,0 +1,150 @@
+package encryption
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestKeyring(t *testing.T, ids ...string) (*Keyring, []string) {
+	t.Helper()
+	var entries []string
+	for _, id := range ids {
+		entry, err := GenerateKey(id)
+		if err != nil {
+			t.Fatalf("GenerateKey(%q) failed: %v", id, err)
+		}
+		entries = append(entries, entry)
+	}
+	keyring, err := NewKeyring(Config{Keys: strings.Join(entries, ",")})
+	if err != nil {
+		t.Fatalf("NewKeyring failed: %v", err)
+	}
+	return keyring, entries
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	keyring, _ := newTestKeyring(t, "k1")
+
+	ciphertext, keyID, err := keyring.Encrypt("hunter2")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if keyID != "k1" {
+		t.Errorf("Expected key ID k1, got %q", keyID)
+	}
+	if !strings.HasPrefix(ciphertext, sealedPrefix) || strings.Contains(ciphertext, "hunter2") {
+		t.Errorf("Expected a sealed value, got %q", ciphertext)
+	}
+
+	again, _, _ := keyring.Encrypt("hunter2")
+	if again == ciphertext {
+		t.Error("Expected every encryption to use a fresh data key and nonce")
+	}
+
+	plaintext, err := keyring.Decrypt(ciphertext, keyID)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if plaintext != "hunter2" {
+		t.Errorf("Expected hunter2, got %q", plaintext)
+	}
+}
+
+func TestKeyring_WithoutKeysStoresPlaintext(t *testing.T) {
+	keyring, err := NewKeyring(Config{})
+	if err != nil {
+		t.Fatalf("NewKeyring failed: %v", err)
+	}
+	if keyring.Enabled() {
+		t.Error("Expected a keyring without keys to be disabled")
+	}
+
+	value, keyID, err := keyring.Encrypt("hunter2")
+	if err != nil || value != "hunter2" || keyID != "" {
+		t.Errorf("Expected the value as is with an empty key ID, got %q %q %v", value, keyID, err)
+	}
+	if plaintext, err := keyring.Decrypt("hunter2", ""); err != nil || plaintext != "hunter2" {
+		t.Errorf("Expected values without a key ID to pass through, got %q %v", plaintext, err)
+	}
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	old, entries := newTestKeyring(t, "old")
+	ciphertext, keyID, _ := old.Encrypt("hunter2")
+
+	// The new key goes first, the old one stays to decrypt what it sealed
+	newEntry, _ := GenerateKey("new")
+	rotated, err := NewKeyring(Config{Keys: newEntry + "," + entries[0]})
+	if err != nil {
+		t.Fatalf("NewKeyring failed: %v", err)
+	}
+	if rotated.PrimaryKeyID() != "new" {
+		t.Errorf("Expected the first key to be primary, got %q", rotated.PrimaryKeyID())
+	}
+	if plaintext, err := rotated.Decrypt(ciphertext, keyID); err != nil || plaintext != "hunter2" {
+		t.Errorf("Expected the retired key to still decrypt, got %q %v", plaintext, err)
+	}
+
+	withoutOld, _ := NewKeyring(Config{Keys: newEntry})
+	if _, err := withoutOld.Decrypt(ciphertext, keyID); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected ErrUnknownKey once the old key is dropped, got %v", err)
+	}
+}
+
+func TestKeyring_RejectsTampering(t *testing.T) {
+	keyring, _ := newTestKeyring(t, "k1", "k2")
+	ciphertext, _, _ := keyring.Encrypt("hunter2")
+
+	// The wrapped data key is bound to its key ID
+	if _, err := keyring.Decrypt(ciphertext, "k2"); !errors.Is(err, ErrMalformedValue) {
+		t.Errorf("Expected ErrMalformedValue under another key ID, got %v", err)
+	}
+
+	tampered := ciphertext[:len(ciphertext)-2] + "AA"
+	if tampered == ciphertext {
+		tampered = ciphertext[:len(ciphertext)-2] + "BB"
+	}
+	if _, err := keyring.Decrypt(tampered, "k1"); !errors.Is(err, ErrMalformedValue) {
+		t.Errorf("Expected ErrMalformedValue for a modified value, got %v", err)
+	}
+	if _, err := keyring.Decrypt("hunter2", "k1"); !errors.Is(err, ErrMalformedValue) {
+		t.Errorf("Expected ErrMalformedValue for an unsealed value, got %v", err)
+	}
+}
+
+func TestNewKeyring_KeyFile(t *testing.T) {
+	entry, _ := GenerateKey("file")
+	path := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(path, []byte("# rotated 2026-10\n"+entry+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	keyring, err := NewKeyring(Config{KeyFile: path})
+	if err != nil {
+		t.Fatalf("NewKeyring failed: %v", err)
+	}
+	if keyring.PrimaryKeyID() != "file" {
+		t.Errorf("Expected key from the file, got %q", keyring.PrimaryKeyID())
+	}
+}
+
+func TestNewKeyring_InvalidKeys(t *testing.T) {
+	entry, _ := GenerateKey("k1")
+	tests := map[string]string{
+		"missing id": "bm90IGEga2V5",
+		"short key":  "k1:" + "c2hvcnQ=",
+		"not base64": "k1:???",
+		"duplicate":  entry + "," + entry,
+		"empty id":   ":" + strings.TrimPrefix(entry, "k1:"),
+	}
+	for name, keys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewKeyring(Config{Keys: keys}); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Expected ErrInvalidKey, got %v", err)
+			}
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package events
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/logging"
 	"sync"
 )
 
@@ -18,57 +20,27 @@ func NewInMemoryEventBus() *InMemoryEventBus {
 }
 
 func (bus *InMemoryEventBus) Publish(event shared.DomainEvent) error {
-	bus.mu.RLock()
-	defer bus.mu.RUnlock()
-
-	for _, handler := range bus.handlers {
-		if handler.CanHandle(event.EventType()) {
-			if err := handler.Handle(event); err != nil {
-				log.Printf("Error handling event %s: %v", event.EventType(), err)
-			}
-		}
-	}
-
-	return nil
-}
-
-func (bus *InMemoryEventBus) Subscribe(handler shared.EventHandler) error {
-	bus.mu.Lock()
-	defer bus.mu.Unlock()
-
-	bus.handlers = append(bus.handlers, handler)
-	return nil
-}
-
-type AsyncEventBus struct {
-	handlers []shared.EventHandler
-	mu       sync.RWMutex
+	return bus.PublishContext(context.Background(), event)
 }
 
-func NewAsyncEventBus() *AsyncEventBus {
-	return &AsyncEventBus{
-		handlers: make([]shared.EventHandler, 0),
-	}
-}
-
-func (bus *AsyncEventBus) Publish(event shared.DomainEvent) error {
+// PublishContext runs the handlers in ctx, in the correlation of the event
+func (bus *InMemoryEventBus) PublishContext(ctx context.Context, event shared.DomainEvent) error {
 	bus.mu.RLock()
 	defer bus.mu.RUnlock()
 
+	ctx = shared.CausedBy(ctx, event)
 	for _, handler := range bus.handlers {
 		if handler.CanHandle(event.EventType()) {
-			go func(h shared.EventHandler, e shared.DomainEvent) {
-				if err := h.Handle(e); err != nil {
-					log.Printf("Error handling event %s: %v", e.EventType(), err)
-				}
-			}(handler, event)
+			if err := handler.Handle(ctx, event); err != nil {
+				slog.ErrorContext(ctx, "Error handling event", logging.Event(event), "error", err)
+			}
 		}
 	}
 
 	return nil
 }
 
-func (bus *AsyncEventBus) Subscribe(handler shared.EventHandler) error {
+func (bus *InMemoryEventBus) Subscribe(handler shared.EventHandler) error {
 	bus.mu.Lock()
 	defer bus.mu.Unlock()
 
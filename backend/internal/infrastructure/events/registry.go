@@ -0,0 +1,91 @@
+package events
+
+import (
+	"parrotflow/internal/domain/access"
+	"parrotflow/internal/domain/agent"
+	"parrotflow/internal/domain/apikey"
+	"parrotflow/internal/domain/deadletter"
+	"parrotflow/internal/domain/enrollment"
+	"parrotflow/internal/domain/proxy"
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/secret"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/domain/tag"
+	"parrotflow/internal/domain/webhook"
+)
+
+// NewDefaultRegistry creates a registry that knows every domain event type
+func NewDefaultRegistry() *shared.EventRegistry {
+	r := shared.NewEventRegistry()
+
+	shared.RegisterEvent[agent.AgentRegistered](r, agent.EventAgentRegistered)
+	shared.RegisterEvent[agent.AgentDeregistered](r, agent.EventAgentDeregistered)
+	shared.RegisterEvent[agent.AgentStatusChanged](r, agent.EventAgentStatusChanged)
+	shared.RegisterEvent[agent.AgentDisconnected](r, agent.EventAgentDisconnected)
+	shared.RegisterEvent[agent.AgentCapabilitiesUpdated](r, agent.EventAgentCapabilitiesUpdated)
+	shared.RegisterEvent[agent.AgentRevoked](r, agent.EventAgentRevoked)
+
+	shared.RegisterEvent[proxy.ProxyCreated](r, proxy.EventProxyCreated)
+	shared.RegisterEvent[proxy.ProxyStatusChanged](r, proxy.EventProxyStatusChanged)
+	shared.RegisterEvent[proxy.ProxyFailed](r, proxy.EventProxyFailed)
+	shared.RegisterEvent[proxy.ProxyDeleted](r, proxy.EventProxyDeleted)
+	shared.RegisterEvent[proxy.ProxyRestored](r, proxy.EventProxyRestored)
+
+	shared.RegisterEvent[tag.TagCreated](r, tag.EventTagCreated)
+	shared.RegisterEvent[tag.TagDeleted](r, tag.EventTagDeleted)
+	shared.RegisterEvent[tag.TagRestored](r, tag.EventTagRestored)
+
+	shared.RegisterEvent[scenario.ScenarioCreated](r, scenario.EventScenarioCreated)
+	shared.RegisterEvent[scenario.ScenarioUpdated](r, scenario.EventScenarioUpdated)
+	shared.RegisterEvent[scenario.ScenarioDeleted](r, scenario.EventScenarioDeleted)
+	shared.RegisterEvent[scenario.ScenarioRestored](r, scenario.EventScenarioRestored)
+	shared.RegisterEvent[scenario.ScenarioContextUpdated](r, scenario.EventScenarioContextUpdated)
+	shared.RegisterEvent[scenario.ScenarioParametersUpdated](r, scenario.EventScenarioParametersUpdated)
+
+	shared.RegisterEvent[run.RunCreated](r, run.EventRunCreated)
+	shared.RegisterEvent[run.RunStarted](r, run.EventRunStarted)
+	shared.RegisterEvent[run.RunCompleted](r, run.EventRunCompleted)
+	shared.RegisterEvent[run.RunFailed](r, run.EventRunFailed)
+	shared.RegisterEvent[run.RunCancelled](r, run.EventRunCancelled)
+	shared.RegisterEvent[run.RunProgress](r, run.EventRunProgress)
+
+	shared.RegisterEvent[webhook.WebhookCreated](r, webhook.EventWebhookCreated)
+	shared.RegisterEvent[webhook.WebhookDeleted](r, webhook.EventWebhookDeleted)
+	shared.RegisterEvent[webhook.WebhookEnabled](r, webhook.EventWebhookEnabled)
+	shared.RegisterEvent[webhook.WebhookDisabled](r, webhook.EventWebhookDisabled)
+
+	shared.RegisterEvent[secret.SecretCreated](r, secret.EventSecretCreated)
+	shared.RegisterEvent[secret.SecretUpdated](r, secret.EventSecretUpdated)
+	shared.RegisterEvent[secret.SecretDeleted](r, secret.EventSecretDeleted)
+
+	shared.RegisterEvent[apikey.APIKeyCreated](r, apikey.EventAPIKeyCreated)
+	shared.RegisterEvent[apikey.APIKeyRevoked](r, apikey.EventAPIKeyRevoked)
+
+	shared.RegisterEvent[access.RolesAssigned](r, access.EventRolesAssigned)
+	shared.RegisterEvent[access.RolesUnassigned](r, access.EventRolesUnassigned)
+
+	shared.RegisterEvent[enrollment.TokenCreated](r, enrollment.EventTokenCreated)
+	shared.RegisterEvent[enrollment.TokenRedeemed](r, enrollment.EventTokenRedeemed)
+	shared.RegisterEvent[enrollment.TokenRevoked](r, enrollment.EventTokenRevoked)
+
+	shared.RegisterEvent[deadletter.DeadLetterReplayed](r, deadletter.EventDeadLetterReplayed)
+	shared.RegisterEvent[deadletter.DeadLetterDiscarded](r, deadletter.EventDeadLetterDiscarded)
+
+	// Run and scenario events used PascalCase names before every type became dotted;
+	// outbox rows written back then still carry them
+	r.Alias("RunCreated", run.EventRunCreated)
+	r.Alias("RunStarted", run.EventRunStarted)
+	r.Alias("RunCompleted", run.EventRunCompleted)
+	r.Alias("RunFailed", run.EventRunFailed)
+	r.Alias("RunCancelled", run.EventRunCancelled)
+	r.Alias("RunProgress", run.EventRunProgress)
+	r.Alias("ScenarioCreated", scenario.EventScenarioCreated)
+	r.Alias("ScenarioUpdated", scenario.EventScenarioUpdated)
+	r.Alias("ScenarioDeleted", scenario.EventScenarioDeleted)
+	r.Alias("ScenarioRestored", scenario.EventScenarioRestored)
+	r.Alias("ScenarioContextUpdated", scenario.EventScenarioContextUpdated)
+	r.Alias("ScenarioParametersUpdated", scenario.EventScenarioParametersUpdated)
+
+	return r
+}
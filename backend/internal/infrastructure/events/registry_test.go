@@ -0,0 +1,96 @@
+package events
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/shared"
+)
+
+func TestRegistry_RoundTrip(t *testing.T) {
+	registry := NewDefaultRegistry()
+	event := run.RunFailed{
+		BaseEvent: shared.NewBaseEvent(run.EventRunFailed, "7"),
+		RunID:     "7",
+		Reason:    "boom",
+	}
+
+	envelope, err := registry.Encode(event)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if envelope.Type != "run.failed" || envelope.AggregateType != "run" || envelope.Version != 1 {
+		t.Errorf("Envelope = %s/%s v%d, want run.failed/run v1", envelope.Type, envelope.AggregateType, envelope.Version)
+	}
+
+	// The envelope survives a trip through JSON
+	data, _ := json.Marshal(envelope)
+	var received shared.Envelope
+	if err := json.Unmarshal(data, &received); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	decoded, err := registry.Decode(received)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	failed, ok := decoded.(run.RunFailed)
+	if !ok {
+		t.Fatalf("Decode() = %T, want run.RunFailed", decoded)
+	}
+	if failed.Reason != "boom" || failed.EventID() != event.EventID() || !failed.OccurredAt().Equal(event.OccurredAt()) {
+		t.Errorf("Decode() = %+v, want %+v", failed, event)
+	}
+}
+
+func TestRegistry_DecodesLegacyTypeNames(t *testing.T) {
+	registry := NewDefaultRegistry()
+	envelope := shared.Envelope{
+		ID:          "legacy",
+		Type:        "RunCompleted",
+		AggregateID: "3",
+		OccurredAt:  time.Now(),
+		Payload:     json.RawMessage(`{"RunID":"3"}`),
+	}
+
+	decoded, err := registry.Decode(envelope)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if _, ok := decoded.(run.RunCompleted); !ok || decoded.EventType() != run.EventRunCompleted {
+		t.Errorf("Decode() = %T %q, want run.RunCompleted %q", decoded, decoded.EventType(), run.EventRunCompleted)
+	}
+}
+
+func TestRegistry_UnknownAndNewerEvents(t *testing.T) {
+	registry := NewDefaultRegistry()
+	payload := json.RawMessage(`{"foo":"bar"}`)
+
+	decoded, err := registry.Decode(shared.Envelope{ID: "1", Type: "widget.created", Version: 3, Payload: payload})
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	raw, ok := decoded.(shared.RawEvent)
+	if !ok {
+		t.Fatalf("Decode() = %T, want shared.RawEvent", decoded)
+	}
+	if data, _ := json.Marshal(raw); !reflect.DeepEqual(json.RawMessage(data), payload) {
+		t.Errorf("RawEvent encodes as %s, want %s", data, payload)
+	}
+
+	if _, err := registry.Decode(shared.Envelope{ID: "2", Type: run.EventRunFailed, Version: 2, Payload: payload}); err == nil {
+		t.Error("Decode() accepted a payload version newer than the registered one")
+	}
+}
+
+func TestRegistry_KnowsEveryHandledEvent(t *testing.T) {
+	registry := NewDefaultRegistry()
+	for _, eventType := range []string{run.EventRunProgress, "scenario.context.updated", "webhook.disabled", "agent.registered"} {
+		if !registry.IsRegistered(eventType) {
+			t.Errorf("IsRegistered(%q) = false", eventType)
+		}
+	}
+}
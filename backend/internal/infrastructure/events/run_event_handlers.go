@@ -1,7 +1,8 @@
 package events
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"parrotflow/internal/domain/run"
 	"parrotflow/internal/domain/shared"
 )
@@ -15,9 +16,9 @@ func NewRunCreatedHandler() *RunCreatedHandler {
 }
 
 // Handle handles the run created event
-func (h *RunCreatedHandler) Handle(event shared.DomainEvent) error {
+func (h *RunCreatedHandler) Handle(ctx context.Context, event shared.DomainEvent) error {
 	if runCreated, ok := event.(run.RunCreated); ok {
-		log.Printf("Run created: %s for scenario: %s", runCreated.RunID, runCreated.ScenarioID)
+		slog.InfoContext(ctx, "Run created", "run_id", runCreated.RunID, "scenario_id", runCreated.ScenarioID)
 		// Here you could add additional logic like:
 		// - Send notifications
 		// - Update metrics
@@ -28,7 +29,7 @@ func (h *RunCreatedHandler) Handle(event shared.DomainEvent) error {
 
 // CanHandle checks if this handler can handle the event type
 func (h *RunCreatedHandler) CanHandle(eventType string) bool {
-	return eventType == "RunCreated"
+	return eventType == run.EventRunCreated
 }
 
 // RunStartedHandler handles run started events
@@ -40,9 +41,9 @@ func NewRunStartedHandler() *RunStartedHandler {
 }
 
 // Handle handles the run started event
-func (h *RunStartedHandler) Handle(event shared.DomainEvent) error {
+func (h *RunStartedHandler) Handle(ctx context.Context, event shared.DomainEvent) error {
 	if runStarted, ok := event.(run.RunStarted); ok {
-		log.Printf("Run started: %s for scenario: %s at %v", runStarted.RunID, runStarted.ScenarioID, runStarted.StartedAt)
+		slog.InfoContext(ctx, "Run started", "run_id", runStarted.RunID, "scenario_id", runStarted.ScenarioID, "started_at", runStarted.StartedAt)
 		// Here you could add additional logic like:
 		// - Send notifications
 		// - Update metrics
@@ -53,7 +54,7 @@ func (h *RunStartedHandler) Handle(event shared.DomainEvent) error {
 
 // CanHandle checks if this handler can handle the event type
 func (h *RunStartedHandler) CanHandle(eventType string) bool {
-	return eventType == "RunStarted"
+	return eventType == run.EventRunStarted
 }
 
 // RunCompletedHandler handles run completed events
@@ -65,9 +66,9 @@ func NewRunCompletedHandler() *RunCompletedHandler {
 }
 
 // Handle handles the run completed event
-func (h *RunCompletedHandler) Handle(event shared.DomainEvent) error {
+func (h *RunCompletedHandler) Handle(ctx context.Context, event shared.DomainEvent) error {
 	if runCompleted, ok := event.(run.RunCompleted); ok {
-		log.Printf("Run completed: %s for scenario: %s at %v", runCompleted.RunID, runCompleted.ScenarioID, runCompleted.FinishedAt)
+		slog.InfoContext(ctx, "Run completed", "run_id", runCompleted.RunID, "scenario_id", runCompleted.ScenarioID, "finished_at", runCompleted.FinishedAt)
 		// Here you could add additional logic like:
 		// - Send notifications
 		// - Update metrics
@@ -78,7 +79,7 @@ func (h *RunCompletedHandler) Handle(event shared.DomainEvent) error {
 
 // CanHandle checks if this handler can handle the event type
 func (h *RunCompletedHandler) CanHandle(eventType string) bool {
-	return eventType == "RunCompleted"
+	return eventType == run.EventRunCompleted
 }
 
 // RunFailedHandler handles run failed events
@@ -90,9 +91,9 @@ func NewRunFailedHandler() *RunFailedHandler {
 }
 
 // Handle handles the run failed event
-func (h *RunFailedHandler) Handle(event shared.DomainEvent) error {
+func (h *RunFailedHandler) Handle(ctx context.Context, event shared.DomainEvent) error {
 	if runFailed, ok := event.(run.RunFailed); ok {
-		log.Printf("Run failed: %s for scenario: %s at %v with reason: %s", runFailed.RunID, runFailed.ScenarioID, runFailed.FailedAt, runFailed.Reason)
+		slog.WarnContext(ctx, "Run failed", "run_id", runFailed.RunID, "scenario_id", runFailed.ScenarioID, "failed_at", runFailed.FailedAt, "reason", runFailed.Reason)
 		// Here you could add additional logic like:
 		// - Send notifications
 		// - Update metrics
@@ -104,5 +105,5 @@ func (h *RunFailedHandler) Handle(event shared.DomainEvent) error {
 
 // CanHandle checks if this handler can handle the event type
 func (h *RunFailedHandler) CanHandle(eventType string) bool {
-	return eventType == "RunFailed"
+	return eventType == run.EventRunFailed
 }
@@ -1,7 +1,8 @@
 package events
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"parrotflow/internal/domain/scenario"
 	"parrotflow/internal/domain/shared"
 )
@@ -15,9 +16,9 @@ func NewScenarioCreatedHandler() *ScenarioCreatedHandler {
 }
 
 // Handle handles the scenario created event
-func (h *ScenarioCreatedHandler) Handle(event shared.DomainEvent) error {
+func (h *ScenarioCreatedHandler) Handle(ctx context.Context, event shared.DomainEvent) error {
 	if scenarioCreated, ok := event.(scenario.ScenarioCreated); ok {
-		log.Printf("Scenario created: %s with name: %s", scenarioCreated.ScenarioID, scenarioCreated.Name)
+		slog.InfoContext(ctx, "Scenario created", "scenario_id", scenarioCreated.ScenarioID, "name", scenarioCreated.Name)
 		// Here you could add additional logic like:
 		// - Send notifications
 		// - Update search indexes
@@ -28,7 +29,7 @@ func (h *ScenarioCreatedHandler) Handle(event shared.DomainEvent) error {
 
 // CanHandle checks if this handler can handle the event type
 func (h *ScenarioCreatedHandler) CanHandle(eventType string) bool {
-	return eventType == "ScenarioCreated"
+	return eventType == scenario.EventScenarioCreated
 }
 
 // ScenarioUpdatedHandler handles scenario updated events
@@ -40,9 +41,9 @@ func NewScenarioUpdatedHandler() *ScenarioUpdatedHandler {
 }
 
 // Handle handles the scenario updated event
-func (h *ScenarioUpdatedHandler) Handle(event shared.DomainEvent) error {
+func (h *ScenarioUpdatedHandler) Handle(ctx context.Context, event shared.DomainEvent) error {
 	if scenarioUpdated, ok := event.(scenario.ScenarioUpdated); ok {
-		log.Printf("Scenario updated: %s with changes: %v", scenarioUpdated.ScenarioID, scenarioUpdated.Changes)
+		slog.InfoContext(ctx, "Scenario updated", "scenario_id", scenarioUpdated.ScenarioID, "changes", scenarioUpdated.Changes)
 		// Here you could add additional logic like:
 		// - Update search indexes
 		// - Send notifications
@@ -53,7 +54,7 @@ func (h *ScenarioUpdatedHandler) Handle(event shared.DomainEvent) error {
 
 // CanHandle checks if this handler can handle the event type
 func (h *ScenarioUpdatedHandler) CanHandle(eventType string) bool {
-	return eventType == "ScenarioUpdated"
+	return eventType == scenario.EventScenarioUpdated
 }
 
 // ScenarioDeletedHandler handles scenario deleted events
@@ -65,9 +66,9 @@ func NewScenarioDeletedHandler() *ScenarioDeletedHandler {
 }
 
 // Handle handles the scenario deleted event
-func (h *ScenarioDeletedHandler) Handle(event shared.DomainEvent) error {
+func (h *ScenarioDeletedHandler) Handle(ctx context.Context, event shared.DomainEvent) error {
 	if scenarioDeleted, ok := event.(scenario.ScenarioDeleted); ok {
-		log.Printf("Scenario deleted: %s", scenarioDeleted.ScenarioID)
+		slog.InfoContext(ctx, "Scenario deleted", "scenario_id", scenarioDeleted.ScenarioID)
 		// Here you could add additional logic like:
 		// - Clean up related data
 		// - Update search indexes
@@ -78,5 +79,5 @@ func (h *ScenarioDeletedHandler) Handle(event shared.DomainEvent) error {
 
 // CanHandle checks if this handler can handle the event type
 func (h *ScenarioDeletedHandler) CanHandle(eventType string) bool {
-	return eventType == "ScenarioDeleted"
+	return eventType == scenario.EventScenarioDeleted
 }
@@ -0,0 +1,365 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/logging"
+	"parrotflow/internal/infrastructure/tracing"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrBusShutDown is returned when publishing to a bus that is shutting down
+var ErrBusShutDown = errors.New("event bus is shut down")
+
+// ErrHandlerTimeout is the failure recorded when a handler exceeds its timeout
+var ErrHandlerTimeout = errors.New("event handler timed out")
+
+// FailurePolicy decides what happens to an event a handler failed on,
+// by returning an error, panicking or timing out
+type FailurePolicy string
+
+const (
+	// FailureRetry retries the handler with backoff, then dead-letters the event
+	FailureRetry FailurePolicy = "retry"
+	// FailureDeadLetter hands the event to the dead-letter sink straight away
+	FailureDeadLetter FailurePolicy = "dead-letter"
+	// FailureDrop logs the failure and moves on
+	FailureDrop FailurePolicy = "drop"
+)
+
+// HandlerPolicy controls how one subscribed handler is run
+// Retries back off on the worker, holding up every aggregate sharing it until the
+// handler succeeds or gives up; keep MaxRetries and RetryBackoff small, and dead-letter
+// events of handlers that need longer to recover
+type HandlerPolicy struct {
+	OnFailure    FailurePolicy
+	MaxRetries   int           // Retries after the first attempt, for FailureRetry
+	RetryBackoff time.Duration // Doubled after every retry
+	Timeout      time.Duration // Overrides the bus timeout when set
+}
+
+// DeadLetterSink keeps events that a handler gave up on
+type DeadLetterSink interface {
+	DeadLetter(ctx context.Context, event shared.DomainEvent, handler string, cause error) error
+}
+
+// WorkerPoolConfig sizes the pool and sets the defaults of subscribed handlers
+type WorkerPoolConfig struct {
+	Workers        int
+	QueueSize      int // Per worker; Publish blocks while the queue of a worker is full
+	HandlerTimeout time.Duration
+	DefaultPolicy  HandlerPolicy
+
+	// Timed-out handlers left running in the background; once that many are, a worker
+	// waits for the handler it gave up on before moving on. 0 always waits
+	MaxAbandoned int
+}
+
+func DefaultWorkerPoolConfig() WorkerPoolConfig {
+	return WorkerPoolConfig{
+		Workers:        8,
+		QueueSize:      256,
+		HandlerTimeout: 30 * time.Second,
+		DefaultPolicy: HandlerPolicy{
+			OnFailure:    FailureRetry,
+			MaxRetries:   3,
+			RetryBackoff: time.Second,
+		},
+		MaxAbandoned: 64,
+	}
+}
+
+type subscription struct {
+	handler shared.EventHandler
+	name    string
+	policy  HandlerPolicy
+}
+
+type job struct {
+	event         shared.DomainEvent
+	subscriptions []subscription
+	traceParent   string // Trace the event was published in, handlers continue it
+}
+
+// WorkerPoolEventBus runs handlers on a fixed number of workers with bounded queues
+// Events of the same aggregate always go to the same worker, so handlers see them
+// in publish order; a slow or retrying handler only holds up the aggregates sharing
+// its worker
+type WorkerPoolEventBus struct {
+	config      WorkerPoolConfig
+	deadLetters DeadLetterSink
+
+	subscriptions []subscription
+	subMu         sync.RWMutex
+
+	queues  []chan job
+	workers sync.WaitGroup
+
+	// Holds a slot for every timed-out handler that has not returned yet
+	abandoned chan struct{}
+
+	// Shutdown stops intake, waits for publishers blocked on a full queue, then drains
+	closed     bool
+	closeMu    sync.RWMutex
+	closing    chan struct{}
+	publishing sync.WaitGroup
+
+	// Cancelled when the drain deadline passes, to cut retries short
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewWorkerPoolEventBus starts the workers; deadLetters may be nil, dead-lettered
+// events are then only logged
+func NewWorkerPoolEventBus(config WorkerPoolConfig, deadLetters DeadLetterSink) *WorkerPoolEventBus {
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	bus := &WorkerPoolEventBus{
+		config:      config,
+		deadLetters: deadLetters,
+		queues:      make([]chan job, config.Workers),
+		abandoned:   make(chan struct{}, max(config.MaxAbandoned, 0)),
+		closing:     make(chan struct{}),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	for i := range bus.queues {
+		bus.queues[i] = make(chan job, config.QueueSize)
+		bus.workers.Add(1)
+		go bus.work(bus.queues[i])
+	}
+	return bus
+}
+
+// Publish queues the event for the handlers that can handle it
+// It blocks while the worker of the event's aggregate is backed up
+func (bus *WorkerPoolEventBus) Publish(event shared.DomainEvent) error {
+	return bus.PublishContext(context.Background(), event)
+}
+
+// PublishContext is Publish with handlers continuing the trace of ctx
+// Only the trace is kept, handlers run long after ctx may be cancelled
+func (bus *WorkerPoolEventBus) PublishContext(ctx context.Context, event shared.DomainEvent) error {
+	bus.closeMu.RLock()
+	if bus.closed {
+		bus.closeMu.RUnlock()
+		return ErrBusShutDown
+	}
+	bus.publishing.Add(1)
+	bus.closeMu.RUnlock()
+	defer bus.publishing.Done()
+
+	subscriptions := bus.subscriptionsFor(event.EventType())
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	select {
+	case bus.queues[bus.shard(event)] <- job{event: event, subscriptions: subscriptions, traceParent: tracing.TraceParent(ctx)}:
+		return nil
+	case <-bus.closing:
+		return ErrBusShutDown
+	}
+}
+
+// Subscribe adds a handler with the default policy
+func (bus *WorkerPoolEventBus) Subscribe(handler shared.EventHandler) error {
+	return bus.SubscribeWithPolicy(handler, bus.config.DefaultPolicy)
+}
+
+// SubscribeWithPolicy adds a handler with its own failure policy and timeout
+func (bus *WorkerPoolEventBus) SubscribeWithPolicy(handler shared.EventHandler, policy HandlerPolicy) error {
+	if policy.Timeout <= 0 {
+		policy.Timeout = bus.config.HandlerTimeout
+	}
+
+	bus.subMu.Lock()
+	defer bus.subMu.Unlock()
+	bus.subscriptions = append(bus.subscriptions, subscription{
+		handler: handler,
+		name:    fmt.Sprintf("%T", handler),
+		policy:  policy,
+	})
+	return nil
+}
+
+// Backlog returns how many events wait in the worker queues and how many they can hold
+func (bus *WorkerPoolEventBus) Backlog() (queued, capacity int) {
+	for _, queue := range bus.queues {
+		queued += len(queue)
+		capacity += cap(queue)
+	}
+	return queued, capacity
+}
+
+// Abandoned returns how many timed-out handlers are still running in the background
+func (bus *WorkerPoolEventBus) Abandoned() int {
+	return len(bus.abandoned)
+}
+
+// Shutdown stops accepting events and waits until queued ones are handled
+// When ctx expires first, pending retries are abandoned and ctx.Err() is returned
+func (bus *WorkerPoolEventBus) Shutdown(ctx context.Context) error {
+	bus.closeMu.Lock()
+	if !bus.closed {
+		bus.closed = true
+		close(bus.closing)
+		bus.closeMu.Unlock()
+
+		bus.publishing.Wait()
+		for _, queue := range bus.queues {
+			close(queue)
+		}
+	} else {
+		bus.closeMu.Unlock()
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		bus.workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		bus.cancel()
+		return ctx.Err()
+	}
+}
+
+func (bus *WorkerPoolEventBus) subscriptionsFor(eventType string) []subscription {
+	bus.subMu.RLock()
+	defer bus.subMu.RUnlock()
+
+	var matching []subscription
+	for _, s := range bus.subscriptions {
+		if s.handler.CanHandle(eventType) {
+			matching = append(matching, s)
+		}
+	}
+	return matching
+}
+
+// shard picks the worker of the event's aggregate
+func (bus *WorkerPoolEventBus) shard(event shared.DomainEvent) int {
+	h := fnv.New32a()
+	h.Write([]byte(shared.AggregateTypeOf(event.EventType())))
+	h.Write([]byte{0})
+	h.Write([]byte(event.AggregateID()))
+	return int(h.Sum32() % uint32(len(bus.queues)))
+}
+
+func (bus *WorkerPoolEventBus) work(queue <-chan job) {
+	defer bus.workers.Done()
+	for j := range queue {
+		ctx := shared.CausedBy(tracing.ContextWithTraceParent(bus.ctx, j.traceParent), j.event)
+		for _, s := range j.subscriptions {
+			bus.dispatch(ctx, s, j.event)
+		}
+	}
+}
+
+// dispatch runs one handler on one event and applies its policy on failure
+// A traced event gets a span per handler, covering its retries
+func (bus *WorkerPoolEventBus) dispatch(ctx context.Context, s subscription, event shared.DomainEvent) {
+	var err error
+	if tracing.IsTraced(ctx) {
+		var span trace.Span
+		ctx, span = tracing.Tracer().Start(ctx, "handle "+event.EventType(), trace.WithAttributes(attribute.String("handler", s.name)))
+		defer span.End()
+		defer func() { tracing.RecordError(span, err) }()
+	}
+
+	if err = bus.invoke(ctx, s, event); err == nil {
+		return
+	}
+
+	switch s.policy.OnFailure {
+	case FailureDrop:
+		slog.WarnContext(ctx, "Dropping event after handler failed", logging.Event(event), "handler", s.name, "error", err)
+		return
+	case FailureRetry:
+		backoff := s.policy.RetryBackoff
+		for retry := 1; retry <= s.policy.MaxRetries; retry++ {
+			select {
+			case <-time.After(backoff):
+			case <-bus.ctx.Done():
+				slog.WarnContext(ctx, "Abandoning event on shutdown", logging.Event(event), "handler", s.name, "error", err)
+				return
+			}
+			if err = bus.invoke(ctx, s, event); err == nil {
+				return
+			}
+			backoff *= 2
+		}
+	}
+
+	bus.deadLetter(ctx, s, event, err)
+}
+
+// invoke runs the handler with panic recovery and a timeout
+// A handler that times out has its context cancelled and is left running in the
+// background, its result ignored, as long as fewer than MaxAbandoned handlers are;
+// otherwise the worker waits for it and returns its result
+func (bus *WorkerPoolEventBus) invoke(ctx context.Context, s subscription, event shared.DomainEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, s.policy.Timeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				result <- fmt.Errorf("handler panicked: %v", r)
+			}
+		}()
+		result <- s.handler.Handle(ctx, event)
+	}()
+
+	timer := time.NewTimer(s.policy.Timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+	}
+
+	select {
+	case bus.abandoned <- struct{}{}:
+		slog.WarnContext(ctx, "Abandoning timed-out event handler", logging.Event(event), "handler", s.name, "abandoned", len(bus.abandoned))
+		go func() {
+			<-result
+			<-bus.abandoned
+		}()
+		return fmt.Errorf("%w after %s", ErrHandlerTimeout, s.policy.Timeout)
+	default:
+		slog.WarnContext(ctx, "Waiting for timed-out event handler, too many are abandoned", logging.Event(event), "handler", s.name, "abandoned", len(bus.abandoned))
+		return <-result
+	}
+}
+
+// deadLetter hands the event to the sink; ctx only carries the event's correlation,
+// the event is stored even when the bus is shutting down
+func (bus *WorkerPoolEventBus) deadLetter(ctx context.Context, s subscription, event shared.DomainEvent, cause error) {
+	ctx = context.WithoutCancel(ctx)
+	slog.WarnContext(ctx, "Dead-lettering event", logging.Event(event), "handler", s.name, "error", cause)
+	if bus.deadLetters == nil {
+		return
+	}
+	if err := bus.deadLetters.DeadLetter(ctx, event, s.name, cause); err != nil {
+		slog.ErrorContext(ctx, "Error dead-lettering event", logging.Event(event), "error", err)
+	}
+}
@@ -0,0 +1,280 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/shared"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordingHandler records the run progress it sees and fails as told
+type recordingHandler struct {
+	mu     sync.Mutex
+	seen   map[string][]string
+	calls  int
+	handle func(call int) error
+}
+
+func (h *recordingHandler) CanHandle(eventType string) bool {
+	return eventType == run.EventRunProgress
+}
+
+func (h *recordingHandler) Handle(ctx context.Context, event shared.DomainEvent) error {
+	progress := event.(run.RunProgress)
+	h.mu.Lock()
+	h.calls++
+	if h.seen == nil {
+		h.seen = make(map[string][]string)
+	}
+	h.seen[progress.RunID] = append(h.seen[progress.RunID], progress.NodeID)
+	call := h.calls
+	h.mu.Unlock()
+
+	if h.handle != nil {
+		return h.handle(call)
+	}
+	return nil
+}
+
+func (h *recordingHandler) callCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.calls
+}
+
+// recordingDeadLetters keeps dead-lettered events in memory
+type recordingDeadLetters struct {
+	mu     sync.Mutex
+	causes []error
+}
+
+func (d *recordingDeadLetters) DeadLetter(ctx context.Context, event shared.DomainEvent, handler string, cause error) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.causes = append(d.causes, cause)
+	return nil
+}
+
+// newProgress reports progress on node n, so handlers can tell the order events arrive in
+func newProgress(runID string, n int) run.RunProgress {
+	return run.RunProgress{
+		BaseEvent: shared.NewBaseEvent(run.EventRunProgress, runID),
+		RunID:     runID,
+		NodeID:    strconv.Itoa(n),
+	}
+}
+
+func testConfig() WorkerPoolConfig {
+	config := DefaultWorkerPoolConfig()
+	config.Workers = 4
+	config.QueueSize = 2
+	config.HandlerTimeout = time.Second
+	config.DefaultPolicy.RetryBackoff = time.Millisecond
+	return config
+}
+
+func TestWorkerPoolEventBus_KeepsAggregateOrder(t *testing.T) {
+	bus := NewWorkerPoolEventBus(testConfig(), nil)
+	handler := &recordingHandler{}
+	bus.Subscribe(handler)
+
+	for i := 0; i < 50; i++ {
+		for _, runID := range []string{"1", "2", "3"} {
+			if err := bus.Publish(newProgress(runID, i)); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+		}
+	}
+	if err := bus.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	for runID, seen := range handler.seen {
+		if len(seen) != 50 {
+			t.Fatalf("Run %s: handled %d events, want 50", runID, len(seen))
+		}
+		for i, nodeID := range seen {
+			if nodeID != strconv.Itoa(i) {
+				t.Fatalf("Run %s: event for node %s handled at position %d", runID, nodeID, i)
+			}
+		}
+	}
+	if err := bus.Publish(newProgress("1", 50)); !errors.Is(err, ErrBusShutDown) {
+		t.Errorf("Publish() after Shutdown error = %v, want ErrBusShutDown", err)
+	}
+}
+
+// contextHandler hands the context it handled an event in to a channel
+type contextHandler chan context.Context
+
+func (h contextHandler) CanHandle(eventType string) bool { return true }
+
+func (h contextHandler) Handle(ctx context.Context, event shared.DomainEvent) error {
+	h <- ctx
+	return nil
+}
+
+func TestWorkerPoolEventBus_HandlersContinueTrace(t *testing.T) {
+	bus := NewWorkerPoolEventBus(testConfig(), nil)
+	handler := make(contextHandler, 1)
+	bus.Subscribe(handler)
+
+	traceID := trace.TraceID{1, 2, 3}
+	published := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	}))
+	event := newProgress("1", 1)
+	if err := bus.PublishContext(published, event); err != nil {
+		t.Fatalf("PublishContext() error = %v", err)
+	}
+	bus.Shutdown(context.Background())
+
+	ctx := <-handler
+	if got := trace.SpanContextFromContext(ctx).TraceID(); got != traceID {
+		t.Errorf("Handler trace = %s, want %s", got, traceID)
+	}
+	if got := shared.CorrelationFromContext(ctx).CausationID; got != event.EventID() {
+		t.Errorf("Handler causation = %q, want the event %q", got, event.EventID())
+	}
+}
+
+func TestWorkerPoolEventBus_FailurePolicies(t *testing.T) {
+	deadLetters := &recordingDeadLetters{}
+	bus := NewWorkerPoolEventBus(testConfig(), deadLetters)
+
+	// Fails twice, then succeeds within its retries
+	flaky := &recordingHandler{handle: func(call int) error {
+		if call < 3 {
+			return errors.New("flaky")
+		}
+		return nil
+	}}
+	bus.Subscribe(flaky)
+
+	// Panics every time and is dead-lettered after its retries
+	panicking := &recordingHandler{handle: func(int) error { panic("boom") }}
+	bus.SubscribeWithPolicy(panicking, HandlerPolicy{OnFailure: FailureRetry, MaxRetries: 2, RetryBackoff: time.Millisecond})
+
+	// Hangs and is dropped without retries
+	hanging := &recordingHandler{handle: func(int) error { time.Sleep(time.Second); return nil }}
+	bus.SubscribeWithPolicy(hanging, HandlerPolicy{OnFailure: FailureDrop, Timeout: 10 * time.Millisecond})
+
+	bus.Publish(newProgress("1", 1))
+	if err := bus.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	if flaky.callCount() != 3 {
+		t.Errorf("Flaky handler called %d times, want 3", flaky.callCount())
+	}
+	if panicking.callCount() != 3 {
+		t.Errorf("Panicking handler called %d times, want 3", panicking.callCount())
+	}
+	if hanging.callCount() != 1 {
+		t.Errorf("Hanging handler called %d times, want 1", hanging.callCount())
+	}
+	if len(deadLetters.causes) != 1 {
+		t.Fatalf("Dead-lettered %d events, want 1", len(deadLetters.causes))
+	}
+	if cause := deadLetters.causes[0].Error(); cause != "handler panicked: boom" {
+		t.Errorf("Dead-letter cause = %q", cause)
+	}
+}
+
+func TestWorkerPoolEventBus_ShutdownDrainsQueue(t *testing.T) {
+	config := testConfig()
+	config.Workers = 1
+	bus := NewWorkerPoolEventBus(config, nil)
+
+	started := make(chan struct{}, 3)
+	release := make(chan struct{})
+	handler := &recordingHandler{handle: func(int) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+	bus.Subscribe(handler)
+	for i := 0; i < 3; i++ {
+		bus.Publish(newProgress("1", i))
+	}
+	<-started
+
+	// Shutdown waits for the event in flight and the two queued behind it
+	done := make(chan error, 1)
+	go func() { done <- bus.Shutdown(context.Background()) }()
+	select {
+	case err := <-done:
+		t.Fatalf("Shutdown() = %v while an event was in flight", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if got := handler.seen["1"]; len(got) != 3 {
+		t.Errorf("Handled %d events before Shutdown returned, want 3", len(got))
+	}
+}
+
+func TestWorkerPoolEventBus_ShutdownDeadline(t *testing.T) {
+	config := testConfig()
+	config.Workers = 1
+	bus := NewWorkerPoolEventBus(config, nil)
+
+	release := make(chan struct{})
+	defer close(release)
+	bus.Subscribe(&recordingHandler{handle: func(int) error { <-release; return nil }})
+	for i := 0; i < 2; i++ {
+		bus.Publish(newProgress(strconv.Itoa(i), i))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := bus.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() error = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestWorkerPoolEventBus_BoundsAbandonedHandlers(t *testing.T) {
+	config := testConfig()
+	config.Workers = 1
+	config.MaxAbandoned = 1
+	bus := NewWorkerPoolEventBus(config, nil)
+
+	release := make(chan struct{})
+	handler := &recordingHandler{handle: func(int) error { <-release; return nil }}
+	bus.SubscribeWithPolicy(handler, HandlerPolicy{OnFailure: FailureDrop, Timeout: 10 * time.Millisecond})
+	bus.Publish(newProgress("1", 1))
+	bus.Publish(newProgress("1", 2))
+
+	// The first call is abandoned, the worker waits for the second
+	deadline := time.Now().Add(time.Second)
+	for handler.callCount() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if handler.callCount() != 2 || bus.Abandoned() != 1 {
+		t.Fatalf("Calls = %d with %d abandoned, want 2 calls and 1 abandoned", handler.callCount(), bus.Abandoned())
+	}
+
+	close(release)
+	if err := bus.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	for bus.Abandoned() != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if bus.Abandoned() != 0 {
+		t.Errorf("Abandoned() = %d after the handlers returned, want 0", bus.Abandoned())
+	}
+}
@@ -0,0 +1,190 @@
+// Package health runs the probes that tell whether the backend can do its work
+// Liveness only says the process is running; readiness probes the database, the
+// broker and the background machinery so traffic is only routed to working instances
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Config sets the probe timeout and when the optional components count as degraded
+type Config struct {
+	Timeout          time.Duration // Per probe
+	HeartbeatTimeout time.Duration // After which a connected agent counts as stale
+	MaxStaleAgents   float64       // Share of connected agents that may be stale
+	MaxEventBacklog  float64       // Share of the event queues that may be filled
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Timeout:          2 * time.Second,
+		HeartbeatTimeout: 5 * time.Minute,
+		MaxStaleAgents:   0.5,
+		MaxEventBacklog:  0.8,
+	}
+}
+
+// Status of a component or of the whole instance
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDegraded Status = "degraded" // Working, but something needs attention
+	StatusDown     Status = "down"
+)
+
+// severity orders statuses from best to worst
+func (s Status) severity() int {
+	switch s {
+	case StatusUp:
+		return 0
+	case StatusDegraded:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Result is what a probe found out about its component
+type Result struct {
+	Status Status
+	Detail string
+}
+
+// Up reports a working component
+func Up(detail string) Result {
+	return Result{Status: StatusUp, Detail: detail}
+}
+
+// Degraded reports a component that works but needs attention
+func Degraded(detail string) Result {
+	return Result{Status: StatusDegraded, Detail: detail}
+}
+
+// Down reports a component that does not work
+func Down(err error) Result {
+	return Result{Status: StatusDown, Detail: err.Error()}
+}
+
+// Probe checks one component; it must return once ctx is done
+type Probe func(ctx context.Context) Result
+
+// ComponentReport is the result of one probe
+type ComponentReport struct {
+	Name     string
+	Status   Status
+	Detail   string
+	Critical bool
+	Duration time.Duration
+}
+
+// Report is the outcome of running the registered probes
+type Report struct {
+	Status     Status
+	Ready      bool // False when a critical component is down
+	Components []ComponentReport
+	CheckedAt  time.Time
+}
+
+type registration struct {
+	name     string
+	probe    Probe
+	critical bool
+}
+
+// Registry holds the probes of the instance's components
+type Registry struct {
+	timeout time.Duration
+	started time.Time
+
+	mu     sync.RWMutex
+	probes []registration
+}
+
+// NewRegistry creates a registry giving every probe at most timeout to answer
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout, started: time.Now()}
+}
+
+// Register adds a probe of a component the instance cannot work without
+// The instance is not ready while the component is down
+func (r *Registry) Register(name string, probe Probe) {
+	r.register(registration{name: name, probe: probe, critical: true})
+}
+
+// RegisterOptional adds a probe of a component whose failure only degrades the instance
+func (r *Registry) RegisterOptional(name string, probe Probe) {
+	r.register(registration{name: name, probe: probe})
+}
+
+func (r *Registry) register(probe registration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.probes = append(r.probes, probe)
+}
+
+// Uptime is how long the process has been running
+func (r *Registry) Uptime() time.Duration {
+	return time.Since(r.started)
+}
+
+// Check runs every probe concurrently and reports on the instance
+// A probe that does not answer within the timeout is reported as down
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.RLock()
+	probes := append([]registration(nil), r.probes...)
+	r.mu.RUnlock()
+
+	components := make([]ComponentReport, len(probes))
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			components[i] = r.run(ctx, probe)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Ready: true, Components: components, CheckedAt: time.Now()}
+	for _, component := range components {
+		status := component.Status
+		if status == StatusDown && !component.Critical {
+			status = StatusDegraded
+		}
+		if status.severity() > report.Status.severity() {
+			report.Status = status
+		}
+		if component.Status == StatusDown && component.Critical {
+			report.Ready = false
+		}
+	}
+	sort.Slice(report.Components, func(i, j int) bool { return report.Components[i].Name < report.Components[j].Name })
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, probe registration) ComponentReport {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	started := time.Now()
+	results := make(chan Result, 1)
+	go func() { results <- probe.probe(ctx) }()
+
+	var result Result
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		result = Down(ctx.Err())
+	}
+	return ComponentReport{
+		Name:     probe.name,
+		Status:   result.Status,
+		Detail:   result.Detail,
+		Critical: probe.critical,
+		Duration: time.Since(started),
+	}
+}
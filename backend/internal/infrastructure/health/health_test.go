@@ -0,0 +1,101 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"parrotflow/internal/infrastructure/health"
+)
+
+func probe(result health.Result) health.Probe {
+	return func(ctx context.Context) health.Result { return result }
+}
+
+func TestRegistry_Check(t *testing.T) {
+	down := health.Down(errors.New("connection refused"))
+	hanging := func(ctx context.Context) health.Result {
+		<-ctx.Done()
+		return health.Up("too late")
+	}
+
+	tests := []struct {
+		name       string
+		register   func(r *health.Registry)
+		wantStatus health.Status
+		wantReady  bool
+	}{
+		{
+			name: "all up",
+			register: func(r *health.Registry) {
+				r.Register("database", probe(health.Up("")))
+				r.RegisterOptional("agents", probe(health.Up("")))
+			},
+			wantStatus: health.StatusUp,
+			wantReady:  true,
+		},
+		{
+			name: "optional component down only degrades",
+			register: func(r *health.Registry) {
+				r.Register("database", probe(health.Up("")))
+				r.RegisterOptional("agents", probe(down))
+			},
+			wantStatus: health.StatusDegraded,
+			wantReady:  true,
+		},
+		{
+			name: "critical component down",
+			register: func(r *health.Registry) {
+				r.Register("database", probe(down))
+				r.RegisterOptional("agents", probe(health.Degraded("")))
+			},
+			wantStatus: health.StatusDown,
+			wantReady:  false,
+		},
+		{
+			name: "probe exceeding the timeout counts as down",
+			register: func(r *health.Registry) {
+				r.Register("broker", hanging)
+			},
+			wantStatus: health.StatusDown,
+			wantReady:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := health.NewRegistry(50 * time.Millisecond)
+			tt.register(registry)
+
+			report := registry.Check(context.Background())
+			if report.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", report.Status, tt.wantStatus)
+			}
+			if report.Ready != tt.wantReady {
+				t.Errorf("Ready = %v, want %v", report.Ready, tt.wantReady)
+			}
+		})
+	}
+}
+
+type backlog struct{ queued, capacity int }
+
+func (b backlog) Backlog() (int, int) { return b.queued, b.capacity }
+
+func TestEventBacklog(t *testing.T) {
+	tests := []struct {
+		backlog backlog
+		want    health.Status
+	}{
+		{backlog{queued: 10, capacity: 100}, health.StatusUp},
+		{backlog{queued: 80, capacity: 100}, health.StatusDegraded},
+		{backlog{queued: 100, capacity: 100}, health.StatusDown},
+	}
+	for _, tt := range tests {
+		got := health.EventBacklog(tt.backlog, 0.8)(context.Background())
+		if got.Status != tt.want {
+			t.Errorf("EventBacklog(%d of %d) = %q, want %q", tt.backlog.queued, tt.backlog.capacity, got.Status, tt.want)
+		}
+	}
+}
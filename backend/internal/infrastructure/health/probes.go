@@ -0,0 +1,111 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"parrotflow/internal/domain/agent"
+	"parrotflow/internal/models"
+	"parrotflow/internal/ports"
+
+	"gorm.io/gorm"
+)
+
+// DatabasePing checks that the database accepts connections and answers a ping
+func DatabasePing(db *gorm.DB) Probe {
+	return func(ctx context.Context) Result {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return Down(err)
+		}
+		if err := sqlDB.PingContext(ctx); err != nil {
+			return Down(err)
+		}
+		// A locked SQLite database still answers pings, a write lock shows on a query
+		if err := db.WithContext(ctx).Exec("SELECT 1").Error; err != nil {
+			return Down(err)
+		}
+		return Up(fmt.Sprintf("%d open connections", sqlDB.Stats().OpenConnections))
+	}
+}
+
+// SchemaVersion checks that the database was migrated to the schema of this build
+// A schema migrated by a newer build usually still works and only degrades the instance
+func SchemaVersion(db *gorm.DB) Probe {
+	return func(ctx context.Context) Result {
+		var version int
+		err := db.WithContext(ctx).Model(&models.SchemaMigration{}).
+			Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+		if err != nil {
+			return Down(err)
+		}
+		switch {
+		case version < models.SchemaVersion:
+			return Down(fmt.Errorf("schema is at version %d, this build needs %d", version, models.SchemaVersion))
+		case version > models.SchemaVersion:
+			return Degraded(fmt.Sprintf("schema is at version %d, migrated by a newer build than this one (%d)", version, models.SchemaVersion))
+		default:
+			return Up(fmt.Sprintf("version %d", version))
+		}
+	}
+}
+
+// Broker checks that the message broker can be reached
+func Broker(broker ports.MessageBroker) Probe {
+	return func(ctx context.Context) Result {
+		if err := broker.Ping(ctx); err != nil {
+			return Down(err)
+		}
+		return Up("")
+	}
+}
+
+// BacklogReporter is an event bus that reports how full its queues are
+type BacklogReporter interface {
+	Backlog() (queued, capacity int)
+}
+
+// EventBacklog degrades the instance when the in-process event queues fill past
+// threshold, a share between 0 and 1 of their capacity; publishing blocks once they are full
+func EventBacklog(bus BacklogReporter, threshold float64) Probe {
+	return func(ctx context.Context) Result {
+		queued, capacity := bus.Backlog()
+		detail := fmt.Sprintf("%d of %d queued", queued, capacity)
+		switch {
+		case capacity > 0 && queued >= capacity:
+			return Down(fmt.Errorf("queues are full, %s", detail))
+		case capacity > 0 && float64(queued)/float64(capacity) >= threshold:
+			return Degraded(detail)
+		default:
+			return Up(detail)
+		}
+	}
+}
+
+// StaleAgents degrades the instance when more than maxRatio of the connected agents
+// missed their heartbeat for longer than heartbeatTimeout
+func StaleAgents(agents agent.Repository, heartbeatTimeout time.Duration, maxRatio float64) Probe {
+	return func(ctx context.Context) Result {
+		all, err := agents.FindAll(ctx)
+		if err != nil {
+			return Down(err)
+		}
+		connected, stale := 0, 0
+		for _, a := range all {
+			if a.Status == agent.AgentStatusOffline || a.Status == agent.AgentStatusDisconnected {
+				continue
+			}
+			connected++
+			if !a.IsHealthy(heartbeatTimeout) {
+				stale++
+			}
+		}
+
+		detail := fmt.Sprintf("%d of %d connected agents stale", stale, connected)
+		if connected > 0 && float64(stale)/float64(connected) > maxRatio {
+			return Degraded(detail)
+		}
+		return Up(detail)
+	}
+}
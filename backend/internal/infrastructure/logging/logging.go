@@ -0,0 +1,90 @@
+// Package logging configures the structured logger and ties log records to the
+// request, event or message they were written for
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"parrotflow/internal/domain/shared"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Output formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Config selects the output format and the minimum level that is logged
+type Config struct {
+	Format string
+	Level  slog.Level
+}
+
+func DefaultConfig() Config {
+	return Config{Format: FormatText, Level: slog.LevelInfo}
+}
+
+// ParseLevel reads a level name: debug, info, warn or error
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return level, fmt.Errorf("unknown log level %q", name)
+	}
+	return level, nil
+}
+
+// New creates a logger writing to w
+// Records logged with a context carrying a correlation get correlation_id and causation_id attributes,
+// records logged within a span get trace_id and span_id
+func New(w io.Writer, config Config) (*slog.Logger, error) {
+	options := &slog.HandlerOptions{Level: config.Level}
+
+	var handler slog.Handler
+	switch strings.ToLower(config.Format) {
+	case FormatText, "":
+		handler = slog.NewTextHandler(w, options)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, options)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", config.Format)
+	}
+	return slog.New(correlationHandler{handler}), nil
+}
+
+// correlationHandler adds the correlation and span of the record's context to the record
+type correlationHandler struct {
+	slog.Handler
+}
+
+func (h correlationHandler) Handle(ctx context.Context, record slog.Record) error {
+	c := shared.CorrelationFromContext(ctx)
+	if c.CorrelationID != "" {
+		record.AddAttrs(slog.String("correlation_id", c.CorrelationID))
+	}
+	if c.CausationID != "" {
+		record.AddAttrs(slog.String("causation_id", c.CausationID))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h correlationHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return correlationHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h correlationHandler) WithGroup(name string) slog.Handler {
+	return correlationHandler{h.Handler.WithGroup(name)}
+}
+
+// Event returns the attributes identifying an event in log records
+func Event(event shared.DomainEvent) slog.Attr {
+	return slog.Group("event", slog.String("id", event.EventID()), slog.String("type", event.EventType()))
+}
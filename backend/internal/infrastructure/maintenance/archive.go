@@ -0,0 +1,115 @@
+package maintenance
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"parrotflow/internal/domain/run"
+)
+
+// ArchivedRun is one line of a run archive file
+type ArchivedRun struct {
+	ID            string            `json:"id"`
+	ScenarioID    string            `json:"scenario_id"`
+	Status        string            `json:"status"`
+	FailureReason string            `json:"failure_reason,omitempty"`
+	Parameters    string            `json:"parameters"`
+	CreatedAt     time.Time         `json:"created_at"`
+	StartedAt     *time.Time        `json:"started_at,omitempty"`
+	FinishedAt    *time.Time        `json:"finished_at,omitempty"`
+	Steps         []ArchivedRunStep `json:"steps,omitempty"`
+}
+
+// ArchivedRunStep is the last reported state of one node of an archived run
+type ArchivedRunStep struct {
+	NodeID     string    `json:"node_id"`
+	Status     string    `json:"status"`
+	Message    string    `json:"message,omitempty"`
+	ReportedAt time.Time `json:"reported_at"`
+}
+
+func newArchivedRun(r *run.Run, steps []run.NodeStep) ArchivedRun {
+	archived := ArchivedRun{
+		ID:            r.Id.String(),
+		ScenarioID:    r.ScenarioID.String(),
+		Status:        r.Status.String(),
+		FailureReason: r.FailureReason,
+		Parameters:    r.Parameters,
+		CreatedAt:     r.CreatedAt.Time(),
+	}
+	if r.StartedAt != nil {
+		startedAt := r.StartedAt.Time()
+		archived.StartedAt = &startedAt
+	}
+	if r.FinishedAt != nil {
+		finishedAt := r.FinishedAt.Time()
+		archived.FinishedAt = &finishedAt
+	}
+	for _, step := range steps {
+		archived.Steps = append(archived.Steps, ArchivedRunStep{
+			NodeID:     step.NodeID,
+			Status:     step.Status.String(),
+			Message:    step.Message,
+			ReportedAt: step.ReportedAt,
+		})
+	}
+	return archived
+}
+
+// FileArchiver writes compacted runs as gzip-compressed JSON lines on local disk,
+// one file per scenario and compaction: <dir>/scenario-<id>/runs-<timestamp>.jsonl.gz
+type FileArchiver struct {
+	dir string
+	now func() time.Time
+}
+
+func NewFileArchiver(dir string) *FileArchiver {
+	return &FileArchiver{
+		dir: dir,
+		now: time.Now,
+	}
+}
+
+// Archive writes the runs to a temporary file and renames it into place,
+// so a crash never leaves a truncated archive behind
+func (a *FileArchiver) Archive(scenarioID string, runs []*run.Run, steps map[string][]run.NodeStep) error {
+	dir := filepath.Join(a.dir, "scenario-"+scenarioID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("runs-%s.jsonl.gz", a.now().UTC().Format("20060102T150405.000000000"))
+	tmp, err := os.CreateTemp(dir, name+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := writeArchive(tmp, runs, steps); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
+
+func writeArchive(file *os.File, runs []*run.Run, steps map[string][]run.NodeStep) error {
+	gz := gzip.NewWriter(file)
+	encoder := json.NewEncoder(gz)
+	for _, r := range runs {
+		if err := encoder.Encode(newArchivedRun(r, steps[r.Id.String()])); err != nil {
+			return err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return file.Sync()
+}
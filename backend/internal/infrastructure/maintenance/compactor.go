@@ -0,0 +1,174 @@
+package maintenance
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/scenario"
+)
+
+// RunStore loads the run history of a scenario and removes compacted runs
+type RunStore interface {
+	FindByScenarioID(ctx context.Context, scenarioID scenario.ScenarioID) ([]*run.Run, error)
+	FindNodeSteps(ctx context.Context, runs []*run.Run) (map[string][]run.NodeStep, error)
+	Compact(ctx context.Context, runs []*run.Run) error
+}
+
+// Archiver keeps a copy of runs and their node steps, keyed by run ID, before they are compacted
+type Archiver interface {
+	Archive(scenarioID string, runs []*run.Run, steps map[string][]run.NodeStep) error
+}
+
+// CompactionConfig holds the global retention policy and how often it is applied
+// Scenarios with their own policy override Retention
+type CompactionConfig struct {
+	Retention  scenario.RetentionPolicy
+	Interval   time.Duration
+	ArchiveDir string // Compacted runs are written here first; empty deletes them outright
+}
+
+func DefaultCompactionConfig() CompactionConfig {
+	return CompactionConfig{
+		Interval:   time.Hour,
+		ArchiveDir: "archive/runs",
+	}
+}
+
+// RunCompactor applies retention policies to run history
+// Expired runs are archived (when an archiver is set), then deleted and folded
+// into summary counters by the store
+type RunCompactor struct {
+	scenarios scenario.Repository
+	runs      RunStore
+	archiver  Archiver
+	config    CompactionConfig
+	now       func() time.Time
+}
+
+func NewRunCompactor(scenarios scenario.Repository, runs RunStore, archiver Archiver, config CompactionConfig) *RunCompactor {
+	return &RunCompactor{
+		scenarios: scenarios,
+		runs:      runs,
+		archiver:  archiver,
+		config:    config,
+		now:       time.Now,
+	}
+}
+
+// Run compacts run history until the context is cancelled
+func (c *RunCompactor) Run(ctx context.Context) {
+	if c.config.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := c.CompactOnce(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Error compacting run history", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CompactOnce applies retention to every scenario and returns how many runs were removed
+// A failing scenario is logged and skipped so the others still get compacted
+func (c *RunCompactor) CompactOnce(ctx context.Context) (int, error) {
+	page, err := c.scenarios.FindAll(ctx, scenario.SearchCriteria{})
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	var firstErr error
+	for _, s := range page.Items {
+		compacted, err := c.compactScenario(ctx, s)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			slog.ErrorContext(ctx, "Error compacting runs", "scenario_id", s.Id.String(), "error", err)
+			continue
+		}
+		if compacted > 0 {
+			slog.InfoContext(ctx, "Compacted runs", "scenario_id", s.Id.String(), "compacted", compacted)
+		}
+		total += compacted
+	}
+
+	return total, firstErr
+}
+
+func (c *RunCompactor) compactScenario(ctx context.Context, s *scenario.Scenario) (int, error) {
+	policy := c.policyFor(s)
+	if policy.IsUnlimited() {
+		return 0, nil
+	}
+
+	runs, err := c.runs.FindByScenarioID(ctx, s.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := selectExpired(runs, policy, c.now())
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	// Only delete once the archive is safely on disk
+	// Compaction deletes the node steps too, so they go into the archive with their run
+	if c.archiver != nil {
+		steps, err := c.runs.FindNodeSteps(ctx, expired)
+		if err != nil {
+			return 0, err
+		}
+		if err := c.archiver.Archive(s.Id.String(), expired, steps); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := c.runs.Compact(ctx, expired); err != nil {
+		return 0, err
+	}
+	return len(expired), nil
+}
+
+func (c *RunCompactor) policyFor(s *scenario.Scenario) scenario.RetentionPolicy {
+	if s.Retention != nil {
+		return *s.Retention
+	}
+	return c.config.Retention
+}
+
+// selectExpired picks the finished runs that fall outside the policy
+// MaxRuns counts every run newest first, so unfinished or kept runs still use up the quota
+func selectExpired(runs []*run.Run, policy scenario.RetentionPolicy, now time.Time) []*run.Run {
+	sorted := make([]*run.Run, len(runs))
+	copy(sorted, runs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Time().After(sorted[j].CreatedAt.Time())
+	})
+
+	expired := make([]*run.Run, 0)
+	for i, r := range sorted {
+		if !r.IsFinished() || policy.Keeps(r.Status.String()) {
+			continue
+		}
+
+		tooOld := policy.MaxAge > 0 && now.Sub(r.CreatedAt.Time()) > policy.MaxAge
+		tooMany := policy.MaxRuns > 0 && i >= policy.MaxRuns
+		if tooOld || tooMany {
+			expired = append(expired, r)
+		}
+	}
+	return expired
+}
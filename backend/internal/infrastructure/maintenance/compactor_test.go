@@ -0,0 +1,261 @@
+package maintenance
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/shared"
+)
+
+// MockScenarioRepository serves a fixed list of scenarios
+type MockScenarioRepository struct {
+	scenario.Repository
+	scenarios []*scenario.Scenario
+}
+
+func (m *MockScenarioRepository) FindAll(ctx context.Context, criteria scenario.SearchCriteria) (shared.Page[*scenario.Scenario], error) {
+	return shared.Page[*scenario.Scenario]{Items: m.scenarios, Total: int64(len(m.scenarios))}, nil
+}
+
+// MockRunStore records the runs it was asked to compact
+type MockRunStore struct {
+	runs      map[string][]*run.Run
+	steps     map[string][]run.NodeStep
+	compacted []*run.Run
+}
+
+func (m *MockRunStore) FindByScenarioID(ctx context.Context, scenarioID scenario.ScenarioID) ([]*run.Run, error) {
+	return m.runs[scenarioID.String()], nil
+}
+
+func (m *MockRunStore) FindNodeSteps(ctx context.Context, runs []*run.Run) (map[string][]run.NodeStep, error) {
+	steps := make(map[string][]run.NodeStep)
+	for _, r := range runs {
+		if s, ok := m.steps[r.Id.String()]; ok {
+			steps[r.Id.String()] = s
+		}
+	}
+	return steps, nil
+}
+
+func (m *MockRunStore) Compact(ctx context.Context, runs []*run.Run) error {
+	m.compacted = append(m.compacted, runs...)
+	return nil
+}
+
+// MockArchiver records archived runs per scenario and their steps
+type MockArchiver struct {
+	archived map[string][]*run.Run
+	steps    map[string][]run.NodeStep
+}
+
+func (m *MockArchiver) Archive(scenarioID string, runs []*run.Run, steps map[string][]run.NodeStep) error {
+	if m.archived == nil {
+		m.archived = make(map[string][]*run.Run)
+		m.steps = make(map[string][]run.NodeStep)
+	}
+	m.archived[scenarioID] = append(m.archived[scenarioID], runs...)
+	for runID, s := range steps {
+		m.steps[runID] = s
+	}
+	return nil
+}
+
+func newTestScenario(t *testing.T, id string) *scenario.Scenario {
+	t.Helper()
+	scenarioID, _ := scenario.NewScenarioID(id)
+	s, err := scenario.NewScenario(scenarioID, "scenario "+id)
+	if err != nil {
+		t.Fatalf("NewScenario() error = %v", err)
+	}
+	return s
+}
+
+func newTestRun(id, scenarioID string, status shared.Status, createdAt time.Time) *run.Run {
+	runID, _ := run.NewRunID(id)
+	sid, _ := scenario.NewScenarioID(scenarioID)
+	return &run.Run{
+		Id:         runID,
+		ScenarioID: sid,
+		Status:     status,
+		Parameters: "{}",
+		CreatedAt:  shared.NewTimestamp(createdAt),
+		UpdatedAt:  shared.NewTimestamp(createdAt),
+	}
+}
+
+func runIDs(runs []*run.Run) []string {
+	ids := make([]string, len(runs))
+	for i, r := range runs {
+		ids[i] = r.Id.String()
+	}
+	return ids
+}
+
+func equalIDs(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSelectExpired(t *testing.T) {
+	now := time.Date(2024, 10, 27, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	runs := []*run.Run{
+		newTestRun("old-failed", "s1", shared.StatusFailed, now.Add(-10*day)),
+		newTestRun("newest", "s1", shared.StatusCompleted, now.Add(-time.Hour)),
+		newTestRun("old-running", "s1", shared.StatusRunning, now.Add(-9*day)),
+		newTestRun("middle", "s1", shared.StatusCompleted, now.Add(-2*day)),
+		newTestRun("old-completed", "s1", shared.StatusCompleted, now.Add(-8*day)),
+	}
+
+	tests := []struct {
+		name   string
+		policy scenario.RetentionPolicy
+		want   []string
+	}{
+		{
+			name:   "max age",
+			policy: scenario.RetentionPolicy{MaxAge: 7 * day},
+			want:   []string{"old-completed", "old-failed"},
+		},
+		{
+			name:   "max runs counts unfinished runs",
+			policy: scenario.RetentionPolicy{MaxRuns: 2},
+			want:   []string{"old-completed", "old-failed"},
+		},
+		{
+			name:   "max runs keeps the newest",
+			policy: scenario.RetentionPolicy{MaxRuns: 1},
+			want:   []string{"middle", "old-completed", "old-failed"},
+		},
+		{
+			name:   "kept statuses",
+			policy: scenario.RetentionPolicy{MaxAge: 7 * day, KeepStatuses: []string{"FAILED"}},
+			want:   []string{"old-completed"},
+		},
+		{
+			name:   "unlimited",
+			policy: scenario.RetentionPolicy{},
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := runIDs(selectExpired(runs, tt.policy, now))
+			if !equalIDs(got, tt.want) {
+				t.Errorf("selectExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunCompactor_ScenarioPolicyOverridesGlobal(t *testing.T) {
+	now := time.Date(2024, 10, 27, 12, 0, 0, 0, time.UTC)
+	global := newTestScenario(t, "global")
+	custom := newTestScenario(t, "custom")
+	custom.UpdateRetention(&scenario.RetentionPolicy{MaxRuns: 1})
+
+	store := &MockRunStore{runs: map[string][]*run.Run{
+		"global": {
+			newTestRun("g1", "global", shared.StatusCompleted, now.Add(-time.Hour)),
+			newTestRun("g2", "global", shared.StatusCompleted, now.Add(-2*time.Hour)),
+		},
+		"custom": {
+			newTestRun("c1", "custom", shared.StatusCompleted, now.Add(-time.Hour)),
+			newTestRun("c2", "custom", shared.StatusCompleted, now.Add(-2*time.Hour)),
+		},
+	}, steps: map[string][]run.NodeStep{
+		"c2": {{NodeID: "click", Status: run.NodeStatusFailed, Message: "button not found", ReportedAt: now}},
+	}}
+	archiver := &MockArchiver{}
+	repo := &MockScenarioRepository{scenarios: []*scenario.Scenario{global, custom}}
+
+	compactor := NewRunCompactor(repo, store, archiver, CompactionConfig{
+		Retention: scenario.RetentionPolicy{MaxAge: 30 * 24 * time.Hour},
+		Interval:  time.Hour,
+	})
+	compactor.now = func() time.Time { return now }
+
+	compacted, err := compactor.CompactOnce(context.Background())
+	if err != nil {
+		t.Fatalf("CompactOnce() error = %v, want nil", err)
+	}
+	if compacted != 1 {
+		t.Errorf("CompactOnce() compacted = %d, want 1", compacted)
+	}
+	if got := runIDs(store.compacted); !equalIDs(got, []string{"c2"}) {
+		t.Errorf("Compacted runs = %v, want [c2]", got)
+	}
+	if got := runIDs(archiver.archived["custom"]); !equalIDs(got, []string{"c2"}) {
+		t.Errorf("Archived runs = %v, want [c2]", got)
+	}
+	// Compaction deletes the steps, the archive keeps them
+	if steps := archiver.steps["c2"]; len(steps) != 1 || steps[0].Message != "button not found" {
+		t.Errorf("Archived steps = %+v, want the failed click of c2", steps)
+	}
+}
+
+func TestFileArchiver_WritesGzipJSONLines(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 10, 27, 12, 0, 0, 0, time.UTC)
+	archiver := NewFileArchiver(dir)
+	archiver.now = func() time.Time { return now }
+
+	failed := newTestRun("r2", "s1", shared.StatusFailed, now.Add(-2*time.Hour))
+	failed.FailureReason = "agent lost"
+	runs := []*run.Run{newTestRun("r1", "s1", shared.StatusCompleted, now.Add(-time.Hour)), failed}
+	steps := map[string][]run.NodeStep{
+		"r2": {{NodeID: "click", Status: run.NodeStatusFailed, Message: "button not found", ReportedAt: now}},
+	}
+	if err := archiver.Archive("s1", runs, steps); err != nil {
+		t.Fatalf("Archive() error = %v, want nil", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "scenario-s1", "*"))
+	if err != nil || len(files) != 1 || filepath.Ext(files[0]) != ".gz" {
+		t.Fatalf("Archive files = %v, want a single .jsonl.gz", files)
+	}
+
+	file, err := os.Open(files[0])
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+
+	var got []ArchivedRun
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var archived ArchivedRun
+		if err := json.Unmarshal(scanner.Bytes(), &archived); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		got = append(got, archived)
+	}
+
+	if len(got) != 2 || got[0].ID != "r1" || got[1].Status != "FAILED" {
+		t.Fatalf("Archived runs = %+v, want r1 and the failed r2", got)
+	}
+	if got[1].FailureReason != "agent lost" || len(got[1].Steps) != 1 || got[1].Steps[0].Message != "button not found" {
+		t.Errorf("Archived r2 = %+v, want its failure reason and node step", got[1])
+	}
+}
@@ -0,0 +1,109 @@
+package maintenance
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Config groups the settings of all maintenance workers
+type Config struct {
+	Purge      PurgeConfig
+	Compaction CompactionConfig
+	Rollup     RollupConfig
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Purge:      DefaultPurgeConfig(),
+		Compaction: DefaultCompactionConfig(),
+		Rollup:     DefaultRollupConfig(),
+	}
+}
+
+// Purger permanently removes entities that were moved to the trash before a cutoff
+type Purger interface {
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+}
+
+// PurgeConfig controls how long trashed entities are kept and how often the trash is swept
+type PurgeConfig struct {
+	Retention time.Duration
+	Interval  time.Duration
+}
+
+func DefaultPurgeConfig() PurgeConfig {
+	return PurgeConfig{
+		Retention: 30 * 24 * time.Hour,
+		Interval:  time.Hour,
+	}
+}
+
+// PurgeWorker periodically empties the trash of everything older than the retention period
+// A zero or negative retention disables purging, so trashed entities are kept forever
+type PurgeWorker struct {
+	purgers map[string]Purger
+	config  PurgeConfig
+	now     func() time.Time
+}
+
+func NewPurgeWorker(config PurgeConfig) *PurgeWorker {
+	return &PurgeWorker{
+		purgers: make(map[string]Purger),
+		config:  config,
+		now:     time.Now,
+	}
+}
+
+// AddPurger registers the trash of one aggregate under a name used in logs
+// Must be called before Run
+func (w *PurgeWorker) AddPurger(name string, purger Purger) {
+	w.purgers[name] = purger
+}
+
+// Run sweeps the trash until the context is cancelled
+func (w *PurgeWorker) Run(ctx context.Context) {
+	if w.config.Retention <= 0 || w.config.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.PurgeOnce(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Error purging trash", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeOnce purges every registered trash and returns how many entities were removed
+// Purgers are independent, so a failing one does not stop the others
+func (w *PurgeWorker) PurgeOnce(ctx context.Context) (int64, error) {
+	cutoff := w.now().Add(-w.config.Retention)
+
+	var total int64
+	var firstErr error
+	for name, purger := range w.purgers {
+		purged, err := purger.PurgeDeleted(ctx, cutoff)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			slog.ErrorContext(ctx, "Error purging trash", "trash", name, "error", err)
+			continue
+		}
+		if purged > 0 {
+			slog.InfoContext(ctx, "Purged trash", "trash", name, "purged", purged)
+		}
+		total += purged
+	}
+
+	return total, firstErr
+}
@@ -0,0 +1,76 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// MockPurger records the cutoff it was called with
+type MockPurger struct {
+	purged int64
+	err    error
+	before time.Time
+	calls  int
+}
+
+func (m *MockPurger) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	m.calls++
+	m.before = before
+	return m.purged, m.err
+}
+
+func TestPurgeWorker_UsesRetentionCutoff(t *testing.T) {
+	now := time.Date(2024, 10, 27, 12, 0, 0, 0, time.UTC)
+	scenarios := &MockPurger{purged: 2}
+	tags := &MockPurger{purged: 1}
+
+	worker := NewPurgeWorker(PurgeConfig{Retention: 24 * time.Hour, Interval: time.Hour})
+	worker.now = func() time.Time { return now }
+	worker.AddPurger("scenarios", scenarios)
+	worker.AddPurger("tags", tags)
+
+	purged, err := worker.PurgeOnce(context.Background())
+	if err != nil {
+		t.Fatalf("PurgeOnce() error = %v, want nil", err)
+	}
+	if purged != 3 {
+		t.Errorf("PurgeOnce() purged = %d, want 3", purged)
+	}
+
+	want := now.Add(-24 * time.Hour)
+	if !scenarios.before.Equal(want) || !tags.before.Equal(want) {
+		t.Errorf("Cutoff = %v / %v, want %v", scenarios.before, tags.before, want)
+	}
+}
+
+func TestPurgeWorker_ContinuesAfterFailure(t *testing.T) {
+	failing := &MockPurger{err: errors.New("database is locked")}
+	healthy := &MockPurger{purged: 4}
+
+	worker := NewPurgeWorker(DefaultPurgeConfig())
+	worker.AddPurger("proxies", failing)
+	worker.AddPurger("tags", healthy)
+
+	purged, err := worker.PurgeOnce(context.Background())
+	if err == nil {
+		t.Error("PurgeOnce() error = nil, want the purger failure")
+	}
+	if purged != 4 || healthy.calls != 1 {
+		t.Errorf("Healthy purger calls = %d purged = %d, want 1 and 4", healthy.calls, purged)
+	}
+}
+
+func TestPurgeWorker_DisabledRetention(t *testing.T) {
+	purger := &MockPurger{}
+	worker := NewPurgeWorker(PurgeConfig{Retention: 0, Interval: time.Millisecond})
+	worker.AddPurger("scenarios", purger)
+
+	// Returns immediately instead of sweeping
+	worker.Run(context.Background())
+
+	if purger.calls != 0 {
+		t.Errorf("Purger calls = %d, want 0 with retention disabled", purger.calls)
+	}
+}
@@ -0,0 +1,69 @@
+package maintenance
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// RollupStore recomputes the daily run rollup
+type RollupStore interface {
+	RefreshRollup(ctx context.Context, since time.Time) error
+}
+
+// RollupConfig controls how often the run rollup is refreshed and how far back
+// Days older than Lookback keep their rollup rows as they are, so they survive run
+// retention; Lookback should stay below the retention age, runs compacted within
+// it drop out of the rollup on the next refresh
+type RollupConfig struct {
+	Interval time.Duration // Zero disables the rollup
+	Lookback time.Duration
+}
+
+func DefaultRollupConfig() RollupConfig {
+	return RollupConfig{
+		Lookback: 48 * time.Hour,
+	}
+}
+
+// RollupWorker keeps the materialized daily run rollup up to date
+type RollupWorker struct {
+	store  RollupStore
+	config RollupConfig
+	now    func() time.Time
+}
+
+func NewRollupWorker(store RollupStore, config RollupConfig) *RollupWorker {
+	return &RollupWorker{
+		store:  store,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Run refreshes the rollup until the context is cancelled
+func (w *RollupWorker) Run(ctx context.Context) {
+	if w.config.Interval <= 0 || w.config.Lookback <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.RefreshOnce(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Error refreshing run rollup", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RefreshOnce recomputes the rollup of the days within the lookback
+func (w *RollupWorker) RefreshOnce(ctx context.Context) error {
+	return w.store.RefreshRollup(ctx, w.now().Add(-w.config.Lookback))
+}
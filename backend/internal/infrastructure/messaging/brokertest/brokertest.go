@@ -0,0 +1,154 @@
+// Package brokertest checks that a message broker behaves the way the ports describe,
+// so every implementation is held to the same contract
+package brokertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"parrotflow/internal/ports"
+)
+
+const timeout = 5 * time.Second
+
+// Run tests the broker newBroker returns; every subtest gets a fresh broker
+func Run(t *testing.T, newBroker func(t *testing.T) ports.MessageBroker) {
+	t.Run("AnswersPing", func(t *testing.T) {
+		testPing(t, newBroker(t))
+	})
+	t.Run("DeliversPublishedMessages", func(t *testing.T) {
+		testDelivers(t, newBroker(t))
+	})
+	t.Run("DeadLettersRejectedMessages", func(t *testing.T) {
+		testDeadLetters(t, newBroker(t))
+	})
+	t.Run("StopsConsumers", func(t *testing.T) {
+		testStops(t, newBroker(t))
+	})
+}
+
+func testMessage(id string) ports.Message {
+	return ports.Message{
+		ID:          id,
+		ContentType: "application/json",
+		Headers:     map[string]string{"x-source": "brokertest"},
+		Body:        []byte(`{"id":"` + id + `"}`),
+	}
+}
+
+func testPing(t *testing.T, broker ports.MessageBroker) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := broker.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+}
+
+func testDelivers(t *testing.T, broker ports.MessageBroker) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := broker.AssertQueue(ctx, "brokertest.deliver"); err != nil {
+		t.Fatalf("AssertQueue() error = %v", err)
+	}
+	for _, id := range []string{"1", "2"} {
+		if err := broker.Publish(ctx, "brokertest.deliver", testMessage(id)); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	received := make(chan ports.Message, 2)
+	err := broker.Consume(ctx, "brokertest.deliver", func(ctx context.Context, message ports.Message) error {
+		received <- message
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+
+	for _, id := range []string{"1", "2"} {
+		select {
+		case message := <-received:
+			want := testMessage(id)
+			if message.ID != want.ID || message.ContentType != want.ContentType || string(message.Body) != string(want.Body) {
+				t.Errorf("Received %+v, want %+v", message, want)
+			}
+			if message.Headers["x-source"] != "brokertest" {
+				t.Errorf("Received headers %v", message.Headers)
+			}
+		case <-ctx.Done():
+			t.Fatalf("Message %s was not delivered", id)
+		}
+	}
+}
+
+func testDeadLetters(t *testing.T, broker ports.MessageBroker) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := broker.AssertQueue(ctx, "brokertest.reject"); err != nil {
+		t.Fatalf("AssertQueue() error = %v", err)
+	}
+	err := broker.Consume(ctx, "brokertest.reject", func(ctx context.Context, message ports.Message) error {
+		return errors.New("cannot process")
+	})
+	if err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+	if err := broker.Publish(ctx, "brokertest.reject", testMessage("3")); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	deadLetters := make(chan ports.DeadLetteredMessage, 1)
+	go broker.ConsumeDeadLetters(ctx, func(ctx context.Context, message ports.DeadLetteredMessage) error {
+		deadLetters <- message
+		return nil
+	})
+
+	select {
+	case dead := <-deadLetters:
+		if dead.Queue != "brokertest.reject" || dead.Reason != "rejected" {
+			t.Errorf("Dead-lettered from %q for %q", dead.Queue, dead.Reason)
+		}
+		if dead.ID != "3" || string(dead.Body) != `{"id":"3"}` || dead.Headers["x-source"] != "brokertest" {
+			t.Errorf("Dead-lettered %+v", dead.Message)
+		}
+		if dead.DeadLetteredAt.IsZero() {
+			t.Error("Dead-lettered without a time")
+		}
+	case <-ctx.Done():
+		t.Fatal("Rejected message was not dead-lettered")
+	}
+}
+
+func testStops(t *testing.T, broker ports.MessageBroker) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := broker.AssertQueue(ctx, "brokertest.stop"); err != nil {
+		t.Fatalf("AssertQueue() error = %v", err)
+	}
+	received := make(chan ports.Message, 1)
+	err := broker.Consume(ctx, "brokertest.stop", func(ctx context.Context, message ports.Message) error {
+		received <- message
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+	if err := broker.Stop(ctx); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	if err := broker.Publish(ctx, "brokertest.stop", testMessage("4")); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	select {
+	case message := <-received:
+		t.Errorf("Stopped consumer received %+v", message)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
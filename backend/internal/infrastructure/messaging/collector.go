@@ -0,0 +1,86 @@
+package messaging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"parrotflow/internal/domain/deadletter"
+	"parrotflow/internal/ports"
+	utils "parrotflow/pkg/shared"
+)
+
+// DeadLetterCollector declares the agent queues with their dead-letter routing and
+// moves dead-lettered messages into the dead letter repository, where operators
+// can inspect, replay or discard them
+type DeadLetterCollector struct {
+	broker     ports.DeadLetterBroker
+	repository deadletter.Repository
+	queues     []string
+	retryDelay time.Duration
+}
+
+func NewDeadLetterCollector(broker ports.DeadLetterBroker, repository deadletter.Repository, queues []string) *DeadLetterCollector {
+	return &DeadLetterCollector{
+		broker:     broker,
+		repository: repository,
+		queues:     queues,
+		retryDelay: 5 * time.Second,
+	}
+}
+
+// Run collects dead letters until the context is cancelled, reconnecting when
+// the broker is unavailable
+func (c *DeadLetterCollector) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := c.declare(ctx); err != nil {
+			slog.ErrorContext(ctx, "Error declaring broker queues", "error", err)
+		} else if err := c.broker.ConsumeDeadLetters(ctx, c.Collect); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Error consuming dead letters", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(c.retryDelay):
+		}
+	}
+}
+
+func (c *DeadLetterCollector) declare(ctx context.Context) error {
+	for _, queue := range c.queues {
+		if err := c.broker.AssertQueue(ctx, queue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Collect stores one dead-lettered message
+// Its events join the correlation of the message, so they can be traced to the request that sent it
+func (c *DeadLetterCollector) Collect(ctx context.Context, message ports.DeadLetteredMessage) error {
+	ctx = ContextWithMessage(ctx, message.Message)
+
+	id, err := deadletter.NewDeadLetterID(utils.CustomUUID())
+	if err != nil {
+		return err
+	}
+
+	d, err := deadletter.NewDeadLetter(id, message.Queue, message.Reason, message.Body, message.DeadLetteredAt)
+	if err != nil {
+		return err
+	}
+	d.MessageID = message.ID
+	d.ContentType = message.ContentType
+	if message.Headers != nil {
+		d.Headers = message.Headers
+	}
+	if message.Count > 0 {
+		d.DeathCount = message.Count
+	}
+
+	if err := c.repository.Save(ctx, d); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "Collected dead letter", "dead_letter_id", d.Id.String(), "queue", d.Queue, "reason", d.Reason)
+	return nil
+}
@@ -0,0 +1,62 @@
+package messaging
+
+import (
+	"context"
+
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/ports"
+)
+
+// Headers carrying the correlation of a message, read by the agent and copied onto
+// the messages it sends back
+const (
+	HeaderCorrelationID = "x-correlation-id"
+	HeaderCausationID   = "x-causation-id"
+)
+
+// CorrelatedBroker ties messages to the work that sent them: published messages get
+// the correlation of the publishing context, and consumers are handed a context
+// whose correlation is the one of the message they handle
+type CorrelatedBroker struct {
+	ports.MessageBroker
+}
+
+// NewCorrelatedBroker wraps a broker so correlations cross it
+func NewCorrelatedBroker(broker ports.MessageBroker) *CorrelatedBroker {
+	return &CorrelatedBroker{MessageBroker: broker}
+}
+
+// Publish sends the message with the correlation headers of ctx
+// Headers the message already carries win, so a replayed message keeps its original chain
+func (b *CorrelatedBroker) Publish(ctx context.Context, queue string, message ports.Message) error {
+	c := shared.CorrelationFromContext(ctx)
+	if c.CorrelationID == "" {
+		return b.MessageBroker.Publish(ctx, queue, message)
+	}
+
+	headers := make(map[string]string, len(message.Headers)+2)
+	headers[HeaderCorrelationID] = c.CorrelationID
+	headers[HeaderCausationID] = c.CausationID
+	for key, value := range message.Headers {
+		headers[key] = value
+	}
+	message.Headers = headers
+	return b.MessageBroker.Publish(ctx, queue, message)
+}
+
+// Consume hands the handler a context carrying the correlation of each message
+func (b *CorrelatedBroker) Consume(ctx context.Context, queue string, handler ports.MessageHandler) error {
+	return b.MessageBroker.Consume(ctx, queue, func(ctx context.Context, message ports.Message) error {
+		return handler(ContextWithMessage(ctx, message), message)
+	})
+}
+
+// ContextWithMessage returns a context for handling a message: it stays in the
+// message's correlation, or starts one at the message, and the message is its cause
+func ContextWithMessage(ctx context.Context, message ports.Message) context.Context {
+	c := shared.Correlation{CorrelationID: message.Headers[HeaderCorrelationID], CausationID: message.ID}
+	if c.CorrelationID == "" {
+		c.CorrelationID = message.ID
+	}
+	return shared.ContextWithCorrelation(ctx, c)
+}
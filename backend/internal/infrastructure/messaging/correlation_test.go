@@ -0,0 +1,55 @@
+package messaging_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/messaging"
+	"parrotflow/internal/infrastructure/messaging/memory"
+	"parrotflow/internal/ports"
+)
+
+func TestCorrelatedBroker_CarriesCorrelationAcrossTheBroker(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	broker := messaging.NewCorrelatedBroker(memory.NewBroker())
+	if err := broker.AssertQueue(ctx, "correlated"); err != nil {
+		t.Fatalf("AssertQueue() error = %v", err)
+	}
+
+	requestCtx := shared.ContextWithCorrelation(ctx, shared.Correlation{CorrelationID: "request-1", CausationID: "request-1"})
+	if err := broker.Publish(requestCtx, "correlated", ports.Message{ID: "message-1"}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	// A replayed message keeps the chain it was first sent in
+	replayed := ports.Message{ID: "message-2", Headers: map[string]string{messaging.HeaderCorrelationID: "request-0"}}
+	if err := broker.Publish(requestCtx, "correlated", replayed); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	received := make(chan shared.Correlation, 2)
+	err := broker.Consume(ctx, "correlated", func(ctx context.Context, message ports.Message) error {
+		received <- shared.CorrelationFromContext(ctx)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+
+	for _, want := range []shared.Correlation{
+		{CorrelationID: "request-1", CausationID: "message-1"},
+		{CorrelationID: "request-0", CausationID: "message-2"},
+	} {
+		select {
+		case got := <-received:
+			if got != want {
+				t.Errorf("Handler correlation = %+v, want %+v", got, want)
+			}
+		case <-ctx.Done():
+			t.Fatal("Message was not delivered")
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/secret"
+	"parrotflow/internal/domain/shared"
+	messages "parrotflow/internal/interfaces/messaging"
+	"parrotflow/internal/ports"
+)
+
+// errNoDefinition is returned for runs started without a snapshot of their scenario
+var errNoDefinition = errors.New("run has no scenario definition to execute")
+
+// RunDispatcher sends started runs to the agents as execution requests
+// Secret references in the scenario parameters are resolved here and nowhere else:
+// the resolved values only travel in the request, never in stored runs or events
+type RunDispatcher struct {
+	runs      run.Repository
+	secrets   secret.Repository
+	publisher ports.MessagePublisher
+}
+
+func NewRunDispatcher(runs run.Repository, secrets secret.Repository, publisher ports.MessagePublisher) *RunDispatcher {
+	return &RunDispatcher{
+		runs:      runs,
+		secrets:   secrets,
+		publisher: publisher,
+	}
+}
+
+// Handle publishes the execution request of a started run
+// A run whose secrets cannot be resolved, or that has no definition, fails: retrying would
+// not bring a deleted secret back
+func (d *RunDispatcher) Handle(ctx context.Context, event shared.DomainEvent) error {
+	started, ok := event.(run.RunStarted)
+	if !ok {
+		return nil
+	}
+
+	message, err := d.executionRequest(ctx, started)
+	switch {
+	case errors.Is(err, secret.ErrUnknownSecret):
+		return d.fail(ctx, started.RunID, fmt.Sprintf("secret references could not be resolved: %v", err))
+	case errors.Is(err, errNoDefinition):
+		return d.fail(ctx, started.RunID, err.Error())
+	case err != nil:
+		return fmt.Errorf("building execution request of run %s: %w", started.RunID, err)
+	}
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	// The run ID identifies the request, so agents can drop one relayed twice
+	err = d.publisher.Publish(ctx, AgentRequestQueue, ports.Message{
+		ID:          started.RunID,
+		ContentType: "application/json",
+		Body:        body,
+	})
+	if err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "Run dispatched to agents", "run_id", started.RunID, "scenario_id", started.ScenarioID)
+	return nil
+}
+
+func (d *RunDispatcher) CanHandle(eventType string) bool {
+	return eventType == run.EventRunStarted
+}
+
+// fail fails a run that cannot be dispatched, unless it finished in the meantime
+// Its RunFailed event is recorded with it and relayed like any other
+func (d *RunDispatcher) fail(ctx context.Context, runID, reason string) error {
+	id, err := run.NewRunID(runID)
+	if err != nil {
+		return err
+	}
+	err = command.RetryOnConflict(ctx, func() error {
+		r, err := d.runs.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if r.Status != shared.StatusRunning {
+			return nil
+		}
+		if err := r.Fail(reason); err != nil {
+			return err
+		}
+		return d.runs.Save(ctx, r)
+	})
+	if err != nil {
+		return fmt.Errorf("failing run %s: %w", runID, err)
+	}
+	slog.WarnContext(ctx, "Run failed before dispatch", "run_id", runID, "reason", reason)
+	return nil
+}
+
+// executionRequest builds the request of a started run from the scenario definition it
+// snapshotted when it started, so edits to the scenario since do not reach it. The secrets
+// the definition references are resolved; one deleted since fails with secret.ErrUnknownSecret
+func (d *RunDispatcher) executionRequest(ctx context.Context, started run.RunStarted) (*messages.ExecuteScenarioMessage, error) {
+	runID, err := run.NewRunID(started.RunID)
+	if err != nil {
+		return nil, err
+	}
+	r, err := d.runs.FindByID(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	if r.Definition == nil {
+		return nil, errNoDefinition
+	}
+	definition := r.Definition
+
+	found, err := d.secrets.FindByNames(ctx, r.SecretNames)
+	if err != nil {
+		return nil, err
+	}
+	resolve := &resolver{values: secret.Values(found)}
+
+	message := &messages.ExecuteScenarioMessage{
+		RunID:        started.RunID,
+		ScenarioID:   started.ScenarioID,
+		Context:      contextMessage(definition.Context),
+		ControlQueue: messages.QueueAgentControl(started.RunID),
+		ReplyQueue:   ProgressQueue(started.RunID),
+	}
+	message.InputData.Parameters = make([]messages.NodeParameters, len(definition.InputData.Parameters))
+	for i, node := range definition.InputData.Parameters {
+		message.InputData.Parameters[i] = messages.NodeParameters{
+			BlockID: node.BlockID,
+			Input:   resolve.parameters(node.Input),
+			Output:  resolve.parameters(node.Output),
+		}
+	}
+	message.Parameters = messages.Parameters{
+		Input:  resolve.parameterItems(definition.Parameters.Input),
+		Output: resolve.parameterItems(definition.Parameters.Output),
+	}
+	return message, resolve.err
+}
+
+func contextMessage(c scenario.Context) messages.Context {
+	blocks := make([]messages.Node, len(c.Blocks))
+	for i, node := range c.Blocks {
+		blocks[i] = messages.Node{
+			ID:       node.Id,
+			NodeType: messages.NodeType(node.NodeType),
+			Position: messages.Point2D{X: float64(node.Position.X), Y: float64(node.Position.Y)},
+		}
+	}
+	edges := make([]messages.Edge, len(c.Edges))
+	for i, edge := range c.Edges {
+		edges[i] = messages.Edge{
+			ID:           edge.Id,
+			Source:       edge.Source,
+			Target:       edge.Target,
+			SourceHandle: edge.SourceHandle,
+			TargetHandle: edge.TargetHandle,
+			Condition:    edge.Condition,
+		}
+	}
+	return messages.Context{Blocks: blocks, Edges: edges}
+}
+
+// resolver resolves secret references in parameter values, keeping the first error
+type resolver struct {
+	values map[string]string
+	err    error
+}
+
+func (r *resolver) parameters(parameters []scenario.Parameter) []messages.Parameter {
+	resolved := make([]messages.Parameter, len(parameters))
+	for i, p := range parameters {
+		resolved[i] = messages.Parameter{Name: p.Name, Value: r.value(p.Value)}
+	}
+	return resolved
+}
+
+func (r *resolver) parameterItems(items []scenario.ParameterItem) []messages.ParameterItem {
+	resolved := make([]messages.ParameterItem, len(items))
+	for i, item := range items {
+		resolved[i] = messages.ParameterItem{
+			Parameter: messages.Parameter{Name: item.Parameter.Name, Value: r.value(item.Parameter.Value)},
+			ParamType: messages.ParameterItemParamType(item.ParamType),
+			Values:    item.Values,
+		}
+	}
+	return resolved
+}
+
+func (r *resolver) value(value any) any {
+	resolved, err := secret.Resolve(value, r.values)
+	if err != nil {
+		if r.err == nil {
+			r.err = err
+		}
+		return nil
+	}
+	return resolved
+}
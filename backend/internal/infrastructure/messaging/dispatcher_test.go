@@ -0,0 +1,252 @@
+package messaging_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	runcommand "parrotflow/internal/application/command/run"
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/secret"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/messaging"
+	"parrotflow/internal/infrastructure/messaging/memory"
+	messages "parrotflow/internal/interfaces/messaging"
+)
+
+// stubScenarios serves a single scenario
+type stubScenarios struct {
+	scenario.Repository
+	scenario *scenario.Scenario
+}
+
+func (s *stubScenarios) FindByID(ctx context.Context, id scenario.ScenarioID) (*scenario.Scenario, error) {
+	return s.scenario, nil
+}
+
+// stubRuns keeps runs in memory
+type stubRuns struct {
+	run.Repository
+	runs map[string]*run.Run
+}
+
+func (s *stubRuns) FindByID(ctx context.Context, id run.RunID) (*run.Run, error) {
+	return s.runs[id.String()], nil
+}
+
+func (s *stubRuns) Save(ctx context.Context, r *run.Run) error {
+	s.runs[r.Id.String()] = r
+	return nil
+}
+
+// stubSecrets keeps secrets in memory
+type stubSecrets struct {
+	secret.Repository
+	secrets []*secret.Secret
+}
+
+func (s *stubSecrets) FindByNames(ctx context.Context, names []string) ([]*secret.Secret, error) {
+	var found []*secret.Secret
+	for _, sec := range s.secrets {
+		for _, name := range names {
+			if sec.Name == name {
+				found = append(found, sec)
+			}
+		}
+	}
+	return found, nil
+}
+
+func loginScenario(t *testing.T) *scenario.Scenario {
+	t.Helper()
+	id, _ := scenario.NewScenarioID("1")
+	s, err := scenario.NewScenario(id, "login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start, _ := scenario.NewNode("start", "start", scenario.NewPoint2D(0, 0))
+	input, _ := scenario.NewNode("password", "inputdata", scenario.NewPoint2D(1, 2))
+	edge, _ := scenario.NewEdge("e1", "start", "password", "", "")
+	s.UpdateContext(scenario.NewContext([]scenario.Node{start, input}, []scenario.Edge{edge}))
+
+	text, _ := scenario.NewParameter("text", "${secret:SHOP_PASSWORD}")
+	selector, _ := scenario.NewParameter("selector", "#password")
+	node, _ := scenario.NewNodeParameters("password", []scenario.Parameter{text, selector}, nil)
+	s.UpdateInputData(scenario.NewInputData([]scenario.NodeParameters{node}))
+
+	user, _ := scenario.NewParameter("user", map[string]any{"login": "bot", "token": "Bearer ${secret:API_TOKEN}"})
+	s.UpdateParameters(scenario.NewParameters([]scenario.ParameterItem{scenario.NewParameterItem(user, "string", nil)}, nil))
+	return s
+}
+
+func newSecret(t *testing.T, id, name, value string) *secret.Secret {
+	t.Helper()
+	secretID, _ := secret.NewSecretID(id)
+	s, err := secret.NewSecret(secretID, name, value, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func runStarted(runID string) run.RunStarted {
+	return run.RunStarted{
+		BaseEvent:  shared.NewBaseEvent(run.EventRunStarted, runID),
+		RunID:      runID,
+		ScenarioID: "1",
+	}
+}
+
+// startedRuns holds a running run of the login scenario that referenced secretNames when it started
+func startedRuns(t *testing.T, runID string, secretNames ...string) *stubRuns {
+	t.Helper()
+	id, _ := run.NewRunID(runID)
+	scenarioID, _ := scenario.NewScenarioID("1")
+	r, err := run.NewRun(id, scenarioID, "{}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	definition := loginScenario(t).Definition()
+	r.Definition = &definition
+	r.SecretNames = secretNames
+	r.ClearEvents()
+	return &stubRuns{runs: map[string]*run.Run{runID: r}}
+}
+
+func TestRunDispatcher_ResolvesSecretsInTheExecutionRequest(t *testing.T) {
+	broker := memory.NewBroker()
+	secrets := &stubSecrets{secrets: []*secret.Secret{
+		newSecret(t, "1", "SHOP_PASSWORD", "hunter2"),
+		newSecret(t, "2", "API_TOKEN", "t0k3n"),
+	}}
+	runs := startedRuns(t, "42", "SHOP_PASSWORD", "API_TOKEN")
+	dispatcher := messaging.NewRunDispatcher(runs, secrets, broker)
+
+	if err := dispatcher.Handle(context.Background(), runStarted("42")); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	published := broker.Messages(messaging.AgentRequestQueue)
+	if len(published) != 1 {
+		t.Fatalf("Published %d requests, want 1", len(published))
+	}
+	if published[0].ID != "42" {
+		t.Errorf("Message ID = %q, want the run ID", published[0].ID)
+	}
+
+	var message messages.ExecuteScenarioMessage
+	if err := json.Unmarshal(published[0].Body, &message); err != nil {
+		t.Fatalf("Request is not an ExecuteScenarioMessage: %v", err)
+	}
+	if message.RunID != "42" || message.ReplyQueue != messaging.ProgressQueue("42") {
+		t.Errorf("Request = run %q reply %q, want run 42 on its progress queue", message.RunID, message.ReplyQueue)
+	}
+	if len(message.Context.Blocks) != 2 || message.Context.Blocks[1].NodeType != messages.NodeTypeInputdata || len(message.Context.Edges) != 1 {
+		t.Errorf("Context = %+v, want the scenario graph", message.Context)
+	}
+
+	input := message.InputData.Parameters[0].Input
+	if input[0].Value != "hunter2" || input[1].Value != "#password" {
+		t.Errorf("Node input = %+v, want the password resolved and the selector untouched", input)
+	}
+	user := message.Parameters.Input[0].Parameter.Value.(map[string]any)
+	if user["token"] != "Bearer t0k3n" || user["login"] != "bot" {
+		t.Errorf("Scenario parameter = %+v, want nested references resolved", user)
+	}
+}
+
+func TestRunDispatcher_FailsRunsWithUnresolvableSecrets(t *testing.T) {
+	secrets := &stubSecrets{secrets: []*secret.Secret{
+		newSecret(t, "1", "SHOP_PASSWORD", "hunter2"),
+		newSecret(t, "2", "API_TOKEN", "t0k3n"),
+	}}
+	tests := []struct {
+		name         string
+		secrets      *stubSecrets
+		noDefinition bool
+		reason       string
+	}{
+		{"secret deleted after the run started", &stubSecrets{secrets: secrets.secrets[:1]}, false, "API_TOKEN"},
+		{"started without a scenario definition", secrets, true, "definition"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := memory.NewBroker()
+			runs := startedRuns(t, "42", "SHOP_PASSWORD", "API_TOKEN")
+			if tt.noDefinition {
+				runs.runs["42"].Definition = nil
+			}
+			dispatcher := messaging.NewRunDispatcher(runs, tt.secrets, broker)
+
+			// Retrying would not resolve them, the run fails instead of the handler
+			if err := dispatcher.Handle(context.Background(), runStarted("42")); err != nil {
+				t.Fatalf("Handle() error = %v, want the run failed", err)
+			}
+			r := runs.runs["42"]
+			if r.Status != shared.StatusFailed || !strings.Contains(r.FailureReason, tt.reason) {
+				t.Errorf("Run = %s %q, want FAILED naming %s", r.Status, r.FailureReason, tt.reason)
+			}
+			if len(r.Events) != 1 || r.Events[0].EventType() != run.EventRunFailed {
+				t.Errorf("Run events = %v, want RunFailed recorded with the run", r.Events)
+			}
+			if published := broker.Messages(messaging.AgentRequestQueue); len(published) != 0 {
+				t.Errorf("Published %d requests with unresolved references, want none", len(published))
+			}
+		})
+	}
+}
+
+// discardEventBus drops the events handlers publish
+type discardEventBus struct{}
+
+func (discardEventBus) Publish(event shared.DomainEvent) error      { return nil }
+func (discardEventBus) Subscribe(handler shared.EventHandler) error { return nil }
+
+func TestRunDispatcher_SendsTheScenarioAsItWasWhenTheRunStarted(t *testing.T) {
+	ctx := context.Background()
+	broker := memory.NewBroker()
+	secrets := &stubSecrets{secrets: []*secret.Secret{
+		newSecret(t, "1", "SHOP_PASSWORD", "hunter2"),
+		newSecret(t, "2", "API_TOKEN", "t0k3n"),
+	}}
+	scenarios := &stubScenarios{scenario: loginScenario(t)}
+	id, _ := run.NewRunID("42")
+	scenarioID, _ := scenario.NewScenarioID("1")
+	r, _ := run.NewRun(id, scenarioID, "{}")
+	runs := &stubRuns{runs: map[string]*run.Run{"42": r}}
+	if _, err := runcommand.NewStartRunCommandHandler(runs, scenarios, secrets, discardEventBus{}).Handle(ctx, runcommand.StartRunCommand{RunID: id}); err != nil {
+		t.Fatalf("StartRun() error = %v", err)
+	}
+
+	// The scenario is edited to reference a secret the run never checked
+	other, _ := scenario.NewParameter("text", "${secret:OTHER_PASSWORD}")
+	node, _ := scenario.NewNodeParameters("password", []scenario.Parameter{other}, nil)
+	scenarios.scenario.UpdateInputData(scenario.NewInputData([]scenario.NodeParameters{node}))
+	scenarios.scenario.UpdateParameters(scenario.NewParameters(nil, nil))
+
+	if err := messaging.NewRunDispatcher(runs, secrets, broker).Handle(ctx, runStarted("42")); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	if r := runs.runs["42"]; r.Status != shared.StatusRunning {
+		t.Fatalf("Run = %s %q, want it still running", r.Status, r.FailureReason)
+	}
+	published := broker.Messages(messaging.AgentRequestQueue)
+	if len(published) != 1 {
+		t.Fatalf("Published %d requests, want 1", len(published))
+	}
+	var message messages.ExecuteScenarioMessage
+	if err := json.Unmarshal(published[0].Body, &message); err != nil {
+		t.Fatalf("Request is not an ExecuteScenarioMessage: %v", err)
+	}
+	if input := message.InputData.Parameters[0].Input; input[0].Value != "hunter2" {
+		t.Errorf("Node input = %+v, want the parameters the run started with", input)
+	}
+	if len(message.Parameters.Input) != 1 {
+		t.Errorf("Scenario parameters = %+v, want the parameters the run started with", message.Parameters)
+	}
+}
@@ -0,0 +1,224 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"parrotflow/internal/ports"
+)
+
+// Broker is an in-process message broker for tests and single-binary deployments
+// Messages live in memory only; queues asserted through AssertQueue dead-letter
+// rejected messages like their RabbitMQ counterparts
+type Broker struct {
+	mu          sync.Mutex
+	queues      map[string]*queue
+	deadLetters []ports.DeadLetteredMessage
+	deadNotify  chan struct{}
+	retryDelay  time.Duration
+	now         func() time.Time
+
+	// Consumers run until stop is closed; Stop replaces it for later consumers
+	stop      chan struct{}
+	consumers sync.WaitGroup
+}
+
+type queue struct {
+	messages []ports.Message
+	asserted bool
+	notify   chan struct{} // Signalled when messages are added
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		queues:     make(map[string]*queue),
+		deadNotify: make(chan struct{}, 1),
+		retryDelay: time.Second,
+		now:        time.Now,
+		stop:       make(chan struct{}),
+	}
+}
+
+func (b *Broker) AssertQueue(ctx context.Context, name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.queue(name).asserted = true
+	return nil
+}
+
+func (b *Broker) Publish(ctx context.Context, name string, message ports.Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	q := b.queue(name)
+	q.messages = append(q.messages, message)
+	signal(q.notify)
+	return nil
+}
+
+// Consume hands messages of the queue to handler one at a time until Stop is called
+// or ctx is done; consumers of the same queue compete for its messages
+func (b *Broker) Consume(ctx context.Context, name string, handler ports.MessageHandler) error {
+	b.mu.Lock()
+	q := b.queue(name)
+	stop := b.stop
+	b.consumers.Add(1)
+	b.mu.Unlock()
+
+	go func() {
+		defer b.consumers.Done()
+		for {
+			message, ok := b.next(ctx, q, stop)
+			if !ok {
+				return
+			}
+			if err := handler(ctx, message); err != nil {
+				b.Reject(name, message, "rejected")
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the running consumers and waits for them to return
+func (b *Broker) Stop(ctx context.Context) error {
+	b.mu.Lock()
+	close(b.stop)
+	b.stop = make(chan struct{})
+	b.mu.Unlock()
+
+	stopped := make(chan struct{})
+	go func() {
+		b.consumers.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Ping always succeeds, the broker lives in the process
+func (b *Broker) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (b *Broker) Close() error {
+	return b.Stop(context.Background())
+}
+
+// Messages returns the messages waiting in a queue
+func (b *Broker) Messages(name string) []ports.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if q, ok := b.queues[name]; ok {
+		return append([]ports.Message(nil), q.messages...)
+	}
+	return nil
+}
+
+// Reject is what a consumer does with a message it cannot process: the message is
+// dead-lettered if its queue was asserted through AssertQueue and dropped otherwise
+func (b *Broker) Reject(name string, message ports.Message, reason string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if q, ok := b.queues[name]; !ok || !q.asserted {
+		return
+	}
+
+	count := 1
+	for _, d := range b.deadLetters {
+		if d.Queue == name && d.ID != "" && d.ID == message.ID {
+			count = d.Count + 1
+		}
+	}
+	b.deadLetters = append(b.deadLetters, ports.DeadLetteredMessage{
+		Message:        message,
+		Queue:          name,
+		Reason:         reason,
+		Count:          count,
+		DeadLetteredAt: b.now(),
+	})
+	signal(b.deadNotify)
+}
+
+// ConsumeDeadLetters hands dead letters to handler in the order they were rejected
+// A dead letter the handler fails on is retried after a delay
+func (b *Broker) ConsumeDeadLetters(ctx context.Context, handler func(context.Context, ports.DeadLetteredMessage) error) error {
+	for {
+		b.mu.Lock()
+		pending := len(b.deadLetters) > 0
+		var next ports.DeadLetteredMessage
+		if pending {
+			next = b.deadLetters[0]
+		}
+		b.mu.Unlock()
+
+		if !pending {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-b.deadNotify:
+			}
+			continue
+		}
+
+		if err := handler(ctx, next); err != nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(b.retryDelay):
+			}
+			continue
+		}
+
+		b.mu.Lock()
+		b.deadLetters = b.deadLetters[1:]
+		b.mu.Unlock()
+	}
+}
+
+// queue returns the named queue, creating it on first use; callers hold b.mu
+func (b *Broker) queue(name string) *queue {
+	q, ok := b.queues[name]
+	if !ok {
+		q = &queue{notify: make(chan struct{}, 1)}
+		b.queues[name] = q
+	}
+	return q
+}
+
+// next takes the first message off the queue, waiting for one to arrive
+func (b *Broker) next(ctx context.Context, q *queue, stop <-chan struct{}) (ports.Message, bool) {
+	for {
+		b.mu.Lock()
+		if len(q.messages) > 0 {
+			message := q.messages[0]
+			q.messages = q.messages[1:]
+			if len(q.messages) > 0 {
+				// Wake up the next competing consumer
+				signal(q.notify)
+			}
+			b.mu.Unlock()
+			return message, true
+		}
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ports.Message{}, false
+		case <-stop:
+			return ports.Message{}, false
+		case <-q.notify:
+		}
+	}
+}
+
+func signal(notify chan struct{}) {
+	select {
+	case notify <- struct{}{}:
+	default:
+	}
+}
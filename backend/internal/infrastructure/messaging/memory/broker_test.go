@@ -0,0 +1,16 @@
+package memory
+
+import (
+	"testing"
+
+	"parrotflow/internal/infrastructure/messaging/brokertest"
+	"parrotflow/internal/ports"
+)
+
+func TestBroker(t *testing.T) {
+	brokertest.Run(t, func(t *testing.T) ports.MessageBroker {
+		broker := NewBroker()
+		t.Cleanup(func() { broker.Close() })
+		return broker
+	})
+}
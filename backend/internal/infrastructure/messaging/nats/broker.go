@@ -0,0 +1,375 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"parrotflow/internal/infrastructure/messaging"
+	"parrotflow/internal/ports"
+
+	"github.com/nats-io/nats-server/v2/server"
+	gonats "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Headers carrying the message fields NATS has no place for, and why a message
+// was dead-lettered
+const (
+	headerMessageID   = "X-Message-Id"
+	headerContentType = "Content-Type"
+	headerDeathQueue  = "X-Death-Queue"
+	headerDeathReason = "X-Death-Reason"
+	headerDeathCount  = "X-Death-Count"
+)
+
+const (
+	queueSubjectPrefix = "queues."
+	deadLetterStream   = "DEAD_LETTERS"
+	workerConsumer     = "workers"   // Durable consumer shared by the consumers of a queue
+	collectorConsumer  = "collector" // Durable consumer of the dead-letter stream
+)
+
+var invalidStreamChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// Broker keeps every queue in a JetStream work-queue stream
+// JetStream has no dead-letter exchange, so the broker publishes rejected messages
+// to the dead-letter stream itself
+type Broker struct {
+	conn   *gonats.Conn
+	js     jetstream.JetStream
+	server *server.Server // Embedded server, nil when connected to an external one
+
+	mu       sync.Mutex
+	asserted map[string]bool
+	running  []jetstream.ConsumeContext
+
+	retryDelay time.Duration
+}
+
+// EmbeddedConfig configures the NATS server started inside the backend process
+type EmbeddedConfig struct {
+	StoreDir string // Where JetStream keeps the streams
+	Port     int    // Client port; 0 only accepts in-process connections
+}
+
+// NewEmbeddedBroker starts a NATS server with JetStream in-process and connects to it
+func NewEmbeddedBroker(config EmbeddedConfig) (*Broker, error) {
+	options := &server.Options{
+		ServerName: "parrotflow",
+		JetStream:  true,
+		StoreDir:   config.StoreDir,
+		Port:       config.Port,
+		DontListen: config.Port == 0,
+		NoSigs:     true,
+		NoLog:      true,
+	}
+	srv, err := server.NewServer(options)
+	if err != nil {
+		return nil, fmt.Errorf("create embedded NATS server: %w", err)
+	}
+	srv.Start()
+	if !srv.ReadyForConnections(10 * time.Second) {
+		srv.Shutdown()
+		return nil, errors.New("embedded NATS server did not start")
+	}
+
+	conn, err := gonats.Connect("", gonats.InProcessServer(srv))
+	if err != nil {
+		srv.Shutdown()
+		return nil, fmt.Errorf("connect to embedded NATS server: %w", err)
+	}
+	broker, err := newBroker(conn)
+	if err != nil {
+		conn.Close()
+		srv.Shutdown()
+		return nil, err
+	}
+	broker.server = srv
+	return broker, nil
+}
+
+// NewBroker connects to an external NATS server with JetStream enabled
+func NewBroker(url string) (*Broker, error) {
+	conn, err := gonats.Connect(url, gonats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("connect to broker: %w", err)
+	}
+	broker, err := newBroker(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return broker, nil
+}
+
+func newBroker(conn *gonats.Conn) (*Broker, error) {
+	js, err := jetstream.New(conn)
+	if err != nil {
+		return nil, err
+	}
+	return &Broker{
+		conn:       conn,
+		js:         js,
+		asserted:   make(map[string]bool),
+		retryDelay: time.Second,
+	}, nil
+}
+
+// AssertQueue creates the stream of the queue unless it exists
+func (b *Broker) AssertQueue(ctx context.Context, queue string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.assert(ctx, queue)
+}
+
+// Publish sends a message to a queue, creating its stream on first use
+func (b *Broker) Publish(ctx context.Context, queue string, message ports.Message) error {
+	b.mu.Lock()
+	err := b.assert(ctx, queue)
+	b.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	msg := gonats.NewMsg(queueSubject(queue))
+	for key, value := range message.Headers {
+		msg.Header.Set(key, value)
+	}
+	if message.ID != "" {
+		msg.Header.Set(headerMessageID, message.ID)
+	}
+	if message.ContentType != "" {
+		msg.Header.Set(headerContentType, message.ContentType)
+	}
+	msg.Data = message.Body
+
+	if _, err := b.js.PublishMsg(ctx, msg); err != nil {
+		return fmt.Errorf("publish to %s: %w", queue, err)
+	}
+	return nil
+}
+
+// Consume pulls messages of the queue through its durable consumer in the background
+// A message handler fails on is published to the dead-letter stream, then acknowledged
+func (b *Broker) Consume(ctx context.Context, queue string, handler ports.MessageHandler) error {
+	if err := b.AssertQueue(ctx, queue); err != nil {
+		return err
+	}
+	consumer, err := b.js.CreateOrUpdateConsumer(ctx, streamName(queue), jetstream.ConsumerConfig{
+		Durable:   workerConsumer,
+		AckPolicy: jetstream.AckExplicitPolicy,
+	})
+	if err != nil {
+		return fmt.Errorf("create consumer of %s: %w", queue, err)
+	}
+
+	running, err := consumer.Consume(func(msg jetstream.Msg) {
+		if err := handler(ctx, toMessage(msg)); err != nil {
+			b.reject(ctx, queue, msg, "rejected")
+			return
+		}
+		msg.Ack()
+	})
+	if err != nil {
+		return fmt.Errorf("consume %s: %w", queue, err)
+	}
+
+	b.mu.Lock()
+	b.running = append(b.running, running)
+	b.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			running.Stop()
+		case <-running.Closed():
+		}
+	}()
+	return nil
+}
+
+// Stop stops the running consumers and waits for them to return
+func (b *Broker) Stop(ctx context.Context) error {
+	b.mu.Lock()
+	running := b.running
+	b.running = nil
+	b.mu.Unlock()
+
+	for _, r := range running {
+		r.Stop()
+	}
+	for _, r := range running {
+		select {
+		case <-r.Closed():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// ConsumeDeadLetters hands messages of the dead-letter stream to handler until ctx is done
+// A message handler fails on is redelivered after a delay
+func (b *Broker) ConsumeDeadLetters(ctx context.Context, handler func(context.Context, ports.DeadLetteredMessage) error) error {
+	if err := b.assertDeadLetters(ctx); err != nil {
+		return err
+	}
+	consumer, err := b.js.CreateOrUpdateConsumer(ctx, deadLetterStream, jetstream.ConsumerConfig{
+		Durable:   collectorConsumer,
+		AckPolicy: jetstream.AckExplicitPolicy,
+	})
+	if err != nil {
+		return fmt.Errorf("create dead-letter consumer: %w", err)
+	}
+
+	running, err := consumer.Consume(func(msg jetstream.Msg) {
+		if err := handler(ctx, toDeadLetteredMessage(msg)); err != nil {
+			msg.NakWithDelay(b.retryDelay)
+			return
+		}
+		msg.Ack()
+	})
+	if err != nil {
+		return fmt.Errorf("consume %s: %w", messaging.DeadLetterQueue, err)
+	}
+	defer running.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-running.Closed():
+		return errors.New("dead-letter consumer closed")
+	}
+}
+
+// Ping checks the connection and that JetStream answers on it
+func (b *Broker) Ping(ctx context.Context) error {
+	if status := b.conn.Status(); status != gonats.CONNECTED {
+		return fmt.Errorf("nats connection is %s", status)
+	}
+	if _, err := b.js.AccountInfo(ctx); err != nil {
+		return fmt.Errorf("jetstream: %w", err)
+	}
+	return nil
+}
+
+// Close stops the consumers, closes the connection and shuts the embedded server down
+func (b *Broker) Close() error {
+	b.Stop(context.Background())
+	b.conn.Close()
+	if b.server != nil {
+		b.server.Shutdown()
+		b.server.WaitForShutdown()
+	}
+	return nil
+}
+
+// reject publishes the message to the dead-letter stream and acknowledges it
+// When that fails the message is left for redelivery
+func (b *Broker) reject(ctx context.Context, queue string, msg jetstream.Msg, reason string) {
+	if err := b.assertDeadLetters(ctx); err != nil {
+		msg.NakWithDelay(b.retryDelay)
+		return
+	}
+
+	dead := gonats.NewMsg(messaging.DeadLetterQueue)
+	for key, values := range msg.Headers() {
+		dead.Header[key] = values
+	}
+	dead.Header.Set(headerDeathQueue, queue)
+	dead.Header.Set(headerDeathReason, reason)
+	dead.Header.Set(headerDeathCount, "1")
+	dead.Data = msg.Data()
+
+	if _, err := b.js.PublishMsg(ctx, dead); err != nil {
+		msg.NakWithDelay(b.retryDelay)
+		return
+	}
+	msg.Ack()
+}
+
+// assert creates the stream of a queue; callers hold b.mu
+func (b *Broker) assert(ctx context.Context, queue string) error {
+	if b.asserted[queue] {
+		return nil
+	}
+	_, err := b.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:      streamName(queue),
+		Subjects:  []string{queueSubject(queue)},
+		Retention: jetstream.WorkQueuePolicy,
+		Storage:   jetstream.FileStorage,
+	})
+	if err != nil {
+		return fmt.Errorf("assert queue %s: %w", queue, err)
+	}
+	b.asserted[queue] = true
+	return nil
+}
+
+func (b *Broker) assertDeadLetters(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.asserted[messaging.DeadLetterQueue] {
+		return nil
+	}
+	_, err := b.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     deadLetterStream,
+		Subjects: []string{messaging.DeadLetterQueue},
+		Storage:  jetstream.FileStorage,
+	})
+	if err != nil {
+		return fmt.Errorf("assert dead-letter stream: %w", err)
+	}
+	b.asserted[messaging.DeadLetterQueue] = true
+	return nil
+}
+
+// streamName maps a queue to a valid stream name; queues differing only in
+// characters streams do not allow, like agent.requests and agent_requests, collide
+func streamName(queue string) string {
+	return "QUEUE_" + invalidStreamChars.ReplaceAllString(queue, "_")
+}
+
+func queueSubject(queue string) string {
+	return queueSubjectPrefix + queue
+}
+
+func toMessage(msg jetstream.Msg) ports.Message {
+	message := ports.Message{Headers: make(map[string]string), Body: msg.Data()}
+	for key := range msg.Headers() {
+		value := msg.Headers().Get(key)
+		switch key {
+		case headerMessageID:
+			message.ID = value
+		case headerContentType:
+			message.ContentType = value
+		case headerDeathQueue, headerDeathReason, headerDeathCount:
+		default:
+			message.Headers[key] = value
+		}
+	}
+	return message
+}
+
+func toDeadLetteredMessage(msg jetstream.Msg) ports.DeadLetteredMessage {
+	headers := msg.Headers()
+	message := ports.DeadLetteredMessage{
+		Message:        toMessage(msg),
+		Queue:          headers.Get(headerDeathQueue),
+		Reason:         headers.Get(headerDeathReason),
+		Count:          1,
+		DeadLetteredAt: time.Now(),
+	}
+	if count, err := strconv.Atoi(headers.Get(headerDeathCount)); err == nil {
+		message.Count = count
+	}
+	if metadata, err := msg.Metadata(); err == nil {
+		message.DeadLetteredAt = metadata.Timestamp
+	}
+	return message
+}
@@ -0,0 +1,19 @@
+package nats
+
+import (
+	"testing"
+
+	"parrotflow/internal/infrastructure/messaging/brokertest"
+	"parrotflow/internal/ports"
+)
+
+func TestEmbeddedBroker(t *testing.T) {
+	brokertest.Run(t, func(t *testing.T) ports.MessageBroker {
+		broker, err := NewEmbeddedBroker(EmbeddedConfig{StoreDir: t.TempDir()})
+		if err != nil {
+			t.Fatalf("NewEmbeddedBroker() error = %v", err)
+		}
+		t.Cleanup(func() { broker.Close() })
+		return broker
+	})
+}
@@ -0,0 +1,346 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"parrotflow/internal/infrastructure/messaging"
+	"parrotflow/internal/ports"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Broker talks to RabbitMQ over AMQP 0.9.1
+// It connects lazily and reconnects on the next call after the connection dropped
+type Broker struct {
+	url            string
+	prefetch       int
+	reconnectDelay time.Duration
+
+	mu   sync.Mutex
+	conn *amqp.Connection
+	ch   *amqp.Channel // Shared by declarations and publishing
+
+	// Consumers run until stop is closed; Stop replaces it for later consumers
+	stop      chan struct{}
+	consumers sync.WaitGroup
+}
+
+func NewBroker(url string) *Broker {
+	return &Broker{
+		url:            url,
+		prefetch:       10,
+		reconnectDelay: 5 * time.Second,
+		stop:           make(chan struct{}),
+	}
+}
+
+// QueueArguments routes rejected messages of a queue to the dead-letter exchange
+// The agent declares its queues with the same arguments
+func QueueArguments() amqp.Table {
+	return amqp.Table{"x-dead-letter-exchange": messaging.DeadLetterExchange}
+}
+
+func (b *Broker) AssertQueue(ctx context.Context, queue string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch, err := b.channel()
+	if err != nil {
+		return err
+	}
+	if _, err := ch.QueueDeclare(queue, true, false, false, false, QueueArguments()); err != nil {
+		return fmt.Errorf("declare queue %s: %w", queue, err)
+	}
+	return nil
+}
+
+func (b *Broker) Publish(ctx context.Context, queue string, message ports.Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch, err := b.channel()
+	if err != nil {
+		return err
+	}
+
+	headers := make(amqp.Table, len(message.Headers))
+	for key, value := range message.Headers {
+		headers[key] = value
+	}
+	return ch.PublishWithContext(ctx, "", queue, false, false, amqp.Publishing{
+		MessageId:    message.ID,
+		ContentType:  message.ContentType,
+		Headers:      headers,
+		Body:         message.Body,
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+	})
+}
+
+// Consume consumes the queue on its own channel in the background
+// A message is acknowledged once handler accepts it and rejected without requeueing
+// otherwise, which dead-letters it; the consumer reconnects when the connection drops
+func (b *Broker) Consume(ctx context.Context, queue string, handler ports.MessageHandler) error {
+	ch, deliveries, err := b.consume(queue)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	stop := b.stop
+	b.consumers.Add(1)
+	b.mu.Unlock()
+
+	go func() {
+		defer b.consumers.Done()
+		for {
+			b.handle(ctx, stop, deliveries, handler)
+			ch.Close()
+			if ctx.Err() != nil || isClosed(stop) {
+				return
+			}
+
+			// The broker closed the consumer; retry until it is back or we are stopped
+			for {
+				slog.WarnContext(ctx, "Consumer lost its channel, reconnecting", "queue", queue, "delay", b.reconnectDelay)
+				select {
+				case <-ctx.Done():
+					return
+				case <-stop:
+					return
+				case <-time.After(b.reconnectDelay):
+				}
+				if ch, deliveries, err = b.consume(queue); err == nil {
+					break
+				}
+				slog.ErrorContext(ctx, "Error consuming queue", "queue", queue, "error", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// handle processes deliveries until the channel closes, ctx is done or stop is closed
+func (b *Broker) handle(ctx context.Context, stop <-chan struct{}, deliveries <-chan amqp.Delivery, handler ports.MessageHandler) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case delivery, ok := <-deliveries:
+			if !ok {
+				return
+			}
+			if err := handler(ctx, toMessage(delivery)); err != nil {
+				delivery.Nack(false, false)
+				continue
+			}
+			delivery.Ack(false)
+		}
+	}
+}
+
+// Stop stops the running consumers and waits for them to return
+func (b *Broker) Stop(ctx context.Context) error {
+	b.mu.Lock()
+	close(b.stop)
+	b.stop = make(chan struct{})
+	b.mu.Unlock()
+
+	stopped := make(chan struct{})
+	go func() {
+		b.consumers.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ConsumeDeadLetters consumes the dead-letter queue on its own channel
+// A message is acknowledged once handler accepts it, and requeued otherwise
+func (b *Broker) ConsumeDeadLetters(ctx context.Context, handler func(context.Context, ports.DeadLetteredMessage) error) error {
+	ch, deliveries, err := b.consume(messaging.DeadLetterQueue)
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case delivery, ok := <-deliveries:
+			if !ok {
+				return errors.New("dead-letter consumer closed by the broker")
+			}
+			if err := handler(ctx, toDeadLetteredMessage(delivery)); err != nil {
+				// Leave it in the queue; pause so a failing store is not hammered
+				delivery.Nack(false, true)
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(time.Second):
+				}
+				continue
+			}
+			delivery.Ack(false)
+		}
+	}
+}
+
+// Close stops the consumers and closes the connection
+// Ping opens the connection and channel unless they are open
+func (b *Broker) Ping(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, err := b.channel()
+	return err
+}
+
+func (b *Broker) Close() error {
+	b.Stop(context.Background())
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.conn == nil || b.conn.IsClosed() {
+		return nil
+	}
+	return b.conn.Close()
+}
+
+// consume opens a channel of its own for a consumer of the queue
+func (b *Broker) consume(queue string) (*amqp.Channel, <-chan amqp.Delivery, error) {
+	b.mu.Lock()
+	_, err := b.channel()
+	var ch *amqp.Channel
+	if err == nil {
+		ch, err = b.conn.Channel()
+	}
+	b.mu.Unlock()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := ch.Qos(b.prefetch, 0, false); err != nil {
+		ch.Close()
+		return nil, nil, err
+	}
+	deliveries, err := ch.Consume(queue, "", false, false, false, false, nil)
+	if err != nil {
+		ch.Close()
+		return nil, nil, fmt.Errorf("consume %s: %w", queue, err)
+	}
+	return ch, deliveries, nil
+}
+
+// channel returns the shared channel, (re)connecting and declaring the
+// dead-letter exchange and queue when needed; callers hold b.mu
+func (b *Broker) channel() (*amqp.Channel, error) {
+	if b.conn != nil && !b.conn.IsClosed() && b.ch != nil && !b.ch.IsClosed() {
+		return b.ch, nil
+	}
+
+	if b.conn == nil || b.conn.IsClosed() {
+		conn, err := amqp.Dial(b.url)
+		if err != nil {
+			return nil, fmt.Errorf("connect to broker: %w", err)
+		}
+		b.conn = conn
+	}
+
+	ch, err := b.conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+	if err := declareDeadLetterTopology(ch); err != nil {
+		ch.Close()
+		return nil, err
+	}
+	b.ch = ch
+	return ch, nil
+}
+
+func declareDeadLetterTopology(ch *amqp.Channel) error {
+	if err := ch.ExchangeDeclare(messaging.DeadLetterExchange, amqp.ExchangeFanout, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare exchange %s: %w", messaging.DeadLetterExchange, err)
+	}
+	if _, err := ch.QueueDeclare(messaging.DeadLetterQueue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare queue %s: %w", messaging.DeadLetterQueue, err)
+	}
+	return ch.QueueBind(messaging.DeadLetterQueue, "", messaging.DeadLetterExchange, false, nil)
+}
+
+func toMessage(delivery amqp.Delivery) ports.Message {
+	headers := make(map[string]string, len(delivery.Headers))
+	for key, value := range delivery.Headers {
+		headers[key] = fmt.Sprint(value)
+	}
+	return ports.Message{
+		ID:          delivery.MessageId,
+		ContentType: delivery.ContentType,
+		Headers:     headers,
+		Body:        delivery.Body,
+	}
+}
+
+func isClosed(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// toDeadLetteredMessage reads where and why a message was dead-lettered from the
+// x-death header RabbitMQ adds; its first entry is the most recent death
+func toDeadLetteredMessage(delivery amqp.Delivery) ports.DeadLetteredMessage {
+	message := ports.DeadLetteredMessage{
+		Message: ports.Message{
+			ID:          delivery.MessageId,
+			ContentType: delivery.ContentType,
+			Headers:     make(map[string]string),
+			Body:        delivery.Body,
+		},
+		Queue:          messaging.DeadLetterQueue,
+		DeadLetteredAt: time.Now(),
+	}
+
+	for key, value := range delivery.Headers {
+		switch key {
+		case "x-death", "x-first-death-queue", "x-first-death-reason", "x-first-death-exchange",
+			"x-last-death-queue", "x-last-death-reason", "x-last-death-exchange":
+		default:
+			message.Headers[key] = fmt.Sprint(value)
+		}
+	}
+
+	deaths, _ := delivery.Headers["x-death"].([]interface{})
+	if len(deaths) == 0 {
+		return message
+	}
+	death, _ := deaths[0].(amqp.Table)
+	if queue, ok := death["queue"].(string); ok && queue != "" {
+		message.Queue = queue
+	}
+	if reason, ok := death["reason"].(string); ok {
+		message.Reason = reason
+	}
+	if count, ok := death["count"].(int64); ok {
+		message.Count = int(count)
+	}
+	if at, ok := death["time"].(time.Time); ok {
+		message.DeadLetteredAt = at
+	}
+	return message
+}
@@ -0,0 +1,46 @@
+package messaging
+
+import "fmt"
+
+// Broker topology shared with the agent, which declares the same queues with
+// the same arguments
+const (
+	// DeadLetterExchange receives every message rejected from a declared queue
+	DeadLetterExchange = "parrotflow.dlx"
+	// DeadLetterQueue is bound to DeadLetterExchange and drained by the collector
+	DeadLetterQueue = "parrotflow.dead-letters"
+	// AgentRequestQueue carries execution requests to agents
+	AgentRequestQueue = "agent.requests"
+)
+
+// ProgressQueue is the queue an agent reports the progress of a run on
+func ProgressQueue(runID string) string {
+	return fmt.Sprintf("agent.progress.%s", runID)
+}
+
+// Brokers the backend can run on
+const (
+	BrokerMemory   = "memory"   // In-process, for tests and single-binary deployments
+	BrokerRabbitMQ = "rabbitmq" // External RabbitMQ, the broker agents use
+	BrokerNATS     = "nats"     // NATS JetStream, embedded unless a URL is set
+)
+
+// Config selects the broker and the queues dead letters are collected for
+type Config struct {
+	Broker    string
+	BrokerURL string // Required for RabbitMQ; for NATS an empty URL starts an embedded server
+
+	// Embedded NATS server
+	NATSStoreDir string
+	NATSPort     int // 0 only accepts in-process connections
+
+	Queues []string
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Broker:       BrokerMemory,
+		NATSStoreDir: "data/nats",
+		Queues:       []string{AgentRequestQueue},
+	}
+}
@@ -0,0 +1,67 @@
+package messaging
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"parrotflow/internal/infrastructure/tracing"
+	"parrotflow/internal/ports"
+)
+
+// TracedBroker puts a span around every publish and handled message, and carries
+// the trace context in the message headers so the spans of the agent join the
+// trace of the request that sent it
+type TracedBroker struct {
+	ports.MessageBroker
+}
+
+// NewTracedBroker wraps a broker so traces cross it
+func NewTracedBroker(broker ports.MessageBroker) *TracedBroker {
+	return &TracedBroker{MessageBroker: broker}
+}
+
+// Publish sends the message with the trace context of its send span
+func (b *TracedBroker) Publish(ctx context.Context, queue string, message ports.Message) error {
+	ctx, span := tracing.Tracer().Start(ctx, "send "+queue,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(messageAttributes(queue, message, semconv.MessagingOperationTypeSend)...),
+	)
+	defer span.End()
+
+	headers := make(map[string]string, len(message.Headers)+2)
+	for key, value := range message.Headers {
+		headers[key] = value
+	}
+	tracing.Inject(ctx, headers)
+	message.Headers = headers
+
+	err := b.MessageBroker.Publish(ctx, queue, message)
+	tracing.RecordError(span, err)
+	return err
+}
+
+// Consume handles each message in a span continuing the trace of its sender
+func (b *TracedBroker) Consume(ctx context.Context, queue string, handler ports.MessageHandler) error {
+	return b.MessageBroker.Consume(ctx, queue, func(ctx context.Context, message ports.Message) error {
+		ctx, span := tracing.Tracer().Start(tracing.Extract(ctx, message.Headers), "process "+queue,
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(messageAttributes(queue, message, semconv.MessagingOperationTypeProcess)...),
+		)
+		defer span.End()
+
+		err := handler(ctx, message)
+		tracing.RecordError(span, err)
+		return err
+	})
+}
+
+func messageAttributes(queue string, message ports.Message, operation attribute.KeyValue) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		operation,
+		semconv.MessagingDestinationName(queue),
+		semconv.MessagingMessageID(message.ID),
+	}
+}
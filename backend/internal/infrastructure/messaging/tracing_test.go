@@ -0,0 +1,85 @@
+package messaging_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"parrotflow/internal/infrastructure/messaging"
+	"parrotflow/internal/infrastructure/messaging/memory"
+	"parrotflow/internal/infrastructure/tracing"
+	"parrotflow/internal/ports"
+)
+
+func TestTracedBroker_ConsumerJoinsTheTraceOfThePublisher(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewProvider(exporter, tracing.DefaultConfig())
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+		_ = provider.Shutdown(context.Background())
+	})
+
+	broker := messaging.NewTracedBroker(memory.NewBroker())
+	if err := broker.AssertQueue(ctx, "traced"); err != nil {
+		t.Fatalf("AssertQueue() error = %v", err)
+	}
+
+	requestCtx, request := tracing.Tracer().Start(ctx, "request")
+	if err := broker.Publish(requestCtx, "traced", ports.Message{ID: "message-1"}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	request.End()
+
+	received := make(chan ports.Message, 1)
+	err := broker.Consume(ctx, "traced", func(ctx context.Context, message ports.Message) error {
+		if !tracing.IsTraced(ctx) {
+			t.Error("Handler context is not traced")
+		}
+		received <- message
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+
+	select {
+	case message := <-received:
+		if message.Headers["traceparent"] == "" {
+			t.Error("Message was published without a traceparent header")
+		}
+	case <-ctx.Done():
+		t.Fatal("Message was not delivered")
+	}
+	// The consumer span ends once the handler has returned
+	for len(exporter.GetSpans()) < 3 && ctx.Err() == nil {
+		if err := provider.ForceFlush(ctx); err != nil {
+			t.Fatalf("ForceFlush() error = %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	traceID := request.SpanContext().TraceID()
+	names := map[string]bool{}
+	for _, span := range exporter.GetSpans() {
+		names[span.Name] = true
+		if span.SpanContext.TraceID() != traceID {
+			t.Errorf("Span %q is in trace %s, want %s", span.Name, span.SpanContext.TraceID(), traceID)
+		}
+	}
+	for _, name := range []string{"request", "send traced", "process traced"} {
+		if !names[name] {
+			t.Errorf("Span %q was not exported", name)
+		}
+	}
+}
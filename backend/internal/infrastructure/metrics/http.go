@@ -0,0 +1,23 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Middleware times every API request under the OperationID of its huma operation
+// It must be added with UseMiddleware before the operations are registered
+func (m *Metrics) Middleware(ctx huma.Context, next func(huma.Context)) {
+	start := time.Now()
+	next(ctx)
+
+	status := ctx.Status()
+	if status == 0 {
+		status = 200
+	}
+	m.httpDuration.
+		WithLabelValues(ctx.Operation().OperationID, ctx.Method(), strconv.Itoa(status)).
+		Observe(time.Since(start).Seconds())
+}
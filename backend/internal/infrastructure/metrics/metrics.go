@@ -0,0 +1,108 @@
+// Package metrics exposes Prometheus metrics of runs, agents, proxies and the HTTP API
+//
+// Counters and histograms are fed by event bus subscribers, so domain code knows
+// nothing about metrics, and by proxy health checks, which are not events; gauges describing current state are read from the
+// repositories on every scrape, so they are right after a restart
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"parrotflow/internal/domain/agent"
+	"parrotflow/internal/domain/run"
+)
+
+const namespace = "parrotflow"
+
+// Metrics owns the registry served on /metrics
+type Metrics struct {
+	registry *prometheus.Registry
+
+	runsCreated  *prometheus.CounterVec
+	runsFinished *prometheus.CounterVec
+	runDuration  *prometheus.HistogramVec
+
+	proxyChecks  *prometheus.CounterVec
+	proxyLatency *prometheus.HistogramVec
+
+	httpDuration *prometheus.HistogramVec
+}
+
+// New creates the metrics and registers them with a registry of their own
+func New(runs run.Repository, agents agent.Repository) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		runsCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "runs_created_total",
+			Help:      "Runs created, by scenario.",
+		}, []string{"scenario_id"}),
+		runsFinished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "runs_finished_total",
+			Help:      "Runs that completed, failed or were cancelled, by scenario and final status.",
+		}, []string{"scenario_id", "status"}),
+		runDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "run_duration_seconds",
+			Help:      "Time from the start of a run until it completed or failed, by scenario and final status.",
+			Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600},
+		}, []string{"scenario_id", "status"}),
+		proxyChecks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "proxy_checks_total",
+			Help:      "Proxy connections recorded, by proxy and result (success or failure).",
+		}, []string{"proxy_id", "result"}),
+		proxyLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "proxy_latency_seconds",
+			Help:      "Latency of successful proxy connections, by proxy.",
+			Buckets:   []float64{.025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"proxy_id"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of API requests, by operation, method and response status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "method", "status"}),
+	}
+
+	m.registry.MustRegister(
+		m.runsCreated,
+		m.runsFinished,
+		m.runDuration,
+		m.proxyChecks,
+		m.proxyLatency,
+		m.httpDuration,
+		newStateCollector(runs, agents),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveHealthCheck counts a proxy health check and times it when it succeeded
+// It makes Metrics the proxy.HealthObserver
+func (m *Metrics) ObserveHealthCheck(proxyID string, success bool, latency time.Duration) {
+	if !success {
+		m.proxyChecks.WithLabelValues(proxyID, "failure").Inc()
+		return
+	}
+	m.proxyChecks.WithLabelValues(proxyID, "success").Inc()
+	m.proxyLatency.WithLabelValues(proxyID).Observe(latency.Seconds())
+}
+
+// Registry returns the registry the metrics are registered with
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
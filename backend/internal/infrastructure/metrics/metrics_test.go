@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"parrotflow/internal/domain/agent"
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/shared"
+)
+
+// Only the queries the state collector makes are implemented
+type stubRuns struct {
+	run.Repository
+	pending int64
+}
+
+func (r stubRuns) FindAll(ctx context.Context, criteria run.SearchCriteria) (shared.Page[*run.Run], error) {
+	return shared.Page[*run.Run]{Total: r.pending}, nil
+}
+
+type stubAgents struct {
+	agent.Repository
+	agents []*agent.Agent
+}
+
+func (r stubAgents) FindAll(ctx context.Context) ([]*agent.Agent, error) {
+	return r.agents, nil
+}
+
+func testAgent(status agent.AgentStatus, running, slots int) *agent.Agent {
+	a := &agent.Agent{Status: status, CurrentRunCount: running}
+	a.Capabilities.ResourceLimits.MaxConcurrentRuns = slots
+	return a
+}
+
+func TestRecorders_CountEventsAndHealthChecks(t *testing.T) {
+	m := New(stubRuns{}, stubAgents{})
+	runs := NewRunRecorder(m)
+
+	started := time.Now().Add(-90 * time.Second)
+	for _, event := range []shared.DomainEvent{
+		run.RunCreated{ScenarioID: "s1"},
+		run.RunCreated{ScenarioID: "s1"},
+		run.RunCompleted{ScenarioID: "s1", StartedAt: started, FinishedAt: started.Add(90 * time.Second)},
+		run.RunFailed{ScenarioID: "s1", StartedAt: started, FailedAt: started.Add(time.Second)},
+		run.RunCancelled{ScenarioID: "s1", CancelledAt: started},
+	} {
+		runs.Handle(context.Background(), event)
+	}
+	m.ObserveHealthCheck("p1", true, 120*time.Millisecond)
+	m.ObserveHealthCheck("p1", false, 0)
+	m.ObserveHealthCheck("p1", false, 0)
+
+	if got := testutil.ToFloat64(m.runsCreated.WithLabelValues("s1")); got != 2 {
+		t.Errorf("runs_created_total = %v, want 2", got)
+	}
+	for _, status := range []string{"COMPLETED", "FAILED", "CANCELLED"} {
+		if got := testutil.ToFloat64(m.runsFinished.WithLabelValues("s1", status)); got != 1 {
+			t.Errorf("runs_finished_total{status=%s} = %v, want 1", status, got)
+		}
+	}
+	// Cancelled runs are counted but not timed
+	if got := testutil.CollectAndCount(m.runDuration); got != 2 {
+		t.Errorf("run_duration_seconds has %d series, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.proxyChecks.WithLabelValues("p1", "failure")); got != 2 {
+		t.Errorf("proxy_checks_total{result=failure} = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.proxyChecks.WithLabelValues("p1", "success")); got != 1 {
+		t.Errorf("proxy_checks_total{result=success} = %v, want 1", got)
+	}
+}
+
+func TestStateCollector_ReadsRepositories(t *testing.T) {
+	collector := newStateCollector(stubRuns{pending: 3}, stubAgents{agents: []*agent.Agent{
+		testAgent(agent.AgentStatusBusy, 2, 2),
+		testAgent(agent.AgentStatusIdle, 0, 2),
+		testAgent(agent.AgentStatusOffline, 0, 4), // Not counted towards capacity
+	}})
+
+	expected := `
+# HELP parrotflow_agent_capacity_utilization Share of the concurrent run slots of connected agents that are in use, from 0 to 1.
+# TYPE parrotflow_agent_capacity_utilization gauge
+parrotflow_agent_capacity_utilization 0.5
+# HELP parrotflow_agents Registered agents, by status.
+# TYPE parrotflow_agents gauge
+parrotflow_agents{status="busy"} 1
+parrotflow_agents{status="disconnected"} 0
+parrotflow_agents{status="idle"} 1
+parrotflow_agents{status="offline"} 1
+parrotflow_agents{status="online"} 0
+# HELP parrotflow_runs_pending Runs waiting for an agent.
+# TYPE parrotflow_runs_pending gauge
+parrotflow_runs_pending 3
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"parrotflow/internal/domain/agent"
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/shared"
+)
+
+// scrapeTimeout bounds the repository queries of one scrape
+const scrapeTimeout = 5 * time.Second
+
+var (
+	runsPendingDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "runs_pending"),
+		"Runs waiting for an agent.",
+		nil, nil,
+	)
+	agentsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "agents"),
+		"Registered agents, by status.",
+		[]string{"status"}, nil,
+	)
+	agentUtilizationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "agent_capacity_utilization"),
+		"Share of the concurrent run slots of connected agents that are in use, from 0 to 1.",
+		nil, nil,
+	)
+)
+
+// agentStatuses are reported even without agents, so the series do not come and go
+var agentStatuses = []agent.AgentStatus{
+	agent.AgentStatusOnline,
+	agent.AgentStatusIdle,
+	agent.AgentStatusBusy,
+	agent.AgentStatusOffline,
+	agent.AgentStatusDisconnected,
+}
+
+// stateCollector reads the gauges describing current state from the repositories
+type stateCollector struct {
+	runs   run.Repository
+	agents agent.Repository
+}
+
+func newStateCollector(runs run.Repository, agents agent.Repository) *stateCollector {
+	return &stateCollector{runs: runs, agents: agents}
+}
+
+func (c *stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- runsPendingDesc
+	ch <- agentsDesc
+	ch <- agentUtilizationDesc
+}
+
+func (c *stateCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	c.collectRuns(ctx, ch)
+	c.collectAgents(ctx, ch)
+}
+
+func (c *stateCollector) collectRuns(ctx context.Context, ch chan<- prometheus.Metric) {
+	criteria := run.NewSearchCriteria().WithStatus(shared.StatusPending.String()).WithPagination(1, 0)
+	pending, err := c.runs.FindAll(ctx, criteria)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(runsPendingDesc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(runsPendingDesc, prometheus.GaugeValue, float64(pending.Total))
+}
+
+func (c *stateCollector) collectAgents(ctx context.Context, ch chan<- prometheus.Metric) {
+	agents, err := c.agents.FindAll(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(agentsDesc, err)
+		ch <- prometheus.NewInvalidMetric(agentUtilizationDesc, err)
+		return
+	}
+
+	counts := make(map[string]int, len(agentStatuses))
+	for _, status := range agentStatuses {
+		counts[status.String()] = 0
+	}
+	var used, capacity int
+	for _, a := range agents {
+		counts[a.Status.String()]++
+		if a.Status == agent.AgentStatusOffline || a.Status == agent.AgentStatusDisconnected {
+			continue
+		}
+		used += a.CurrentRunCount
+		capacity += a.Capabilities.ResourceLimits.MaxConcurrentRuns
+	}
+
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(agentsDesc, prometheus.GaugeValue, float64(count), status)
+	}
+	utilization := 0.0
+	if capacity > 0 {
+		utilization = float64(used) / float64(capacity)
+	}
+	ch <- prometheus.MustNewConstMetric(agentUtilizationDesc, prometheus.GaugeValue, utilization)
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/shared"
+	"time"
+)
+
+// RunRecorder counts runs and times them from their events
+type RunRecorder struct {
+	metrics *Metrics
+}
+
+func NewRunRecorder(metrics *Metrics) *RunRecorder {
+	return &RunRecorder{metrics: metrics}
+}
+
+// Handle records one run event
+func (h *RunRecorder) Handle(ctx context.Context, event shared.DomainEvent) error {
+	switch e := event.(type) {
+	case run.RunCreated:
+		h.metrics.runsCreated.WithLabelValues(e.ScenarioID).Inc()
+	case run.RunCompleted:
+		h.finished(e.ScenarioID, shared.StatusCompleted.String(), e.StartedAt, e.FinishedAt)
+	case run.RunFailed:
+		h.finished(e.ScenarioID, shared.StatusFailed.String(), e.StartedAt, e.FailedAt)
+	case run.RunCancelled:
+		h.finished(e.ScenarioID, shared.StatusCancelled.String(), time.Time{}, e.CancelledAt)
+	}
+	return nil
+}
+
+// finished counts a finished run and times it when it had started
+// Events recorded before runs carried their start time are only counted
+func (h *RunRecorder) finished(scenarioID, status string, startedAt, finishedAt time.Time) {
+	h.metrics.runsFinished.WithLabelValues(scenarioID, status).Inc()
+	if !startedAt.IsZero() && !finishedAt.Before(startedAt) {
+		h.metrics.runDuration.WithLabelValues(scenarioID, status).Observe(finishedAt.Sub(startedAt).Seconds())
+	}
+}
+
+func (h *RunRecorder) CanHandle(eventType string) bool {
+	switch eventType {
+	case run.EventRunCreated, run.EventRunCompleted, run.EventRunFailed, run.EventRunCancelled:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package outbox
+
+import (
+	"context"
+
+	"parrotflow/internal/domain/shared"
+)
+
+// EventBus is the shared.EventBus handed to command handlers
+// Publishing only makes sure the event is in the outbox and wakes the relay;
+// subscribers receive events from the relay through the dispatcher bus
+type EventBus struct {
+	store      Store
+	relay      *Relay
+	dispatcher shared.EventBus
+}
+
+func NewEventBus(store Store, relay *Relay, dispatcher shared.EventBus) *EventBus {
+	return &EventBus{
+		store:      store,
+		relay:      relay,
+		dispatcher: dispatcher,
+	}
+}
+
+// Publish is idempotent for events already written together with their aggregate
+func (b *EventBus) Publish(event shared.DomainEvent) error {
+	if err := b.store.Append(context.Background(), event); err != nil {
+		return err
+	}
+	b.relay.Notify()
+	return nil
+}
+
+func (b *EventBus) Subscribe(handler shared.EventHandler) error {
+	return b.dispatcher.Subscribe(handler)
+}
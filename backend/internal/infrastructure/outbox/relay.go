@@ -0,0 +1,254 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/tracing"
+	"parrotflow/internal/models"
+	"parrotflow/internal/ports"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Store is the persistence the relay needs from the outbox table
+type Store interface {
+	Append(ctx context.Context, events ...shared.DomainEvent) error
+	FetchPending(ctx context.Context, limit int, now time.Time) ([]models.OutboxEvent, error)
+	MarkDelivered(ctx context.Context, id uint64, deliveredAt time.Time) error
+	MarkSinkDelivered(ctx context.Context, id uint64, sinks []string) error
+	MarkFailed(ctx context.Context, id uint64, status string, attempts int, nextAttemptAt time.Time, lastError string) error
+}
+
+// Sink is a destination the relay delivers events to
+type Sink interface {
+	// Name identifies the sink in the outbox records, it must not change between releases
+	Name() string
+	Deliver(ctx context.Context, event shared.DomainEvent) error
+}
+
+// ContextPublisher is an in-process event bus whose handlers continue the trace
+// events are published in
+type ContextPublisher interface {
+	PublishContext(ctx context.Context, event shared.DomainEvent) error
+}
+
+// EventBusSink delivers events to the in-process event bus
+type EventBusSink struct {
+	name string
+	bus  ContextPublisher
+}
+
+func NewEventBusSink(name string, bus ContextPublisher) *EventBusSink {
+	return &EventBusSink{name: name, bus: bus}
+}
+
+func (s *EventBusSink) Name() string {
+	return s.name
+}
+
+// Deliver hands the bus the relay's context, so handlers continue the trace of the
+// request that saved the event
+func (s *EventBusSink) Deliver(ctx context.Context, event shared.DomainEvent) error {
+	return s.bus.PublishContext(ctx, event)
+}
+
+type sequenceKey struct{}
+
+// ContextWithSequence returns a context carrying the event log sequence of the event being delivered
+func ContextWithSequence(ctx context.Context, sequence uint64) context.Context {
+	return context.WithValue(ctx, sequenceKey{}, sequence)
+}
+
+// SequenceFromContext returns the event log sequence of the event a sink is handling,
+// 0 outside of a relay delivery
+func SequenceFromContext(ctx context.Context) uint64 {
+	sequence, _ := ctx.Value(sequenceKey{}).(uint64)
+	return sequence
+}
+
+// RelayConfig controls polling and retry behaviour of the relay
+type RelayConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+}
+
+func DefaultRelayConfig() RelayConfig {
+	return RelayConfig{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		MaxAttempts:  10,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   5 * time.Minute,
+	}
+}
+
+// Relay moves pending outbox events to the registered sinks
+// Delivery is at-least-once: an event is retried with exponential backoff
+// until every sink accepts it or MaxAttempts is reached. Sinks that accepted it
+// are recorded and skipped by the retries, so one failing sink does not make the
+// others receive the event again
+type Relay struct {
+	store    Store
+	registry *shared.EventRegistry
+	sinks    []Sink
+	config   RelayConfig
+	notify   chan struct{}
+	now      func() time.Time
+}
+
+func NewRelay(store Store, registry *shared.EventRegistry, config RelayConfig, sinks ...Sink) *Relay {
+	return &Relay{
+		store:    store,
+		registry: registry,
+		sinks:    sinks,
+		config:   config,
+		notify:   make(chan struct{}, 1),
+		now:      time.Now,
+	}
+}
+
+// AddSink registers an additional delivery target, e.g. an external broker
+// Must be called before Run
+func (r *Relay) AddSink(sink Sink) {
+	r.sinks = append(r.sinks, sink)
+}
+
+// Notify wakes the relay up without waiting for the next poll
+func (r *Relay) Notify() {
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run polls the outbox until the context is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.ProcessPending(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Error relaying outbox events", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.notify:
+		}
+	}
+}
+
+// ProcessPending delivers one batch of due events and returns how many were delivered
+func (r *Relay) ProcessPending(ctx context.Context) (int, error) {
+	pending, err := r.store.FetchPending(ctx, r.config.BatchSize, r.now())
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for i := range pending {
+		if ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+
+		record := &pending[i]
+		if err := r.deliver(ctx, record); err != nil {
+			if markErr := r.markFailed(ctx, record, err); markErr != nil {
+				return delivered, markErr
+			}
+			continue
+		}
+
+		if err := r.store.MarkDelivered(ctx, record.ID, r.now()); err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+
+	return delivered, nil
+}
+
+func (r *Relay) deliver(ctx context.Context, record *models.OutboxEvent) (err error) {
+	event, err := r.registry.Decode(ports.OutboxPersistenceToEnvelope(record))
+	if err != nil {
+		return err
+	}
+
+	// Delivery continues the trace of the request that saved the event
+	ctx = tracing.ContextWithTraceParent(ctx, record.TraceParent)
+	if tracing.IsTraced(ctx) {
+		var span trace.Span
+		ctx, span = tracing.Tracer().Start(ctx, "relay "+record.EventType)
+		defer span.End()
+		defer func() { tracing.RecordError(span, err) }()
+	}
+
+	delivered, err := ports.OutboxDeliveredSinks(record)
+	if err != nil {
+		return err
+	}
+
+	// Whatever the sinks do happens because of the event
+	ctx = shared.CausedBy(ctx, event)
+	ctx = ContextWithSequence(ctx, record.Sequence)
+	for _, sink := range r.sinks {
+		if slices.Contains(delivered, sink.Name()) {
+			continue
+		}
+		if err = sink.Deliver(ctx, event); err != nil {
+			return err
+		}
+		delivered = append(delivered, sink.Name())
+		if err = r.store.MarkSinkDelivered(ctx, record.ID, delivered); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Relay) markFailed(ctx context.Context, record *models.OutboxEvent, cause error) error {
+	attempts := record.Attempts + 1
+	status := models.OutboxStatusPending
+	if attempts >= r.config.MaxAttempts {
+		status = models.OutboxStatusFailed
+		slog.ErrorContext(recordContext(ctx, record), "Giving up on outbox event",
+			"event", slog.GroupValue(slog.String("id", record.EventID), slog.String("type", record.EventType)),
+			"attempts", attempts, "error", cause)
+	}
+
+	return r.store.MarkFailed(ctx, record.ID, status, attempts, r.now().Add(r.backoff(attempts)), cause.Error())
+}
+
+// recordContext returns ctx in the correlation of an outbox record
+func recordContext(ctx context.Context, record *models.OutboxEvent) context.Context {
+	return shared.ContextWithCorrelation(ctx, shared.Correlation{
+		CorrelationID: record.CorrelationID,
+		CausationID:   record.CausationID,
+	})
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	return Backoff(r.config.BaseBackoff, r.config.MaxBackoff, attempts)
+}
+
+// Backoff is the delay before retrying after the given number of failed attempts:
+// base after the first, doubled for every further one up to max
+func Backoff(base, max time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
@@ -0,0 +1,260 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/events"
+	"parrotflow/internal/models"
+	"parrotflow/internal/ports"
+)
+
+// MockStore is an in-memory implementation of Store for testing
+type MockStore struct {
+	events []models.OutboxEvent
+	nextID uint64
+}
+
+func (m *MockStore) Append(ctx context.Context, events ...shared.DomainEvent) error {
+	for _, event := range events {
+		for _, existing := range m.events {
+			if existing.EventID == event.EventID() {
+				return nil
+			}
+		}
+		row, err := ports.OutboxDomainEventToPersistence(event)
+		if err != nil {
+			return err
+		}
+		m.nextID++
+		row.ID = m.nextID
+		m.events = append(m.events, *row)
+	}
+	return nil
+}
+
+func (m *MockStore) FetchPending(ctx context.Context, limit int, now time.Time) ([]models.OutboxEvent, error) {
+	result := make([]models.OutboxEvent, 0)
+	for _, e := range m.events {
+		if e.Status == models.OutboxStatusPending && !e.NextAttemptAt.After(now) && len(result) < limit {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockStore) MarkDelivered(ctx context.Context, id uint64, deliveredAt time.Time) error {
+	e := m.find(id)
+	e.Status = models.OutboxStatusDelivered
+	e.DeliveredAt = &deliveredAt
+	return nil
+}
+
+func (m *MockStore) MarkSinkDelivered(ctx context.Context, id uint64, sinks []string) error {
+	delivered, err := json.Marshal(sinks)
+	if err != nil {
+		return err
+	}
+	m.find(id).DeliveredSinks = string(delivered)
+	return nil
+}
+
+func (m *MockStore) MarkFailed(ctx context.Context, id uint64, status string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	e := m.find(id)
+	e.Status = status
+	e.Attempts = attempts
+	e.NextAttemptAt = nextAttemptAt
+	e.LastError = lastError
+	return nil
+}
+
+func (m *MockStore) find(id uint64) *models.OutboxEvent {
+	for i := range m.events {
+		if m.events[i].ID == id {
+			return &m.events[i]
+		}
+	}
+	return nil
+}
+
+// MockSink records delivered events and can be told to fail
+type MockSink struct {
+	name       string
+	delivered  []shared.DomainEvent
+	deliverErr error
+}
+
+func (m *MockSink) Name() string {
+	if m.name == "" {
+		return "mock"
+	}
+	return m.name
+}
+
+func (m *MockSink) Deliver(ctx context.Context, event shared.DomainEvent) error {
+	if m.deliverErr != nil {
+		return m.deliverErr
+	}
+	m.delivered = append(m.delivered, event)
+	return nil
+}
+
+func newRunCreated() run.RunCreated {
+	return run.RunCreated{
+		BaseEvent:  shared.NewBaseEvent(run.EventRunCreated, "42"),
+		RunID:      "42",
+		ScenarioID: "7",
+		Parameters: "{}",
+	}
+}
+
+func TestRelay_DeliversDecodedEvents(t *testing.T) {
+	store := &MockStore{}
+	sink := &MockSink{}
+	relay := NewRelay(store, events.NewDefaultRegistry(), DefaultRelayConfig(), sink)
+
+	event := newRunCreated()
+	store.Append(context.Background(), event)
+
+	delivered, err := relay.ProcessPending(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessPending() error = %v, want nil", err)
+	}
+	if delivered != 1 {
+		t.Fatalf("ProcessPending() delivered = %d, want 1", delivered)
+	}
+
+	got, ok := sink.delivered[0].(run.RunCreated)
+	if !ok {
+		t.Fatalf("Expected run.RunCreated, got %T", sink.delivered[0])
+	}
+	if got.EventID() != event.EventID() || got.ScenarioID != "7" {
+		t.Errorf("Decoded event = %+v, want ID %s and scenario 7", got, event.EventID())
+	}
+	if store.events[0].Status != models.OutboxStatusDelivered {
+		t.Errorf("Outbox status = %s, want delivered", store.events[0].Status)
+	}
+}
+
+func TestRelay_BacksOffOnFailure(t *testing.T) {
+	store := &MockStore{}
+	sink := &MockSink{deliverErr: errors.New("broker unavailable")}
+	relay := NewRelay(store, events.NewDefaultRegistry(), DefaultRelayConfig(), sink)
+
+	now := time.Now()
+	relay.now = func() time.Time { return now }
+	store.Append(context.Background(), newRunCreated())
+	store.events[0].NextAttemptAt = now
+
+	if _, err := relay.ProcessPending(context.Background()); err != nil {
+		t.Fatalf("ProcessPending() error = %v, want nil", err)
+	}
+
+	record := store.events[0]
+	if record.Status != models.OutboxStatusPending || record.Attempts != 1 {
+		t.Errorf("After one failure got status=%s attempts=%d, want pending/1", record.Status, record.Attempts)
+	}
+	if !record.NextAttemptAt.After(now) {
+		t.Error("Expected next attempt to be scheduled in the future")
+	}
+
+	// Not due yet, so nothing should be retried
+	if _, err := relay.ProcessPending(context.Background()); err != nil {
+		t.Fatalf("ProcessPending() error = %v, want nil", err)
+	}
+	if store.events[0].Attempts != 1 {
+		t.Errorf("Attempts = %d, want 1 before backoff elapses", store.events[0].Attempts)
+	}
+}
+
+func TestRelay_RetriesOnlyFailedSinks(t *testing.T) {
+	store := &MockStore{}
+	bus := &MockSink{name: "bus"}
+	webhooks := &MockSink{name: "webhooks", deliverErr: errors.New("database locked")}
+	relay := NewRelay(store, events.NewDefaultRegistry(), DefaultRelayConfig(), bus, webhooks)
+
+	now := time.Now()
+	relay.now = func() time.Time { return now }
+	store.Append(context.Background(), newRunCreated())
+	store.events[0].NextAttemptAt = now
+
+	if _, err := relay.ProcessPending(context.Background()); err != nil {
+		t.Fatalf("ProcessPending() error = %v, want nil", err)
+	}
+	if store.events[0].Status != models.OutboxStatusPending {
+		t.Fatalf("Outbox status = %s, want pending after a failed sink", store.events[0].Status)
+	}
+
+	// The retry reaches the failed sink only
+	webhooks.deliverErr = nil
+	now = now.Add(time.Hour)
+	if _, err := relay.ProcessPending(context.Background()); err != nil {
+		t.Fatalf("ProcessPending() error = %v, want nil", err)
+	}
+	if len(bus.delivered) != 1 || len(webhooks.delivered) != 1 {
+		t.Errorf("Deliveries = %d to the bus and %d to webhooks, want 1 each", len(bus.delivered), len(webhooks.delivered))
+	}
+	if store.events[0].Status != models.OutboxStatusDelivered {
+		t.Errorf("Outbox status = %s, want delivered", store.events[0].Status)
+	}
+}
+
+func TestRelay_GivesUpAfterMaxAttempts(t *testing.T) {
+	store := &MockStore{}
+	sink := &MockSink{deliverErr: errors.New("boom")}
+	config := DefaultRelayConfig()
+	config.MaxAttempts = 2
+	relay := NewRelay(store, events.NewDefaultRegistry(), config, sink)
+
+	store.Append(context.Background(), newRunCreated())
+	store.events[0].Attempts = 1
+
+	relay.ProcessPending(context.Background())
+
+	if store.events[0].Status != models.OutboxStatusFailed {
+		t.Errorf("Outbox status = %s, want failed", store.events[0].Status)
+	}
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	config := DefaultRelayConfig()
+	config.BaseBackoff = time.Second
+	config.MaxBackoff = 5 * time.Second
+	relay := NewRelay(&MockStore{}, shared.NewEventRegistry(), config)
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{10, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := relay.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestEventBus_PublishIsIdempotent(t *testing.T) {
+	store := &MockStore{}
+	relay := NewRelay(store, events.NewDefaultRegistry(), DefaultRelayConfig())
+	bus := NewEventBus(store, relay, nil)
+
+	event := newRunCreated()
+	store.Append(context.Background(), event) // written by the repository
+	bus.Publish(event)                        // published by the command handler
+
+	if len(store.events) != 1 {
+		t.Errorf("Expected 1 outbox row, got %d", len(store.events))
+	}
+}
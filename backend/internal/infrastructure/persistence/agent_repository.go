@@ -2,30 +2,75 @@ package persistence
 
 import (
 	"context"
-	"errors"
 	"parrotflow/internal/domain/agent"
+	"parrotflow/internal/domain/enrollment"
 	"parrotflow/internal/domain/tag"
 	"parrotflow/internal/models"
 	"parrotflow/internal/ports"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AgentRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	cipher ports.Cipher // Encrypts credential secrets at rest
 }
 
-func NewAgentRepository(db *gorm.DB) *AgentRepository {
-	return &AgentRepository{db: db}
+func NewAgentRepository(db *gorm.DB, cipher ports.Cipher) *AgentRepository {
+	return &AgentRepository{db: db, cipher: cipher}
 }
 
 func (r *AgentRepository) Save(ctx context.Context, a *agent.Agent) error {
-	model, err := ports.AgentDomainEntityToPersistence(a)
+	model, err := ports.AgentDomainEntityToPersistence(a, r.cipher)
 	if err != nil {
 		return err
 	}
 
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return r.save(tx, a, model)
+	})
+	if err != nil {
+		return err
+	}
+	return r.applyStored(a, model)
+}
+
+// Enroll saves a new agent and redeems the enrollment token it registered with in one
+// transaction, so an agent is never stored without its token or the other way round
+// The token is redeemed by the stored ID of the agent; when another registration
+// redeemed it first, saving it fails with a concurrent modification and nothing is stored
+func (r *AgentRepository) Enroll(ctx context.Context, a *agent.Agent, token *enrollment.Token) error {
+	model, err := ports.AgentDomainEntityToPersistence(a, r.cipher)
+	if err != nil {
+		return err
+	}
+
+	var tokenModel *models.EnrollmentToken
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.save(tx, a, model); err != nil {
+			return err
+		}
+		if err := token.Redeem(ports.AgentFormatID(model.ID)); err != nil {
+			return err
+		}
+		tokenModel = ports.EnrollmentTokenDomainEntityToPersistence(token)
+		if err := saveVersioned(tx, tokenModel, "enrollment token"); err != nil {
+			return err
+		}
+		return appendOutboxEvents(tx, token.Events, ports.EnrollmentTokenFormatID(tokenModel.ID))
+	})
+	if err != nil {
+		return err
+	}
+
+	token.Version = tokenModel.Version
+	return r.applyStored(a, model)
+}
+
+// save writes the agent, its tags and its pending events with the transaction tx
+func (r *AgentRepository) save(tx *gorm.DB, a *agent.Agent, model *models.Agent) error {
 	// Load tags from IDs
 	if len(a.Tags) > 0 {
 		tagIDs := make([]uint64, len(a.Tags))
@@ -33,22 +78,34 @@ func (r *AgentRepository) Save(ctx context.Context, a *agent.Agent) error {
 			tagIDs[i] = ports.TagParseID(tagID.String())
 		}
 		var tags []models.Tag
-		if err := r.db.WithContext(ctx).Where("id IN ?", tagIDs).Find(&tags).Error; err != nil {
+		if err := tx.Where("id IN ?", tagIDs).Find(&tags).Error; err != nil {
 			return err
 		}
 		model.Tags = tags
 	}
 
 	// Save agent
-	if err = r.db.WithContext(ctx).Save(model).Error; err != nil {
+	if err := saveVersioned(tx, model, "agent"); err != nil {
 		return err
 	}
 
 	// Update tag associations
-	if err = r.db.WithContext(ctx).Model(model).Association("Tags").Replace(model.Tags); err != nil {
+	if err := tx.Model(model).Association("Tags").Replace(model.Tags); err != nil {
 		return err
 	}
 
+	// Record pending domain events in the same transaction
+	return appendOutboxEvents(tx, a.Events, ports.AgentFormatID(model.ID))
+}
+
+// applyStored gives a saved agent its stored ID and version
+func (r *AgentRepository) applyStored(a *agent.Agent, model *models.Agent) error {
+	id, err := agent.NewAgentID(ports.AgentFormatID(model.ID))
+	if err != nil {
+		return err
+	}
+	a.Id = id
+	a.Version = model.Version
 	return nil
 }
 
@@ -56,24 +113,24 @@ func (r *AgentRepository) FindByID(ctx context.Context, id agent.AgentID) (*agen
 	var model models.Agent
 	if err := r.db.WithContext(ctx).Preload("Tags").Where("id = ?", ports.AgentParseID(id.String())).First(&model).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.New("agent not found")
+			return nil, agent.ErrAgentNotFound
 		}
 		return nil, err
 	}
 
-	return ports.AgentPersistenceToDomainEntity(&model)
+	return r.toDomain(&model)
 }
 
 func (r *AgentRepository) FindByName(ctx context.Context, name string) (*agent.Agent, error) {
 	var model models.Agent
 	if err := r.db.WithContext(ctx).Preload("Tags").Where("name = ?", name).First(&model).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.New("agent not found")
+			return nil, agent.ErrAgentNotFound
 		}
 		return nil, err
 	}
 
-	return ports.AgentPersistenceToDomainEntity(&model)
+	return r.toDomain(&model)
 }
 
 func (r *AgentRepository) FindAll(ctx context.Context) ([]*agent.Agent, error) {
@@ -84,7 +141,7 @@ func (r *AgentRepository) FindAll(ctx context.Context) ([]*agent.Agent, error) {
 
 	agents := make([]*agent.Agent, len(models))
 	for i, model := range models {
-		a, err := ports.AgentPersistenceToDomainEntity(&model)
+		a, err := r.toDomain(&model)
 		if err != nil {
 			return nil, err
 		}
@@ -133,7 +190,7 @@ func (r *AgentRepository) FindByCriteria(ctx context.Context, criteria agent.Sea
 	}
 
 	// Convert to domain entities
-	agents, err := ConvertSliceToDomainPtr(models, ports.AgentPersistenceToDomainEntity)
+	agents, err := ConvertSliceToDomainPtr(models, r.toDomain)
 	if err != nil {
 		return nil, err
 	}
@@ -160,7 +217,7 @@ func (r *AgentRepository) FindByStatus(ctx context.Context, status agent.AgentSt
 
 	agents := make([]*agent.Agent, len(models))
 	for i, model := range models {
-		a, err := ports.AgentPersistenceToDomainEntity(&model)
+		a, err := r.toDomain(&model)
 		if err != nil {
 			return nil, err
 		}
@@ -196,7 +253,7 @@ func (r *AgentRepository) FindByTags(ctx context.Context, tagIDs []tag.TagID) ([
 
 	agents := make([]*agent.Agent, len(models))
 	for i, model := range models {
-		a, err := ports.AgentPersistenceToDomainEntity(&model)
+		a, err := r.toDomain(&model)
 		if err != nil {
 			return nil, err
 		}
@@ -220,7 +277,7 @@ func (r *AgentRepository) FindAvailable(ctx context.Context) ([]*agent.Agent, er
 
 	agents := make([]*agent.Agent, 0, len(models))
 	for _, model := range models {
-		a, err := ports.AgentPersistenceToDomainEntity(&model)
+		a, err := r.toDomain(&model)
 		if err != nil {
 			return nil, err
 		}
@@ -246,7 +303,7 @@ func (r *AgentRepository) FindByBrowserType(ctx context.Context, browserType age
 
 	agents := make([]*agent.Agent, len(models))
 	for i, model := range models {
-		a, err := ports.AgentPersistenceToDomainEntity(&model)
+		a, err := r.toDomain(&model)
 		if err != nil {
 			return nil, err
 		}
@@ -268,7 +325,7 @@ func (r *AgentRepository) FindByPlatform(ctx context.Context, platform agent.Pla
 
 	agents := make([]*agent.Agent, len(models))
 	for i, model := range models {
-		a, err := ports.AgentPersistenceToDomainEntity(&model)
+		a, err := r.toDomain(&model)
 		if err != nil {
 			return nil, err
 		}
@@ -292,7 +349,7 @@ func (r *AgentRepository) FindStaleAgents(ctx context.Context, heartbeatTimeout
 
 	agents := make([]*agent.Agent, len(models))
 	for i, model := range models {
-		a, err := ports.AgentPersistenceToDomainEntity(&model)
+		a, err := r.toDomain(&model)
 		if err != nil {
 			return nil, err
 		}
@@ -317,3 +374,36 @@ func (r *AgentRepository) ExistsByName(ctx context.Context, name string) (bool,
 	err := r.db.WithContext(ctx).Model(&models.Agent{}).Where("name = ?", name).Count(&count).Error
 	return count > 0, err
 }
+
+// UseNonce records a signature nonce of the agent and forgets those older than agent.NonceRetention
+// Times are stored in UTC so they compare as text
+func (r *AgentRepository) UseNonce(ctx context.Context, id agent.AgentID, nonce string, now time.Time) error {
+	now = now.UTC()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("seen_at < ?", now.Add(-agent.NonceRetention)).Delete(&models.AgentNonce{}).Error; err != nil {
+			return err
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.AgentNonce{
+			AgentID: ports.AgentParseID(id.String()),
+			Nonce:   nonce,
+			SeenAt:  now,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return agent.ErrReplayedSignature
+		}
+		return nil
+	})
+}
+
+// ReencryptCredentials encrypts the credential secrets not encrypted with the primary key yet
+// and returns how many it changed
+func (r *AgentRepository) ReencryptCredentials(ctx context.Context) (int, error) {
+	return reencrypt(r.db.WithContext(ctx), &models.Agent{}, "credential_secret", "credential_key_id", r.cipher)
+}
+
+func (r *AgentRepository) toDomain(model *models.Agent) (*agent.Agent, error) {
+	return ports.AgentPersistenceToDomainEntity(model, r.cipher)
+}
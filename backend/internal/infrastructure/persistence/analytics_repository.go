@@ -0,0 +1,344 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"parrotflow/internal/domain/analytics"
+	"parrotflow/internal/models"
+	"parrotflow/internal/ports"
+
+	"gorm.io/gorm"
+)
+
+// AnalyticsRepository computes run analytics with SQLite aggregate and window functions
+type AnalyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// runDurationMs is the duration of a finished run, NULL for runs never started or not finished
+const runDurationMs = `CASE WHEN status IN ('COMPLETED', 'FAILED') AND julianday(started_at) > julianday('1900-01-01')
+	AND julianday(finished_at) >= julianday(started_at)
+	THEN CAST(ROUND((julianday(finished_at) - julianday(started_at)) * 86400000) AS INTEGER) END`
+
+// bucketExpressions start the bucket of a run's creation time, in UTC
+var bucketExpressions = map[string]string{
+	analytics.IntervalHour: `strftime('%Y-%m-%d %H:00:00', created_at)`,
+	analytics.IntervalDay:  `date(created_at)`,
+	analytics.IntervalWeek: `date(created_at, 'weekday 0', '-6 days')`, // Monday
+}
+
+// rollupBucketExpressions start the bucket of a rollup day
+var rollupBucketExpressions = map[string]string{
+	analytics.IntervalDay:  `day`,
+	analytics.IntervalWeek: `date(day, 'weekday 0', '-6 days')`,
+}
+
+// unixMillis converts an SQLite time expression to milliseconds since the epoch
+func unixMillis(expression string) string {
+	return fmt.Sprintf("CAST(ROUND((julianday(%s) - 2440587.5) * 86400000) AS INTEGER)", expression)
+}
+
+// runsCTE selects the runs a report covers as the "runs" common table expression
+func runsCTE(criteria analytics.Criteria) (string, []any) {
+	bucket := bucketExpressions[criteria.Interval]
+	if bucket == "" {
+		bucket = "''"
+	}
+
+	// Timestamps are stored as text with the offset they were written with, so they
+	// only compare as instants through julianday
+	conditions := []string{
+		"julianday(created_at) >= julianday(?)",
+		"julianday(created_at) < julianday(?)",
+		"scenario_id NOT IN (SELECT id FROM scenarios WHERE deleted_at IS NOT NULL)",
+	}
+	args := []any{criteria.Since, criteria.Until}
+	if criteria.ScenarioID != "" {
+		conditions = append(conditions, "scenario_id = ?")
+		args = append(args, ports.ScenarioParseID(criteria.ScenarioID))
+	}
+
+	return fmt.Sprintf(`WITH runs AS (
+	SELECT id, scenario_id, status, COALESCE(failure_reason, '') AS failure_reason,
+		finished_at, %s AS bucket, %s AS duration_ms
+	FROM scenario_runs
+	WHERE %s
+)`, bucket, runDurationMs, strings.Join(conditions, " AND ")), args
+}
+
+type countsRow struct {
+	Bucket    string
+	Total     int64
+	Completed int64
+	Failed    int64
+	Cancelled int64
+	Measured  int64
+	P50       int64
+	P95       int64
+}
+
+func (row countsRow) counts() analytics.Counts {
+	return analytics.Counts{Total: row.Total, Completed: row.Completed, Failed: row.Failed, Cancelled: row.Cancelled}
+}
+
+func (row countsRow) durations() analytics.Durations {
+	return analytics.Durations{
+		Measured: row.Measured,
+		P50:      time.Duration(row.P50) * time.Millisecond,
+		P95:      time.Duration(row.P95) * time.Millisecond,
+	}
+}
+
+func (r *AnalyticsRepository) Report(ctx context.Context, criteria analytics.Criteria) (*analytics.Report, error) {
+	db := r.db.WithContext(ctx)
+	report := &analytics.Report{Criteria: criteria}
+
+	// The overall counts come from the same source as the buckets, so they add up
+	count := countByBucket
+	if criteria.Source == analytics.SourceRollup {
+		count = countRollupByBucket
+	}
+
+	overall := criteria
+	overall.Interval = ""
+	totals, err := count(db, overall)
+	if err != nil {
+		return nil, err
+	}
+	if len(totals) == 1 {
+		report.Counts = totals[0].counts()
+		report.Durations = totals[0].durations()
+	}
+
+	buckets, err := count(db, criteria)
+	if err != nil {
+		return nil, err
+	}
+	report.Buckets = make([]analytics.Bucket, 0, len(buckets))
+	for _, row := range buckets {
+		start, err := parseBucket(row.Bucket)
+		if err != nil {
+			return nil, err
+		}
+		report.Buckets = append(report.Buckets, analytics.Bucket{Start: start, Counts: row.counts(), Durations: row.durations()})
+	}
+
+	if report.FailureReasons, err = failureReasons(db, criteria); err != nil {
+		return nil, err
+	}
+	if report.FailingNodes, err = failingNodes(db, criteria); err != nil {
+		return nil, err
+	}
+	if criteria.ScenarioID == "" {
+		if report.Scenarios, err = scenarioStats(db, criteria); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// countByBucket counts runs by outcome and takes nearest-rank duration percentiles per bucket
+func countByBucket(db *gorm.DB, criteria analytics.Criteria) ([]countsRow, error) {
+	cte, args := runsCTE(criteria)
+	query := cte + `,
+ranked AS (
+	SELECT bucket, duration_ms,
+		ROW_NUMBER() OVER (PARTITION BY bucket ORDER BY duration_ms) AS rn,
+		COUNT(*) OVER (PARTITION BY bucket) AS n
+	FROM runs
+	WHERE duration_ms IS NOT NULL
+),
+percentiles AS (
+	SELECT bucket, COUNT(*) AS measured,
+		MIN(CASE WHEN rn * 100 >= n * 50 THEN duration_ms END) AS p50,
+		MIN(CASE WHEN rn * 100 >= n * 95 THEN duration_ms END) AS p95
+	FROM ranked
+	GROUP BY bucket
+),
+counts AS (
+	SELECT bucket, COUNT(*) AS total,
+		SUM(status = 'COMPLETED') AS completed,
+		SUM(status = 'FAILED') AS failed,
+		SUM(status = 'CANCELLED') AS cancelled
+	FROM runs
+	GROUP BY bucket
+)
+SELECT counts.bucket, total, completed, failed, cancelled,
+	COALESCE(measured, 0) AS measured, COALESCE(p50, 0) AS p50, COALESCE(p95, 0) AS p95
+FROM counts LEFT JOIN percentiles ON percentiles.bucket = counts.bucket
+ORDER BY counts.bucket`
+
+	var rows []countsRow
+	err := db.Raw(query, args...).Scan(&rows).Error
+	return rows, err
+}
+
+// countRollupByBucket counts runs by outcome from the daily rollup
+// The rollup keeps no percentiles, so the durations of its buckets stay zero
+func countRollupByBucket(db *gorm.DB, criteria analytics.Criteria) ([]countsRow, error) {
+	bucket := rollupBucketExpressions[criteria.Interval]
+	if bucket == "" {
+		bucket = "''"
+	}
+
+	conditions := []string{
+		"day >= date(?)",
+		"day < date(?)",
+		"scenario_id NOT IN (SELECT id FROM scenarios WHERE deleted_at IS NOT NULL)",
+	}
+	args := []any{criteria.Since, criteria.Until}
+	if criteria.ScenarioID != "" {
+		conditions = append(conditions, "scenario_id = ?")
+		args = append(args, ports.ScenarioParseID(criteria.ScenarioID))
+	}
+
+	query := fmt.Sprintf(`SELECT %s AS bucket, SUM(run_count) AS total,
+	SUM(CASE WHEN status = 'COMPLETED' THEN run_count ELSE 0 END) AS completed,
+	SUM(CASE WHEN status = 'FAILED' THEN run_count ELSE 0 END) AS failed,
+	SUM(CASE WHEN status = 'CANCELLED' THEN run_count ELSE 0 END) AS cancelled
+FROM run_rollups
+WHERE %s
+GROUP BY bucket
+ORDER BY bucket`, bucket, strings.Join(conditions, " AND "))
+
+	var rows []countsRow
+	err := db.Raw(query, args...).Scan(&rows).Error
+	return rows, err
+}
+
+func failureReasons(db *gorm.DB, criteria analytics.Criteria) ([]analytics.FailureReason, error) {
+	cte, args := runsCTE(criteria)
+	query := cte + fmt.Sprintf(`
+SELECT failure_reason AS reason, COUNT(*) AS count, %s AS last_seen_ms
+FROM runs
+WHERE status = 'FAILED' AND failure_reason <> ''
+GROUP BY failure_reason
+ORDER BY count DESC, last_seen_ms DESC
+LIMIT ?`, unixMillis("MAX(finished_at)"))
+
+	var rows []struct {
+		Reason     string
+		Count      int64
+		LastSeenMs int64
+	}
+	if err := db.Raw(query, append(args, criteria.Limit)...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	reasons := make([]analytics.FailureReason, len(rows))
+	for i, row := range rows {
+		reasons[i] = analytics.FailureReason{
+			Reason:     row.Reason,
+			Count:      row.Count,
+			LastSeenAt: time.UnixMilli(row.LastSeenMs).UTC(),
+		}
+	}
+	return reasons, nil
+}
+
+func failingNodes(db *gorm.DB, criteria analytics.Criteria) ([]analytics.NodeFailures, error) {
+	cte, args := runsCTE(criteria)
+	query := cte + `,
+steps AS (
+	SELECT run_node_steps.* FROM run_node_steps JOIN runs ON runs.id = run_node_steps.run_id
+)
+SELECT scenario_id, node_id, COUNT(*) AS runs, SUM(status = 'FAILED') AS failures,
+	(SELECT message FROM steps AS latest
+		WHERE latest.scenario_id = steps.scenario_id AND latest.node_id = steps.node_id AND latest.status = 'FAILED'
+		ORDER BY latest.reported_at DESC LIMIT 1) AS last_message
+FROM steps
+GROUP BY scenario_id, node_id
+HAVING failures > 0
+ORDER BY failures DESC, CAST(failures AS REAL) / runs DESC
+LIMIT ?`
+
+	var rows []struct {
+		ScenarioID  uint64
+		NodeID      string
+		Runs        int64
+		Failures    int64
+		LastMessage string
+	}
+	if err := db.Raw(query, append(args, criteria.Limit)...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	nodes := make([]analytics.NodeFailures, len(rows))
+	for i, row := range rows {
+		nodes[i] = analytics.NodeFailures{
+			ScenarioID:  ports.ScenarioFormatID(row.ScenarioID),
+			NodeID:      row.NodeID,
+			Runs:        row.Runs,
+			Failures:    row.Failures,
+			LastMessage: row.LastMessage,
+		}
+	}
+	return nodes, nil
+}
+
+func scenarioStats(db *gorm.DB, criteria analytics.Criteria) ([]analytics.ScenarioStats, error) {
+	cte, args := runsCTE(criteria)
+	query := cte + `
+SELECT scenario_id, COUNT(*) AS total,
+	SUM(status = 'COMPLETED') AS completed,
+	SUM(status = 'FAILED') AS failed,
+	SUM(status = 'CANCELLED') AS cancelled
+FROM runs
+GROUP BY scenario_id
+ORDER BY CAST(failed AS REAL) / MAX(completed + failed, 1) DESC, failed DESC, scenario_id
+LIMIT ?`
+
+	var rows []struct {
+		ScenarioID uint64
+		Total      int64
+		Completed  int64
+		Failed     int64
+		Cancelled  int64
+	}
+	if err := db.Raw(query, append(args, criteria.Limit)...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	stats := make([]analytics.ScenarioStats, len(rows))
+	for i, row := range rows {
+		stats[i] = analytics.ScenarioStats{
+			ScenarioID: ports.ScenarioFormatID(row.ScenarioID),
+			Counts:     analytics.Counts{Total: row.Total, Completed: row.Completed, Failed: row.Failed, Cancelled: row.Cancelled},
+		}
+	}
+	return stats, nil
+}
+
+// RefreshRollup replaces the rollup rows of the days from since on with fresh counts
+// Days before since are left alone, their runs may have been compacted already
+func (r *AnalyticsRepository) RefreshRollup(ctx context.Context, since time.Time) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("day >= date(?)", since).Delete(&models.RunRollup{}).Error; err != nil {
+			return err
+		}
+		return tx.Exec(fmt.Sprintf(`INSERT INTO run_rollups
+	(scenario_id, day, status, run_count, measured_count, total_duration_ms, created_at, updated_at, version)
+SELECT scenario_id, date(created_at) AS day, status, COUNT(*), COUNT(duration_ms), COALESCE(SUM(duration_ms), 0), ?, ?, 1
+FROM (SELECT scenario_id, created_at, status, %s AS duration_ms FROM scenario_runs) AS runs
+WHERE date(created_at) >= date(?)
+GROUP BY scenario_id, day, status`, runDurationMs), now, now, since).Error
+	})
+}
+
+// parseBucket reads the start of a bucket as produced by the bucket expressions
+func parseBucket(bucket string) (time.Time, error) {
+	for _, layout := range []string{time.DateTime, time.DateOnly} {
+		if start, err := time.ParseInLocation(layout, bucket, time.UTC); err == nil {
+			return start, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unexpected analytics bucket %q", bucket)
+}
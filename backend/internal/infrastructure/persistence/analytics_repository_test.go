@@ -0,0 +1,248 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"parrotflow/internal/domain/analytics"
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func TestAnalytics_Report(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.OutboxEvent{}, &models.EventLogEntry{}, &models.RunNodeStep{}, &models.RunRollup{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	ctx := context.Background()
+	if err := db.Create(&models.Scenario{ScenarioBase: models.ScenarioBase{Name: "measured"}}).Error; err != nil {
+		t.Fatalf("Create(scenario) error = %v", err)
+	}
+
+	// Ten completed runs on the first day lasting 1 to 10 seconds, two failed ones on the next
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	for i := 1; i <= 10; i++ {
+		createdAt := day.Add(time.Duration(i) * time.Minute)
+		insertRun(t, db, createdAt, "COMPLETED", time.Duration(i)*time.Second, "")
+	}
+	insertRun(t, db, day.Add(25*time.Hour), "FAILED", time.Second, "timeout")
+	insertRun(t, db, day.Add(26*time.Hour), "CANCELLED", 0, "")
+
+	// A run failing through the domain records its reason and node steps; it is
+	// created now, outside the days above
+	runs := NewRunRepository(db)
+	scenarioID, _ := scenario.NewScenarioID("1")
+	runID, _ := run.NewRunID("100")
+	r, _ := run.NewRun(runID, scenarioID, "{}")
+	_ = r.Start()
+	_ = r.ReportProgress("login", run.NodeStatusCompleted, "")
+	_ = r.ReportProgress("checkout", run.NodeStatusRunning, "")
+	_ = r.ReportProgress("checkout", run.NodeStatusFailed, "button not found")
+	_ = r.Fail("timeout")
+	if err := runs.Save(ctx, r); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	repository := NewAnalyticsRepository(db)
+	report, err := repository.Report(ctx, analytics.Criteria{
+		ScenarioID: "1",
+		Since:      day,
+		Until:      day.Add(48 * time.Hour),
+		Interval:   analytics.IntervalDay,
+		Source:     analytics.SourceRuns,
+		Limit:      10,
+	})
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+
+	wantCounts := analytics.Counts{Total: 12, Completed: 10, Failed: 1, Cancelled: 1}
+	if report.Counts != wantCounts {
+		t.Errorf("Counts = %+v, want %+v", report.Counts, wantCounts)
+	}
+	// Nearest rank over 1..10s and the failed 1s run: the 6th and 11th of 11 values
+	if report.Durations.Measured != 11 || report.Durations.P50 != 5*time.Second || report.Durations.P95 != 10*time.Second {
+		t.Errorf("Durations = %+v, want 11 measured, p50 5s, p95 10s", report.Durations)
+	}
+	if len(report.Buckets) != 2 {
+		t.Fatalf("Report has %d buckets, want 2", len(report.Buckets))
+	}
+	if first := report.Buckets[0]; !first.Start.Equal(day) || first.Completed != 10 || first.SuccessRate() != 1 {
+		t.Errorf("First bucket = %+v, want 10 completed runs on %s", first, day)
+	}
+	if second := report.Buckets[1]; second.Failed != 1 || second.FailureRate() != 1 {
+		t.Errorf("Second bucket = %+v, want the failed run", second)
+	}
+
+	// Bounds and creation times in other offsets cover the same instants
+	berlin := time.FixedZone("CET", 3600)
+	insertRun(t, db, day.Add(47*time.Hour).In(berlin), "COMPLETED", 0, "")
+	zoned, err := repository.Report(ctx, analytics.Criteria{
+		ScenarioID: "1",
+		Since:      day.In(berlin),
+		Until:      day.Add(48 * time.Hour).In(berlin),
+		Interval:   analytics.IntervalDay,
+		Source:     analytics.SourceRuns,
+		Limit:      10,
+	})
+	if err != nil {
+		t.Fatalf("Report(zoned) error = %v", err)
+	}
+	if zoned.Counts.Total != 13 {
+		t.Errorf("Zoned report counts %d runs, want 13", zoned.Counts.Total)
+	}
+
+	// Failure reasons and nodes cover the runs of any time, here the one saved now
+	recent, err := repository.Report(ctx, analytics.Criteria{
+		Since:    time.Now().Add(-time.Hour),
+		Until:    time.Now().Add(time.Hour),
+		Interval: analytics.IntervalHour,
+		Source:   analytics.SourceRuns,
+		Limit:    10,
+	})
+	if err != nil {
+		t.Fatalf("Report(recent) error = %v", err)
+	}
+	if len(recent.FailureReasons) != 1 || recent.FailureReasons[0].Reason != "timeout" {
+		t.Errorf("FailureReasons = %+v, want timeout", recent.FailureReasons)
+	}
+	if len(recent.FailingNodes) != 1 {
+		t.Fatalf("FailingNodes = %+v, want checkout", recent.FailingNodes)
+	}
+	if node := recent.FailingNodes[0]; node.NodeID != "checkout" || node.Failures != 1 || node.LastMessage != "button not found" {
+		t.Errorf("Failing node = %+v, want the last state of checkout", node)
+	}
+	if len(recent.Scenarios) != 1 || recent.Scenarios[0].Failed != 1 {
+		t.Errorf("Scenarios = %+v, want scenario 1 with its failed run", recent.Scenarios)
+	}
+}
+
+func TestAnalytics_RollupOutlivesCompactedRuns(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.RunNodeStep{}, &models.RunRollup{}, &models.RunSummary{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	ctx := context.Background()
+	if err := db.Create(&models.Scenario{ScenarioBase: models.ScenarioBase{Name: "rolled up"}}).Error; err != nil {
+		t.Fatalf("Create(scenario) error = %v", err)
+	}
+
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	insertRun(t, db, day.Add(time.Hour), "COMPLETED", time.Second, "")
+	insertRun(t, db, day.Add(2*time.Hour), "FAILED", time.Second, "timeout")
+
+	repository := NewAnalyticsRepository(db)
+	if err := repository.RefreshRollup(ctx, day); err != nil {
+		t.Fatalf("RefreshRollup() error = %v", err)
+	}
+	if err := db.Where("1 = 1").Delete(&models.ScenarioRun{}).Error; err != nil {
+		t.Fatalf("Delete(runs) error = %v", err)
+	}
+
+	report, err := repository.Report(ctx, analytics.Criteria{
+		Since:    day,
+		Until:    day.Add(7 * 24 * time.Hour),
+		Interval: analytics.IntervalWeek,
+		Source:   analytics.SourceRollup,
+		Limit:    10,
+	})
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	if len(report.Buckets) != 1 {
+		t.Fatalf("Report has %d buckets, want 1", len(report.Buckets))
+	}
+	if bucket := report.Buckets[0]; !bucket.Start.Equal(day) || bucket.Completed != 1 || bucket.Failed != 1 {
+		t.Errorf("Bucket = %+v, want one completed and one failed run in the week of %s", bucket, day)
+	}
+	// The overall counts come from the rollup too, not from the compacted runs
+	if want := (analytics.Counts{Total: 2, Completed: 1, Failed: 1}); report.Counts != want {
+		t.Errorf("Counts = %+v, want %+v", report.Counts, want)
+	}
+}
+
+func TestAnalytics_PurgedScenarioLeavesNothingBehind(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.OutboxEvent{}, &models.EventLogEntry{}, &models.RunNodeStep{}, &models.RunRollup{}, &models.RunSummary{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	ctx := context.Background()
+	if err := db.Create(&models.Scenario{ScenarioBase: models.ScenarioBase{Name: "purged"}}).Error; err != nil {
+		t.Fatalf("Create(scenario) error = %v", err)
+	}
+
+	// A run with node steps, rolled up and summarized
+	runs := NewRunRepository(db)
+	scenarioID, _ := scenario.NewScenarioID("1")
+	runID, _ := run.NewRunID("100")
+	r, _ := run.NewRun(runID, scenarioID, "{}")
+	_ = r.Start()
+	_ = r.ReportProgress("checkout", run.NodeStatusFailed, "button not found")
+	_ = r.Fail("timeout")
+	if err := runs.Save(ctx, r); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	repository := NewAnalyticsRepository(db)
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	if err := repository.RefreshRollup(ctx, day); err != nil {
+		t.Fatalf("RefreshRollup() error = %v", err)
+	}
+	if err := db.Create(&models.RunSummary{ScenarioID: 1, Status: "FAILED", RunCount: 3}).Error; err != nil {
+		t.Fatalf("Create(summary) error = %v", err)
+	}
+
+	scenarios := NewScenarioRepository(db)
+	if err := scenarios.Delete(ctx, scenarioID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if purged, err := scenarios.PurgeDeleted(ctx, time.Now().Add(time.Hour)); err != nil || purged != 1 {
+		t.Fatalf("PurgeDeleted() = %d, %v, want 1 purged", purged, err)
+	}
+
+	for _, model := range []any{&models.ScenarioRun{}, &models.RunNodeStep{}, &models.RunRollup{}, &models.RunSummary{}} {
+		var count int64
+		db.Model(model).Count(&count)
+		if count != 0 {
+			t.Errorf("%T: %d rows left after the purge", model, count)
+		}
+	}
+
+	// Once the scenario row is gone analytics cannot tell its rows apart anymore
+	for _, source := range []string{analytics.SourceRuns, analytics.SourceRollup} {
+		report, err := repository.Report(ctx, analytics.Criteria{
+			Since:    day.Add(-24 * time.Hour),
+			Until:    day.Add(48 * time.Hour),
+			Interval: analytics.IntervalDay,
+			Source:   source,
+			Limit:    10,
+		})
+		if err != nil {
+			t.Fatalf("Report(%s) error = %v", source, err)
+		}
+		if report.Counts.Total != 0 || len(report.Buckets) != 0 || len(report.FailingNodes) != 0 || len(report.Scenarios) != 0 {
+			t.Errorf("Report(%s) = %+v, want the purged scenario gone", source, report)
+		}
+	}
+}
+
+func insertRun(t *testing.T, db *gorm.DB, createdAt time.Time, status string, duration time.Duration, reason string) {
+	t.Helper()
+	model := &models.ScenarioRun{
+		Model:         models.Model{CreatedAt: createdAt, UpdatedAt: createdAt, Version: 1},
+		ScenarioID:    1,
+		Status:        status,
+		Parameters:    "{}",
+		FailureReason: reason,
+	}
+	if duration > 0 {
+		model.StartedAt = createdAt
+		model.FinishedAt = createdAt.Add(duration)
+	}
+	if err := db.Create(model).Error; err != nil {
+		t.Fatalf("Create(run) error = %v", err)
+	}
+}
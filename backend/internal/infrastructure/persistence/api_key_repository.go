@@ -0,0 +1,80 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"parrotflow/internal/domain/apikey"
+	"parrotflow/internal/models"
+	"parrotflow/internal/ports"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) Save(ctx context.Context, k *apikey.APIKey) error {
+	model := ports.APIKeyDomainEntityToPersistence(k)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, model, "api key"); err != nil {
+			return err
+		}
+
+		// Record pending domain events in the same transaction
+		return appendOutboxEvents(tx, k.Events, ports.APIKeyFormatID(model.ID))
+	})
+	if err != nil {
+		return err
+	}
+
+	id, err := apikey.NewAPIKeyID(ports.APIKeyFormatID(model.ID))
+	if err != nil {
+		return err
+	}
+	k.Id = id
+	k.Version = model.Version
+	return nil
+}
+
+func (r *APIKeyRepository) FindByID(ctx context.Context, id apikey.APIKeyID) (*apikey.APIKey, error) {
+	return r.findOne(ctx, "id = ?", ports.APIKeyParseID(id.String()))
+}
+
+func (r *APIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*apikey.APIKey, error) {
+	return r.findOne(ctx, "prefix = ?", prefix)
+}
+
+func (r *APIKeyRepository) findOne(ctx context.Context, condition string, value any) (*apikey.APIKey, error) {
+	var model models.APIKey
+	if err := r.db.WithContext(ctx).Where(condition, value).First(&model).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, apikey.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	return ports.APIKeyPersistenceToDomainEntity(&model)
+}
+
+func (r *APIKeyRepository) FindAll(ctx context.Context) ([]*apikey.APIKey, error) {
+	var models []models.APIKey
+	if err := r.db.WithContext(ctx).Order("created_at ASC, id ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return ConvertSliceToDomainPtr(models, ports.APIKeyPersistenceToDomainEntity)
+}
+
+// RecordUsage bypasses saveVersioned: a key in use must not conflict with an admin revoking it
+func (r *APIKeyRepository) RecordUsage(ctx context.Context, id apikey.APIKeyID, usedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ?", ports.APIKeyParseID(id.String())).
+		UpdateColumn("last_used_at", usedAt).Error
+}
@@ -0,0 +1,121 @@
+package persistence
+
+import (
+	"context"
+	"strings"
+
+	"parrotflow/internal/domain/audit"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/models"
+	"parrotflow/internal/ports"
+
+	"gorm.io/gorm"
+)
+
+// auditBatchSize is the number of entries Each reads at a time
+const auditBatchSize = 500
+
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+func (r *AuditLogRepository) Append(ctx context.Context, entry *audit.Entry) error {
+	model := ports.AuditDomainEntryToPersistence(entry)
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return err
+	}
+	entry.Sequence = model.ID
+	return nil
+}
+
+func (r *AuditLogRepository) Find(ctx context.Context, criteria audit.Criteria) (shared.Page[*audit.Entry], error) {
+	var page shared.Page[*audit.Entry]
+	var entries []models.AuditEntry
+	query := r.filter(r.db.WithContext(ctx), criteria)
+
+	total, err := countTotal(query, &entries)
+	if err != nil {
+		return page, err
+	}
+	page.Total = total
+
+	if ascending(criteria) {
+		query = query.Order("id ASC")
+	} else {
+		query = query.Order("id DESC")
+	}
+	if err := applyPage(query, criteria.Limit, criteria.Offset).Find(&entries).Error; err != nil {
+		return page, err
+	}
+
+	page.Items, err = ConvertSliceToDomainPtr(entries, ports.AuditPersistenceToDomain)
+	return page, err
+}
+
+// Each pages through the entries by sequence rather than offset, so entries recorded
+// meanwhile neither shift nor repeat the batches
+func (r *AuditLogRepository) Each(ctx context.Context, criteria audit.Criteria, fn func(*audit.Entry) error) error {
+	var last uint64
+	for {
+		var entries []models.AuditEntry
+		query := r.filter(r.db.WithContext(ctx), criteria).Limit(auditBatchSize)
+		switch {
+		case ascending(criteria):
+			query = query.Where("id > ?", last).Order("id ASC")
+		case last > 0:
+			query = query.Where("id < ?", last).Order("id DESC")
+		default:
+			query = query.Order("id DESC")
+		}
+		if err := query.Find(&entries).Error; err != nil {
+			return err
+		}
+
+		for i := range entries {
+			entry, err := ports.AuditPersistenceToDomain(&entries[i])
+			if err != nil {
+				return err
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		if len(entries) < auditBatchSize {
+			return nil
+		}
+		last = entries[len(entries)-1].ID
+	}
+}
+
+func (r *AuditLogRepository) filter(query *gorm.DB, criteria audit.Criteria) *gorm.DB {
+	if criteria.Actor != "" {
+		query = query.Where("actor = ?", criteria.Actor)
+	}
+	if len(criteria.OperationIDs) > 0 {
+		query = query.Where("operation_id IN ?", criteria.OperationIDs)
+	}
+	if criteria.ResourceType != "" {
+		query = query.Where("resource_type = ?", criteria.ResourceType)
+	}
+	if criteria.ResourceID != "" {
+		query = query.Where("resource_id = ?", criteria.ResourceID)
+	}
+	if criteria.Outcome != "" {
+		query = query.Where("outcome = ?", criteria.Outcome)
+	}
+	if !criteria.Since.IsZero() {
+		query = query.Where("occurred_at >= ?", criteria.Since)
+	}
+	if !criteria.Until.IsZero() {
+		query = query.Where("occurred_at < ?", criteria.Until)
+	}
+	return query
+}
+
+func ascending(criteria audit.Criteria) bool {
+	return strings.EqualFold(criteria.OrderDir, shared.SortAsc)
+}
@@ -0,0 +1,86 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"parrotflow/internal/domain/audit"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/models"
+)
+
+func TestAuditLog_AppendsFiltersAndExports(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.AuditEntry{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	ctx := context.Background()
+	if err := ProtectAuditLog(ctx, db); err != nil {
+		t.Fatalf("ProtectAuditLog() error = %v", err)
+	}
+	log := NewAuditLogRepository(db)
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range auditBatchSize + 2 {
+		entry := &audit.Entry{
+			OccurredAt:   start.Add(time.Duration(i) * time.Second),
+			Actor:        "apikey:1",
+			OperationID:  "start-run",
+			Method:       "POST",
+			Path:         "/api/runs/1/start",
+			ResourceType: "runs",
+			ResourceID:   "1",
+			Status:       200,
+			Outcome:      audit.OutcomeSuccess,
+		}
+		if i%2 == 1 {
+			entry.Actor, entry.Status, entry.Outcome = audit.ActorAnonymous, 401, audit.OutcomeDenied
+		}
+		if err := log.Append(ctx, entry); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if entry.Sequence != uint64(i+1) {
+			t.Fatalf("Sequence = %d, want %d", entry.Sequence, i+1)
+		}
+	}
+
+	page, err := log.Find(ctx, audit.Criteria{Outcome: audit.OutcomeDenied, Limit: 10})
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if page.Total != (auditBatchSize+2)/2 || len(page.Items) != 10 {
+		t.Errorf("Find(denied) = %d of %d entries, want 10 of %d", len(page.Items), page.Total, (auditBatchSize+2)/2)
+	}
+	if first := page.Items[0]; first.Sequence != auditBatchSize+2 || first.Actor != audit.ActorAnonymous {
+		t.Errorf("First entry = #%d by %s, want the newest, anonymous", first.Sequence, first.Actor)
+	}
+
+	// Exports read every batch, in either order
+	for _, order := range []string{shared.SortAsc, shared.SortDesc} {
+		var sequences []uint64
+		err := log.Each(ctx, audit.Criteria{Actor: "apikey:1", OrderDir: order, Limit: 1}, func(e *audit.Entry) error {
+			sequences = append(sequences, e.Sequence)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Each(%s) error = %v", order, err)
+		}
+		if len(sequences) != (auditBatchSize+2)/2 {
+			t.Fatalf("Each(%s) = %d entries, want %d", order, len(sequences), (auditBatchSize+2)/2)
+		}
+		for i := 1; i < len(sequences); i++ {
+			if (order == shared.SortAsc) != (sequences[i] > sequences[i-1]) {
+				t.Fatalf("Each(%s) yields #%d after #%d", order, sequences[i], sequences[i-1])
+			}
+		}
+	}
+
+	// Entries can be neither changed nor removed
+	if err := db.Model(&models.AuditEntry{}).Where("id = 1").Update("actor", "someone else").Error; err == nil {
+		t.Error("Update() succeeded, want the audit log to reject it")
+	}
+	if err := db.Where("id = 1").Delete(&models.AuditEntry{}).Error; err == nil {
+		t.Error("Delete() succeeded, want the audit log to reject it")
+	}
+}
@@ -0,0 +1,98 @@
+package persistence
+
+import (
+	"context"
+
+	"parrotflow/internal/domain/deadletter"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/models"
+	"parrotflow/internal/ports"
+
+	"gorm.io/gorm"
+)
+
+type DeadLetterRepository struct {
+	db     *gorm.DB
+	cipher ports.Cipher // Encrypts payloads at rest
+}
+
+func NewDeadLetterRepository(db *gorm.DB, cipher ports.Cipher) *DeadLetterRepository {
+	return &DeadLetterRepository{db: db, cipher: cipher}
+}
+
+func (r *DeadLetterRepository) Save(ctx context.Context, d *deadletter.DeadLetter) error {
+	model, err := ports.DeadLetterDomainEntityToPersistence(d, r.cipher)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, model, "dead letter"); err != nil {
+			return err
+		}
+
+		// Record pending domain events in the same transaction
+		return appendOutboxEvents(tx, d.Events, ports.DeadLetterFormatID(model.ID))
+	})
+	if err != nil {
+		return err
+	}
+
+	// A newly collected dead letter takes over the stored ID
+	id, err := deadletter.NewDeadLetterID(ports.DeadLetterFormatID(model.ID))
+	if err != nil {
+		return err
+	}
+	d.Id = id
+	d.Version = model.Version
+	return nil
+}
+
+func (r *DeadLetterRepository) FindByID(ctx context.Context, id deadletter.DeadLetterID) (*deadletter.DeadLetter, error) {
+	var model models.MessageDeadLetter
+	if err := r.db.WithContext(ctx).Where("id = ?", ports.DeadLetterParseID(id.String())).First(&model).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, deadletter.ErrDeadLetterNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&model)
+}
+
+func (r *DeadLetterRepository) Find(ctx context.Context, criteria deadletter.Criteria) (shared.Page[*deadletter.DeadLetter], error) {
+	var page shared.Page[*deadletter.DeadLetter]
+	var models []models.MessageDeadLetter
+	query := r.db.WithContext(ctx)
+
+	if criteria.Queue != "" {
+		query = query.Where("queue = ?", criteria.Queue)
+	}
+	if criteria.Status != nil {
+		query = query.Where("status = ?", criteria.Status.String())
+	}
+
+	total, err := countTotal(query, &models)
+	if err != nil {
+		return page, err
+	}
+	page.Total = total
+
+	query = query.Order("created_at DESC, id DESC")
+	if err := applyPage(query, criteria.Limit, criteria.Offset).Find(&models).Error; err != nil {
+		return page, err
+	}
+
+	page.Items, err = ConvertSliceToDomainPtr(models, r.toDomain)
+	return page, err
+}
+
+// ReencryptPayloads encrypts the payloads not encrypted with the primary key yet and
+// returns how many it changed
+func (r *DeadLetterRepository) ReencryptPayloads(ctx context.Context) (int, error) {
+	return reencrypt(r.db.WithContext(ctx), &models.MessageDeadLetter{}, "payload", "payload_key_id", r.cipher)
+}
+
+func (r *DeadLetterRepository) toDomain(model *models.MessageDeadLetter) (*deadletter.DeadLetter, error) {
+	return ports.DeadLetterPersistenceToDomainEntity(model, r.cipher)
+}
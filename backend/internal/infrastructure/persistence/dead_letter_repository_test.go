@@ -0,0 +1,59 @@
+package persistence
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"parrotflow/internal/domain/deadletter"
+	"parrotflow/internal/infrastructure/encryption"
+	"parrotflow/internal/models"
+)
+
+func TestDeadLetterRepository_SealsPayloads(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.MessageDeadLetter{}, &models.OutboxEvent{}, &models.EventLogEntry{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	first, _ := encryption.GenerateKey("k1")
+	keyring, err := encryption.NewKeyring(encryption.Config{Keys: first})
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	ctx := context.Background()
+
+	// Execution requests carry the resolved secret values
+	payload := []byte(`{"run_id":"7","parameters":{"password":"hunter2"}}`)
+	d, err := deadletter.NewDeadLetter(deadletter.DeadLetterID{}, "agent.requests", "rejected", payload, time.Now())
+	if err != nil {
+		t.Fatalf("NewDeadLetter() error = %v", err)
+	}
+	if err := NewDeadLetterRepository(db, keyring).Save(ctx, d); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	var stored models.MessageDeadLetter
+	db.First(&stored)
+	if strings.Contains(string(stored.Payload), "hunter2") || stored.PayloadKeyID != "k1" {
+		t.Errorf("Stored payload = %q with key %q, want it encrypted with k1", stored.Payload, stored.PayloadKeyID)
+	}
+
+	// Rotating to a new primary key keeps the payload readable
+	second, _ := encryption.GenerateKey("k2")
+	rotated, err := encryption.NewKeyring(encryption.Config{Keys: second + "," + first})
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	repository := NewDeadLetterRepository(db, rotated)
+	if n, err := repository.ReencryptPayloads(ctx); err != nil || n != 1 {
+		t.Fatalf("ReencryptPayloads() = %d, %v, want 1 payload re-encrypted", n, err)
+	}
+	found, err := repository.FindByID(ctx, d.Id)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if string(found.Payload) != string(payload) {
+		t.Errorf("Payload = %q, want it decrypted", found.Payload)
+	}
+}
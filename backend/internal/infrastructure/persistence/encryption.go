@@ -0,0 +1,50 @@
+package persistence
+
+import (
+	"fmt"
+
+	"parrotflow/internal/ports"
+
+	"gorm.io/gorm"
+)
+
+// sealedValue is one encrypted column of a row with the ID of its key
+type sealedValue struct {
+	ID    uint64
+	Value string
+	KeyID string
+}
+
+// reencrypt encrypts the values of column that are not encrypted with the cipher's
+// primary key yet, recording the key in keyColumn, and returns how many it changed
+// Only the stored form changes, so versions are left alone; trashed rows are included
+func reencrypt(db *gorm.DB, model any, column, keyColumn string, cipher ports.Cipher) (int, error) {
+	var stale []sealedValue
+	err := db.Unscoped().Model(model).
+		Select("id", column+" AS value", keyColumn+" AS key_id").
+		Where(column+" <> '' AND "+keyColumn+" <> ?", cipher.PrimaryKeyID()).
+		Scan(&stale).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, row := range stale {
+		plaintext, err := cipher.Decrypt(row.Value, row.KeyID)
+		if err != nil {
+			return 0, fmt.Errorf("row %d: %w", row.ID, err)
+		}
+		value, keyID, err := cipher.Encrypt(plaintext)
+		if err != nil {
+			return 0, err
+		}
+
+		// Matching the old key skips rows a concurrent save already re-encrypted
+		err = db.Unscoped().Model(model).
+			Where("id = ? AND "+keyColumn+" = ?", row.ID, row.KeyID).
+			UpdateColumns(map[string]any{column: value, keyColumn: keyID}).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(stale), nil
+}
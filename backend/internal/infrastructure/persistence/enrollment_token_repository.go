@@ -0,0 +1,72 @@
+package persistence
+
+import (
+	"context"
+
+	"parrotflow/internal/domain/enrollment"
+	"parrotflow/internal/models"
+	"parrotflow/internal/ports"
+
+	"gorm.io/gorm"
+)
+
+type EnrollmentTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewEnrollmentTokenRepository(db *gorm.DB) *EnrollmentTokenRepository {
+	return &EnrollmentTokenRepository{db: db}
+}
+
+func (r *EnrollmentTokenRepository) Save(ctx context.Context, t *enrollment.Token) error {
+	model := ports.EnrollmentTokenDomainEntityToPersistence(t)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, model, "enrollment token"); err != nil {
+			return err
+		}
+
+		// Record pending domain events in the same transaction
+		return appendOutboxEvents(tx, t.Events, ports.EnrollmentTokenFormatID(model.ID))
+	})
+	if err != nil {
+		return err
+	}
+
+	id, err := enrollment.NewTokenID(ports.EnrollmentTokenFormatID(model.ID))
+	if err != nil {
+		return err
+	}
+	t.Id = id
+	t.Version = model.Version
+	return nil
+}
+
+func (r *EnrollmentTokenRepository) FindByID(ctx context.Context, id enrollment.TokenID) (*enrollment.Token, error) {
+	return r.findOne(ctx, "id = ?", ports.EnrollmentTokenParseID(id.String()))
+}
+
+func (r *EnrollmentTokenRepository) FindByPrefix(ctx context.Context, prefix string) (*enrollment.Token, error) {
+	return r.findOne(ctx, "prefix = ?", prefix)
+}
+
+func (r *EnrollmentTokenRepository) findOne(ctx context.Context, condition string, value any) (*enrollment.Token, error) {
+	var model models.EnrollmentToken
+	if err := r.db.WithContext(ctx).Where(condition, value).First(&model).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, enrollment.ErrTokenNotFound
+		}
+		return nil, err
+	}
+
+	return ports.EnrollmentTokenPersistenceToDomainEntity(&model)
+}
+
+func (r *EnrollmentTokenRepository) FindAll(ctx context.Context) ([]*enrollment.Token, error) {
+	var models []models.EnrollmentToken
+	if err := r.db.WithContext(ctx).Order("created_at ASC, id ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return ConvertSliceToDomainPtr(models, ports.EnrollmentTokenPersistenceToDomainEntity)
+}
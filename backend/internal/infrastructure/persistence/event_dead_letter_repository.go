@@ -0,0 +1,40 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// EventDeadLetterRepository stores events that event bus handlers gave up on,
+// as envelopes so they can be decoded and handled again
+type EventDeadLetterRepository struct {
+	db *gorm.DB
+}
+
+func NewEventDeadLetterRepository(db *gorm.DB) *EventDeadLetterRepository {
+	return &EventDeadLetterRepository{db: db}
+}
+
+func (r *EventDeadLetterRepository) DeadLetter(ctx context.Context, event shared.DomainEvent, handler string, cause error) error {
+	envelope, err := shared.NewEnvelope(event)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Create(&models.EventDeadLetter{
+		EventID:   event.EventID(),
+		EventType: event.EventType(),
+		Handler:   handler,
+		Envelope:  string(data),
+		Error:     cause.Error(),
+	}).Error
+}
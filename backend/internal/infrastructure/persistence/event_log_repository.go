@@ -0,0 +1,72 @@
+package persistence
+
+import (
+	"context"
+	"strings"
+
+	"parrotflow/internal/domain/eventlog"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/models"
+	"parrotflow/internal/ports"
+
+	"gorm.io/gorm"
+)
+
+type EventLogRepository struct {
+	db *gorm.DB
+}
+
+func NewEventLogRepository(db *gorm.DB) *EventLogRepository {
+	return &EventLogRepository{db: db}
+}
+
+func (r *EventLogRepository) Find(ctx context.Context, criteria eventlog.Criteria) (shared.Page[*eventlog.Entry], error) {
+	var page shared.Page[*eventlog.Entry]
+	var entries []models.EventLogEntry
+	query := r.db.WithContext(ctx)
+
+	if len(criteria.EventTypes) > 0 {
+		query = query.Where("event_type IN ?", criteria.EventTypes)
+	}
+	if criteria.AggregateType != "" {
+		query = query.Where("aggregate_type = ?", criteria.AggregateType)
+	}
+	if criteria.AggregateID != "" {
+		query = query.Where("aggregate_id = ?", criteria.AggregateID)
+	}
+	if criteria.CorrelationID != "" {
+		query = query.Where("correlation_id = ?", criteria.CorrelationID)
+	}
+	if !criteria.Since.IsZero() {
+		query = query.Where("occurred_at >= ?", criteria.Since)
+	}
+	if !criteria.Until.IsZero() {
+		query = query.Where("occurred_at < ?", criteria.Until)
+	}
+
+	total, err := countTotal(query, &entries)
+	if err != nil {
+		return page, err
+	}
+	page.Total = total
+
+	// Events raised together share a timestamp, the sequence keeps them in recording order
+	if strings.EqualFold(criteria.OrderDir, shared.SortAsc) {
+		query = query.Order("occurred_at ASC, id ASC")
+	} else {
+		query = query.Order("occurred_at DESC, id DESC")
+	}
+	if err := applyPage(query, criteria.Limit, criteria.Offset).Find(&entries).Error; err != nil {
+		return page, err
+	}
+
+	page.Items, err = ConvertSliceToDomainPtr(entries, ports.EventLogPersistenceToDomain)
+	return page, err
+}
+
+// LastSequence returns the sequence of the last event recorded in the event log, 0 when it is empty
+func (r *EventLogRepository) LastSequence(ctx context.Context) (uint64, error) {
+	var last uint64
+	err := r.db.WithContext(ctx).Model(&models.EventLogEntry{}).Select("COALESCE(MAX(id), 0)").Scan(&last).Error
+	return last, err
+}
@@ -0,0 +1,136 @@
+package persistence
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"parrotflow/internal/domain/eventlog"
+	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/models"
+	"parrotflow/internal/ports"
+)
+
+func TestEventLog_RecordsHistoryUnderStoredID(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.OutboxEvent{}, &models.EventLogEntry{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	ctx := context.Background()
+	scenarios := NewScenarioRepository(db)
+	log := NewEventLogRepository(db)
+
+	// A new scenario raises its created event with a provisional ID
+	id, _ := scenario.NewScenarioID("provisional")
+	s, err := scenario.NewScenario(id, "logged")
+	if err != nil {
+		t.Fatalf("NewScenario() error = %v", err)
+	}
+	if err := scenarios.Save(ctx, s); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// The scenario and the payload of its event take over the stored ID
+	if s.Id.String() != "1" {
+		t.Errorf("Saved scenario ID = %s, want the stored ID 1", s.Id)
+	}
+	var created models.OutboxEvent
+	db.Where("event_type = ?", scenario.EventScenarioCreated).First(&created)
+	if strings.Contains(created.Payload, "provisional") || !strings.Contains(created.Payload, `"1"`) {
+		t.Errorf("Created payload = %s, want the provisional ID replaced with 1", created.Payload)
+	}
+
+	// Events that are already recorded are not recorded twice
+	restored := scenario.ScenarioRestored{
+		BaseEvent:  shared.NewBaseEvent(scenario.EventScenarioRestored, "1"),
+		ScenarioID: "1",
+	}
+	if err := NewOutboxRepository(db).Append(ctx, append(s.Events, restored)...); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	history, err := log.Find(ctx, eventlog.Criteria{AggregateType: eventlog.AggregateScenario, AggregateID: "1", OrderDir: shared.SortAsc})
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if history.Total != 2 || len(history.Items) != 2 {
+		t.Fatalf("History has %d events (total %d), want 2", len(history.Items), history.Total)
+	}
+	if first := history.Items[0]; first.Type != scenario.EventScenarioCreated || first.AggregateType != "scenario" || first.Sequence == 0 {
+		t.Errorf("First event = %s/%s #%d, want scenario.created/scenario", first.Type, first.AggregateType, first.Sequence)
+	}
+	if history.Items[1].ID != restored.EventID() {
+		t.Errorf("Second event = %s, want the restored event", history.Items[1].Type)
+	}
+
+	// Pending outbox events carry their sequence, which streams use as event IDs
+	pending, err := NewOutboxRepository(db).FetchPending(ctx, 10, time.Now().Add(time.Minute))
+	if err != nil || len(pending) != 2 || pending[1].Sequence != history.Items[1].Sequence {
+		t.Errorf("FetchPending() = %+v, %v; want both events with their sequence", pending, err)
+	}
+	if last, err := log.LastSequence(ctx); err != nil || last != history.Items[1].Sequence {
+		t.Errorf("LastSequence() = %d, %v; want %d", last, err, history.Items[1].Sequence)
+	}
+
+	// Filters narrow the log down
+	filtered, err := log.Find(ctx, eventlog.Criteria{EventTypes: []string{scenario.EventScenarioRestored}})
+	if err != nil || filtered.Total != 1 {
+		t.Errorf("Find(type) = %d events, %v; want 1", filtered.Total, err)
+	}
+	future, err := log.Find(ctx, eventlog.Criteria{Since: time.Now().Add(time.Hour)})
+	if err != nil || future.Total != 0 {
+		t.Errorf("Find(since) = %d events, %v; want 0", future.Total, err)
+	}
+}
+
+func TestEventLog_RecordsCorrelationOfSavingContext(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.OutboxEvent{}, &models.EventLogEntry{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	correlation := shared.Correlation{CorrelationID: "request-1", CausationID: "request-1"}
+	ctx := shared.ContextWithCorrelation(context.Background(), correlation)
+	scenarios := NewScenarioRepository(db)
+
+	id, _ := scenario.NewScenarioID("provisional")
+	s, err := scenario.NewScenario(id, "correlated")
+	if err != nil {
+		t.Fatalf("NewScenario() error = %v", err)
+	}
+	if err := scenarios.Save(ctx, s); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	// Saved without a correlation, so it must not join the chain
+	other, _ := scenario.NewScenario(id, "uncorrelated")
+	if err := scenarios.Save(context.Background(), other); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	chain, err := NewEventLogRepository(db).Find(context.Background(), eventlog.Criteria{CorrelationID: "request-1"})
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if chain.Total != 1 || len(chain.Items) != 1 {
+		t.Fatalf("Correlation has %d events, want 1", chain.Total)
+	}
+	if entry := chain.Items[0]; entry.CausationID != "request-1" {
+		t.Errorf("Causation = %q, want request-1", entry.CausationID)
+	}
+
+	// The relay rebuilds events with their correlation
+	var pending []models.OutboxEvent
+	if err := db.Where("correlation_id = ?", "request-1").Find(&pending).Error; err != nil || len(pending) != 1 {
+		t.Fatalf("Outbox has %d correlated events, %v; want 1", len(pending), err)
+	}
+	registry := shared.NewEventRegistry()
+	shared.RegisterEvent[scenario.ScenarioCreated](registry, scenario.EventScenarioCreated)
+	event, err := registry.Decode(ports.OutboxPersistenceToEnvelope(&pending[0]))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got := shared.CorrelationOf(event); got != correlation {
+		t.Errorf("Decoded correlation = %+v, want %+v", got, correlation)
+	}
+}
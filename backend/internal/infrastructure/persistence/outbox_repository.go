@@ -0,0 +1,119 @@
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/tracing"
+	"parrotflow/internal/models"
+	"parrotflow/internal/ports"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// Append stores events in the outbox outside of an aggregate transaction
+// Events that are already stored are ignored, so it is safe to call after Save
+func (r *OutboxRepository) Append(ctx context.Context, events ...shared.DomainEvent) error {
+	return appendOutboxEvents(r.db.WithContext(ctx), events, "")
+}
+
+// FetchPending retrieves pending events whose next attempt is due, oldest first,
+// with their event log sequence
+func (r *OutboxRepository) FetchPending(ctx context.Context, limit int, now time.Time) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := r.db.WithContext(ctx).
+		Select("outbox_events.*, event_log.id AS sequence").
+		Joins("LEFT JOIN event_log ON event_log.event_id = outbox_events.event_id").
+		Where("outbox_events.status = ?", models.OutboxStatusPending).
+		Where("outbox_events.next_attempt_at <= ?", now).
+		Order("outbox_events.occurred_at ASC, outbox_events.id ASC").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+func (r *OutboxRepository) MarkDelivered(ctx context.Context, id uint64, deliveredAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.OutboxEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       models.OutboxStatusDelivered,
+		"delivered_at": deliveredAt,
+		"last_error":   "",
+	}).Error
+}
+
+// MarkSinkDelivered records the sinks that accepted an event still pending for others
+func (r *OutboxRepository) MarkSinkDelivered(ctx context.Context, id uint64, sinks []string) error {
+	delivered, err := json.Marshal(sinks)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Model(&models.OutboxEvent{}).Where("id = ?", id).
+		Update("delivered_sinks", string(delivered)).Error
+}
+
+// MarkFailed records a failed delivery attempt
+// The event stays pending until nextAttemptAt unless status is failed
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uint64, status string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return r.db.WithContext(ctx).Model(&models.OutboxEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":          status,
+		"attempts":        attempts,
+		"next_attempt_at": nextAttemptAt,
+		"last_error":      lastError,
+	}).Error
+}
+
+// appendOutboxEvents writes events to the outbox and the event log using the given
+// handle, which is usually the transaction that persists the aggregate raising them
+// aggregateID, when set, is the stored ID of that aggregate; it replaces the ID the
+// events were raised with, which is provisional for aggregates created in this transaction,
+// in the envelope and in the payload. Provisional IDs are UUIDs, so their quoted form
+// only occurs in the payload where the aggregate's ID was written
+// Events are tied to the correlation of the handle's context, i.e. the request saving them
+func appendOutboxEvents(tx *gorm.DB, events []shared.DomainEvent, aggregateID string) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows := make([]*models.OutboxEvent, 0, len(events))
+	entries := make([]*models.EventLogEntry, 0, len(events))
+	for _, event := range events {
+		envelope, err := shared.NewEnvelope(shared.Correlate(tx.Statement.Context, event))
+		if err != nil {
+			return err
+		}
+		if aggregateID != "" && envelope.AggregateID != aggregateID {
+			if envelope.AggregateID != "" {
+				envelope.Payload = bytes.ReplaceAll(envelope.Payload, quoted(envelope.AggregateID), quoted(aggregateID))
+			}
+			envelope.AggregateID = aggregateID
+		}
+		row := ports.OutboxEnvelopeToPersistence(envelope)
+		row.TraceParent = tracing.TraceParent(tx.Statement.Context)
+		rows = append(rows, row)
+		entries = append(entries, ports.EventLogEnvelopeToPersistence(envelope))
+	}
+
+	ignoreRecorded := clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}},
+		DoNothing: true,
+	}
+	if err := tx.Clauses(ignoreRecorded).Create(&rows).Error; err != nil {
+		return err
+	}
+	return tx.Clauses(ignoreRecorded).Create(&entries).Error
+}
+
+// quoted is id as a JSON string
+func quoted(id string) []byte {
+	return []byte(`"` + id + `"`)
+}
@@ -0,0 +1,61 @@
+package persistence
+
+import (
+	"fmt"
+	"strings"
+
+	"parrotflow/internal/domain/shared"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sortColumns maps the sortable fields a domain exposes to table columns
+// Only fields listed here ever reach the ORDER BY clause
+type sortColumns map[string]string
+
+// applySort orders by an allowlisted field, newest first by default, with id as a
+// tiebreaker so pages stay stable when several rows share the same value
+func applySort(query *gorm.DB, orderBy, orderDir string, columns sortColumns) (*gorm.DB, error) {
+	if orderBy == "" {
+		orderBy = "created_at"
+	}
+	column, ok := columns[orderBy]
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot sort by %q", shared.ErrInvalidPageRequest, orderBy)
+	}
+
+	desc := !strings.EqualFold(orderDir, shared.SortAsc)
+	return query.
+		Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: desc}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: desc}), nil
+}
+
+// applyPage limits the query to one page; a zero limit returns every row
+func applyPage(query *gorm.DB, limit, offset int) *gorm.DB {
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+	return query
+}
+
+// countTotal counts the rows matching the filters so far, on a copy of the query
+// so the caller can keep chaining ordering and paging onto the original
+func countTotal(query *gorm.DB, model interface{}) (int64, error) {
+	var total int64
+	err := query.Session(&gorm.Session{}).Model(model).Count(&total).Error
+	return total, err
+}
+
+// applyCursor keeps the rows strictly after the cursor in (created_at, id) descending order
+func applyCursor(query *gorm.DB, token string, parseID func(string) uint64) (*gorm.DB, error) {
+	cursor, err := shared.DecodeCursor(token)
+	if err != nil {
+		return nil, err
+	}
+	return query.Where("(created_at < ? OR (created_at = ? AND id < ?))",
+		cursor.CreatedAt, cursor.CreatedAt, parseID(cursor.ID)), nil
+}
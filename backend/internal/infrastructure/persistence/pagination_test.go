@@ -0,0 +1,120 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	// Every connection to :memory: opens its own empty database
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.Scenario{}, &models.ScenarioRun{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	return db
+}
+
+// seedRuns inserts count runs for scenario 1, one minute apart, with two sharing
+// the same created_at so the id tiebreaker is exercised
+func seedRuns(t *testing.T, db *gorm.DB, count int) {
+	t.Helper()
+	if err := db.Create(&models.Scenario{ScenarioBase: models.ScenarioBase{Name: "paged"}}).Error; err != nil {
+		t.Fatalf("Create(scenario) error = %v", err)
+	}
+
+	start := time.Date(2024, 10, 27, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < count; i++ {
+		createdAt := start.Add(time.Duration(i) * time.Minute)
+		if i == count-1 {
+			createdAt = start.Add(time.Duration(i-1) * time.Minute)
+		}
+		model := models.ScenarioRun{
+			Model:      models.Model{CreatedAt: createdAt},
+			ScenarioID: 1,
+			Status:     "COMPLETED",
+			StartedAt:  createdAt,
+			Parameters: "{}",
+		}
+		if err := db.Create(&model).Error; err != nil {
+			t.Fatalf("Create(run) error = %v", err)
+		}
+	}
+}
+
+func TestRunRepository_FindAll_CursorWalksEveryRunOnce(t *testing.T) {
+	db := newTestDB(t)
+	seedRuns(t, db, 5)
+	repo := NewRunRepository(db)
+
+	seen := make(map[string]bool)
+	criteria := run.NewSearchCriteria().WithPagination(2, 0)
+	for pages := 0; pages < 5; pages++ {
+		page, err := repo.FindAll(context.Background(), criteria)
+		if err != nil {
+			t.Fatalf("FindAll() error = %v", err)
+		}
+		if page.Total != 5 {
+			t.Errorf("FindAll() total = %d, want 5", page.Total)
+		}
+		for _, r := range page.Items {
+			if seen[r.Id.String()] {
+				t.Errorf("Run %s returned twice", r.Id.String())
+			}
+			seen[r.Id.String()] = true
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		criteria = criteria.WithCursor(page.NextCursor)
+	}
+
+	if len(seen) != 5 {
+		t.Errorf("Runs seen = %d, want 5", len(seen))
+	}
+}
+
+func TestRunRepository_FindAll_OffsetPageHasTotal(t *testing.T) {
+	db := newTestDB(t)
+	seedRuns(t, db, 5)
+	repo := NewRunRepository(db)
+
+	page, err := repo.FindAll(context.Background(), run.NewSearchCriteria().WithPagination(2, 4))
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	if len(page.Items) != 1 || page.Total != 5 || page.NextCursor != "" {
+		t.Errorf("FindAll() = %d items, total %d, cursor %q; want 1, 5 and none",
+			len(page.Items), page.Total, page.NextCursor)
+	}
+}
+
+func TestScenarioRepository_FindAll_RejectsUnknownSortField(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewScenarioRepository(db)
+
+	criteria := scenario.NewSearchCriteria().WithOrdering("name; DROP TABLE scenarios", "asc")
+	_, err := repo.FindAll(context.Background(), criteria)
+	if !errors.Is(err, shared.ErrInvalidPageRequest) {
+		t.Errorf("FindAll() error = %v, want ErrInvalidPageRequest", err)
+	}
+	if !db.Migrator().HasTable(&models.Scenario{}) {
+		t.Error("Scenarios table was dropped")
+	}
+}
@@ -3,7 +3,10 @@ package persistence
 import (
 	"context"
 	"errors"
+	"time"
+
 	"parrotflow/internal/domain/proxy"
+	"parrotflow/internal/domain/shared"
 	"parrotflow/internal/domain/tag"
 	"parrotflow/internal/models"
 	"parrotflow/internal/ports"
@@ -12,42 +15,86 @@ import (
 )
 
 type ProxyRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	cipher ports.Cipher // Encrypts passwords at rest
 }
 
-func NewProxyRepository(db *gorm.DB) *ProxyRepository {
-	return &ProxyRepository{db: db}
+func NewProxyRepository(db *gorm.DB, cipher ports.Cipher) *ProxyRepository {
+	return &ProxyRepository{db: db, cipher: cipher}
 }
 
 func (r *ProxyRepository) Save(ctx context.Context, p *proxy.Proxy) error {
-	model, err := ports.ProxyDomainEntityToPersistence(p)
+	model, err := ports.ProxyDomainEntityToPersistence(p, r.cipher)
 	if err != nil {
 		return err
 	}
 
-	// Load tags from IDs
-	if len(p.Tags) > 0 {
-		tagIDs := make([]uint64, len(p.Tags))
-		for i, tagID := range p.Tags {
-			tagIDs[i] = ports.TagParseID(tagID.String())
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkPasswordEncrypted(tx, model); err != nil {
+			return err
 		}
-		var tags []models.Tag
-		if err := r.db.WithContext(ctx).Where("id IN ?", tagIDs).Find(&tags).Error; err != nil {
+
+		// Load tags from IDs
+		if len(p.Tags) > 0 {
+			tagIDs := make([]uint64, len(p.Tags))
+			for i, tagID := range p.Tags {
+				tagIDs[i] = ports.TagParseID(tagID.String())
+			}
+			var tags []models.Tag
+			if err := tx.Where("id IN ?", tagIDs).Find(&tags).Error; err != nil {
+				return err
+			}
+			model.Tags = tags
+		}
+
+		// Use Association for many-to-many relationship
+		if err := saveVersioned(tx, model, "proxy"); err != nil {
+			return err
+		}
+
+		// Update associations
+		if err := tx.Model(model).Association("Tags").Replace(model.Tags); err != nil {
 			return err
 		}
-		model.Tags = tags
-	}
 
-	// Use Association for many-to-many relationship
-	if err = r.db.WithContext(ctx).Save(model).Error; err != nil {
+		// Record pending domain events in the same transaction
+		return appendOutboxEvents(tx, p.Events, ports.ProxyFormatID(model.ID))
+	})
+	if err != nil {
 		return err
 	}
 
-	// Update associations
-	if err = r.db.WithContext(ctx).Model(model).Association("Tags").Replace(model.Tags); err != nil {
+	// A new proxy takes over the stored ID, like its recorded events
+	id, err := proxy.NewProxyID(ports.ProxyFormatID(model.ID))
+	if err != nil {
 		return err
 	}
+	p.Id = id
+	p.Version = model.Version
+	return nil
+}
 
+// checkPasswordEncrypted refuses to write a password in plaintext; without keys only a
+// password stored before encryption was set up can be kept, as it is
+func checkPasswordEncrypted(tx *gorm.DB, model *models.Proxy) error {
+	if model.Password == "" || model.PasswordKeyID != "" {
+		return nil
+	}
+	if model.ID == 0 {
+		return shared.ErrEncryptionRequired
+	}
+
+	var stored models.Proxy
+	err := tx.Unscoped().Select("password", "password_key_id").Where("id = ?", model.ID).Take(&stored).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return shared.ErrEncryptionRequired
+	}
+	if err != nil {
+		return err
+	}
+	if stored.PasswordKeyID != "" || stored.Password != model.Password {
+		return shared.ErrEncryptionRequired
+	}
 	return nil
 }
 
@@ -60,7 +107,7 @@ func (r *ProxyRepository) FindByID(ctx context.Context, id proxy.ProxyID) (*prox
 		return nil, err
 	}
 
-	return ports.ProxyPersistenceToDomainEntity(&model)
+	return r.toDomain(&model)
 }
 
 func (r *ProxyRepository) FindByName(ctx context.Context, name string) (*proxy.Proxy, error) {
@@ -72,7 +119,7 @@ func (r *ProxyRepository) FindByName(ctx context.Context, name string) (*proxy.P
 		return nil, err
 	}
 
-	return ports.ProxyPersistenceToDomainEntity(&model)
+	return r.toDomain(&model)
 }
 
 func (r *ProxyRepository) FindAll(ctx context.Context) ([]*proxy.Proxy, error) {
@@ -83,7 +130,7 @@ func (r *ProxyRepository) FindAll(ctx context.Context) ([]*proxy.Proxy, error) {
 
 	proxies := make([]*proxy.Proxy, len(models))
 	for i, model := range models {
-		p, err := ports.ProxyPersistenceToDomainEntity(&model)
+		p, err := r.toDomain(&model)
 		if err != nil {
 			return nil, err
 		}
@@ -101,7 +148,7 @@ func (r *ProxyRepository) FindByStatus(ctx context.Context, status proxy.ProxySt
 
 	proxies := make([]*proxy.Proxy, len(models))
 	for i, model := range models {
-		p, err := ports.ProxyPersistenceToDomainEntity(&model)
+		p, err := r.toDomain(&model)
 		if err != nil {
 			return nil, err
 		}
@@ -137,7 +184,7 @@ func (r *ProxyRepository) FindByTags(ctx context.Context, tagIDs []tag.TagID) ([
 
 	proxies := make([]*proxy.Proxy, len(models))
 	for i, model := range models {
-		p, err := ports.ProxyPersistenceToDomainEntity(&model)
+		p, err := r.toDomain(&model)
 		if err != nil {
 			return nil, err
 		}
@@ -151,12 +198,43 @@ func (r *ProxyRepository) FindActive(ctx context.Context) ([]*proxy.Proxy, error
 	return r.FindByStatus(ctx, proxy.ProxyStatusActive)
 }
 
+func (r *ProxyRepository) FindTrashed(ctx context.Context) ([]*proxy.Proxy, error) {
+	var models []models.Proxy
+	if err := r.db.WithContext(ctx).Unscoped().Preload("Tags").Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return ConvertSliceToDomainPtr(models, r.toDomain)
+}
+
 func (r *ProxyRepository) Delete(ctx context.Context, id proxy.ProxyID) error {
 	return r.db.WithContext(ctx).Where("id = ?", ports.ProxyParseID(id.String())).Delete(&models.Proxy{}).Error
 }
 
+func (r *ProxyRepository) Restore(ctx context.Context, id proxy.ProxyID) error {
+	return restoreTrashed(r.db.WithContext(ctx), &models.Proxy{}, ports.ProxyParseID(id.String()), proxy.ErrProxyNotFound)
+}
+
+// PurgeDeleted permanently removes proxies trashed before the cutoff
+func (r *ProxyRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return purgeTrashed(r.db.WithContext(ctx), &models.Proxy{}, before, func(tx *gorm.DB, ids []uint64) error {
+		return tx.Exec("DELETE FROM proxy_tags WHERE proxy_id IN ?", ids).Error
+	})
+}
+
+// Exists also counts trashed proxies, since their names stay reserved until purged
 func (r *ProxyRepository) Exists(ctx context.Context, name string) (bool, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&models.Proxy{}).Where("name = ?", name).Count(&count).Error
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.Proxy{}).Where("name = ?", name).Count(&count).Error
 	return count > 0, err
 }
+
+func (r *ProxyRepository) toDomain(model *models.Proxy) (*proxy.Proxy, error) {
+	return ports.ProxyPersistenceToDomainEntity(model, r.cipher)
+}
+
+// ReencryptPasswords encrypts the passwords not encrypted with the primary key yet,
+// trashed proxies included, and returns how many it changed
+func (r *ProxyRepository) ReencryptPasswords(ctx context.Context) (int, error) {
+	return reencrypt(r.db.WithContext(ctx), &models.Proxy{}, "password", "password_key_id", r.cipher)
+}
@@ -0,0 +1,64 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"parrotflow/internal/domain/proxy"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/encryption"
+	"parrotflow/internal/models"
+)
+
+func TestProxyRepository_RefusesPlaintextPasswords(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.Tag{}, &models.Proxy{}, &models.OutboxEvent{}, &models.EventLogEntry{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	keyring, err := encryption.NewKeyring(encryption.Config{})
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	repository := NewProxyRepository(db, keyring)
+	ctx := context.Background()
+	credentials, _ := proxy.NewProxyCredentials("user", "hunter2")
+
+	// Without keys, proxies without a password are stored as before
+	p, _ := proxy.NewProxy(proxy.ProxyID{}, "eu-1", "10.0.0.1", 3128, proxy.ProtocolHTTP)
+	if err := repository.Save(ctx, p); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// but a password is not written in plaintext, neither on a new proxy nor an existing one
+	withPassword, _ := proxy.NewProxy(proxy.ProxyID{}, "eu-2", "10.0.0.2", 3128, proxy.ProtocolHTTP)
+	withPassword.SetCredentials(credentials)
+	if err := repository.Save(ctx, withPassword); !errors.Is(err, shared.ErrEncryptionRequired) {
+		t.Errorf("Save() of a new proxy with a password error = %v, want %v", err, shared.ErrEncryptionRequired)
+	}
+	p.SetCredentials(credentials)
+	if err := repository.Save(ctx, p); !errors.Is(err, shared.ErrEncryptionRequired) {
+		t.Errorf("Save() of a password error = %v, want %v", err, shared.ErrEncryptionRequired)
+	}
+	var count int64
+	db.Model(&models.Proxy{}).Where("password <> ''").Count(&count)
+	if count != 0 {
+		t.Errorf("%d passwords stored in plaintext, want none", count)
+	}
+
+	// A password stored before encryption was set up still lets the proxy record its health
+	db.Model(&models.Proxy{}).Where("name = ?", "eu-1").Updates(map[string]any{"username": "user", "password": "hunter2"})
+	legacy, err := repository.FindByID(ctx, p.Id)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	legacy.RecordSuccess(12)
+	if err := repository.Save(ctx, legacy); err != nil {
+		t.Errorf("Save() of a proxy with a stored plaintext password error = %v", err)
+	}
+	changed, _ := proxy.NewProxyCredentials("user", "changed")
+	legacy.SetCredentials(changed)
+	if err := repository.Save(ctx, legacy); !errors.Is(err, shared.ErrEncryptionRequired) {
+		t.Errorf("Save() of a changed password error = %v, want %v", err, shared.ErrEncryptionRequired)
+	}
+}
@@ -0,0 +1,77 @@
+package persistence
+
+import (
+	"context"
+
+	"parrotflow/internal/domain/access"
+	"parrotflow/internal/models"
+	"parrotflow/internal/ports"
+
+	"gorm.io/gorm"
+)
+
+type RoleAssignmentRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleAssignmentRepository(db *gorm.DB) *RoleAssignmentRepository {
+	return &RoleAssignmentRepository{db: db}
+}
+
+func (r *RoleAssignmentRepository) Save(ctx context.Context, a *access.RoleAssignment) error {
+	model := ports.RoleAssignmentDomainEntityToPersistence(a)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, model, "role assignment"); err != nil {
+			return err
+		}
+
+		// Record pending domain events in the same transaction
+		return appendOutboxEvents(tx, a.Events, ports.RoleAssignmentFormatID(model.ID))
+	})
+	if err != nil {
+		return err
+	}
+
+	id, err := access.NewRoleAssignmentID(ports.RoleAssignmentFormatID(model.ID))
+	if err != nil {
+		return err
+	}
+	a.Id = id
+	a.Version = model.Version
+	return nil
+}
+
+// FindBySubject runs for every authenticated request, and most JWT subjects have no
+// assignment; Find instead of First keeps those misses out of the GORM log
+func (r *RoleAssignmentRepository) FindBySubject(ctx context.Context, subject string) (*access.RoleAssignment, error) {
+	var model models.RoleAssignment
+	result := r.db.WithContext(ctx).Where("subject = ?", subject).Limit(1).Find(&model)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, access.ErrAssignmentNotFound
+	}
+
+	return ports.RoleAssignmentPersistenceToDomainEntity(&model)
+}
+
+func (r *RoleAssignmentRepository) FindAll(ctx context.Context) ([]*access.RoleAssignment, error) {
+	var models []models.RoleAssignment
+	if err := r.db.WithContext(ctx).Order("subject ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return ConvertSliceToDomainPtr(models, ports.RoleAssignmentPersistenceToDomainEntity)
+}
+
+func (r *RoleAssignmentRepository) Delete(ctx context.Context, a *access.RoleAssignment) error {
+	assignmentID := ports.RoleAssignmentParseID(a.Id.String())
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", assignmentID).Delete(&models.RoleAssignment{}).Error; err != nil {
+			return err
+		}
+		return appendOutboxEvents(tx, a.Events, a.Id.String())
+	})
+}
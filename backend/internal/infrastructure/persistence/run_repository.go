@@ -3,12 +3,16 @@ package persistence
 import (
 	"context"
 	"errors"
+	"fmt"
 	"parrotflow/internal/domain/run"
 	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/shared"
 	"parrotflow/internal/models"
 	"parrotflow/internal/ports"
+	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type RunRepository struct {
@@ -19,19 +23,40 @@ func NewRunRepository(db *gorm.DB) *RunRepository {
 	return &RunRepository{db: db}
 }
 
-func (r *RunRepository) Save(ctx context.Context, run *run.Run) error {
-	model, err := ports.RunDomainEntityToPersistence(run)
+func (r *RunRepository) Save(ctx context.Context, entity *run.Run) error {
+	model, err := ports.RunDomainEntityToPersistence(entity)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, model, "run"); err != nil {
+			return err
+		}
+		if err := saveNodeSteps(tx, model, entity.Events); err != nil {
+			return err
+		}
 
-	if err = r.db.WithContext(ctx).Save(model).Error; err != nil {
+		// Record pending domain events in the same transaction
+		return appendOutboxEvents(tx, entity.Events, ports.RunFormatID(model.ID))
+	})
+	if err != nil {
 		return err
 	}
 
+	// A new run takes over the stored ID, like its recorded events
+	id, err := run.NewRunID(ports.RunFormatID(model.ID))
+	if err != nil {
+		return err
+	}
+	entity.Id = id
+	entity.Version = model.Version
 	return nil
 }
 
 func (r *RunRepository) FindByID(ctx context.Context, id run.RunID) (*run.Run, error) {
 	var model models.ScenarioRun
-	if err := r.db.WithContext(ctx).Where("id = ?", ports.RunParseID(id.String())).First(&model).Error; err != nil {
+	if err := r.db.WithContext(ctx).Scopes(withoutTrashedScenarios).Where("id = ?", ports.RunParseID(id.String())).First(&model).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.New("run not found")
 		}
@@ -43,7 +68,7 @@ func (r *RunRepository) FindByID(ctx context.Context, id run.RunID) (*run.Run, e
 
 func (r *RunRepository) FindByScenarioID(ctx context.Context, scenarioID scenario.ScenarioID) ([]*run.Run, error) {
 	var models []models.ScenarioRun
-	if err := r.db.WithContext(ctx).Where("scenario_id = ?", ports.RunParseID(scenarioID.String())).Find(&models).Error; err != nil {
+	if err := r.db.WithContext(ctx).Scopes(withoutTrashedScenarios).Where("scenario_id = ?", ports.RunParseID(scenarioID.String())).Find(&models).Error; err != nil {
 		return nil, err
 	}
 
@@ -59,9 +84,20 @@ func (r *RunRepository) FindByScenarioID(ctx context.Context, scenarioID scenari
 	return runs, nil
 }
 
-func (r *RunRepository) FindAll(ctx context.Context, criteria run.SearchCriteria) ([]*run.Run, error) {
+// runSortColumns backs run.SortableFields
+var runSortColumns = sortColumns{
+	"created_at":  "created_at",
+	"started_at":  "started_at",
+	"finished_at": "finished_at",
+	"status":      "status",
+}
+
+// FindAll returns one page of runs with the total matching the filters
+// With the default created_at desc ordering the page also carries a cursor for the next one
+func (r *RunRepository) FindAll(ctx context.Context, criteria run.SearchCriteria) (shared.Page[*run.Run], error) {
+	var page shared.Page[*run.Run]
 	var models []models.ScenarioRun
-	query := r.db.WithContext(ctx)
+	query := r.db.WithContext(ctx).Scopes(withoutTrashedScenarios)
 
 	if !criteria.ScenarioID.IsEmpty() {
 		query = query.Where("scenario_id = ?", ports.RunParseID(criteria.ScenarioID.String()))
@@ -70,41 +106,63 @@ func (r *RunRepository) FindAll(ctx context.Context, criteria run.SearchCriteria
 		query = query.Where("status = ?", criteria.Status)
 	}
 
-	orderBy := criteria.OrderBy
-	if orderBy == "" {
-		orderBy = "created_at"
-	}
-	orderDir := criteria.OrderDir
-	if orderDir == "" {
-		orderDir = "desc"
+	total, err := countTotal(query, &models)
+	if err != nil {
+		return page, err
 	}
-	query = query.Order(orderBy + " " + orderDir)
+	page.Total = total
 
-	if criteria.Limit > 0 {
-		query = query.Limit(criteria.Limit)
+	keyset := (criteria.OrderBy == "" || criteria.OrderBy == "created_at") &&
+		!strings.EqualFold(criteria.OrderDir, shared.SortAsc)
+	offset := criteria.Offset
+	if criteria.Cursor != "" {
+		if !keyset {
+			return page, fmt.Errorf("%w: cursor pagination only orders by created_at desc", shared.ErrInvalidPageRequest)
+		}
+		if query, err = applyCursor(query, criteria.Cursor, ports.RunParseID); err != nil {
+			return page, err
+		}
+		offset = 0
 	}
-	if criteria.Offset > 0 {
-		query = query.Offset(criteria.Offset)
+
+	query, err = applySort(query, criteria.OrderBy, criteria.OrderDir, runSortColumns)
+	if err != nil {
+		return page, err
 	}
 
-	if err := query.Find(&models).Error; err != nil {
-		return nil, err
+	// Fetch one extra row to learn whether another page follows
+	limit := criteria.Limit
+	if limit > 0 {
+		limit++
+	}
+	if err := applyPage(query, limit, offset).Find(&models).Error; err != nil {
+		return page, err
 	}
 
-	runs := make([]*run.Run, len(models))
-	for i, model := range models {
-		run, err := ports.RunPersistenceToDomainEntity(&model)
-		if err != nil {
-			return nil, err
-		}
-		runs[i] = run
+	hasMore := criteria.Limit > 0 && len(models) > criteria.Limit
+	if hasMore {
+		models = models[:criteria.Limit]
 	}
 
-	return runs, nil
+	page.Items, err = ConvertSliceToDomainPtr(models, ports.RunPersistenceToDomainEntity)
+	if err != nil {
+		return page, err
+	}
+	if hasMore && keyset {
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = shared.NewCursor(last.CreatedAt.Time(), last.Id.String()).Encode()
+	}
+	return page, nil
 }
 
 func (r *RunRepository) Delete(ctx context.Context, id run.RunID) error {
-	return r.db.WithContext(ctx).Where("id = ?", ports.RunParseID(id.String())).Delete(&models.ScenarioRun{}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		runID := ports.RunParseID(id.String())
+		if err := tx.Where("run_id = ?", runID).Delete(&models.RunNodeStep{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", runID).Delete(&models.ScenarioRun{}).Error
+	})
 }
 
 func (r *RunRepository) Exists(ctx context.Context, id run.RunID) (bool, error) {
@@ -112,3 +170,116 @@ func (r *RunRepository) Exists(ctx context.Context, id run.RunID) (bool, error)
 	err := r.db.WithContext(ctx).Model(&models.ScenarioRun{}).Where("id = ?", ports.RunParseID(id.String())).Count(&count).Error
 	return count > 0, err
 }
+
+// FindNodeSteps returns the node steps of the runs keyed by run ID, in reporting order
+func (r *RunRepository) FindNodeSteps(ctx context.Context, runs []*run.Run) (map[string][]run.NodeStep, error) {
+	ids := make([]uint64, len(runs))
+	for i, rn := range runs {
+		ids[i] = ports.RunParseID(rn.Id.String())
+	}
+
+	var rows []models.RunNodeStep
+	if err := r.db.WithContext(ctx).Where("run_id IN ?", ids).Order("reported_at ASC, id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	steps := make(map[string][]run.NodeStep)
+	for i := range rows {
+		step, err := ports.RunNodeStepPersistenceToDomain(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		runID := ports.RunFormatID(rows[i].RunID)
+		steps[runID] = append(steps[runID], step)
+	}
+	return steps, nil
+}
+
+// Compact permanently removes runs and folds them into the per-scenario run_summaries
+// counters in the same transaction, so statistics survive the detailed rows
+func (r *RunRepository) Compact(ctx context.Context, runs []*run.Run) error {
+	if len(runs) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := make([]uint64, len(runs))
+		for i, rn := range runs {
+			ids[i] = ports.RunParseID(rn.Id.String())
+			if err := addToRunSummary(tx, rn); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("run_id IN ?", ids).Delete(&models.RunNodeStep{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&models.ScenarioRun{}).Error
+	})
+}
+
+// saveNodeSteps keeps the last reported state of every node from the run's progress events
+func saveNodeSteps(tx *gorm.DB, model *models.ScenarioRun, events []shared.DomainEvent) error {
+	steps := make(map[string]*models.RunNodeStep)
+	var order []string
+	for _, event := range events {
+		progress, ok := event.(run.RunProgress)
+		if !ok {
+			continue
+		}
+		if _, seen := steps[progress.NodeID]; !seen {
+			order = append(order, progress.NodeID)
+		}
+		steps[progress.NodeID] = &models.RunNodeStep{
+			Model:      models.Model{Version: 1},
+			RunID:      model.ID,
+			ScenarioID: model.ScenarioID,
+			NodeID:     progress.NodeID,
+			Status:     progress.NodeStatus,
+			Message:    progress.Message,
+			ReportedAt: progress.ReportedAt,
+		}
+	}
+	if len(order) == 0 {
+		return nil
+	}
+
+	rows := make([]*models.RunNodeStep, len(order))
+	for i, nodeID := range order {
+		rows[i] = steps[nodeID]
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "run_id"}, {Name: "node_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "message", "reported_at", "updated_at"}),
+	}).Create(&rows).Error
+}
+
+func addToRunSummary(tx *gorm.DB, rn *run.Run) error {
+	summary := models.RunSummary{
+		ScenarioID: ports.ScenarioParseID(rn.ScenarioID.String()),
+		Status:     rn.Status.String(),
+	}
+	err := tx.Where("scenario_id = ? AND status = ?", summary.ScenarioID, summary.Status).
+		FirstOrInit(&summary).Error
+	if err != nil {
+		return err
+	}
+
+	createdAt := rn.CreatedAt.Time()
+	summary.RunCount++
+	if rn.StartedAt != nil && rn.FinishedAt != nil {
+		summary.TotalDurationMs += rn.FinishedAt.Time().Sub(rn.StartedAt.Time()).Milliseconds()
+	}
+	if summary.FirstRunAt == nil || createdAt.Before(*summary.FirstRunAt) {
+		summary.FirstRunAt = &createdAt
+	}
+	if summary.LastRunAt == nil || createdAt.After(*summary.LastRunAt) {
+		summary.LastRunAt = &createdAt
+	}
+
+	if summary.ID == 0 {
+		summary.Version = 1
+		return tx.Create(&summary).Error
+	}
+	return tx.Save(&summary).Error
+}
@@ -3,7 +3,10 @@ package persistence
 import (
 	"context"
 	"errors"
+	"time"
+
 	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/shared"
 	"parrotflow/internal/models"
 	"parrotflow/internal/ports"
 
@@ -20,11 +23,29 @@ func NewScenarioRepository(db *gorm.DB) *ScenarioRepository {
 
 func (r *ScenarioRepository) Save(ctx context.Context, s *scenario.Scenario) error {
 	model, err := ports.ScenarioDomainEntityToPersistence(s)
+	if err != nil {
+		return err
+	}
 
-	if err = r.db.WithContext(ctx).Save(model).Error; err != nil {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, model, "scenario"); err != nil {
+			return err
+		}
+
+		// Record pending domain events in the same transaction
+		return appendOutboxEvents(tx, s.Events, ports.ScenarioFormatID(model.ID))
+	})
+	if err != nil {
 		return err
 	}
 
+	// A new scenario takes over the stored ID, like its recorded events
+	id, err := scenario.NewScenarioID(ports.ScenarioFormatID(model.ID))
+	if err != nil {
+		return err
+	}
+	s.Id = id
+	s.Version = model.Version
 	return nil
 }
 
@@ -52,10 +73,21 @@ func (r *ScenarioRepository) FindByName(ctx context.Context, name string) (*scen
 	return ports.ScenarioPersistenceToDomainEntity(&model)
 }
 
-func (r *ScenarioRepository) FindAll(ctx context.Context, criteria scenario.SearchCriteria) ([]*scenario.Scenario, error) {
+// scenarioSortColumns backs scenario.SortableFields
+var scenarioSortColumns = sortColumns{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"name":       "name",
+}
+
+func (r *ScenarioRepository) FindAll(ctx context.Context, criteria scenario.SearchCriteria) (shared.Page[*scenario.Scenario], error) {
+	var page shared.Page[*scenario.Scenario]
 	var models []models.Scenario
 	query := r.db.WithContext(ctx)
 
+	if criteria.Trashed {
+		query = query.Unscoped().Where("deleted_at IS NOT NULL")
+	}
 	if criteria.Name != "" {
 		query = query.Where("name LIKE ?", "%"+criteria.Name+"%")
 	}
@@ -63,43 +95,46 @@ func (r *ScenarioRepository) FindAll(ctx context.Context, criteria scenario.Sear
 		query = query.Where("tag = ?", criteria.Tag)
 	}
 
-	orderBy := criteria.OrderBy
-	if orderBy == "" {
-		orderBy = "created_at"
-	}
-	orderDir := criteria.OrderDir
-	if orderDir == "" {
-		orderDir = "desc"
-	}
-	query = query.Order(orderBy + " " + orderDir)
-
-	if criteria.Limit > 0 {
-		query = query.Limit(criteria.Limit)
-	}
-	if criteria.Offset > 0 {
-		query = query.Offset(criteria.Offset)
+	total, err := countTotal(query, &models)
+	if err != nil {
+		return page, err
 	}
+	page.Total = total
 
-	if err := query.Find(&models).Error; err != nil {
-		return nil, err
+	query, err = applySort(query, criteria.OrderBy, criteria.OrderDir, scenarioSortColumns)
+	if err != nil {
+		return page, err
 	}
-
-	scenarios := make([]*scenario.Scenario, len(models))
-	for i, model := range models {
-		scenario, err := ports.ScenarioPersistenceToDomainEntity(&model)
-		if err != nil {
-			return nil, err
-		}
-		scenarios[i] = scenario
+	if err := applyPage(query, criteria.Limit, criteria.Offset).Find(&models).Error; err != nil {
+		return page, err
 	}
 
-	return scenarios, nil
+	page.Items, err = ConvertSliceToDomainPtr(models, ports.ScenarioPersistenceToDomainEntity)
+	return page, err
 }
 
 func (r *ScenarioRepository) Delete(ctx context.Context, id scenario.ScenarioID) error {
 	return r.db.WithContext(ctx).Where("id = ?", ports.ScenarioParseID(id.String())).Delete(&models.Scenario{}).Error
 }
 
+func (r *ScenarioRepository) Restore(ctx context.Context, id scenario.ScenarioID) error {
+	return restoreTrashed(r.db.WithContext(ctx), &models.Scenario{}, ports.ScenarioParseID(id.String()), scenario.ErrScenarioNotFound)
+}
+
+// PurgeDeleted permanently removes scenarios trashed before the cutoff, along with their
+// runs and everything kept about them; analytics only hides trashed scenarios while
+// their row exists
+func (r *ScenarioRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return purgeTrashed(r.db.WithContext(ctx), &models.Scenario{}, before, func(tx *gorm.DB, ids []uint64) error {
+		for _, model := range []any{&models.RunNodeStep{}, &models.RunRollup{}, &models.RunSummary{}, &models.ScenarioRun{}} {
+			if err := tx.Where("scenario_id IN ?", ids).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (r *ScenarioRepository) Exists(ctx context.Context, id scenario.ScenarioID) (bool, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&models.Scenario{}).Where("id = ?", ports.ScenarioParseID(id.String())).Count(&count).Error
@@ -0,0 +1,35 @@
+package persistence
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"parrotflow/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecordSchemaVersion notes that the database was migrated to the schema of this build
+// Call it after AutoMigrate; the readiness check compares it with models.SchemaVersion
+func RecordSchemaVersion(ctx context.Context, db *gorm.DB) error {
+	return db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.SchemaMigration{Version: models.SchemaVersion, AppliedAt: time.Now()}).Error
+}
+
+// ProtectAuditLog makes the audit log append-only: SQLite aborts any update or delete
+// of its rows, whoever issues them. Call it after AutoMigrate
+func ProtectAuditLog(ctx context.Context, db *gorm.DB) error {
+	for _, statement := range []string{"UPDATE", "DELETE"} {
+		trigger := "audit_log_no_" + strings.ToLower(statement)
+		err := db.WithContext(ctx).Exec("CREATE TRIGGER IF NOT EXISTS " + trigger +
+			" BEFORE " + statement + " ON audit_log" +
+			" BEGIN SELECT RAISE(ABORT, 'the audit log is append-only'); END").Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
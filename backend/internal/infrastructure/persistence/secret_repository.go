@@ -0,0 +1,107 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"parrotflow/internal/domain/secret"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/models"
+	"parrotflow/internal/ports"
+
+	"gorm.io/gorm"
+)
+
+type SecretRepository struct {
+	db     *gorm.DB
+	cipher ports.Cipher // Encrypts values at rest
+}
+
+func NewSecretRepository(db *gorm.DB, cipher ports.Cipher) *SecretRepository {
+	return &SecretRepository{db: db, cipher: cipher}
+}
+
+func (r *SecretRepository) Save(ctx context.Context, s *secret.Secret) error {
+	model, err := ports.SecretDomainEntityToPersistence(s, r.cipher)
+	if err != nil {
+		return err
+	}
+	// Values are never written in plaintext, secrets need an encryption key
+	if model.ValueKeyID == "" {
+		return shared.ErrEncryptionRequired
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, model, "secret"); err != nil {
+			return err
+		}
+
+		// Record pending domain events in the same transaction
+		return appendOutboxEvents(tx, s.Events, ports.SecretFormatID(model.ID))
+	})
+	if err != nil {
+		return err
+	}
+
+	id, err := secret.NewSecretID(ports.SecretFormatID(model.ID))
+	if err != nil {
+		return err
+	}
+	s.Id = id
+	s.Version = model.Version
+	return nil
+}
+
+func (r *SecretRepository) FindByID(ctx context.Context, id secret.SecretID) (*secret.Secret, error) {
+	var model models.Secret
+	if err := r.db.WithContext(ctx).Where("id = ?", ports.SecretParseID(id.String())).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, secret.ErrSecretNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&model)
+}
+
+func (r *SecretRepository) FindByNames(ctx context.Context, names []string) ([]*secret.Secret, error) {
+	if len(names) == 0 {
+		return []*secret.Secret{}, nil
+	}
+
+	var models []models.Secret
+	if err := r.db.WithContext(ctx).Where("name IN ?", names).Order("name ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return ConvertSliceToDomainPtr(models, r.toDomain)
+}
+
+func (r *SecretRepository) FindAll(ctx context.Context) ([]*secret.Secret, error) {
+	var models []models.Secret
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return ConvertSliceToDomainPtr(models, r.toDomain)
+}
+
+func (r *SecretRepository) Exists(ctx context.Context, name string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Secret{}).Where("name = ?", name).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *SecretRepository) Delete(ctx context.Context, id secret.SecretID) error {
+	return r.db.WithContext(ctx).Where("id = ?", ports.SecretParseID(id.String())).Delete(&models.Secret{}).Error
+}
+
+// ReencryptValues encrypts the values not encrypted with the primary key yet and
+// returns how many it changed
+func (r *SecretRepository) ReencryptValues(ctx context.Context) (int, error) {
+	return reencrypt(r.db.WithContext(ctx), &models.Secret{}, "value", "value_key_id", r.cipher)
+}
+
+func (r *SecretRepository) toDomain(model *models.Secret) (*secret.Secret, error) {
+	return ports.SecretPersistenceToDomainEntity(model, r.cipher)
+}
@@ -0,0 +1,37 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"parrotflow/internal/domain/secret"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/encryption"
+	"parrotflow/internal/models"
+)
+
+func TestSecretRepository_RequiresEncryptionKey(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.Secret{}, &models.OutboxEvent{}, &models.EventLogEntry{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	keyring, err := encryption.NewKeyring(encryption.Config{})
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	ctx := context.Background()
+
+	s, err := secret.NewSecret(secret.SecretID{}, "DB_PASSWORD", "hunter2", "")
+	if err != nil {
+		t.Fatalf("NewSecret() error = %v", err)
+	}
+	if err := NewSecretRepository(db, keyring).Save(ctx, s); !errors.Is(err, shared.ErrEncryptionRequired) {
+		t.Errorf("Save() without keys error = %v, want %v", err, shared.ErrEncryptionRequired)
+	}
+	var count int64
+	db.Model(&models.Secret{}).Count(&count)
+	if count != 0 {
+		t.Errorf("%d secrets stored without keys, want none", count)
+	}
+}
@@ -3,6 +3,8 @@ package persistence
 import (
 	"context"
 	"errors"
+	"time"
+
 	"parrotflow/internal/domain/tag"
 	"parrotflow/internal/models"
 	"parrotflow/internal/ports"
@@ -24,10 +26,25 @@ func (r *TagRepository) Save(ctx context.Context, t *tag.Tag) error {
 		return err
 	}
 
-	if err = r.db.WithContext(ctx).Save(model).Error; err != nil {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, model, "tag"); err != nil {
+			return err
+		}
+
+		// Record pending domain events in the same transaction
+		return appendOutboxEvents(tx, t.Events, ports.TagFormatID(model.ID))
+	})
+	if err != nil {
 		return err
 	}
 
+	// A new tag takes over the stored ID, like its recorded events
+	id, err := tag.NewTagID(ports.TagFormatID(model.ID))
+	if err != nil {
+		return err
+	}
+	t.Id = id
+	t.Version = model.Version
 	return nil
 }
 
@@ -91,12 +108,36 @@ func (r *TagRepository) FindByIDs(ctx context.Context, ids []tag.TagID) ([]*tag.
 	return ConvertSliceToDomainPtr(models, ports.TagPersistenceToDomainEntity)
 }
 
+func (r *TagRepository) FindTrashed(ctx context.Context) ([]*tag.Tag, error) {
+	var models []models.Tag
+	if err := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return ConvertSliceToDomainPtr(models, ports.TagPersistenceToDomainEntity)
+}
+
 func (r *TagRepository) Delete(ctx context.Context, id tag.TagID) error {
 	return r.db.WithContext(ctx).Where("id = ?", ports.TagParseID(id.String())).Delete(&models.Tag{}).Error
 }
 
+func (r *TagRepository) Restore(ctx context.Context, id tag.TagID) error {
+	return restoreTrashed(r.db.WithContext(ctx), &models.Tag{}, ports.TagParseID(id.String()), tag.ErrTagNotFound)
+}
+
+// PurgeDeleted permanently removes tags trashed before the cutoff and detaches them from agents and proxies
+func (r *TagRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return purgeTrashed(r.db.WithContext(ctx), &models.Tag{}, before, func(tx *gorm.DB, ids []uint64) error {
+		if err := tx.Exec("DELETE FROM proxy_tags WHERE tag_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		return tx.Exec("DELETE FROM agent_tags WHERE tag_id IN ?", ids).Error
+	})
+}
+
+// Exists also counts trashed tags, since their names stay reserved until purged
 func (r *TagRepository) Exists(ctx context.Context, name string) (bool, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&models.Tag{}).Where("LOWER(name) = LOWER(?)", name).Count(&count).Error
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.Tag{}).Where("LOWER(name) = LOWER(?)", name).Count(&count).Error
 	return count > 0, err
 }
@@ -0,0 +1,70 @@
+package persistence
+
+import (
+	"time"
+
+	"parrotflow/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// restoreTrashed clears deleted_at on a trashed row and bumps its version so
+// stale copies loaded before the delete can't overwrite the restored state
+// notFound is returned when no trashed row has the ID
+func restoreTrashed(db *gorm.DB, model interface{}, id uint64, notFound error) error {
+	result := db.Unscoped().Model(model).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// trashedBefore returns the IDs of rows that were moved to the trash before the cutoff
+func trashedBefore(tx *gorm.DB, model interface{}, before time.Time) ([]uint64, error) {
+	var ids []uint64
+	err := tx.Unscoped().Model(model).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// purgeTrashed permanently deletes trashed rows older than the cutoff
+// cleanup runs in the same transaction first, so dependent rows go with them
+func purgeTrashed(db *gorm.DB, model interface{}, before time.Time, cleanup func(tx *gorm.DB, ids []uint64) error) (int64, error) {
+	var purged int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		ids, err := trashedBefore(tx, model, before)
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		if cleanup != nil {
+			if err := cleanup(tx, ids); err != nil {
+				return err
+			}
+		}
+
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(model)
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
+
+// withoutTrashedScenarios hides runs whose scenario is in the trash
+func withoutTrashedScenarios(db *gorm.DB) *gorm.DB {
+	trashed := db.Session(&gorm.Session{NewDB: true}).
+		Unscoped().
+		Model(&models.Scenario{}).
+		Select("id").
+		Where("deleted_at IS NOT NULL")
+	return db.Where("scenario_id NOT IN (?)", trashed)
+}
@@ -0,0 +1,35 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/models"
+)
+
+func TestScenarioRepository_RestoreNotTrashed(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	if err := db.Create(&models.Scenario{ScenarioBase: models.ScenarioBase{Name: "live"}}).Error; err != nil {
+		t.Fatalf("Create(scenario) error = %v", err)
+	}
+	repository := NewScenarioRepository(db)
+
+	// Neither a live scenario nor an unknown one is in the trash
+	for _, value := range []string{"1", "42"} {
+		id, _ := scenario.NewScenarioID(value)
+		if err := repository.Restore(ctx, id); !errors.Is(err, scenario.ErrScenarioNotFound) {
+			t.Errorf("Restore(%s) error = %v, want ErrScenarioNotFound", value, err)
+		}
+	}
+
+	id, _ := scenario.NewScenarioID("1")
+	if err := repository.Delete(ctx, id); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := repository.Restore(ctx, id); err != nil {
+		t.Errorf("Restore() after Delete() error = %v", err)
+	}
+}
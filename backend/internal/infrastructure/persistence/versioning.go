@@ -0,0 +1,52 @@
+package persistence
+
+import (
+	"parrotflow/internal/domain/shared"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// versionedModel is satisfied by every model embedding models.Model
+type versionedModel interface {
+	GetID() uint64
+	GetVersion() uint64
+	SetVersion(version uint64)
+}
+
+// saveVersioned inserts a model that has never been saved (version 0), or
+// updates it with a conditional "WHERE version = ?" and bumps the version
+// Associations are left to the caller
+//
+// A zero-row update means another writer saved first; the typed
+// shared.ErrConcurrentModification is returned so callers can reload and retry
+func saveVersioned(tx *gorm.DB, model versionedModel, aggregate string) error {
+	expected := model.GetVersion()
+
+	if expected == 0 {
+		model.SetVersion(1)
+		if err := tx.Omit(clause.Associations).Create(model).Error; err != nil {
+			model.SetVersion(0)
+			return err
+		}
+		return nil
+	}
+
+	model.SetVersion(expected + 1)
+	result := tx.Model(model).
+		Where("version = ?", expected).
+		Select("*").
+		Omit(clause.Associations, "id", "created_at").
+		Updates(model)
+	if result.Error != nil {
+		model.SetVersion(expected)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		model.SetVersion(expected)
+		return shared.NewConcurrentModificationError(aggregate, strconv.FormatUint(model.GetID(), 10), expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/domain/webhook"
+	"parrotflow/internal/models"
+	"parrotflow/internal/ports"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository struct {
+	db     *gorm.DB
+	cipher ports.Cipher // Encrypts signing secrets at rest
+}
+
+func NewWebhookRepository(db *gorm.DB, cipher ports.Cipher) *WebhookRepository {
+	return &WebhookRepository{db: db, cipher: cipher}
+}
+
+func (r *WebhookRepository) Save(ctx context.Context, w *webhook.Webhook) error {
+	model, err := ports.WebhookDomainEntityToPersistence(w, r.cipher)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, model, "webhook"); err != nil {
+			return err
+		}
+
+		// Record pending domain events in the same transaction
+		return appendOutboxEvents(tx, w.Events, ports.WebhookFormatID(model.ID))
+	})
+	if err != nil {
+		return err
+	}
+
+	// Deliveries reference the stored ID, so a new webhook takes it over
+	id, err := webhook.NewWebhookID(ports.WebhookFormatID(model.ID))
+	if err != nil {
+		return err
+	}
+	w.Id = id
+	w.Version = model.Version
+	return nil
+}
+
+func (r *WebhookRepository) FindByID(ctx context.Context, id webhook.WebhookID) (*webhook.Webhook, error) {
+	var model models.Webhook
+	if err := r.db.WithContext(ctx).Where("id = ?", ports.WebhookParseID(id.String())).First(&model).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, webhook.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&model)
+}
+
+func (r *WebhookRepository) FindAll(ctx context.Context) ([]*webhook.Webhook, error) {
+	var models []models.Webhook
+	if err := r.db.WithContext(ctx).Order("created_at ASC, id ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return ConvertSliceToDomainPtr(models, r.toDomain)
+}
+
+// FindSubscribed looks the event type up in the JSON array of subscribed types
+func (r *WebhookRepository) FindSubscribed(ctx context.Context, eventType string) ([]*webhook.Webhook, error) {
+	var models []models.Webhook
+	err := r.db.WithContext(ctx).
+		Where("enabled = ?", true).
+		Where("EXISTS (SELECT 1 FROM json_each(webhooks.event_types) WHERE json_each.value IN ?)", []string{eventType, webhook.AllEvents}).
+		Order("id ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ConvertSliceToDomainPtr(models, r.toDomain)
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id webhook.WebhookID) error {
+	webhookID := ports.WebhookParseID(id.String())
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", webhookID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", webhookID).Delete(&models.Webhook{}).Error
+	})
+}
+
+// ReencryptSecrets encrypts the signing secrets not encrypted with the primary key yet
+// and returns how many it changed
+func (r *WebhookRepository) ReencryptSecrets(ctx context.Context) (int, error) {
+	return reencrypt(r.db.WithContext(ctx), &models.Webhook{}, "secret", "secret_key_id", r.cipher)
+}
+
+func (r *WebhookRepository) toDomain(model *models.Webhook) (*webhook.Webhook, error) {
+	return ports.WebhookPersistenceToDomainEntity(model, r.cipher)
+}
+
+type WebhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db: db}
+}
+
+// Enqueue ignores deliveries that already exist, so an event relayed twice is sent once
+func (r *WebhookDeliveryRepository) Enqueue(ctx context.Context, deliveries ...*webhook.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	rows := make([]*models.WebhookDelivery, len(deliveries))
+	for i, d := range deliveries {
+		rows[i] = ports.WebhookDeliveryDomainToPersistence(d)
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "webhook_id"}, {Name: "event_id"}},
+		DoNothing: true,
+	}).Create(&rows).Error
+}
+
+func (r *WebhookDeliveryRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*webhook.Delivery, error) {
+	var models []models.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Where("status = ?", webhook.DeliveryStatusPending.String()).
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ConvertSliceToDomainPtr(models, ports.WebhookDeliveryPersistenceToDomain)
+}
+
+func (r *WebhookDeliveryRepository) Update(ctx context.Context, d *webhook.Delivery) error {
+	model := ports.WebhookDeliveryDomainToPersistence(d)
+	result := r.db.WithContext(ctx).Model(&models.WebhookDelivery{}).Where("id = ?", model.ID).Updates(map[string]interface{}{
+		"status":          model.Status,
+		"attempts":        model.Attempts,
+		"next_attempt_at": model.NextAttemptAt,
+		"response_status": model.ResponseStatus,
+		"last_error":      model.LastError,
+		"duration_ms":     model.DurationMs,
+		"delivered_at":    model.DeliveredAt,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("webhook delivery not found")
+	}
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) FindByWebhook(ctx context.Context, criteria webhook.DeliveryCriteria) (shared.Page[*webhook.Delivery], error) {
+	var page shared.Page[*webhook.Delivery]
+	var models []models.WebhookDelivery
+	query := r.db.WithContext(ctx).Where("webhook_id = ?", ports.WebhookParseID(criteria.WebhookID.String()))
+
+	if criteria.Status != nil {
+		query = query.Where("status = ?", criteria.Status.String())
+	}
+
+	total, err := countTotal(query, &models)
+	if err != nil {
+		return page, err
+	}
+	page.Total = total
+
+	query = query.Order("created_at DESC, id DESC")
+	if err := applyPage(query, criteria.Limit, criteria.Offset).Find(&models).Error; err != nil {
+		return page, err
+	}
+
+	page.Items, err = ConvertSliceToDomainPtr(models, ports.WebhookDeliveryPersistenceToDomain)
+	return page, err
+}
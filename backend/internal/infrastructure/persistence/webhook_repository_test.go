@@ -0,0 +1,53 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/webhook"
+	"parrotflow/internal/infrastructure/encryption"
+	"parrotflow/internal/models"
+)
+
+func TestWebhookRepository_SealsSecretsAndFindsSubscribed(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.Webhook{}, &models.OutboxEvent{}, &models.EventLogEntry{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	entry, _ := encryption.GenerateKey("k1")
+	keyring, err := encryption.NewKeyring(encryption.Config{Keys: entry})
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	repository := NewWebhookRepository(db, keyring)
+	ctx := context.Background()
+
+	const secret = "0123456789abcdef0123"
+	for _, eventTypes := range [][]string{{run.EventRunFailed}, {run.EventRunCompleted}, {webhook.AllEvents}} {
+		w, err := webhook.NewWebhook(webhook.WebhookID{}, "https://example.com/hook", eventTypes, secret)
+		if err != nil {
+			t.Fatalf("NewWebhook() error = %v", err)
+		}
+		if err := repository.Save(ctx, w); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	var stored models.Webhook
+	db.First(&stored)
+	if stored.Secret == secret || stored.SecretKeyID != "k1" {
+		t.Errorf("Stored secret = %q with key %q, want it encrypted with k1", stored.Secret, stored.SecretKeyID)
+	}
+
+	subscribed, err := repository.FindSubscribed(ctx, run.EventRunFailed)
+	if err != nil {
+		t.Fatalf("FindSubscribed() error = %v", err)
+	}
+	if len(subscribed) != 2 || subscribed[0].Id.String() != "1" || subscribed[1].Id.String() != "3" {
+		t.Fatalf("FindSubscribed() returned %d webhooks, want 1 and 3", len(subscribed))
+	}
+	if subscribed[0].Secret != secret {
+		t.Errorf("Secret = %q, want it decrypted", subscribed[0].Secret)
+	}
+}
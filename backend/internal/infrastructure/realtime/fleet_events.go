@@ -0,0 +1,44 @@
+package realtime
+
+import (
+	"context"
+	"parrotflow/internal/domain/agent"
+	"parrotflow/internal/domain/proxy"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/outbox"
+)
+
+// Hub topics carrying fleet state
+const (
+	AgentsTopic  = "agents"
+	ProxiesTopic = "proxies"
+)
+
+// FleetEventPublisher forwards agent and proxy state changes from the event bus to the hub
+type FleetEventPublisher struct {
+	hub *Hub
+}
+
+func NewFleetEventPublisher(hub *Hub) *FleetEventPublisher {
+	return &FleetEventPublisher{hub: hub}
+}
+
+func (p *FleetEventPublisher) Handle(ctx context.Context, event shared.DomainEvent) error {
+	switch event.EventType() {
+	case agent.EventAgentStatusChanged, agent.EventAgentDisconnected:
+		p.hub.Publish(AgentsTopic, outbox.SequenceFromContext(ctx), event)
+	case proxy.EventProxyStatusChanged, proxy.EventProxyFailed:
+		p.hub.Publish(ProxiesTopic, outbox.SequenceFromContext(ctx), event)
+	}
+	return nil
+}
+
+func (p *FleetEventPublisher) CanHandle(eventType string) bool {
+	switch eventType {
+	case agent.EventAgentStatusChanged, agent.EventAgentDisconnected,
+		proxy.EventProxyStatusChanged, proxy.EventProxyFailed:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,201 @@
+package realtime
+
+import (
+	"sync"
+	"time"
+
+	"parrotflow/internal/domain/shared"
+)
+
+// Message is a domain event published on a topic
+// Its ID is the event log sequence of the event, so IDs increase across all topics
+// and survive restarts: a client can resume any topic from the last ID it saw
+type Message struct {
+	ID    uint64
+	Topic string
+	Event shared.DomainEvent
+}
+
+// HubConfig bounds the memory the hub keeps for history and slow subscribers
+type HubConfig struct {
+	HistorySize      int // Messages kept per topic for Last-Event-ID resume
+	MaxTopics        int // Topics with history; the least recently used idle topic is evicted first
+	SubscriberBuffer int // Messages queued per subscriber before it is dropped
+}
+
+func DefaultHubConfig() HubConfig {
+	return HubConfig{
+		HistorySize:      256,
+		MaxTopics:        1024,
+		SubscriberBuffer: 64,
+	}
+}
+
+type topic struct {
+	history     []Message
+	since       uint64 // History is complete after this ID; earlier messages were evicted or published before the topic existed
+	subscribers map[*Subscription]struct{}
+	lastUsed    time.Time
+}
+
+// Hub fans published messages out to every subscriber of a topic
+// Publishing never blocks: a subscriber whose buffer is full is dropped and
+// has to resubscribe from the last message it received
+type Hub struct {
+	config HubConfig
+	topics map[string]*topic
+	lastID uint64
+	mu     sync.Mutex
+	now    func() time.Time
+}
+
+// NewHub creates a hub resuming after lastID, the last event log sequence recorded
+// before it started; clients resuming from an earlier ID get an incomplete replay
+func NewHub(config HubConfig, lastID uint64) *Hub {
+	return &Hub{
+		config: config,
+		topics: make(map[string]*topic),
+		lastID: lastID,
+		now:    time.Now,
+	}
+}
+
+// Publish records the event under id in the topic history and hands it to its subscribers
+// id is the event log sequence of the event; events that were not recorded pass 0
+// and are numbered after the last ID the hub has seen
+func (h *Hub) Publish(name string, id uint64, event shared.DomainEvent) Message {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// A new topic misses whatever was published before this message
+	t := h.topic(name)
+	if id == 0 {
+		id = h.lastID + 1
+	}
+	h.lastID = max(h.lastID, id)
+	msg := Message{ID: id, Topic: name, Event: event}
+
+	t.history = append(t.history, msg)
+	if len(t.history) > h.config.HistorySize {
+		evicted := len(t.history) - h.config.HistorySize
+		t.since = t.history[evicted-1].ID
+		t.history = t.history[evicted:]
+	}
+
+	for sub := range t.subscribers {
+		select {
+		case sub.messages <- msg:
+		default:
+			h.drop(t, sub)
+		}
+	}
+
+	return msg
+}
+
+// Subscribe starts receiving messages of a topic
+// The history after afterID is returned for replay; complete reports whether it
+// holds every message published after afterID. When it does not, e.g. after a
+// restart or for a client that has not seen the topic yet, callers send a snapshot
+// of the current state instead of the replay
+func (h *Hub) Subscribe(name string, afterID uint64) (sub *Subscription, replay []Message, complete bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t := h.topic(name)
+	sub = &Subscription{
+		hub:      h,
+		topic:    name,
+		messages: make(chan Message, h.config.SubscriberBuffer),
+	}
+	t.subscribers[sub] = struct{}{}
+
+	replay = make([]Message, 0)
+	for _, msg := range t.history {
+		if msg.ID > afterID {
+			replay = append(replay, msg)
+		}
+	}
+	return sub, replay, afterID >= t.since
+}
+
+func (h *Hub) unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if t, ok := h.topics[sub.topic]; ok {
+		if _, subscribed := t.subscribers[sub]; subscribed {
+			delete(t.subscribers, sub)
+			close(sub.messages)
+		}
+	}
+}
+
+// drop disconnects a subscriber that could not keep up; callers hold the lock
+func (h *Hub) drop(t *topic, sub *Subscription) {
+	delete(t.subscribers, sub)
+	sub.dropped = true
+	close(sub.messages)
+}
+
+// topic returns the named topic, creating it and evicting an idle one if needed; callers hold the lock
+func (h *Hub) topic(name string) *topic {
+	if t, ok := h.topics[name]; ok {
+		t.lastUsed = h.now()
+		return t
+	}
+
+	if len(h.topics) >= h.config.MaxTopics {
+		h.evictIdle()
+	}
+
+	t := &topic{
+		since:       h.lastID,
+		subscribers: make(map[*Subscription]struct{}),
+		lastUsed:    h.now(),
+	}
+	h.topics[name] = t
+	return t
+}
+
+func (h *Hub) evictIdle() {
+	var oldest string
+	var oldestUsed time.Time
+	for name, t := range h.topics {
+		if len(t.subscribers) > 0 {
+			continue
+		}
+		if oldest == "" || t.lastUsed.Before(oldestUsed) {
+			oldest = name
+			oldestUsed = t.lastUsed
+		}
+	}
+	if oldest != "" {
+		delete(h.topics, oldest)
+	}
+}
+
+// Subscription delivers the messages of one topic
+type Subscription struct {
+	hub      *Hub
+	topic    string
+	messages chan Message
+	dropped  bool
+}
+
+// Messages is closed when the subscription ends, either by Close or because it was dropped
+func (s *Subscription) Messages() <-chan Message {
+	return s.messages
+}
+
+// Dropped reports whether the hub disconnected the subscriber for falling behind
+// Only meaningful once Messages is closed
+func (s *Subscription) Dropped() bool {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.dropped
+}
+
+func (s *Subscription) Close() {
+	s.hub.unsubscribe(s)
+}
@@ -0,0 +1,105 @@
+package realtime
+
+import (
+	"testing"
+
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/shared"
+)
+
+func newTestEvent(eventType, runID string) shared.DomainEvent {
+	return run.RunStarted{
+		BaseEvent: shared.NewBaseEvent(eventType, runID),
+		RunID:     runID,
+	}
+}
+
+func TestHub_ReplaysHistoryAfterLastEventID(t *testing.T) {
+	hub := NewHub(DefaultHubConfig(), 0)
+	first := hub.Publish("run:1", 0, newTestEvent(run.EventRunStarted, "1"))
+	hub.Publish("run:2", 0, newTestEvent(run.EventRunStarted, "2"))
+	second := hub.Publish("run:1", 0, newTestEvent(run.EventRunProgress, "1"))
+
+	sub, replay, _ := hub.Subscribe("run:1", first.ID)
+	defer sub.Close()
+
+	if len(replay) != 1 || replay[0].ID != second.ID {
+		t.Fatalf("Subscribe() replay = %+v, want only message %d", replay, second.ID)
+	}
+
+	live := hub.Publish("run:1", 0, newTestEvent(run.EventRunCompleted, "1"))
+	if msg := <-sub.Messages(); msg.ID != live.ID {
+		t.Errorf("Live message ID = %d, want %d", msg.ID, live.ID)
+	}
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	hub := NewHub(HubConfig{HistorySize: 10, MaxTopics: 10, SubscriberBuffer: 1}, 0)
+	slow, _, _ := hub.Subscribe("run:1", 0)
+	fast, _, _ := hub.Subscribe("run:1", 0)
+	defer fast.Close()
+
+	hub.Publish("run:1", 0, newTestEvent(run.EventRunStarted, "1"))
+	<-fast.Messages()
+	hub.Publish("run:1", 0, newTestEvent(run.EventRunProgress, "1"))
+
+	// The first message is still buffered, then the channel is closed
+	<-slow.Messages()
+	if _, ok := <-slow.Messages(); ok || !slow.Dropped() {
+		t.Error("Slow subscriber was not dropped")
+	}
+	if _, ok := <-fast.Messages(); !ok || fast.Dropped() {
+		t.Error("Fast subscriber was dropped")
+	}
+
+	// Closing after being dropped must not panic
+	slow.Close()
+}
+
+func TestHub_HistoryIsBounded(t *testing.T) {
+	hub := NewHub(HubConfig{HistorySize: 2, MaxTopics: 1, SubscriberBuffer: 1}, 0)
+	hub.Publish("run:1", 0, newTestEvent(run.EventRunStarted, "1"))
+	hub.Publish("run:1", 0, newTestEvent(run.EventRunProgress, "1"))
+	last := hub.Publish("run:1", 0, newTestEvent(run.EventRunCompleted, "1"))
+
+	sub, replay, complete := hub.Subscribe("run:1", 0)
+	if len(replay) != 2 || replay[1].ID != last.ID || complete {
+		t.Errorf("Replay = %+v, complete = %v, want the last 2 messages of an incomplete history", replay, complete)
+	}
+	sub.Close()
+
+	// Publishing on a new topic evicts the idle one
+	hub.Publish("run:2", 0, newTestEvent(run.EventRunStarted, "2"))
+	sub, replay, _ = hub.Subscribe("run:1", 0)
+	defer sub.Close()
+	if len(replay) != 0 {
+		t.Errorf("Replay of evicted topic = %+v, want empty", replay)
+	}
+}
+
+func TestHub_ResumesAfterRestart(t *testing.T) {
+	// Events up to 40 were recorded before the restart, the hub has none of them
+	hub := NewHub(DefaultHubConfig(), 40)
+
+	sub, replay, complete := hub.Subscribe("run:1", 38)
+	sub.Close()
+	if len(replay) != 0 || complete {
+		t.Errorf("Subscribe(38) = %+v, complete = %v, want an incomplete empty replay", replay, complete)
+	}
+
+	// Message IDs are the event log sequences the relay delivers
+	msg := hub.Publish("run:1", 41, newTestEvent(run.EventRunProgress, "1"))
+	if msg.ID != 41 {
+		t.Errorf("Publish() ID = %d, want 41", msg.ID)
+	}
+	sub, replay, complete = hub.Subscribe("run:1", 40)
+	defer sub.Close()
+	if len(replay) != 1 || !complete {
+		t.Errorf("Subscribe(40) = %+v, complete = %v, want message 41 of a complete history", replay, complete)
+	}
+
+	// Events that were not recorded are numbered after it
+	if msg := hub.Publish("run:1", 0, newTestEvent(run.EventRunProgress, "1")); msg.ID != 42 {
+		t.Errorf("Publish() ID = %d, want 42", msg.ID)
+	}
+}
@@ -0,0 +1,75 @@
+package realtime
+
+import (
+	"context"
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/outbox"
+)
+
+// RunTopic is the hub topic carrying the events of one run
+func RunTopic(runID string) string {
+	return "run:" + runID
+}
+
+// ScenarioRunsTopic is the hub topic carrying the events of every run of a scenario
+func ScenarioRunsTopic(scenarioID string) string {
+	return "runs:" + scenarioID
+}
+
+// RunEventPublisher forwards run events from the event bus to the run and scenario topics
+type RunEventPublisher struct {
+	hub *Hub
+}
+
+func NewRunEventPublisher(hub *Hub) *RunEventPublisher {
+	return &RunEventPublisher{hub: hub}
+}
+
+func (p *RunEventPublisher) Handle(ctx context.Context, event shared.DomainEvent) error {
+	sequence := outbox.SequenceFromContext(ctx)
+	p.hub.Publish(RunTopic(event.AggregateID()), sequence, event)
+	if scenarioID := runScenarioID(event); scenarioID != "" {
+		p.hub.Publish(ScenarioRunsTopic(scenarioID), sequence, event)
+	}
+	return nil
+}
+
+func (p *RunEventPublisher) CanHandle(eventType string) bool {
+	switch eventType {
+	case run.EventRunCreated, run.EventRunStarted, run.EventRunProgress,
+		run.EventRunCompleted, run.EventRunFailed, run.EventRunCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFinalRunEvent reports whether no further events follow on the run topic
+func IsFinalRunEvent(event shared.DomainEvent) bool {
+	switch event.EventType() {
+	case run.EventRunCompleted, run.EventRunFailed, run.EventRunCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+func runScenarioID(event shared.DomainEvent) string {
+	switch e := event.(type) {
+	case run.RunCreated:
+		return e.ScenarioID
+	case run.RunStarted:
+		return e.ScenarioID
+	case run.RunProgress:
+		return e.ScenarioID
+	case run.RunCompleted:
+		return e.ScenarioID
+	case run.RunFailed:
+		return e.ScenarioID
+	case run.RunCancelled:
+		return e.ScenarioID
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"errors"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// InstrumentGORM adds a span around every statement run with a traced context
+// Statements of background loops, which run without a trace, are left out so they
+// do not flood the exporter with root spans
+func InstrumentGORM(db *gorm.DB) error {
+	c := db.Callback()
+	return errors.Join(
+		c.Create().Before("gorm:create").Register("tracing:before_create", startStatement("create")),
+		c.Create().After("gorm:create").Register("tracing:after_create", endStatement),
+		c.Query().Before("gorm:query").Register("tracing:before_query", startStatement("query")),
+		c.Query().After("gorm:query").Register("tracing:after_query", endStatement),
+		c.Update().Before("gorm:update").Register("tracing:before_update", startStatement("update")),
+		c.Update().After("gorm:update").Register("tracing:after_update", endStatement),
+		c.Delete().Before("gorm:delete").Register("tracing:before_delete", startStatement("delete")),
+		c.Delete().After("gorm:delete").Register("tracing:after_delete", endStatement),
+		c.Row().Before("gorm:row").Register("tracing:before_row", startStatement("row")),
+		c.Row().After("gorm:row").Register("tracing:after_row", endStatement),
+		c.Raw().Before("gorm:raw").Register("tracing:before_raw", startStatement("raw")),
+		c.Raw().After("gorm:raw").Register("tracing:after_raw", endStatement),
+	)
+}
+
+type statementSpan struct {
+	span      trace.Span
+	operation string
+}
+
+func startStatement(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		if tx.Statement.Context == nil || !IsTraced(tx.Statement.Context) {
+			return
+		}
+		_, span := Tracer().Start(tx.Statement.Context, "db."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemNameKey.String(tx.Dialector.Name()),
+				semconv.DBOperationName(operation),
+			),
+		)
+		tx.InstanceSet(gormSpanKey, statementSpan{span: span, operation: operation})
+	}
+}
+
+func endStatement(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	statement := value.(statementSpan)
+	span := statement.span
+	defer span.End()
+
+	if table := tx.Statement.Table; table != "" {
+		span.SetName("db." + statement.operation + " " + table)
+		span.SetAttributes(semconv.DBCollectionName(table))
+	}
+	span.SetAttributes(semconv.DBQueryText(tx.Statement.SQL.String()))
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		RecordError(span, tx.Error)
+	}
+}
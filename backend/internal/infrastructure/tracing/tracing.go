@@ -0,0 +1,99 @@
+// Package tracing sets up OpenTelemetry tracing and carries trace context across
+// the places a request leaves the process: the database, the outbox and the broker
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Name of the tracer of every span the backend starts
+const instrumentation = "parrotflow"
+
+// Exporters spans can be sent to
+const (
+	ExporterNone   = "none"   // Tracing disabled
+	ExporterStdout = "stdout" // Pretty-printed JSON on stdout, for local debugging
+	ExporterOTLP   = "otlp"   // OTLP over HTTP to a collector
+)
+
+// Config selects where spans go and how many traces are kept
+type Config struct {
+	Exporter     string
+	OTLPEndpoint string  // host:port of the collector; empty uses OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318
+	OTLPInsecure bool    // Plain HTTP instead of HTTPS
+	SampleRatio  float64 // Share of new traces recorded; traces started upstream follow the caller's decision
+	ServiceName  string
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Exporter:    ExporterNone,
+		SampleRatio: 1,
+		ServiceName: "parrotflow-backend",
+	}
+}
+
+// Setup installs the global tracer provider and the W3C trace context propagator
+// The returned function flushes pending spans and must be called on shutdown
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch config.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = newStdoutExporter(os.Stdout)
+	case ExporterOTLP:
+		options := []otlptracehttp.Option{}
+		if config.OTLPEndpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(config.OTLPEndpoint))
+		}
+		if config.OTLPInsecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, options...)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", config.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	provider := NewProvider(exporter, config)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// NewProvider creates a tracer provider batching spans to the exporter
+// Tests pass an in-memory exporter from go.opentelemetry.io/otel/sdk/trace/tracetest
+func NewProvider(exporter sdktrace.SpanExporter, config Config) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(config.ServiceName))),
+	)
+}
+
+func newStdoutExporter(w io.Writer) (sdktrace.SpanExporter, error) {
+	return stdouttrace.New(stdouttrace.WithWriter(w), stdouttrace.WithPrettyPrint())
+}
+
+// Tracer returns the tracer of the global provider
+// It is looked up on every call so spans follow a provider installed after startup
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
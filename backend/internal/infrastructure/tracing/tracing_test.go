@@ -0,0 +1,76 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"parrotflow/internal/infrastructure/tracing"
+)
+
+type record struct {
+	ID   uint
+	Name string
+}
+
+// recordSpans installs a provider exporting to memory for the duration of the test
+func recordSpans(t *testing.T) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewProvider(exporter, tracing.DefaultConfig())
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return provider, exporter
+}
+
+func TestInstrumentGORM_TracesStatementsOfTracedContexts(t *testing.T) {
+	provider, exporter := recordSpans(t)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	if err := tracing.InstrumentGORM(db); err != nil {
+		t.Fatalf("InstrumentGORM() error = %v", err)
+	}
+	if err := db.AutoMigrate(&record{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+
+	// Untraced statements, like those of background loops, get no span
+	if err := db.WithContext(context.Background()).Create(&record{Name: "untraced"}).Error; err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	ctx, parent := tracing.Tracer().Start(context.Background(), "request")
+	var records []record
+	if err := db.WithContext(ctx).Find(&records).Error; err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	parent.End()
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush() error = %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Exported %d spans, want the request and its query", len(spans))
+	}
+	query := spans[0]
+	if query.Name != "db.query records" {
+		t.Errorf("Span name = %q, want %q", query.Name, "db.query records")
+	}
+	if query.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("Query span is not a child of the request span")
+	}
+}
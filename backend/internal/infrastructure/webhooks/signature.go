@@ -0,0 +1,50 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Request headers sent with every delivery
+const (
+	HeaderEvent     = "X-Parrotflow-Event"
+	HeaderDelivery  = "X-Parrotflow-Delivery"
+	HeaderTimestamp = "X-Parrotflow-Timestamp"
+	HeaderSignature = "X-Parrotflow-Signature"
+)
+
+const signaturePrefix = "sha256="
+
+// Sign computes the signature header value for a body sent at timestamp (unix seconds)
+// The timestamp is part of the signed message so a captured request cannot be replayed later:
+//
+//	sha256=hex(HMAC-SHA256(secret, "<timestamp>.<body>"))
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a received signature and rejects timestamps older than tolerance
+// Receivers written in Go can use it as-is; it documents the scheme for everyone else
+func Verify(secret, signature, timestamp string, body []byte, tolerance time.Duration, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("invalid webhook timestamp")
+	}
+	if tolerance > 0 && now.Sub(time.Unix(ts, 0)).Abs() > tolerance {
+		return errors.New("webhook timestamp outside tolerance")
+	}
+	if !strings.HasPrefix(signature, signaturePrefix) ||
+		!hmac.Equal([]byte(signature), []byte(Sign(secret, ts, body))) {
+		return errors.New("webhook signature mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/domain/webhook"
+)
+
+// Sink is an outbox sink that enqueues a delivery for every webhook subscribed to an event
+// The body of every delivery is the event envelope (see shared.Envelope)
+// Enqueueing is idempotent, so the relay may redeliver an event without duplicating requests
+type Sink struct {
+	webhooks   webhook.Repository
+	deliveries webhook.DeliveryRepository
+	worker     *Worker
+	now        func() time.Time
+}
+
+func NewSink(webhooks webhook.Repository, deliveries webhook.DeliveryRepository, worker *Worker) *Sink {
+	return &Sink{
+		webhooks:   webhooks,
+		deliveries: deliveries,
+		worker:     worker,
+		now:        time.Now,
+	}
+}
+
+func (s *Sink) Name() string {
+	return "webhooks"
+}
+
+func (s *Sink) Deliver(ctx context.Context, event shared.DomainEvent) error {
+	subscribed, err := s.webhooks.FindSubscribed(ctx, event.EventType())
+	if err != nil || len(subscribed) == 0 {
+		return err
+	}
+
+	envelope, err := shared.NewEnvelope(event)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	deliveries := make([]*webhook.Delivery, len(subscribed))
+	for i, w := range subscribed {
+		deliveries[i] = webhook.NewDelivery(w.Id, event.EventID(), event.EventType(), body, now)
+	}
+	if err := s.deliveries.Enqueue(ctx, deliveries...); err != nil {
+		return err
+	}
+
+	s.worker.Notify()
+	return nil
+}
@@ -0,0 +1,264 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	command "parrotflow/internal/application/command"
+	"parrotflow/internal/domain/webhook"
+	"parrotflow/internal/infrastructure/outbox"
+)
+
+// Config controls polling, retries and automatic disabling of webhooks
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Timeout      time.Duration // Per request
+	MaxAttempts  int           // Attempts per delivery before it is marked failed
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	DisableAfter int // Consecutive failed deliveries that disable a webhook, 0 never disables
+	// AllowPrivateTargets lets webhooks reach loopback, private and link-local addresses,
+	// which are refused by default so webhooks cannot be pointed at internal services
+	AllowPrivateTargets bool
+}
+
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: time.Second,
+		BatchSize:    50,
+		Timeout:      10 * time.Second,
+		MaxAttempts:  8,
+		BaseBackoff:  10 * time.Second,
+		MaxBackoff:   time.Hour,
+		DisableAfter: 5,
+	}
+}
+
+// ErrForbiddenTarget is reported for deliveries to an address webhooks may not reach
+var ErrForbiddenTarget = errors.New("webhook target is a loopback, private or link-local address")
+
+// Worker sends due deliveries to their webhooks
+// A delivery succeeds on any 2xx response; anything else, redirects included, is
+// retried with exponential backoff until MaxAttempts, after which it counts towards
+// disabling the webhook
+type Worker struct {
+	webhooks   webhook.Repository
+	deliveries webhook.DeliveryRepository
+	client     *http.Client
+	config     Config
+	notify     chan struct{}
+	now        func() time.Time
+}
+
+func NewWorker(webhooks webhook.Repository, deliveries webhook.DeliveryRepository, config Config) *Worker {
+	return &Worker{
+		webhooks:   webhooks,
+		deliveries: deliveries,
+		client:     newClient(config),
+		config:     config,
+		notify:     make(chan struct{}, 1),
+		now:        time.Now,
+	}
+}
+
+// Notify wakes the worker up without waiting for the next poll
+func (w *Worker) Notify() {
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends deliveries until the context is cancelled
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.ProcessDue(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Error delivering webhooks", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.notify:
+		}
+	}
+}
+
+// ProcessDue attempts one batch of due deliveries and returns how many succeeded
+func (w *Worker) ProcessDue(ctx context.Context) (int, error) {
+	due, err := w.deliveries.FindDue(ctx, w.now(), w.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	succeeded := 0
+	for _, d := range due {
+		if ctx.Err() != nil {
+			return succeeded, ctx.Err()
+		}
+
+		ok, err := w.process(ctx, d)
+		if err != nil {
+			return succeeded, err
+		}
+		if ok {
+			succeeded++
+		}
+	}
+
+	return succeeded, nil
+}
+
+func (w *Worker) process(ctx context.Context, d *webhook.Delivery) (bool, error) {
+	hook, err := w.webhooks.FindByID(ctx, d.WebhookID)
+	if errors.Is(err, webhook.ErrWebhookNotFound) {
+		d.Abandon("webhook no longer exists")
+		return false, w.deliveries.Update(ctx, d)
+	}
+	if err != nil {
+		return false, err
+	}
+	if !hook.Enabled {
+		d.Abandon(webhook.ErrWebhookDisabled.Error())
+		return false, w.deliveries.Update(ctx, d)
+	}
+
+	started := w.now()
+	status, sendErr := w.send(ctx, hook, d)
+	duration := w.now().Sub(started)
+
+	if sendErr == nil {
+		d.Succeed(status, duration, w.now())
+		if err := w.deliveries.Update(ctx, d); err != nil {
+			return false, err
+		}
+		return true, w.recordResult(ctx, hook.Id, true)
+	}
+
+	if d.Attempts+1 < w.config.MaxAttempts {
+		d.Retry(status, duration, sendErr.Error(), w.now().Add(outbox.Backoff(w.config.BaseBackoff, w.config.MaxBackoff, d.Attempts+1)))
+		return false, w.deliveries.Update(ctx, d)
+	}
+
+	slog.WarnContext(ctx, "Giving up on webhook delivery", "delivery_id", d.ID, "event_type", d.EventType, "url", hook.URL, "attempts", d.Attempts+1, "error", sendErr)
+	d.Fail(status, duration, sendErr.Error())
+	if err := w.deliveries.Update(ctx, d); err != nil {
+		return false, err
+	}
+	return false, w.recordResult(ctx, hook.Id, false)
+}
+
+// send posts the payload and returns the response status, 0 if none was received
+func (w *Worker) send(ctx context.Context, hook *webhook.Webhook, d *webhook.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := w.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Parrotflow-Webhook/1.0")
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderDelivery, d.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, timestamp, d.Payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Drain a little of the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// recordResult updates the failure streak of a webhook, reloading it if a user
+// modified it concurrently
+func (w *Worker) recordResult(ctx context.Context, id webhook.WebhookID, succeeded bool) error {
+	return command.RetryOnConflict(ctx, func() error {
+		hook, err := w.webhooks.FindByID(ctx, id)
+		if err != nil {
+			// Deleted in the meantime, nothing left to record
+			if errors.Is(err, webhook.ErrWebhookNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if succeeded {
+			if !hook.RecordDeliverySuccess() {
+				return nil
+			}
+		} else {
+			hook.RecordDeliveryFailure(w.config.DisableAfter)
+			if !hook.Enabled {
+				slog.WarnContext(ctx, "Disabled webhook", "webhook_id", hook.Id.String(), "url", hook.URL, "reason", hook.DisabledReason)
+			}
+		}
+
+		// Save also records the disabled event in the outbox
+		return w.webhooks.Save(ctx, hook)
+	})
+}
+
+// newClient returns the client deliveries are sent with; it does not follow redirects
+// and, unless AllowPrivateTargets, refuses to connect to internal addresses
+// The address is checked once resolved, so names pointing inside are refused as well
+func newClient(config Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !config.AllowPrivateTargets {
+		dialer := &net.Dialer{Timeout: config.Timeout, Control: refuseInternalAddress}
+		transport.DialContext = dialer.DialContext
+		// A proxy would connect to the target on our behalf, past the check
+		transport.Proxy = nil
+	}
+
+	return &http.Client{
+		Timeout:   config.Timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// refuseInternalAddress is a net.Dialer Control rejecting loopback, private, link-local
+// and unspecified addresses
+func refuseInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%w: %s", ErrForbiddenTarget, ip)
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/domain/webhook"
+)
+
+// MockWebhookRepository keeps webhooks in memory
+type MockWebhookRepository struct {
+	webhook.Repository
+	webhooks map[string]*webhook.Webhook
+}
+
+func (m *MockWebhookRepository) Save(ctx context.Context, w *webhook.Webhook) error {
+	w.Version++
+	m.webhooks[w.Id.String()] = w
+	return nil
+}
+
+func (m *MockWebhookRepository) FindByID(ctx context.Context, id webhook.WebhookID) (*webhook.Webhook, error) {
+	w, ok := m.webhooks[id.String()]
+	if !ok {
+		return nil, webhook.ErrWebhookNotFound
+	}
+	return w, nil
+}
+
+func (m *MockWebhookRepository) FindSubscribed(ctx context.Context, eventType string) ([]*webhook.Webhook, error) {
+	var subscribed []*webhook.Webhook
+	for _, w := range m.webhooks {
+		if w.Subscribes(eventType) {
+			subscribed = append(subscribed, w)
+		}
+	}
+	return subscribed, nil
+}
+
+// MockDeliveryRepository keeps deliveries in memory, in enqueue order
+type MockDeliveryRepository struct {
+	webhook.DeliveryRepository
+	deliveries []*webhook.Delivery
+}
+
+func (m *MockDeliveryRepository) Enqueue(ctx context.Context, deliveries ...*webhook.Delivery) error {
+	for _, d := range deliveries {
+		d.ID = strconv.Itoa(len(m.deliveries) + 1)
+		m.deliveries = append(m.deliveries, d)
+	}
+	return nil
+}
+
+func (m *MockDeliveryRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]*webhook.Delivery, error) {
+	var due []*webhook.Delivery
+	for _, d := range m.deliveries {
+		if d.Status == webhook.DeliveryStatusPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	return due, nil
+}
+
+func (m *MockDeliveryRepository) Update(ctx context.Context, d *webhook.Delivery) error {
+	return nil
+}
+
+const testSecret = "0123456789abcdef0123"
+
+func newTestSetup(t *testing.T, url string, config Config) (*Worker, *Sink, *MockWebhookRepository, *MockDeliveryRepository) {
+	t.Helper()
+	id, _ := webhook.NewWebhookID("1")
+	w, err := webhook.NewWebhook(id, url, []string{run.EventRunFailed}, testSecret)
+	if err != nil {
+		t.Fatalf("NewWebhook() error = %v", err)
+	}
+
+	w.ClearEvents()
+
+	// Test servers listen on loopback
+	config.AllowPrivateTargets = true
+	webhooks := &MockWebhookRepository{webhooks: map[string]*webhook.Webhook{"1": w}}
+	deliveries := &MockDeliveryRepository{}
+	worker := NewWorker(webhooks, deliveries, config)
+	return worker, NewSink(webhooks, deliveries, worker), webhooks, deliveries
+}
+
+func newRunFailed() shared.DomainEvent {
+	return run.RunFailed{
+		BaseEvent: shared.NewBaseEvent(run.EventRunFailed, "7"),
+		RunID:     "7",
+		Reason:    "boom",
+	}
+}
+
+func TestVerify_RejectsTamperedBody(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"type":"RunFailed"}`)
+	signature := Sign(testSecret, now.Unix(), body)
+	timestamp := "1700000000"
+
+	if err := Verify(testSecret, signature, timestamp, body, time.Minute, now); err != nil {
+		t.Errorf("Verify() error = %v, want nil", err)
+	}
+	if err := Verify(testSecret, signature, timestamp, []byte(`{"type":"RunCompleted"}`), time.Minute, now); err == nil {
+		t.Error("Verify() accepted a tampered body")
+	}
+	if err := Verify(testSecret, signature, timestamp, body, time.Minute, now.Add(time.Hour)); err == nil {
+		t.Error("Verify() accepted a stale timestamp")
+	}
+}
+
+func TestWorker_DeliversSignedPayload(t *testing.T) {
+	received := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- Verify(testSecret, r.Header.Get(HeaderSignature), r.Header.Get(HeaderTimestamp), body, time.Minute, time.Now())
+		if r.Header.Get(HeaderEvent) != run.EventRunFailed {
+			t.Errorf("%s = %q, want %q", HeaderEvent, r.Header.Get(HeaderEvent), run.EventRunFailed)
+		}
+	}))
+	defer server.Close()
+
+	worker, sink, _, deliveries := newTestSetup(t, server.URL, DefaultConfig())
+	ctx := context.Background()
+
+	// Unsubscribed events are not enqueued
+	sink.Deliver(ctx, run.RunCompleted{BaseEvent: shared.NewBaseEvent(run.EventRunCompleted, "7")})
+	if err := sink.Deliver(ctx, newRunFailed()); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	if len(deliveries.deliveries) != 1 {
+		t.Fatalf("Enqueued %d deliveries, want 1", len(deliveries.deliveries))
+	}
+
+	if n, err := worker.ProcessDue(ctx); err != nil || n != 1 {
+		t.Fatalf("ProcessDue() = %d, %v, want 1 delivered", n, err)
+	}
+	if err := <-received; err != nil {
+		t.Errorf("Signature check failed: %v", err)
+	}
+	if d := deliveries.deliveries[0]; d.Status != webhook.DeliveryStatusSucceeded || d.ResponseStatus != http.StatusOK {
+		t.Errorf("Delivery = %s (%d), want succeeded (200)", d.Status, d.ResponseStatus)
+	}
+}
+
+func TestWorker_RetriesWithBackoffThenDisables(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.MaxAttempts = 3
+	config.BaseBackoff = time.Minute
+	config.DisableAfter = 1
+	worker, sink, webhooks, deliveries := newTestSetup(t, server.URL, config)
+
+	now := time.Now()
+	worker.now = func() time.Time { return now }
+	sink.now = worker.now
+	ctx := context.Background()
+	sink.Deliver(ctx, newRunFailed())
+	d := deliveries.deliveries[0]
+
+	// 1st attempt fails and is retried after 1m, the 2nd after 2m, the 3rd gives up
+	for attempt, wantDelay := range []time.Duration{time.Minute, 2 * time.Minute} {
+		worker.ProcessDue(ctx)
+		if d.Status != webhook.DeliveryStatusPending || d.NextAttemptAt.Sub(now) != wantDelay {
+			t.Fatalf("After attempt %d: status %s, next attempt in %v, want pending in %v", attempt+1, d.Status, d.NextAttemptAt.Sub(now), wantDelay)
+		}
+		if d.ResponseStatus != http.StatusInternalServerError {
+			t.Errorf("ResponseStatus = %d, want 500", d.ResponseStatus)
+		}
+
+		// Nothing is due before the backoff elapsed
+		if n, _ := worker.ProcessDue(ctx); n != 0 || d.Attempts != attempt+1 {
+			t.Fatalf("Delivery retried before its backoff elapsed")
+		}
+		now = d.NextAttemptAt
+	}
+
+	worker.ProcessDue(ctx)
+	if d.Status != webhook.DeliveryStatusFailed || d.Attempts != 3 {
+		t.Errorf("Delivery = %s after %d attempts, want failed after 3", d.Status, d.Attempts)
+	}
+
+	hook := webhooks.webhooks["1"]
+	if hook.Enabled || hook.ConsecutiveFailures != 1 || len(hook.Events) != 1 {
+		t.Errorf("Webhook enabled = %v, failures = %d, events = %d; want disabled with a WebhookDisabled event",
+			hook.Enabled, hook.ConsecutiveFailures, len(hook.Events))
+	}
+
+	// A disabled webhook receives nothing new
+	sink.Deliver(ctx, run.RunFailed{BaseEvent: shared.NewBaseEvent(run.EventRunFailed, "8"), RunID: "8"})
+	if len(deliveries.deliveries) != 1 {
+		t.Errorf("Enqueued %d deliveries for a disabled webhook", len(deliveries.deliveries)-1)
+	}
+}
+
+func TestWorker_RefusesInternalTargetsAndRedirects(t *testing.T) {
+	reached := false
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	defer internal.Close()
+	redirecting := httptest.NewServer(http.RedirectHandler(internal.URL, http.StatusFound))
+	defer redirecting.Close()
+
+	hook, _ := webhook.NewWebhook(webhook.WebhookID{}, internal.URL, []string{webhook.AllEvents}, testSecret)
+	d := &webhook.Delivery{ID: "1", EventType: run.EventRunFailed, Payload: []byte(`{}`)}
+	ctx := context.Background()
+
+	worker := NewWorker(&MockWebhookRepository{}, &MockDeliveryRepository{}, DefaultConfig())
+	if _, err := worker.send(ctx, hook, d); !errors.Is(err, ErrForbiddenTarget) {
+		t.Errorf("send() to loopback error = %v, want ErrForbiddenTarget", err)
+	}
+
+	// Allowed to reach the redirecting server, the worker still does not follow it inside
+	config := DefaultConfig()
+	config.AllowPrivateTargets = true
+	worker = NewWorker(&MockWebhookRepository{}, &MockDeliveryRepository{}, config)
+	hook.URL = redirecting.URL
+	if status, err := worker.send(ctx, hook, d); status != http.StatusFound || err == nil {
+		t.Errorf("send() = %d, %v; want a failed 302", status, err)
+	}
+	if reached {
+		t.Error("Redirect was followed")
+	}
+}
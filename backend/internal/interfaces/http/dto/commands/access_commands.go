@@ -0,0 +1,29 @@
+package commands
+
+type AssignRolesRequest struct {
+	Subject string `path:"subject" doc:"apikey:<id> for an API key, the sub claim for a JWT"`
+	Body    struct {
+		Roles []string `json:"roles" minItems:"1" enum:"viewer,operator,author,admin" doc:"Roles replacing those the subject has"`
+	}
+}
+
+type AssignRolesResponse struct {
+	Body RoleAssignmentDTO
+}
+
+type UnassignRolesRequest struct {
+	Subject string `path:"subject" doc:"apikey:<id> for an API key, the sub claim for a JWT"`
+}
+
+type UnassignRolesResponse struct {
+	Body struct {
+		Success bool `json:"success"`
+	}
+}
+
+type RoleAssignmentDTO struct {
+	Subject   string   `json:"subject"`
+	Roles     []string `json:"roles"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
@@ -6,6 +6,7 @@ type RegisterAgentRequest struct {
 		Name           string                  `json:"name" minLength:"1" maxLength:"255" doc:"Agent name"`
 		Capabilities   CapabilitiesDTO         `json:"capabilities" doc:"Agent capabilities"`
 		ConnectionInfo ConnectionInfoDTO       `json:"connection_info" doc:"Agent connection information"`
+		EnrollmentToken string                 `json:"enrollment_token" minLength:"1" doc:"One-time token minted by an admin (pfe_...), exchanged for the agent credential"`
 	}
 }
 
@@ -18,12 +19,18 @@ type RegisterAgentResponse struct {
 		Capabilities   CapabilitiesDTO         `json:"capabilities" doc:"Agent capabilities"`
 		ConnectionInfo ConnectionInfoDTO       `json:"connection_info" doc:"Agent connection information"`
 		RegisteredAt   string                  `json:"registered_at" doc:"Registration timestamp"`
+		Credential     string                  `json:"credential" doc:"Secret the agent signs heartbeats and progress reports with; only returned at registration, store it safely"`
 	}
 }
 
 // UpdateHeartbeatRequest represents the request to update agent heartbeat
+// It is signed with the agent credential instead of carrying an API key
 type UpdateHeartbeatRequest struct {
-	ID string `path:"id" doc:"Agent ID"`
+	ID        string `path:"id" doc:"Agent ID"`
+	Timestamp string `header:"X-Parrotflow-Timestamp" required:"true" doc:"Unix seconds the request was signed at, at most 5 minutes off"`
+	Nonce     string `header:"X-Parrotflow-Nonce" required:"true" maxLength:"64" doc:"Unique per request, a request carrying a used nonce is refused as a replay"`
+	Signature string `header:"X-Parrotflow-Signature" required:"true" doc:"sha256=hex(HMAC-SHA256(credential, \"<timestamp>.<nonce>.heartbeat.<body>\"))"`
+	RawBody   []byte
 }
 
 // UpdateHeartbeatResponse represents the response after updating heartbeat
@@ -68,8 +75,9 @@ type ReleaseRunResponse struct {
 
 // UpdateAgentRequest represents the request to update an agent
 type UpdateAgentRequest struct {
-	ID   string `path:"id" doc:"Agent ID"`
-	Body struct {
+	ID      string `path:"id" doc:"Agent ID"`
+	IfMatch string `header:"If-Match" doc:"ETag of the version being modified; a stale value fails with 409 Conflict"`
+	Body    struct {
 		Name         *string          `json:"name,omitempty" minLength:"1" maxLength:"255" doc:"Agent name"`
 		Capabilities *CapabilitiesDTO `json:"capabilities,omitempty" doc:"Agent capabilities"`
 		TagsToAdd    []string         `json:"tags_to_add,omitempty" doc:"Tag IDs to add"`
@@ -79,6 +87,7 @@ type UpdateAgentRequest struct {
 
 // UpdateAgentResponse represents the response after updating an agent
 type UpdateAgentResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
 	Body struct {
 		ID           string          `json:"id" doc:"Agent ID"`
 		Name         string          `json:"name" doc:"Agent name"`
@@ -101,6 +110,21 @@ type DeregisterAgentResponse struct {
 	}
 }
 
+// RevokeAgentRequest represents the request to revoke an agent
+type RevokeAgentRequest struct {
+	ID string `path:"id" doc:"Agent ID"`
+}
+
+// RevokeAgentResponse represents the response after revoking an agent
+type RevokeAgentResponse struct {
+	Body struct {
+		ID        string `json:"id" doc:"Agent ID"`
+		Name      string `json:"name" doc:"Agent name"`
+		Status    string `json:"status" doc:"Agent status"`
+		RevokedAt string `json:"revoked_at" doc:"When the agent credential stopped working"`
+	}
+}
+
 // CapabilitiesDTO represents agent capabilities in API format
 type CapabilitiesDTO struct {
 	Browsers []BrowserCapabilityDTO `json:"browsers" doc:"Supported browsers"`
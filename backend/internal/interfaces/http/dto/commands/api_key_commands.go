@@ -0,0 +1,38 @@
+package commands
+
+import "time"
+
+type CreateAPIKeyRequest struct {
+	Body struct {
+		Name      string     `json:"name" minLength:"1" maxLength:"255" doc:"What the key is for, e.g. the integration using it"`
+		Scopes    []string   `json:"scopes" minItems:"1" enum:"read,write,admin" doc:"read allows queries, write commands as well, admin also manages API keys"`
+		ExpiresAt *time.Time `json:"expires_at,omitempty" doc:"When the key stops working (RFC 3339), never when omitted"`
+	}
+}
+
+type CreateAPIKeyResponse struct {
+	Body struct {
+		APIKeyDTO
+		Key string `json:"key" doc:"The API key; only returned when it is created, store it safely"`
+	}
+}
+
+type RevokeAPIKeyRequest struct {
+	ID string `path:"id"`
+}
+
+type RevokeAPIKeyResponse struct {
+	Body APIKeyDTO
+}
+
+// APIKeyDTO never includes the key or its hash
+type APIKeyDTO struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix" doc:"Public part of the key, pf_<prefix>_..., to tell keys apart"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  *string  `json:"expires_at,omitempty"`
+	RevokedAt  *string  `json:"revoked_at,omitempty"`
+	LastUsedAt *string  `json:"last_used_at,omitempty" doc:"Updated at most once a minute"`
+	CreatedAt  string   `json:"created_at"`
+}
@@ -0,0 +1,26 @@
+package commands
+
+type ReplayDeadLetterRequest struct {
+	ID string `path:"id"`
+}
+
+type ReplayDeadLetterResponse struct {
+	Body struct {
+		ID         string `json:"id"`
+		Queue      string `json:"queue" doc:"Queue the message was published to again"`
+		Status     string `json:"status"`
+		ResolvedAt string `json:"resolved_at"`
+	}
+}
+
+type DiscardDeadLetterRequest struct {
+	ID string `path:"id"`
+}
+
+type DiscardDeadLetterResponse struct {
+	Body struct {
+		ID         string `json:"id"`
+		Status     string `json:"status"`
+		ResolvedAt string `json:"resolved_at"`
+	}
+}
@@ -0,0 +1,37 @@
+package commands
+
+import "time"
+
+type CreateEnrollmentTokenRequest struct {
+	Body struct {
+		Name      string     `json:"name" minLength:"1" maxLength:"255" doc:"What the token is for, e.g. the host the agent will run on"`
+		ExpiresAt *time.Time `json:"expires_at,omitempty" doc:"When the token stops working (RFC 3339), 24 hours from now when omitted"`
+	}
+}
+
+type CreateEnrollmentTokenResponse struct {
+	Body struct {
+		EnrollmentTokenDTO
+		Token string `json:"token" doc:"The enrollment token; only returned when it is created, hand it to the agent"`
+	}
+}
+
+type RevokeEnrollmentTokenRequest struct {
+	ID string `path:"id"`
+}
+
+type RevokeEnrollmentTokenResponse struct {
+	Body EnrollmentTokenDTO
+}
+
+// EnrollmentTokenDTO never includes the token or its hash
+type EnrollmentTokenDTO struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Prefix    string  `json:"prefix" doc:"Public part of the token, pfe_<prefix>_..., to tell tokens apart"`
+	ExpiresAt string  `json:"expires_at"`
+	RevokedAt *string `json:"revoked_at,omitempty"`
+	UsedAt    *string `json:"used_at,omitempty"`
+	AgentID   string  `json:"agent_id,omitempty" doc:"Agent that registered with the token"`
+	CreatedAt string  `json:"created_at"`
+}
@@ -35,8 +35,9 @@ type CreateProxyResponse struct {
 
 // UpdateProxyRequest is the input for updating an existing proxy
 type UpdateProxyRequest struct {
-	ID string `path:"id" doc:"Proxy ID"`
-	Body struct {
+	ID      string `path:"id" doc:"Proxy ID"`
+	IfMatch string `header:"If-Match" doc:"ETag of the version being modified; a stale value fails with 409 Conflict"`
+	Body    struct {
 		Name     *string `json:"name,omitempty" minLength:"1" maxLength:"100" doc:"Proxy name"`
 		Host     *string `json:"host,omitempty" minLength:"1" doc:"Proxy host/IP address"`
 		Port     *int    `json:"port,omitempty" minimum:"1" maximum:"65535" doc:"Proxy port"`
@@ -48,6 +49,7 @@ type UpdateProxyRequest struct {
 
 // UpdateProxyResponse is the output after updating a proxy
 type UpdateProxyResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
 	Body struct {
 		ID              string   `json:"id" doc:"Unique proxy identifier"`
 		Name            string   `json:"name" doc:"Proxy name"`
@@ -78,6 +80,21 @@ type DeleteProxyResponse struct {
 	}
 }
 
+// RestoreProxyRequest is the input for taking a proxy out of the trash
+type RestoreProxyRequest struct {
+	ID string `path:"id" doc:"Proxy ID"`
+}
+
+// RestoreProxyResponse is the output after restoring a proxy
+type RestoreProxyResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
+	Body struct {
+		ID     string `json:"id" doc:"Unique proxy identifier"`
+		Name   string `json:"name" doc:"Proxy name"`
+		Status string `json:"status" doc:"Current proxy status"`
+	}
+}
+
 // RecordHealthRequest is the input for recording proxy health check results
 type RecordHealthRequest struct {
 	ID string `path:"id" doc:"Proxy ID"`
@@ -28,3 +28,38 @@ type StartRunResponse struct {
 		StartedAt string `json:"started_at"`
 	}
 }
+
+type CancelRunRequest struct {
+	ID string `path:"id"`
+}
+
+type CancelRunResponse struct {
+	Body struct {
+		ID         string `json:"id"`
+		Status     string `json:"status"`
+		FinishedAt string `json:"finished_at"`
+	}
+}
+
+// ReportRunProgressRequest is signed with the credential of the reporting agent
+type ReportRunProgressRequest struct {
+	ID        string `path:"id"`
+	AgentID   string `header:"X-Parrotflow-Agent" required:"true" doc:"ID of the reporting agent"`
+	Timestamp string `header:"X-Parrotflow-Timestamp" required:"true" doc:"Unix seconds the request was signed at, at most 5 minutes off"`
+	Nonce     string `header:"X-Parrotflow-Nonce" required:"true" maxLength:"64" doc:"Unique per request, a request carrying a used nonce is refused as a replay"`
+	Signature string `header:"X-Parrotflow-Signature" required:"true" doc:"sha256=hex(HMAC-SHA256(credential, \"<timestamp>.<nonce>.progress.<run id>.<body>\"))"`
+	RawBody   []byte
+	Body      struct {
+		NodeID  string `json:"node_id" minLength:"1" doc:"Scenario node the report is about"`
+		Status  string `json:"status" enum:"RUNNING,COMPLETED,FAILED,SKIPPED" doc:"Node execution status"`
+		Message string `json:"message,omitempty" doc:"Free-form detail, e.g. an error message"`
+	}
+}
+
+type ReportRunProgressResponse struct {
+	Body struct {
+		ID        string `json:"id"`
+		Status    string `json:"status"`
+		UpdatedAt string `json:"updated_at"`
+	}
+}
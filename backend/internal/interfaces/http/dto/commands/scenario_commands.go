@@ -27,8 +27,9 @@ type CreateScenarioResponse struct {
 }
 
 type UpdateScenarioRequest struct {
-	ID   string `path:"id"`
-	Body struct {
+	ID      string `path:"id"`
+	IfMatch string `header:"If-Match" doc:"ETag of the version being modified; a stale value fails with 409 Conflict"`
+	Body    struct {
 		Name        *string               `json:"name,omitempty"`
 		Description *string               `json:"description,omitempty"`
 		Tag         *string               `json:"tag,omitempty"`
@@ -40,6 +41,7 @@ type UpdateScenarioRequest struct {
 }
 
 type UpdateScenarioResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
 	Body struct {
 		ID          string                `json:"id"`
 		Name        string                `json:"name"`
@@ -62,3 +64,32 @@ type DeleteScenarioResponse struct {
 		Success bool `json:"success"`
 	}
 }
+
+type RestoreScenarioRequest struct {
+	ID string `path:"id"`
+}
+
+type RestoreScenarioResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
+	Body struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+}
+
+type SetScenarioRetentionRequest struct {
+	ID   string `path:"id"`
+	Body shared.RetentionPolicyDTO
+}
+
+type ClearScenarioRetentionRequest struct {
+	ID string `path:"id"`
+}
+
+type ScenarioRetentionResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
+	Body struct {
+		ID        string                     `json:"id"`
+		Retention *shared.RetentionPolicyDTO `json:"retention,omitempty" doc:"Run retention override, absent when the global policy applies"`
+	}
+}
@@ -0,0 +1,48 @@
+package commands
+
+type CreateSecretRequest struct {
+	Body struct {
+		Name        string `json:"name" pattern:"^[A-Za-z_][A-Za-z0-9_.-]{0,63}$" doc:"Name scenarios reference the secret by, as ${secret:NAME}"`
+		Value       string `json:"value" minLength:"1" doc:"Secret value; it is encrypted and never returned"`
+		Description string `json:"description,omitempty" doc:"Secret description"`
+	}
+}
+
+type CreateSecretResponse struct {
+	Body struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		Reference   string `json:"reference" doc:"How scenario parameters reference the secret"`
+		Description string `json:"description"`
+		CreatedAt   string `json:"created_at"`
+	}
+}
+
+type UpdateSecretRequest struct {
+	ID      string `path:"id"`
+	IfMatch string `header:"If-Match" doc:"ETag of the version being modified; a stale value fails with 409 Conflict"`
+	Body    struct {
+		Value       *string `json:"value,omitempty" minLength:"1" doc:"New secret value, used by runs started afterwards"`
+		Description *string `json:"description,omitempty" doc:"Secret description"`
+	}
+}
+
+type UpdateSecretResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
+	Body struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		UpdatedAt   string `json:"updated_at"`
+	}
+}
+
+type DeleteSecretRequest struct {
+	ID string `path:"id"`
+}
+
+type DeleteSecretResponse struct {
+	Body struct {
+		Success bool `json:"success"`
+	}
+}
@@ -23,14 +23,16 @@ type CreateTagResponse struct {
 }
 
 type UpdateTagRequest struct {
-	ID   string `path:"id"`
-	Body struct {
+	ID      string `path:"id"`
+	IfMatch string `header:"If-Match" doc:"ETag of the version being modified; a stale value fails with 409 Conflict"`
+	Body    struct {
 		Description *string `json:"description,omitempty" doc:"Tag description"`
 		Color       *string `json:"color,omitempty" pattern:"^#[0-9A-Fa-f]{6}$" doc:"Hex color (#RRGGBB)"`
 	}
 }
 
 type UpdateTagResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
 	Body struct {
 		ID          string `json:"id"`
 		Name        string `json:"name"`
@@ -51,3 +53,15 @@ type DeleteTagResponse struct {
 		Success bool `json:"success"`
 	}
 }
+
+type RestoreTagRequest struct {
+	ID string `path:"id"`
+}
+
+type RestoreTagResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
+	Body struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+}
@@ -0,0 +1,67 @@
+package commands
+
+type CreateWebhookRequest struct {
+	Body struct {
+		URL         string   `json:"url" format:"uri" maxLength:"2048" doc:"Endpoint receiving POST requests"`
+		EventTypes  []string `json:"event_types" minItems:"1" doc:"Event types to deliver, e.g. RunFailed, or * for every event"`
+		Secret      string   `json:"secret,omitempty" minLength:"16" maxLength:"255" doc:"HMAC-SHA256 signing secret, generated when omitted"`
+		Description string   `json:"description,omitempty" doc:"Webhook description"`
+	}
+}
+
+type CreateWebhookResponse struct {
+	Body struct {
+		ID          string   `json:"id"`
+		URL         string   `json:"url"`
+		EventTypes  []string `json:"event_types"`
+		Secret      string   `json:"secret" doc:"Signing secret; only returned when the webhook is created"`
+		Description string   `json:"description"`
+		Enabled     bool     `json:"enabled"`
+		CreatedAt   string   `json:"created_at"`
+	}
+}
+
+type UpdateWebhookRequest struct {
+	ID      string `path:"id"`
+	IfMatch string `header:"If-Match" doc:"ETag of the version being modified; a stale value fails with 409 Conflict"`
+	Body    struct {
+		URL         *string  `json:"url,omitempty" format:"uri" maxLength:"2048" doc:"Endpoint receiving POST requests"`
+		EventTypes  []string `json:"event_types,omitempty" minItems:"1" doc:"Event types to deliver, or * for every event"`
+		Secret      *string  `json:"secret,omitempty" minLength:"16" maxLength:"255" doc:"New signing secret"`
+		Description *string  `json:"description,omitempty" doc:"Webhook description"`
+	}
+}
+
+type UpdateWebhookResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
+	Body struct {
+		ID          string   `json:"id"`
+		URL         string   `json:"url"`
+		EventTypes  []string `json:"event_types"`
+		Description string   `json:"description"`
+		Enabled     bool     `json:"enabled"`
+		UpdatedAt   string   `json:"updated_at"`
+	}
+}
+
+type DeleteWebhookRequest struct {
+	ID string `path:"id"`
+}
+
+type DeleteWebhookResponse struct {
+	Body struct {
+		Success bool `json:"success"`
+	}
+}
+
+type EnableWebhookRequest struct {
+	ID string `path:"id"`
+}
+
+type EnableWebhookResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
+	Body struct {
+		ID      string `json:"id"`
+		Enabled bool   `json:"enabled"`
+	}
+}
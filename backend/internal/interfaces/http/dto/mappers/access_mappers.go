@@ -0,0 +1,87 @@
+package mappers
+
+import (
+	"slices"
+
+	"parrotflow/internal/domain/access"
+	"parrotflow/internal/infrastructure/auth"
+	"parrotflow/internal/interfaces/http/dto/commands"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+func buildRoleAssignmentDTO(a *access.RoleAssignment) commands.RoleAssignmentDTO {
+	return commands.RoleAssignmentDTO{
+		Subject:   a.Subject,
+		Roles:     roleNames(a.Roles),
+		CreatedAt: FormatTimestamp(a.CreatedAt.Time()),
+		UpdatedAt: FormatTimestamp(a.UpdatedAt.Time()),
+	}
+}
+
+func roleNames(roles []access.Role) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.String()
+	}
+	return names
+}
+
+func permissionNames(permissions []access.Permission) []string {
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = permission.String()
+	}
+	return names
+}
+
+func RoleAssignmentToAssignResponse(a *access.RoleAssignment) *commands.AssignRolesResponse {
+	return &commands.AssignRolesResponse{Body: buildRoleAssignmentDTO(a)}
+}
+
+func RoleAssignmentToUnassignResponse() *commands.UnassignRolesResponse {
+	response := &commands.UnassignRolesResponse{}
+	response.Body.Success = true
+	return response
+}
+
+func RoleAssignmentToListResponse(assignments []*access.RoleAssignment) *queries.ListRoleAssignmentsResponse {
+	response := &queries.ListRoleAssignmentsResponse{}
+	response.Body.Assignments = MapSlicePtr(assignments, buildRoleAssignmentDTO)
+	return response
+}
+
+func AccessPolicyToResponse() *queries.GetAccessPolicyResponse {
+	response := &queries.GetAccessPolicyResponse{}
+	for _, role := range access.Roles {
+		response.Body.Roles = append(response.Body.Roles, queries.RolePolicyDTO{
+			Role:        role.String(),
+			Permissions: permissionNames(access.PermissionsOf(role)),
+		})
+	}
+	return response
+}
+
+func PrincipalToResponse(principal *auth.Principal) *queries.GetCurrentPrincipalResponse {
+	var permissions []access.Permission
+	for _, permission := range access.Permissions {
+		if principal.Can(permission) {
+			permissions = append(permissions, permission)
+		}
+	}
+
+	response := &queries.GetCurrentPrincipalResponse{}
+	response.Body.Subject = principal.Subject
+	response.Body.Name = principal.Name
+	response.Body.Method = principal.Method
+	response.Body.Scopes = slices.Clone(principal.Scopes)
+	response.Body.Roles = roleNames(principal.Roles)
+	response.Body.Permissions = permissionNames(permissions)
+	return response
+}
+
+// Mapper instances for handler injection
+var (
+	RoleAssignmentAssignMapper   = UpdateMapperFunc[*access.RoleAssignment, *commands.AssignRolesResponse](RoleAssignmentToAssignResponse)
+	RoleAssignmentUnassignMapper = DeleteMapperFunc[*commands.UnassignRolesResponse](RoleAssignmentToUnassignResponse)
+	RoleAssignmentListMapper     = ListMapperFunc[access.RoleAssignment, *queries.ListRoleAssignmentsResponse](RoleAssignmentToListResponse)
+)
@@ -6,8 +6,9 @@ import (
 	"parrotflow/internal/interfaces/http/dto/queries"
 )
 
-// ToRegisterAgentResponse converts domain agent to register response DTO
-func ToRegisterAgentResponse(a *agent.Agent) *commands.RegisterAgentResponse {
+// ToRegisterAgentResponse converts an enrolled agent to register response DTO
+func ToRegisterAgentResponse(enrolled *agent.EnrolledAgent) *commands.RegisterAgentResponse {
+	a := enrolled.Agent
 	response := &commands.RegisterAgentResponse{}
 	response.Body.ID = a.Id.String()
 	response.Body.Name = a.Name
@@ -15,6 +16,7 @@ func ToRegisterAgentResponse(a *agent.Agent) *commands.RegisterAgentResponse {
 	response.Body.Capabilities = ToCapabilitiesDTO(a.Capabilities)
 	response.Body.ConnectionInfo = ToConnectionInfoDTO(a.ConnectionInfo)
 	response.Body.RegisteredAt = a.RegisteredAt.Time().Format("2006-01-02T15:04:05Z07:00")
+	response.Body.Credential = enrolled.Secret
 	return response
 }
 
@@ -53,6 +55,7 @@ func ToReleaseRunResponse(a *agent.Agent) *commands.ReleaseRunResponse {
 // ToUpdateAgentResponse converts domain agent to update response DTO
 func ToUpdateAgentResponse(a *agent.Agent) *commands.UpdateAgentResponse {
 	response := &commands.UpdateAgentResponse{}
+	response.ETag = FormatETag(a.Version)
 	response.Body.ID = a.Id.String()
 	response.Body.Name = a.Name
 	response.Body.Status = a.Status.String()
@@ -72,9 +75,22 @@ func ToDeregisterAgentResponse() *commands.DeregisterAgentResponse {
 	return response
 }
 
+// ToRevokeAgentResponse converts a revoked agent to revoke response DTO
+func ToRevokeAgentResponse(a *agent.Agent) *commands.RevokeAgentResponse {
+	response := &commands.RevokeAgentResponse{}
+	response.Body.ID = a.Id.String()
+	response.Body.Name = a.Name
+	response.Body.Status = a.Status.String()
+	if a.IsRevoked() {
+		response.Body.RevokedAt = FormatTimestamp(*a.Credential.RevokedAt)
+	}
+	return response
+}
+
 // ToGetAgentResponse converts domain agent to get response DTO
 func ToGetAgentResponse(a *agent.Agent) *queries.GetAgentResponse {
 	response := &queries.GetAgentResponse{}
+	response.ETag = FormatETag(a.Version)
 	response.Body = ToAgentDTO(a)
 	return response
 }
@@ -137,6 +153,13 @@ func ToAgentDTO(a *agent.Agent) queries.AgentDTO {
 		dto.LastHeartbeatAt = &heartbeat
 	}
 
+	if a.Credential != nil {
+		if !a.Credential.IssuedAt.IsZero() {
+			dto.CredentialIssuedAt = optionalTimestamp(&a.Credential.IssuedAt)
+		}
+		dto.RevokedAt = optionalTimestamp(a.Credential.RevokedAt)
+	}
+
 	return dto
 }
 
@@ -279,11 +302,12 @@ func FromConnectionInfoDTO(dto commands.ConnectionInfoDTO) (agent.ConnectionInfo
 
 // Mapper instances using functional types - eliminates empty struct boilerplate
 var (
-	AgentRegisterMapper       = CreateMapperFunc[*agent.Agent, *commands.RegisterAgentResponse](ToRegisterAgentResponse)
+	AgentRegisterMapper       = CreateMapperFunc[*agent.EnrolledAgent, *commands.RegisterAgentResponse](ToRegisterAgentResponse)
 	AgentHeartbeatMapper      = CreateMapperFunc[*agent.Agent, *commands.UpdateHeartbeatResponse](ToUpdateHeartbeatResponse)
 	AgentAssignRunMapper      = CreateMapperFunc[*agent.Agent, *commands.AssignRunResponse](ToAssignRunResponse)
 	AgentReleaseRunMapper     = CreateMapperFunc[*agent.Agent, *commands.ReleaseRunResponse](ToReleaseRunResponse)
 	AgentUpdateMapper         = UpdateMapperFunc[*agent.Agent, *commands.UpdateAgentResponse](ToUpdateAgentResponse)
+	AgentRevokeMapper         = UpdateMapperFunc[*agent.Agent, *commands.RevokeAgentResponse](ToRevokeAgentResponse)
 	AgentDeregisterMapper     = DeleteMapperFunc[*commands.DeregisterAgentResponse](ToDeregisterAgentResponse)
 	AgentGetMapper            = GetMapperFunc[*agent.Agent, *queries.GetAgentResponse](ToGetAgentResponse)
 	AgentListMapper           = ListMapperFunc[agent.Agent, *queries.ListAgentsResponse](ToListAgentsResponse)
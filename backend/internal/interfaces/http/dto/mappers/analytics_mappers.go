@@ -0,0 +1,82 @@
+package mappers
+
+import (
+	"parrotflow/internal/domain/analytics"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+func AnalyticsReportToResponse(report *analytics.Report) *queries.GetAnalyticsResponse {
+	response := &queries.GetAnalyticsResponse{}
+	response.Body = queries.AnalyticsReportDTO{
+		ScenarioID:     report.Criteria.ScenarioID,
+		Since:          FormatTimestamp(report.Criteria.Since),
+		Until:          FormatTimestamp(report.Criteria.Until),
+		Interval:       report.Criteria.Interval,
+		Source:         report.Criteria.Source,
+		Runs:           mapRunOutcomesToDTO(report.Counts),
+		Durations:      mapRunDurationsToDTO(report.Durations),
+		Buckets:        MapSlice(report.Buckets, mapAnalyticsBucketToDTO),
+		FailureReasons: MapSlice(report.FailureReasons, mapFailureReasonToDTO),
+		FailingNodes:   MapSlice(report.FailingNodes, mapFailingNodeToDTO),
+		Scenarios:      MapSlice(report.Scenarios, mapScenarioOutcomesToDTO),
+	}
+	return response
+}
+
+func mapRunOutcomesToDTO(c analytics.Counts) queries.RunOutcomesDTO {
+	return queries.RunOutcomesDTO{
+		Total:       c.Total,
+		Completed:   c.Completed,
+		Failed:      c.Failed,
+		Cancelled:   c.Cancelled,
+		SuccessRate: c.SuccessRate(),
+		FailureRate: c.FailureRate(),
+	}
+}
+
+func mapRunDurationsToDTO(d analytics.Durations) queries.RunDurationsDTO {
+	return queries.RunDurationsDTO{
+		Measured: d.Measured,
+		P50Ms:    d.P50.Milliseconds(),
+		P95Ms:    d.P95.Milliseconds(),
+	}
+}
+
+func mapAnalyticsBucketToDTO(b analytics.Bucket) queries.AnalyticsBucketDTO {
+	return queries.AnalyticsBucketDTO{
+		Start:     FormatTimestamp(b.Start),
+		Runs:      mapRunOutcomesToDTO(b.Counts),
+		Durations: mapRunDurationsToDTO(b.Durations),
+	}
+}
+
+func mapFailureReasonToDTO(r analytics.FailureReason) queries.FailureReasonDTO {
+	return queries.FailureReasonDTO{
+		Reason:     r.Reason,
+		Count:      r.Count,
+		LastSeenAt: FormatTimestamp(r.LastSeenAt),
+	}
+}
+
+func mapFailingNodeToDTO(n analytics.NodeFailures) queries.FailingNodeDTO {
+	return queries.FailingNodeDTO{
+		ScenarioID:  n.ScenarioID,
+		NodeID:      n.NodeID,
+		Runs:        n.Runs,
+		Failures:    n.Failures,
+		FailureRate: n.FailureRate(),
+		LastMessage: n.LastMessage,
+	}
+}
+
+func mapScenarioOutcomesToDTO(s analytics.ScenarioStats) queries.ScenarioOutcomesDTO {
+	return queries.ScenarioOutcomesDTO{
+		ScenarioID: s.ScenarioID,
+		Runs:       mapRunOutcomesToDTO(s.Counts),
+	}
+}
+
+// Mapper instances for handler injection
+var (
+	AnalyticsReportMapper = GetMapperFunc[*analytics.Report, *queries.GetAnalyticsResponse](AnalyticsReportToResponse)
+)
@@ -0,0 +1,54 @@
+package mappers
+
+import (
+	"time"
+
+	"parrotflow/internal/domain/apikey"
+	"parrotflow/internal/interfaces/http/dto/commands"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+func buildAPIKeyDTO(k *apikey.APIKey) commands.APIKeyDTO {
+	return commands.APIKeyDTO{
+		ID:         k.Id.String(),
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		ExpiresAt:  optionalTimestamp(k.ExpiresAt),
+		RevokedAt:  optionalTimestamp(k.RevokedAt),
+		LastUsedAt: optionalTimestamp(k.LastUsedAt),
+		CreatedAt:  FormatTimestamp(k.CreatedAt.Time()),
+	}
+}
+
+func optionalTimestamp(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := FormatTimestamp(*t)
+	return &formatted
+}
+
+func APIKeyToCreateResponse(issued *apikey.IssuedKey) *commands.CreateAPIKeyResponse {
+	response := &commands.CreateAPIKeyResponse{}
+	response.Body.APIKeyDTO = buildAPIKeyDTO(issued.APIKey)
+	response.Body.Key = issued.Token
+	return response
+}
+
+func APIKeyToRevokeResponse(k *apikey.APIKey) *commands.RevokeAPIKeyResponse {
+	return &commands.RevokeAPIKeyResponse{Body: buildAPIKeyDTO(k)}
+}
+
+func APIKeyToListResponse(keys []*apikey.APIKey) *queries.ListAPIKeysResponse {
+	response := &queries.ListAPIKeysResponse{}
+	response.Body.APIKeys = MapSlicePtr(keys, buildAPIKeyDTO)
+	return response
+}
+
+// Mapper instances for handler injection
+var (
+	APIKeyCreateMapper = CreateMapperFunc[*apikey.IssuedKey, *commands.CreateAPIKeyResponse](APIKeyToCreateResponse)
+	APIKeyRevokeMapper = UpdateMapperFunc[*apikey.APIKey, *commands.RevokeAPIKeyResponse](APIKeyToRevokeResponse)
+	APIKeyListMapper   = ListMapperFunc[apikey.APIKey, *queries.ListAPIKeysResponse](APIKeyToListResponse)
+)
@@ -0,0 +1,122 @@
+package mappers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strconv"
+
+	"parrotflow/internal/domain/audit"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/interfaces/http/dto/queries"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Formats the audit log is exported in
+const (
+	AuditExportCSV   = "csv"
+	AuditExportJSONL = "jsonl"
+)
+
+// auditCSVHeader names the columns of CSV exports, in the order of auditCSVRecord
+var auditCSVHeader = []string{
+	"sequence", "occurred_at", "actor", "actor_name", "auth_method", "operation_id", "method", "path",
+	"resource_type", "resource_id", "status", "outcome", "error", "correlation_id", "duration_ms", "request",
+}
+
+func buildAuditEntryDTO(e *audit.Entry) queries.AuditEntryDTO {
+	return queries.AuditEntryDTO{
+		Sequence:      e.Sequence,
+		OccurredAt:    FormatTimestamp(e.OccurredAt),
+		Actor:         e.Actor,
+		ActorName:     e.ActorName,
+		AuthMethod:    e.AuthMethod,
+		OperationID:   e.OperationID,
+		Method:        e.Method,
+		Path:          e.Path,
+		ResourceType:  e.ResourceType,
+		ResourceID:    e.ResourceID,
+		Request:       e.Request,
+		Status:        e.Status,
+		Outcome:       e.Outcome,
+		Error:         e.Error,
+		CorrelationID: e.CorrelationID,
+		DurationMs:    e.Duration.Milliseconds(),
+	}
+}
+
+func auditCSVRecord(e *audit.Entry) []string {
+	dto := buildAuditEntryDTO(e)
+	return []string{
+		strconv.FormatUint(dto.Sequence, 10), dto.OccurredAt, dto.Actor, dto.ActorName, dto.AuthMethod,
+		dto.OperationID, dto.Method, dto.Path, dto.ResourceType, dto.ResourceID, strconv.Itoa(dto.Status),
+		dto.Outcome, dto.Error, dto.CorrelationID, strconv.FormatInt(dto.DurationMs, 10), string(dto.Request),
+	}
+}
+
+func AuditEntriesToListResponse(page, rpp int) func(shared.Page[*audit.Entry]) *queries.ListAuditEntriesResponse {
+	return func(entries shared.Page[*audit.Entry]) *queries.ListAuditEntriesResponse {
+		response := &queries.ListAuditEntriesResponse{}
+		response.Body.Data = MapSlicePtr(entries.Items, buildAuditEntryDTO)
+		response.Body.Total = entries.Total
+		response.Body.Page = page
+		response.Body.RPP = rpp
+		return response
+	}
+}
+
+// AuditExportToStream streams an export as a file download in the format
+// The status is sent before the entries are read, so failures midway only cut the file short
+func AuditExportToStream(format string) func(audit.Export) *huma.StreamResponse {
+	return func(export audit.Export) *huma.StreamResponse {
+		return &huma.StreamResponse{Body: func(ctx huma.Context) {
+			var err error
+			if format == AuditExportCSV {
+				ctx.SetHeader("Content-Type", "text/csv; charset=utf-8")
+				ctx.SetHeader("Content-Disposition", `attachment; filename="audit-log.csv"`)
+				err = writeAuditCSV(ctx.BodyWriter(), export)
+			} else {
+				ctx.SetHeader("Content-Type", "application/jsonl")
+				ctx.SetHeader("Content-Disposition", `attachment; filename="audit-log.jsonl"`)
+				err = writeAuditJSONL(ctx.BodyWriter(), export)
+			}
+			if err != nil {
+				slog.ErrorContext(ctx.Context(), "Audit log export failed", "format", format, "error", err)
+			}
+		}}
+	}
+}
+
+func writeAuditCSV(w io.Writer, export audit.Export) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(auditCSVHeader); err != nil {
+		return err
+	}
+	err := export(func(e *audit.Entry) error {
+		return writer.Write(auditCSVRecord(e))
+	})
+	writer.Flush()
+	if err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+func writeAuditJSONL(w io.Writer, export audit.Export) error {
+	encoder := json.NewEncoder(w)
+	return export(func(e *audit.Entry) error {
+		return encoder.Encode(buildAuditEntryDTO(e))
+	})
+}
+
+// AuditListMapperFactory creates a list mapper with pagination
+func AuditListMapperFactory(page, rpp int) PageMapperFunc[audit.Entry, *queries.ListAuditEntriesResponse] {
+	return PageMapperFunc[audit.Entry, *queries.ListAuditEntriesResponse](AuditEntriesToListResponse(page, rpp))
+}
+
+// AuditExportMapperFactory creates an export mapper for the format
+func AuditExportMapperFactory(format string) GetMapperFunc[audit.Export, *huma.StreamResponse] {
+	return GetMapperFunc[audit.Export, *huma.StreamResponse](AuditExportToStream(format))
+}
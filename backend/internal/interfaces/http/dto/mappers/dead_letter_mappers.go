@@ -0,0 +1,84 @@
+package mappers
+
+import (
+	"encoding/base64"
+	"unicode/utf8"
+
+	"parrotflow/internal/domain/deadletter"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/interfaces/http/dto/commands"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+func buildDeadLetterDTO(d *deadletter.DeadLetter) queries.DeadLetterDTO {
+	dto := queries.DeadLetterDTO{
+		ID:             d.Id.String(),
+		MessageID:      d.MessageID,
+		Queue:          d.Queue,
+		Reason:         d.Reason,
+		ContentType:    d.ContentType,
+		Headers:        d.Headers,
+		DeathCount:     d.DeathCount,
+		Status:         d.Status.String(),
+		DeadLetteredAt: FormatTimestamp(d.DeadLetteredAt),
+		CreatedAt:      FormatTimestamp(d.CreatedAt.Time()),
+	}
+	if utf8.Valid(d.Payload) {
+		dto.Payload = string(d.Payload)
+		dto.PayloadEncoding = "utf-8"
+	} else {
+		dto.Payload = base64.StdEncoding.EncodeToString(d.Payload)
+		dto.PayloadEncoding = "base64"
+	}
+	if d.ResolvedAt != nil {
+		resolved := FormatTimestamp(*d.ResolvedAt)
+		dto.ResolvedAt = &resolved
+	}
+	return dto
+}
+
+func DeadLetterToReplayResponse(d *deadletter.DeadLetter) *commands.ReplayDeadLetterResponse {
+	response := &commands.ReplayDeadLetterResponse{}
+	response.Body.ID = d.Id.String()
+	response.Body.Queue = d.Queue
+	response.Body.Status = d.Status.String()
+	response.Body.ResolvedAt = FormatOptionalTimestamp(d.ResolvedAt)
+	return response
+}
+
+func DeadLetterToDiscardResponse(d *deadletter.DeadLetter) *commands.DiscardDeadLetterResponse {
+	response := &commands.DiscardDeadLetterResponse{}
+	response.Body.ID = d.Id.String()
+	response.Body.Status = d.Status.String()
+	response.Body.ResolvedAt = FormatOptionalTimestamp(d.ResolvedAt)
+	return response
+}
+
+func DeadLetterToGetResponse(d *deadletter.DeadLetter) *queries.GetDeadLetterResponse {
+	response := &queries.GetDeadLetterResponse{}
+	response.Body = buildDeadLetterDTO(d)
+	return response
+}
+
+func DeadLettersToListResponse(page, rpp int) func(shared.Page[*deadletter.DeadLetter]) *queries.ListDeadLettersResponse {
+	return func(deadLetters shared.Page[*deadletter.DeadLetter]) *queries.ListDeadLettersResponse {
+		response := &queries.ListDeadLettersResponse{}
+		response.Body.Data = MapSlicePtr(deadLetters.Items, buildDeadLetterDTO)
+		response.Body.Total = deadLetters.Total
+		response.Body.Page = page
+		response.Body.RPP = rpp
+		return response
+	}
+}
+
+// Mapper instances for handler injection
+var (
+	DeadLetterReplayMapper  = UpdateMapperFunc[*deadletter.DeadLetter, *commands.ReplayDeadLetterResponse](DeadLetterToReplayResponse)
+	DeadLetterDiscardMapper = UpdateMapperFunc[*deadletter.DeadLetter, *commands.DiscardDeadLetterResponse](DeadLetterToDiscardResponse)
+	DeadLetterGetMapper     = GetMapperFunc[*deadletter.DeadLetter, *queries.GetDeadLetterResponse](DeadLetterToGetResponse)
+)
+
+// DeadLetterListMapperFactory creates a list mapper with pagination
+func DeadLetterListMapperFactory(page, rpp int) PageMapperFunc[deadletter.DeadLetter, *queries.ListDeadLettersResponse] {
+	return PageMapperFunc[deadletter.DeadLetter, *queries.ListDeadLettersResponse](DeadLettersToListResponse(page, rpp))
+}
@@ -0,0 +1,44 @@
+package mappers
+
+import (
+	"parrotflow/internal/domain/enrollment"
+	"parrotflow/internal/interfaces/http/dto/commands"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+func buildEnrollmentTokenDTO(t *enrollment.Token) commands.EnrollmentTokenDTO {
+	return commands.EnrollmentTokenDTO{
+		ID:        t.Id.String(),
+		Name:      t.Name,
+		Prefix:    t.Prefix,
+		ExpiresAt: FormatTimestamp(t.ExpiresAt),
+		RevokedAt: optionalTimestamp(t.RevokedAt),
+		UsedAt:    optionalTimestamp(t.UsedAt),
+		AgentID:   t.AgentID,
+		CreatedAt: FormatTimestamp(t.CreatedAt.Time()),
+	}
+}
+
+func EnrollmentTokenToCreateResponse(issued *enrollment.IssuedToken) *commands.CreateEnrollmentTokenResponse {
+	response := &commands.CreateEnrollmentTokenResponse{}
+	response.Body.EnrollmentTokenDTO = buildEnrollmentTokenDTO(issued.Token)
+	response.Body.Token = issued.Plaintext
+	return response
+}
+
+func EnrollmentTokenToRevokeResponse(t *enrollment.Token) *commands.RevokeEnrollmentTokenResponse {
+	return &commands.RevokeEnrollmentTokenResponse{Body: buildEnrollmentTokenDTO(t)}
+}
+
+func EnrollmentTokenToListResponse(tokens []*enrollment.Token) *queries.ListEnrollmentTokensResponse {
+	response := &queries.ListEnrollmentTokensResponse{}
+	response.Body.EnrollmentTokens = MapSlicePtr(tokens, buildEnrollmentTokenDTO)
+	return response
+}
+
+// Mapper instances for handler injection
+var (
+	EnrollmentTokenCreateMapper = CreateMapperFunc[*enrollment.IssuedToken, *commands.CreateEnrollmentTokenResponse](EnrollmentTokenToCreateResponse)
+	EnrollmentTokenRevokeMapper = UpdateMapperFunc[*enrollment.Token, *commands.RevokeEnrollmentTokenResponse](EnrollmentTokenToRevokeResponse)
+	EnrollmentTokenListMapper   = ListMapperFunc[enrollment.Token, *queries.ListEnrollmentTokensResponse](EnrollmentTokenToListResponse)
+)
@@ -0,0 +1,39 @@
+package mappers
+
+import (
+	"parrotflow/internal/domain/eventlog"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+func buildEventDTO(e *eventlog.Entry) queries.EventDTO {
+	return queries.EventDTO{
+		ID:            e.ID,
+		Sequence:      e.Sequence,
+		Type:          e.Type,
+		Version:       e.Version,
+		AggregateType: e.AggregateType,
+		AggregateID:   e.AggregateID,
+		OccurredAt:    FormatTimestamp(e.OccurredAt),
+		RecordedAt:    FormatTimestamp(e.RecordedAt),
+		CorrelationID: e.CorrelationID,
+		CausationID:   e.CausationID,
+		Payload:       e.Payload,
+	}
+}
+
+func EventsToListResponse(page, rpp int) func(shared.Page[*eventlog.Entry]) *queries.ListEventsResponse {
+	return func(entries shared.Page[*eventlog.Entry]) *queries.ListEventsResponse {
+		response := &queries.ListEventsResponse{}
+		response.Body.Data = MapSlicePtr(entries.Items, buildEventDTO)
+		response.Body.Total = entries.Total
+		response.Body.Page = page
+		response.Body.RPP = rpp
+		return response
+	}
+}
+
+// EventListMapperFactory creates a list mapper with pagination
+func EventListMapperFactory(page, rpp int) PageMapperFunc[eventlog.Entry, *queries.ListEventsResponse] {
+	return PageMapperFunc[eventlog.Entry, *queries.ListEventsResponse](EventsToListResponse(page, rpp))
+}
@@ -0,0 +1,36 @@
+package mappers
+
+import (
+	"net/http"
+	"time"
+
+	"parrotflow/internal/infrastructure/health"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+// HealthReportToResponse answers 503 unless the report says the instance is ready
+func HealthReportToResponse(report health.Report, uptime time.Duration) *queries.HealthResponse {
+	response := &queries.HealthResponse{
+		Status: http.StatusOK,
+		Body: queries.HealthDTO{
+			Status:        string(report.Status),
+			UptimeSeconds: int64(uptime.Seconds()),
+			CheckedAt:     FormatTimestamp(report.CheckedAt),
+			Components:    MapSlice(report.Components, mapComponentHealthToDTO),
+		},
+	}
+	if !report.Ready {
+		response.Status = http.StatusServiceUnavailable
+	}
+	return response
+}
+
+func mapComponentHealthToDTO(c health.ComponentReport) queries.ComponentHealthDTO {
+	return queries.ComponentHealthDTO{
+		Name:       c.Name,
+		Status:     string(c.Status),
+		Critical:   c.Critical,
+		Detail:     c.Detail,
+		DurationMs: float64(c.Duration.Microseconds()) / 1000,
+	}
+}
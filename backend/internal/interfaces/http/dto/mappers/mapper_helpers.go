@@ -1,6 +1,21 @@
 package mappers
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"parrotflow/internal/domain/shared"
+)
+
+// ErrInvalidETag is returned for If-Match headers that hold no version this API issued
+var ErrInvalidETag = errors.New("invalid If-Match header")
+
+// ErrWeakETag is returned for weak validators in If-Match, which uses strong comparison
+// (RFC 9110, 13.1.1) so a weak validator never matches
+var ErrWeakETag = errors.New("weak ETag in If-Match never matches")
 
 // FormatTimestamp converts a time.Time to RFC3339 string
 func FormatTimestamp(t time.Time) string {
@@ -15,6 +30,30 @@ func FormatOptionalTimestamp(t *time.Time) string {
 	return t.Format("2006-01-02T15:04:05Z07:00")
 }
 
+// FormatETag renders an aggregate version as a strong ETag, e.g. "3"
+func FormatETag(version uint64) string {
+	return `"` + strconv.FormatUint(version, 10) + `"`
+}
+
+// ParseETag reads the version back from an If-Match header
+// An empty header or "*" means no precondition and yields nil
+func ParseETag(header string) (*uint64, error) {
+	value := strings.TrimSpace(header)
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+	if strings.HasPrefix(value, "W/") {
+		return nil, fmt.Errorf("%w: %s", ErrWeakETag, header)
+	}
+	value = strings.Trim(value, `"`)
+
+	version, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidETag, header)
+	}
+	return &version, nil
+}
+
 // MapSlice applies a mapper function to a slice
 func MapSlice[TIn any, TOut any](items []TIn, mapper func(TIn) TOut) []TOut {
 	result := make([]TOut, len(items))
@@ -63,6 +102,13 @@ func (f ListMapperFunc[TDomain, TResponse]) Map(domains []*TDomain) TResponse {
 	return f(domains)
 }
 
+// PageMapperFunc wraps a function to satisfy the Mapper interface for paginated list operations
+type PageMapperFunc[TDomain any, TResponse any] func(shared.Page[*TDomain]) TResponse
+
+func (f PageMapperFunc[TDomain, TResponse]) Map(page shared.Page[*TDomain]) TResponse {
+	return f(page)
+}
+
 // DeleteMapperFunc wraps a zero-argument function to satisfy mapper pattern
 type DeleteMapperFunc[TResponse any] func() TResponse
 
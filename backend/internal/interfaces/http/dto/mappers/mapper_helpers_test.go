@@ -1,6 +1,7 @@
 package mappers
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -102,3 +103,42 @@ func TestCreateMapperFunc(t *testing.T) {
 		t.Errorf("CreateMapperFunc().Map() = %v, want %v", result.Output, "Hello, World")
 	}
 }
+
+func TestFormatETag(t *testing.T) {
+	if got := FormatETag(3); got != `"3"` {
+		t.Errorf("FormatETag(3) = %v, want %v", got, `"3"`)
+	}
+}
+
+func TestParseETag(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    *uint64
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"*", nil, false},
+		{`"7"`, ptrUint64(7), false},
+		{`W/"7"`, nil, true},
+		{"7", ptrUint64(7), false},
+		{`"abc"`, nil, true},
+	}
+
+	if _, err := ParseETag(`W/"7"`); !errors.Is(err, ErrWeakETag) {
+		t.Errorf("ParseETag(weak) error = %v, want ErrWeakETag", err)
+	}
+	for _, tt := range tests {
+		got, err := ParseETag(tt.header)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseETag(%q) error = %v, wantErr %v", tt.header, err, tt.wantErr)
+			continue
+		}
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("ParseETag(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func ptrUint64(v uint64) *uint64 {
+	return &v
+}
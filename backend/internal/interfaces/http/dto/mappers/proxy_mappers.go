@@ -19,6 +19,12 @@ func buildProxyDTO(p *proxy.Proxy) queries.ProxyDTO {
 		lastFailure = &s
 	}
 
+	var deletedAt *string
+	if p.DeletedAt != nil {
+		s := FormatTimestamp(p.DeletedAt.Time())
+		deletedAt = &s
+	}
+
 	tagStrings := make([]string, len(p.Tags))
 	for i, tagID := range p.Tags {
 		tagStrings[i] = tagID.String()
@@ -31,7 +37,7 @@ func buildProxyDTO(p *proxy.Proxy) queries.ProxyDTO {
 		Port:           p.Port,
 		Protocol:       p.Protocol.String(),
 		Status:         p.Status.String(),
-		ConnectionURL:  p.GetConnectionURL(),
+		ConnectionURL:  p.GetRedactedConnectionURL(),
 		HasCredentials: p.Credentials != nil,
 		Tags:           tagStrings,
 		LastCheckedAt:  lastChecked,
@@ -40,6 +46,7 @@ func buildProxyDTO(p *proxy.Proxy) queries.ProxyDTO {
 		SuccessCount:   p.SuccessCount,
 		AverageLatency: p.AverageLatency,
 		CreatedAt:      FormatTimestamp(p.CreatedAt.Time()),
+		DeletedAt:      deletedAt,
 	}
 }
 
@@ -60,6 +67,7 @@ func ProxyToCreateResponse(p *proxy.Proxy) *commands.CreateProxyResponse {
 func ProxyToUpdateResponse(p *proxy.Proxy) *commands.UpdateProxyResponse {
 	dto := buildProxyDTO(p)
 	response := &commands.UpdateProxyResponse{}
+	response.ETag = FormatETag(p.Version)
 	response.Body.ID = dto.ID
 	response.Body.Name = dto.Name
 	response.Body.Host = dto.Host
@@ -102,8 +110,18 @@ func ProxyToRecordHealthResponse(p *proxy.Proxy) *commands.RecordHealthResponse
 	return response
 }
 
+func ProxyToRestoreResponse(p *proxy.Proxy) *commands.RestoreProxyResponse {
+	response := &commands.RestoreProxyResponse{}
+	response.ETag = FormatETag(p.Version)
+	response.Body.ID = p.Id.String()
+	response.Body.Name = p.Name
+	response.Body.Status = p.Status.String()
+	return response
+}
+
 func ProxyToGetResponse(p *proxy.Proxy) *queries.GetProxyResponse {
 	response := &queries.GetProxyResponse{}
+	response.ETag = FormatETag(p.Version)
 	response.Body = buildProxyDTO(p)
 	return response
 }
@@ -120,6 +138,17 @@ func ProxyToActiveListResponse(proxies []*proxy.Proxy) *queries.GetActiveProxies
 	return response
 }
 
+func ProxyToCredentialsResponse(p *proxy.Proxy) *queries.GetProxyCredentialsResponse {
+	response := &queries.GetProxyCredentialsResponse{}
+	response.Body.ID = p.Id.String()
+	response.Body.ConnectionURL = p.GetConnectionURL()
+	if p.Credentials != nil {
+		response.Body.Username = p.Credentials.Username
+		response.Body.Password = p.Credentials.Password
+	}
+	return response
+}
+
 // Mapper instances for handler injection - using functional types
 var (
 	ProxyCreateMapper      = CreateMapperFunc[*proxy.Proxy, *commands.CreateProxyResponse](ProxyToCreateResponse)
@@ -128,7 +157,9 @@ var (
 	ProxyActivateMapper    = CreateMapperFunc[*proxy.Proxy, *commands.ActivateProxyResponse](ProxyToActivateResponse)
 	ProxyDeactivateMapper  = CreateMapperFunc[*proxy.Proxy, *commands.DeactivateProxyResponse](ProxyToDeactivateResponse)
 	ProxyRecordHealthMapper = CreateMapperFunc[*proxy.Proxy, *commands.RecordHealthResponse](ProxyToRecordHealthResponse)
+	ProxyRestoreMapper     = UpdateMapperFunc[*proxy.Proxy, *commands.RestoreProxyResponse](ProxyToRestoreResponse)
 	ProxyGetMapper         = GetMapperFunc[*proxy.Proxy, *queries.GetProxyResponse](ProxyToGetResponse)
+	ProxyCredentialsMapper = GetMapperFunc[*proxy.Proxy, *queries.GetProxyCredentialsResponse](ProxyToCredentialsResponse)
 	ProxyListMapper        = ListMapperFunc[proxy.Proxy, *queries.ListProxiesResponse](ProxyToListResponse)
 	ProxyActiveListMapper  = ListMapperFunc[proxy.Proxy, *queries.GetActiveProxiesResponse](ProxyToActiveListResponse)
 )
@@ -2,6 +2,7 @@ package mappers
 
 import (
 	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/shared"
 	"parrotflow/internal/interfaces/http/dto/commands"
 	"parrotflow/internal/interfaces/http/dto/queries"
 )
@@ -24,6 +25,8 @@ func buildRunDTO(r *run.Run) queries.RunListItem {
 		StartedAt:  &startedAt,
 		FinishedAt: &finishedAt,
 		CreatedAt:  FormatTimestamp(r.CreatedAt.Time()),
+
+		FailureReason: r.FailureReason,
 	}
 }
 
@@ -50,6 +53,25 @@ func RunToStartResponse(r *run.Run) *commands.StartRunResponse {
 	return response
 }
 
+func RunToCancelResponse(r *run.Run) *commands.CancelRunResponse {
+	dto := buildRunDTO(r)
+	response := &commands.CancelRunResponse{}
+	response.Body.ID = dto.ID
+	response.Body.Status = dto.Status
+	if dto.FinishedAt != nil {
+		response.Body.FinishedAt = *dto.FinishedAt
+	}
+	return response
+}
+
+func RunToProgressResponse(r *run.Run) *commands.ReportRunProgressResponse {
+	response := &commands.ReportRunProgressResponse{}
+	response.Body.ID = r.Id.String()
+	response.Body.Status = r.Status.String()
+	response.Body.UpdatedAt = FormatTimestamp(r.UpdatedAt.Time())
+	return response
+}
+
 func RunToGetResponse(r *run.Run) *queries.GetRunResponse {
 	dto := buildRunDTO(r)
 	response := &queries.GetRunResponse{}
@@ -63,19 +85,76 @@ func RunToGetResponse(r *run.Run) *queries.GetRunResponse {
 	return response
 }
 
-func RunToListResponse(runs []*run.Run) *queries.ListRunsResponse {
-	response := &queries.ListRunsResponse{}
-	response.Body.Data = MapSlicePtr(runs, buildRunDTO)
-	response.Body.Total = len(runs)
-	response.Body.Page = 1
-	response.Body.RPP = len(runs)
-	return response
+func RunToListResponse(page, rpp int) func(shared.Page[*run.Run]) *queries.ListRunsResponse {
+	return func(runs shared.Page[*run.Run]) *queries.ListRunsResponse {
+		response := &queries.ListRunsResponse{}
+		response.Body.Data = MapSlicePtr(runs.Items, buildRunDTO)
+		response.Body.Total = runs.Total
+		response.Body.Page = page
+		response.Body.RPP = rpp
+		response.Body.NextCursor = runs.NextCursor
+		return response
+	}
 }
 
 // Mapper instances for handler injection
 var (
-	RunCreateMapper = CreateMapperFunc[*run.Run, *commands.CreateRunResponse](RunToCreateResponse)
-	RunStartMapper  = CreateMapperFunc[*run.Run, *commands.StartRunResponse](RunToStartResponse)
-	RunGetMapper    = GetMapperFunc[*run.Run, *queries.GetRunResponse](RunToGetResponse)
-	RunListMapper   = ListMapperFunc[run.Run, *queries.ListRunsResponse](RunToListResponse)
+	RunCreateMapper   = CreateMapperFunc[*run.Run, *commands.CreateRunResponse](RunToCreateResponse)
+	RunStartMapper    = CreateMapperFunc[*run.Run, *commands.StartRunResponse](RunToStartResponse)
+	RunCancelMapper   = UpdateMapperFunc[*run.Run, *commands.CancelRunResponse](RunToCancelResponse)
+	RunGetMapper      = GetMapperFunc[*run.Run, *queries.GetRunResponse](RunToGetResponse)
+	RunProgressMapper = UpdateMapperFunc[*run.Run, *commands.ReportRunProgressResponse](RunToProgressResponse)
 )
+
+// RunListMapperFactory creates a list mapper with pagination
+func RunListMapperFactory(page, rpp int) PageMapperFunc[run.Run, *queries.ListRunsResponse] {
+	return PageMapperFunc[run.Run, *queries.ListRunsResponse](RunToListResponse(page, rpp))
+}
+
+// RunToStatusEvent builds the stream snapshot of a run's current state
+func RunToStatusEvent(r *run.Run) queries.RunStatusEvent {
+	return queries.RunStatusEvent{
+		RunID:      r.Id.String(),
+		ScenarioID: r.ScenarioID.String(),
+		Status:     r.Status.String(),
+		At:         FormatTimestamp(r.UpdatedAt.Time()),
+	}
+}
+
+// RunEventToStreamEvent converts a run domain event to its stream payload
+// Returns false for events that are not streamed
+func RunEventToStreamEvent(event shared.DomainEvent) (any, bool) {
+	status := func(runID, scenarioID string, s shared.Status) queries.RunStatusEvent {
+		return queries.RunStatusEvent{
+			RunID:      runID,
+			ScenarioID: scenarioID,
+			Status:     s.String(),
+			At:         FormatTimestamp(event.OccurredAt()),
+		}
+	}
+
+	switch e := event.(type) {
+	case run.RunCreated:
+		return status(e.RunID, e.ScenarioID, shared.StatusPending), true
+	case run.RunStarted:
+		return status(e.RunID, e.ScenarioID, shared.StatusRunning), true
+	case run.RunCompleted:
+		return status(e.RunID, e.ScenarioID, shared.StatusCompleted), true
+	case run.RunFailed:
+		dto := status(e.RunID, e.ScenarioID, shared.StatusFailed)
+		dto.Reason = e.Reason
+		return dto, true
+	case run.RunCancelled:
+		return status(e.RunID, e.ScenarioID, shared.StatusCancelled), true
+	case run.RunProgress:
+		return queries.RunProgressEvent{
+			RunID:   e.RunID,
+			NodeID:  e.NodeID,
+			Status:  e.NodeStatus,
+			Message: e.Message,
+			At:      FormatTimestamp(e.ReportedAt),
+		}, true
+	default:
+		return nil, false
+	}
+}
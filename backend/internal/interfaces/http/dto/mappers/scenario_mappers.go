@@ -1,7 +1,10 @@
 package mappers
 
 import (
+	"time"
+
 	"parrotflow/internal/domain/scenario"
+	domainshared "parrotflow/internal/domain/shared"
 	"parrotflow/internal/interfaces/http/dto/commands"
 	"parrotflow/internal/interfaces/http/dto/queries"
 	"parrotflow/internal/interfaces/http/dto/shared"
@@ -71,6 +74,12 @@ func mapParameterItemToDTO(pi scenario.ParameterItem) shared.ParameterItemDTO {
 }
 
 func buildScenarioDTO(s *scenario.Scenario) queries.ScenarioResponseItem {
+	var deletedAt *string
+	if s.DeletedAt != nil {
+		formatted := FormatTimestamp(s.DeletedAt.Time())
+		deletedAt = &formatted
+	}
+
 	return queries.ScenarioResponseItem{
 		ID:          s.Id.String(),
 		Name:        s.Name,
@@ -80,8 +89,21 @@ func buildScenarioDTO(s *scenario.Scenario) queries.ScenarioResponseItem {
 		Context:     mapContextToDTO(s.Context),
 		InputData:   mapInputDataToDTO(s.InputData),
 		Parameters:  mapParametersToDTO(s.Parameters),
+		Retention:   mapRetentionToDTO(s.Retention),
 		CreatedAt:   FormatTimestamp(s.CreatedAt.Time()),
 		UpdatedAt:   FormatTimestamp(s.UpdatedAt.Time()),
+		DeletedAt:   deletedAt,
+	}
+}
+
+func mapRetentionToDTO(policy *scenario.RetentionPolicy) *shared.RetentionPolicyDTO {
+	if policy == nil {
+		return nil
+	}
+	return &shared.RetentionPolicyDTO{
+		MaxAgeDays:   int(policy.MaxAge / (24 * time.Hour)),
+		MaxRuns:      policy.MaxRuns,
+		KeepStatuses: policy.KeepStatuses,
 	}
 }
 
@@ -167,6 +189,7 @@ func ScenarioToCreateResponse(s *scenario.Scenario) *commands.CreateScenarioResp
 func ScenarioToUpdateResponse(s *scenario.Scenario) *commands.UpdateScenarioResponse {
 	dto := buildScenarioDTO(s)
 	response := &commands.UpdateScenarioResponse{}
+	response.ETag = FormatETag(s.Version)
 	response.Body.ID = dto.ID
 	response.Body.Name = dto.Name
 	response.Body.Description = dto.Description
@@ -185,33 +208,56 @@ func ScenarioToDeleteResponse() *commands.DeleteScenarioResponse {
 	return response
 }
 
+func ScenarioToRestoreResponse(s *scenario.Scenario) *commands.RestoreScenarioResponse {
+	response := &commands.RestoreScenarioResponse{}
+	response.ETag = FormatETag(s.Version)
+	response.Body.ID = s.Id.String()
+	response.Body.Name = s.Name
+	return response
+}
+
+func ScenarioToRetentionResponse(s *scenario.Scenario) *commands.ScenarioRetentionResponse {
+	response := &commands.ScenarioRetentionResponse{}
+	response.ETag = FormatETag(s.Version)
+	response.Body.ID = s.Id.String()
+	response.Body.Retention = mapRetentionToDTO(s.Retention)
+	return response
+}
+
 func ScenarioToGetResponse(s *scenario.Scenario) *queries.GetScenarioResponse {
 	response := &queries.GetScenarioResponse{}
+	response.ETag = FormatETag(s.Version)
 	response.Body = buildScenarioDTO(s)
 	return response
 }
 
 // ScenarioToListResponse creates a list response with pagination
-func ScenarioToListResponse(page, rpp int) func([]*scenario.Scenario) *queries.ListScenariosResponse {
-	return func(scenarios []*scenario.Scenario) *queries.ListScenariosResponse {
+func ScenarioToListResponse(page, rpp int) func(domainshared.Page[*scenario.Scenario]) *queries.ListScenariosResponse {
+	return func(scenarios domainshared.Page[*scenario.Scenario]) *queries.ListScenariosResponse {
 		response := &queries.ListScenariosResponse{}
-		response.Body.Total = len(scenarios)
+		response.Body.Total = scenarios.Total
 		response.Body.Page = page
 		response.Body.RPP = rpp
-		response.Body.Data = MapSlicePtr(scenarios, buildScenarioDTO)
+		response.Body.Data = MapSlicePtr(scenarios.Items, buildScenarioDTO)
 		return response
 	}
 }
 
 // Mapper instances for handler injection
 var (
-	ScenarioCreateMapper = CreateMapperFunc[*scenario.Scenario, *commands.CreateScenarioResponse](ScenarioToCreateResponse)
-	ScenarioUpdateMapper = UpdateMapperFunc[*scenario.Scenario, *commands.UpdateScenarioResponse](ScenarioToUpdateResponse)
-	ScenarioDeleteMapper = DeleteMapperFunc[*commands.DeleteScenarioResponse](ScenarioToDeleteResponse)
-	ScenarioGetMapper    = GetMapperFunc[*scenario.Scenario, *queries.GetScenarioResponse](ScenarioToGetResponse)
+	ScenarioCreateMapper    = CreateMapperFunc[*scenario.Scenario, *commands.CreateScenarioResponse](ScenarioToCreateResponse)
+	ScenarioUpdateMapper    = UpdateMapperFunc[*scenario.Scenario, *commands.UpdateScenarioResponse](ScenarioToUpdateResponse)
+	ScenarioDeleteMapper    = DeleteMapperFunc[*commands.DeleteScenarioResponse](ScenarioToDeleteResponse)
+	ScenarioRestoreMapper   = UpdateMapperFunc[*scenario.Scenario, *commands.RestoreScenarioResponse](ScenarioToRestoreResponse)
+	ScenarioRetentionMapper = UpdateMapperFunc[*scenario.Scenario, *commands.ScenarioRetentionResponse](ScenarioToRetentionResponse)
+	ScenarioGetMapper       = GetMapperFunc[*scenario.Scenario, *queries.GetScenarioResponse](ScenarioToGetResponse)
 )
 
 // ScenarioListMapperFactory creates a list mapper with pagination
-func ScenarioListMapperFactory(page, rpp int) ListMapperFunc[scenario.Scenario, *queries.ListScenariosResponse] {
-	return ListMapperFunc[scenario.Scenario, *queries.ListScenariosResponse](ScenarioToListResponse(page, rpp))
+func ScenarioListMapperFactory(page, rpp int) PageMapperFunc[scenario.Scenario, *queries.ListScenariosResponse] {
+	return PageMapperFunc[scenario.Scenario, *queries.ListScenariosResponse](ScenarioToListResponse(page, rpp))
+}
+
+func MapRetentionFromDTO(dto shared.RetentionPolicyDTO) (scenario.RetentionPolicy, error) {
+	return scenario.NewRetentionPolicy(time.Duration(dto.MaxAgeDays)*24*time.Hour, dto.MaxRuns, dto.KeepStatuses)
 }
@@ -0,0 +1,66 @@
+package mappers
+
+import (
+	"parrotflow/internal/domain/secret"
+	"parrotflow/internal/interfaces/http/dto/commands"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+func buildSecretDTO(s *secret.Secret) queries.SecretDTO {
+	return queries.SecretDTO{
+		ID:          s.Id.String(),
+		Name:        s.Name,
+		Reference:   secret.Reference(s.Name),
+		Description: s.Description,
+		CreatedAt:   FormatTimestamp(s.CreatedAt.Time()),
+		UpdatedAt:   FormatTimestamp(s.UpdatedAt.Time()),
+	}
+}
+
+func SecretToCreateResponse(s *secret.Secret) *commands.CreateSecretResponse {
+	response := &commands.CreateSecretResponse{}
+	response.Body.ID = s.Id.String()
+	response.Body.Name = s.Name
+	response.Body.Reference = secret.Reference(s.Name)
+	response.Body.Description = s.Description
+	response.Body.CreatedAt = FormatTimestamp(s.CreatedAt.Time())
+	return response
+}
+
+func SecretToUpdateResponse(s *secret.Secret) *commands.UpdateSecretResponse {
+	response := &commands.UpdateSecretResponse{}
+	response.ETag = FormatETag(s.Version)
+	response.Body.ID = s.Id.String()
+	response.Body.Name = s.Name
+	response.Body.Description = s.Description
+	response.Body.UpdatedAt = FormatTimestamp(s.UpdatedAt.Time())
+	return response
+}
+
+func SecretToDeleteResponse() *commands.DeleteSecretResponse {
+	response := &commands.DeleteSecretResponse{}
+	response.Body.Success = true
+	return response
+}
+
+func SecretToGetResponse(s *secret.Secret) *queries.GetSecretResponse {
+	response := &queries.GetSecretResponse{}
+	response.ETag = FormatETag(s.Version)
+	response.Body = buildSecretDTO(s)
+	return response
+}
+
+func SecretToListResponse(secrets []*secret.Secret) *queries.ListSecretsResponse {
+	response := &queries.ListSecretsResponse{}
+	response.Body.Secrets = MapSlicePtr(secrets, buildSecretDTO)
+	return response
+}
+
+// Mapper instances for handler injection
+var (
+	SecretCreateMapper = CreateMapperFunc[*secret.Secret, *commands.CreateSecretResponse](SecretToCreateResponse)
+	SecretUpdateMapper = UpdateMapperFunc[*secret.Secret, *commands.UpdateSecretResponse](SecretToUpdateResponse)
+	SecretDeleteMapper = DeleteMapperFunc[*commands.DeleteSecretResponse](SecretToDeleteResponse)
+	SecretGetMapper    = GetMapperFunc[*secret.Secret, *queries.GetSecretResponse](SecretToGetResponse)
+	SecretListMapper   = ListMapperFunc[secret.Secret, *queries.ListSecretsResponse](SecretToListResponse)
+)
@@ -7,6 +7,12 @@ import (
 )
 
 func buildTagDTO(t *tag.Tag) queries.TagDTO {
+	var deletedAt *string
+	if t.DeletedAt != nil {
+		s := FormatTimestamp(t.DeletedAt.Time())
+		deletedAt = &s
+	}
+
 	return queries.TagDTO{
 		ID:          t.Id.String(),
 		Name:        t.Name,
@@ -16,6 +22,7 @@ func buildTagDTO(t *tag.Tag) queries.TagDTO {
 		IsSystem:    t.IsSystem,
 		CreatedAt:   FormatTimestamp(t.CreatedAt.Time()),
 		UpdatedAt:   FormatTimestamp(t.UpdatedAt.Time()),
+		DeletedAt:   deletedAt,
 	}
 }
 
@@ -38,6 +45,7 @@ func TagToCreateResponse(t *tag.Tag) *commands.CreateTagResponse {
 func TagToUpdateResponse(t *tag.Tag) *commands.UpdateTagResponse {
 	dto := buildTagDTO(t)
 	response := &commands.UpdateTagResponse{}
+	response.ETag = FormatETag(t.Version)
 	response.Body.ID = dto.ID
 	response.Body.Name = dto.Name
 	response.Body.Category = dto.Category
@@ -54,8 +62,17 @@ func TagToDeleteResponse() *commands.DeleteTagResponse {
 	return response
 }
 
+func TagToRestoreResponse(t *tag.Tag) *commands.RestoreTagResponse {
+	response := &commands.RestoreTagResponse{}
+	response.ETag = FormatETag(t.Version)
+	response.Body.ID = t.Id.String()
+	response.Body.Name = t.Name
+	return response
+}
+
 func TagToGetResponse(t *tag.Tag) *queries.GetTagResponse {
 	response := &queries.GetTagResponse{}
+	response.ETag = FormatETag(t.Version)
 	response.Body = buildTagDTO(t)
 	return response
 }
@@ -68,9 +85,10 @@ func TagToListResponse(tags []*tag.Tag) *queries.ListTagsResponse {
 
 // Mapper instances for handler injection - using functional types
 var (
-	TagCreateMapper = CreateMapperFunc[*tag.Tag, *commands.CreateTagResponse](TagToCreateResponse)
-	TagUpdateMapper = UpdateMapperFunc[*tag.Tag, *commands.UpdateTagResponse](TagToUpdateResponse)
-	TagDeleteMapper = DeleteMapperFunc[*commands.DeleteTagResponse](TagToDeleteResponse)
-	TagGetMapper    = GetMapperFunc[*tag.Tag, *queries.GetTagResponse](TagToGetResponse)
-	TagListMapper   = ListMapperFunc[tag.Tag, *queries.ListTagsResponse](TagToListResponse)
+	TagCreateMapper  = CreateMapperFunc[*tag.Tag, *commands.CreateTagResponse](TagToCreateResponse)
+	TagUpdateMapper  = UpdateMapperFunc[*tag.Tag, *commands.UpdateTagResponse](TagToUpdateResponse)
+	TagDeleteMapper  = DeleteMapperFunc[*commands.DeleteTagResponse](TagToDeleteResponse)
+	TagRestoreMapper = UpdateMapperFunc[*tag.Tag, *commands.RestoreTagResponse](TagToRestoreResponse)
+	TagGetMapper     = GetMapperFunc[*tag.Tag, *queries.GetTagResponse](TagToGetResponse)
+	TagListMapper    = ListMapperFunc[tag.Tag, *queries.ListTagsResponse](TagToListResponse)
 )
@@ -0,0 +1,122 @@
+package mappers
+
+import (
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/domain/webhook"
+	"parrotflow/internal/interfaces/http/dto/commands"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+func buildWebhookDTO(w *webhook.Webhook) queries.WebhookDTO {
+	return queries.WebhookDTO{
+		ID:                  w.Id.String(),
+		URL:                 w.URL,
+		EventTypes:          w.EventTypes,
+		Description:         w.Description,
+		Enabled:             w.Enabled,
+		ConsecutiveFailures: w.ConsecutiveFailures,
+		DisabledReason:      w.DisabledReason,
+		CreatedAt:           FormatTimestamp(w.CreatedAt.Time()),
+		UpdatedAt:           FormatTimestamp(w.UpdatedAt.Time()),
+	}
+}
+
+func buildWebhookDeliveryDTO(d *webhook.Delivery) queries.WebhookDeliveryDTO {
+	dto := queries.WebhookDeliveryDTO{
+		ID:             d.ID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Status:         d.Status.String(),
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		DurationMs:     d.Duration.Milliseconds(),
+		CreatedAt:      FormatTimestamp(d.CreatedAt),
+	}
+	if d.Status == webhook.DeliveryStatusPending {
+		next := FormatTimestamp(d.NextAttemptAt)
+		dto.NextAttemptAt = &next
+	}
+	if d.DeliveredAt != nil {
+		delivered := FormatTimestamp(*d.DeliveredAt)
+		dto.DeliveredAt = &delivered
+	}
+	return dto
+}
+
+func WebhookToCreateResponse(w *webhook.Webhook) *commands.CreateWebhookResponse {
+	response := &commands.CreateWebhookResponse{}
+	response.Body.ID = w.Id.String()
+	response.Body.URL = w.URL
+	response.Body.EventTypes = w.EventTypes
+	response.Body.Secret = w.Secret
+	response.Body.Description = w.Description
+	response.Body.Enabled = w.Enabled
+	response.Body.CreatedAt = FormatTimestamp(w.CreatedAt.Time())
+	return response
+}
+
+func WebhookToUpdateResponse(w *webhook.Webhook) *commands.UpdateWebhookResponse {
+	response := &commands.UpdateWebhookResponse{}
+	response.ETag = FormatETag(w.Version)
+	response.Body.ID = w.Id.String()
+	response.Body.URL = w.URL
+	response.Body.EventTypes = w.EventTypes
+	response.Body.Description = w.Description
+	response.Body.Enabled = w.Enabled
+	response.Body.UpdatedAt = FormatTimestamp(w.UpdatedAt.Time())
+	return response
+}
+
+func WebhookToDeleteResponse() *commands.DeleteWebhookResponse {
+	response := &commands.DeleteWebhookResponse{}
+	response.Body.Success = true
+	return response
+}
+
+func WebhookToEnableResponse(w *webhook.Webhook) *commands.EnableWebhookResponse {
+	response := &commands.EnableWebhookResponse{}
+	response.ETag = FormatETag(w.Version)
+	response.Body.ID = w.Id.String()
+	response.Body.Enabled = w.Enabled
+	return response
+}
+
+func WebhookToGetResponse(w *webhook.Webhook) *queries.GetWebhookResponse {
+	response := &queries.GetWebhookResponse{}
+	response.ETag = FormatETag(w.Version)
+	response.Body = buildWebhookDTO(w)
+	return response
+}
+
+func WebhookToListResponse(webhooks []*webhook.Webhook) *queries.ListWebhooksResponse {
+	response := &queries.ListWebhooksResponse{}
+	response.Body.Webhooks = MapSlicePtr(webhooks, buildWebhookDTO)
+	return response
+}
+
+func WebhookDeliveriesToListResponse(page, rpp int) func(shared.Page[*webhook.Delivery]) *queries.ListWebhookDeliveriesResponse {
+	return func(deliveries shared.Page[*webhook.Delivery]) *queries.ListWebhookDeliveriesResponse {
+		response := &queries.ListWebhookDeliveriesResponse{}
+		response.Body.Data = MapSlicePtr(deliveries.Items, buildWebhookDeliveryDTO)
+		response.Body.Total = deliveries.Total
+		response.Body.Page = page
+		response.Body.RPP = rpp
+		return response
+	}
+}
+
+// Mapper instances for handler injection
+var (
+	WebhookCreateMapper = CreateMapperFunc[*webhook.Webhook, *commands.CreateWebhookResponse](WebhookToCreateResponse)
+	WebhookUpdateMapper = UpdateMapperFunc[*webhook.Webhook, *commands.UpdateWebhookResponse](WebhookToUpdateResponse)
+	WebhookDeleteMapper = DeleteMapperFunc[*commands.DeleteWebhookResponse](WebhookToDeleteResponse)
+	WebhookEnableMapper = UpdateMapperFunc[*webhook.Webhook, *commands.EnableWebhookResponse](WebhookToEnableResponse)
+	WebhookGetMapper    = GetMapperFunc[*webhook.Webhook, *queries.GetWebhookResponse](WebhookToGetResponse)
+	WebhookListMapper   = ListMapperFunc[webhook.Webhook, *queries.ListWebhooksResponse](WebhookToListResponse)
+)
+
+// WebhookDeliveryListMapperFactory creates a list mapper with pagination
+func WebhookDeliveryListMapperFactory(page, rpp int) PageMapperFunc[webhook.Delivery, *queries.ListWebhookDeliveriesResponse] {
+	return PageMapperFunc[webhook.Delivery, *queries.ListWebhookDeliveriesResponse](WebhookDeliveriesToListResponse(page, rpp))
+}
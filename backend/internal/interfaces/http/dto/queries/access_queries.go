@@ -0,0 +1,37 @@
+package queries
+
+import "parrotflow/internal/interfaces/http/dto/commands"
+
+type ListRoleAssignmentsRequest struct{}
+
+type ListRoleAssignmentsResponse struct {
+	Body struct {
+		Assignments []commands.RoleAssignmentDTO `json:"assignments"`
+	}
+}
+
+type GetAccessPolicyRequest struct{}
+
+type GetAccessPolicyResponse struct {
+	Body struct {
+		Roles []RolePolicyDTO `json:"roles" doc:"Every role with the permissions it grants, least privileged first"`
+	}
+}
+
+type RolePolicyDTO struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+type GetCurrentPrincipalRequest struct{}
+
+type GetCurrentPrincipalResponse struct {
+	Body struct {
+		Subject     string   `json:"subject" doc:"Subject to assign roles to"`
+		Name        string   `json:"name,omitempty"`
+		Method      string   `json:"method" enum:"api_key,jwt,none" doc:"How the caller authenticated"`
+		Scopes      []string `json:"scopes" doc:"What the credential may be used for"`
+		Roles       []string `json:"roles" doc:"Assigned roles and those of the token's roles claim"`
+		Permissions []string `json:"permissions" doc:"Permissions the roles grant"`
+	}
+}
@@ -9,6 +9,7 @@ type GetAgentRequest struct {
 
 // GetAgentResponse represents the response containing a single agent
 type GetAgentResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
 	Body AgentDTO `json:"agent" doc:"Agent details"`
 }
 
@@ -69,4 +70,6 @@ type AgentDTO struct {
 	RegisteredAt    string                       `json:"registered_at" doc:"Registration timestamp"`
 	UpdatedAt       string                       `json:"updated_at" doc:"Update timestamp"`
 	ConnectionInfo  commands.ConnectionInfoDTO   `json:"connection_info" doc:"Connection information"`
+	CredentialIssuedAt *string                   `json:"credential_issued_at,omitempty" doc:"When the agent enrolled and got its credential"`
+	RevokedAt          *string                   `json:"revoked_at,omitempty" doc:"When the agent credential was revoked"`
 }
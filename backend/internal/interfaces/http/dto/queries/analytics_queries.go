@@ -0,0 +1,79 @@
+package queries
+
+import "time"
+
+type GetScenarioAnalyticsRequest struct {
+	ID string `path:"id"`
+	AnalyticsCriteria
+}
+
+type GetFleetAnalyticsRequest struct {
+	AnalyticsCriteria
+}
+
+type AnalyticsCriteria struct {
+	Since    time.Time `query:"since" doc:"Only runs created at or after this time (RFC 3339), 30 days before until by default"`
+	Until    time.Time `query:"until" doc:"Only runs created before this time (RFC 3339), now by default"`
+	Interval string    `query:"interval" enum:"hour,day,week" doc:"Width of the time buckets, day by default; buckets start at UTC boundaries, weeks on Monday"`
+	Source   string    `query:"source" enum:"runs,rollup" doc:"Compute the buckets from the runs (default) or from the daily rollup, which reaches back past run retention but has no percentiles"`
+	Limit    int       `query:"limit" default:"10" minimum:"1" maximum:"100" doc:"How many failure reasons, failing nodes and scenarios to list"`
+}
+
+type GetAnalyticsResponse struct {
+	Body AnalyticsReportDTO
+}
+
+type AnalyticsReportDTO struct {
+	ScenarioID     string                `json:"scenario_id,omitempty" doc:"Empty for the fleet-wide summary"`
+	Since          string                `json:"since"`
+	Until          string                `json:"until"`
+	Interval       string                `json:"interval"`
+	Source         string                `json:"source"`
+	Runs           RunOutcomesDTO        `json:"runs"`
+	Durations      RunDurationsDTO       `json:"durations"`
+	Buckets        []AnalyticsBucketDTO  `json:"buckets"`
+	FailureReasons []FailureReasonDTO    `json:"failure_reasons" doc:"Most frequent first"`
+	FailingNodes   []FailingNodeDTO      `json:"failing_nodes" doc:"Nodes with the most failures first"`
+	Scenarios      []ScenarioOutcomesDTO `json:"scenarios,omitempty" doc:"Fleet-wide summary only, highest failure rate first"`
+}
+
+type RunOutcomesDTO struct {
+	Total       int64   `json:"total"`
+	Completed   int64   `json:"completed"`
+	Failed      int64   `json:"failed"`
+	Cancelled   int64   `json:"cancelled"`
+	SuccessRate float64 `json:"success_rate" doc:"Completed runs among completed and failed ones, from 0 to 1"`
+	FailureRate float64 `json:"failure_rate" doc:"Failed runs among completed and failed ones, from 0 to 1"`
+}
+
+type RunDurationsDTO struct {
+	Measured int64 `json:"measured" doc:"Finished runs with a start and finish time"`
+	P50Ms    int64 `json:"p50_ms"`
+	P95Ms    int64 `json:"p95_ms"`
+}
+
+type AnalyticsBucketDTO struct {
+	Start     string          `json:"start"`
+	Runs      RunOutcomesDTO  `json:"runs"`
+	Durations RunDurationsDTO `json:"durations"`
+}
+
+type FailureReasonDTO struct {
+	Reason     string `json:"reason"`
+	Count      int64  `json:"count"`
+	LastSeenAt string `json:"last_seen_at"`
+}
+
+type FailingNodeDTO struct {
+	ScenarioID  string  `json:"scenario_id"`
+	NodeID      string  `json:"node_id"`
+	Runs        int64   `json:"runs" doc:"Runs that reached the node"`
+	Failures    int64   `json:"failures"`
+	FailureRate float64 `json:"failure_rate"`
+	LastMessage string  `json:"last_message,omitempty" doc:"Message of the latest failure"`
+}
+
+type ScenarioOutcomesDTO struct {
+	ScenarioID string         `json:"scenario_id"`
+	Runs       RunOutcomesDTO `json:"runs"`
+}
@@ -0,0 +1,11 @@
+package queries
+
+import "parrotflow/internal/interfaces/http/dto/commands"
+
+type ListAPIKeysRequest struct{}
+
+type ListAPIKeysResponse struct {
+	Body struct {
+		APIKeys []commands.APIKeyDTO `json:"api_keys"`
+	}
+}
@@ -0,0 +1,57 @@
+package queries
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditFilters are the filters shared by listing and exporting the audit log
+type AuditFilters struct {
+	Actor        string    `query:"actor" doc:"Only calls by this subject, e.g. apikey:3, or anonymous"`
+	OperationID  []string  `query:"operation_id" doc:"Only calls to these operations, comma-separated, e.g. create-secret,delete-secret"`
+	ResourceType string    `query:"resource_type" doc:"Only calls on this kind of resource, as named in the path, e.g. scenarios"`
+	ResourceID   string    `query:"resource_id" doc:"Only calls on this resource"`
+	Outcome      string    `query:"outcome" enum:"success,denied,failure" doc:"Only calls with this outcome"`
+	Since        time.Time `query:"since" doc:"Only calls made at or after this time (RFC 3339)"`
+	Until        time.Time `query:"until" doc:"Only calls made before this time (RFC 3339)"`
+	Order        string    `query:"order" enum:"asc,desc" doc:"Order of recording, desc by default"`
+}
+
+type ListAuditEntriesRequest struct {
+	AuditFilters
+	Page int `query:"page" default:"1" minimum:"1"`
+	RPP  int `query:"rpp" default:"50" minimum:"1" maximum:"100"`
+}
+
+type ExportAuditEntriesRequest struct {
+	AuditFilters
+	Format string `query:"format" enum:"csv,jsonl" default:"jsonl" doc:"jsonl for one JSON entry per line, csv for spreadsheets"`
+}
+
+type AuditEntryDTO struct {
+	Sequence      uint64          `json:"sequence" doc:"Position in the audit log"`
+	OccurredAt    string          `json:"occurred_at"`
+	Actor         string          `json:"actor" doc:"Subject of the caller, e.g. apikey:3, or anonymous"`
+	ActorName     string          `json:"actor_name,omitempty"`
+	AuthMethod    string          `json:"auth_method,omitempty"`
+	OperationID   string          `json:"operation_id"`
+	Method        string          `json:"method"`
+	Path          string          `json:"path"`
+	ResourceType  string          `json:"resource_type"`
+	ResourceID    string          `json:"resource_id,omitempty"`
+	Request       json.RawMessage `json:"request,omitempty" doc:"Request body with passwords, tokens, secrets and the like redacted"`
+	Status        int             `json:"status"`
+	Outcome       string          `json:"outcome" enum:"success,denied,failure"`
+	Error         string          `json:"error,omitempty"`
+	CorrelationID string          `json:"correlation_id,omitempty" doc:"ID shared by everything the call caused, see the event log"`
+	DurationMs    int64           `json:"duration_ms"`
+}
+
+type ListAuditEntriesResponse struct {
+	Body struct {
+		Data  []AuditEntryDTO `json:"data"`
+		Total int64           `json:"total" doc:"Entries matching the filters across all pages"`
+		Page  int             `json:"page"`
+		RPP   int             `json:"rpp"`
+	}
+}
@@ -0,0 +1,41 @@
+package queries
+
+type GetDeadLetterRequest struct {
+	ID string `path:"id"`
+}
+
+type GetDeadLetterResponse struct {
+	Body DeadLetterDTO
+}
+
+type ListDeadLettersRequest struct {
+	Queue  string `query:"queue" doc:"Only messages rejected from this queue, e.g. agent.requests"`
+	Status string `query:"status" enum:"pending,replayed,discarded" doc:"Filter by status (optional)"`
+	Page   int    `query:"page" default:"1" minimum:"1"`
+	RPP    int    `query:"rpp" default:"10" minimum:"1" maximum:"100"`
+}
+
+type ListDeadLettersResponse struct {
+	Body struct {
+		Data  []DeadLetterDTO `json:"data"`
+		Total int64           `json:"total" doc:"Dead letters matching the filters across all pages"`
+		Page  int             `json:"page"`
+		RPP   int             `json:"rpp"`
+	}
+}
+
+type DeadLetterDTO struct {
+	ID              string            `json:"id"`
+	MessageID       string            `json:"message_id,omitempty"`
+	Queue           string            `json:"queue"`
+	Reason          string            `json:"reason" doc:"Why the broker dead-lettered the message: rejected, expired, maxlen or delivery_limit"`
+	ContentType     string            `json:"content_type,omitempty"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	Payload         string            `json:"payload"`
+	PayloadEncoding string            `json:"payload_encoding" enum:"utf-8,base64" doc:"base64 when the payload is not valid UTF-8"`
+	DeathCount      int               `json:"death_count"`
+	Status          string            `json:"status"`
+	DeadLetteredAt  string            `json:"dead_lettered_at"`
+	ResolvedAt      *string           `json:"resolved_at,omitempty"`
+	CreatedAt       string            `json:"created_at"`
+}
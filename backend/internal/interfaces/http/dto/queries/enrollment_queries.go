@@ -0,0 +1,11 @@
+package queries
+
+import "parrotflow/internal/interfaces/http/dto/commands"
+
+type ListEnrollmentTokensRequest struct{}
+
+type ListEnrollmentTokensResponse struct {
+	Body struct {
+		EnrollmentTokens []commands.EnrollmentTokenDTO `json:"enrollment_tokens"`
+	}
+}
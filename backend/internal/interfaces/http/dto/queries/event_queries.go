@@ -0,0 +1,51 @@
+package queries
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ListEventsRequest struct {
+	Type          []string  `query:"type" doc:"Only events of these types, e.g. agent.capabilities.updated (repeatable)"`
+	AggregateType string    `query:"aggregate_type" doc:"Only events raised by this kind of aggregate, e.g. agent"`
+	AggregateID   string    `query:"aggregate_id" doc:"Only events raised by this aggregate"`
+	CorrelationID string    `query:"correlation_id" doc:"Only events caused, directly or not, by the request with this correlation ID"`
+	Since         time.Time `query:"since" doc:"Only events that occurred at or after this time (RFC 3339)"`
+	Until         time.Time `query:"until" doc:"Only events that occurred before this time (RFC 3339)"`
+	Page          int       `query:"page" default:"1" minimum:"1"`
+	RPP           int       `query:"rpp" default:"10" minimum:"1" maximum:"100"`
+	Order         string    `query:"order" enum:"asc,desc" doc:"Order of occurrence, desc by default"`
+}
+
+type GetAggregateHistoryRequest struct {
+	ID    string    `path:"id"`
+	Type  []string  `query:"type" doc:"Only events of these types (repeatable)"`
+	Since time.Time `query:"since" doc:"Only events that occurred at or after this time (RFC 3339)"`
+	Until time.Time `query:"until" doc:"Only events that occurred before this time (RFC 3339)"`
+	Page  int       `query:"page" default:"1" minimum:"1"`
+	RPP   int       `query:"rpp" default:"50" minimum:"1" maximum:"100"`
+	Order string    `query:"order" enum:"asc,desc" doc:"Order of occurrence, asc (chronological) by default"`
+}
+
+type EventDTO struct {
+	ID            string          `json:"id"`
+	Sequence      uint64          `json:"sequence" doc:"Position in the event log"`
+	Type          string          `json:"type"`
+	Version       int             `json:"version" doc:"Payload schema version"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	OccurredAt    string          `json:"occurred_at"`
+	RecordedAt    string          `json:"recorded_at"`
+	CorrelationID string          `json:"correlation_id,omitempty" doc:"ID shared by everything the originating request caused"`
+	CausationID   string          `json:"causation_id,omitempty" doc:"ID of the request, event or message that caused this event"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+type ListEventsResponse struct {
+	Body struct {
+		Data  []EventDTO `json:"data"`
+		Total int64      `json:"total" doc:"Events matching the filters across all pages"`
+		Page  int        `json:"page"`
+		RPP   int        `json:"rpp"`
+	}
+}
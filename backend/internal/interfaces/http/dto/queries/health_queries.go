@@ -0,0 +1,24 @@
+package queries
+
+type HealthRequest struct{}
+
+// HealthResponse is answered with 503 when the instance should not get traffic
+type HealthResponse struct {
+	Status int
+	Body   HealthDTO
+}
+
+type HealthDTO struct {
+	Status        string               `json:"status" enum:"up,degraded,down"`
+	UptimeSeconds int64                `json:"uptime_seconds"`
+	CheckedAt     string               `json:"checked_at"`
+	Components    []ComponentHealthDTO `json:"components,omitempty" doc:"Result of every readiness probe, omitted by the liveness check"`
+}
+
+type ComponentHealthDTO struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status" enum:"up,degraded,down"`
+	Critical   bool    `json:"critical" doc:"Whether the instance is not ready while the component is down"`
+	Detail     string  `json:"detail,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+}
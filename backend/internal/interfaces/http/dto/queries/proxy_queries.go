@@ -7,13 +7,30 @@ type GetProxyRequest struct {
 
 // GetProxyResponse is the output for a single proxy
 type GetProxyResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
 	Body ProxyDTO `json:"proxy"`
 }
 
+// GetProxyCredentialsRequest is the input for getting the credentials of a proxy
+type GetProxyCredentialsRequest struct {
+	ID string `path:"id" doc:"Proxy ID"`
+}
+
+// GetProxyCredentialsResponse is the output for the credentials of a proxy
+type GetProxyCredentialsResponse struct {
+	Body struct {
+		ID            string `json:"id" doc:"Unique proxy identifier"`
+		Username      string `json:"username,omitempty" doc:"Username for authentication, omitted without credentials"`
+		Password      string `json:"password,omitempty" doc:"Password for authentication, omitted without credentials"`
+		ConnectionURL string `json:"connection_url" doc:"Full proxy connection URL including the password"`
+	}
+}
+
 // ListProxiesRequest is the input for listing proxies
 type ListProxiesRequest struct {
-	Status string   `query:"status" required:"false" enum:"active,inactive,checking,failed" doc:"Filter by proxy status"`
-	Tags   []string `query:"tags" required:"false" doc:"Filter by tag IDs (proxy must have all specified tags)"`
+	Status  string   `query:"status" required:"false" enum:"active,inactive,checking,failed" doc:"Filter by proxy status"`
+	Tags    []string `query:"tags" required:"false" doc:"Filter by tag IDs (proxy must have all specified tags)"`
+	Trashed bool     `query:"trashed" required:"false" doc:"List proxies in the trash instead of live ones"`
 }
 
 // ListProxiesResponse is the output for listing proxies
@@ -45,7 +62,7 @@ type ProxyDTO struct {
 	Port            int      `json:"port" doc:"Proxy port"`
 	Protocol        string   `json:"protocol" doc:"Proxy protocol (http, https, socks5)"`
 	Status          string   `json:"status" doc:"Current proxy status"`
-	ConnectionURL   string   `json:"connection_url" doc:"Full proxy connection URL"`
+	ConnectionURL   string   `json:"connection_url" doc:"Proxy connection URL, the password masked; see get-proxy-credentials"`
 	HasCredentials  bool     `json:"has_credentials" doc:"Whether proxy has authentication credentials"`
 	Tags            []string `json:"tags" doc:"Associated tag IDs"`
 	LastCheckedAt   *string  `json:"last_checked_at,omitempty" doc:"Last health check timestamp"`
@@ -55,4 +72,5 @@ type ProxyDTO struct {
 	AverageLatency  int      `json:"average_latency_ms" doc:"Average latency in milliseconds"`
 	CreatedAt       string   `json:"created_at" doc:"Creation timestamp"`
 	UpdatedAt       string   `json:"updated_at" doc:"Last update timestamp"`
+	DeletedAt       *string  `json:"deleted_at,omitempty" doc:"When the proxy was moved to the trash"`
 }
@@ -20,8 +20,11 @@ type GetRunResponse struct {
 type ListRunsRequest struct {
 	ScenarioID string `query:"scenario_id"`
 	Status     string `query:"status"`
-	Page       int    `query:"page"`
-	RPP        int    `query:"rpp"`
+	Page       int    `query:"page" default:"1" minimum:"1"`
+	RPP        int    `query:"rpp" default:"10" minimum:"1" maximum:"100"`
+	Sort       string `query:"sort" enum:"created_at,started_at,finished_at,status" doc:"Field to order by, created_at by default"`
+	Order      string `query:"order" enum:"asc,desc" doc:"Sort direction, desc by default"`
+	Cursor     string `query:"cursor" doc:"Opaque next_cursor from a previous page; replaces page and keeps created_at desc ordering"`
 }
 
 type RunListItem struct {
@@ -32,13 +35,49 @@ type RunListItem struct {
 	StartedAt  *string `json:"started_at,omitempty"`
 	FinishedAt *string `json:"finished_at,omitempty"`
 	CreatedAt  string  `json:"created_at"`
+
+	FailureReason string `json:"failure_reason,omitempty"`
 }
 
 type ListRunsResponse struct {
 	Body struct {
-		Data  []RunListItem `json:"data"`
-		Total int           `json:"total"`
-		Page  int           `json:"page"`
-		RPP   int           `json:"rpp"`
+		Data       []RunListItem `json:"data"`
+		Total      int64         `json:"total" doc:"Runs matching the filters across all pages"`
+		Page       int           `json:"page"`
+		RPP        int           `json:"rpp"`
+		NextCursor string        `json:"next_cursor,omitempty" doc:"Pass as cursor to fetch the next page, absent on the last one"`
 	}
 }
+
+type StreamRunEventsRequest struct {
+	ID          string `path:"id"`
+	LastEventID string `header:"Last-Event-ID" doc:"ID of the last event received, to resume after a disconnect"`
+}
+
+// RunStatusEvent is sent as the initial snapshot and on every run state change
+type RunStatusEvent struct {
+	RunID      string `json:"run_id"`
+	ScenarioID string `json:"scenario_id"`
+	Status     string `json:"status"`
+	Reason     string `json:"reason,omitempty"`
+	At         string `json:"at"`
+}
+
+// RunProgressEvent is sent when a scenario node reports progress
+type RunProgressEvent struct {
+	RunID   string `json:"run_id"`
+	NodeID  string `json:"node_id"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	At      string `json:"at"`
+}
+
+// StreamHeartbeatEvent keeps idle connections open through proxies
+type StreamHeartbeatEvent struct {
+	At string `json:"at"`
+}
+
+// StreamErrorEvent ends a stream that could not be served
+type StreamErrorEvent struct {
+	Message string `json:"message"`
+}
@@ -7,33 +7,40 @@ type GetScenarioRequest struct {
 }
 
 type ScenarioResponseItem struct {
-	ID          string                `json:"id"`
-	Name        string                `json:"name"`
-	Description string                `json:"description"`
-	Tag         string                `json:"tag"`
-	Icon        string                `json:"icon"`
-	Context     shared.ContextDTO     `json:"context"`
-	InputData   shared.InputDataDTO   `json:"input_data"`
-	Parameters  shared.ParametersDTO  `json:"parameters"`
-	CreatedAt   string                `json:"created_at"`
-	UpdatedAt   string                `json:"updated_at"`
+	ID          string                     `json:"id"`
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	Tag         string                     `json:"tag"`
+	Icon        string                     `json:"icon"`
+	Context     shared.ContextDTO          `json:"context"`
+	InputData   shared.InputDataDTO        `json:"input_data"`
+	Parameters  shared.ParametersDTO       `json:"parameters"`
+	Retention   *shared.RetentionPolicyDTO `json:"retention,omitempty" doc:"Run retention override, absent when the global policy applies"`
+	CreatedAt   string                     `json:"created_at"`
+	UpdatedAt   string                     `json:"updated_at"`
+	DeletedAt   *string                    `json:"deleted_at,omitempty"`
 }
 
 type GetScenarioResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
 	Body ScenarioResponseItem
 }
 
 type ListScenariosRequest struct {
-	Name string `query:"name"`
-	Tag  string `query:"tag"`
-	Page int    `query:"page"`
-	RPP  int    `query:"rpp"`
+	Name  string `query:"name"`
+	Tag   string `query:"tag"`
+	Page  int    `query:"page" default:"1" minimum:"1"`
+	RPP   int    `query:"rpp" default:"10" minimum:"1" maximum:"100"`
+	Sort  string `query:"sort" enum:"created_at,updated_at,name" doc:"Field to order by, created_at by default"`
+	Order string `query:"order" enum:"asc,desc" doc:"Sort direction, desc by default"`
+
+	Trashed bool `query:"trashed" doc:"List scenarios in the trash instead of live ones"`
 }
 
 type ListScenariosResponse struct {
 	Body struct {
 		Data  []ScenarioResponseItem `json:"data"`
-		Total int                    `json:"total"`
+		Total int64                  `json:"total" doc:"Scenarios matching the filters across all pages"`
 		Page  int                    `json:"page"`
 		RPP   int                    `json:"rpp"`
 	}
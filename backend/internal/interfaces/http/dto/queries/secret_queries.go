@@ -0,0 +1,28 @@
+package queries
+
+type GetSecretRequest struct {
+	ID string `path:"id"`
+}
+
+type GetSecretResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
+	Body SecretDTO
+}
+
+type ListSecretsRequest struct{}
+
+type ListSecretsResponse struct {
+	Body struct {
+		Secrets []SecretDTO `json:"secrets"`
+	}
+}
+
+// SecretDTO never includes the value
+type SecretDTO struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Reference   string `json:"reference" doc:"How scenario parameters reference the secret"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
@@ -5,20 +5,23 @@ type GetTagRequest struct {
 }
 
 type GetTagResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
 	Body struct {
-		ID          string `json:"id"`
-		Name        string `json:"name"`
-		Category    string `json:"category"`
-		Description string `json:"description"`
-		Color       string `json:"color"`
-		IsSystem    bool   `json:"is_system"`
-		CreatedAt   string `json:"created_at"`
-		UpdatedAt   string `json:"updated_at"`
+		ID          string  `json:"id"`
+		Name        string  `json:"name"`
+		Category    string  `json:"category"`
+		Description string  `json:"description"`
+		Color       string  `json:"color"`
+		IsSystem    bool    `json:"is_system"`
+		CreatedAt   string  `json:"created_at"`
+		UpdatedAt   string  `json:"updated_at"`
+		DeletedAt   *string `json:"deleted_at,omitempty"`
 	}
 }
 
 type ListTagsRequest struct {
 	Category string `query:"category" enum:"system,custom,capacity,region" doc:"Filter by category (optional)"`
+	Trashed  bool   `query:"trashed" doc:"List tags in the trash instead of live ones"`
 }
 
 type ListTagsResponse struct {
@@ -28,12 +31,13 @@ type ListTagsResponse struct {
 }
 
 type TagDTO struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Category    string `json:"category"`
-	Description string `json:"description"`
-	Color       string `json:"color"`
-	IsSystem    bool   `json:"is_system"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Category    string  `json:"category"`
+	Description string  `json:"description"`
+	Color       string  `json:"color"`
+	IsSystem    bool    `json:"is_system"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+	DeletedAt   *string `json:"deleted_at,omitempty"`
 }
@@ -0,0 +1,61 @@
+package queries
+
+type GetWebhookRequest struct {
+	ID string `path:"id"`
+}
+
+type GetWebhookResponse struct {
+	ETag string `header:"ETag" doc:"Current version of the resource, usable in If-Match"`
+	Body WebhookDTO
+}
+
+type ListWebhooksRequest struct{}
+
+type ListWebhooksResponse struct {
+	Body struct {
+		Webhooks []WebhookDTO `json:"webhooks"`
+	}
+}
+
+// WebhookDTO never includes the signing secret
+type WebhookDTO struct {
+	ID                  string   `json:"id"`
+	URL                 string   `json:"url"`
+	EventTypes          []string `json:"event_types"`
+	Description         string   `json:"description"`
+	Enabled             bool     `json:"enabled"`
+	ConsecutiveFailures int      `json:"consecutive_failures"`
+	DisabledReason      string   `json:"disabled_reason,omitempty"`
+	CreatedAt           string   `json:"created_at"`
+	UpdatedAt           string   `json:"updated_at"`
+}
+
+type ListWebhookDeliveriesRequest struct {
+	ID     string `path:"id"`
+	Status string `query:"status" enum:"pending,succeeded,failed" doc:"Filter by delivery status (optional)"`
+	Page   int    `query:"page" default:"1" minimum:"1"`
+	RPP    int    `query:"rpp" default:"10" minimum:"1" maximum:"100"`
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Body struct {
+		Data  []WebhookDeliveryDTO `json:"data"`
+		Total int64                `json:"total" doc:"Deliveries matching the filters across all pages"`
+		Page  int                  `json:"page"`
+		RPP   int                  `json:"rpp"`
+	}
+}
+
+type WebhookDeliveryDTO struct {
+	ID             string  `json:"id"`
+	EventID        string  `json:"event_id"`
+	EventType      string  `json:"event_type"`
+	Status         string  `json:"status"`
+	Attempts       int     `json:"attempts"`
+	ResponseStatus int     `json:"response_status,omitempty"`
+	LastError      string  `json:"last_error,omitempty"`
+	DurationMs     int64   `json:"duration_ms"`
+	NextAttemptAt  *string `json:"next_attempt_at,omitempty" doc:"Only set while the delivery is pending"`
+	DeliveredAt    *string `json:"delivered_at,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+}
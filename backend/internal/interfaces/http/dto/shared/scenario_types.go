@@ -52,3 +52,9 @@ type ParameterItemDTO struct {
 	ParamType string       `json:"param_type"`
 	Values    []string     `json:"values"`
 }
+
+type RetentionPolicyDTO struct {
+	MaxAgeDays   int      `json:"max_age_days,omitempty" minimum:"0" doc:"Compact finished runs older than this many days (0 means no age limit)"`
+	MaxRuns      int      `json:"max_runs,omitempty" minimum:"0" doc:"Keep at most this many runs (0 means no limit)"`
+	KeepStatuses []string `json:"keep_statuses,omitempty" doc:"Run statuses that are never compacted, e.g. FAILED"`
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+
+	command "parrotflow/internal/application/command/access"
+	query "parrotflow/internal/application/query/access"
+	"parrotflow/internal/domain/access"
+	"parrotflow/internal/infrastructure/auth"
+	"parrotflow/internal/interfaces/http/dto/commands"
+	"parrotflow/internal/interfaces/http/dto/mappers"
+	"parrotflow/internal/interfaces/http/dto/queries"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type AccessHandler struct {
+	// Command handlers
+	assignCommandHandler   *command.AssignRolesCommandHandler
+	unassignCommandHandler *command.UnassignRolesCommandHandler
+
+	// Query handlers
+	listQueryHandler *query.ListRoleAssignmentsQueryHandler
+
+	// Mappers - using functional types
+	assignMapper   mappers.UpdateMapperFunc[*access.RoleAssignment, *commands.AssignRolesResponse]
+	unassignMapper mappers.DeleteMapperFunc[*commands.UnassignRolesResponse]
+	listMapper     mappers.ListMapperFunc[access.RoleAssignment, *queries.ListRoleAssignmentsResponse]
+}
+
+func NewAccessHandler(
+	assignCommandHandler *command.AssignRolesCommandHandler,
+	unassignCommandHandler *command.UnassignRolesCommandHandler,
+	listQueryHandler *query.ListRoleAssignmentsQueryHandler,
+) *AccessHandler {
+	return &AccessHandler{
+		assignCommandHandler:   assignCommandHandler,
+		unassignCommandHandler: unassignCommandHandler,
+		listQueryHandler:       listQueryHandler,
+		assignMapper:           mappers.RoleAssignmentAssignMapper,
+		unassignMapper:         mappers.RoleAssignmentUnassignMapper,
+		listMapper:             mappers.RoleAssignmentListMapper,
+	}
+}
+
+func (h *AccessHandler) AssignRoles(ctx context.Context, req *commands.AssignRolesRequest) (*commands.AssignRolesResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.AssignRolesRequest) (command.AssignRolesCommand, error) {
+			return command.AssignRolesCommand{Subject: r.Subject, Roles: r.Body.Roles}, nil
+		},
+		CommandHandlerFunc[command.AssignRolesCommand, *access.RoleAssignment](h.assignCommandHandler.Handle),
+		h.assignMapper,
+	)
+}
+
+func (h *AccessHandler) UnassignRoles(ctx context.Context, req *commands.UnassignRolesRequest) (*commands.UnassignRolesResponse, error) {
+	return HandleSimpleCommand(
+		ctx,
+		req,
+		func(r *commands.UnassignRolesRequest) (command.UnassignRolesCommand, error) {
+			return command.UnassignRolesCommand{Subject: r.Subject}, nil
+		},
+		SimpleCommandHandlerFunc[command.UnassignRolesCommand](h.unassignCommandHandler.Handle),
+		h.unassignMapper.Map,
+	)
+}
+
+func (h *AccessHandler) ListRoleAssignments(ctx context.Context, req *queries.ListRoleAssignmentsRequest) (*queries.ListRoleAssignmentsResponse, error) {
+	return HandleQuery(
+		ctx,
+		req,
+		func(r *queries.ListRoleAssignmentsRequest) (query.ListRoleAssignmentsQuery, error) {
+			return query.ListRoleAssignmentsQuery{}, nil
+		},
+		QueryHandlerFunc[query.ListRoleAssignmentsQuery, []*access.RoleAssignment](h.listQueryHandler.Handle),
+		h.listMapper,
+	)
+}
+
+// GetPolicy describes the roles; the permission of each operation is in its description
+func (h *AccessHandler) GetPolicy(ctx context.Context, req *queries.GetAccessPolicyRequest) (*queries.GetAccessPolicyResponse, error) {
+	return mappers.AccessPolicyToResponse(), nil
+}
+
+// GetCurrentPrincipal tells callers who they are authenticated as and what they may do
+func (h *AccessHandler) GetCurrentPrincipal(ctx context.Context, req *queries.GetCurrentPrincipalRequest) (*queries.GetCurrentPrincipalResponse, error) {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return nil, huma.Error401Unauthorized("authentication required")
+	}
+	return mappers.PrincipalToResponse(principal), nil
+}
@@ -21,6 +21,7 @@ type AgentHandler struct {
 	releaseRunCommandHandler     *agentcommand.ReleaseRunCommandHandler
 	updateCommandHandler         *agentcommand.UpdateAgentCommandHandler
 	deregisterCommandHandler     *agentcommand.DeregisterAgentCommandHandler
+	revokeCommandHandler         *agentcommand.RevokeAgentCommandHandler
 
 	// Queries
 	getQueryHandler          *agentquery.GetAgentQueryHandler
@@ -29,12 +30,13 @@ type AgentHandler struct {
 	getStaleQueryHandler     *agentquery.GetStaleAgentsQueryHandler
 
 	// Mappers - using functional types
-	registerMapper       mappers.CreateMapperFunc[*agent.Agent, *commands.RegisterAgentResponse]
+	registerMapper       mappers.CreateMapperFunc[*agent.EnrolledAgent, *commands.RegisterAgentResponse]
 	heartbeatMapper      mappers.CreateMapperFunc[*agent.Agent, *commands.UpdateHeartbeatResponse]
 	assignRunMapper      mappers.CreateMapperFunc[*agent.Agent, *commands.AssignRunResponse]
 	releaseRunMapper     mappers.CreateMapperFunc[*agent.Agent, *commands.ReleaseRunResponse]
 	updateMapper         mappers.UpdateMapperFunc[*agent.Agent, *commands.UpdateAgentResponse]
 	deregisterMapper     mappers.DeleteMapperFunc[*commands.DeregisterAgentResponse]
+	revokeMapper         mappers.UpdateMapperFunc[*agent.Agent, *commands.RevokeAgentResponse]
 	getMapper            mappers.GetMapperFunc[*agent.Agent, *queries.GetAgentResponse]
 	listMapper           mappers.ListMapperFunc[agent.Agent, *queries.ListAgentsResponse]
 	availableListMapper  mappers.ListMapperFunc[agent.Agent, *queries.GetAvailableAgentsResponse]
@@ -48,6 +50,7 @@ func NewAgentHandler(
 	releaseRunCommandHandler *agentcommand.ReleaseRunCommandHandler,
 	updateCommandHandler *agentcommand.UpdateAgentCommandHandler,
 	deregisterCommandHandler *agentcommand.DeregisterAgentCommandHandler,
+	revokeCommandHandler *agentcommand.RevokeAgentCommandHandler,
 	getQueryHandler *agentquery.GetAgentQueryHandler,
 	listQueryHandler *agentquery.ListAgentsQueryHandler,
 	getAvailableQueryHandler *agentquery.GetAvailableAgentsQueryHandler,
@@ -60,6 +63,7 @@ func NewAgentHandler(
 		releaseRunCommandHandler:     releaseRunCommandHandler,
 		updateCommandHandler:         updateCommandHandler,
 		deregisterCommandHandler:     deregisterCommandHandler,
+		revokeCommandHandler:         revokeCommandHandler,
 		getQueryHandler:              getQueryHandler,
 		listQueryHandler:             listQueryHandler,
 		getAvailableQueryHandler:     getAvailableQueryHandler,
@@ -70,6 +74,7 @@ func NewAgentHandler(
 		releaseRunMapper:             mappers.AgentReleaseRunMapper,
 		updateMapper:                 mappers.AgentUpdateMapper,
 		deregisterMapper:             mappers.AgentDeregisterMapper,
+		revokeMapper:                 mappers.AgentRevokeMapper,
 		getMapper:                    mappers.AgentGetMapper,
 		listMapper:                   mappers.AgentListMapper,
 		availableListMapper:          mappers.AgentAvailableListMapper,
@@ -93,12 +98,13 @@ func (h *AgentHandler) RegisterAgent(ctx context.Context, req *commands.Register
 			}
 
 			return agentcommand.RegisterAgentCommand{
-				Name:           r.Body.Name,
-				Capabilities:   capabilities,
-				ConnectionInfo: connectionInfo,
+				Name:            r.Body.Name,
+				Capabilities:    capabilities,
+				ConnectionInfo:  connectionInfo,
+				EnrollmentToken: r.Body.EnrollmentToken,
 			}, nil
 		},
-		CommandHandlerFunc[agentcommand.RegisterAgentCommand, *agent.Agent](h.registerCommandHandler.Handle),
+		CommandHandlerFunc[agentcommand.RegisterAgentCommand, *agent.EnrolledAgent](h.registerCommandHandler.Handle),
 		h.registerMapper,
 	)
 }
@@ -112,7 +118,11 @@ func (h *AgentHandler) UpdateHeartbeat(ctx context.Context, req *commands.Update
 			if err != nil {
 				return agentcommand.UpdateHeartbeatCommand{}, err
 			}
-			return agentcommand.UpdateHeartbeatCommand{AgentID: agentID}, nil
+			return agentcommand.UpdateHeartbeatCommand{
+				AgentID:   agentID,
+				Signature: agent.Signature{Timestamp: r.Timestamp, Nonce: r.Nonce, Value: r.Signature},
+				Body:      r.RawBody,
+			}, nil
 		},
 		CommandHandlerFunc[agentcommand.UpdateHeartbeatCommand, *agent.Agent](h.updateHeartbeatCommandHandler.Handle),
 		h.heartbeatMapper,
@@ -160,6 +170,10 @@ func (h *AgentHandler) UpdateAgent(ctx context.Context, req *commands.UpdateAgen
 			if err != nil {
 				return agentcommand.UpdateAgentCommand{}, err
 			}
+			expectedVersion, err := mappers.ParseETag(r.IfMatch)
+			if err != nil {
+				return agentcommand.UpdateAgentCommand{}, err
+			}
 
 			var capabilities *agent.Capabilities
 			if r.Body.Capabilities != nil {
@@ -195,11 +209,12 @@ func (h *AgentHandler) UpdateAgent(ctx context.Context, req *commands.UpdateAgen
 			}
 
 			return agentcommand.UpdateAgentCommand{
-				AgentID:      agentID,
-				Name:         r.Body.Name,
-				Capabilities: capabilities,
-				TagsToAdd:    tagsToAdd,
-				TagsToRemove: tagsToRemove,
+				AgentID:         agentID,
+				Name:            r.Body.Name,
+				Capabilities:    capabilities,
+				TagsToAdd:       tagsToAdd,
+				TagsToRemove:    tagsToRemove,
+				ExpectedVersion: expectedVersion,
 			}, nil
 		},
 		CommandHandlerFunc[agentcommand.UpdateAgentCommand, *agent.Agent](h.updateCommandHandler.Handle),
@@ -223,6 +238,22 @@ func (h *AgentHandler) DeregisterAgent(ctx context.Context, req *commands.Deregi
 	)
 }
 
+func (h *AgentHandler) RevokeAgent(ctx context.Context, req *commands.RevokeAgentRequest) (*commands.RevokeAgentResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.RevokeAgentRequest) (agentcommand.RevokeAgentCommand, error) {
+			agentID, err := agent.NewAgentID(r.ID)
+			if err != nil {
+				return agentcommand.RevokeAgentCommand{}, err
+			}
+			return agentcommand.RevokeAgentCommand{AgentID: agentID}, nil
+		},
+		CommandHandlerFunc[agentcommand.RevokeAgentCommand, *agent.Agent](h.revokeCommandHandler.Handle),
+		h.revokeMapper,
+	)
+}
+
 func (h *AgentHandler) GetAgent(ctx context.Context, req *queries.GetAgentRequest) (*queries.GetAgentResponse, error) {
 	return HandleQuery(
 		ctx,
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+
+	query "parrotflow/internal/application/query/analytics"
+	"parrotflow/internal/domain/analytics"
+	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/interfaces/http/dto/mappers"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+type AnalyticsHandler struct {
+	// Query handlers
+	scenarioQueryHandler *query.GetScenarioAnalyticsQueryHandler
+	fleetQueryHandler    *query.GetFleetAnalyticsQueryHandler
+
+	// Mappers - using functional types
+	reportMapper mappers.GetMapperFunc[*analytics.Report, *queries.GetAnalyticsResponse]
+}
+
+func NewAnalyticsHandler(
+	scenarioQueryHandler *query.GetScenarioAnalyticsQueryHandler,
+	fleetQueryHandler *query.GetFleetAnalyticsQueryHandler,
+) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		scenarioQueryHandler: scenarioQueryHandler,
+		fleetQueryHandler:    fleetQueryHandler,
+		reportMapper:         mappers.AnalyticsReportMapper,
+	}
+}
+
+func (h *AnalyticsHandler) GetScenarioAnalytics(ctx context.Context, req *queries.GetScenarioAnalyticsRequest) (*queries.GetAnalyticsResponse, error) {
+	return HandleQuery(
+		ctx,
+		req,
+		func(r *queries.GetScenarioAnalyticsRequest) (query.GetScenarioAnalyticsQuery, error) {
+			id, err := scenario.NewScenarioID(r.ID)
+			if err != nil {
+				return query.GetScenarioAnalyticsQuery{}, err
+			}
+			return query.GetScenarioAnalyticsQuery{ScenarioID: id, Criteria: analyticsCriteria(r.AnalyticsCriteria)}, nil
+		},
+		QueryHandlerFunc[query.GetScenarioAnalyticsQuery, *analytics.Report](h.scenarioQueryHandler.Handle),
+		h.reportMapper,
+	)
+}
+
+func (h *AnalyticsHandler) GetFleetAnalytics(ctx context.Context, req *queries.GetFleetAnalyticsRequest) (*queries.GetAnalyticsResponse, error) {
+	return HandleQuery(
+		ctx,
+		req,
+		func(r *queries.GetFleetAnalyticsRequest) (query.GetFleetAnalyticsQuery, error) {
+			return query.GetFleetAnalyticsQuery{Criteria: analyticsCriteria(r.AnalyticsCriteria)}, nil
+		},
+		QueryHandlerFunc[query.GetFleetAnalyticsQuery, *analytics.Report](h.fleetQueryHandler.Handle),
+		h.reportMapper,
+	)
+}
+
+func analyticsCriteria(r queries.AnalyticsCriteria) analytics.Criteria {
+	return analytics.Criteria{
+		Since:    r.Since,
+		Until:    r.Until,
+		Interval: r.Interval,
+		Source:   r.Source,
+		Limit:    r.Limit,
+	}
+}
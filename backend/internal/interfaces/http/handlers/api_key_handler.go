@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+
+	command "parrotflow/internal/application/command/apikey"
+	query "parrotflow/internal/application/query/apikey"
+	"parrotflow/internal/domain/apikey"
+	"parrotflow/internal/interfaces/http/dto/commands"
+	"parrotflow/internal/interfaces/http/dto/mappers"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+type APIKeyHandler struct {
+	// Command handlers
+	createCommandHandler *command.CreateAPIKeyCommandHandler
+	revokeCommandHandler *command.RevokeAPIKeyCommandHandler
+
+	// Query handlers
+	listQueryHandler *query.ListAPIKeysQueryHandler
+
+	// Mappers - using functional types
+	createMapper mappers.CreateMapperFunc[*apikey.IssuedKey, *commands.CreateAPIKeyResponse]
+	revokeMapper mappers.UpdateMapperFunc[*apikey.APIKey, *commands.RevokeAPIKeyResponse]
+	listMapper   mappers.ListMapperFunc[apikey.APIKey, *queries.ListAPIKeysResponse]
+}
+
+func NewAPIKeyHandler(
+	createCommandHandler *command.CreateAPIKeyCommandHandler,
+	revokeCommandHandler *command.RevokeAPIKeyCommandHandler,
+	listQueryHandler *query.ListAPIKeysQueryHandler,
+) *APIKeyHandler {
+	return &APIKeyHandler{
+		createCommandHandler: createCommandHandler,
+		revokeCommandHandler: revokeCommandHandler,
+		listQueryHandler:     listQueryHandler,
+		createMapper:         mappers.APIKeyCreateMapper,
+		revokeMapper:         mappers.APIKeyRevokeMapper,
+		listMapper:           mappers.APIKeyListMapper,
+	}
+}
+
+func (h *APIKeyHandler) CreateAPIKey(ctx context.Context, req *commands.CreateAPIKeyRequest) (*commands.CreateAPIKeyResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.CreateAPIKeyRequest) (command.CreateAPIKeyCommand, error) {
+			return command.CreateAPIKeyCommand{
+				Name:      r.Body.Name,
+				Scopes:    r.Body.Scopes,
+				ExpiresAt: r.Body.ExpiresAt,
+			}, nil
+		},
+		CommandHandlerFunc[command.CreateAPIKeyCommand, *apikey.IssuedKey](h.createCommandHandler.Handle),
+		h.createMapper,
+	)
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(ctx context.Context, req *commands.RevokeAPIKeyRequest) (*commands.RevokeAPIKeyResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.RevokeAPIKeyRequest) (command.RevokeAPIKeyCommand, error) {
+			keyID, err := apikey.NewAPIKeyID(r.ID)
+			if err != nil {
+				return command.RevokeAPIKeyCommand{}, err
+			}
+			return command.RevokeAPIKeyCommand{ID: keyID}, nil
+		},
+		CommandHandlerFunc[command.RevokeAPIKeyCommand, *apikey.APIKey](h.revokeCommandHandler.Handle),
+		h.revokeMapper,
+	)
+}
+
+func (h *APIKeyHandler) ListAPIKeys(ctx context.Context, req *queries.ListAPIKeysRequest) (*queries.ListAPIKeysResponse, error) {
+	return HandleQuery(
+		ctx,
+		req,
+		func(r *queries.ListAPIKeysRequest) (query.ListAPIKeysQuery, error) {
+			return query.ListAPIKeysQuery{}, nil
+		},
+		QueryHandlerFunc[query.ListAPIKeysQuery, []*apikey.APIKey](h.listQueryHandler.Handle),
+		h.listMapper,
+	)
+}
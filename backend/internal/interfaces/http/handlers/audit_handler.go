@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+
+	query "parrotflow/internal/application/query/audit"
+	"parrotflow/internal/domain/audit"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/interfaces/http/dto/mappers"
+	"parrotflow/internal/interfaces/http/dto/queries"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type AuditHandler struct {
+	// Query handlers
+	listQueryHandler   *query.ListAuditEntriesQueryHandler
+	exportQueryHandler *query.ExportAuditEntriesQueryHandler
+}
+
+func NewAuditHandler(
+	listQueryHandler *query.ListAuditEntriesQueryHandler,
+	exportQueryHandler *query.ExportAuditEntriesQueryHandler,
+) *AuditHandler {
+	return &AuditHandler{
+		listQueryHandler:   listQueryHandler,
+		exportQueryHandler: exportQueryHandler,
+	}
+}
+
+func (h *AuditHandler) ListAuditEntries(ctx context.Context, req *queries.ListAuditEntriesRequest) (*queries.ListAuditEntriesResponse, error) {
+	return HandleQuery(
+		ctx,
+		req,
+		func(r *queries.ListAuditEntriesRequest) (query.ListAuditEntriesQuery, error) {
+			criteria := auditCriteria(r.AuditFilters)
+			criteria.Limit = r.RPP
+			criteria.Offset = (r.Page - 1) * r.RPP
+			return query.ListAuditEntriesQuery{Criteria: criteria}, nil
+		},
+		QueryHandlerFunc[query.ListAuditEntriesQuery, shared.Page[*audit.Entry]](h.listQueryHandler.Handle),
+		mappers.AuditListMapperFactory(req.Page, req.RPP),
+	)
+}
+
+func (h *AuditHandler) ExportAuditEntries(ctx context.Context, req *queries.ExportAuditEntriesRequest) (*huma.StreamResponse, error) {
+	return HandleQuery(
+		ctx,
+		req,
+		func(r *queries.ExportAuditEntriesRequest) (query.ExportAuditEntriesQuery, error) {
+			return query.ExportAuditEntriesQuery{Criteria: auditCriteria(r.AuditFilters)}, nil
+		},
+		QueryHandlerFunc[query.ExportAuditEntriesQuery, audit.Export](h.exportQueryHandler.Handle),
+		mappers.AuditExportMapperFactory(req.Format),
+	)
+}
+
+func auditCriteria(filters queries.AuditFilters) audit.Criteria {
+	return audit.Criteria{
+		Actor:        filters.Actor,
+		OperationIDs: filters.OperationID,
+		ResourceType: filters.ResourceType,
+		ResourceID:   filters.ResourceID,
+		Outcome:      filters.Outcome,
+		Since:        filters.Since,
+		Until:        filters.Until,
+		OrderDir:     filters.Order,
+	}
+}
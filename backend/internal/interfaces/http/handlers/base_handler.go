@@ -2,7 +2,26 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+
+	"parrotflow/internal/domain/access"
+	"parrotflow/internal/domain/agent"
+	"parrotflow/internal/domain/analytics"
+	"parrotflow/internal/domain/apikey"
+	"parrotflow/internal/domain/deadletter"
+	"parrotflow/internal/domain/enrollment"
+	"parrotflow/internal/domain/proxy"
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/secret"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/domain/tag"
+	"parrotflow/internal/domain/webhook"
+	"parrotflow/internal/infrastructure/tracing"
+	"parrotflow/internal/interfaces/http/dto/mappers"
+
+	"github.com/danielgtaylor/huma/v2"
 )
 
 type CommandHandler[TCommand any, TResult any] interface {
@@ -30,12 +49,12 @@ func HandleCommand[TRequest any, TCommand any, TDomain any, TResponse any](
 
 	cmd, err := buildCommand(request)
 	if err != nil {
-		return zero, fmt.Errorf("invalid request: %w", err)
+		return zero, toHTTPError(fmt.Errorf("invalid request: %w", err))
 	}
 
-	result, err := handler.Handle(ctx, cmd)
+	result, err := traced(ctx, cmd, handler.Handle)
 	if err != nil {
-		return zero, err
+		return zero, toHTTPError(err)
 	}
 
 	return mapper.Map(result), nil
@@ -52,12 +71,12 @@ func HandleQuery[TRequest any, TQuery any, TDomain any, TResponse any](
 
 	query, err := buildQuery(request)
 	if err != nil {
-		return zero, fmt.Errorf("invalid request: %w", err)
+		return zero, toHTTPError(fmt.Errorf("invalid request: %w", err))
 	}
 
-	result, err := handler.Handle(ctx, query)
+	result, err := traced(ctx, query, handler.Handle)
 	if err != nil {
-		return zero, err
+		return zero, toHTTPError(err)
 	}
 
 	return mapper.Map(result), nil
@@ -84,12 +103,14 @@ func HandleSimpleCommand[TRequest any, TCommand any, TResponse any](
 
 	cmd, err := buildCommand(request)
 	if err != nil {
-		return zero, fmt.Errorf("invalid request: %w", err)
+		return zero, toHTTPError(fmt.Errorf("invalid request: %w", err))
 	}
 
-	err = handler.Handle(ctx, cmd)
+	_, err = traced(ctx, cmd, func(ctx context.Context, cmd TCommand) (struct{}, error) {
+		return struct{}{}, handler.Handle(ctx, cmd)
+	})
 	if err != nil {
-		return zero, err
+		return zero, toHTTPError(err)
 	}
 
 	return buildResponse(), nil
@@ -112,3 +133,54 @@ type MapperFunc[TDomain any, TDTO any] func(TDomain) TDTO
 func (f MapperFunc[TDomain, TDTO]) Map(domain TDomain) TDTO {
 	return f(domain)
 }
+
+// traced runs a command or query handler in a span named after the message type
+func traced[TMessage any, TResult any](
+	ctx context.Context,
+	message TMessage,
+	handle func(context.Context, TMessage) (TResult, error),
+) (TResult, error) {
+	ctx, span := tracing.Tracer().Start(ctx, fmt.Sprintf("%T", message))
+	defer span.End()
+
+	result, err := handle(ctx, message)
+	tracing.RecordError(span, err)
+	return result, err
+}
+
+// toHTTPError maps well-known domain errors to HTTP status errors
+// Anything else is passed through unchanged
+func toHTTPError(err error) error {
+	switch {
+	case errors.Is(err, shared.ErrConcurrentModification):
+		return huma.Error409Conflict(err.Error())
+	case errors.Is(err, shared.ErrInvalidPageRequest), errors.Is(err, analytics.ErrInvalidCriteria),
+		errors.Is(err, apikey.ErrExpiryInPast), errors.Is(err, access.ErrUnknownRole), errors.Is(err, access.ErrNoRoles),
+		errors.Is(err, enrollment.ErrExpiryInPast), errors.Is(err, secret.ErrInvalidName), errors.Is(err, secret.ErrEmptyValue),
+		errors.Is(err, secret.ErrUnknownSecret), errors.Is(err, mappers.ErrInvalidETag):
+		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, enrollment.ErrInvalidToken), errors.Is(err, enrollment.ErrTokenExpired),
+		errors.Is(err, enrollment.ErrTokenRevoked), errors.Is(err, agent.ErrMissingSignature),
+		errors.Is(err, agent.ErrInvalidSignature), errors.Is(err, agent.ErrStaleSignature), errors.Is(err, agent.ErrReplayedSignature),
+		errors.Is(err, agent.ErrNoCredential), errors.Is(err, agent.ErrAgentRevoked):
+		return huma.Error401Unauthorized(err.Error())
+	case errors.Is(err, run.ErrRunClaimedByAnotherAgent):
+		return huma.Error403Forbidden(err.Error())
+	case errors.Is(err, shared.ErrEncryptionRequired):
+		return huma.Error422UnprocessableEntity(err.Error())
+	case errors.Is(err, mappers.ErrWeakETag):
+		return huma.Error412PreconditionFailed(err.Error())
+	case errors.Is(err, webhook.ErrWebhookNotFound), errors.Is(err, deadletter.ErrDeadLetterNotFound),
+		errors.Is(err, analytics.ErrScenarioNotFound), errors.Is(err, apikey.ErrAPIKeyNotFound),
+		errors.Is(err, access.ErrAssignmentNotFound), errors.Is(err, enrollment.ErrTokenNotFound),
+		errors.Is(err, agent.ErrAgentNotFound), errors.Is(err, secret.ErrSecretNotFound),
+		errors.Is(err, scenario.ErrScenarioNotFound), errors.Is(err, tag.ErrTagNotFound),
+		errors.Is(err, proxy.ErrProxyNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, deadletter.ErrAlreadyResolved), errors.Is(err, enrollment.ErrTokenUsed),
+		errors.Is(err, agent.ErrAgentAlreadyExists), errors.Is(err, secret.ErrSecretAlreadyExists):
+		return huma.Error409Conflict(err.Error())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+
+	command "parrotflow/internal/application/command/deadletter"
+	query "parrotflow/internal/application/query/deadletter"
+	"parrotflow/internal/domain/deadletter"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/interfaces/http/dto/commands"
+	"parrotflow/internal/interfaces/http/dto/mappers"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+type DeadLetterHandler struct {
+	// Command handlers
+	replayCommandHandler  *command.ReplayDeadLetterCommandHandler
+	discardCommandHandler *command.DiscardDeadLetterCommandHandler
+
+	// Query handlers
+	getQueryHandler  *query.GetDeadLetterQueryHandler
+	listQueryHandler *query.ListDeadLettersQueryHandler
+
+	// Mappers - using functional types
+	replayMapper  mappers.UpdateMapperFunc[*deadletter.DeadLetter, *commands.ReplayDeadLetterResponse]
+	discardMapper mappers.UpdateMapperFunc[*deadletter.DeadLetter, *commands.DiscardDeadLetterResponse]
+	getMapper     mappers.GetMapperFunc[*deadletter.DeadLetter, *queries.GetDeadLetterResponse]
+}
+
+func NewDeadLetterHandler(
+	replayCommandHandler *command.ReplayDeadLetterCommandHandler,
+	discardCommandHandler *command.DiscardDeadLetterCommandHandler,
+	getQueryHandler *query.GetDeadLetterQueryHandler,
+	listQueryHandler *query.ListDeadLettersQueryHandler,
+) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		replayCommandHandler:  replayCommandHandler,
+		discardCommandHandler: discardCommandHandler,
+		getQueryHandler:       getQueryHandler,
+		listQueryHandler:      listQueryHandler,
+		replayMapper:          mappers.DeadLetterReplayMapper,
+		discardMapper:         mappers.DeadLetterDiscardMapper,
+		getMapper:             mappers.DeadLetterGetMapper,
+	}
+}
+
+func (h *DeadLetterHandler) ReplayDeadLetter(ctx context.Context, req *commands.ReplayDeadLetterRequest) (*commands.ReplayDeadLetterResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.ReplayDeadLetterRequest) (command.ReplayDeadLetterCommand, error) {
+			id, err := deadletter.NewDeadLetterID(r.ID)
+			if err != nil {
+				return command.ReplayDeadLetterCommand{}, err
+			}
+			return command.ReplayDeadLetterCommand{ID: id}, nil
+		},
+		CommandHandlerFunc[command.ReplayDeadLetterCommand, *deadletter.DeadLetter](h.replayCommandHandler.Handle),
+		h.replayMapper,
+	)
+}
+
+func (h *DeadLetterHandler) DiscardDeadLetter(ctx context.Context, req *commands.DiscardDeadLetterRequest) (*commands.DiscardDeadLetterResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.DiscardDeadLetterRequest) (command.DiscardDeadLetterCommand, error) {
+			id, err := deadletter.NewDeadLetterID(r.ID)
+			if err != nil {
+				return command.DiscardDeadLetterCommand{}, err
+			}
+			return command.DiscardDeadLetterCommand{ID: id}, nil
+		},
+		CommandHandlerFunc[command.DiscardDeadLetterCommand, *deadletter.DeadLetter](h.discardCommandHandler.Handle),
+		h.discardMapper,
+	)
+}
+
+func (h *DeadLetterHandler) GetDeadLetter(ctx context.Context, req *queries.GetDeadLetterRequest) (*queries.GetDeadLetterResponse, error) {
+	return HandleQuery(
+		ctx,
+		req,
+		func(r *queries.GetDeadLetterRequest) (query.GetDeadLetterQuery, error) {
+			id, err := deadletter.NewDeadLetterID(r.ID)
+			if err != nil {
+				return query.GetDeadLetterQuery{}, err
+			}
+			return query.GetDeadLetterQuery{ID: id}, nil
+		},
+		QueryHandlerFunc[query.GetDeadLetterQuery, *deadletter.DeadLetter](h.getQueryHandler.Handle),
+		h.getMapper,
+	)
+}
+
+func (h *DeadLetterHandler) ListDeadLetters(ctx context.Context, req *queries.ListDeadLettersRequest) (*queries.ListDeadLettersResponse, error) {
+	return HandleQuery(
+		ctx,
+		req,
+		func(r *queries.ListDeadLettersRequest) (query.ListDeadLettersQuery, error) {
+			criteria := deadletter.Criteria{
+				Queue:  r.Queue,
+				Limit:  r.RPP,
+				Offset: (r.Page - 1) * r.RPP,
+			}
+			if r.Status != "" {
+				status, err := deadletter.NewStatus(r.Status)
+				if err != nil {
+					return query.ListDeadLettersQuery{}, err
+				}
+				criteria.Status = &status
+			}
+			return query.ListDeadLettersQuery{Criteria: criteria}, nil
+		},
+		QueryHandlerFunc[query.ListDeadLettersQuery, shared.Page[*deadletter.DeadLetter]](h.listQueryHandler.Handle),
+		mappers.DeadLetterListMapperFactory(req.Page, req.RPP),
+	)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+
+	command "parrotflow/internal/application/command/enrollment"
+	query "parrotflow/internal/application/query/enrollment"
+	"parrotflow/internal/domain/enrollment"
+	"parrotflow/internal/interfaces/http/dto/commands"
+	"parrotflow/internal/interfaces/http/dto/mappers"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+type EnrollmentHandler struct {
+	// Command handlers
+	createCommandHandler *command.CreateEnrollmentTokenCommandHandler
+	revokeCommandHandler *command.RevokeEnrollmentTokenCommandHandler
+
+	// Query handlers
+	listQueryHandler *query.ListEnrollmentTokensQueryHandler
+
+	// Mappers - using functional types
+	createMapper mappers.CreateMapperFunc[*enrollment.IssuedToken, *commands.CreateEnrollmentTokenResponse]
+	revokeMapper mappers.UpdateMapperFunc[*enrollment.Token, *commands.RevokeEnrollmentTokenResponse]
+	listMapper   mappers.ListMapperFunc[enrollment.Token, *queries.ListEnrollmentTokensResponse]
+}
+
+func NewEnrollmentHandler(
+	createCommandHandler *command.CreateEnrollmentTokenCommandHandler,
+	revokeCommandHandler *command.RevokeEnrollmentTokenCommandHandler,
+	listQueryHandler *query.ListEnrollmentTokensQueryHandler,
+) *EnrollmentHandler {
+	return &EnrollmentHandler{
+		createCommandHandler: createCommandHandler,
+		revokeCommandHandler: revokeCommandHandler,
+		listQueryHandler:     listQueryHandler,
+		createMapper:         mappers.EnrollmentTokenCreateMapper,
+		revokeMapper:         mappers.EnrollmentTokenRevokeMapper,
+		listMapper:           mappers.EnrollmentTokenListMapper,
+	}
+}
+
+func (h *EnrollmentHandler) CreateEnrollmentToken(ctx context.Context, req *commands.CreateEnrollmentTokenRequest) (*commands.CreateEnrollmentTokenResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.CreateEnrollmentTokenRequest) (command.CreateEnrollmentTokenCommand, error) {
+			return command.CreateEnrollmentTokenCommand{
+				Name:      r.Body.Name,
+				ExpiresAt: r.Body.ExpiresAt,
+			}, nil
+		},
+		CommandHandlerFunc[command.CreateEnrollmentTokenCommand, *enrollment.IssuedToken](h.createCommandHandler.Handle),
+		h.createMapper,
+	)
+}
+
+func (h *EnrollmentHandler) RevokeEnrollmentToken(ctx context.Context, req *commands.RevokeEnrollmentTokenRequest) (*commands.RevokeEnrollmentTokenResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.RevokeEnrollmentTokenRequest) (command.RevokeEnrollmentTokenCommand, error) {
+			tokenID, err := enrollment.NewTokenID(r.ID)
+			if err != nil {
+				return command.RevokeEnrollmentTokenCommand{}, err
+			}
+			return command.RevokeEnrollmentTokenCommand{ID: tokenID}, nil
+		},
+		CommandHandlerFunc[command.RevokeEnrollmentTokenCommand, *enrollment.Token](h.revokeCommandHandler.Handle),
+		h.revokeMapper,
+	)
+}
+
+func (h *EnrollmentHandler) ListEnrollmentTokens(ctx context.Context, req *queries.ListEnrollmentTokensRequest) (*queries.ListEnrollmentTokensResponse, error) {
+	return HandleQuery(
+		ctx,
+		req,
+		func(r *queries.ListEnrollmentTokensRequest) (query.ListEnrollmentTokensQuery, error) {
+			return query.ListEnrollmentTokensQuery{}, nil
+		},
+		QueryHandlerFunc[query.ListEnrollmentTokensQuery, []*enrollment.Token](h.listQueryHandler.Handle),
+		h.listMapper,
+	)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+
+	query "parrotflow/internal/application/query/eventlog"
+	"parrotflow/internal/domain/eventlog"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/interfaces/http/dto/mappers"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+type EventHandler struct {
+	// Query handlers
+	listQueryHandler    *query.ListEventsQueryHandler
+	historyQueryHandler *query.GetAggregateHistoryQueryHandler
+}
+
+func NewEventHandler(
+	listQueryHandler *query.ListEventsQueryHandler,
+	historyQueryHandler *query.GetAggregateHistoryQueryHandler,
+) *EventHandler {
+	return &EventHandler{
+		listQueryHandler:    listQueryHandler,
+		historyQueryHandler: historyQueryHandler,
+	}
+}
+
+func (h *EventHandler) ListEvents(ctx context.Context, req *queries.ListEventsRequest) (*queries.ListEventsResponse, error) {
+	return HandleQuery(
+		ctx,
+		req,
+		func(r *queries.ListEventsRequest) (query.ListEventsQuery, error) {
+			return query.ListEventsQuery{Criteria: eventlog.Criteria{
+				EventTypes:    r.Type,
+				AggregateType: r.AggregateType,
+				AggregateID:   r.AggregateID,
+				CorrelationID: r.CorrelationID,
+				Since:         r.Since,
+				Until:         r.Until,
+				OrderDir:      r.Order,
+				Limit:         r.RPP,
+				Offset:        (r.Page - 1) * r.RPP,
+			}}, nil
+		},
+		QueryHandlerFunc[query.ListEventsQuery, shared.Page[*eventlog.Entry]](h.listQueryHandler.Handle),
+		mappers.EventListMapperFactory(req.Page, req.RPP),
+	)
+}
+
+// History returns the history endpoint of one kind of aggregate
+func (h *EventHandler) History(aggregateType string) func(context.Context, *queries.GetAggregateHistoryRequest) (*queries.ListEventsResponse, error) {
+	return func(ctx context.Context, req *queries.GetAggregateHistoryRequest) (*queries.ListEventsResponse, error) {
+		return HandleQuery(
+			ctx,
+			req,
+			func(r *queries.GetAggregateHistoryRequest) (query.GetAggregateHistoryQuery, error) {
+				return query.GetAggregateHistoryQuery{
+					AggregateType: aggregateType,
+					AggregateID:   r.ID,
+					Criteria: eventlog.Criteria{
+						EventTypes: r.Type,
+						Since:      r.Since,
+						Until:      r.Until,
+						OrderDir:   r.Order,
+						Limit:      r.RPP,
+						Offset:     (r.Page - 1) * r.RPP,
+					},
+				}, nil
+			},
+			QueryHandlerFunc[query.GetAggregateHistoryQuery, shared.Page[*eventlog.Entry]](h.historyQueryHandler.Handle),
+			mappers.EventListMapperFactory(req.Page, req.RPP),
+		)
+	}
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"parrotflow/internal/infrastructure/health"
+	"parrotflow/internal/interfaces/http/dto/mappers"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+type HealthHandler struct {
+	registry *health.Registry
+}
+
+func NewHealthHandler(registry *health.Registry) *HealthHandler {
+	return &HealthHandler{registry: registry}
+}
+
+// Live answers as long as the process serves requests; it probes nothing, so a
+// failing dependency never gets the instance restarted
+func (h *HealthHandler) Live(ctx context.Context, req *queries.HealthRequest) (*queries.HealthResponse, error) {
+	report := health.Report{Status: health.StatusUp, Ready: true, CheckedAt: time.Now()}
+	return mappers.HealthReportToResponse(report, h.registry.Uptime()), nil
+}
+
+// Ready runs every probe and fails while a critical component is down
+func (h *HealthHandler) Ready(ctx context.Context, req *queries.HealthRequest) (*queries.HealthResponse, error) {
+	return mappers.HealthReportToResponse(h.registry.Check(ctx), h.registry.Uptime()), nil
+}
@@ -19,6 +19,7 @@ type ProxyHandler struct {
 	activateCommandHandler   *command.ActivateProxyCommandHandler
 	deactivateCommandHandler *command.DeactivateProxyCommandHandler
 	recordHealthCommandHandler *command.RecordHealthCommandHandler
+	restoreCommandHandler    *command.RestoreProxyCommandHandler
 
 	// Queries
 	getQueryHandler    *query.GetProxyQueryHandler
@@ -32,7 +33,9 @@ type ProxyHandler struct {
 	activateMapper     mappers.CreateMapperFunc[*proxy.Proxy, *commands.ActivateProxyResponse]
 	deactivateMapper   mappers.CreateMapperFunc[*proxy.Proxy, *commands.DeactivateProxyResponse]
 	recordHealthMapper mappers.CreateMapperFunc[*proxy.Proxy, *commands.RecordHealthResponse]
+	restoreMapper      mappers.UpdateMapperFunc[*proxy.Proxy, *commands.RestoreProxyResponse]
 	getMapper          mappers.GetMapperFunc[*proxy.Proxy, *queries.GetProxyResponse]
+	credentialsMapper  mappers.GetMapperFunc[*proxy.Proxy, *queries.GetProxyCredentialsResponse]
 	listMapper         mappers.ListMapperFunc[proxy.Proxy, *queries.ListProxiesResponse]
 	activeListMapper   mappers.ListMapperFunc[proxy.Proxy, *queries.GetActiveProxiesResponse]
 }
@@ -44,6 +47,7 @@ func NewProxyHandler(
 	recordHealthCommandHandler *command.RecordHealthCommandHandler,
 	activateCommandHandler *command.ActivateProxyCommandHandler,
 	deactivateCommandHandler *command.DeactivateProxyCommandHandler,
+	restoreCommandHandler *command.RestoreProxyCommandHandler,
 	getQueryHandler *query.GetProxyQueryHandler,
 	listQueryHandler *query.ListProxiesQueryHandler,
 	activeQueryHandler *query.GetActiveProxiesQueryHandler,
@@ -55,6 +59,7 @@ func NewProxyHandler(
 		activateCommandHandler:     activateCommandHandler,
 		deactivateCommandHandler:   deactivateCommandHandler,
 		recordHealthCommandHandler: recordHealthCommandHandler,
+		restoreCommandHandler:      restoreCommandHandler,
 		getQueryHandler:            getQueryHandler,
 		listQueryHandler:           listQueryHandler,
 		activeQueryHandler:         activeQueryHandler,
@@ -64,7 +69,9 @@ func NewProxyHandler(
 		activateMapper:             mappers.ProxyActivateMapper,
 		deactivateMapper:           mappers.ProxyDeactivateMapper,
 		recordHealthMapper:         mappers.ProxyRecordHealthMapper,
+		restoreMapper:              mappers.ProxyRestoreMapper,
 		getMapper:                  mappers.ProxyGetMapper,
+		credentialsMapper:          mappers.ProxyCredentialsMapper,
 		listMapper:                 mappers.ProxyListMapper,
 		activeListMapper:           mappers.ProxyActiveListMapper,
 	}
@@ -94,12 +101,17 @@ func (h *ProxyHandler) UpdateProxy(ctx context.Context, req *commands.UpdateProx
 		ctx,
 		req,
 		func(r *commands.UpdateProxyRequest) (command.UpdateProxyCommand, error) {
+			expectedVersion, err := mappers.ParseETag(r.IfMatch)
+			if err != nil {
+				return command.UpdateProxyCommand{}, err
+			}
 			return command.UpdateProxyCommand{
-				ID:       r.ID,
-				Name:     r.Body.Name,
-				Host:     r.Body.Host,
-				Port:     r.Body.Port,
-				Protocol: r.Body.Protocol,
+				ID:              r.ID,
+				Name:            r.Body.Name,
+				Host:            r.Body.Host,
+				Port:            r.Body.Port,
+				Protocol:        r.Body.Protocol,
+				ExpectedVersion: expectedVersion,
 			}, nil
 		},
 		CommandHandlerFunc[command.UpdateProxyCommand, *proxy.Proxy](h.updateCommandHandler.Handle),
@@ -176,12 +188,40 @@ func (h *ProxyHandler) GetProxy(ctx context.Context, req *queries.GetProxyReques
 	)
 }
 
+func (h *ProxyHandler) GetProxyCredentials(ctx context.Context, req *queries.GetProxyCredentialsRequest) (*queries.GetProxyCredentialsResponse, error) {
+	return HandleQuery(
+		ctx,
+		req,
+		func(r *queries.GetProxyCredentialsRequest) (query.GetProxyQuery, error) {
+			proxyID, err := proxy.NewProxyID(r.ID)
+			if err != nil {
+				return query.GetProxyQuery{}, err
+			}
+			return query.GetProxyQuery{ID: proxyID}, nil
+		},
+		QueryHandlerFunc[query.GetProxyQuery, *proxy.Proxy](h.getQueryHandler.Handle),
+		h.credentialsMapper,
+	)
+}
+
+func (h *ProxyHandler) RestoreProxy(ctx context.Context, req *commands.RestoreProxyRequest) (*commands.RestoreProxyResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.RestoreProxyRequest) (command.RestoreProxyCommand, error) {
+			return command.RestoreProxyCommand{ID: r.ID}, nil
+		},
+		CommandHandlerFunc[command.RestoreProxyCommand, *proxy.Proxy](h.restoreCommandHandler.Handle),
+		h.restoreMapper,
+	)
+}
+
 func (h *ProxyHandler) ListProxies(ctx context.Context, req *queries.ListProxiesRequest) (*queries.ListProxiesResponse, error) {
 	return HandleQuery(
 		ctx,
 		req,
 		func(r *queries.ListProxiesRequest) (query.ListProxiesQuery, error) {
-			return query.ListProxiesQuery{}, nil
+			return query.ListProxiesQuery{Trashed: r.Trashed}, nil
 		},
 		QueryHandlerFunc[query.ListProxiesQuery, []*proxy.Proxy](h.listQueryHandler.Handle),
 		h.listMapper,
@@ -2,44 +2,69 @@ package handlers
 
 import (
 	"context"
+	"strconv"
+	"time"
 
 	command "parrotflow/internal/application/command/run"
 	query "parrotflow/internal/application/query/run"
+	"parrotflow/internal/domain/agent"
 	"parrotflow/internal/domain/run"
 	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/realtime"
 	"parrotflow/internal/interfaces/http/dto/commands"
 	"parrotflow/internal/interfaces/http/dto/mappers"
 	"parrotflow/internal/interfaces/http/dto/queries"
+
+	"github.com/danielgtaylor/huma/v2/sse"
+)
+
+const (
+	// runStreamHeartbeat is how often an idle run stream sends a heartbeat event
+	runStreamHeartbeat = 15 * time.Second
+	// runStreamRetry tells clients how long to wait before reconnecting, in milliseconds
+	runStreamRetry = 2000
 )
 
 type RunHandler struct {
 	createCommandHandler *command.CreateRunCommandHandler
 	startCommandHandler  *command.StartRunCommandHandler
+	cancelCommandHandler *command.CancelRunCommandHandler
 	getQueryHandler      *query.GetRunQueryHandler
 	listQueryHandler     *query.ListRunsQueryHandler
+	progressHandler      *command.ReportRunProgressCommandHandler
+	hub                  *realtime.Hub
 
 	// Mappers - using functional types
-	createMapper mappers.CreateMapperFunc[*run.Run, *commands.CreateRunResponse]
-	startMapper  mappers.CreateMapperFunc[*run.Run, *commands.StartRunResponse]
-	getMapper    mappers.GetMapperFunc[*run.Run, *queries.GetRunResponse]
-	listMapper   mappers.ListMapperFunc[run.Run, *queries.ListRunsResponse]
+	createMapper   mappers.CreateMapperFunc[*run.Run, *commands.CreateRunResponse]
+	startMapper    mappers.CreateMapperFunc[*run.Run, *commands.StartRunResponse]
+	cancelMapper   mappers.UpdateMapperFunc[*run.Run, *commands.CancelRunResponse]
+	getMapper      mappers.GetMapperFunc[*run.Run, *queries.GetRunResponse]
+	progressMapper mappers.UpdateMapperFunc[*run.Run, *commands.ReportRunProgressResponse]
 }
 
 func NewRunHandler(
 	createCommandHandler *command.CreateRunCommandHandler,
 	startCommandHandler *command.StartRunCommandHandler,
+	cancelCommandHandler *command.CancelRunCommandHandler,
 	getQueryHandler *query.GetRunQueryHandler,
 	listQueryHandler *query.ListRunsQueryHandler,
+	progressHandler *command.ReportRunProgressCommandHandler,
+	hub *realtime.Hub,
 ) *RunHandler {
 	return &RunHandler{
 		createCommandHandler: createCommandHandler,
 		startCommandHandler:  startCommandHandler,
+		cancelCommandHandler: cancelCommandHandler,
 		getQueryHandler:      getQueryHandler,
 		listQueryHandler:     listQueryHandler,
+		progressHandler:      progressHandler,
+		hub:                  hub,
 		createMapper:         mappers.RunCreateMapper,
 		startMapper:          mappers.RunStartMapper,
+		cancelMapper:         mappers.RunCancelMapper,
 		getMapper:            mappers.RunGetMapper,
-		listMapper:           mappers.RunListMapper,
+		progressMapper:       mappers.RunProgressMapper,
 	}
 }
 
@@ -75,6 +100,22 @@ func (h *RunHandler) StartRun(ctx context.Context, req *commands.StartRunRequest
 	)
 }
 
+func (h *RunHandler) CancelRun(ctx context.Context, req *commands.CancelRunRequest) (*commands.CancelRunResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.CancelRunRequest) (command.CancelRunCommand, error) {
+			runID, err := run.NewRunID(r.ID)
+			if err != nil {
+				return command.CancelRunCommand{}, err
+			}
+			return command.CancelRunCommand{RunID: runID}, nil
+		},
+		CommandHandlerFunc[command.CancelRunCommand, *run.Run](h.cancelCommandHandler.Handle),
+		h.cancelMapper,
+	)
+}
+
 func (h *RunHandler) GetRun(ctx context.Context, req *queries.GetRunRequest) (*queries.GetRunResponse, error) {
 	return HandleQuery(
 		ctx,
@@ -96,7 +137,16 @@ func (h *RunHandler) ListRuns(ctx context.Context, req *queries.ListRunsRequest)
 		ctx,
 		req,
 		func(r *queries.ListRunsRequest) (query.ListRunsQuery, error) {
-			criteria := run.NewSearchCriteria()
+			// A cursor picks up where the previous page ended, so the page number no longer applies
+			offset := (r.Page - 1) * r.RPP
+			if r.Cursor != "" {
+				offset = 0
+			}
+			criteria := run.NewSearchCriteria().
+				WithStatus(r.Status).
+				WithPagination(r.RPP, offset).
+				WithOrdering(r.Sort, r.Order).
+				WithCursor(r.Cursor)
 			if r.ScenarioID != "" {
 				scenarioID, err := scenario.NewScenarioID(r.ScenarioID)
 				if err != nil {
@@ -106,7 +156,115 @@ func (h *RunHandler) ListRuns(ctx context.Context, req *queries.ListRunsRequest)
 			}
 			return query.ListRunsQuery{Criteria: criteria}, nil
 		},
-		QueryHandlerFunc[query.ListRunsQuery, []*run.Run](h.listQueryHandler.Handle),
-		h.listMapper,
+		QueryHandlerFunc[query.ListRunsQuery, shared.Page[*run.Run]](h.listQueryHandler.Handle),
+		mappers.RunListMapperFactory(req.Page, req.RPP),
+	)
+}
+
+func (h *RunHandler) ReportRunProgress(ctx context.Context, req *commands.ReportRunProgressRequest) (*commands.ReportRunProgressResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.ReportRunProgressRequest) (command.ReportRunProgressCommand, error) {
+			runID, err := run.NewRunID(r.ID)
+			if err != nil {
+				return command.ReportRunProgressCommand{}, err
+			}
+			status, err := run.NewNodeStatus(r.Body.Status)
+			if err != nil {
+				return command.ReportRunProgressCommand{}, err
+			}
+			agentID, err := agent.NewAgentID(r.AgentID)
+			if err != nil {
+				return command.ReportRunProgressCommand{}, err
+			}
+			return command.ReportRunProgressCommand{
+				RunID:     runID,
+				NodeID:    r.Body.NodeID,
+				Status:    status,
+				Message:   r.Body.Message,
+				AgentID:   agentID,
+				Signature: agent.Signature{Timestamp: r.Timestamp, Nonce: r.Nonce, Value: r.Signature},
+				Body:      r.RawBody,
+			}, nil
+		},
+		CommandHandlerFunc[command.ReportRunProgressCommand, *run.Run](h.progressHandler.Handle),
+		h.progressMapper,
 	)
 }
+
+// StreamRunEvents sends a snapshot of the run followed by its state changes and node progress
+// With Last-Event-ID the buffered events after that ID are replayed instead of the snapshot,
+// unless some of them are no longer buffered, e.g. after a restart; then the snapshot is sent
+// The stream ends once the run is finished, or when the client falls too far behind
+func (h *RunHandler) StreamRunEvents(ctx context.Context, req *queries.StreamRunEventsRequest, send sse.Sender) {
+	runID, err := run.NewRunID(req.ID)
+	if err != nil {
+		send.Data(queries.StreamErrorEvent{Message: err.Error()})
+		return
+	}
+	lastEventID, _ := strconv.ParseUint(req.LastEventID, 10, 64)
+
+	// Subscribe before loading the run so no change slips in between
+	sub, replay, complete := h.hub.Subscribe(realtime.RunTopic(runID.String()), lastEventID)
+	defer sub.Close()
+	resume := lastEventID != 0 && complete
+
+	r, err := h.getQueryHandler.Handle(ctx, query.GetRunQuery{ID: runID})
+	if err != nil {
+		send.Data(queries.StreamErrorEvent{Message: err.Error()})
+		return
+	}
+	if !resume {
+		if err := send(sse.Message{Data: mappers.RunToStatusEvent(r), Retry: runStreamRetry}); err != nil {
+			return
+		}
+		replay = nil
+	}
+
+	for _, msg := range replay {
+		if done, err := sendRunEvent(send, msg); done || err != nil {
+			return
+		}
+	}
+	if r.IsFinished() {
+		if resume {
+			send.Data(mappers.RunToStatusEvent(r))
+		}
+		return
+	}
+
+	heartbeat := time.NewTicker(runStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-sub.Messages():
+			if !ok {
+				// Dropped for falling behind, the client resumes with Last-Event-ID
+				return
+			}
+			if done, err := sendRunEvent(send, msg); done || err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := send.Data(queries.StreamHeartbeatEvent{At: mappers.FormatTimestamp(time.Now())}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// sendRunEvent writes one hub message and reports whether it was the last one of the run
+func sendRunEvent(send sse.Sender, msg realtime.Message) (bool, error) {
+	data, ok := mappers.RunEventToStreamEvent(msg.Event)
+	if !ok {
+		return false, nil
+	}
+	if err := send(sse.Message{ID: int(msg.ID), Data: data}); err != nil {
+		return true, err
+	}
+	return realtime.IsFinalRunEvent(msg.Event), nil
+}
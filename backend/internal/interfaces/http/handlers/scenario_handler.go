@@ -6,42 +6,53 @@ import (
 	command "parrotflow/internal/application/command/scenario"
 	query "parrotflow/internal/application/query/scenario"
 	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/shared"
 	"parrotflow/internal/interfaces/http/dto/commands"
 	"parrotflow/internal/interfaces/http/dto/mappers"
 	"parrotflow/internal/interfaces/http/dto/queries"
 )
 
 type ScenarioHandler struct {
-	createCommandHandler *command.CreateScenarioCommandHandler
-	updateCommandHandler *command.UpdateScenarioCommandHandler
-	deleteCommandHandler *command.DeleteScenarioCommandHandler
-	getQueryHandler      *query.GetScenarioQueryHandler
-	listQueryHandler     *query.ListScenariosQueryHandler
+	createCommandHandler  *command.CreateScenarioCommandHandler
+	updateCommandHandler  *command.UpdateScenarioCommandHandler
+	deleteCommandHandler  *command.DeleteScenarioCommandHandler
+	restoreCommandHandler *command.RestoreScenarioCommandHandler
+	retentionHandler      *command.SetScenarioRetentionCommandHandler
+	getQueryHandler       *query.GetScenarioQueryHandler
+	listQueryHandler      *query.ListScenariosQueryHandler
 
 	// Mappers - using functional types
-	createMapper mappers.CreateMapperFunc[*scenario.Scenario, *commands.CreateScenarioResponse]
-	updateMapper mappers.UpdateMapperFunc[*scenario.Scenario, *commands.UpdateScenarioResponse]
-	deleteMapper mappers.DeleteMapperFunc[*commands.DeleteScenarioResponse]
-	getMapper    mappers.GetMapperFunc[*scenario.Scenario, *queries.GetScenarioResponse]
+	createMapper    mappers.CreateMapperFunc[*scenario.Scenario, *commands.CreateScenarioResponse]
+	updateMapper    mappers.UpdateMapperFunc[*scenario.Scenario, *commands.UpdateScenarioResponse]
+	deleteMapper    mappers.DeleteMapperFunc[*commands.DeleteScenarioResponse]
+	restoreMapper   mappers.UpdateMapperFunc[*scenario.Scenario, *commands.RestoreScenarioResponse]
+	retentionMapper mappers.UpdateMapperFunc[*scenario.Scenario, *commands.ScenarioRetentionResponse]
+	getMapper       mappers.GetMapperFunc[*scenario.Scenario, *queries.GetScenarioResponse]
 }
 
 func NewScenarioHandler(
 	createCommandHandler *command.CreateScenarioCommandHandler,
 	updateCommandHandler *command.UpdateScenarioCommandHandler,
 	deleteCommandHandler *command.DeleteScenarioCommandHandler,
+	restoreCommandHandler *command.RestoreScenarioCommandHandler,
+	retentionHandler *command.SetScenarioRetentionCommandHandler,
 	getQueryHandler *query.GetScenarioQueryHandler,
 	listQueryHandler *query.ListScenariosQueryHandler,
 ) *ScenarioHandler {
 	return &ScenarioHandler{
-		createCommandHandler: createCommandHandler,
-		updateCommandHandler: updateCommandHandler,
-		deleteCommandHandler: deleteCommandHandler,
-		getQueryHandler:      getQueryHandler,
-		listQueryHandler:     listQueryHandler,
-		createMapper:         mappers.ScenarioCreateMapper,
-		updateMapper:         mappers.ScenarioUpdateMapper,
-		deleteMapper:         mappers.ScenarioDeleteMapper,
-		getMapper:            mappers.ScenarioGetMapper,
+		createCommandHandler:  createCommandHandler,
+		updateCommandHandler:  updateCommandHandler,
+		deleteCommandHandler:  deleteCommandHandler,
+		restoreCommandHandler: restoreCommandHandler,
+		retentionHandler:      retentionHandler,
+		getQueryHandler:       getQueryHandler,
+		listQueryHandler:      listQueryHandler,
+		createMapper:          mappers.ScenarioCreateMapper,
+		updateMapper:          mappers.ScenarioUpdateMapper,
+		deleteMapper:          mappers.ScenarioDeleteMapper,
+		restoreMapper:         mappers.ScenarioRestoreMapper,
+		retentionMapper:       mappers.ScenarioRetentionMapper,
+		getMapper:             mappers.ScenarioGetMapper,
 	}
 }
 
@@ -86,11 +97,16 @@ func (h *ScenarioHandler) ListScenarios(ctx context.Context, req *queries.ListSc
 			limit := r.RPP
 			offset := (r.Page - 1) * r.RPP
 			return query.ListScenariosQuery{Criteria: scenario.SearchCriteria{
-				Limit:  limit,
-				Offset: offset,
+				Name:     r.Name,
+				Tag:      r.Tag,
+				Trashed:  r.Trashed,
+				Limit:    limit,
+				Offset:   offset,
+				OrderBy:  r.Sort,
+				OrderDir: r.Order,
 			}}, nil
 		},
-		QueryHandlerFunc[query.ListScenariosQuery, []*scenario.Scenario](h.listQueryHandler.Handle),
+		QueryHandlerFunc[query.ListScenariosQuery, shared.Page[*scenario.Scenario]](h.listQueryHandler.Handle),
 		mappers.ScenarioListMapperFactory(req.Page, req.RPP),
 	)
 }
@@ -104,13 +120,18 @@ func (h *ScenarioHandler) UpdateScenario(ctx context.Context, req *commands.Upda
 			if err != nil {
 				return command.UpdateScenarioCommand{}, err
 			}
+			expectedVersion, err := mappers.ParseETag(r.IfMatch)
+			if err != nil {
+				return command.UpdateScenarioCommand{}, err
+			}
 
 			cmd := command.UpdateScenarioCommand{
-				ID:          scenarioID,
-				Name:        r.Body.Name,
-				Description: r.Body.Description,
-				Tag:         r.Body.Tag,
-				Icon:        r.Body.Icon,
+				ID:              scenarioID,
+				Name:            r.Body.Name,
+				Description:     r.Body.Description,
+				Tag:             r.Body.Tag,
+				Icon:            r.Body.Icon,
+				ExpectedVersion: expectedVersion,
 			}
 
 			// Map value objects if provided
@@ -149,3 +170,55 @@ func (h *ScenarioHandler) DeleteScenario(ctx context.Context, req *commands.Dele
 		h.deleteMapper.Map,
 	)
 }
+
+func (h *ScenarioHandler) RestoreScenario(ctx context.Context, req *commands.RestoreScenarioRequest) (*commands.RestoreScenarioResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.RestoreScenarioRequest) (command.RestoreScenarioCommand, error) {
+			scenarioID, err := scenario.NewScenarioID(r.ID)
+			if err != nil {
+				return command.RestoreScenarioCommand{}, err
+			}
+			return command.RestoreScenarioCommand{ID: scenarioID}, nil
+		},
+		CommandHandlerFunc[command.RestoreScenarioCommand, *scenario.Scenario](h.restoreCommandHandler.Handle),
+		h.restoreMapper,
+	)
+}
+
+func (h *ScenarioHandler) SetScenarioRetention(ctx context.Context, req *commands.SetScenarioRetentionRequest) (*commands.ScenarioRetentionResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.SetScenarioRetentionRequest) (command.SetScenarioRetentionCommand, error) {
+			scenarioID, err := scenario.NewScenarioID(r.ID)
+			if err != nil {
+				return command.SetScenarioRetentionCommand{}, err
+			}
+			policy, err := mappers.MapRetentionFromDTO(r.Body)
+			if err != nil {
+				return command.SetScenarioRetentionCommand{}, err
+			}
+			return command.SetScenarioRetentionCommand{ID: scenarioID, Policy: &policy}, nil
+		},
+		CommandHandlerFunc[command.SetScenarioRetentionCommand, *scenario.Scenario](h.retentionHandler.Handle),
+		h.retentionMapper,
+	)
+}
+
+func (h *ScenarioHandler) ClearScenarioRetention(ctx context.Context, req *commands.ClearScenarioRetentionRequest) (*commands.ScenarioRetentionResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.ClearScenarioRetentionRequest) (command.SetScenarioRetentionCommand, error) {
+			scenarioID, err := scenario.NewScenarioID(r.ID)
+			if err != nil {
+				return command.SetScenarioRetentionCommand{}, err
+			}
+			return command.SetScenarioRetentionCommand{ID: scenarioID}, nil
+		},
+		CommandHandlerFunc[command.SetScenarioRetentionCommand, *scenario.Scenario](h.retentionHandler.Handle),
+		h.retentionMapper,
+	)
+}
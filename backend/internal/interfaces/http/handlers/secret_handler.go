@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+
+	command "parrotflow/internal/application/command/secret"
+	query "parrotflow/internal/application/query/secret"
+	"parrotflow/internal/domain/secret"
+	"parrotflow/internal/interfaces/http/dto/commands"
+	"parrotflow/internal/interfaces/http/dto/mappers"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+type SecretHandler struct {
+	// Command handlers
+	createCommandHandler *command.CreateSecretCommandHandler
+	updateCommandHandler *command.UpdateSecretCommandHandler
+	deleteCommandHandler *command.DeleteSecretCommandHandler
+
+	// Query handlers
+	getQueryHandler  *query.GetSecretQueryHandler
+	listQueryHandler *query.ListSecretsQueryHandler
+
+	// Mappers - using functional types
+	createMapper mappers.CreateMapperFunc[*secret.Secret, *commands.CreateSecretResponse]
+	updateMapper mappers.UpdateMapperFunc[*secret.Secret, *commands.UpdateSecretResponse]
+	deleteMapper mappers.DeleteMapperFunc[*commands.DeleteSecretResponse]
+	getMapper    mappers.GetMapperFunc[*secret.Secret, *queries.GetSecretResponse]
+	listMapper   mappers.ListMapperFunc[secret.Secret, *queries.ListSecretsResponse]
+}
+
+func NewSecretHandler(
+	createCommandHandler *command.CreateSecretCommandHandler,
+	updateCommandHandler *command.UpdateSecretCommandHandler,
+	deleteCommandHandler *command.DeleteSecretCommandHandler,
+	getQueryHandler *query.GetSecretQueryHandler,
+	listQueryHandler *query.ListSecretsQueryHandler,
+) *SecretHandler {
+	return &SecretHandler{
+		createCommandHandler: createCommandHandler,
+		updateCommandHandler: updateCommandHandler,
+		deleteCommandHandler: deleteCommandHandler,
+		getQueryHandler:      getQueryHandler,
+		listQueryHandler:     listQueryHandler,
+		createMapper:         mappers.SecretCreateMapper,
+		updateMapper:         mappers.SecretUpdateMapper,
+		deleteMapper:         mappers.SecretDeleteMapper,
+		getMapper:            mappers.SecretGetMapper,
+		listMapper:           mappers.SecretListMapper,
+	}
+}
+
+func (h *SecretHandler) CreateSecret(ctx context.Context, req *commands.CreateSecretRequest) (*commands.CreateSecretResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.CreateSecretRequest) (command.CreateSecretCommand, error) {
+			return command.CreateSecretCommand{
+				Name:        r.Body.Name,
+				Value:       r.Body.Value,
+				Description: r.Body.Description,
+			}, nil
+		},
+		CommandHandlerFunc[command.CreateSecretCommand, *secret.Secret](h.createCommandHandler.Handle),
+		h.createMapper,
+	)
+}
+
+func (h *SecretHandler) UpdateSecret(ctx context.Context, req *commands.UpdateSecretRequest) (*commands.UpdateSecretResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.UpdateSecretRequest) (command.UpdateSecretCommand, error) {
+			secretID, err := secret.NewSecretID(r.ID)
+			if err != nil {
+				return command.UpdateSecretCommand{}, err
+			}
+			expectedVersion, err := mappers.ParseETag(r.IfMatch)
+			if err != nil {
+				return command.UpdateSecretCommand{}, err
+			}
+			return command.UpdateSecretCommand{
+				ID:              secretID,
+				Value:           r.Body.Value,
+				Description:     r.Body.Description,
+				ExpectedVersion: expectedVersion,
+			}, nil
+		},
+		CommandHandlerFunc[command.UpdateSecretCommand, *secret.Secret](h.updateCommandHandler.Handle),
+		h.updateMapper,
+	)
+}
+
+func (h *SecretHandler) DeleteSecret(ctx context.Context, req *commands.DeleteSecretRequest) (*commands.DeleteSecretResponse, error) {
+	return HandleSimpleCommand(
+		ctx,
+		req,
+		func(r *commands.DeleteSecretRequest) (command.DeleteSecretCommand, error) {
+			secretID, err := secret.NewSecretID(r.ID)
+			if err != nil {
+				return command.DeleteSecretCommand{}, err
+			}
+			return command.DeleteSecretCommand{ID: secretID}, nil
+		},
+		SimpleCommandHandlerFunc[command.DeleteSecretCommand](h.deleteCommandHandler.Handle),
+		h.deleteMapper.Map,
+	)
+}
+
+func (h *SecretHandler) GetSecret(ctx context.Context, req *queries.GetSecretRequest) (*queries.GetSecretResponse, error) {
+	return HandleQuery(
+		ctx,
+		req,
+		func(r *queries.GetSecretRequest) (query.GetSecretQuery, error) {
+			secretID, err := secret.NewSecretID(r.ID)
+			if err != nil {
+				return query.GetSecretQuery{}, err
+			}
+			return query.GetSecretQuery{ID: secretID}, nil
+		},
+		QueryHandlerFunc[query.GetSecretQuery, *secret.Secret](h.getQueryHandler.Handle),
+		h.getMapper,
+	)
+}
+
+func (h *SecretHandler) ListSecrets(ctx context.Context, req *queries.ListSecretsRequest) (*queries.ListSecretsResponse, error) {
+	return HandleQuery(
+		ctx,
+		req,
+		func(r *queries.ListSecretsRequest) (query.ListSecretsQuery, error) {
+			return query.ListSecretsQuery{}, nil
+		},
+		QueryHandlerFunc[query.ListSecretsQuery, []*secret.Secret](h.listQueryHandler.Handle),
+		h.listMapper,
+	)
+}
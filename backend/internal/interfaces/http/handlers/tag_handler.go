@@ -13,40 +13,45 @@ import (
 
 type TagHandler struct {
 	// Command handlers
-	createCommandHandler *command.CreateTagCommandHandler
-	updateCommandHandler *command.UpdateTagCommandHandler
-	deleteCommandHandler *command.DeleteTagCommandHandler
+	createCommandHandler  *command.CreateTagCommandHandler
+	updateCommandHandler  *command.UpdateTagCommandHandler
+	deleteCommandHandler  *command.DeleteTagCommandHandler
+	restoreCommandHandler *command.RestoreTagCommandHandler
 
 	// Query handlers
 	getQueryHandler  *query.GetTagQueryHandler
 	listQueryHandler *query.ListTagsQueryHandler
 
 	// Mappers - using functional types
-	createMapper mappers.CreateMapperFunc[*tag.Tag, *commands.CreateTagResponse]
-	updateMapper mappers.UpdateMapperFunc[*tag.Tag, *commands.UpdateTagResponse]
-	deleteMapper mappers.DeleteMapperFunc[*commands.DeleteTagResponse]
-	getMapper    mappers.GetMapperFunc[*tag.Tag, *queries.GetTagResponse]
-	listMapper   mappers.ListMapperFunc[tag.Tag, *queries.ListTagsResponse]
+	createMapper  mappers.CreateMapperFunc[*tag.Tag, *commands.CreateTagResponse]
+	updateMapper  mappers.UpdateMapperFunc[*tag.Tag, *commands.UpdateTagResponse]
+	deleteMapper  mappers.DeleteMapperFunc[*commands.DeleteTagResponse]
+	restoreMapper mappers.UpdateMapperFunc[*tag.Tag, *commands.RestoreTagResponse]
+	getMapper     mappers.GetMapperFunc[*tag.Tag, *queries.GetTagResponse]
+	listMapper    mappers.ListMapperFunc[tag.Tag, *queries.ListTagsResponse]
 }
 
 func NewTagHandler(
 	createCommandHandler *command.CreateTagCommandHandler,
 	updateCommandHandler *command.UpdateTagCommandHandler,
 	deleteCommandHandler *command.DeleteTagCommandHandler,
+	restoreCommandHandler *command.RestoreTagCommandHandler,
 	getQueryHandler *query.GetTagQueryHandler,
 	listQueryHandler *query.ListTagsQueryHandler,
 ) *TagHandler {
 	return &TagHandler{
-		createCommandHandler: createCommandHandler,
-		updateCommandHandler: updateCommandHandler,
-		deleteCommandHandler: deleteCommandHandler,
-		getQueryHandler:      getQueryHandler,
-		listQueryHandler:     listQueryHandler,
-		createMapper:         mappers.TagCreateMapper,
-		updateMapper:         mappers.TagUpdateMapper,
-		deleteMapper:         mappers.TagDeleteMapper,
-		getMapper:            mappers.TagGetMapper,
-		listMapper:           mappers.TagListMapper,
+		createCommandHandler:  createCommandHandler,
+		updateCommandHandler:  updateCommandHandler,
+		deleteCommandHandler:  deleteCommandHandler,
+		restoreCommandHandler: restoreCommandHandler,
+		getQueryHandler:       getQueryHandler,
+		listQueryHandler:      listQueryHandler,
+		createMapper:          mappers.TagCreateMapper,
+		updateMapper:          mappers.TagUpdateMapper,
+		deleteMapper:          mappers.TagDeleteMapper,
+		restoreMapper:         mappers.TagRestoreMapper,
+		getMapper:             mappers.TagGetMapper,
+		listMapper:            mappers.TagListMapper,
 	}
 }
 
@@ -76,10 +81,15 @@ func (h *TagHandler) UpdateTag(ctx context.Context, req *commands.UpdateTagReque
 			if err != nil {
 				return command.UpdateTagCommand{}, err
 			}
+			expectedVersion, err := mappers.ParseETag(r.IfMatch)
+			if err != nil {
+				return command.UpdateTagCommand{}, err
+			}
 			return command.UpdateTagCommand{
-				ID:          tagID,
-				Description: r.Body.Description,
-				Color:       r.Body.Color,
+				ID:              tagID,
+				Description:     r.Body.Description,
+				Color:           r.Body.Color,
+				ExpectedVersion: expectedVersion,
 			}, nil
 		},
 		CommandHandlerFunc[command.UpdateTagCommand, *tag.Tag](h.updateCommandHandler.Handle),
@@ -103,6 +113,22 @@ func (h *TagHandler) DeleteTag(ctx context.Context, req *commands.DeleteTagReque
 	)
 }
 
+func (h *TagHandler) RestoreTag(ctx context.Context, req *commands.RestoreTagRequest) (*commands.RestoreTagResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.RestoreTagRequest) (command.RestoreTagCommand, error) {
+			tagID, err := tag.NewTagID(r.ID)
+			if err != nil {
+				return command.RestoreTagCommand{}, err
+			}
+			return command.RestoreTagCommand{ID: tagID}, nil
+		},
+		CommandHandlerFunc[command.RestoreTagCommand, *tag.Tag](h.restoreCommandHandler.Handle),
+		h.restoreMapper,
+	)
+}
+
 func (h *TagHandler) GetTag(ctx context.Context, req *queries.GetTagRequest) (*queries.GetTagResponse, error) {
 	return HandleQuery(
 		ctx,
@@ -124,7 +150,7 @@ func (h *TagHandler) ListTags(ctx context.Context, req *queries.ListTagsRequest)
 		ctx,
 		req,
 		func(r *queries.ListTagsRequest) (query.ListTagsQuery, error) {
-			q := query.ListTagsQuery{}
+			q := query.ListTagsQuery{Trashed: r.Trashed}
 			if r.Category != "" {
 				category, err := tag.NewTagCategory(r.Category)
 				if err != nil {
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+
+	command "parrotflow/internal/application/command/webhook"
+	query "parrotflow/internal/application/query/webhook"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/domain/webhook"
+	"parrotflow/internal/interfaces/http/dto/commands"
+	"parrotflow/internal/interfaces/http/dto/mappers"
+	"parrotflow/internal/interfaces/http/dto/queries"
+)
+
+type WebhookHandler struct {
+	// Command handlers
+	createCommandHandler *command.CreateWebhookCommandHandler
+	updateCommandHandler *command.UpdateWebhookCommandHandler
+	deleteCommandHandler *command.DeleteWebhookCommandHandler
+	enableCommandHandler *command.EnableWebhookCommandHandler
+
+	// Query handlers
+	getQueryHandler        *query.GetWebhookQueryHandler
+	listQueryHandler       *query.ListWebhooksQueryHandler
+	deliveriesQueryHandler *query.ListWebhookDeliveriesQueryHandler
+
+	// Mappers - using functional types
+	createMapper mappers.CreateMapperFunc[*webhook.Webhook, *commands.CreateWebhookResponse]
+	updateMapper mappers.UpdateMapperFunc[*webhook.Webhook, *commands.UpdateWebhookResponse]
+	deleteMapper mappers.DeleteMapperFunc[*commands.DeleteWebhookResponse]
+	enableMapper mappers.UpdateMapperFunc[*webhook.Webhook, *commands.EnableWebhookResponse]
+	getMapper    mappers.GetMapperFunc[*webhook.Webhook, *queries.GetWebhookResponse]
+	listMapper   mappers.ListMapperFunc[webhook.Webhook, *queries.ListWebhooksResponse]
+}
+
+func NewWebhookHandler(
+	createCommandHandler *command.CreateWebhookCommandHandler,
+	updateCommandHandler *command.UpdateWebhookCommandHandler,
+	deleteCommandHandler *command.DeleteWebhookCommandHandler,
+	enableCommandHandler *command.EnableWebhookCommandHandler,
+	getQueryHandler *query.GetWebhookQueryHandler,
+	listQueryHandler *query.ListWebhooksQueryHandler,
+	deliveriesQueryHandler *query.ListWebhookDeliveriesQueryHandler,
+) *WebhookHandler {
+	return &WebhookHandler{
+		createCommandHandler:   createCommandHandler,
+		updateCommandHandler:   updateCommandHandler,
+		deleteCommandHandler:   deleteCommandHandler,
+		enableCommandHandler:   enableCommandHandler,
+		getQueryHandler:        getQueryHandler,
+		listQueryHandler:       listQueryHandler,
+		deliveriesQueryHandler: deliveriesQueryHandler,
+		createMapper:           mappers.WebhookCreateMapper,
+		updateMapper:           mappers.WebhookUpdateMapper,
+		deleteMapper:           mappers.WebhookDeleteMapper,
+		enableMapper:           mappers.WebhookEnableMapper,
+		getMapper:              mappers.WebhookGetMapper,
+		listMapper:             mappers.WebhookListMapper,
+	}
+}
+
+func (h *WebhookHandler) CreateWebhook(ctx context.Context, req *commands.CreateWebhookRequest) (*commands.CreateWebhookResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.CreateWebhookRequest) (command.CreateWebhookCommand, error) {
+			return command.CreateWebhookCommand{
+				URL:         r.Body.URL,
+				EventTypes:  r.Body.EventTypes,
+				Secret:      r.Body.Secret,
+				Description: r.Body.Description,
+			}, nil
+		},
+		CommandHandlerFunc[command.CreateWebhookCommand, *webhook.Webhook](h.createCommandHandler.Handle),
+		h.createMapper,
+	)
+}
+
+func (h *WebhookHandler) UpdateWebhook(ctx context.Context, req *commands.UpdateWebhookRequest) (*commands.UpdateWebhookResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.UpdateWebhookRequest) (command.UpdateWebhookCommand, error) {
+			webhookID, err := webhook.NewWebhookID(r.ID)
+			if err != nil {
+				return command.UpdateWebhookCommand{}, err
+			}
+			expectedVersion, err := mappers.ParseETag(r.IfMatch)
+			if err != nil {
+				return command.UpdateWebhookCommand{}, err
+			}
+			return command.UpdateWebhookCommand{
+				ID:              webhookID,
+				URL:             r.Body.URL,
+				EventTypes:      r.Body.EventTypes,
+				Secret:          r.Body.Secret,
+				Description:     r.Body.Description,
+				ExpectedVersion: expectedVersion,
+			}, nil
+		},
+		CommandHandlerFunc[command.UpdateWebhookCommand, *webhook.Webhook](h.updateCommandHandler.Handle),
+		h.updateMapper,
+	)
+}
+
+func (h *WebhookHandler) DeleteWebhook(ctx context.Context, req *commands.DeleteWebhookRequest) (*commands.DeleteWebhookResponse, error) {
+	return HandleSimpleCommand(
+		ctx,
+		req,
+		func(r *commands.DeleteWebhookRequest) (command.DeleteWebhookCommand, error) {
+			webhookID, err := webhook.NewWebhookID(r.ID)
+			if err != nil {
+				return command.DeleteWebhookCommand{}, err
+			}
+			return command.DeleteWebhookCommand{ID: webhookID}, nil
+		},
+		SimpleCommandHandlerFunc[command.DeleteWebhookCommand](h.deleteCommandHandler.Handle),
+		h.deleteMapper.Map,
+	)
+}
+
+func (h *WebhookHandler) EnableWebhook(ctx context.Context, req *commands.EnableWebhookRequest) (*commands.EnableWebhookResponse, error) {
+	return HandleCommand(
+		ctx,
+		req,
+		func(r *commands.EnableWebhookRequest) (command.EnableWebhookCommand, error) {
+			webhookID, err := webhook.NewWebhookID(r.ID)
+			if err != nil {
+				return command.EnableWebhookCommand{}, err
+			}
+			return command.EnableWebhookCommand{ID: webhookID}, nil
+		},
+		CommandHandlerFunc[command.EnableWebhookCommand, *webhook.Webhook](h.enableCommandHandler.Handle),
+		h.enableMapper,
+	)
+}
+
+func (h *WebhookHandler) GetWebhook(ctx context.Context, req *queries.GetWebhookRequest) (*queries.GetWebhookResponse, error) {
+	return HandleQuery(
+		ctx,
+		req,
+		func(r *queries.GetWebhookRequest) (query.GetWebhookQuery, error) {
+			webhookID, err := webhook.NewWebhookID(r.ID)
+			if err != nil {
+				return query.GetWebhookQuery{}, err
+			}
+			return query.GetWebhookQuery{ID: webhookID}, nil
+		},
+		QueryHandlerFunc[query.GetWebhookQuery, *webhook.Webhook](h.getQueryHandler.Handle),
+		h.getMapper,
+	)
+}
+
+func (h *WebhookHandler) ListWebhooks(ctx context.Context, req *queries.ListWebhooksRequest) (*queries.ListWebhooksResponse, error) {
+	return HandleQuery(
+		ctx,
+		req,
+		func(r *queries.ListWebhooksRequest) (query.ListWebhooksQuery, error) {
+			return query.ListWebhooksQuery{}, nil
+		},
+		QueryHandlerFunc[query.ListWebhooksQuery, []*webhook.Webhook](h.listQueryHandler.Handle),
+		h.listMapper,
+	)
+}
+
+func (h *WebhookHandler) ListWebhookDeliveries(ctx context.Context, req *queries.ListWebhookDeliveriesRequest) (*queries.ListWebhookDeliveriesResponse, error) {
+	return HandleQuery(
+		ctx,
+		req,
+		func(r *queries.ListWebhookDeliveriesRequest) (query.ListWebhookDeliveriesQuery, error) {
+			webhookID, err := webhook.NewWebhookID(r.ID)
+			if err != nil {
+				return query.ListWebhookDeliveriesQuery{}, err
+			}
+			criteria := webhook.DeliveryCriteria{
+				WebhookID: webhookID,
+				Limit:     r.RPP,
+				Offset:    (r.Page - 1) * r.RPP,
+			}
+			if r.Status != "" {
+				status, err := webhook.NewDeliveryStatus(r.Status)
+				if err != nil {
+					return query.ListWebhookDeliveriesQuery{}, err
+				}
+				criteria.Status = &status
+			}
+			return query.ListWebhookDeliveriesQuery{Criteria: criteria}, nil
+		},
+		QueryHandlerFunc[query.ListWebhookDeliveriesQuery, shared.Page[*webhook.Delivery]](h.deliveriesQueryHandler.Handle),
+		mappers.WebhookDeliveryListMapperFactory(req.Page, req.RPP),
+	)
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"parrotflow/internal/domain/audit"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/auth"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// MetadataAudited marks operations whose calls are recorded in the audit log
+const MetadataAudited = "audited"
+
+// MetadataRedactedFields lists the request fields of an operation redacted from the
+// audit log on top of those audit.Summarize always redacts, e.g. the value of a secret
+const MetadataRedactedFields = "redactedFields"
+
+// maxAuditedResponseBytes bounds the part of responses kept to find the created
+// resource's ID or the error detail in
+const maxAuditedResponseBytes = 16 << 10
+
+// auditRecord collects what inner middleware learns about a call, Authenticate
+// notes the principal there since the context it stores it in is not handed back
+type auditRecord struct {
+	principal *auth.Principal
+}
+
+type auditRecordKey struct{}
+
+// Audit is huma middleware recording calls to audited operations once they have been
+// served, whatever their outcome; it runs before Authenticate so denied calls are
+// recorded too. Failing to record a call is logged and does not fail the call
+func Audit(repository audit.Repository) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		op := ctx.Operation()
+		if op.Metadata[MetadataAudited] != true {
+			next(ctx)
+			return
+		}
+
+		start := time.Now()
+		recorded := &auditedContext{humaContext: ctx, response: &cappedBuffer{limit: maxAuditedResponseBytes}}
+		recorded.request, recorded.body = peekBody(ctx.BodyReader())
+		record := &auditRecord{}
+		next(huma.WithValue(recorded, auditRecordKey{}, record))
+
+		status := ctx.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		fields, _ := op.Metadata[MetadataRedactedFields].([]string)
+		entry := &audit.Entry{
+			OccurredAt:    start,
+			Actor:         audit.ActorAnonymous,
+			OperationID:   op.OperationID,
+			Method:        ctx.Method(),
+			Path:          ctx.URL().Path,
+			ResourceType:  resourceType(op.Path),
+			ResourceID:    pathResourceID(ctx, op.Path),
+			Request:       audit.Summarize(recorded.request, fields...),
+			Status:        status,
+			Outcome:       audit.OutcomeOf(status),
+			CorrelationID: shared.CorrelationFromContext(ctx.Context()).CorrelationID,
+			Duration:      time.Since(start),
+		}
+		if principal := record.principal; principal != nil {
+			entry.Actor = principal.Subject
+			entry.ActorName = principal.Name
+			entry.AuthMethod = principal.Method
+		}
+		var response struct {
+			ID     string `json:"id"`
+			Detail string `json:"detail"`
+		}
+		if json.Unmarshal(recorded.response.Bytes(), &response) == nil {
+			if entry.ResourceID == "" && entry.Outcome == audit.OutcomeSuccess {
+				entry.ResourceID = response.ID
+			}
+			if entry.Outcome != audit.OutcomeSuccess {
+				entry.Error = response.Detail
+			}
+		}
+
+		// The call is served; its cancellation must not lose the entry
+		if err := repository.Append(context.WithoutCancel(ctx.Context()), entry); err != nil {
+			slog.ErrorContext(ctx.Context(), "Could not record audit entry",
+				"operation_id", entry.OperationID, "actor", entry.Actor, "error", err)
+		}
+	}
+}
+
+// noteAuditedPrincipal tells Audit who made the call ctx belongs to
+func noteAuditedPrincipal(ctx context.Context, principal *auth.Principal) {
+	if record, ok := ctx.Value(auditRecordKey{}).(*auditRecord); ok {
+		record.principal = principal
+	}
+}
+
+// humaContext lets auditedContext embed huma.Context, whose Context method a field
+// named Context would shadow
+type humaContext = huma.Context
+
+// auditedContext hands the handler the request body Audit already read from
+// and keeps the start of the response
+type auditedContext struct {
+	humaContext
+	request  []byte
+	body     io.Reader
+	response *cappedBuffer
+}
+
+func (c *auditedContext) BodyReader() io.Reader {
+	return c.body
+}
+
+func (c *auditedContext) BodyWriter() io.Writer {
+	return io.MultiWriter(c.humaContext.BodyWriter(), c.response)
+}
+
+// peekBody reads enough of a request body to summarize it and returns a reader
+// yielding the whole body again
+// Bodies larger than audit.MaxRequestBytes are left to stream; huma limits their size
+func peekBody(body io.Reader) ([]byte, io.Reader) {
+	if body == nil {
+		return nil, http.NoBody
+	}
+	head, _ := io.ReadAll(io.LimitReader(body, audit.MaxRequestBytes+1))
+	return head, io.MultiReader(bytes.NewReader(head), body)
+}
+
+// cappedBuffer keeps the first limit bytes written to it and discards the rest
+type cappedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(room, len(p))])
+	}
+	return len(p), nil
+}
+
+// resourceType is the first segment of an operation's path after /api, e.g. runs
+func resourceType(path string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(path, "/api"), "/"), "/")
+	return segment
+}
+
+// pathResourceID is the value of the first parameter in an operation's path, if any
+func pathResourceID(ctx huma.Context, path string) string {
+	_, rest, found := strings.Cut(path, "{")
+	if !found {
+		return ""
+	}
+	name, _, _ := strings.Cut(rest, "}")
+	return ctx.Param(name)
+}
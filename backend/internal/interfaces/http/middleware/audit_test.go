@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"parrotflow/internal/domain/audit"
+	"parrotflow/internal/infrastructure/auth"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+type recordingAuditLog struct {
+	audit.Repository
+	entries []*audit.Entry
+}
+
+func (l *recordingAuditLog) Append(ctx context.Context, entry *audit.Entry) error {
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+func TestAudit(t *testing.T) {
+	_, api := humatest.New(t)
+	log := &recordingAuditLog{}
+
+	// Stands in for Authenticate, which runs after Audit, with the caller named in a header
+	api.UseMiddleware(Audit(log), func(ctx huma.Context, next func(huma.Context)) {
+		subject := ctx.Header("X-Subject")
+		if subject == "" {
+			_ = huma.WriteErr(api, ctx, http.StatusUnauthorized, "authentication required")
+			return
+		}
+		next(huma.WithContext(ctx, withPrincipal(ctx.Context(), &auth.Principal{Subject: subject, Method: "api_key"})))
+	})
+
+	type secretInput struct {
+		Body struct {
+			Name   string            `json:"name"`
+			Value  string            `json:"value"`
+			Params []json.RawMessage `json:"params,omitempty"`
+		}
+	}
+	type secretOutput struct {
+		Body struct {
+			ID string `json:"id"`
+		}
+	}
+	var received string
+	huma.Register(api, huma.Operation{
+		OperationID: "create-secret",
+		Method:      http.MethodPost,
+		Path:        "/api/secrets/",
+		Metadata:    map[string]any{MetadataAudited: true, MetadataRedactedFields: []string{"value"}},
+	}, func(ctx context.Context, input *secretInput) (*secretOutput, error) {
+		received = input.Body.Value
+		out := &secretOutput{}
+		out.Body.ID = "7"
+		return out, nil
+	})
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-secret",
+		Method:      http.MethodDelete,
+		Path:        "/api/secrets/{id}",
+		Metadata:    map[string]any{MetadataAudited: true},
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		return nil, huma.Error404NotFound("secret not found")
+	})
+	huma.Register(api, huma.Operation{
+		OperationID: "update-agent-heartbeat",
+		Method:      http.MethodPost,
+		Path:        "/api/agents/{id}/heartbeat",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	body := map[string]any{
+		"name":   "SHOP",
+		"value":  "hunter2",
+		"params": []any{map[string]any{"name": "password", "value": "swordfish"}, map[string]any{"token": "pfe_x"}},
+	}
+	if resp := api.Post("/api/secrets/", "X-Subject: apikey:1", body); resp.Code != http.StatusOK {
+		t.Fatalf("Create status = %d, body %s", resp.Code, resp.Body.String())
+	}
+	if received != "hunter2" {
+		t.Errorf("Handler got value %q, want the request body untouched", received)
+	}
+	api.Delete("/api/secrets/3", "X-Subject: apikey:1")
+	api.Delete("/api/secrets/3")
+	api.Post("/api/agents/1/heartbeat", "X-Subject: agent", map[string]any{})
+
+	if len(log.entries) != 3 {
+		t.Fatalf("Recorded %d entries, want 3 leaving the unaudited heartbeat out", len(log.entries))
+	}
+
+	created := log.entries[0]
+	if created.Actor != "apikey:1" || created.OperationID != "create-secret" || created.ResourceType != "secrets" ||
+		created.ResourceID != "7" || created.Outcome != audit.OutcomeSuccess {
+		t.Errorf("Create entry = %+v, want apikey:1 creating secret 7", created)
+	}
+	for _, leaked := range []string{"hunter2", "swordfish", "pfe_x"} {
+		if strings.Contains(string(created.Request), leaked) {
+			t.Errorf("Request summary %s leaks %q", created.Request, leaked)
+		}
+	}
+	if !strings.Contains(string(created.Request), `"name":"SHOP"`) {
+		t.Errorf("Request summary %s, want it to keep the name", created.Request)
+	}
+
+	if failed := log.entries[1]; failed.ResourceID != "3" || failed.Status != http.StatusNotFound ||
+		failed.Outcome != audit.OutcomeFailure || failed.Error == "" {
+		t.Errorf("Delete entry = %+v, want a failure on secret 3 with its error", failed)
+	}
+	if denied := log.entries[2]; denied.Actor != audit.ActorAnonymous || denied.Outcome != audit.OutcomeDenied {
+		t.Errorf("Anonymous entry = %+v, want an anonymous denial", denied)
+	}
+}
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"parrotflow/internal/infrastructure/auth"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HeaderAPIKey carries an API key; keys are accepted as bearer tokens as well
+const HeaderAPIKey = "X-API-Key"
+
+// ProtocolAccessTokenPrefix marks the Sec-WebSocket-Protocol value carrying a bearer token on
+// WebSocket upgrades, browsers cannot set headers there. Unlike a query parameter it does not
+// end up in URLs logged by proxies, and the server never selects it as the protocol
+const ProtocolAccessTokenPrefix = "access_token."
+
+// MetadataSelfAuthenticated marks operations that authenticate their callers themselves,
+// such as agents registering with an enrollment token or signing their heartbeats
+// Only operations on the public allowlist of the routes carry it
+const MetadataSelfAuthenticated = "selfAuthenticated"
+
+// IsSelfAuthenticated reports whether an operation is exempt from Authenticate and Authorize
+func IsSelfAuthenticated(op *huma.Operation) bool {
+	return op.Metadata[MetadataSelfAuthenticated] == true
+}
+
+// challenge is the WWW-Authenticate value of 401 responses
+const challenge = `Bearer realm="parrotflow"`
+
+// Authenticate is huma middleware rejecting calls without valid credentials, or whose
+// credentials grant none of the scopes the operation's security requirements list
+// Only self-authenticated operations are exempt; one without requirements, e.g. hidden
+// from the OpenAPI description and so never given any, still needs credentials
+// The principal is available to handlers through auth.PrincipalFrom
+func Authenticate(api huma.API, authenticator *auth.Authenticator) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		if IsSelfAuthenticated(ctx.Operation()) {
+			next(ctx)
+			return
+		}
+		requirements := ctx.Operation().Security
+
+		principal, err := authenticator.Authenticate(ctx.Context(), ctx.Header("Authorization"), ctx.Header(HeaderAPIKey))
+		if err != nil {
+			if !isCredentialsError(err) {
+				_ = huma.WriteErr(api, ctx, http.StatusInternalServerError, "could not verify credentials")
+				return
+			}
+			ctx.SetHeader("WWW-Authenticate", challenge)
+			_ = huma.WriteErr(api, ctx, http.StatusUnauthorized, "authentication required", err)
+			return
+		}
+
+		if scope := missingScope(principal, requirements); scope != "" {
+			noteAuditedPrincipal(ctx.Context(), principal)
+			_ = huma.WriteErr(api, ctx, http.StatusForbidden, fmt.Sprintf("this operation requires the %s scope", scope))
+			return
+		}
+
+		next(huma.WithContext(ctx, withPrincipal(ctx.Context(), principal)))
+	}
+}
+
+// RequireScope protects plain HTTP handlers, such as the WebSocket endpoint, like
+// Authenticate protects API operations
+func RequireScope(authenticator *auth.Authenticator, scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization := r.Header.Get("Authorization")
+			if token := protocolAccessToken(r); token != "" && authorization == "" {
+				authorization = "Bearer " + token
+			}
+
+			principal, err := authenticator.Authenticate(r.Context(), authorization, r.Header.Get(HeaderAPIKey))
+			if err != nil {
+				if !isCredentialsError(err) {
+					http.Error(w, "could not verify credentials", http.StatusInternalServerError)
+					return
+				}
+				w.Header().Set("WWW-Authenticate", challenge)
+				http.Error(w, "authentication required: "+err.Error(), http.StatusUnauthorized)
+				return
+			}
+			if !principal.Allows(scope) {
+				http.Error(w, fmt.Sprintf("this endpoint requires the %s scope", scope), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(withPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// protocolAccessToken returns the bearer token offered as a WebSocket subprotocol, if any
+func protocolAccessToken(r *http.Request) string {
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(header, ",") {
+			if token, ok := strings.CutPrefix(strings.TrimSpace(protocol), ProtocolAccessTokenPrefix); ok {
+				return token
+			}
+		}
+	}
+	return ""
+}
+
+// missingScope returns the scope to report when no requirement is met, empty when one is
+// Requirements are alternatives (API key or bearer token) listing the same scopes
+func missingScope(principal *auth.Principal, requirements []map[string][]string) string {
+	missing := ""
+	for _, requirement := range requirements {
+		satisfied := true
+		for _, scopes := range requirement {
+			for _, scope := range scopes {
+				if !principal.Allows(scope) {
+					satisfied = false
+					missing = scope
+				}
+			}
+		}
+		if satisfied {
+			return ""
+		}
+	}
+	return missing
+}
+
+// isCredentialsError tells the caller's mistakes from failures to check credentials
+func isCredentialsError(err error) bool {
+	return errors.Is(err, auth.ErrMissingCredentials) || errors.Is(err, auth.ErrInvalidCredentials)
+}
+
+// withPrincipal stores the principal and names it on the request's span and in its audit entry
+func withPrincipal(ctx context.Context, principal *auth.Principal) context.Context {
+	noteAuditedPrincipal(ctx, principal)
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("enduser.id", principal.Subject),
+		attribute.String("enduser.auth_method", principal.Method),
+	)
+	return auth.WithPrincipal(ctx, principal)
+}
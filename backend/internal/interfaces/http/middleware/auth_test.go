@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProtocolAccessToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol string
+		want     string
+	}{
+		{"offered with the channel protocol", "parrotflow.v1, access_token.eyJhbGciOi.e30.sig", "eyJhbGciOi.e30.sig"},
+		{"not offered", "parrotflow.v1", ""},
+		{"no protocol", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/ws?access_token=ignored", nil)
+			if tt.protocol != "" {
+				r.Header.Set("Sec-WebSocket-Protocol", tt.protocol)
+			}
+			if got := protocolAccessToken(r); got != tt.want {
+				t.Errorf("protocolAccessToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
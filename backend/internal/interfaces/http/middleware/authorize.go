@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"parrotflow/internal/domain/access"
+	"parrotflow/internal/infrastructure/auth"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// MetadataPermission is the operation metadata key holding the access.Permission it requires
+// The empty permission lets any authenticated caller in
+const MetadataPermission = "permission"
+
+// PermissionOf returns the permission an operation requires, if any
+func PermissionOf(op *huma.Operation) (access.Permission, bool) {
+	permission, ok := op.Metadata[MetadataPermission].(access.Permission)
+	return permission, ok
+}
+
+// Authorize is huma middleware rejecting calls by principals whose roles lack the
+// permission of the operation; it runs after Authenticate, before the handler
+// Operations without a permission are refused unless they are self-authenticated
+func Authorize(api huma.API) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		if IsSelfAuthenticated(ctx.Operation()) {
+			next(ctx)
+			return
+		}
+		permission, ok := PermissionOf(ctx.Operation())
+		if !ok {
+			_ = huma.WriteErr(api, ctx, http.StatusForbidden, ctx.Operation().OperationID+" is not covered by the access policy")
+			return
+		}
+
+		principal, ok := auth.PrincipalFrom(ctx.Context())
+		if !ok {
+			ctx.SetHeader("WWW-Authenticate", challenge)
+			_ = huma.WriteErr(api, ctx, http.StatusUnauthorized, "authentication required")
+			return
+		}
+		if permission != "" && !principal.Can(permission) {
+			_ = huma.WriteErr(api, ctx, http.StatusForbidden, forbidden(ctx.Operation().OperationID, permission),
+				&huma.ErrorDetail{Location: "permission", Value: permission, Message: "required by " + ctx.Operation().OperationID},
+				&huma.ErrorDetail{Location: "roles", Value: roleNames(principal.Roles), Message: "roles of " + principal.Subject},
+			)
+			return
+		}
+
+		next(ctx)
+	}
+}
+
+// RequirePermission protects plain HTTP handlers like Authorize protects API operations
+// It belongs after RequireScope, which authenticates the request
+func RequirePermission(permission access.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.PrincipalFrom(r.Context())
+			if !ok || !principal.Can(permission) {
+				http.Error(w, forbidden(r.URL.Path, permission), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func roleNames(roles []access.Role) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.String()
+	}
+	return names
+}
+
+// forbidden explains which roles would have been allowed
+func forbidden(operation string, permission access.Permission) string {
+	names := roleNames(access.RolesGranting(permission))
+	return fmt.Sprintf("%s requires the %s permission, which only these roles grant: %s", operation, permission, strings.Join(names, ", "))
+}
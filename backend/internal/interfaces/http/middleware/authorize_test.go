@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"parrotflow/internal/domain/access"
+	"parrotflow/internal/infrastructure/auth"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func TestAuthorize(t *testing.T) {
+	_, api := humatest.New(t)
+
+	// Stands in for Authenticate, with the roles named in a header
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		if header := ctx.Header("X-Roles"); header != "" {
+			roles, _ := access.NormalizeRoles(strings.Split(header, ","))
+			principal := &auth.Principal{Subject: "alice", Roles: roles}
+			ctx = huma.WithContext(ctx, auth.WithPrincipal(ctx.Context(), principal))
+		}
+		next(ctx)
+	}, Authorize(api))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "deregister-agent",
+		Method:      http.MethodDelete,
+		Path:        "/agents/{id}",
+		Metadata:    map[string]any{MetadataPermission: access.PermissionAgentsDeregister},
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	if resp := api.Delete("/agents/1", "X-Roles: admin"); resp.Code != http.StatusNoContent {
+		t.Errorf("Admin status = %d, want %d", resp.Code, http.StatusNoContent)
+	}
+
+	resp := api.Delete("/agents/1", "X-Roles: operator,author")
+	if resp.Code != http.StatusForbidden {
+		t.Fatalf("Operator status = %d, want %d", resp.Code, http.StatusForbidden)
+	}
+	body := resp.Body.String()
+	for _, want := range []string{"deregister-agent requires the agents:deregister permission", "only these roles grant: admin", `"operator"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Body = %s, want it to mention %q", body, want)
+		}
+	}
+
+	if resp := api.Delete("/agents/1"); resp.Code != http.StatusUnauthorized {
+		t.Errorf("Anonymous status = %d, want %d", resp.Code, http.StatusUnauthorized)
+	}
+
+	// Operations missing from the policy are refused, unless they authenticate callers themselves
+	huma.Register(api, huma.Operation{
+		OperationID: "get-unlisted",
+		Method:      http.MethodGet,
+		Path:        "/unlisted",
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+	if resp := api.Get("/unlisted", "X-Roles: admin"); resp.Code != http.StatusForbidden {
+		t.Errorf("Unlisted operation status = %d, want %d", resp.Code, http.StatusForbidden)
+	}
+	huma.Register(api, huma.Operation{
+		OperationID: "register-agent",
+		Method:      http.MethodPost,
+		Path:        "/agents",
+		Metadata:    map[string]any{MetadataSelfAuthenticated: true},
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+	if resp := api.Post("/agents", map[string]any{}); resp.Code != http.StatusNoContent {
+		t.Errorf("Self-authenticated operation status = %d, want %d", resp.Code, http.StatusNoContent)
+	}
+}
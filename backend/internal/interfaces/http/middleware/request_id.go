@@ -0,0 +1,81 @@
+// Package middleware holds the chi middleware wrapped around every API route
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"parrotflow/internal/domain/shared"
+	pkgshared "parrotflow/pkg/shared"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// Headers carrying the IDs of a request
+const (
+	HeaderRequestID     = "X-Request-ID"
+	HeaderCorrelationID = "X-Correlation-ID"
+)
+
+// maxIDLength bounds the IDs accepted from clients, they are stored with events
+const maxIDLength = 128
+
+// RequestID gives every request an ID, taken from X-Request-ID when the client sent one
+// The request ID causes whatever the request does; the correlation ID, taken from
+// X-Correlation-ID when the client is part of a larger flow, is carried by every event
+// and message that follows from it. Both are echoed in the response headers
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := clientID(r.Header.Get(HeaderRequestID))
+		if requestID == "" {
+			requestID = pkgshared.CustomUUID()
+		}
+		correlationID := clientID(r.Header.Get(HeaderCorrelationID))
+		if correlationID == "" {
+			correlationID = requestID
+		}
+
+		w.Header().Set(HeaderRequestID, requestID)
+		w.Header().Set(HeaderCorrelationID, correlationID)
+
+		ctx := shared.ContextWithCorrelation(r.Context(), shared.Correlation{
+			CorrelationID: correlationID,
+			CausationID:   requestID,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Logger logs every request once it has been served
+// It must run after RequestID for the records to carry the request's IDs
+func Logger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.Log(r.Context(), level, "Served request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", status,
+			"bytes", ww.BytesWritten(),
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+	})
+}
+
+func clientID(value string) string {
+	if len(value) > maxIDLength {
+		return ""
+	}
+	return value
+}
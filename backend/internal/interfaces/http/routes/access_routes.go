@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"net/http"
+	"parrotflow/internal/domain/apikey"
+	"parrotflow/internal/interfaces/http/handlers"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func RegisterAccessRoutes(api *huma.API, accessHandler *handlers.AccessHandler) {
+	tags := []string{"access"}
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "get-access-policy",
+		Method:      http.MethodGet,
+		Path:        "/api/access/policy",
+		Summary:     "Get the access policy",
+		Description: "List the roles and the permissions each of them grants",
+		Tags:        tags,
+	}, accessHandler.GetPolicy)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "get-current-principal",
+		Method:      http.MethodGet,
+		Path:        "/api/access/me",
+		Summary:     "Get the current principal",
+		Description: "Show who the credentials authenticate as, with their scopes, roles and permissions",
+		Tags:        tags,
+	}, accessHandler.GetCurrentPrincipal)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "list-role-assignments",
+		Method:      http.MethodGet,
+		Path:        "/api/access/roles",
+		Summary:     "List role assignments",
+		Description: "Get the roles assigned to API keys and identity provider subjects",
+		Tags:        tags,
+		Security:    requireScope(apikey.ScopeAdmin),
+	}, accessHandler.ListRoleAssignments)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "assign-roles",
+		Method:      http.MethodPut,
+		Path:        "/api/access/roles/{subject}",
+		Summary:     "Assign roles",
+		Description: "Replace the roles of an API key or identity provider subject",
+		Tags:        tags,
+		Security:    requireScope(apikey.ScopeAdmin),
+	}, accessHandler.AssignRoles)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "unassign-roles",
+		Method:      http.MethodDelete,
+		Path:        "/api/access/roles/{subject}",
+		Summary:     "Unassign roles",
+		Description: "Remove every role of a subject; roles in a JWT's roles claim still apply",
+		Tags:        tags,
+		Security:    requireScope(apikey.ScopeAdmin),
+	}, accessHandler.UnassignRoles)
+}
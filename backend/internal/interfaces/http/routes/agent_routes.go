@@ -13,8 +13,9 @@ func RegisterAgentRoutes(api *huma.API, handler *handlers.AgentHandler) {
 		Method:      "POST",
 		Path:        "/api/agents/",
 		Summary:     "Register a new agent",
-		Description: "Registers a new agent with the system (auto-registration on first heartbeat)",
+		Description: "Registers a new agent in exchange for a one-time enrollment token and returns the credential it signs its heartbeats and progress reports with",
 		Tags:        []string{"agents"},
+		Security:    []map[string][]string{}, // The enrollment token authenticates the agent
 	}, handler.RegisterAgent)
 
 	// Update heartbeat - POST /api/agents/{id}/heartbeat
@@ -23,8 +24,9 @@ func RegisterAgentRoutes(api *huma.API, handler *handlers.AgentHandler) {
 		Method:      "POST",
 		Path:        "/api/agents/{id}/heartbeat",
 		Summary:     "Update agent heartbeat",
-		Description: "Updates the agent's last heartbeat timestamp and auto-recovers from disconnected status",
+		Description: "Updates the agent's last heartbeat timestamp and auto-recovers from disconnected status; the body, `{}` when there is nothing to add, is signed with the agent credential",
 		Tags:        []string{"agents"},
+		Security:    signedByAgent(),
 	}, handler.UpdateHeartbeat)
 
 	// Assign run - POST /api/agents/{id}/assign-run
@@ -106,4 +108,14 @@ func RegisterAgentRoutes(api *huma.API, handler *handlers.AgentHandler) {
 		Description: "Marks the agent as offline and deregisters it from the system",
 		Tags:        []string{"agents"},
 	}, handler.DeregisterAgent)
+
+	// Revoke agent - POST /api/agents/{id}/revoke
+	huma.Register(*api, huma.Operation{
+		OperationID: "revoke-agent",
+		Method:      "POST",
+		Path:        "/api/agents/{id}/revoke",
+		Summary:     "Revoke an agent",
+		Description: "Invalidates the agent credential and deregisters it; the agent needs a new enrollment token to come back",
+		Tags:        []string{"agents"},
+	}, handler.RevokeAgent)
 }
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"net/http"
+	"parrotflow/internal/interfaces/http/handlers"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func RegisterAnalyticsRoutes(api *huma.API, analyticsHandler *handlers.AnalyticsHandler) {
+	tags := []string{"analytics"}
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "get-fleet-analytics",
+		Method:      http.MethodGet,
+		Path:        "/api/analytics/summary",
+		Summary:     "Get fleet-wide run analytics",
+		Description: "Success and failure rates over time, duration percentiles, the most frequent failure reasons, the nodes and the scenarios that fail most often",
+		Tags:        tags,
+	}, analyticsHandler.GetFleetAnalytics)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "get-scenario-analytics",
+		Method:      http.MethodGet,
+		Path:        "/api/analytics/scenarios/{id}",
+		Summary:     "Get the run analytics of a scenario",
+		Description: "Success and failure rates over time, duration percentiles, the most frequent failure reasons and the nodes that fail most often",
+		Tags:        tags,
+	}, analyticsHandler.GetScenarioAnalytics)
+}
@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"net/http"
+	"parrotflow/internal/domain/apikey"
+	"parrotflow/internal/interfaces/http/handlers"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func RegisterAPIKeyRoutes(api *huma.API, apiKeyHandler *handlers.APIKeyHandler) {
+	tags := []string{"api-keys"}
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "create-api-key",
+		Method:      http.MethodPost,
+		Path:        "/api/apikeys/",
+		Summary:     "Create an API key",
+		Description: "Issue a scoped API key; the key is only returned in this response",
+		Tags:        tags,
+		Security:    requireScope(apikey.ScopeAdmin),
+	}, apiKeyHandler.CreateAPIKey)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "list-api-keys",
+		Method:      http.MethodGet,
+		Path:        "/api/apikeys/",
+		Summary:     "List API keys",
+		Description: "Get all API keys, including revoked and expired ones, without their secrets",
+		Tags:        tags,
+		Security:    requireScope(apikey.ScopeAdmin),
+	}, apiKeyHandler.ListAPIKeys)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "revoke-api-key",
+		Method:      http.MethodPost,
+		Path:        "/api/apikeys/{id}/revoke",
+		Summary:     "Revoke an API key",
+		Description: "Stop accepting an API key; revoked keys are kept for reference",
+		Tags:        tags,
+		Security:    requireScope(apikey.ScopeAdmin),
+	}, apiKeyHandler.RevokeAPIKey)
+}
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"net/http"
+
+	"parrotflow/internal/domain/audit"
+	"parrotflow/internal/interfaces/http/middleware"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// RecordAudit makes every POST, PUT, PATCH and DELETE operation registered after it
+// record its calls in the audit log; it must come before RequireAuthentication so
+// calls that are denied are recorded as well
+// Heartbeats and progress reports that agents sign are telemetry rather than changes
+// and are left out, what they change is in the event log
+func RecordAudit(api *huma.API, repository audit.Repository) {
+	oapi := (*api).OpenAPI()
+	oapi.OnAddOperation = append(oapi.OnAddOperation, func(oapi *huma.OpenAPI, op *huma.Operation) {
+		switch op.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			return
+		}
+		if isSignedByAgent(op) {
+			return
+		}
+		if op.Metadata == nil {
+			op.Metadata = map[string]any{}
+		}
+		op.Metadata[middleware.MetadataAudited] = true
+	})
+
+	(*api).UseMiddleware(middleware.Audit(repository))
+}
+
+// redactFields is the metadata of operations whose request carries sensitive fields
+// the audit log does not redact by name, such as a secret's value
+func redactFields(fields ...string) map[string]any {
+	return map[string]any{middleware.MetadataRedactedFields: fields}
+}
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"net/http"
+	"parrotflow/internal/interfaces/http/handlers"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func RegisterAuditRoutes(api *huma.API, auditHandler *handlers.AuditHandler) {
+	tags := []string{"audit"}
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "list-audit-entries",
+		Method:      http.MethodGet,
+		Path:        "/api/audit",
+		Summary:     "List audit entries",
+		Description: "Search the record of every call that changed something, or tried to, by actor, operation, resource, outcome and time, newest first",
+		Tags:        tags,
+	}, auditHandler.ListAuditEntries)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "export-audit-entries",
+		Method:      http.MethodGet,
+		Path:        "/api/audit/export",
+		Summary:     "Export audit entries",
+		Description: "Download every audit entry matching the filters as CSV or JSON lines",
+		Tags:        tags,
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Audit entries",
+				Content: map[string]*huma.MediaType{
+					"text/csv":          {Schema: &huma.Schema{Type: huma.TypeString}},
+					"application/jsonl": {Schema: &huma.Schema{Type: huma.TypeString}},
+				},
+			},
+		},
+	}, auditHandler.ExportAuditEntries)
+}
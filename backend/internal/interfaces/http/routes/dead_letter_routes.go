@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"net/http"
+	"parrotflow/internal/interfaces/http/handlers"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func RegisterDeadLetterRoutes(api *huma.API, deadLetterHandler *handlers.DeadLetterHandler) {
+	tags := []string{"dead-letters"}
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "list-dead-letters",
+		Method:      http.MethodGet,
+		Path:        "/api/dead-letters/",
+		Summary:     "List dead-lettered messages",
+		Description: "Get the broker messages agents or the backend rejected, newest first",
+		Tags:        tags,
+	}, deadLetterHandler.ListDeadLetters)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "get-dead-letter",
+		Method:      http.MethodGet,
+		Path:        "/api/dead-letters/{id}",
+		Summary:     "Get a dead-lettered message",
+		Description: "Inspect a dead-lettered message, including its headers and payload",
+		Tags:        tags,
+	}, deadLetterHandler.GetDeadLetter)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "replay-dead-letter",
+		Method:      http.MethodPost,
+		Path:        "/api/dead-letters/{id}/replay",
+		Summary:     "Replay a dead-lettered message",
+		Description: "Publish the message to the queue it was rejected from again",
+		Tags:        tags,
+	}, deadLetterHandler.ReplayDeadLetter)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "discard-dead-letter",
+		Method:      http.MethodPost,
+		Path:        "/api/dead-letters/{id}/discard",
+		Summary:     "Discard a dead-lettered message",
+		Description: "Drop the message for good; it stays listed with the discarded status",
+		Tags:        tags,
+	}, deadLetterHandler.DiscardDeadLetter)
+}
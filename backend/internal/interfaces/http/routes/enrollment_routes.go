@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"net/http"
+	"parrotflow/internal/domain/apikey"
+	"parrotflow/internal/interfaces/http/handlers"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func RegisterEnrollmentRoutes(api *huma.API, enrollmentHandler *handlers.EnrollmentHandler) {
+	tags := []string{"enrollment"}
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "create-enrollment-token",
+		Method:      http.MethodPost,
+		Path:        "/api/enrollment-tokens/",
+		Summary:     "Create an enrollment token",
+		Description: "Mint a one-time token an agent registers with; the token is only returned in this response",
+		Tags:        tags,
+		Security:    requireScope(apikey.ScopeAdmin),
+	}, enrollmentHandler.CreateEnrollmentToken)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "list-enrollment-tokens",
+		Method:      http.MethodGet,
+		Path:        "/api/enrollment-tokens/",
+		Summary:     "List enrollment tokens",
+		Description: "Get all enrollment tokens, including used, revoked and expired ones, without their secrets",
+		Tags:        tags,
+		Security:    requireScope(apikey.ScopeAdmin),
+	}, enrollmentHandler.ListEnrollmentTokens)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "revoke-enrollment-token",
+		Method:      http.MethodPost,
+		Path:        "/api/enrollment-tokens/{id}/revoke",
+		Summary:     "Revoke an enrollment token",
+		Description: "Stop accepting an unused enrollment token; revoke the agent instead once it registered",
+		Tags:        tags,
+		Security:    requireScope(apikey.ScopeAdmin),
+	}, enrollmentHandler.RevokeEnrollmentToken)
+}
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"net/http"
+	"parrotflow/internal/domain/eventlog"
+	"parrotflow/internal/interfaces/http/handlers"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func RegisterEventRoutes(api *huma.API, eventHandler *handlers.EventHandler) {
+	huma.Register(*api, huma.Operation{
+		OperationID: "list-events",
+		Method:      http.MethodGet,
+		Path:        "/api/events",
+		Summary:     "List domain events",
+		Description: "Search the event log by event type, aggregate and time, newest first",
+		Tags:        []string{"events"},
+	}, eventHandler.ListEvents)
+
+	histories := []struct {
+		aggregateType string
+		resource      string
+	}{
+		{eventlog.AggregateAgent, "agents"},
+		{eventlog.AggregateProxy, "proxies"},
+		{eventlog.AggregateRun, "runs"},
+		{eventlog.AggregateScenario, "scenarios"},
+	}
+	for _, history := range histories {
+		huma.Register(*api, huma.Operation{
+			OperationID: "get-" + history.aggregateType + "-history",
+			Method:      http.MethodGet,
+			Path:        "/api/" + history.resource + "/{id}/history",
+			Summary:     "Get the history of a " + history.aggregateType,
+			Description: "Get the events raised by a " + history.aggregateType + " in the order they occurred, including after it was deleted",
+			Tags:        []string{history.resource, "events"},
+		}, eventHandler.History(history.aggregateType))
+	}
+}
@@ -0,0 +1,155 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"parrotflow/internal/domain/access"
+	"parrotflow/internal/infrastructure/auth"
+	"parrotflow/internal/infrastructure/realtime"
+	"parrotflow/internal/interfaces/http/middleware"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// authenticatedOnly marks operations any authenticated caller may use, whatever its roles
+const authenticatedOnly access.Permission = ""
+
+// operationPermissions is the policy table of the API: the permission each operation
+// requires; access.Role.Grants tells which roles have it
+// Reading operations missing here require access.PermissionRead; every other operation
+// must be listed, registering one that is not panics
+// Operations on the public allowlist need no permission and are not listed
+var operationPermissions = map[string]access.Permission{
+	// Agents
+	"assign-run-to-agent":     access.PermissionAgentsOperate,
+	"release-run-from-agent":  access.PermissionAgentsOperate,
+	"update-agent":            access.PermissionAgentsOperate,
+	"deregister-agent":        access.PermissionAgentsDeregister,
+	"revoke-agent":            access.PermissionAgentsDeregister,
+	"create-enrollment-token": access.PermissionAgentsEnroll,
+	"list-enrollment-tokens":  access.PermissionAgentsEnroll,
+	"revoke-enrollment-token": access.PermissionAgentsEnroll,
+
+	// Proxies
+	"create-proxy":          access.PermissionProxiesEdit,
+	"update-proxy":          access.PermissionProxiesEdit,
+	"delete-proxy":          access.PermissionProxiesEdit,
+	"restore-proxy":         access.PermissionProxiesEdit,
+	"record-proxy-health":   access.PermissionProxiesOperate,
+	"activate-proxy":        access.PermissionProxiesOperate,
+	"deactivate-proxy":      access.PermissionProxiesOperate,
+	"get-proxy-credentials": access.PermissionProxiesCredentials,
+
+	// Secrets
+	"create-secret": access.PermissionSecretsEdit,
+	"update-secret": access.PermissionSecretsEdit,
+	"delete-secret": access.PermissionSecretsEdit,
+
+	// Tags
+	"create-tag":  access.PermissionTagsEdit,
+	"update-tag":  access.PermissionTagsEdit,
+	"delete-tag":  access.PermissionTagsEdit,
+	"restore-tag": access.PermissionTagsEdit,
+
+	// Scenarios
+	"create-scenario":          access.PermissionScenariosEdit,
+	"update-scenario":          access.PermissionScenariosEdit,
+	"delete-scenario":          access.PermissionScenariosEdit,
+	"restore-scenario":         access.PermissionScenariosEdit,
+	"set-scenario-retention":   access.PermissionScenariosEdit,
+	"clear-scenario-retention": access.PermissionScenariosEdit,
+
+	// Runs
+	"create-run": access.PermissionRunsExecute,
+	"start-run":  access.PermissionRunsExecute,
+	"cancel-run": access.PermissionRunsExecute,
+
+	// Webhooks
+	"create-webhook":          access.PermissionWebhooksManage,
+	"get-webhook":             access.PermissionWebhooksManage,
+	"list-webhooks":           access.PermissionWebhooksManage,
+	"update-webhook":          access.PermissionWebhooksManage,
+	"delete-webhook":          access.PermissionWebhooksManage,
+	"enable-webhook":          access.PermissionWebhooksManage,
+	"list-webhook-deliveries": access.PermissionWebhooksManage,
+
+	// Dead letters, their payloads can carry resolved secrets
+	"list-dead-letters":   access.PermissionDeadLettersManage,
+	"get-dead-letter":     access.PermissionDeadLettersManage,
+	"replay-dead-letter":  access.PermissionDeadLettersManage,
+	"discard-dead-letter": access.PermissionDeadLettersManage,
+
+	// API keys and roles
+	"create-api-key":        access.PermissionAccessManage,
+	"list-api-keys":         access.PermissionAccessManage,
+	"revoke-api-key":        access.PermissionAccessManage,
+	"list-role-assignments": access.PermissionAccessManage,
+	"assign-roles":          access.PermissionAccessManage,
+	"unassign-roles":        access.PermissionAccessManage,
+	"get-current-principal": authenticatedOnly,
+
+	// Audit log
+	"list-audit-entries":   access.PermissionAuditRead,
+	"export-audit-entries": access.PermissionAuditRead,
+}
+
+// requirePermission stores the permission of an operation where middleware.Authorize
+// finds it and mentions it in the operation's description
+func requirePermission(op *huma.Operation) {
+	permission, ok := operationPermissions[op.OperationID]
+	if !ok {
+		if op.Method != http.MethodGet {
+			panic(fmt.Sprintf("operation %s is missing from the permission table", op.OperationID))
+		}
+		permission = access.PermissionRead
+	}
+
+	if op.Metadata == nil {
+		op.Metadata = map[string]any{}
+	}
+	op.Metadata[middleware.MetadataPermission] = permission
+	if permission == authenticatedOnly {
+		return
+	}
+
+	roles := access.RolesGranting(permission)
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.String()
+	}
+	note := fmt.Sprintf("Requires the `%s` permission (%s).", permission, strings.Join(names, ", "))
+	if op.Description == "" {
+		op.Description = note
+	} else {
+		op.Description += "\n\n" + note
+	}
+}
+
+// topicPermissions is the permission subscribing to each WebSocket topic requires,
+// keyed by the topic up to its ID, e.g. runs: for runs:12
+var topicPermissions = map[string]access.Permission{
+	realtime.AgentsTopic:           access.PermissionRead,
+	realtime.ProxiesTopic:          access.PermissionRead,
+	realtime.ScenarioRunsTopic(""): access.PermissionRead,
+}
+
+// AuthorizeTopic is the ws.AuthHooks Authorize hook checking subscriptions against
+// topicPermissions; a topic missing there is refused
+func AuthorizeTopic(r *http.Request, topic string) error {
+	kind, _, found := strings.Cut(topic, ":")
+	if found {
+		kind += ":"
+	}
+	permission, ok := topicPermissions[kind]
+	if !ok {
+		return fmt.Errorf("topic %s has no permission assigned", topic)
+	}
+
+	principal, ok := auth.PrincipalFrom(r.Context())
+	if !ok || !principal.Can(permission) {
+		return fmt.Errorf("subscribing to %s requires the %s permission", topic, permission)
+	}
+	return nil
+}
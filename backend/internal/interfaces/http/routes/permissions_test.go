@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"testing"
+
+	"parrotflow/internal/container"
+	"parrotflow/internal/interfaces/http/middleware"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func TestEveryOperationHasPermission(t *testing.T) {
+	_, testAPI := humatest.New(t)
+	api := huma.API(testAPI)
+	RegisterAllRoutes(&api, &container.Application{SecretsEnabled: true})
+
+	for path, item := range api.OpenAPI().Paths {
+		for _, op := range []*huma.Operation{item.Get, item.Put, item.Post, item.Delete, item.Patch} {
+			if op == nil || publicOperations[op.OperationID] {
+				continue
+			}
+			if _, ok := middleware.PermissionOf(op); !ok {
+				t.Errorf("Operation %s (%s %s) has no permission", op.OperationID, op.Method, path)
+			}
+		}
+	}
+}
+
+func TestSecretRoutesNeedEncryptionKey(t *testing.T) {
+	_, testAPI := humatest.New(t)
+	api := huma.API(testAPI)
+	RegisterAllRoutes(&api, &container.Application{})
+
+	if _, ok := api.OpenAPI().Paths["/api/secrets/"]; ok {
+		t.Error("Secret routes registered without an encryption key")
+	}
+}
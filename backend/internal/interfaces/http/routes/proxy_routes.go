@@ -30,6 +30,16 @@ func RegisterProxyRoutes(api *huma.API, handler *handlers.ProxyHandler) {
 		Tags:        []string{"proxies"},
 	}, handler.GetProxy)
 
+	// GET /api/proxies/{id}/credentials - Get the credentials of a proxy
+	huma.Register(*api, huma.Operation{
+		OperationID: "get-proxy-credentials",
+		Method:      "GET",
+		Path:        "/api/proxies/{id}/credentials",
+		Summary:     "Get proxy credentials",
+		Description: "Retrieves the username, password and unmasked connection URL of a proxy",
+		Tags:        []string{"proxies"},
+	}, handler.GetProxyCredentials)
+
 	// GET /api/proxies/ - List all proxies with optional filters
 	huma.Register(*api, huma.Operation{
 		OperationID: "list-proxies",
@@ -60,16 +70,26 @@ func RegisterProxyRoutes(api *huma.API, handler *handlers.ProxyHandler) {
 		Tags:        []string{"proxies"},
 	}, handler.UpdateProxy)
 
-	// DELETE /api/proxies/{id} - Delete a proxy
+	// DELETE /api/proxies/{id} - Move a proxy to the trash
 	huma.Register(*api, huma.Operation{
 		OperationID: "delete-proxy",
 		Method:      "DELETE",
 		Path:        "/api/proxies/{id}",
 		Summary:     "Delete proxy",
-		Description: "Deletes a proxy configuration",
+		Description: "Moves a proxy configuration to the trash; it is purged after the retention period",
 		Tags:        []string{"proxies"},
 	}, handler.DeleteProxy)
 
+	// POST /api/proxies/{id}/restore - Restore proxy from the trash
+	huma.Register(*api, huma.Operation{
+		OperationID: "restore-proxy",
+		Method:      "POST",
+		Path:        "/api/proxies/{id}/restore",
+		Summary:     "Restore proxy",
+		Description: "Takes a proxy back out of the trash",
+		Tags:        []string{"proxies"},
+	}, handler.RestoreProxy)
+
 	// POST /api/proxies/{id}/health - Record health check result
 	huma.Register(*api, huma.Operation{
 		OperationID: "record-proxy-health",
@@ -7,8 +7,17 @@ import (
 
 // RegisterAllRoutes registers all API routes with the given application
 func RegisterAllRoutes(api *huma.API, app *container.Application) {
+	// Middleware applies to the operations registered after it
+	(*api).UseMiddleware(app.Metrics.Middleware)
+
 	// System routes
-	RegisterSystemRoutes(api)
+	RegisterSystemRoutes(api, app.HealthHandler)
+
+	// Changes made through operations registered from here on are audited
+	RecordAudit(api, app.AuditLog)
+
+	// Every operation registered from here on requires credentials
+	RequireAuthentication(api, app.Authenticator)
 
 	// Domain routes
 	RegisterAgentRoutes(api, app.AgentHandler)
@@ -16,4 +25,15 @@ func RegisterAllRoutes(api *huma.API, app *container.Application) {
 	RegisterTagRoutes(api, app.TagHandler)
 	RegisterScenarioRoutes(api, app.ScenarioHandler)
 	RegisterRunRoutes(api, app.RunHandler)
+	RegisterWebhookRoutes(api, app.WebhookHandler)
+	if app.SecretsEnabled {
+		RegisterSecretRoutes(api, app.SecretHandler)
+	}
+	RegisterEventRoutes(api, app.EventHandler)
+	RegisterDeadLetterRoutes(api, app.DeadLetterHandler)
+	RegisterAnalyticsRoutes(api, app.AnalyticsHandler)
+	RegisterAPIKeyRoutes(api, app.APIKeyHandler)
+	RegisterEnrollmentRoutes(api, app.EnrollmentHandler)
+	RegisterAccessRoutes(api, app.AccessHandler)
+	RegisterAuditRoutes(api, app.AuditHandler)
 }
@@ -2,9 +2,11 @@ package routes
 
 import (
 	"net/http"
+	"parrotflow/internal/interfaces/http/dto/queries"
 	"parrotflow/internal/interfaces/http/handlers"
 
 	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/sse"
 )
 
 var (
@@ -49,4 +51,37 @@ func RegisterRunRoutes(api *huma.API, runHandler *handlers.RunHandler) {
 		Description: "Start execution of a run",
 		Tags:        apiTag,
 	}, runHandler.StartRun)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "cancel-run",
+		Method:      http.MethodPost,
+		Path:        "/api/runs/{id}/cancel",
+		Summary:     "Cancel a run",
+		Description: "Stop a pending or running run; cancelling a cancelled run changes nothing",
+		Tags:        apiTag,
+	}, runHandler.CancelRun)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "report-run-progress",
+		Method:      http.MethodPost,
+		Path:        "/api/runs/{id}/progress",
+		Summary:     "Report run progress",
+		Description: "Report the execution status of a scenario node in a running run; signed with the credential of the reporting agent",
+		Tags:        apiTag,
+		Security:    signedByAgent(),
+	}, runHandler.ReportRunProgress)
+
+	sse.Register(*api, huma.Operation{
+		OperationID: "stream-run-events",
+		Method:      http.MethodGet,
+		Path:        "/api/runs/{id}/events",
+		Summary:     "Stream run events",
+		Description: "Server-Sent Events stream of run state changes and node progress, resumable with Last-Event-ID",
+		Tags:        apiTag,
+	}, map[string]any{
+		"status":    queries.RunStatusEvent{},
+		"progress":  queries.RunProgressEvent{},
+		"heartbeat": queries.StreamHeartbeatEvent{},
+		"error":     queries.StreamErrorEvent{},
+	}, runHandler.StreamRunEvents)
 }
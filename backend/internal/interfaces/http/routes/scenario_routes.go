@@ -48,7 +48,34 @@ func RegisterScenarioRoutes(api *huma.API, scenarioHandler *handlers.ScenarioHan
 		Method:      "DELETE",
 		Path:        "/api/scenarios/{id}",
 		Summary:     "Delete a scenario",
-		Description: "Delete a scenario by its ID",
+		Description: "Move a scenario to the trash; its runs are hidden until it is restored",
 		Tags:        []string{"scenarios"},
 	}, scenarioHandler.DeleteScenario)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "restore-scenario",
+		Method:      "POST",
+		Path:        "/api/scenarios/{id}/restore",
+		Summary:     "Restore a scenario",
+		Description: "Take a scenario back out of the trash, along with its run history",
+		Tags:        []string{"scenarios"},
+	}, scenarioHandler.RestoreScenario)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "set-scenario-retention",
+		Method:      "PUT",
+		Path:        "/api/scenarios/{id}/retention",
+		Summary:     "Set run retention for a scenario",
+		Description: "Override the global run retention policy for this scenario",
+		Tags:        []string{"scenarios"},
+	}, scenarioHandler.SetScenarioRetention)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "clear-scenario-retention",
+		Method:      "DELETE",
+		Path:        "/api/scenarios/{id}/retention",
+		Summary:     "Clear run retention for a scenario",
+		Description: "Remove the scenario override so the global run retention policy applies",
+		Tags:        []string{"scenarios"},
+	}, scenarioHandler.ClearScenarioRetention)
 }
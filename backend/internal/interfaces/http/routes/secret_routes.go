@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"net/http"
+	"parrotflow/internal/interfaces/http/handlers"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func RegisterSecretRoutes(api *huma.API, secretHandler *handlers.SecretHandler) {
+	tags := []string{"secrets"}
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "create-secret",
+		Method:      http.MethodPost,
+		Path:        "/api/secrets/",
+		Summary:     "Create a secret",
+		Description: "Store an encrypted value scenario parameters reference as ${secret:NAME}; it is resolved only in the execution request sent to agents and never returned",
+		Tags:        tags,
+		Metadata:    redactFields("value"),
+	}, secretHandler.CreateSecret)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "get-secret",
+		Method:      http.MethodGet,
+		Path:        "/api/secrets/{id}",
+		Summary:     "Get a secret by ID",
+		Description: "Retrieve a secret's name and description, without its value",
+		Tags:        tags,
+	}, secretHandler.GetSecret)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "list-secrets",
+		Method:      http.MethodGet,
+		Path:        "/api/secrets/",
+		Summary:     "List secrets",
+		Description: "Get all secrets ordered by name, without their values",
+		Tags:        tags,
+	}, secretHandler.ListSecrets)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "update-secret",
+		Method:      http.MethodPatch,
+		Path:        "/api/secrets/{id}",
+		Summary:     "Update a secret",
+		Description: "Replace the value or description of a secret; runs already started keep the value they were sent",
+		Tags:        tags,
+		Metadata:    redactFields("value"),
+	}, secretHandler.UpdateSecret)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "delete-secret",
+		Method:      http.MethodDelete,
+		Path:        "/api/secrets/{id}",
+		Summary:     "Delete a secret",
+		Description: "Permanently delete a secret; runs of scenarios still referencing it cannot be started",
+		Tags:        tags,
+	}, secretHandler.DeleteSecret)
+}
@@ -0,0 +1,143 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"parrotflow/internal/domain/apikey"
+	"parrotflow/internal/infrastructure/auth"
+	"parrotflow/internal/interfaces/http/middleware"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Security schemes documented in the OpenAPI description
+const (
+	apiKeyScheme         = "apiKey"
+	bearerScheme         = "bearer"
+	agentSignatureScheme = "agentSignature"
+)
+
+// publicOperations is the allowlist of operations callable without credentials: the
+// system endpoints registered before RequireAuthentication, and those whose callers
+// authenticate themselves with an enrollment token or their agent signature
+// Any other operation declaring an empty or signedByAgent requirement panics on registration
+var publicOperations = map[string]bool{
+	"root":                   true,
+	"health":                 true,
+	"health-live":            true,
+	"health-ready":           true,
+	"register-agent":         true,
+	"update-agent-heartbeat": true,
+	"report-run-progress":    true,
+}
+
+// RequireAuthentication documents the security schemes and makes every operation
+// registered after it require credentials: the read scope for GET operations and the
+// write scope for the others, unless the operation declares requireScope itself, and
+// a role granting the operation's permission from operationPermissions
+// Operations on the public allowlist declaring an empty requirement stay public and those
+// declaring signedByAgent are left to their handlers, which verify the agent's signature
+// Everything else is denied unless authenticated and granted its permission, including
+// hidden operations, which this hook never sees
+func RequireAuthentication(api *huma.API, authenticator *auth.Authenticator) {
+	oapi := (*api).OpenAPI()
+	if oapi.Components.SecuritySchemes == nil {
+		oapi.Components.SecuritySchemes = map[string]*huma.SecurityScheme{}
+	}
+	oapi.Components.SecuritySchemes[apiKeyScheme] = &huma.SecurityScheme{
+		Type:        "apiKey",
+		In:          "header",
+		Name:        middleware.HeaderAPIKey,
+		Description: "API key created with `parrotflow apikey create`; it is accepted as a bearer token as well",
+	}
+	oapi.Components.SecuritySchemes[bearerScheme] = &huma.SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+		Description:  "JWT of the configured identity provider, with scopes in the scope or scp claim",
+	}
+	oapi.Components.SecuritySchemes[agentSignatureScheme] = &huma.SecurityScheme{
+		Type: "apiKey",
+		In:   "header",
+		Name: "X-Parrotflow-Signature",
+		Description: "HMAC-SHA256 of the request with the credential an agent got when it registered with an enrollment token, " +
+			"together with the X-Parrotflow-Timestamp and X-Parrotflow-Nonce headers; a nonce is accepted once. See the operations for the signed message",
+	}
+
+	oapi.OnAddOperation = append(oapi.OnAddOperation, func(oapi *huma.OpenAPI, op *huma.Operation) {
+		if op.Security == nil {
+			op.Security = requireScope(defaultScope(op.Method))
+		}
+		if len(op.Security) == 0 || isSignedByAgent(op) {
+			if !publicOperations[op.OperationID] {
+				panic(fmt.Sprintf("operation %s skips authentication but is not on the public allowlist", op.OperationID))
+			}
+			if op.Metadata == nil {
+				op.Metadata = map[string]any{}
+			}
+			op.Metadata[middleware.MetadataSelfAuthenticated] = true
+			documentErrors(op, http.StatusUnauthorized)
+			return
+		}
+		requirePermission(op)
+		documentErrors(op, http.StatusUnauthorized, http.StatusForbidden)
+	})
+
+	(*api).UseMiddleware(middleware.Authenticate(*api, authenticator), middleware.Authorize(*api))
+}
+
+// requireScope is the security requirement of operations needing a scope
+func requireScope(scope string) []map[string][]string {
+	return []map[string][]string{
+		{apiKeyScheme: {scope}},
+		{bearerScheme: {scope}},
+	}
+}
+
+// signedByAgent is the security requirement of operations agents call with requests
+// signed by their credential instead of API keys
+func signedByAgent() []map[string][]string {
+	return []map[string][]string{{agentSignatureScheme: {}}}
+}
+
+func isSignedByAgent(op *huma.Operation) bool {
+	for _, requirement := range op.Security {
+		if _, ok := requirement[agentSignatureScheme]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func defaultScope(method string) string {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return apikey.ScopeRead
+	default:
+		return apikey.ScopeWrite
+	}
+}
+
+// documentErrors adds error responses shaped like those huma already declared
+func documentErrors(op *huma.Operation, statuses ...int) {
+	var template *huma.Response
+	for code, response := range op.Responses {
+		if code == "default" || strings.HasPrefix(code, "4") || strings.HasPrefix(code, "5") {
+			template = response
+			break
+		}
+	}
+	if template == nil {
+		return
+	}
+
+	for _, status := range statuses {
+		code := strconv.Itoa(status)
+		if _, ok := op.Responses[code]; !ok {
+			op.Responses[code] = &huma.Response{Description: http.StatusText(status), Content: template.Content}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"parrotflow/internal/infrastructure/auth"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func TestRequireAuthentication_FailsClosed(t *testing.T) {
+	_, testAPI := humatest.New(t)
+	api := huma.API(testAPI)
+	authenticator, err := auth.NewAuthenticator(context.Background(), auth.DefaultConfig(), nil, nil)
+	if err != nil {
+		t.Fatalf("NewAuthenticator() error = %v", err)
+	}
+	RequireAuthentication(&api, authenticator)
+
+	// Hidden operations never get security requirements, they still need credentials
+	huma.Register(api, huma.Operation{
+		OperationID: "get-hidden",
+		Method:      http.MethodGet,
+		Path:        "/hidden",
+		Hidden:      true,
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+	if resp := testAPI.Get("/hidden"); resp.Code != http.StatusUnauthorized {
+		t.Errorf("Hidden operation status = %d, want %d", resp.Code, http.StatusUnauthorized)
+	}
+
+	// Opting out of authentication takes a place on the allowlist
+	defer func() {
+		if recover() == nil {
+			t.Error("Registering a public operation missing from the allowlist did not panic")
+		}
+	}()
+	huma.Register(api, huma.Operation{
+		OperationID: "get-unlisted",
+		Method:      http.MethodGet,
+		Path:        "/unlisted",
+		Security:    []map[string][]string{},
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+}
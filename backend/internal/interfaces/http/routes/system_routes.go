@@ -2,6 +2,9 @@ package routes
 
 import (
 	"context"
+	"net/http"
+
+	"parrotflow/internal/interfaces/http/handlers"
 
 	"github.com/danielgtaylor/huma/v2"
 )
@@ -12,7 +15,7 @@ type GreetingOutput struct {
 	}
 }
 
-func RegisterSystemRoutes(api *huma.API) {
+func RegisterSystemRoutes(api *huma.API, healthHandler *handlers.HealthHandler) {
 	huma.Register(*api, huma.Operation{
 		OperationID: "root",
 		Method:      "GET",
@@ -28,14 +31,28 @@ func RegisterSystemRoutes(api *huma.API) {
 
 	huma.Register(*api, huma.Operation{
 		OperationID: "health",
-		Method:      "GET",
+		Method:      http.MethodGet,
 		Path:        "/health",
 		Summary:     "Health Check",
-		Description: "Check the health status of the API",
+		Description: "Same as /health/live, kept for existing health checks",
 		Tags:        []string{"system"},
-	}, func(ctx context.Context, i *struct{}) (*GreetingOutput, error) {
-		resp := &GreetingOutput{}
-		resp.Body.Message = "OK"
-		return resp, nil
-	})
+	}, healthHandler.Live)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "health-live",
+		Method:      http.MethodGet,
+		Path:        "/health/live",
+		Summary:     "Liveness check",
+		Description: "Answers while the process serves requests, without probing its dependencies",
+		Tags:        []string{"system"},
+	}, healthHandler.Live)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "health-ready",
+		Method:      http.MethodGet,
+		Path:        "/health/ready",
+		Summary:     "Readiness check",
+		Description: "Probes the database, schema, broker, event backlog and agents; answers 503 while a critical component is down",
+		Tags:        []string{"system"},
+	}, healthHandler.Ready)
 }
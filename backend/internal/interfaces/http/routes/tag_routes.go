@@ -48,7 +48,16 @@ func RegisterTagRoutes(api *huma.API, tagHandler *handlers.TagHandler) {
 		Method:      "DELETE",
 		Path:        "/api/tags/{id}",
 		Summary:     "Delete a tag",
-		Description: "Delete a tag by its ID (system tags cannot be deleted)",
+		Description: "Move a tag to the trash by its ID (system tags cannot be deleted)",
 		Tags:        []string{"tags"},
 	}, tagHandler.DeleteTag)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "restore-tag",
+		Method:      "POST",
+		Path:        "/api/tags/{id}/restore",
+		Summary:     "Restore a tag",
+		Description: "Take a tag back out of the trash",
+		Tags:        []string{"tags"},
+	}, tagHandler.RestoreTag)
 }
@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"net/http"
+	"parrotflow/internal/interfaces/http/handlers"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func RegisterWebhookRoutes(api *huma.API, webhookHandler *handlers.WebhookHandler) {
+	tags := []string{"webhooks"}
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "create-webhook",
+		Method:      http.MethodPost,
+		Path:        "/api/webhooks/",
+		Summary:     "Create a webhook",
+		Description: "Subscribe an HTTP endpoint to domain events; payloads are signed with HMAC-SHA256 in the X-Parrotflow-Signature header",
+		Tags:        tags,
+	}, webhookHandler.CreateWebhook)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "get-webhook",
+		Method:      http.MethodGet,
+		Path:        "/api/webhooks/{id}",
+		Summary:     "Get a webhook by ID",
+		Description: "Retrieve a specific webhook by its ID",
+		Tags:        tags,
+	}, webhookHandler.GetWebhook)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "list-webhooks",
+		Method:      http.MethodGet,
+		Path:        "/api/webhooks/",
+		Summary:     "List webhooks",
+		Description: "Get all webhooks, including disabled ones",
+		Tags:        tags,
+	}, webhookHandler.ListWebhooks)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "update-webhook",
+		Method:      http.MethodPatch,
+		Path:        "/api/webhooks/{id}",
+		Summary:     "Update a webhook",
+		Description: "Change the URL, subscribed event types, secret or description of a webhook",
+		Tags:        tags,
+	}, webhookHandler.UpdateWebhook)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "delete-webhook",
+		Method:      http.MethodDelete,
+		Path:        "/api/webhooks/{id}",
+		Summary:     "Delete a webhook",
+		Description: "Permanently delete a webhook and its delivery log",
+		Tags:        tags,
+	}, webhookHandler.DeleteWebhook)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "enable-webhook",
+		Method:      http.MethodPost,
+		Path:        "/api/webhooks/{id}/enable",
+		Summary:     "Enable a webhook",
+		Description: "Re-enable a webhook that was disabled after repeated failed deliveries",
+		Tags:        tags,
+	}, webhookHandler.EnableWebhook)
+
+	huma.Register(*api, huma.Operation{
+		OperationID: "list-webhook-deliveries",
+		Method:      http.MethodGet,
+		Path:        "/api/webhooks/{id}/deliveries",
+		Summary:     "List webhook deliveries",
+		Description: "Get the delivery log of a webhook, newest first",
+		Tags:        tags,
+	}, webhookHandler.ListWebhookDeliveries)
+}
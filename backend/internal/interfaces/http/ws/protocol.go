@@ -0,0 +1,106 @@
+package ws
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/realtime"
+)
+
+// Subprotocol is the WebSocket protocol of the channel
+// Browsers offer it alongside the access token protocol, e.g.
+// Sec-WebSocket-Protocol: parrotflow.v1, access_token.<token>
+// and the server selects it, so the token is never echoed back
+const Subprotocol = "parrotflow.v1"
+
+// Client actions
+const (
+	ActionSubscribe   = "subscribe"
+	ActionUnsubscribe = "unsubscribe"
+)
+
+// Server message types
+const (
+	TypeSubscribed   = "subscribed"
+	TypeUnsubscribed = "unsubscribed"
+	TypeEvent        = "event"
+	TypeError        = "error"
+)
+
+// ClientMessage is sent by clients to manage their subscriptions, e.g.
+// {"action":"subscribe","topic":"agents","filter":{"aggregate_ids":["42"]}}
+type ClientMessage struct {
+	Action  string `json:"action"`
+	Topic   string `json:"topic"`
+	Filter  Filter `json:"filter,omitempty"`
+	AfterID uint64 `json:"after_id,omitempty"` // Replay buffered events after this ID, e.g. after being dropped
+}
+
+// Filter narrows a subscription on the server; an empty list matches everything
+type Filter struct {
+	EventTypes   []string `json:"event_types,omitempty"`
+	AggregateIDs []string `json:"aggregate_ids,omitempty"`
+}
+
+func (f Filter) Matches(event shared.DomainEvent) bool {
+	return matchesAny(f.EventTypes, event.EventType()) && matchesAny(f.AggregateIDs, event.AggregateID())
+}
+
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ServerMessage is pushed to clients: subscription acknowledgements, events and errors
+type ServerMessage struct {
+	Type        string     `json:"type"`
+	Topic       string     `json:"topic,omitempty"`
+	ID          uint64     `json:"id,omitempty"`
+	EventType   string     `json:"event_type,omitempty"`
+	AggregateID string     `json:"aggregate_id,omitempty"`
+	OccurredAt  *time.Time `json:"occurred_at,omitempty"`
+	Data        any        `json:"data,omitempty"`
+	Message     string     `json:"message,omitempty"`
+
+	// Set on the acknowledgement when events after after_id are no longer buffered,
+	// e.g. after a restart: nothing is replayed and the client reloads the state it shows
+	Resync bool `json:"resync,omitempty"`
+}
+
+func eventMessage(msg realtime.Message) ServerMessage {
+	occurredAt := msg.Event.OccurredAt()
+	return ServerMessage{
+		Type:        TypeEvent,
+		Topic:       msg.Topic,
+		ID:          msg.ID,
+		EventType:   msg.Event.EventType(),
+		AggregateID: msg.Event.AggregateID(),
+		OccurredAt:  &occurredAt,
+		Data:        msg.Event,
+	}
+}
+
+func errorMessage(topic, format string, args ...any) ServerMessage {
+	return ServerMessage{Type: TypeError, Topic: topic, Message: fmt.Sprintf(format, args...)}
+}
+
+// validateTopic accepts the fleet topics and per-scenario run topics, e.g. runs:12
+func validateTopic(topic string) error {
+	switch {
+	case topic == realtime.AgentsTopic, topic == realtime.ProxiesTopic:
+		return nil
+	case strings.HasPrefix(topic, realtime.ScenarioRunsTopic("")) && len(topic) > len(realtime.ScenarioRunsTopic("")):
+		return nil
+	default:
+		return fmt.Errorf("unknown topic %q, expected agents, proxies or runs:<scenario_id>", topic)
+	}
+}
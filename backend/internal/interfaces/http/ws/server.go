@@ -0,0 +1,227 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"parrotflow/internal/infrastructure/realtime"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+)
+
+// Config controls keepalive and limits of WebSocket connections
+type Config struct {
+	PingInterval     time.Duration // How often clients are pinged; a missing pong closes the connection
+	WriteTimeout     time.Duration
+	MaxSubscriptions int      // Topics one connection may subscribe to at once
+	OriginPatterns   []string // Extra origins allowed besides the request host, e.g. the dashboard dev server
+}
+
+func DefaultConfig() Config {
+	return Config{
+		PingInterval:     30 * time.Second,
+		WriteTimeout:     10 * time.Second,
+		MaxSubscriptions: 32,
+	}
+}
+
+// AuthHooks plug authentication into the WebSocket channel
+// Nil hooks accept every connection and subscription
+type AuthHooks struct {
+	// Authenticate runs on the upgrade request; an error rejects it with 401 Unauthorized
+	Authenticate func(r *http.Request) error
+	// Authorize runs on every subscribe; an error is reported to the client and the subscription refused
+	Authorize func(r *http.Request, topic string) error
+}
+
+// Server pushes hub events to WebSocket clients subscribed to topics
+type Server struct {
+	hub    *realtime.Hub
+	config Config
+	auth   AuthHooks
+}
+
+func NewServer(hub *realtime.Hub, config Config) *Server {
+	return &Server{
+		hub:    hub,
+		config: config,
+	}
+}
+
+// SetAuthHooks must be called before the server handles requests
+func (s *Server) SetAuthHooks(hooks AuthHooks) {
+	s.auth = hooks
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.auth.Authenticate != nil {
+		if err := s.auth.Authenticate(r); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		Subprotocols:   []string{Subprotocol},
+		OriginPatterns: s.config.OriginPatterns,
+	})
+	if err != nil {
+		// Accept already wrote the error response
+		return
+	}
+	defer conn.CloseNow()
+
+	c := &connection{
+		server:        s,
+		conn:          conn,
+		request:       r,
+		subscriptions: make(map[string]*realtime.Subscription),
+	}
+	c.serve(r.Context())
+}
+
+// connection is one WebSocket client
+// subscriptions is only touched by the read loop; forwarders only write
+type connection struct {
+	server        *Server
+	conn          *websocket.Conn
+	request       *http.Request
+	subscriptions map[string]*realtime.Subscription
+	forwarders    sync.WaitGroup
+}
+
+func (c *connection) serve(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		cancel()
+		for _, sub := range c.subscriptions {
+			sub.Close()
+		}
+		c.forwarders.Wait()
+	}()
+
+	go c.keepalive(ctx, cancel)
+
+	for {
+		var msg ClientMessage
+		if err := wsjson.Read(ctx, c.conn, &msg); err != nil {
+			return
+		}
+
+		switch msg.Action {
+		case ActionSubscribe:
+			c.subscribe(ctx, msg)
+		case ActionUnsubscribe:
+			c.unsubscribe(ctx, msg.Topic)
+		default:
+			c.send(ctx, errorMessage(msg.Topic, "unknown action %q, expected subscribe or unsubscribe", msg.Action))
+		}
+	}
+}
+
+func (c *connection) subscribe(ctx context.Context, msg ClientMessage) {
+	if err := validateTopic(msg.Topic); err != nil {
+		c.send(ctx, errorMessage(msg.Topic, "%v", err))
+		return
+	}
+	if c.server.auth.Authorize != nil {
+		if err := c.server.auth.Authorize(c.request, msg.Topic); err != nil {
+			c.send(ctx, errorMessage(msg.Topic, "%v", err))
+			return
+		}
+	}
+
+	// Subscribing again replaces the filter, e.g. after the previous subscription was dropped
+	if existing, ok := c.subscriptions[msg.Topic]; ok {
+		existing.Close()
+		delete(c.subscriptions, msg.Topic)
+	}
+	if len(c.subscriptions) >= c.server.config.MaxSubscriptions {
+		c.send(ctx, errorMessage(msg.Topic, "too many subscriptions, the limit is %d", c.server.config.MaxSubscriptions))
+		return
+	}
+
+	sub, replay, complete := c.server.hub.Subscribe(msg.Topic, msg.AfterID)
+	ack := ServerMessage{Type: TypeSubscribed, Topic: msg.Topic}
+	if msg.AfterID == 0 {
+		replay = nil
+	} else if !complete {
+		// Replaying part of what was missed would look like a full catch-up
+		replay = nil
+		ack.Resync = true
+	}
+	c.subscriptions[msg.Topic] = sub
+	c.send(ctx, ack)
+
+	c.forwarders.Add(1)
+	go c.forward(ctx, sub, replay, msg.Topic, msg.Filter)
+}
+
+func (c *connection) unsubscribe(ctx context.Context, topic string) {
+	sub, ok := c.subscriptions[topic]
+	if !ok {
+		c.send(ctx, errorMessage(topic, "not subscribed"))
+		return
+	}
+	sub.Close()
+	delete(c.subscriptions, topic)
+	c.send(ctx, ServerMessage{Type: TypeUnsubscribed, Topic: topic})
+}
+
+// forward pushes the matching events of one subscription until it is closed
+func (c *connection) forward(ctx context.Context, sub *realtime.Subscription, replay []realtime.Message, topic string, filter Filter) {
+	defer c.forwarders.Done()
+
+	for _, msg := range replay {
+		if filter.Matches(msg.Event) {
+			c.send(ctx, eventMessage(msg))
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-sub.Messages():
+			if !ok {
+				if sub.Dropped() {
+					c.send(ctx, errorMessage(topic, "subscription dropped for falling behind, subscribe again with after_id"))
+				}
+				return
+			}
+			if filter.Matches(msg.Event) {
+				c.send(ctx, eventMessage(msg))
+			}
+		}
+	}
+}
+
+// keepalive pings the client and closes the connection when a pong does not come back in time
+func (c *connection) keepalive(ctx context.Context, cancel context.CancelFunc) {
+	ticker := time.NewTicker(c.server.config.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pingCtx, pingCancel := context.WithTimeout(ctx, c.server.config.WriteTimeout)
+			err := c.conn.Ping(pingCtx)
+			pingCancel()
+			if err != nil {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func (c *connection) send(ctx context.Context, msg ServerMessage) {
+	writeCtx, cancel := context.WithTimeout(ctx, c.server.config.WriteTimeout)
+	defer cancel()
+	wsjson.Write(writeCtx, c.conn, msg)
+}
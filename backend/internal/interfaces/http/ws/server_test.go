@@ -0,0 +1,130 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"parrotflow/internal/domain/agent"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/realtime"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+)
+
+func newAgentEvent(agentID, status string) shared.DomainEvent {
+	return agent.AgentStatusChanged{
+		BaseEvent: shared.NewBaseEvent(agent.EventAgentStatusChanged, agentID),
+		AgentID:   agentID,
+		NewStatus: status,
+	}
+}
+
+func dial(t *testing.T, ctx context.Context, server *Server) *websocket.Conn {
+	t.Helper()
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(ts.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.CloseNow() })
+	return conn
+}
+
+func readMessage(t *testing.T, ctx context.Context, conn *websocket.Conn) ServerMessage {
+	t.Helper()
+	var msg ServerMessage
+	if err := wsjson.Read(ctx, conn, &msg); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	return msg
+}
+
+func TestServer_PushesFilteredEvents(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hub := realtime.NewHub(realtime.DefaultHubConfig(), 0)
+	conn := dial(t, ctx, NewServer(hub, DefaultConfig()))
+
+	wsjson.Write(ctx, conn, ClientMessage{
+		Action: ActionSubscribe,
+		Topic:  realtime.AgentsTopic,
+		Filter: Filter{AggregateIDs: []string{"2"}},
+	})
+	if msg := readMessage(t, ctx, conn); msg.Type != TypeSubscribed {
+		t.Fatalf("First message = %+v, want subscribed", msg)
+	}
+
+	hub.Publish(realtime.AgentsTopic, 0, newAgentEvent("1", "BUSY"))
+	published := hub.Publish(realtime.AgentsTopic, 0, newAgentEvent("2", "IDLE"))
+
+	msg := readMessage(t, ctx, conn)
+	if msg.Type != TypeEvent || msg.ID != published.ID || msg.AggregateID != "2" {
+		t.Errorf("Event message = %+v, want event %d of agent 2", msg, published.ID)
+	}
+}
+
+func TestServer_RejectsUnknownTopicAndUnauthorizedSubscription(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := NewServer(realtime.NewHub(realtime.DefaultHubConfig(), 0), DefaultConfig())
+	server.SetAuthHooks(AuthHooks{
+		Authorize: func(r *http.Request, topic string) error {
+			if topic == realtime.ProxiesTopic {
+				return errors.New("forbidden")
+			}
+			return nil
+		},
+	})
+	conn := dial(t, ctx, server)
+
+	for _, topic := range []string{"runs:", "secrets", realtime.ProxiesTopic} {
+		wsjson.Write(ctx, conn, ClientMessage{Action: ActionSubscribe, Topic: topic})
+		if msg := readMessage(t, ctx, conn); msg.Type != TypeError {
+			t.Errorf("Subscribe(%q) = %+v, want error", topic, msg)
+		}
+	}
+}
+
+func TestServer_AuthenticateRejectsHandshake(t *testing.T) {
+	server := NewServer(realtime.NewHub(realtime.DefaultHubConfig(), 0), DefaultConfig())
+	server.SetAuthHooks(AuthHooks{
+		Authenticate: func(r *http.Request) error { return errors.New("missing token") },
+	})
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	_, resp, err := websocket.Dial(context.Background(), "ws"+strings.TrimPrefix(ts.URL, "http"), nil)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Dial() = %v, %v, want 401 Unauthorized", resp, err)
+	}
+}
+
+func TestServer_SelectsChannelProtocolOverAccessToken(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ts := httptest.NewServer(NewServer(realtime.NewHub(realtime.DefaultHubConfig(), 0), DefaultConfig()))
+	defer ts.Close()
+
+	conn, resp, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(ts.URL, "http"), &websocket.DialOptions{
+		Subprotocols: []string{Subprotocol, "access_token.secret"},
+	})
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.CloseNow()
+
+	if conn.Subprotocol() != Subprotocol || strings.Contains(resp.Header.Get("Sec-WebSocket-Protocol"), "secret") {
+		t.Errorf("Selected protocol = %q, want %q without the token", conn.Subprotocol(), Subprotocol)
+	}
+}
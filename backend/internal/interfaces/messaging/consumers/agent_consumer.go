@@ -0,0 +1,260 @@
+// Package consumers handles the messages agents publish to the broker, the
+// broker counterpart of the HTTP handlers
+package consumers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	agentcommand "parrotflow/internal/application/command/agent"
+	runcommand "parrotflow/internal/application/command/run"
+	"parrotflow/internal/domain/agent"
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/shared"
+	messages "parrotflow/internal/interfaces/messaging"
+	"parrotflow/internal/ports"
+)
+
+// Headers agents sign broker messages with, like the X-Parrotflow-* headers of their
+// HTTP requests: the signature covers the timestamp, the nonce, the purpose and the message body
+const (
+	HeaderAgent     = "x-parrotflow-agent"
+	HeaderTimestamp = "x-parrotflow-timestamp"
+	HeaderNonce     = "x-parrotflow-nonce"
+	HeaderSignature = "x-parrotflow-signature"
+)
+
+// ErrAgentMismatch is returned for a heartbeat naming another agent than the one that signed it
+var ErrAgentMismatch = errors.New("message names another agent than its signer")
+
+// AgentConsumer consumes the heartbeats and run progress agents publish to the broker
+// Messages go through the same commands as their HTTP counterparts, so they are verified
+// against the credential of the signing agent; a message failing verification is
+// rejected and ends up with the dead letters
+type AgentConsumer struct {
+	broker     ports.MessageBroker
+	runs       run.Repository
+	heartbeats *agentcommand.UpdateHeartbeatCommandHandler
+	progress   *runcommand.ReportRunProgressCommandHandler
+	retryDelay time.Duration
+	consuming  bool // Whether the heartbeat queue is consumed
+
+	mu      sync.Mutex
+	ctx     context.Context               // Set by Start, progress consumers run until it is done
+	cancels map[string]context.CancelFunc // Progress queue consumers by run ID
+}
+
+func NewAgentConsumer(
+	broker ports.MessageBroker,
+	runs run.Repository,
+	heartbeats *agentcommand.UpdateHeartbeatCommandHandler,
+	progress *runcommand.ReportRunProgressCommandHandler,
+) *AgentConsumer {
+	return &AgentConsumer{
+		broker:     broker,
+		runs:       runs,
+		heartbeats: heartbeats,
+		progress:   progress,
+		retryDelay: 5 * time.Second,
+		cancels:    make(map[string]context.CancelFunc),
+	}
+}
+
+// Start consumes the heartbeat queue and the progress queues of the runs in progress
+// in the background until ctx is done, retrying while the broker is unavailable; the
+// progress queues of runs started later are consumed from Handle
+func (c *AgentConsumer) Start(ctx context.Context) {
+	c.mu.Lock()
+	c.ctx = ctx
+	c.mu.Unlock()
+	go c.run(ctx)
+}
+
+func (c *AgentConsumer) run(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := c.start(ctx)
+		if err == nil {
+			return
+		}
+		slog.ErrorContext(ctx, "Error consuming agent messages", "error", err)
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(c.retryDelay):
+		}
+	}
+}
+
+func (c *AgentConsumer) start(ctx context.Context) error {
+	if !c.consuming {
+		if err := c.consume(ctx, messages.QueueAgentHeartbeat, c.handleHeartbeat); err != nil {
+			return err
+		}
+		c.consuming = true
+	}
+	return c.resumeProgress(ctx)
+}
+
+// resumeProgress consumes the progress queues of the runs started before this process
+func (c *AgentConsumer) resumeProgress(ctx context.Context) error {
+	criteria := run.NewSearchCriteria().WithStatus(shared.StatusRunning.String()).WithPagination(100, 0)
+	for {
+		page, err := c.runs.FindAll(ctx, criteria)
+		if err != nil {
+			return err
+		}
+		for _, r := range page.Items {
+			if err := c.consumeProgress(r.Id.String()); err != nil {
+				return err
+			}
+		}
+		if len(page.Items) < criteria.Limit {
+			return nil
+		}
+		criteria.Offset += criteria.Limit
+	}
+}
+
+// Handle starts consuming the progress queue of a started run and stops once it is finished
+func (c *AgentConsumer) Handle(ctx context.Context, event shared.DomainEvent) error {
+	switch e := event.(type) {
+	case run.RunStarted:
+		return c.consumeProgress(e.RunID)
+	case run.RunCompleted, run.RunFailed, run.RunCancelled:
+		c.stopProgress(event.AggregateID())
+	}
+	return nil
+}
+
+func (c *AgentConsumer) CanHandle(eventType string) bool {
+	switch eventType {
+	case run.EventRunStarted, run.EventRunCompleted, run.EventRunFailed, run.EventRunCancelled:
+		return true
+	}
+	return false
+}
+
+func (c *AgentConsumer) consume(ctx context.Context, queue string, handler ports.MessageHandler) error {
+	// Asserting the queue routes the messages rejected from it to the dead letters
+	if err := c.broker.AssertQueue(ctx, queue); err != nil {
+		return err
+	}
+	return c.broker.Consume(ctx, queue, handler)
+}
+
+func (c *AgentConsumer) consumeProgress(runID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ctx == nil {
+		return errors.New("agent consumer is not started")
+	}
+	if _, ok := c.cancels[runID]; ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	if err := c.consume(ctx, messages.QueueAgentProgress(runID), c.progressHandler(runID)); err != nil {
+		cancel()
+		return err
+	}
+	c.cancels[runID] = cancel
+	return nil
+}
+
+func (c *AgentConsumer) stopProgress(runID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cancel, ok := c.cancels[runID]; ok {
+		cancel()
+		delete(c.cancels, runID)
+	}
+}
+
+func (c *AgentConsumer) handleHeartbeat(ctx context.Context, message ports.Message) error {
+	agentID, signature, err := signedBy(message)
+	if err != nil {
+		return err
+	}
+	var heartbeat messages.AgentHeartbeat
+	if err := json.Unmarshal(message.Body, &heartbeat); err != nil {
+		return err
+	}
+	if heartbeat.AgentID != agentID.String() {
+		return ErrAgentMismatch
+	}
+
+	_, err = c.heartbeats.Handle(ctx, agentcommand.UpdateHeartbeatCommand{
+		AgentID:   agentID,
+		Signature: signature,
+		Body:      message.Body,
+	})
+	return err
+}
+
+// progressHandler handles the progress events of one run
+// Only node events are recorded: agents do not report run outcomes to the backend yet,
+// so run events are acknowledged and dropped
+func (c *AgentConsumer) progressHandler(runID string) ports.MessageHandler {
+	return func(ctx context.Context, message ports.Message) error {
+		agentID, signature, err := signedBy(message)
+		if err != nil {
+			return err
+		}
+		var event messages.ProgressEvent
+		if err := json.Unmarshal(message.Body, &event); err != nil {
+			return err
+		}
+		if event.RunID != runID {
+			return fmt.Errorf("progress event of run %q on the queue of run %s", event.RunID, runID)
+		}
+
+		var status run.NodeStatus
+		switch event.Event {
+		case messages.ProgressEventTypeNodeStarted:
+			status = run.NodeStatusRunning
+		case messages.ProgressEventTypeNodeCompleted:
+			status = run.NodeStatusCompleted
+		case messages.ProgressEventTypeNodeFailed:
+			status = run.NodeStatusFailed
+		default:
+			slog.DebugContext(ctx, "Ignoring run progress event", "run_id", runID, "event", event.Event)
+			return nil
+		}
+
+		id, err := run.NewRunID(runID)
+		if err != nil {
+			return err
+		}
+		_, err = c.progress.Handle(ctx, runcommand.ReportRunProgressCommand{
+			RunID:     id,
+			NodeID:    event.NodeID,
+			Status:    status,
+			Message:   event.Error,
+			AgentID:   agentID,
+			Signature: signature,
+			Body:      message.Body,
+		})
+		return err
+	}
+}
+
+// signedBy reads the signing agent and its signature from the message headers
+func signedBy(message ports.Message) (agent.AgentID, agent.Signature, error) {
+	if message.Headers[HeaderAgent] == "" {
+		return agent.AgentID{}, agent.Signature{}, agent.ErrMissingSignature
+	}
+	agentID, err := agent.NewAgentID(message.Headers[HeaderAgent])
+	if err != nil {
+		return agent.AgentID{}, agent.Signature{}, err
+	}
+	return agentID, agent.Signature{
+		Timestamp: message.Headers[HeaderTimestamp],
+		Nonce:     message.Headers[HeaderNonce],
+		Value:     message.Headers[HeaderSignature],
+	}, nil
+}
@@ -0,0 +1,273 @@
+package consumers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	agentcommand "parrotflow/internal/application/command/agent"
+	runcommand "parrotflow/internal/application/command/run"
+	"parrotflow/internal/domain/agent"
+	"parrotflow/internal/domain/run"
+	"parrotflow/internal/domain/scenario"
+	"parrotflow/internal/domain/secret"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/infrastructure/encryption"
+	"parrotflow/internal/infrastructure/messaging/memory"
+	"parrotflow/internal/infrastructure/persistence"
+	messages "parrotflow/internal/interfaces/messaging"
+	"parrotflow/internal/models"
+	"parrotflow/internal/ports"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// discardEventBus drops published events
+type discardEventBus struct{}
+
+func (discardEventBus) Publish(event shared.DomainEvent) error      { return nil }
+func (discardEventBus) Subscribe(handler shared.EventHandler) error { return nil }
+
+type consumerFixture struct {
+	db       *gorm.DB
+	broker   *memory.Broker
+	agents   *persistence.AgentRepository
+	runs     *persistence.RunRepository
+	secrets  *persistence.SecretRepository
+	consumer *AgentConsumer
+}
+
+func newConsumerFixture(t *testing.T) *consumerFixture {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	// Every connection to :memory: opens its own empty database
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	err = db.AutoMigrate(&models.Tag{}, &models.Agent{}, &models.AgentNonce{}, &models.Scenario{}, &models.ScenarioRun{}, &models.RunNodeStep{},
+		&models.Secret{}, &models.OutboxEvent{}, &models.EventLogEntry{})
+	if err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	entry, _ := encryption.GenerateKey("k1")
+	keyring, err := encryption.NewKeyring(encryption.Config{Keys: entry})
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+
+	bus := discardEventBus{}
+	broker := memory.NewBroker()
+	agents := persistence.NewAgentRepository(db, keyring)
+	runs := persistence.NewRunRepository(db)
+	secrets := persistence.NewSecretRepository(db, keyring)
+	return &consumerFixture{
+		db:      db,
+		broker:  broker,
+		agents:  agents,
+		runs:    runs,
+		secrets: secrets,
+		consumer: NewAgentConsumer(
+			broker,
+			runs,
+			agentcommand.NewUpdateHeartbeatCommandHandler(agents, bus),
+			runcommand.NewReportRunProgressCommandHandler(runs, agents, secrets, bus),
+		),
+	}
+}
+
+// enroll saves an agent with a credential and returns its ID and secret
+func (f *consumerFixture) enroll(t *testing.T, name string) (agent.AgentID, string) {
+	t.Helper()
+	browser, _ := agent.NewBrowserCapability(agent.BrowserChromium, "120", true)
+	osInfo, _ := agent.NewOSInfo(agent.PlatformLinux, agent.ArchAMD64, "6.1")
+	limits, _ := agent.NewResourceLimits(2, 1024, 2)
+	capabilities, _ := agent.NewCapabilities([]agent.BrowserCapability{browser}, osInfo, agent.NewProxyCapability(false, nil), limits, nil)
+	connectionInfo, _ := agent.NewConnectionInfo("10.0.0.2", name, "agent."+name)
+	id, _ := agent.NewAgentID("0")
+	a, err := agent.NewAgent(id, name, capabilities, connectionInfo)
+	if err != nil {
+		t.Fatalf("NewAgent() error = %v", err)
+	}
+	secret, err := a.IssueCredential()
+	if err != nil {
+		t.Fatalf("IssueCredential() error = %v", err)
+	}
+	if err := f.agents.Save(context.Background(), a); err != nil {
+		t.Fatalf("Save(agent) error = %v", err)
+	}
+	return a.Id, secret
+}
+
+// startRun saves a running run of a new scenario that referenced secretNames
+func (f *consumerFixture) startRun(t *testing.T, secretNames ...string) *run.Run {
+	t.Helper()
+	if err := f.db.Create(&models.Scenario{ScenarioBase: models.ScenarioBase{Name: "checkout"}}).Error; err != nil {
+		t.Fatalf("Create(scenario) error = %v", err)
+	}
+	scenarioID, _ := scenario.NewScenarioID("1")
+	runID, _ := run.NewRunID("0")
+	r, _ := run.NewRun(runID, scenarioID, "{}")
+	_ = r.Start()
+	r.SecretNames = secretNames
+	if err := f.runs.Save(context.Background(), r); err != nil {
+		t.Fatalf("Save(run) error = %v", err)
+	}
+	id, _ := run.NewRunID("1")
+	r.Id = id
+	return r
+}
+
+// signed builds a message signed by an agent for a purpose
+func signed(t *testing.T, id agent.AgentID, secret, purpose string, body any) ports.Message {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	now := time.Now().Unix()
+	nonce := strconv.FormatInt(time.Now().UnixNano(), 36)
+	return ports.Message{
+		ContentType: "application/json",
+		Body:        data,
+		Headers: map[string]string{
+			HeaderAgent:     id.String(),
+			HeaderTimestamp: strconv.FormatInt(now, 10),
+			HeaderNonce:     nonce,
+			HeaderSignature: agent.Sign(secret, now, nonce, purpose, data),
+		},
+	}
+}
+
+func TestAgentConsumer_VerifiesHeartbeats(t *testing.T) {
+	ctx := context.Background()
+	f := newConsumerFixture(t)
+	id, secret := f.enroll(t, "worker-1")
+	other, _ := f.enroll(t, "worker-2")
+	heartbeat := messages.AgentHeartbeat{AgentID: id.String(), Status: messages.AgentHeartbeatStatusIdle, Timestamp: time.Now()}
+
+	message := signed(t, id, secret, agent.PurposeHeartbeat, heartbeat)
+	if err := f.consumer.handleHeartbeat(ctx, message); err != nil {
+		t.Fatalf("signed heartbeat error = %v", err)
+	}
+	if err := f.consumer.handleHeartbeat(ctx, message); !errors.Is(err, agent.ErrReplayedSignature) {
+		t.Errorf("replayed heartbeat error = %v, want %v", err, agent.ErrReplayedSignature)
+	}
+	a, _ := f.agents.FindByID(ctx, id)
+	if a.LastHeartbeatAt == nil {
+		t.Error("LastHeartbeatAt not set by the heartbeat")
+	}
+
+	unsigned := signed(t, id, secret, agent.PurposeHeartbeat, heartbeat)
+	unsigned.Headers = nil
+	if err := f.consumer.handleHeartbeat(ctx, unsigned); !errors.Is(err, agent.ErrMissingSignature) {
+		t.Errorf("unsigned heartbeat error = %v, want %v", err, agent.ErrMissingSignature)
+	}
+	if err := f.consumer.handleHeartbeat(ctx, signed(t, id, "not-the-credential", agent.PurposeHeartbeat, heartbeat)); !errors.Is(err, agent.ErrInvalidSignature) {
+		t.Errorf("heartbeat with wrong secret error = %v, want %v", err, agent.ErrInvalidSignature)
+	}
+
+	// An agent cannot beat for another one, even with a valid signature of its own
+	heartbeat.AgentID = other.String()
+	if err := f.consumer.handleHeartbeat(ctx, signed(t, id, secret, agent.PurposeHeartbeat, heartbeat)); !errors.Is(err, ErrAgentMismatch) {
+		t.Errorf("heartbeat for another agent error = %v, want %v", err, ErrAgentMismatch)
+	}
+}
+
+func TestAgentConsumer_ProgressOnlyFromClaimingAgent(t *testing.T) {
+	ctx := context.Background()
+	f := newConsumerFixture(t)
+	first, firstSecret := f.enroll(t, "worker-1")
+	second, secondSecret := f.enroll(t, "worker-2")
+	r := f.startRun(t)
+	runID := r.Id.String()
+	purpose := agent.PurposeProgress(runID)
+	handle := f.consumer.progressHandler(runID)
+
+	event := messages.ProgressEvent{RunID: runID, Event: messages.ProgressEventTypeNodeStarted, NodeID: "goto", Timestamp: time.Now()}
+	if err := handle(ctx, signed(t, first, firstSecret, purpose, event)); err != nil {
+		t.Fatalf("progress of the first agent error = %v", err)
+	}
+	if err := handle(ctx, signed(t, second, secondSecret, purpose, event)); !errors.Is(err, run.ErrRunClaimedByAnotherAgent) {
+		t.Errorf("progress of the second agent error = %v, want %v", err, run.ErrRunClaimedByAnotherAgent)
+	}
+	if err := handle(ctx, signed(t, first, firstSecret, agent.PurposeProgress("42"), event)); !errors.Is(err, agent.ErrInvalidSignature) {
+		t.Errorf("progress signed for another run error = %v, want %v", err, agent.ErrInvalidSignature)
+	}
+
+	stored, err := f.runs.FindByID(ctx, r.Id)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if stored.AgentID != first.String() {
+		t.Errorf("AgentID = %q, want the run claimed by %s", stored.AgentID, first)
+	}
+}
+
+func TestAgentConsumer_MasksSecretsOfTheRun(t *testing.T) {
+	ctx := context.Background()
+	f := newConsumerFixture(t)
+	id, agentSecret := f.enroll(t, "worker-1")
+	for _, s := range [][2]string{{"SHOP_PASSWORD", "hunter2"}, {"PIN", "4821"}, {"OTHER_PASSWORD", "correct-horse"}} {
+		sec, _ := secret.NewSecret(secret.SecretID{}, s[0], s[1], "")
+		if err := f.secrets.Save(ctx, sec); err != nil {
+			t.Fatalf("Save(secret) error = %v", err)
+		}
+	}
+	r := f.startRun(t, "SHOP_PASSWORD", "PIN")
+	runID := r.Id.String()
+
+	event := messages.ProgressEvent{RunID: runID, Event: messages.ProgressEventTypeNodeFailed, NodeID: "login",
+		Error: "login with hunter2 and 4821 failed after 42 attempts, correct-horse", Timestamp: time.Now()}
+	if err := f.consumer.progressHandler(runID)(ctx, signed(t, id, agentSecret, agent.PurposeProgress(runID), event)); err != nil {
+		t.Fatalf("progress error = %v", err)
+	}
+
+	// Short values are masked too, secrets the run did not reference are left alone
+	var step models.RunNodeStep
+	if err := f.db.Where("node_id = ?", "login").First(&step).Error; err != nil {
+		t.Fatalf("First(step) error = %v", err)
+	}
+	if want := "login with " + secret.MaskedValue + " and " + secret.MaskedValue + " failed after 42 attempts, correct-horse"; step.Message != want {
+		t.Errorf("Message = %q, want %q", step.Message, want)
+	}
+}
+
+func TestAgentConsumer_ConsumesProgressOfStartedRuns(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f := newConsumerFixture(t)
+	id, secret := f.enroll(t, "worker-1")
+	r := f.startRun(t)
+	runID := r.Id.String()
+
+	f.consumer.Start(ctx)
+	if err := f.consumer.Handle(ctx, run.RunStarted{RunID: runID}); err != nil {
+		t.Fatalf("Handle(RunStarted) error = %v", err)
+	}
+	event := messages.ProgressEvent{RunID: runID, Event: messages.ProgressEventTypeNodeFailed, NodeID: "click", Error: "button not found", Timestamp: time.Now()}
+	if err := f.broker.Publish(ctx, messages.QueueAgentProgress(runID), signed(t, id, secret, agent.PurposeProgress(runID), event)); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		var step models.RunNodeStep
+		if err := f.db.Where("node_id = ?", "click").First(&step).Error; err == nil {
+			if step.Status != run.NodeStatusFailed.String() || step.Message != "button not found" {
+				t.Errorf("step = %s %q, want FAILED with the agent error", step.Status, step.Message)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("progress published to the broker was not recorded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// initialisms are written in capitals in Go names
+var initialisms = map[string]string{"id": "ID", "url": "URL", "uuid": "UUID", "json": "JSON"}
+
+var placeholder = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// Generate renders the Go types of a schema
+//
+// Required properties are plain fields; optional numbers, booleans, times and
+// objects are pointers so an absent value is not confused with a zero one, other
+// optional properties are omitted when empty. jen.String enums get a named type with
+// a constant per value
+func Generate(schema *Schema, pkg string) *jen.File {
+	f := jen.NewFile(pkg)
+	f.HeaderComment("Code generated by gen from shared/schemas/messages.yaml. DO NOT EDIT.")
+
+	for _, t := range schema.Types {
+		generateType(f, t)
+	}
+	if len(schema.Queues) > 0 {
+		generateQueues(f, schema.Queues)
+	}
+	return f
+}
+
+func generateType(f *jen.File, t *Type) {
+	var enums []jen.Code
+	fields := make([]jen.Code, 0, len(t.Properties))
+	for _, p := range t.Properties {
+		field := jen.Id(goName(p.Name))
+		if p.Description != "" {
+			field = jen.Comment(p.Description).Line().Add(field)
+		}
+
+		fieldType := fieldType(p)
+		if len(p.Enum) > 0 {
+			enumType := enumTypeName(t.Name, p.Name)
+			enums = append(enums, generateEnum(enumType, p))
+			fieldType = jen.Id(enumType)
+		}
+		if !p.Required && isPointer(p) {
+			fieldType = jen.Op("*").Add(fieldType)
+		}
+
+		tag := p.Name
+		if !p.Required {
+			tag += ",omitempty"
+		}
+		fields = append(fields, field.Add(fieldType).Tag(map[string]string{"json": tag}))
+	}
+
+	description := t.Description
+	if description == "" {
+		description = "is the " + t.Name + " schema"
+	}
+	f.Comment(t.Name + " " + description)
+	f.Type().Id(t.Name).Struct(fields...)
+	f.Line()
+	for _, enum := range enums {
+		f.Add(enum)
+		f.Line()
+	}
+}
+
+func generateEnum(name string, p *Property) jen.Code {
+	values := make([]jen.Code, 0, len(p.Enum))
+	for _, value := range p.Enum {
+		values = append(values, jen.Id(name+goName(value)).Id(name).Op("=").Lit(value))
+	}
+	return jen.Type().Id(name).String().
+		Line().Line().
+		Const().Defs(values...)
+}
+
+func generateQueues(f *jen.File, queues []Queue) {
+	var constants []jen.Code
+	for _, q := range queues {
+		if placeholder.MatchString(q.Name) {
+			continue
+		}
+		constants = append(constants, jen.Comment(q.Description).Line().Id(queueName(q)).Op("=").Lit(q.Name))
+	}
+	if len(constants) > 0 {
+		f.Comment("Queues of the messages above")
+		f.Const().Defs(constants...)
+		f.Line()
+	}
+
+	for _, q := range queues {
+		matches := placeholder.FindAllStringSubmatch(q.Name, -1)
+		if len(matches) == 0 {
+			continue
+		}
+
+		params := make([]jen.Code, 0, len(matches))
+		args := make([]jen.Code, 0, len(matches))
+		for _, m := range matches {
+			param := lowerFirst(goName(m[1]))
+			params = append(params, jen.Id(param).String())
+			args = append(args, jen.Id(param))
+		}
+		format := placeholder.ReplaceAllString(q.Name, "%s")
+
+		f.Comment(queueName(q) + " returns the " + q.Name + " queue")
+		f.Comment(q.Description)
+		f.Func().Id(queueName(q)).Params(params...).String().Block(
+			jen.Return(jen.Qual("fmt", "Sprintf").Call(append([]jen.Code{jen.Lit(format)}, args...)...)),
+		)
+		f.Line()
+	}
+}
+
+func fieldType(p *Property) *jen.Statement {
+	switch p.Kind {
+	case "string":
+		if p.Format == "date-time" {
+			return jen.Qual("time", "Time")
+		}
+		return jen.String()
+	case "number":
+		return jen.Float64()
+	case "integer":
+		return jen.Int()
+	case "boolean":
+		return jen.Bool()
+	case "object":
+		return jen.Map(jen.String()).Any()
+	case "array":
+		return jen.Index().Add(fieldType(p.Items))
+	case "ref":
+		return jen.Id(p.Ref)
+	default:
+		return jen.Any()
+	}
+}
+
+// isPointer reports whether an optional property needs a pointer to tell absent from zero
+func isPointer(p *Property) bool {
+	switch p.Kind {
+	case "number", "integer", "boolean", "ref":
+		return true
+	case "string":
+		return p.Format == "date-time"
+	default:
+		return false
+	}
+}
+
+// enumTypeName names the type of an enum property after its schema and property,
+// avoiding stutter like ProgressEventEvent or NodeNodeType
+func enumTypeName(typeName, property string) string {
+	name := goName(property)
+	switch {
+	case strings.HasPrefix(name, typeName):
+		return name
+	case strings.HasSuffix(typeName, name):
+		return typeName + "Type"
+	default:
+		return typeName + name
+	}
+}
+
+func queueName(q Queue) string {
+	return "Queue" + goName(q.Key)
+}
+
+// goName turns snake_case and camelCase names into exported Go names
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+		if initialism, ok := initialisms[part]; ok {
+			b.WriteString(initialism)
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	for prefix, initialism := range initialisms {
+		if strings.HasPrefix(s, initialism) {
+			return prefix + s[len(initialism):]
+		}
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
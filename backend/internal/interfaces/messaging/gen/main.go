@@ -0,0 +1,36 @@
+// Command gen generates the Go message types from the schema the agent generates
+// its TypeScript types from
+//
+//	go run ./gen -schema ../../../../shared/schemas/messages.yaml -out messages_gen.go
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+)
+
+func main() {
+	schemaPath := flag.String("schema", "../../../../shared/schemas/messages.yaml", "Message schema to generate from")
+	outPath := flag.String("out", "messages_gen.go", "File to write the generated types to")
+	pkg := flag.String("package", "messaging", "Package of the generated file")
+	flag.Parse()
+
+	source, err := os.ReadFile(*schemaPath)
+	if err != nil {
+		log.Fatalf("read schema: %v", err)
+	}
+	schema, err := Parse(source)
+	if err != nil {
+		log.Fatalf("parse schema: %v", err)
+	}
+
+	out, err := os.Create(*outPath)
+	if err != nil {
+		log.Fatalf("create %s: %v", *outPath, err)
+	}
+	defer out.Close()
+	if err := Generate(schema, *pkg).Render(out); err != nil {
+		log.Fatalf("render: %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestGenerate_MatchesCommittedTypes(t *testing.T) {
+	source, err := os.ReadFile("../../../../../shared/schemas/messages.yaml")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	schema, err := Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var generated bytes.Buffer
+	if err := Generate(schema, "messaging").Render(&generated); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	committed, err := os.ReadFile("../messages_gen.go")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !bytes.Equal(generated.Bytes(), committed) {
+		t.Error("messages_gen.go is out of date with shared/schemas/messages.yaml, run go generate ./internal/interfaces/messaging")
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is the part of messages.yaml the generator understands, in file order
+type Schema struct {
+	Types  []*Type
+	Queues []Queue
+}
+
+// Type is a schema under components.schemas
+type Type struct {
+	Name        string
+	Description string
+	Properties  []*Property
+}
+
+// Property is one property of an object schema
+type Property struct {
+	Name        string
+	Description string
+	Required    bool
+	Kind        string   // string, number, integer, boolean, object, array, ref or any
+	Format      string   // uuid, date-time, ...
+	Enum        []string // Allowed values of a string
+	Ref         string   // Referenced type, for ref and for arrays of a referenced type
+	Items       *Property
+}
+
+// Queue is an entry of the queues section
+type Queue struct {
+	Key         string
+	Name        string // May contain {placeholders}
+	Description string
+	MessageType string
+}
+
+// node mirrors the schema keywords the generator reads
+type node struct {
+	Type        any       `yaml:"type"`
+	Format      string    `yaml:"format"`
+	Description string    `yaml:"description"`
+	Enum        []string  `yaml:"enum"`
+	Ref         string    `yaml:"$ref"`
+	Required    []string  `yaml:"required"`
+	Properties  yaml.Node `yaml:"properties"`
+	Items       *node     `yaml:"items"`
+	OneOf       []node    `yaml:"oneOf"`
+}
+
+type document struct {
+	Components struct {
+		Schemas yaml.Node `yaml:"schemas"`
+	} `yaml:"components"`
+	Queues yaml.Node `yaml:"queues"`
+}
+
+// Parse reads a message schema
+func Parse(source []byte) (*Schema, error) {
+	var doc document
+	if err := yaml.Unmarshal(source, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Components.Schemas.Kind != yaml.MappingNode {
+		return nil, errors.New("no schemas under components.schemas")
+	}
+
+	schema := &Schema{}
+	err := eachEntry(&doc.Components.Schemas, func(name string, value *yaml.Node) error {
+		var n node
+		if err := value.Decode(&n); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		t, err := parseType(name, n)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		schema.Types = append(schema.Types, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.Queues.Kind == yaml.MappingNode {
+		err = eachEntry(&doc.Queues, func(key string, value *yaml.Node) error {
+			var q struct {
+				Name        string `yaml:"name"`
+				Description string `yaml:"description"`
+				MessageType string `yaml:"message_type"`
+			}
+			if err := value.Decode(&q); err != nil {
+				return fmt.Errorf("queue %s: %w", key, err)
+			}
+			schema.Queues = append(schema.Queues, Queue{Key: key, Name: q.Name, Description: q.Description, MessageType: q.MessageType})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return schema, nil
+}
+
+func parseType(name string, n node) (*Type, error) {
+	if n.Type != "object" {
+		return nil, fmt.Errorf("only object schemas are supported, got %v", n.Type)
+	}
+
+	required := make(map[string]bool, len(n.Required))
+	for _, r := range n.Required {
+		required[r] = true
+	}
+
+	t := &Type{Name: name, Description: n.Description}
+	err := eachEntry(&n.Properties, func(propName string, value *yaml.Node) error {
+		var pn node
+		if err := value.Decode(&pn); err != nil {
+			return err
+		}
+		p, err := parseProperty(pn)
+		if err != nil {
+			return fmt.Errorf("%s: %w", propName, err)
+		}
+		p.Name = propName
+		p.Required = required[propName]
+		t.Properties = append(t.Properties, p)
+		return nil
+	})
+	return t, err
+}
+
+func parseProperty(n node) (*Property, error) {
+	p := &Property{Description: n.Description, Format: n.Format, Enum: n.Enum}
+	switch {
+	case n.Ref != "":
+		p.Kind = "ref"
+		p.Ref = n.Ref[strings.LastIndex(n.Ref, "/")+1:]
+	case len(n.OneOf) > 0:
+		p.Kind = "any"
+	default:
+		kind, ok := n.Type.(string)
+		if !ok {
+			return nil, fmt.Errorf("unsupported type %v", n.Type)
+		}
+		p.Kind = kind
+	}
+
+	switch p.Kind {
+	case "string", "number", "integer", "boolean", "ref", "any":
+	case "object":
+		if n.Properties.Kind != 0 {
+			return nil, errors.New("inline object schemas are not supported, use a $ref")
+		}
+	case "array":
+		if n.Items == nil {
+			return nil, errors.New("array without items")
+		}
+		items, err := parseProperty(*n.Items)
+		if err != nil {
+			return nil, err
+		}
+		p.Items = items
+	default:
+		return nil, fmt.Errorf("unsupported type %q", p.Kind)
+	}
+	return p, nil
+}
+
+// eachEntry walks a YAML mapping in file order
+func eachEntry(mapping *yaml.Node, fn func(key string, value *yaml.Node) error) error {
+	if mapping.Kind == 0 {
+		return nil
+	}
+	if mapping.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping", mapping.Line)
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if err := fn(mapping.Content[i].Value, mapping.Content[i+1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
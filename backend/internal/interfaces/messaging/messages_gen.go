@@ -0,0 +1,248 @@
+// Code generated by gen from shared/schemas/messages.yaml. DO NOT EDIT.
+
+package messaging
+
+import (
+	"fmt"
+	"time"
+)
+
+// Point2D is the Point2D schema
+type Point2D struct {
+	// X coordinate in the flow editor
+	X float64 `json:"x"`
+	// Y coordinate in the flow editor
+	Y float64 `json:"y"`
+}
+
+// Node is the Node schema
+type Node struct {
+	// Unique identifier for the node
+	ID string `json:"id"`
+	// Type of browser action to perform (maps to NodeType in backend)
+	NodeType NodeType `json:"node_type"`
+	Position Point2D  `json:"position"`
+}
+
+type NodeType string
+
+const (
+	NodeTypeStart        NodeType = "start"
+	NodeTypeGoto         NodeType = "goto"
+	NodeTypeWaitduration NodeType = "waitduration"
+	NodeTypeFindelement  NodeType = "findelement"
+	NodeTypeClick        NodeType = "click"
+	NodeTypeInputdata    NodeType = "inputdata"
+	NodeTypeKeypress     NodeType = "keypress"
+	NodeTypeScreenshot   NodeType = "screenshot"
+	NodeTypeLoaddata     NodeType = "loaddata"
+	NodeTypeExtractdata  NodeType = "extractdata"
+)
+
+// Edge is the Edge schema
+type Edge struct {
+	// Unique identifier for the edge
+	ID string `json:"id"`
+	// ID of the source node
+	Source string `json:"source"`
+	// ID of the target node
+	Target string `json:"target"`
+	// Handle ID on source node (for data passing)
+	SourceHandle string `json:"source_handle,omitempty"`
+	// Handle ID on target node (for data receiving)
+	TargetHandle string `json:"target_handle,omitempty"`
+	// Condition for edge traversal (for conditional flows)
+	Condition string `json:"condition,omitempty"`
+}
+
+// Context is the Context schema
+type Context struct {
+	// List of all nodes in the scenario (Context.Blocks in backend)
+	Blocks []Node `json:"blocks"`
+	// List of all connections between nodes (Context.Edges in backend)
+	Edges []Edge `json:"edges"`
+}
+
+// Parameter is the Parameter schema
+type Parameter struct {
+	// Parameter name (e.g., 'selector', 'url', 'timeout')
+	Name string `json:"name"`
+	// Parameter value (can reference variables or be literal)
+	Value any `json:"value"`
+}
+
+// NodeParameters is the NodeParameters schema
+type NodeParameters struct {
+	// ID of the node this configuration belongs to
+	BlockID string `json:"block_id"`
+	// Input parameters for this node
+	Input []Parameter `json:"input"`
+	// Output parameters from node execution (populated by agent)
+	Output []Parameter `json:"output,omitempty"`
+}
+
+// InputData is the InputData schema
+type InputData struct {
+	// Parameters for each node in the scenario (maps to InputData in backend)
+	Parameters []NodeParameters `json:"parameters"`
+}
+
+// ParameterItem is the ParameterItem schema
+type ParameterItem struct {
+	// The parameter itself (name and value)
+	Parameter Parameter `json:"parameter"`
+	// Expected type of the parameter (maps to ParamType in backend)
+	ParamType ParameterItemParamType `json:"param_type"`
+	// Allowed values (for enum-like params)
+	Values []string `json:"values,omitempty"`
+}
+
+type ParameterItemParamType string
+
+const (
+	ParameterItemParamTypeString  ParameterItemParamType = "string"
+	ParameterItemParamTypeNumber  ParameterItemParamType = "number"
+	ParameterItemParamTypeBoolean ParameterItemParamType = "boolean"
+)
+
+// Parameters is the Parameters schema
+type Parameters struct {
+	// Input parameters provided at runtime (scenario-level)
+	Input []ParameterItem `json:"input"`
+	// Output parameters to be extracted (scenario-level)
+	Output []ParameterItem `json:"output"`
+}
+
+// Viewport is the Viewport schema
+type Viewport struct {
+	Width             *int     `json:"width,omitempty"`
+	Height            *int     `json:"height,omitempty"`
+	DeviceScaleFactor *float64 `json:"deviceScaleFactor,omitempty"`
+}
+
+// BrowserConfig is the BrowserConfig schema
+type BrowserConfig struct {
+	// Run browser in headless mode
+	Headless *bool     `json:"headless,omitempty"`
+	Viewport *Viewport `json:"viewport,omitempty"`
+	// Custom user agent string
+	UserAgent string `json:"userAgent,omitempty"`
+	// Default timeout for actions in milliseconds
+	Timeout *int `json:"timeout,omitempty"`
+	// Browser locale
+	Locale string `json:"locale,omitempty"`
+	// Browser timezone
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// ExecuteScenarioMessage is the ExecuteScenarioMessage schema
+type ExecuteScenarioMessage struct {
+	// Unique identifier for this scenario run (Run.Id in backend)
+	RunID string `json:"run_id"`
+	// ID of the scenario being executed (Scenario.Id in backend)
+	ScenarioID string `json:"scenario_id"`
+	// The scenario context (graph structure) to execute
+	Context Context `json:"context"`
+	// Input parameters for each node in the scenario
+	InputData InputData `json:"input_data"`
+	// Scenario-level runtime parameters
+	Parameters Parameters `json:"parameters"`
+	// Browser configuration (agent-specific, not in domain)
+	BrowserConfig *BrowserConfig `json:"browser_config,omitempty"`
+	// RabbitMQ queue name for control commands (pause/cancel)
+	ControlQueue string `json:"control_queue,omitempty"`
+	// RabbitMQ queue name for progress updates
+	ReplyQueue string `json:"reply_queue,omitempty"`
+}
+
+// ProgressEvent is the ProgressEvent schema
+type ProgressEvent struct {
+	// ID of the run this event belongs to (Run.Id in backend)
+	RunID string `json:"run_id"`
+	// Type of progress event (aligns with Run state transitions)
+	Event ProgressEventType `json:"event"`
+	// ID of node (for node-level events)
+	NodeID string `json:"node_id,omitempty"`
+	// Event-specific data (node output, error details, etc.)
+	Data map[string]any `json:"data,omitempty"`
+	// Error message (for failed events)
+	Error string `json:"error,omitempty"`
+	// ISO 8601 timestamp of the event
+	Timestamp time.Time `json:"timestamp"`
+	// Time taken for node execution (for completed events)
+	ExecutionTimeMs *int `json:"execution_time_ms,omitempty"`
+}
+
+type ProgressEventType string
+
+const (
+	ProgressEventTypeRunStarted    ProgressEventType = "run_started"
+	ProgressEventTypeNodeStarted   ProgressEventType = "node_started"
+	ProgressEventTypeNodeCompleted ProgressEventType = "node_completed"
+	ProgressEventTypeNodeFailed    ProgressEventType = "node_failed"
+	ProgressEventTypeRunCompleted  ProgressEventType = "run_completed"
+	ProgressEventTypeRunFailed     ProgressEventType = "run_failed"
+	ProgressEventTypeRunPaused     ProgressEventType = "run_paused"
+	ProgressEventTypeRunResumed    ProgressEventType = "run_resumed"
+	ProgressEventTypeRunCancelled  ProgressEventType = "run_cancelled"
+)
+
+// ControlCommand is the ControlCommand schema
+type ControlCommand struct {
+	// ID of the run to control
+	RunID string `json:"run_id"`
+	// Control action to perform
+	Command ControlCommandType `json:"command"`
+	// ISO 8601 timestamp of the command
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type ControlCommandType string
+
+const (
+	ControlCommandTypePause  ControlCommandType = "pause"
+	ControlCommandTypeResume ControlCommandType = "resume"
+	ControlCommandTypeCancel ControlCommandType = "cancel"
+)
+
+// AgentHeartbeat is the AgentHeartbeat schema
+type AgentHeartbeat struct {
+	// Unique identifier for the agent instance
+	AgentID string `json:"agent_id"`
+	// Current agent status
+	Status AgentHeartbeatStatus `json:"status"`
+	// ID of currently executing run (if any)
+	CurrentRunID string `json:"current_run_id,omitempty"`
+	// ISO 8601 timestamp
+	Timestamp time.Time `json:"timestamp"`
+	// Additional agent metadata (version, capabilities, etc.)
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+type AgentHeartbeatStatus string
+
+const (
+	AgentHeartbeatStatusIdle    AgentHeartbeatStatus = "idle"
+	AgentHeartbeatStatusRunning AgentHeartbeatStatus = "running"
+	AgentHeartbeatStatusError   AgentHeartbeatStatus = "error"
+)
+
+// Queues of the messages above
+const (
+	// Backend sends ExecuteScenarioMessage here to request scenario execution
+	QueueAgentRequests = "agent.requests"
+	// Agent sends periodic heartbeats to indicate availability
+	QueueAgentHeartbeat = "agent.heartbeat"
+)
+
+// QueueAgentProgress returns the agent.progress.{run_id} queue
+// Agent sends ProgressEvent updates here (per-run queue specified in reply_queue)
+func QueueAgentProgress(runID string) string {
+	return fmt.Sprintf("agent.progress.%s", runID)
+}
+
+// QueueAgentControl returns the agent.control.{run_id} queue
+// Backend sends ControlCommand here for run control (per-run queue)
+func QueueAgentControl(runID string) string {
+	return fmt.Sprintf("agent.control.%s", runID)
+}
@@ -0,0 +1,70 @@
+package messaging
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// The agent round-trips the same samples in agent/test/messages
+const samplesDir = "../../../../shared/schemas/samples"
+
+func TestMessages_RoundTripSamples(t *testing.T) {
+	samples := map[string]any{
+		"ExecuteScenarioMessage": &ExecuteScenarioMessage{},
+		"ProgressEvent":          &ProgressEvent{},
+		"ControlCommand":         &ControlCommand{},
+		"AgentHeartbeat":         &AgentHeartbeat{},
+	}
+
+	for name, message := range samples {
+		t.Run(name, func(t *testing.T) {
+			sample, err := os.ReadFile(filepath.Join(samplesDir, name+".json"))
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+
+			// Properties missing from the generated types mean the schema and the types drifted
+			decoder := json.NewDecoder(bytes.NewReader(sample))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(message); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			encoded, err := json.Marshal(message)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			var want, got any
+			json.Unmarshal(sample, &want)
+			json.Unmarshal(encoded, &got)
+			if !reflect.DeepEqual(normalize(want), normalize(got)) {
+				t.Errorf("Round trip changed the message\nsample:  %s\nencoded: %s", sample, encoded)
+			}
+		})
+	}
+}
+
+// normalize rewrites timestamps to one layout, so 09:26:50.000Z as JavaScript writes
+// it equals 09:26:50Z as Go writes it
+func normalize(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = normalize(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+	case string:
+		if ts, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return ts.UTC().Format(time.RFC3339Nano)
+		}
+	}
+	return value
+}
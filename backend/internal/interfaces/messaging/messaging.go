@@ -0,0 +1,6 @@
+// Package messaging holds the messages the backend and the agents exchange over the
+// broker. The types are generated from shared/schemas/messages.yaml, the schema the
+// agent generates its TypeScript types from, so both sides agree on the wire format
+package messaging
+
+//go:generate go run ./gen -schema ../../../../shared/schemas/messages.yaml -out messages_gen.go
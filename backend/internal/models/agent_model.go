@@ -14,6 +14,11 @@ type Agent struct {
 	ConnectionInfo   string     `json:"connection_info" gorm:"type:jsonb;not null"` // JSON
 	Metadata         string     `json:"metadata,omitempty" gorm:"type:jsonb"`        // JSON
 	Tags             []Tag      `json:"tags" gorm:"many2many:agent_tags;"`
+
+	CredentialSecret    string     `json:"-" gorm:"type:text"`  // Encrypted with the key in CredentialKeyID
+	CredentialKeyID     string     `json:"-" gorm:"size:64"`
+	CredentialIssuedAt  *time.Time `json:"credential_issued_at,omitempty"`
+	CredentialRevokedAt *time.Time `json:"credential_revoked_at,omitempty"`
 }
 
 // TableName specifies the table name for GORM
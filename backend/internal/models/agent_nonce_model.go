@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// AgentNonce is a nonce an agent signed a message with; a message carrying it again is a replay
+// Rows are deleted once messages signed at that time would be refused as stale anyway
+type AgentNonce struct {
+	AgentID uint64    `json:"agent_id" gorm:"primaryKey;autoIncrement:false"`
+	Nonce   string    `json:"nonce" gorm:"primaryKey;size:64"`
+	SeenAt  time.Time `json:"seen_at" gorm:"not null;index"` // UTC
+}
+
+// TableName specifies the table name for GORM
+func (AgentNonce) TableName() string {
+	return "agent_nonces"
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// APIKey represents a hashed API key in the database
+type APIKey struct {
+	Model
+	Name       string     `json:"name" gorm:"size:255;not null"`
+	Prefix     string     `json:"prefix" gorm:"size:32;not null;uniqueIndex"`
+	Hash       string     `json:"-" gorm:"size:64;not null"`
+	Scopes     string     `json:"scopes" gorm:"size:255;not null"` // Comma-separated
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (APIKey) TableName() string {
+	return "api_keys"
+}
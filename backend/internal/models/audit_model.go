@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// AuditEntry is a mutating API call kept for compliance
+// The table is append-only: triggers created by persistence.ProtectAuditLog reject
+// updates and deletes
+type AuditEntry struct {
+	ID            uint64    `json:"id" gorm:"primarykey"` // Recording sequence
+	OccurredAt    time.Time `json:"occurred_at" gorm:"not null;index"`
+	Actor         string    `json:"actor" gorm:"size:255;not null;index"`
+	ActorName     string    `json:"actor_name,omitempty" gorm:"size:255"`
+	AuthMethod    string    `json:"auth_method,omitempty" gorm:"size:50"`
+	OperationID   string    `json:"operation_id" gorm:"size:100;not null;index"`
+	Method        string    `json:"method" gorm:"size:10;not null"`
+	Path          string    `json:"path" gorm:"size:2048;not null"`
+	ResourceType  string    `json:"resource_type" gorm:"size:50;not null;index:idx_audit_log_resource"`
+	ResourceID    string    `json:"resource_id,omitempty" gorm:"size:64;index:idx_audit_log_resource"`
+	Request       string    `json:"request,omitempty" gorm:"type:text"` // Redacted JSON
+	Status        int       `json:"status" gorm:"not null"`
+	Outcome       string    `json:"outcome" gorm:"size:20;not null;index"`
+	Error         string    `json:"error,omitempty" gorm:"type:text"`
+	CorrelationID string    `json:"correlation_id,omitempty" gorm:"size:128;index"`
+	DurationMs    int64     `json:"duration_ms"`
+}
+
+// TableName specifies the table name for GORM
+func (AuditEntry) TableName() string {
+	return "audit_log"
+}
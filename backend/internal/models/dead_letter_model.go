@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Dead letter statuses
+const (
+	DeadLetterPending   = "pending"
+	DeadLetterReplayed  = "replayed"
+	DeadLetterDiscarded = "discarded"
+)
+
+// MessageDeadLetter is a broker message collected from the dead-letter queue
+type MessageDeadLetter struct {
+	Model
+	MessageID      string     `json:"message_id" gorm:"size:255;index"`
+	Queue          string     `json:"queue" gorm:"size:255;not null;index"`
+	Reason         string     `json:"reason" gorm:"size:50"`
+	ContentType    string     `json:"content_type" gorm:"size:100"`
+	Headers        string     `json:"headers" gorm:"type:text"` // JSON object
+	Payload        []byte     `json:"payload"`                  // Encrypted with the key in PayloadKeyID
+	PayloadKeyID   string     `json:"-" gorm:"size:64"`
+	DeathCount     int        `json:"death_count" gorm:"default:1"`
+	DeadLetteredAt time.Time  `json:"dead_lettered_at" gorm:"not null"`
+	Status         string     `json:"status" gorm:"size:20;not null;index"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (MessageDeadLetter) TableName() string {
+	return "message_dead_letters"
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// EnrollmentToken represents a hashed agent enrollment token in the database
+type EnrollmentToken struct {
+	Model
+	Name      string     `json:"name" gorm:"size:255;not null"`
+	Prefix    string     `json:"prefix" gorm:"size:32;not null;uniqueIndex"`
+	Hash      string     `json:"-" gorm:"size:64;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	AgentID   *uint64    `json:"agent_id,omitempty" gorm:"index"`
+}
+
+// TableName specifies the table name for GORM
+func (EnrollmentToken) TableName() string {
+	return "enrollment_tokens"
+}
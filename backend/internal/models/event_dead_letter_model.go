@@ -0,0 +1,16 @@
+package models
+
+// EventDeadLetter is an event an in-process handler gave up on
+type EventDeadLetter struct {
+	Model
+	EventID   string `json:"event_id" gorm:"size:64;not null;index"`
+	EventType string `json:"event_type" gorm:"size:100;not null;index"`
+	Handler   string `json:"handler" gorm:"size:255;not null"`
+	Envelope  string `json:"envelope" gorm:"type:text;not null"` // JSON shared.Envelope
+	Error     string `json:"error" gorm:"type:text"`
+}
+
+// TableName specifies the table name for GORM
+func (EventDeadLetter) TableName() string {
+	return "event_dead_letters"
+}
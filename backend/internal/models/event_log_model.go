@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// EventLogEntry is a published domain event kept for history and auditing
+// Unlike outbox rows, entries are never updated or cleaned up
+type EventLogEntry struct {
+	ID            uint64    `json:"id" gorm:"primarykey"` // Recording sequence
+	EventID       string    `json:"event_id" gorm:"size:64;not null;uniqueIndex"`
+	EventType     string    `json:"event_type" gorm:"size:100;not null;index"`
+	EventVersion  int       `json:"event_version" gorm:"not null;default:1"`
+	AggregateType string    `json:"aggregate_type" gorm:"size:50;not null;index:idx_event_log_aggregate"`
+	AggregateID   string    `json:"aggregate_id" gorm:"size:64;not null;index:idx_event_log_aggregate"`
+	Payload       string    `json:"payload" gorm:"type:text;not null"` // JSON
+	OccurredAt    time.Time `json:"occurred_at" gorm:"not null;index"`
+	CorrelationID string    `json:"correlation_id,omitempty" gorm:"size:128;index"`
+	CausationID   string    `json:"causation_id,omitempty" gorm:"size:128"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (EventLogEntry) TableName() string {
+	return "event_log"
+}
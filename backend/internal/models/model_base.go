@@ -4,6 +4,19 @@ import "time"
 
 type Model struct {
 	ID        uint64    `json:"id" gorm:"primarykey"`
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"` // Default sort key and keyset cursor
 	UpdatedAt time.Time `json:"updated_at"`
+	Version   uint64    `json:"version" gorm:"not null;default:1"` // Optimistic locking
+}
+
+func (m *Model) GetID() uint64 {
+	return m.ID
+}
+
+func (m *Model) GetVersion() uint64 {
+	return m.Version
+}
+
+func (m *Model) SetVersion(version uint64) {
+	m.Version = version
 }
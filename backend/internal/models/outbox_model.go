@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Outbox statuses
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusDelivered = "delivered"
+	OutboxStatusFailed    = "failed"
+)
+
+// OutboxEvent represents a domain event waiting to be relayed to the event bus
+// It is written in the same transaction as the aggregate that raised it
+type OutboxEvent struct {
+	Model
+	EventID       string     `json:"event_id" gorm:"size:64;not null;uniqueIndex"`
+	EventType     string     `json:"event_type" gorm:"size:100;not null;index"`
+	EventVersion  int        `json:"event_version" gorm:"not null;default:1"` // Payload schema version
+	AggregateType string     `json:"aggregate_type" gorm:"size:50;not null;default:''"`
+	AggregateID   string     `json:"aggregate_id" gorm:"size:64;not null;index"`
+	Payload       string     `json:"payload" gorm:"type:text;not null"` // JSON
+	OccurredAt    time.Time  `json:"occurred_at" gorm:"not null"`
+	CorrelationID string     `json:"correlation_id,omitempty" gorm:"size:128"`
+	CausationID   string     `json:"causation_id,omitempty" gorm:"size:128"`
+	TraceParent   string     `json:"trace_parent,omitempty" gorm:"size:64"` // W3C traceparent of the saving request
+	Status        string     `json:"status" gorm:"size:20;not null;index"`
+	Attempts      int        `json:"attempts" gorm:"default:0"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"not null;index"`
+	LastError     string     `json:"last_error,omitempty" gorm:"type:text"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+
+	// JSON array of the sinks that accepted the event, retries skip them
+	DeliveredSinks string `json:"delivered_sinks,omitempty" gorm:"type:text"`
+
+	// Event log sequence of the event, read with the pending events and never stored here
+	Sequence uint64 `json:"sequence,omitempty" gorm:"->;-:migration"`
+}
+
+// TableName specifies the table name for GORM
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
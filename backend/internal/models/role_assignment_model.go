@@ -0,0 +1,13 @@
+package models
+
+// RoleAssignment represents the roles of a subject in the database
+type RoleAssignment struct {
+	Model
+	Subject string `json:"subject" gorm:"size:255;not null;uniqueIndex"`
+	Roles   string `json:"roles" gorm:"size:255;not null"` // Comma-separated
+}
+
+// TableName specifies the table name for GORM
+func (RoleAssignment) TableName() string {
+	return "role_assignments"
+}
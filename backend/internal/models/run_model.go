@@ -9,4 +9,26 @@ type ScenarioRun struct {
 	StartedAt  time.Time `json:"started_at" gorm:"not null"`
 	FinishedAt time.Time `json:"finished_at,omitempty"`
 	Parameters string    `json:"parameters" gorm:"not null"`
+
+	FailureReason string `json:"failure_reason,omitempty" gorm:"type:text"`
+	AgentID       uint64 `json:"agent_id,omitempty" gorm:"index"` // 0 until an agent claims the run
+	SecretNames   string `json:"-" gorm:"type:text"`              // JSON array of the names of the referenced secrets
+	Definition    string `json:"-" gorm:"type:text"`              // JSON snapshot of the scenario definition the run executes
+}
+
+// RunNodeStep is the last reported state of one scenario node in a run
+// Steps are written with the run from its progress events and feed the run analytics
+type RunNodeStep struct {
+	Model
+	RunID      uint64    `json:"run_id" gorm:"not null;uniqueIndex:idx_run_node_step"`
+	ScenarioID uint64    `json:"scenario_id" gorm:"not null;index"`
+	NodeID     string    `json:"node_id" gorm:"size:100;not null;uniqueIndex:idx_run_node_step"`
+	Status     string    `json:"status" gorm:"size:20;not null;index"`
+	Message    string    `json:"message,omitempty" gorm:"type:text"`
+	ReportedAt time.Time `json:"reported_at" gorm:"not null;index"`
+}
+
+// TableName specifies the table name for GORM
+func (RunNodeStep) TableName() string {
+	return "run_node_steps"
 }
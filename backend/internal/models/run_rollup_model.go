@@ -0,0 +1,19 @@
+package models
+
+// RunRollup counts the runs of a scenario created on one day, by status
+// It is recomputed from scenario_runs by the rollup worker and, unlike the runs,
+// is not compacted, so the analytics time series reach back past run retention
+type RunRollup struct {
+	Model
+	ScenarioID      uint64 `json:"scenario_id" gorm:"not null;uniqueIndex:idx_run_rollup_scenario_day_status"`
+	Day             string `json:"day" gorm:"size:10;not null;uniqueIndex:idx_run_rollup_scenario_day_status;index"` // YYYY-MM-DD in UTC
+	Status          string `json:"status" gorm:"size:20;not null;uniqueIndex:idx_run_rollup_scenario_day_status"`
+	RunCount        int64  `json:"run_count" gorm:"not null;default:0"`
+	MeasuredCount   int64  `json:"measured_count" gorm:"not null;default:0"` // Runs with both start and finish times
+	TotalDurationMs int64  `json:"total_duration_ms" gorm:"not null;default:0"`
+}
+
+// TableName specifies the table name for GORM
+func (RunRollup) TableName() string {
+	return "run_rollups"
+}
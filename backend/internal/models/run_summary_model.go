@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RunSummary keeps counters for runs removed by retention, per scenario and status
+// so historical statistics survive after the detailed rows are compacted
+type RunSummary struct {
+	Model
+	ScenarioID      uint64     `json:"scenario_id" gorm:"not null;uniqueIndex:idx_run_summary_scenario_status"`
+	Status          string     `json:"status" gorm:"size:20;not null;uniqueIndex:idx_run_summary_scenario_status"`
+	RunCount        int64      `json:"run_count" gorm:"not null;default:0"`
+	TotalDurationMs int64      `json:"total_duration_ms" gorm:"not null;default:0"` // Sum over runs that have both start and finish times
+	FirstRunAt      *time.Time `json:"first_run_at,omitempty"`
+	LastRunAt       *time.Time `json:"last_run_at,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (RunSummary) TableName() string {
+	return "run_summaries"
+}
@@ -1,11 +1,14 @@
 package models
 
+import "gorm.io/gorm"
+
 type ScenarioBase struct {
 	Model
-	Name        string `json:"name" gorm:"size: 255;not null"`
-	Description string `json:"description,omitempty" gorm:"default:NULL"`
-	Tag         string `json:"tag,omitempty" gorm:"default:NULL"`
-	Icon        string `json:"icon,omitempty" gorm:"default:NULL"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"` // Soft delete (trash)
+	Name        string         `json:"name" gorm:"size: 255;not null"`
+	Description string         `json:"description,omitempty" gorm:"default:NULL"`
+	Tag         string         `json:"tag,omitempty" gorm:"default:NULL"`
+	Icon        string         `json:"icon,omitempty" gorm:"default:NULL"`
 }
 
 type Scenario struct {
@@ -13,4 +16,5 @@ type Scenario struct {
 	Context    string `json:"context" gorm:"not null"`
 	InputData  string `json:"input_data" gorm:"not null"`
 	Parameters string `json:"parameters" gorm:"not null"`
+	Retention  string `json:"retention,omitempty" gorm:"type:text"` // JSON, empty to inherit the global policy
 }
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// SchemaVersion is the version of the schema this build migrates the database to
+// Bump it with every change to the models
+const SchemaVersion = 14
+
+// SchemaMigration records that the schema was migrated to a version
+type SchemaMigration struct {
+	Version   int       `json:"version" gorm:"primarykey;autoIncrement:false"`
+	AppliedAt time.Time `json:"applied_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for GORM
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
@@ -0,0 +1,15 @@
+package models
+
+// Secret represents a named secret in the database
+type Secret struct {
+	Model
+	Name        string `json:"name" gorm:"size:64;not null;uniqueIndex"`
+	Value       string `json:"-" gorm:"type:text;not null"` // Encrypted, see ValueKeyID
+	ValueKeyID  string `json:"-" gorm:"size:64;index"`      // Key the value is encrypted with, empty for plaintext
+	Description string `json:"description,omitempty" gorm:"type:text"`
+}
+
+// TableName specifies the table name for GORM
+func (Secret) TableName() string {
+	return "secrets"
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook represents an outgoing webhook subscription in the database
+type Webhook struct {
+	Model
+	URL                 string `json:"url" gorm:"size:2048;not null"`
+	EventTypes          string `json:"event_types" gorm:"type:text;not null"` // JSON array
+	Secret              string `json:"-" gorm:"type:text;not null"`           // Encrypted, see SecretKeyID
+	SecretKeyID         string `json:"-" gorm:"size:64;index"`                // Key the secret is encrypted with, empty for plaintext
+	Description         string `json:"description,omitempty" gorm:"type:text"`
+	Enabled             bool   `json:"enabled" gorm:"not null;index"`
+	ConsecutiveFailures int    `json:"consecutive_failures" gorm:"default:0"`
+	DisabledReason      string `json:"disabled_reason,omitempty" gorm:"type:text"`
+}
+
+// TableName specifies the table name for GORM
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// WebhookDelivery is one event sent to one webhook, kept as the delivery log
+type WebhookDelivery struct {
+	Model
+	WebhookID      uint64     `json:"webhook_id" gorm:"not null;uniqueIndex:idx_webhook_deliveries_event"`
+	EventID        string     `json:"event_id" gorm:"size:64;not null;uniqueIndex:idx_webhook_deliveries_event"`
+	EventType      string     `json:"event_type" gorm:"size:100;not null"`
+	Payload        string     `json:"payload" gorm:"type:text;not null"` // JSON request body
+	Status         string     `json:"status" gorm:"size:20;not null;index"`
+	Attempts       int        `json:"attempts" gorm:"default:0"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"not null;index"`
+	ResponseStatus int        `json:"response_status,omitempty"`
+	LastError      string     `json:"last_error,omitempty" gorm:"type:text"`
+	DurationMs     int64      `json:"duration_ms"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// TableName specifies the table name for GORM
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -12,6 +12,10 @@ func AgentParseID(id string) uint64 {
 	return parseID(id)
 }
 
+func AgentFormatID(id uint64) string {
+	return formatID(id)
+}
+
 // CapabilitiesDTO represents capabilities in JSON format
 type CapabilitiesDTO struct {
 	Browsers []BrowserCapabilityDTO `json:"browsers"`
@@ -52,12 +56,14 @@ type ConnectionInfoDTO struct {
 	QueueName string `json:"queue_name"`
 }
 
-func AgentDomainEntityToPersistence(a *agent.Agent) (*models.Agent, error) {
+// AgentDomainEntityToPersistence encrypts the credential secret with cipher
+func AgentDomainEntityToPersistence(a *agent.Agent, cipher Cipher) (*models.Agent, error) {
 	model := &models.Agent{
 		Model: models.Model{
 			ID:        parseID(a.Id.String()),
 			CreatedAt: a.RegisteredAt.Time(),
 			UpdatedAt: a.UpdatedAt.Time(),
+			Version:   a.Version,
 		},
 		Name:            a.Name,
 		Status:          a.Status.String(),
@@ -94,10 +100,23 @@ func AgentDomainEntityToPersistence(a *agent.Agent) (*models.Agent, error) {
 		model.LastHeartbeatAt = &t
 	}
 
+	if a.Credential != nil {
+		secret, keyID, err := cipher.Encrypt(a.Credential.Secret)
+		if err != nil {
+			return nil, err
+		}
+		issuedAt := a.Credential.IssuedAt
+		model.CredentialSecret = secret
+		model.CredentialKeyID = keyID
+		model.CredentialIssuedAt = &issuedAt
+		model.CredentialRevokedAt = a.Credential.RevokedAt
+	}
+
 	return model, nil
 }
 
-func AgentPersistenceToDomainEntity(model *models.Agent) (*agent.Agent, error) {
+// AgentPersistenceToDomainEntity decrypts the credential secret with cipher
+func AgentPersistenceToDomainEntity(model *models.Agent, cipher Cipher) (*agent.Agent, error) {
 	agentID, err := agent.NewAgentID(formatID(model.ID))
 	if err != nil {
 		return nil, err
@@ -155,7 +174,23 @@ func AgentPersistenceToDomainEntity(model *models.Agent) (*agent.Agent, error) {
 		a.AddTag(tagID)
 	}
 
-	return a, nil
+	if model.CredentialIssuedAt != nil || model.CredentialRevokedAt != nil {
+		secret, err := cipher.Decrypt(model.CredentialSecret, model.CredentialKeyID)
+		if err != nil {
+			return nil, err
+		}
+		a.Credential = &agent.Credential{
+			Secret:    secret,
+			RevokedAt: model.CredentialRevokedAt,
+		}
+		if model.CredentialIssuedAt != nil {
+			a.Credential.IssuedAt = *model.CredentialIssuedAt
+		}
+	}
+
+	a.Version = model.Version
+
+	return rehydrated(a), nil
 }
 
 func marshalCapabilities(cap agent.Capabilities) (string, error) {
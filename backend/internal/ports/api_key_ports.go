@@ -0,0 +1,58 @@
+package ports
+
+import (
+	"parrotflow/internal/domain/apikey"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/models"
+	"strings"
+)
+
+func APIKeyParseID(id string) uint64 {
+	return parseID(id)
+}
+
+func APIKeyFormatID(id uint64) string {
+	return formatID(id)
+}
+
+func APIKeyDomainEntityToPersistence(k *apikey.APIKey) *models.APIKey {
+	return &models.APIKey{
+		Model: models.Model{
+			ID:        parseID(k.Id.String()),
+			CreatedAt: k.CreatedAt.Time(),
+			UpdatedAt: k.UpdatedAt.Time(),
+			Version:   k.Version,
+		},
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Hash:       k.Hash,
+		Scopes:     strings.Join(k.Scopes, ","),
+		ExpiresAt:  k.ExpiresAt,
+		RevokedAt:  k.RevokedAt,
+		LastUsedAt: k.LastUsedAt,
+	}
+}
+
+// APIKeyPersistenceToDomainEntity rebuilds a key without the constructor, which
+// needs the plaintext key and rejects expiries in the past
+func APIKeyPersistenceToDomainEntity(model *models.APIKey) (*apikey.APIKey, error) {
+	keyID, err := apikey.NewAPIKeyID(formatID(model.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &apikey.APIKey{
+		Id:         keyID,
+		Name:       model.Name,
+		Prefix:     model.Prefix,
+		Hash:       model.Hash,
+		Scopes:     strings.Split(model.Scopes, ","),
+		ExpiresAt:  model.ExpiresAt,
+		RevokedAt:  model.RevokedAt,
+		LastUsedAt: model.LastUsedAt,
+		CreatedAt:  shared.NewTimestamp(model.CreatedAt),
+		UpdatedAt:  shared.NewTimestamp(model.UpdatedAt),
+		Version:    model.Version,
+		Events:     make([]shared.DomainEvent, 0),
+	}, nil
+}
@@ -0,0 +1,53 @@
+package ports
+
+import (
+	"encoding/json"
+	"time"
+
+	"parrotflow/internal/domain/audit"
+	"parrotflow/internal/models"
+)
+
+func AuditDomainEntryToPersistence(entry *audit.Entry) *models.AuditEntry {
+	return &models.AuditEntry{
+		OccurredAt:    entry.OccurredAt,
+		Actor:         entry.Actor,
+		ActorName:     entry.ActorName,
+		AuthMethod:    entry.AuthMethod,
+		OperationID:   entry.OperationID,
+		Method:        entry.Method,
+		Path:          entry.Path,
+		ResourceType:  entry.ResourceType,
+		ResourceID:    entry.ResourceID,
+		Request:       string(entry.Request),
+		Status:        entry.Status,
+		Outcome:       entry.Outcome,
+		Error:         entry.Error,
+		CorrelationID: entry.CorrelationID,
+		DurationMs:    entry.Duration.Milliseconds(),
+	}
+}
+
+func AuditPersistenceToDomain(model *models.AuditEntry) (*audit.Entry, error) {
+	entry := &audit.Entry{
+		Sequence:      model.ID,
+		OccurredAt:    model.OccurredAt,
+		Actor:         model.Actor,
+		ActorName:     model.ActorName,
+		AuthMethod:    model.AuthMethod,
+		OperationID:   model.OperationID,
+		Method:        model.Method,
+		Path:          model.Path,
+		ResourceType:  model.ResourceType,
+		ResourceID:    model.ResourceID,
+		Status:        model.Status,
+		Outcome:       model.Outcome,
+		Error:         model.Error,
+		CorrelationID: model.CorrelationID,
+		Duration:      time.Duration(model.DurationMs) * time.Millisecond,
+	}
+	if model.Request != "" {
+		entry.Request = json.RawMessage(model.Request)
+	}
+	return entry, nil
+}
@@ -0,0 +1,74 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Message is one message on a broker queue
+type Message struct {
+	ID          string
+	ContentType string
+	Headers     map[string]string
+	Body        []byte
+}
+
+// MessageHandler processes one consumed message
+// Returning an error rejects the message, which dead-letters it without requeueing
+type MessageHandler func(ctx context.Context, message Message) error
+
+// MessagePublisher publishes messages to broker queues
+// It mirrors the agent's IMessagePublisher
+type MessagePublisher interface {
+	// AssertQueue creates a durable queue unless it exists; rejected messages of
+	// an asserted queue are dead-lettered
+	AssertQueue(ctx context.Context, queue string) error
+
+	// Publish sends a message to a queue
+	Publish(ctx context.Context, queue string, message Message) error
+}
+
+// MessageConsumer consumes messages from broker queues
+// It mirrors the agent's IMessageConsumer
+type MessageConsumer interface {
+	// Consume starts handing messages of queue to handler in the background and
+	// returns; consuming goes on until Stop is called or ctx is done
+	// A message is acknowledged once handler returns nil
+	Consume(ctx context.Context, queue string, handler MessageHandler) error
+
+	// Stop stops every consumer and waits for handlers in progress to return
+	Stop(ctx context.Context) error
+}
+
+// DeadLetteredMessage is a message a consumer rejected, as the broker
+// delivers it from the dead-letter queue
+type DeadLetteredMessage struct {
+	Message
+	Queue          string // Queue the message was rejected from
+	Reason         string // rejected, expired, maxlen or delivery_limit
+	Count          int    // Times it was dead-lettered from Queue
+	DeadLetteredAt time.Time
+}
+
+// DeadLetterBroker is the part of a message broker that handles rejected messages
+// Every queue asserted through it routes rejected messages to a single dead-letter queue
+type DeadLetterBroker interface {
+	MessagePublisher
+
+	// ConsumeDeadLetters hands dead-lettered messages to handler until ctx is done
+	// A message is removed from the dead-letter queue once handler returns nil
+	ConsumeDeadLetters(ctx context.Context, handler func(context.Context, DeadLetteredMessage) error) error
+}
+
+// MessageBroker is a complete broker implementation
+type MessageBroker interface {
+	MessagePublisher
+	MessageConsumer
+	DeadLetterBroker
+
+	// Ping checks that the broker can be reached, reconnecting if needed
+	Ping(ctx context.Context) error
+
+	// Close stops consumers and releases the connection
+	Close() error
+}
@@ -0,0 +1,14 @@
+package ports
+
+// Cipher encrypts credentials before they are stored and decrypts them when loaded
+// encryption.Keyring implements it
+type Cipher interface {
+	// Encrypt returns the stored form of a value and the ID of the key it was encrypted with
+	Encrypt(plaintext string) (ciphertext string, keyID string, err error)
+
+	// Decrypt opens a value stored with the key keyID
+	Decrypt(ciphertext, keyID string) (string, error)
+
+	// PrimaryKeyID is the ID Encrypt records, values stored under another one are due for rotation
+	PrimaryKeyID() string
+}
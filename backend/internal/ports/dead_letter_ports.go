@@ -0,0 +1,89 @@
+package ports
+
+import (
+	"encoding/json"
+	"parrotflow/internal/domain/deadletter"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/models"
+)
+
+func DeadLetterParseID(id string) uint64 {
+	return parseID(id)
+}
+
+func DeadLetterFormatID(id uint64) string {
+	return formatID(id)
+}
+
+// DeadLetterDomainEntityToPersistence encrypts the payload with cipher: the messages
+// of some queues carry credentials, execution requests have their secrets resolved
+func DeadLetterDomainEntityToPersistence(d *deadletter.DeadLetter, cipher Cipher) (*models.MessageDeadLetter, error) {
+	headers, err := json.Marshal(d.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, keyID, err := cipher.Encrypt(string(d.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MessageDeadLetter{
+		Model: models.Model{
+			ID:        parseID(d.Id.String()),
+			CreatedAt: d.CreatedAt.Time(),
+			UpdatedAt: d.UpdatedAt.Time(),
+			Version:   d.Version,
+		},
+		MessageID:      d.MessageID,
+		Queue:          d.Queue,
+		Reason:         d.Reason,
+		ContentType:    d.ContentType,
+		Headers:        string(headers),
+		Payload:        []byte(payload),
+		PayloadKeyID:   keyID,
+		DeathCount:     d.DeathCount,
+		DeadLetteredAt: d.DeadLetteredAt,
+		Status:         d.Status.String(),
+		ResolvedAt:     d.ResolvedAt,
+	}, nil
+}
+
+// DeadLetterPersistenceToDomainEntity decrypts the payload with cipher
+func DeadLetterPersistenceToDomainEntity(model *models.MessageDeadLetter, cipher Cipher) (*deadletter.DeadLetter, error) {
+	id, err := deadletter.NewDeadLetterID(formatID(model.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := deadletter.NewStatus(model.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := cipher.Decrypt(string(model.Payload), model.PayloadKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := deadletter.NewDeadLetter(id, model.Queue, model.Reason, []byte(payload), model.DeadLetteredAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if model.Headers != "" {
+		if err := json.Unmarshal([]byte(model.Headers), &d.Headers); err != nil {
+			return nil, err
+		}
+	}
+	d.MessageID = model.MessageID
+	d.ContentType = model.ContentType
+	d.DeathCount = model.DeathCount
+	d.Status = status
+	d.ResolvedAt = model.ResolvedAt
+	d.CreatedAt = shared.NewTimestamp(model.CreatedAt)
+	d.UpdatedAt = shared.NewTimestamp(model.UpdatedAt)
+	d.Version = model.Version
+
+	return d, nil
+}
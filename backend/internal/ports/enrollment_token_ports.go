@@ -0,0 +1,64 @@
+package ports
+
+import (
+	"parrotflow/internal/domain/enrollment"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/models"
+)
+
+func EnrollmentTokenParseID(id string) uint64 {
+	return parseID(id)
+}
+
+func EnrollmentTokenFormatID(id uint64) string {
+	return formatID(id)
+}
+
+func EnrollmentTokenDomainEntityToPersistence(t *enrollment.Token) *models.EnrollmentToken {
+	model := &models.EnrollmentToken{
+		Model: models.Model{
+			ID:        parseID(t.Id.String()),
+			CreatedAt: t.CreatedAt.Time(),
+			UpdatedAt: t.UpdatedAt.Time(),
+			Version:   t.Version,
+		},
+		Name:      t.Name,
+		Prefix:    t.Prefix,
+		Hash:      t.Hash,
+		ExpiresAt: t.ExpiresAt,
+		RevokedAt: t.RevokedAt,
+		UsedAt:    t.UsedAt,
+	}
+	if t.AgentID != "" {
+		agentID := parseID(t.AgentID)
+		model.AgentID = &agentID
+	}
+	return model
+}
+
+// EnrollmentTokenPersistenceToDomainEntity rebuilds a token without the constructor,
+// which generates a new plaintext token
+func EnrollmentTokenPersistenceToDomainEntity(model *models.EnrollmentToken) (*enrollment.Token, error) {
+	tokenID, err := enrollment.NewTokenID(formatID(model.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	t := &enrollment.Token{
+		Id:        tokenID,
+		Name:      model.Name,
+		Prefix:    model.Prefix,
+		Hash:      model.Hash,
+		ExpiresAt: model.ExpiresAt,
+		RevokedAt: model.RevokedAt,
+		UsedAt:    model.UsedAt,
+		CreatedAt: shared.NewTimestamp(model.CreatedAt),
+		UpdatedAt: shared.NewTimestamp(model.UpdatedAt),
+		Version:   model.Version,
+		Events:    make([]shared.DomainEvent, 0),
+	}
+	if model.AgentID != nil {
+		t.AgentID = formatID(*model.AgentID)
+	}
+	return t, nil
+}
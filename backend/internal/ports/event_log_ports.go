@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"encoding/json"
+	"parrotflow/internal/domain/eventlog"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/models"
+)
+
+func EventLogEnvelopeToPersistence(envelope shared.Envelope) *models.EventLogEntry {
+	return &models.EventLogEntry{
+		EventID:       envelope.ID,
+		EventType:     envelope.Type,
+		EventVersion:  envelope.Version,
+		AggregateType: envelope.AggregateType,
+		AggregateID:   envelope.AggregateID,
+		Payload:       string(envelope.Payload),
+		OccurredAt:    envelope.OccurredAt,
+		CorrelationID: envelope.CorrelationID,
+		CausationID:   envelope.CausationID,
+	}
+}
+
+func EventLogPersistenceToDomain(model *models.EventLogEntry) (*eventlog.Entry, error) {
+	return &eventlog.Entry{
+		Envelope: shared.Envelope{
+			ID:            model.EventID,
+			Type:          model.EventType,
+			Version:       model.EventVersion,
+			AggregateType: model.AggregateType,
+			AggregateID:   model.AggregateID,
+			OccurredAt:    model.OccurredAt,
+			CorrelationID: model.CorrelationID,
+			CausationID:   model.CausationID,
+			Payload:       json.RawMessage(model.Payload),
+		},
+		Sequence:   model.ID,
+		RecordedAt: model.CreatedAt,
+	}, nil
+}
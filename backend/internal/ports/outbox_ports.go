@@ -0,0 +1,55 @@
+package ports
+
+import (
+	"encoding/json"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/models"
+)
+
+func OutboxDomainEventToPersistence(event shared.DomainEvent) (*models.OutboxEvent, error) {
+	envelope, err := shared.NewEnvelope(event)
+	if err != nil {
+		return nil, err
+	}
+	return OutboxEnvelopeToPersistence(envelope), nil
+}
+
+func OutboxEnvelopeToPersistence(envelope shared.Envelope) *models.OutboxEvent {
+	return &models.OutboxEvent{
+		EventID:       envelope.ID,
+		EventType:     envelope.Type,
+		EventVersion:  envelope.Version,
+		AggregateType: envelope.AggregateType,
+		AggregateID:   envelope.AggregateID,
+		Payload:       string(envelope.Payload),
+		OccurredAt:    envelope.OccurredAt,
+		CorrelationID: envelope.CorrelationID,
+		CausationID:   envelope.CausationID,
+		Status:        models.OutboxStatusPending,
+		NextAttemptAt: envelope.OccurredAt,
+	}
+}
+
+func OutboxPersistenceToEnvelope(model *models.OutboxEvent) shared.Envelope {
+	return shared.Envelope{
+		ID:            model.EventID,
+		Type:          model.EventType,
+		Version:       model.EventVersion,
+		AggregateType: model.AggregateType,
+		AggregateID:   model.AggregateID,
+		OccurredAt:    model.OccurredAt,
+		CorrelationID: model.CorrelationID,
+		CausationID:   model.CausationID,
+		Payload:       json.RawMessage(model.Payload),
+	}
+}
+
+// OutboxDeliveredSinks returns the names of the sinks that accepted an outbox event
+func OutboxDeliveredSinks(model *models.OutboxEvent) ([]string, error) {
+	if model.DeliveredSinks == "" {
+		return nil, nil
+	}
+	var sinks []string
+	err := json.Unmarshal([]byte(model.DeliveredSinks), &sinks)
+	return sinks, err
+}
@@ -11,13 +11,20 @@ func ProxyParseID(id string) uint64 {
 	return parseID(id)
 }
 
-func ProxyDomainEntityToPersistence(p *proxy.Proxy) (*models.Proxy, error) {
+func ProxyFormatID(id uint64) string {
+	return formatID(id)
+}
+
+// ProxyDomainEntityToPersistence encrypts the password with cipher
+func ProxyDomainEntityToPersistence(p *proxy.Proxy, cipher Cipher) (*models.Proxy, error) {
 	model := &models.Proxy{
 		Model: models.Model{
 			ID:        parseID(p.Id.String()),
 			CreatedAt: p.CreatedAt.Time(),
 			UpdatedAt: p.UpdatedAt.Time(),
+			Version:   p.Version,
 		},
+		DeletedAt:      parseDeletedAt(p.DeletedAt),
 		Name:           p.Name,
 		Host:           p.Host,
 		Port:           p.Port,
@@ -30,8 +37,13 @@ func ProxyDomainEntityToPersistence(p *proxy.Proxy) (*models.Proxy, error) {
 
 	// Handle credentials
 	if p.Credentials != nil {
+		password, keyID, err := cipher.Encrypt(p.Credentials.Password)
+		if err != nil {
+			return nil, err
+		}
 		model.Username = p.Credentials.Username
-		model.Password = p.Credentials.Password // TODO: Encryption in future
+		model.Password = password
+		model.PasswordKeyID = keyID
 	}
 
 	// Handle timestamps
@@ -50,7 +62,8 @@ func ProxyDomainEntityToPersistence(p *proxy.Proxy) (*models.Proxy, error) {
 	return model, nil
 }
 
-func ProxyPersistenceToDomainEntity(model *models.Proxy) (*proxy.Proxy, error) {
+// ProxyPersistenceToDomainEntity decrypts the password with cipher
+func ProxyPersistenceToDomainEntity(model *models.Proxy, cipher Cipher) (*proxy.Proxy, error) {
 	proxyID, err := proxy.NewProxyID(formatID(model.ID))
 	if err != nil {
 		return nil, err
@@ -68,7 +81,11 @@ func ProxyPersistenceToDomainEntity(model *models.Proxy) (*proxy.Proxy, error) {
 
 	// Set credentials if present
 	if model.Username != "" && model.Password != "" {
-		creds, err := proxy.NewProxyCredentials(model.Username, model.Password)
+		password, err := cipher.Decrypt(model.Password, model.PasswordKeyID)
+		if err != nil {
+			return nil, err
+		}
+		creds, err := proxy.NewProxyCredentials(model.Username, password)
 		if err != nil {
 			return nil, err
 		}
@@ -106,5 +123,8 @@ func ProxyPersistenceToDomainEntity(model *models.Proxy) (*proxy.Proxy, error) {
 		p.AddTag(tagID)
 	}
 
-	return p, nil
+	p.Version = model.Version
+	p.DeletedAt = formatDeletedAt(model.DeletedAt)
+
+	return rehydrated(p), nil
 }
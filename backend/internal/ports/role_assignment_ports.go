@@ -0,0 +1,57 @@
+package ports
+
+import (
+	"parrotflow/internal/domain/access"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/models"
+	"strings"
+)
+
+func RoleAssignmentParseID(id string) uint64 {
+	return parseID(id)
+}
+
+func RoleAssignmentFormatID(id uint64) string {
+	return formatID(id)
+}
+
+func RoleAssignmentDomainEntityToPersistence(a *access.RoleAssignment) *models.RoleAssignment {
+	roles := make([]string, len(a.Roles))
+	for i, role := range a.Roles {
+		roles[i] = role.String()
+	}
+
+	return &models.RoleAssignment{
+		Model: models.Model{
+			ID:        parseID(a.Id.String()),
+			CreatedAt: a.CreatedAt.Time(),
+			UpdatedAt: a.UpdatedAt.Time(),
+			Version:   a.Version,
+		},
+		Subject: a.Subject,
+		Roles:   strings.Join(roles, ","),
+	}
+}
+
+// RoleAssignmentPersistenceToDomainEntity rebuilds an assignment without the constructor,
+// which would record it as newly assigned
+func RoleAssignmentPersistenceToDomainEntity(model *models.RoleAssignment) (*access.RoleAssignment, error) {
+	assignmentID, err := access.NewRoleAssignmentID(formatID(model.ID))
+	if err != nil {
+		return nil, err
+	}
+	roles, err := access.NormalizeRoles(strings.Split(model.Roles, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	return &access.RoleAssignment{
+		Id:        assignmentID,
+		Subject:   model.Subject,
+		Roles:     roles,
+		CreatedAt: shared.NewTimestamp(model.CreatedAt),
+		UpdatedAt: shared.NewTimestamp(model.UpdatedAt),
+		Version:   model.Version,
+		Events:    make([]shared.DomainEvent, 0),
+	}, nil
+}
@@ -1,17 +1,24 @@
 package ports
 
 import (
+	"encoding/json"
 	"parrotflow/internal/domain/run"
 	"parrotflow/internal/domain/scenario"
 	"parrotflow/internal/domain/shared"
 	"parrotflow/internal/models"
 	"strconv"
+
+	"gorm.io/gorm"
 )
 
 func RunParseID(id string) uint64 {
 	return parseID(id)
 }
 
+func RunFormatID(id uint64) string {
+	return formatID(id)
+}
+
 func parseID(id string) uint64 {
 	if id == "" {
 		return 0
@@ -27,16 +34,57 @@ func formatID(id uint64) string {
 	return strconv.FormatUint(id, 10)
 }
 
+// rehydrated marks an aggregate rebuilt from storage as unchanged: rebuilding replays
+// constructors and setters, and the events they raise are not new
+func rehydrated[T interface{ ClearEvents() }](aggregate T) T {
+	aggregate.ClearEvents()
+	return aggregate
+}
+
+func formatDeletedAt(deletedAt gorm.DeletedAt) *shared.Timestamp {
+	if !deletedAt.Valid {
+		return nil
+	}
+	ts := shared.NewTimestamp(deletedAt.Time)
+	return &ts
+}
+
+func parseDeletedAt(deletedAt *shared.Timestamp) gorm.DeletedAt {
+	if deletedAt == nil {
+		return gorm.DeletedAt{}
+	}
+	return gorm.DeletedAt{Time: deletedAt.Time(), Valid: true}
+}
+
 func RunDomainEntityToPersistence(run *run.Run) (*models.ScenarioRun, error) {
 	model := &models.ScenarioRun{
 		Model: models.Model{
 			ID:        parseID(run.Id.String()),
 			CreatedAt: run.CreatedAt.Time(),
 			UpdatedAt: run.UpdatedAt.Time(),
+			Version:   run.Version,
 		},
 		ScenarioID: parseID(run.ScenarioID.String()),
 		Status:     run.Status.String(),
 		Parameters: run.Parameters,
+
+		FailureReason: run.FailureReason,
+		AgentID:       parseID(run.AgentID),
+	}
+
+	if len(run.SecretNames) > 0 {
+		names, err := json.Marshal(run.SecretNames)
+		if err != nil {
+			return nil, err
+		}
+		model.SecretNames = string(names)
+	}
+	if run.Definition != nil {
+		definition, err := json.Marshal(run.Definition)
+		if err != nil {
+			return nil, err
+		}
+		model.Definition = string(definition)
 	}
 
 	if run.StartedAt != nil {
@@ -48,6 +96,19 @@ func RunDomainEntityToPersistence(run *run.Run) (*models.ScenarioRun, error) {
 	return model, nil
 }
 
+func RunNodeStepPersistenceToDomain(model *models.RunNodeStep) (run.NodeStep, error) {
+	status, err := run.NewNodeStatus(model.Status)
+	if err != nil {
+		return run.NodeStep{}, err
+	}
+	return run.NodeStep{
+		NodeID:     model.NodeID,
+		Status:     status,
+		Message:    model.Message,
+		ReportedAt: model.ReportedAt,
+	}, nil
+}
+
 func RunPersistenceToDomainEntity(model *models.ScenarioRun) (*run.Run, error) {
 	runID, err := run.NewRunID(formatID(model.ID))
 	if err != nil {
@@ -70,6 +131,22 @@ func RunPersistenceToDomainEntity(model *models.ScenarioRun) (*run.Run, error) {
 	}
 
 	run.Status = status
+	run.FailureReason = model.FailureReason
+	if model.AgentID != 0 {
+		run.AgentID = formatID(model.AgentID)
+	}
+	if model.SecretNames != "" {
+		if err := json.Unmarshal([]byte(model.SecretNames), &run.SecretNames); err != nil {
+			return nil, err
+		}
+	}
+	if model.Definition != "" {
+		if err := json.Unmarshal([]byte(model.Definition), &run.Definition); err != nil {
+			return nil, err
+		}
+	}
+	run.CreatedAt = shared.NewTimestamp(model.CreatedAt)
+	run.UpdatedAt = shared.NewTimestamp(model.UpdatedAt)
 	if !model.StartedAt.IsZero() {
 		startedAt := shared.NewTimestamp(model.StartedAt)
 		run.StartedAt = &startedAt
@@ -79,5 +156,7 @@ func RunPersistenceToDomainEntity(model *models.ScenarioRun) (*run.Run, error) {
 		run.FinishedAt = &finishedAt
 	}
 
-	return run, nil
+	run.Version = model.Version
+
+	return rehydrated(run), nil
 }
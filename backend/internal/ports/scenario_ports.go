@@ -10,6 +10,10 @@ func ScenarioParseID(id string) uint64 {
 	return parseID(id)
 }
 
+func ScenarioFormatID(id uint64) string {
+	return formatID(id)
+}
+
 func ScenarioDomainEntityToPersistence(s *scenario.Scenario) (*models.Scenario, error) {
 	model := &models.Scenario{
 		ScenarioBase: models.ScenarioBase{
@@ -17,7 +21,9 @@ func ScenarioDomainEntityToPersistence(s *scenario.Scenario) (*models.Scenario,
 				ID:        parseID(s.Id.String()),
 				CreatedAt: s.CreatedAt.Time(),
 				UpdatedAt: s.UpdatedAt.Time(),
+				Version:   s.Version,
 			},
+			DeletedAt:   parseDeletedAt(s.DeletedAt),
 			Name:        s.Name,
 			Description: s.Description,
 			Tag:         s.Tag,
@@ -26,6 +32,7 @@ func ScenarioDomainEntityToPersistence(s *scenario.Scenario) (*models.Scenario,
 		Context:    marshalContext(s.Context),
 		InputData:  marshalInputData(s.InputData),
 		Parameters: marshalParameters(s.Parameters),
+		Retention:  marshalRetention(s.Retention),
 	}
 	return model, nil
 }
@@ -69,7 +76,18 @@ func ScenarioPersistenceToDomainEntity(model *models.Scenario) (*scenario.Scenar
 		s.UpdateParameters(parameters)
 	}
 
-	return s, nil
+	if model.Retention != "" {
+		retention, err := unmarshalRetention(model.Retention)
+		if err != nil {
+			return nil, err
+		}
+		s.UpdateRetention(&retention)
+	}
+
+	s.Version = model.Version
+	s.DeletedAt = formatDeletedAt(model.DeletedAt)
+
+	return rehydrated(s), nil
 }
 
 func marshalContext(context scenario.Context) string {
@@ -87,6 +105,14 @@ func marshalParameters(parameters scenario.Parameters) string {
 	return string(data)
 }
 
+func marshalRetention(retention *scenario.RetentionPolicy) string {
+	if retention == nil {
+		return ""
+	}
+	data, _ := json.Marshal(retention)
+	return string(data)
+}
+
 func unmarshalContext(data string) (scenario.Context, error) {
 	var context scenario.Context
 	err := json.Unmarshal([]byte(data), &context)
@@ -104,3 +130,9 @@ func unmarshalParameters(data string) (scenario.Parameters, error) {
 	err := json.Unmarshal([]byte(data), &parameters)
 	return parameters, err
 }
+
+func unmarshalRetention(data string) (scenario.RetentionPolicy, error) {
+	var retention scenario.RetentionPolicy
+	err := json.Unmarshal([]byte(data), &retention)
+	return retention, err
+}
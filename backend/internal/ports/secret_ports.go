@@ -0,0 +1,59 @@
+package ports
+
+import (
+	"parrotflow/internal/domain/secret"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/models"
+)
+
+func SecretParseID(id string) uint64 {
+	return parseID(id)
+}
+
+func SecretFormatID(id uint64) string {
+	return formatID(id)
+}
+
+// SecretDomainEntityToPersistence encrypts the value with cipher
+func SecretDomainEntityToPersistence(s *secret.Secret, cipher Cipher) (*models.Secret, error) {
+	value, keyID, err := cipher.Encrypt(s.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Secret{
+		Model: models.Model{
+			ID:        parseID(s.Id.String()),
+			CreatedAt: s.CreatedAt.Time(),
+			UpdatedAt: s.UpdatedAt.Time(),
+			Version:   s.Version,
+		},
+		Name:        s.Name,
+		Value:       value,
+		ValueKeyID:  keyID,
+		Description: s.Description,
+	}, nil
+}
+
+// SecretPersistenceToDomainEntity decrypts the value with cipher
+func SecretPersistenceToDomainEntity(model *models.Secret, cipher Cipher) (*secret.Secret, error) {
+	secretID, err := secret.NewSecretID(formatID(model.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := cipher.Decrypt(model.Value, model.ValueKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := secret.NewSecret(secretID, model.Name, value, model.Description)
+	if err != nil {
+		return nil, err
+	}
+	s.CreatedAt = shared.NewTimestamp(model.CreatedAt)
+	s.UpdatedAt = shared.NewTimestamp(model.UpdatedAt)
+	s.Version = model.Version
+
+	return rehydrated(s), nil
+}
@@ -9,13 +9,19 @@ func TagParseID(id string) uint64 {
 	return parseID(id)
 }
 
+func TagFormatID(id uint64) string {
+	return formatID(id)
+}
+
 func TagDomainEntityToPersistence(t *tag.Tag) (*models.Tag, error) {
 	model := &models.Tag{
 		Model: models.Model{
 			ID:        parseID(t.Id.String()),
 			CreatedAt: t.CreatedAt.Time(),
 			UpdatedAt: t.UpdatedAt.Time(),
+			Version:   t.Version,
 		},
+		DeletedAt:   parseDeletedAt(t.DeletedAt),
 		Name:        t.Name,
 		Category:    t.Category.String(),
 		Description: t.Description,
@@ -56,5 +62,8 @@ func TagPersistenceToDomainEntity(model *models.Tag) (*tag.Tag, error) {
 		}
 	}
 
-	return t, nil
+	t.Version = model.Version
+	t.DeletedAt = formatDeletedAt(model.DeletedAt)
+
+	return rehydrated(t), nil
 }
@@ -0,0 +1,129 @@
+package ports
+
+import (
+	"encoding/json"
+	"parrotflow/internal/domain/shared"
+	"parrotflow/internal/domain/webhook"
+	"parrotflow/internal/models"
+	"time"
+)
+
+func WebhookParseID(id string) uint64 {
+	return parseID(id)
+}
+
+func WebhookFormatID(id uint64) string {
+	return formatID(id)
+}
+
+// WebhookDomainEntityToPersistence encrypts the signing secret with cipher
+func WebhookDomainEntityToPersistence(w *webhook.Webhook, cipher Cipher) (*models.Webhook, error) {
+	eventTypes, err := json.Marshal(w.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, keyID, err := cipher.Encrypt(w.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &models.Webhook{
+		Model: models.Model{
+			ID:        parseID(w.Id.String()),
+			CreatedAt: w.CreatedAt.Time(),
+			UpdatedAt: w.UpdatedAt.Time(),
+			Version:   w.Version,
+		},
+		URL:                 w.URL,
+		EventTypes:          string(eventTypes),
+		Secret:              secret,
+		SecretKeyID:         keyID,
+		Description:         w.Description,
+		Enabled:             w.Enabled,
+		ConsecutiveFailures: w.ConsecutiveFailures,
+		DisabledReason:      w.DisabledReason,
+	}
+	return model, nil
+}
+
+// WebhookPersistenceToDomainEntity decrypts the signing secret with cipher
+func WebhookPersistenceToDomainEntity(model *models.Webhook, cipher Cipher) (*webhook.Webhook, error) {
+	webhookID, err := webhook.NewWebhookID(formatID(model.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	var eventTypes []string
+	if err := json.Unmarshal([]byte(model.EventTypes), &eventTypes); err != nil {
+		return nil, err
+	}
+
+	secret, err := cipher.Decrypt(model.Secret, model.SecretKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := webhook.NewWebhook(webhookID, model.URL, eventTypes, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Description = model.Description
+	w.Enabled = model.Enabled
+	w.ConsecutiveFailures = model.ConsecutiveFailures
+	w.DisabledReason = model.DisabledReason
+	w.CreatedAt = shared.NewTimestamp(model.CreatedAt)
+	w.UpdatedAt = shared.NewTimestamp(model.UpdatedAt)
+	w.Version = model.Version
+
+	return rehydrated(w), nil
+}
+
+func WebhookDeliveryDomainToPersistence(d *webhook.Delivery) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		Model: models.Model{
+			ID:        parseID(d.ID),
+			CreatedAt: d.CreatedAt,
+		},
+		WebhookID:      parseID(d.WebhookID.String()),
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        string(d.Payload),
+		Status:         d.Status.String(),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		DurationMs:     d.Duration.Milliseconds(),
+		DeliveredAt:    d.DeliveredAt,
+	}
+}
+
+func WebhookDeliveryPersistenceToDomain(model *models.WebhookDelivery) (*webhook.Delivery, error) {
+	webhookID, err := webhook.NewWebhookID(formatID(model.WebhookID))
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := webhook.NewDeliveryStatus(model.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook.Delivery{
+		ID:             formatID(model.ID),
+		WebhookID:      webhookID,
+		EventID:        model.EventID,
+		EventType:      model.EventType,
+		Payload:        []byte(model.Payload),
+		Status:         status,
+		Attempts:       model.Attempts,
+		NextAttemptAt:  model.NextAttemptAt,
+		ResponseStatus: model.ResponseStatus,
+		LastError:      model.LastError,
+		Duration:       time.Duration(model.DurationMs) * time.Millisecond,
+		DeliveredAt:    model.DeliveredAt,
+		CreatedAt:      model.CreatedAt,
+	}, nil
+}